@@ -2,7 +2,7 @@ import type { NextConfig } from 'next'
 
 const nextConfig: NextConfig = {
   output: 'standalone',
-  serverExternalPackages: ['ioredis', 'bcryptjs', 'ws', 'dockerode', 'tar-stream'],
+  serverExternalPackages: ['ioredis', 'bcryptjs', 'ws', 'dockerode', 'tar-stream', '@prisma/adapter-better-sqlite3', 'better-sqlite3', '@prisma/adapter-mariadb', 'mariadb'],
 }
 
 export default nextConfig
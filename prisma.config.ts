@@ -2,9 +2,15 @@
 // npm install --save-dev prisma dotenv
 import "dotenv/config";
 import { defineConfig } from "prisma/config";
+import { dbDialect } from "./src/lib/db-dialect";
+
+// SQLite ("file:...") and MySQL ("mysql://...") use the schema derived by
+// scripts/dialect-schema.ts and `prisma db push`; the hand-written
+// migrations are Postgres SQL.
+const dialect = dbDialect(process.env["DATABASE_URL"]);
 
 export default defineConfig({
-  schema: "prisma/schema.prisma",
+  schema: dialect === "postgresql" ? "prisma/schema.prisma" : `prisma/schema.${dialect}.prisma`,
   migrations: {
     path: "prisma/migrations",
   },
@@ -0,0 +1,33 @@
+-- CreateEnum
+CREATE TYPE "ContainerAction" AS ENUM ('RESTART', 'START', 'STOP', 'LOGS');
+
+-- CreateTable
+CREATE TABLE "InstanceDelegation" (
+    "id" TEXT NOT NULL,
+    "departmentId" TEXT NOT NULL,
+    "instanceId" TEXT NOT NULL,
+    "actions" "ContainerAction"[] DEFAULT ARRAY[]::"ContainerAction"[],
+    "grantedById" TEXT NOT NULL,
+    "createdAt" TIMESTAMP(3) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "updatedAt" TIMESTAMP(3) NOT NULL,
+
+    CONSTRAINT "InstanceDelegation_pkey" PRIMARY KEY ("id")
+);
+
+-- CreateIndex
+CREATE INDEX "InstanceDelegation_departmentId_idx" ON "InstanceDelegation"("departmentId");
+
+-- CreateIndex
+CREATE INDEX "InstanceDelegation_instanceId_idx" ON "InstanceDelegation"("instanceId");
+
+-- CreateIndex
+CREATE UNIQUE INDEX "InstanceDelegation_departmentId_instanceId_key" ON "InstanceDelegation"("departmentId", "instanceId");
+
+-- AddForeignKey
+ALTER TABLE "InstanceDelegation" ADD CONSTRAINT "InstanceDelegation_departmentId_fkey" FOREIGN KEY ("departmentId") REFERENCES "Department"("id") ON DELETE CASCADE ON UPDATE CASCADE;
+
+-- AddForeignKey
+ALTER TABLE "InstanceDelegation" ADD CONSTRAINT "InstanceDelegation_instanceId_fkey" FOREIGN KEY ("instanceId") REFERENCES "Instance"("id") ON DELETE CASCADE ON UPDATE CASCADE;
+
+-- AddForeignKey
+ALTER TABLE "InstanceDelegation" ADD CONSTRAINT "InstanceDelegation_grantedById_fkey" FOREIGN KEY ("grantedById") REFERENCES "User"("id") ON DELETE RESTRICT ON UPDATE CASCADE;
@@ -0,0 +1,40 @@
+-- CreateEnum
+CREATE TYPE "ApprovalStatus" AS ENUM ('PENDING', 'APPROVED', 'REJECTED', 'EXPIRED', 'FAILED');
+
+-- CreateTable
+CREATE TABLE "ApprovalRequest" (
+    "id" TEXT NOT NULL,
+    "action" TEXT NOT NULL,
+    "resource" TEXT NOT NULL,
+    "resourceId" TEXT,
+    "payload" JSONB NOT NULL,
+    "summary" JSONB,
+    "reason" TEXT,
+    "status" "ApprovalStatus" NOT NULL DEFAULT 'PENDING',
+    "requestedById" TEXT NOT NULL,
+    "reviewedById" TEXT,
+    "reviewComment" TEXT,
+    "reviewedAt" TIMESTAMP(3),
+    "executedAt" TIMESTAMP(3),
+    "error" TEXT,
+    "expiresAt" TIMESTAMP(3) NOT NULL,
+    "createdAt" TIMESTAMP(3) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "updatedAt" TIMESTAMP(3) NOT NULL,
+
+    CONSTRAINT "ApprovalRequest_pkey" PRIMARY KEY ("id")
+);
+
+-- CreateIndex
+CREATE INDEX "ApprovalRequest_status_expiresAt_idx" ON "ApprovalRequest"("status", "expiresAt");
+
+-- CreateIndex
+CREATE INDEX "ApprovalRequest_requestedById_idx" ON "ApprovalRequest"("requestedById");
+
+-- CreateIndex
+CREATE INDEX "ApprovalRequest_resource_resourceId_idx" ON "ApprovalRequest"("resource", "resourceId");
+
+-- AddForeignKey
+ALTER TABLE "ApprovalRequest" ADD CONSTRAINT "ApprovalRequest_requestedById_fkey" FOREIGN KEY ("requestedById") REFERENCES "User"("id") ON DELETE RESTRICT ON UPDATE CASCADE;
+
+-- AddForeignKey
+ALTER TABLE "ApprovalRequest" ADD CONSTRAINT "ApprovalRequest_reviewedById_fkey" FOREIGN KEY ("reviewedById") REFERENCES "User"("id") ON DELETE SET NULL ON UPDATE CASCADE;
@@ -0,0 +1,7 @@
+-- AlterTable
+ALTER TABLE "Department" ADD COLUMN     "defaultAgentId" TEXT,
+ADD COLUMN     "defaultInstanceId" TEXT,
+ADD COLUMN     "welcomePrompt" TEXT;
+
+-- AddForeignKey
+ALTER TABLE "Department" ADD CONSTRAINT "Department_defaultInstanceId_fkey" FOREIGN KEY ("defaultInstanceId") REFERENCES "Instance"("id") ON DELETE SET NULL ON UPDATE CASCADE;
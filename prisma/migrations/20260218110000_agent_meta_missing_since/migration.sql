@@ -0,0 +1,2 @@
+-- AlterTable
+ALTER TABLE "AgentMeta" ADD COLUMN     "missingSince" TIMESTAMP(3);
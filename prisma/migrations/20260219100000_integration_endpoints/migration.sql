@@ -0,0 +1,63 @@
+-- CreateEnum
+CREATE TYPE "IntegrationEventStatus" AS ENUM ('PENDING', 'COMPLETED', 'FAILED');
+
+-- AlterTable
+ALTER TABLE "User" ADD COLUMN     "isServiceAccount" BOOLEAN NOT NULL DEFAULT false;
+
+-- CreateTable
+CREATE TABLE "IntegrationEndpoint" (
+    "id" TEXT NOT NULL,
+    "name" TEXT NOT NULL,
+    "slug" TEXT NOT NULL,
+    "description" TEXT,
+    "instanceId" TEXT NOT NULL,
+    "agentId" TEXT NOT NULL,
+    "secret" TEXT NOT NULL,
+    "promptTemplate" TEXT NOT NULL,
+    "enabled" BOOLEAN NOT NULL DEFAULT true,
+    "serviceAccountId" TEXT NOT NULL,
+    "createdById" TEXT NOT NULL,
+    "lastTriggeredAt" TIMESTAMP(3),
+    "createdAt" TIMESTAMP(3) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "updatedAt" TIMESTAMP(3) NOT NULL,
+
+    CONSTRAINT "IntegrationEndpoint_pkey" PRIMARY KEY ("id")
+);
+
+-- CreateTable
+CREATE TABLE "IntegrationEvent" (
+    "id" TEXT NOT NULL,
+    "endpointId" TEXT NOT NULL,
+    "status" "IntegrationEventStatus" NOT NULL DEFAULT 'PENDING',
+    "chatSessionId" TEXT,
+    "error" TEXT,
+    "sourceIp" TEXT,
+    "createdAt" TIMESTAMP(3) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "completedAt" TIMESTAMP(3),
+
+    CONSTRAINT "IntegrationEvent_pkey" PRIMARY KEY ("id")
+);
+
+-- CreateIndex
+CREATE UNIQUE INDEX "IntegrationEndpoint_slug_key" ON "IntegrationEndpoint"("slug");
+
+-- CreateIndex
+CREATE INDEX "IntegrationEndpoint_instanceId_idx" ON "IntegrationEndpoint"("instanceId");
+
+-- CreateIndex
+CREATE INDEX "IntegrationEvent_endpointId_createdAt_idx" ON "IntegrationEvent"("endpointId", "createdAt");
+
+-- AddForeignKey
+ALTER TABLE "IntegrationEndpoint" ADD CONSTRAINT "IntegrationEndpoint_instanceId_fkey" FOREIGN KEY ("instanceId") REFERENCES "Instance"("id") ON DELETE CASCADE ON UPDATE CASCADE;
+
+-- AddForeignKey
+ALTER TABLE "IntegrationEndpoint" ADD CONSTRAINT "IntegrationEndpoint_serviceAccountId_fkey" FOREIGN KEY ("serviceAccountId") REFERENCES "User"("id") ON DELETE RESTRICT ON UPDATE CASCADE;
+
+-- AddForeignKey
+ALTER TABLE "IntegrationEndpoint" ADD CONSTRAINT "IntegrationEndpoint_createdById_fkey" FOREIGN KEY ("createdById") REFERENCES "User"("id") ON DELETE RESTRICT ON UPDATE CASCADE;
+
+-- AddForeignKey
+ALTER TABLE "IntegrationEvent" ADD CONSTRAINT "IntegrationEvent_endpointId_fkey" FOREIGN KEY ("endpointId") REFERENCES "IntegrationEndpoint"("id") ON DELETE CASCADE ON UPDATE CASCADE;
+
+-- AddForeignKey
+ALTER TABLE "IntegrationEvent" ADD CONSTRAINT "IntegrationEvent_chatSessionId_fkey" FOREIGN KEY ("chatSessionId") REFERENCES "ChatSession"("id") ON DELETE SET NULL ON UPDATE CASCADE;
@@ -0,0 +1,52 @@
+-- CreateEnum
+CREATE TYPE "NotificationChannelType" AS ENUM ('TEAMS', 'WECOM');
+
+-- CreateEnum
+CREATE TYPE "NotificationStatus" AS ENUM ('SENT', 'FAILED');
+
+-- CreateTable
+CREATE TABLE "NotificationChannel" (
+    "id" TEXT NOT NULL,
+    "name" TEXT NOT NULL,
+    "type" "NotificationChannelType" NOT NULL,
+    "departmentId" TEXT NOT NULL,
+    "webhookUrl" TEXT NOT NULL,
+    "template" TEXT,
+    "enabled" BOOLEAN NOT NULL DEFAULT true,
+    "createdById" TEXT NOT NULL,
+    "createdAt" TIMESTAMP(3) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "updatedAt" TIMESTAMP(3) NOT NULL,
+
+    CONSTRAINT "NotificationChannel_pkey" PRIMARY KEY ("id")
+);
+
+-- CreateTable
+CREATE TABLE "NotificationLog" (
+    "id" TEXT NOT NULL,
+    "channelId" TEXT NOT NULL,
+    "source" TEXT NOT NULL,
+    "title" TEXT,
+    "preview" TEXT,
+    "status" "NotificationStatus" NOT NULL,
+    "httpStatus" INTEGER,
+    "error" TEXT,
+    "attempts" INTEGER NOT NULL DEFAULT 1,
+    "createdAt" TIMESTAMP(3) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT "NotificationLog_pkey" PRIMARY KEY ("id")
+);
+
+-- CreateIndex
+CREATE INDEX "NotificationChannel_departmentId_idx" ON "NotificationChannel"("departmentId");
+
+-- CreateIndex
+CREATE INDEX "NotificationLog_channelId_createdAt_idx" ON "NotificationLog"("channelId", "createdAt");
+
+-- AddForeignKey
+ALTER TABLE "NotificationChannel" ADD CONSTRAINT "NotificationChannel_departmentId_fkey" FOREIGN KEY ("departmentId") REFERENCES "Department"("id") ON DELETE CASCADE ON UPDATE CASCADE;
+
+-- AddForeignKey
+ALTER TABLE "NotificationChannel" ADD CONSTRAINT "NotificationChannel_createdById_fkey" FOREIGN KEY ("createdById") REFERENCES "User"("id") ON DELETE RESTRICT ON UPDATE CASCADE;
+
+-- AddForeignKey
+ALTER TABLE "NotificationLog" ADD CONSTRAINT "NotificationLog_channelId_fkey" FOREIGN KEY ("channelId") REFERENCES "NotificationChannel"("id") ON DELETE CASCADE ON UPDATE CASCADE;
@@ -0,0 +1,27 @@
+-- CreateTable
+CREATE TABLE "SessionShare" (
+    "id" TEXT NOT NULL,
+    "tokenHash" TEXT NOT NULL,
+    "chatSessionId" TEXT NOT NULL,
+    "createdById" TEXT NOT NULL,
+    "passwordHash" TEXT,
+    "expiresAt" TIMESTAMP(3) NOT NULL,
+    "revokedAt" TIMESTAMP(3),
+    "viewCount" INTEGER NOT NULL DEFAULT 0,
+    "lastViewedAt" TIMESTAMP(3),
+    "createdAt" TIMESTAMP(3) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT "SessionShare_pkey" PRIMARY KEY ("id")
+);
+
+-- CreateIndex
+CREATE UNIQUE INDEX "SessionShare_tokenHash_key" ON "SessionShare"("tokenHash");
+
+-- CreateIndex
+CREATE INDEX "SessionShare_chatSessionId_idx" ON "SessionShare"("chatSessionId");
+
+-- AddForeignKey
+ALTER TABLE "SessionShare" ADD CONSTRAINT "SessionShare_chatSessionId_fkey" FOREIGN KEY ("chatSessionId") REFERENCES "ChatSession"("id") ON DELETE CASCADE ON UPDATE CASCADE;
+
+-- AddForeignKey
+ALTER TABLE "SessionShare" ADD CONSTRAINT "SessionShare_createdById_fkey" FOREIGN KEY ("createdById") REFERENCES "User"("id") ON DELETE CASCADE ON UPDATE CASCADE;
@@ -0,0 +1,34 @@
+-- CreateTable
+CREATE TABLE "ChatWidget" (
+    "id" TEXT NOT NULL,
+    "name" TEXT NOT NULL,
+    "instanceId" TEXT NOT NULL,
+    "agentId" TEXT NOT NULL,
+    "tokenHash" TEXT NOT NULL,
+    "tokenPrefix" TEXT NOT NULL,
+    "allowedOrigins" TEXT[] DEFAULT ARRAY[]::TEXT[],
+    "rateLimitPerMinute" INTEGER NOT NULL DEFAULT 20,
+    "enabled" BOOLEAN NOT NULL DEFAULT true,
+    "serviceAccountId" TEXT NOT NULL,
+    "createdById" TEXT NOT NULL,
+    "lastUsedAt" TIMESTAMP(3),
+    "createdAt" TIMESTAMP(3) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "updatedAt" TIMESTAMP(3) NOT NULL,
+
+    CONSTRAINT "ChatWidget_pkey" PRIMARY KEY ("id")
+);
+
+-- CreateIndex
+CREATE UNIQUE INDEX "ChatWidget_tokenHash_key" ON "ChatWidget"("tokenHash");
+
+-- CreateIndex
+CREATE INDEX "ChatWidget_instanceId_idx" ON "ChatWidget"("instanceId");
+
+-- AddForeignKey
+ALTER TABLE "ChatWidget" ADD CONSTRAINT "ChatWidget_instanceId_fkey" FOREIGN KEY ("instanceId") REFERENCES "Instance"("id") ON DELETE CASCADE ON UPDATE CASCADE;
+
+-- AddForeignKey
+ALTER TABLE "ChatWidget" ADD CONSTRAINT "ChatWidget_serviceAccountId_fkey" FOREIGN KEY ("serviceAccountId") REFERENCES "User"("id") ON DELETE RESTRICT ON UPDATE CASCADE;
+
+-- AddForeignKey
+ALTER TABLE "ChatWidget" ADD CONSTRAINT "ChatWidget_createdById_fkey" FOREIGN KEY ("createdById") REFERENCES "User"("id") ON DELETE RESTRICT ON UPDATE CASCADE;
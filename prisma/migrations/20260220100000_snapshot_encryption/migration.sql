@@ -0,0 +1,22 @@
+-- CreateTable
+CREATE TABLE "DataKey" (
+    "id" TEXT NOT NULL,
+    "departmentId" TEXT,
+    "wrappedKey" TEXT NOT NULL,
+    "retiredAt" TIMESTAMP(3),
+    "createdAt" TIMESTAMP(3) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT "DataKey_pkey" PRIMARY KEY ("id")
+);
+
+-- CreateIndex
+CREATE INDEX "DataKey_departmentId_idx" ON "DataKey"("departmentId");
+
+-- AlterTable
+ALTER TABLE "ChatMessageSnapshot" ADD COLUMN "dataKeyId" TEXT;
+
+-- CreateIndex
+CREATE INDEX "ChatMessageSnapshot_dataKeyId_idx" ON "ChatMessageSnapshot"("dataKeyId");
+
+-- AddForeignKey
+ALTER TABLE "ChatMessageSnapshot" ADD CONSTRAINT "ChatMessageSnapshot_dataKeyId_fkey" FOREIGN KEY ("dataKeyId") REFERENCES "DataKey"("id") ON DELETE RESTRICT ON UPDATE CASCADE;
@@ -0,0 +1,39 @@
+-- CreateEnum
+CREATE TYPE "ProbeStatus" AS ENUM ('PASS', 'FAIL');
+
+-- CreateTable
+CREATE TABLE "SyntheticProbe" (
+    "id" TEXT NOT NULL,
+    "name" TEXT NOT NULL,
+    "instanceId" TEXT NOT NULL,
+    "agentId" TEXT NOT NULL,
+    "prompt" TEXT NOT NULL,
+    "expectRegex" TEXT,
+    "expectJsonSchema" JSONB,
+    "latencyBudgetMs" INTEGER NOT NULL DEFAULT 30000,
+    "intervalSeconds" INTEGER NOT NULL DEFAULT 300,
+    "failureThreshold" INTEGER NOT NULL DEFAULT 2,
+    "enabled" BOOLEAN NOT NULL DEFAULT true,
+    "lastRunAt" TIMESTAMP(3),
+    "lastStatus" "ProbeStatus",
+    "lastLatencyMs" INTEGER,
+    "lastError" TEXT,
+    "consecutiveFailures" INTEGER NOT NULL DEFAULT 0,
+    "createdById" TEXT NOT NULL,
+    "createdAt" TIMESTAMP(3) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "updatedAt" TIMESTAMP(3) NOT NULL,
+
+    CONSTRAINT "SyntheticProbe_pkey" PRIMARY KEY ("id")
+);
+
+-- CreateIndex
+CREATE INDEX "SyntheticProbe_instanceId_idx" ON "SyntheticProbe"("instanceId");
+
+-- CreateIndex
+CREATE INDEX "SyntheticProbe_enabled_lastRunAt_idx" ON "SyntheticProbe"("enabled", "lastRunAt");
+
+-- AddForeignKey
+ALTER TABLE "SyntheticProbe" ADD CONSTRAINT "SyntheticProbe_instanceId_fkey" FOREIGN KEY ("instanceId") REFERENCES "Instance"("id") ON DELETE CASCADE ON UPDATE CASCADE;
+
+-- AddForeignKey
+ALTER TABLE "SyntheticProbe" ADD CONSTRAINT "SyntheticProbe_createdById_fkey" FOREIGN KEY ("createdById") REFERENCES "User"("id") ON DELETE RESTRICT ON UPDATE CASCADE;
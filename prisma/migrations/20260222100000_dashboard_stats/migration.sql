@@ -0,0 +1,9 @@
+-- CreateTable
+CREATE TABLE "DashboardStat" (
+    "scope" TEXT NOT NULL,
+    "stats" JSONB NOT NULL,
+    "providers" JSONB NOT NULL,
+    "refreshedAt" TIMESTAMP(3) NOT NULL,
+
+    CONSTRAINT "DashboardStat_pkey" PRIMARY KEY ("scope")
+);
@@ -0,0 +1,55 @@
+-- CreateEnum
+CREATE TYPE "AccessReviewStatus" AS ENUM ('OPEN', 'CLOSED');
+
+-- CreateEnum
+CREATE TYPE "AccessReviewDecision" AS ENUM ('PENDING', 'KEEP', 'REVOKE', 'AUTO_REVOKED');
+
+-- CreateTable
+CREATE TABLE "AccessReviewCampaign" (
+    "id" TEXT NOT NULL,
+    "name" TEXT NOT NULL,
+    "status" "AccessReviewStatus" NOT NULL DEFAULT 'OPEN',
+    "dueAt" TIMESTAMP(3) NOT NULL,
+    "autoRevoke" BOOLEAN NOT NULL DEFAULT true,
+    "createdById" TEXT,
+    "lastRemindedAt" TIMESTAMP(3),
+    "closedAt" TIMESTAMP(3),
+    "createdAt" TIMESTAMP(3) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "updatedAt" TIMESTAMP(3) NOT NULL,
+
+    CONSTRAINT "AccessReviewCampaign_pkey" PRIMARY KEY ("id")
+);
+
+-- CreateTable
+CREATE TABLE "AccessReviewItem" (
+    "id" TEXT NOT NULL,
+    "campaignId" TEXT NOT NULL,
+    "kind" TEXT NOT NULL,
+    "subjectId" TEXT NOT NULL,
+    "departmentId" TEXT,
+    "summary" JSONB NOT NULL,
+    "decision" "AccessReviewDecision" NOT NULL DEFAULT 'PENDING',
+    "reviewerId" TEXT,
+    "comment" TEXT,
+    "decidedAt" TIMESTAMP(3),
+
+    CONSTRAINT "AccessReviewItem_pkey" PRIMARY KEY ("id")
+);
+
+-- CreateIndex
+CREATE INDEX "AccessReviewCampaign_status_dueAt_idx" ON "AccessReviewCampaign"("status", "dueAt");
+
+-- CreateIndex
+CREATE INDEX "AccessReviewItem_campaignId_decision_idx" ON "AccessReviewItem"("campaignId", "decision");
+
+-- CreateIndex
+CREATE INDEX "AccessReviewItem_departmentId_idx" ON "AccessReviewItem"("departmentId");
+
+-- AddForeignKey
+ALTER TABLE "AccessReviewCampaign" ADD CONSTRAINT "AccessReviewCampaign_createdById_fkey" FOREIGN KEY ("createdById") REFERENCES "User"("id") ON DELETE SET NULL ON UPDATE CASCADE;
+
+-- AddForeignKey
+ALTER TABLE "AccessReviewItem" ADD CONSTRAINT "AccessReviewItem_campaignId_fkey" FOREIGN KEY ("campaignId") REFERENCES "AccessReviewCampaign"("id") ON DELETE CASCADE ON UPDATE CASCADE;
+
+-- AddForeignKey
+ALTER TABLE "AccessReviewItem" ADD CONSTRAINT "AccessReviewItem_reviewerId_fkey" FOREIGN KEY ("reviewerId") REFERENCES "User"("id") ON DELETE SET NULL ON UPDATE CASCADE;
@@ -0,0 +1,14 @@
+-- AlterTable
+ALTER TABLE "InstanceAccess" ADD COLUMN "expiresAt" TIMESTAMP(3),
+ADD COLUMN "expiryNotifiedAt" TIMESTAMP(3);
+
+-- AlterTable
+ALTER TABLE "User" ADD COLUMN "roleExpiresAt" TIMESTAMP(3),
+ADD COLUMN "baseRole" "Role",
+ADD COLUMN "roleExpiryNotifiedAt" TIMESTAMP(3);
+
+-- CreateIndex
+CREATE INDEX "InstanceAccess_expiresAt_idx" ON "InstanceAccess"("expiresAt");
+
+-- CreateIndex
+CREATE INDEX "User_roleExpiresAt_idx" ON "User"("roleExpiresAt");
@@ -0,0 +1,22 @@
+-- CreateTable
+CREATE TABLE "BreakGlassSession" (
+    "id" TEXT NOT NULL,
+    "userId" TEXT NOT NULL,
+    "justification" TEXT NOT NULL,
+    "expiresAt" TIMESTAMP(3) NOT NULL,
+    "endedAt" TIMESTAMP(3),
+    "endedById" TEXT,
+    "ipAddress" TEXT NOT NULL,
+    "createdAt" TIMESTAMP(3) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT "BreakGlassSession_pkey" PRIMARY KEY ("id")
+);
+
+-- CreateIndex
+CREATE INDEX "BreakGlassSession_userId_idx" ON "BreakGlassSession"("userId");
+
+-- CreateIndex
+CREATE INDEX "BreakGlassSession_expiresAt_idx" ON "BreakGlassSession"("expiresAt");
+
+-- AddForeignKey
+ALTER TABLE "BreakGlassSession" ADD CONSTRAINT "BreakGlassSession_userId_fkey" FOREIGN KEY ("userId") REFERENCES "User"("id") ON DELETE CASCADE ON UPDATE CASCADE;
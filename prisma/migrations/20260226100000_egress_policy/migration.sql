@@ -0,0 +1,21 @@
+-- CreateTable
+CREATE TABLE "EgressPolicy" (
+    "id" TEXT NOT NULL,
+    "departmentId" TEXT NOT NULL,
+    "enabled" BOOLEAN NOT NULL DEFAULT true,
+    "allowedTools" JSONB,
+    "deniedTools" JSONB,
+    "allowedDomains" JSONB,
+    "deniedDomains" JSONB,
+    "updatedById" TEXT NOT NULL,
+    "createdAt" TIMESTAMP(3) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "updatedAt" TIMESTAMP(3) NOT NULL,
+
+    CONSTRAINT "EgressPolicy_pkey" PRIMARY KEY ("id")
+);
+
+-- CreateIndex
+CREATE UNIQUE INDEX "EgressPolicy_departmentId_key" ON "EgressPolicy"("departmentId");
+
+-- AddForeignKey
+ALTER TABLE "EgressPolicy" ADD CONSTRAINT "EgressPolicy_departmentId_fkey" FOREIGN KEY ("departmentId") REFERENCES "Department"("id") ON DELETE CASCADE ON UPDATE CASCADE;
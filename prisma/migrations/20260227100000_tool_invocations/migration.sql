@@ -0,0 +1,42 @@
+-- CreateEnum
+CREATE TYPE "ToolInvocationStatus" AS ENUM ('RUNNING', 'SUCCESS', 'ERROR', 'BLOCKED', 'DENIED', 'ABORTED');
+
+-- CreateTable
+CREATE TABLE "ToolInvocation" (
+    "id" TEXT NOT NULL,
+    "chatSessionId" TEXT NOT NULL,
+    "runId" TEXT NOT NULL,
+    "toolCallId" TEXT,
+    "userId" TEXT NOT NULL,
+    "instanceId" TEXT NOT NULL,
+    "agentId" TEXT NOT NULL,
+    "name" TEXT NOT NULL,
+    "args" JSONB,
+    "result" JSONB,
+    "truncated" BOOLEAN NOT NULL DEFAULT false,
+    "status" "ToolInvocationStatus" NOT NULL DEFAULT 'RUNNING',
+    "durationMs" INTEGER,
+    "dataKeyId" TEXT,
+    "startedAt" TIMESTAMP(3) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "finishedAt" TIMESTAMP(3),
+
+    CONSTRAINT "ToolInvocation_pkey" PRIMARY KEY ("id")
+);
+
+-- CreateIndex
+CREATE INDEX "ToolInvocation_chatSessionId_startedAt_idx" ON "ToolInvocation"("chatSessionId", "startedAt");
+
+-- CreateIndex
+CREATE INDEX "ToolInvocation_instanceId_agentId_startedAt_idx" ON "ToolInvocation"("instanceId", "agentId", "startedAt");
+
+-- CreateIndex
+CREATE INDEX "ToolInvocation_userId_startedAt_idx" ON "ToolInvocation"("userId", "startedAt");
+
+-- CreateIndex
+CREATE INDEX "ToolInvocation_name_idx" ON "ToolInvocation"("name");
+
+-- AddForeignKey
+ALTER TABLE "ToolInvocation" ADD CONSTRAINT "ToolInvocation_chatSessionId_fkey" FOREIGN KEY ("chatSessionId") REFERENCES "ChatSession"("id") ON DELETE CASCADE ON UPDATE CASCADE;
+
+-- AddForeignKey
+ALTER TABLE "ToolInvocation" ADD CONSTRAINT "ToolInvocation_dataKeyId_fkey" FOREIGN KEY ("dataKeyId") REFERENCES "DataKey"("id") ON DELETE RESTRICT ON UPDATE CASCADE;
@@ -0,0 +1,3 @@
+-- AlterTable
+ALTER TABLE "ChatSession" ADD COLUMN "closedReason" TEXT,
+ADD COLUMN "closedAt" TIMESTAMP(3);
@@ -0,0 +1,5 @@
+-- AlterTable
+ALTER TABLE "Department" ADD COLUMN "allowedRegions" JSONB;
+
+-- AlterTable
+ALTER TABLE "Instance" ADD COLUMN "region" TEXT;
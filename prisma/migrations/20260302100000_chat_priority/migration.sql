@@ -0,0 +1,5 @@
+-- CreateEnum
+CREATE TYPE "ChatPriority" AS ENUM ('HIGH', 'NORMAL', 'LOW');
+
+-- AlterTable
+ALTER TABLE "Department" ADD COLUMN "chatPriority" "ChatPriority" NOT NULL DEFAULT 'NORMAL';
@@ -0,0 +1,5 @@
+-- AlterTable
+ALTER TABLE "User" ADD COLUMN "ldapDn" TEXT;
+
+-- CreateIndex
+CREATE UNIQUE INDEX "User_ldapDn_key" ON "User"("ldapDn");
@@ -0,0 +1,25 @@
+-- AlterTable
+ALTER TABLE "User" ADD COLUMN "mfaSecret" TEXT,
+ADD COLUMN "mfaEnabled" BOOLEAN NOT NULL DEFAULT false,
+ADD COLUMN "mfaRequired" BOOLEAN NOT NULL DEFAULT false,
+ADD COLUMN "mfaLastStep" INTEGER;
+
+-- CreateTable
+CREATE TABLE "MfaRecoveryCode" (
+    "id" TEXT NOT NULL,
+    "userId" TEXT NOT NULL,
+    "codeHash" TEXT NOT NULL,
+    "usedAt" TIMESTAMP(3),
+    "createdAt" TIMESTAMP(3) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT "MfaRecoveryCode_pkey" PRIMARY KEY ("id")
+);
+
+-- CreateIndex
+CREATE UNIQUE INDEX "MfaRecoveryCode_codeHash_key" ON "MfaRecoveryCode"("codeHash");
+
+-- CreateIndex
+CREATE INDEX "MfaRecoveryCode_userId_idx" ON "MfaRecoveryCode"("userId");
+
+-- AddForeignKey
+ALTER TABLE "MfaRecoveryCode" ADD CONSTRAINT "MfaRecoveryCode_userId_fkey" FOREIGN KEY ("userId") REFERENCES "User"("id") ON DELETE CASCADE ON UPDATE CASCADE;
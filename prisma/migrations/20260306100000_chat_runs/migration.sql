@@ -0,0 +1,15 @@
+-- CreateTable
+CREATE TABLE "ChatRun" (
+    "id" TEXT NOT NULL,
+    "chatSessionId" TEXT NOT NULL,
+    "userId" TEXT NOT NULL,
+    "instanceId" TEXT NOT NULL,
+    "agentId" TEXT NOT NULL,
+    "sessionKey" TEXT NOT NULL,
+    "startedAt" TIMESTAMP(3) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT "ChatRun_pkey" PRIMARY KEY ("id")
+);
+
+-- AddForeignKey
+ALTER TABLE "ChatRun" ADD CONSTRAINT "ChatRun_chatSessionId_fkey" FOREIGN KEY ("chatSessionId") REFERENCES "ChatSession"("id") ON DELETE CASCADE ON UPDATE CASCADE;
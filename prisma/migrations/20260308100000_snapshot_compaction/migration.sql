@@ -0,0 +1,28 @@
+-- AlterTable
+ALTER TABLE "ChatMessageSnapshot" ADD COLUMN "trimmedAt" TIMESTAMP(3);
+
+-- CreateIndex
+CREATE INDEX "ChatMessageSnapshot_createdAt_idx" ON "ChatMessageSnapshot"("createdAt");
+
+-- CreateTable
+CREATE TABLE "ChatSessionSummary" (
+    "id" TEXT NOT NULL,
+    "chatSessionId" TEXT NOT NULL,
+    "summary" TEXT NOT NULL,
+    "dataKeyId" TEXT,
+    "messagesSummarized" INTEGER NOT NULL,
+    "coversUntil" TIMESTAMP(3) NOT NULL,
+    "createdAt" TIMESTAMP(3) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "updatedAt" TIMESTAMP(3) NOT NULL,
+
+    CONSTRAINT "ChatSessionSummary_pkey" PRIMARY KEY ("id")
+);
+
+-- CreateIndex
+CREATE UNIQUE INDEX "ChatSessionSummary_chatSessionId_key" ON "ChatSessionSummary"("chatSessionId");
+
+-- AddForeignKey
+ALTER TABLE "ChatSessionSummary" ADD CONSTRAINT "ChatSessionSummary_chatSessionId_fkey" FOREIGN KEY ("chatSessionId") REFERENCES "ChatSession"("id") ON DELETE CASCADE ON UPDATE CASCADE;
+
+-- AddForeignKey
+ALTER TABLE "ChatSessionSummary" ADD CONSTRAINT "ChatSessionSummary_dataKeyId_fkey" FOREIGN KEY ("dataKeyId") REFERENCES "DataKey"("id") ON DELETE RESTRICT ON UPDATE CASCADE;
@@ -0,0 +1,24 @@
+-- CreateEnum
+CREATE TYPE "AnnouncementLevel" AS ENUM ('INFO', 'WARNING');
+
+-- CreateTable
+CREATE TABLE "InstanceAnnouncement" (
+    "id" TEXT NOT NULL,
+    "instanceId" TEXT NOT NULL,
+    "message" TEXT NOT NULL,
+    "level" "AnnouncementLevel" NOT NULL DEFAULT 'INFO',
+    "expiresAt" TIMESTAMP(3) NOT NULL,
+    "createdById" TEXT NOT NULL,
+    "createdAt" TIMESTAMP(3) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT "InstanceAnnouncement_pkey" PRIMARY KEY ("id")
+);
+
+-- CreateIndex
+CREATE INDEX "InstanceAnnouncement_instanceId_expiresAt_idx" ON "InstanceAnnouncement"("instanceId", "expiresAt");
+
+-- AddForeignKey
+ALTER TABLE "InstanceAnnouncement" ADD CONSTRAINT "InstanceAnnouncement_instanceId_fkey" FOREIGN KEY ("instanceId") REFERENCES "Instance"("id") ON DELETE CASCADE ON UPDATE CASCADE;
+
+-- AddForeignKey
+ALTER TABLE "InstanceAnnouncement" ADD CONSTRAINT "InstanceAnnouncement_createdById_fkey" FOREIGN KEY ("createdById") REFERENCES "User"("id") ON DELETE RESTRICT ON UPDATE CASCADE;
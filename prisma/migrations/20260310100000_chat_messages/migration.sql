@@ -0,0 +1,61 @@
+-- CreateTable
+CREATE TABLE "ChatMessage" (
+    "id" TEXT NOT NULL,
+    "chatSessionId" TEXT NOT NULL,
+    "position" INTEGER NOT NULL,
+    "role" TEXT NOT NULL,
+    "content" TEXT NOT NULL,
+    "contentBlocks" JSONB,
+    "thinking" TEXT,
+    "toolCalls" JSONB,
+    "runId" TEXT,
+    "dataKeyId" TEXT,
+    "reconciledAt" TIMESTAMP(3),
+    "createdAt" TIMESTAMP(3) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT "ChatMessage_pkey" PRIMARY KEY ("id")
+);
+
+-- CreateIndex
+CREATE INDEX "ChatMessage_chatSessionId_position_idx" ON "ChatMessage"("chatSessionId", "position");
+
+-- CreateIndex
+CREATE INDEX "ChatMessage_reconciledAt_createdAt_idx" ON "ChatMessage"("reconciledAt", "createdAt");
+
+-- CreateIndex
+CREATE INDEX "ChatMessage_dataKeyId_idx" ON "ChatMessage"("dataKeyId");
+
+-- AddForeignKey
+ALTER TABLE "ChatMessage" ADD CONSTRAINT "ChatMessage_chatSessionId_fkey" FOREIGN KEY ("chatSessionId") REFERENCES "ChatSession"("id") ON DELETE CASCADE ON UPDATE CASCADE;
+
+-- AddForeignKey
+ALTER TABLE "ChatMessage" ADD CONSTRAINT "ChatMessage_dataKeyId_fkey" FOREIGN KEY ("dataKeyId") REFERENCES "DataKey"("id") ON DELETE RESTRICT ON UPDATE CASCADE;
+
+-- Move the post-run auto-snapshots of active sessions into the table. The
+-- liveMessages column never had a migration of its own, so it may be missing.
+DO $$
+BEGIN
+    IF EXISTS (
+        SELECT 1 FROM information_schema.columns
+        WHERE table_name = 'ChatSession' AND column_name = 'liveMessages'
+    ) THEN
+        INSERT INTO "ChatMessage" ("id", "chatSessionId", "position", "role", "content", "contentBlocks", "thinking", "toolCalls", "reconciledAt", "createdAt")
+        SELECT
+            gen_random_uuid()::text,
+            s."id",
+            m.ord - 1,
+            m.value->>'role',
+            COALESCE(m.value->>'content', ''),
+            m.value->'contentBlocks',
+            m.value->>'thinking',
+            m.value->'toolCalls',
+            s."updatedAt",
+            s."updatedAt"
+        FROM "ChatSession" s
+        CROSS JOIN LATERAL jsonb_array_elements(s."liveMessages") WITH ORDINALITY AS m(value, ord)
+        WHERE s."isActive" AND jsonb_typeof(s."liveMessages") = 'array'
+          AND m.value->>'role' IN ('user', 'assistant');
+
+        ALTER TABLE "ChatSession" DROP COLUMN "liveMessages";
+    END IF;
+END $$;
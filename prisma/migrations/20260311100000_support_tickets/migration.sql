@@ -0,0 +1,53 @@
+-- CreateEnum
+CREATE TYPE "SupportTicketCategory" AS ENUM ('INCORRECT', 'UNSAFE', 'BROKEN', 'OTHER');
+
+-- CreateEnum
+CREATE TYPE "SupportTicketStatus" AS ENUM ('OPEN', 'RESOLVED');
+
+-- CreateTable
+CREATE TABLE "SupportTicket" (
+    "id" TEXT NOT NULL,
+    "reporterId" TEXT NOT NULL,
+    "chatSessionId" TEXT,
+    "instanceId" TEXT NOT NULL,
+    "agentId" TEXT NOT NULL,
+    "messageId" TEXT,
+    "messageContent" TEXT NOT NULL,
+    "category" "SupportTicketCategory" NOT NULL,
+    "description" TEXT,
+    "diagnostics" JSONB NOT NULL,
+    "status" "SupportTicketStatus" NOT NULL DEFAULT 'OPEN',
+    "assigneeId" TEXT,
+    "externalStatus" INTEGER,
+    "resolution" TEXT,
+    "resolvedById" TEXT,
+    "resolvedAt" TIMESTAMP(3),
+    "createdAt" TIMESTAMP(3) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "updatedAt" TIMESTAMP(3) NOT NULL,
+
+    CONSTRAINT "SupportTicket_pkey" PRIMARY KEY ("id")
+);
+
+-- CreateIndex
+CREATE INDEX "SupportTicket_assigneeId_status_idx" ON "SupportTicket"("assigneeId", "status");
+
+-- CreateIndex
+CREATE INDEX "SupportTicket_instanceId_createdAt_idx" ON "SupportTicket"("instanceId", "createdAt");
+
+-- CreateIndex
+CREATE INDEX "SupportTicket_reporterId_idx" ON "SupportTicket"("reporterId");
+
+-- AddForeignKey
+ALTER TABLE "SupportTicket" ADD CONSTRAINT "SupportTicket_reporterId_fkey" FOREIGN KEY ("reporterId") REFERENCES "User"("id") ON DELETE CASCADE ON UPDATE CASCADE;
+
+-- AddForeignKey
+ALTER TABLE "SupportTicket" ADD CONSTRAINT "SupportTicket_chatSessionId_fkey" FOREIGN KEY ("chatSessionId") REFERENCES "ChatSession"("id") ON DELETE SET NULL ON UPDATE CASCADE;
+
+-- AddForeignKey
+ALTER TABLE "SupportTicket" ADD CONSTRAINT "SupportTicket_instanceId_fkey" FOREIGN KEY ("instanceId") REFERENCES "Instance"("id") ON DELETE CASCADE ON UPDATE CASCADE;
+
+-- AddForeignKey
+ALTER TABLE "SupportTicket" ADD CONSTRAINT "SupportTicket_assigneeId_fkey" FOREIGN KEY ("assigneeId") REFERENCES "User"("id") ON DELETE SET NULL ON UPDATE CASCADE;
+
+-- AddForeignKey
+ALTER TABLE "SupportTicket" ADD CONSTRAINT "SupportTicket_resolvedById_fkey" FOREIGN KEY ("resolvedById") REFERENCES "User"("id") ON DELETE SET NULL ON UPDATE CASCADE;
@@ -0,0 +1,20 @@
+-- Full-text search over chat history (lib/chat/search). Sealed rows hold
+-- ciphertext, so they get no search vector.
+
+-- AlterTable
+ALTER TABLE "ChatMessageSnapshot" ADD COLUMN "searchVector" tsvector
+    GENERATED ALWAYS AS (
+        CASE WHEN "dataKeyId" IS NULL THEN to_tsvector('simple', "content") END
+    ) STORED;
+
+-- AlterTable
+ALTER TABLE "ChatMessage" ADD COLUMN "searchVector" tsvector
+    GENERATED ALWAYS AS (
+        CASE WHEN "dataKeyId" IS NULL THEN to_tsvector('simple', "content") END
+    ) STORED;
+
+-- CreateIndex
+CREATE INDEX "ChatMessageSnapshot_searchVector_idx" ON "ChatMessageSnapshot" USING GIN ("searchVector");
+
+-- CreateIndex
+CREATE INDEX "ChatMessage_searchVector_idx" ON "ChatMessage" USING GIN ("searchVector");
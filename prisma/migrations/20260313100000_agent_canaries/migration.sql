@@ -0,0 +1,65 @@
+-- CreateEnum
+CREATE TYPE "CanaryArm" AS ENUM ('CONTROL', 'CANARY');
+
+-- AlterTable
+ALTER TABLE "ChatSession" ADD COLUMN "canaryId" TEXT,
+ADD COLUMN "canaryArm" "CanaryArm",
+ADD COLUMN "routedAgentId" TEXT;
+
+-- CreateTable
+CREATE TABLE "AgentCanary" (
+    "id" TEXT NOT NULL,
+    "name" TEXT NOT NULL,
+    "departmentId" TEXT NOT NULL,
+    "instanceId" TEXT NOT NULL,
+    "controlAgentId" TEXT NOT NULL,
+    "canaryAgentId" TEXT NOT NULL,
+    "percent" INTEGER NOT NULL,
+    "enabled" BOOLEAN NOT NULL DEFAULT true,
+    "createdById" TEXT NOT NULL,
+    "createdAt" TIMESTAMP(3) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "updatedAt" TIMESTAMP(3) NOT NULL,
+
+    CONSTRAINT "AgentCanary_pkey" PRIMARY KEY ("id")
+);
+
+-- CreateTable
+CREATE TABLE "CanaryRun" (
+    "id" TEXT NOT NULL,
+    "canaryId" TEXT NOT NULL,
+    "chatSessionId" TEXT NOT NULL,
+    "arm" "CanaryArm" NOT NULL,
+    "outcome" TEXT NOT NULL,
+    "firstTokenMs" INTEGER,
+    "durationMs" INTEGER NOT NULL,
+    "createdAt" TIMESTAMP(3) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT "CanaryRun_pkey" PRIMARY KEY ("id")
+);
+
+-- CreateIndex
+CREATE INDEX "ChatSession_canaryId_canaryArm_idx" ON "ChatSession"("canaryId", "canaryArm");
+
+-- CreateIndex
+CREATE INDEX "AgentCanary_departmentId_instanceId_controlAgentId_idx" ON "AgentCanary"("departmentId", "instanceId", "controlAgentId");
+
+-- CreateIndex
+CREATE INDEX "CanaryRun_canaryId_arm_idx" ON "CanaryRun"("canaryId", "arm");
+
+-- AddForeignKey
+ALTER TABLE "ChatSession" ADD CONSTRAINT "ChatSession_canaryId_fkey" FOREIGN KEY ("canaryId") REFERENCES "AgentCanary"("id") ON DELETE SET NULL ON UPDATE CASCADE;
+
+-- AddForeignKey
+ALTER TABLE "AgentCanary" ADD CONSTRAINT "AgentCanary_departmentId_fkey" FOREIGN KEY ("departmentId") REFERENCES "Department"("id") ON DELETE CASCADE ON UPDATE CASCADE;
+
+-- AddForeignKey
+ALTER TABLE "AgentCanary" ADD CONSTRAINT "AgentCanary_instanceId_fkey" FOREIGN KEY ("instanceId") REFERENCES "Instance"("id") ON DELETE CASCADE ON UPDATE CASCADE;
+
+-- AddForeignKey
+ALTER TABLE "AgentCanary" ADD CONSTRAINT "AgentCanary_createdById_fkey" FOREIGN KEY ("createdById") REFERENCES "User"("id") ON DELETE RESTRICT ON UPDATE CASCADE;
+
+-- AddForeignKey
+ALTER TABLE "CanaryRun" ADD CONSTRAINT "CanaryRun_canaryId_fkey" FOREIGN KEY ("canaryId") REFERENCES "AgentCanary"("id") ON DELETE CASCADE ON UPDATE CASCADE;
+
+-- AddForeignKey
+ALTER TABLE "CanaryRun" ADD CONSTRAINT "CanaryRun_chatSessionId_fkey" FOREIGN KEY ("chatSessionId") REFERENCES "ChatSession"("id") ON DELETE CASCADE ON UPDATE CASCADE;
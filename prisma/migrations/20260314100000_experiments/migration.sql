@@ -0,0 +1,36 @@
+-- CreateEnum
+CREATE TYPE "ExperimentStatus" AS ENUM ('DRAFT', 'RUNNING', 'STOPPED');
+
+-- CreateTable
+CREATE TABLE "Experiment" (
+    "id" TEXT NOT NULL,
+    "name" TEXT NOT NULL,
+    "hypothesis" TEXT,
+    "canaryId" TEXT,
+    "instanceId" TEXT NOT NULL,
+    "controlAgentId" TEXT NOT NULL,
+    "canaryAgentId" TEXT NOT NULL,
+    "status" "ExperimentStatus" NOT NULL DEFAULT 'DRAFT',
+    "startVersions" JSONB,
+    "endVersions" JSONB,
+    "results" JSONB,
+    "startedAt" TIMESTAMP(3),
+    "endedAt" TIMESTAMP(3),
+    "createdById" TEXT NOT NULL,
+    "createdAt" TIMESTAMP(3) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "updatedAt" TIMESTAMP(3) NOT NULL,
+
+    CONSTRAINT "Experiment_pkey" PRIMARY KEY ("id")
+);
+
+-- CreateIndex
+CREATE INDEX "Experiment_status_idx" ON "Experiment"("status");
+
+-- CreateIndex
+CREATE INDEX "Experiment_canaryId_idx" ON "Experiment"("canaryId");
+
+-- AddForeignKey
+ALTER TABLE "Experiment" ADD CONSTRAINT "Experiment_canaryId_fkey" FOREIGN KEY ("canaryId") REFERENCES "AgentCanary"("id") ON DELETE SET NULL ON UPDATE CASCADE;
+
+-- AddForeignKey
+ALTER TABLE "Experiment" ADD CONSTRAINT "Experiment_createdById_fkey" FOREIGN KEY ("createdById") REFERENCES "User"("id") ON DELETE RESTRICT ON UPDATE CASCADE;
@@ -0,0 +1,77 @@
+-- CreateEnum
+CREATE TYPE "EvalRunStatus" AS ENUM ('QUEUED', 'RUNNING', 'COMPLETED', 'FAILED');
+
+-- CreateTable
+CREATE TABLE "EvalSet" (
+    "id" TEXT NOT NULL,
+    "name" TEXT NOT NULL,
+    "description" TEXT,
+    "cases" JSONB NOT NULL,
+    "judgeResourceId" TEXT,
+    "judgeModel" TEXT,
+    "createdById" TEXT NOT NULL,
+    "createdAt" TIMESTAMP(3) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "updatedAt" TIMESTAMP(3) NOT NULL,
+
+    CONSTRAINT "EvalSet_pkey" PRIMARY KEY ("id")
+);
+
+-- CreateTable
+CREATE TABLE "EvalRun" (
+    "id" TEXT NOT NULL,
+    "setId" TEXT NOT NULL,
+    "instanceId" TEXT NOT NULL,
+    "agentId" TEXT NOT NULL,
+    "status" "EvalRunStatus" NOT NULL DEFAULT 'QUEUED',
+    "agentVersion" JSONB,
+    "caseCount" INTEGER NOT NULL DEFAULT 0,
+    "passed" INTEGER NOT NULL DEFAULT 0,
+    "failed" INTEGER NOT NULL DEFAULT 0,
+    "error" TEXT,
+    "startedAt" TIMESTAMP(3),
+    "finishedAt" TIMESTAMP(3),
+    "createdById" TEXT NOT NULL,
+    "createdAt" TIMESTAMP(3) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT "EvalRun_pkey" PRIMARY KEY ("id")
+);
+
+-- CreateTable
+CREATE TABLE "EvalResult" (
+    "id" TEXT NOT NULL,
+    "runId" TEXT NOT NULL,
+    "caseKey" TEXT NOT NULL,
+    "prompt" TEXT NOT NULL,
+    "scorer" TEXT NOT NULL,
+    "reply" TEXT,
+    "passed" BOOLEAN NOT NULL,
+    "reason" TEXT,
+    "durationMs" INTEGER,
+    "createdAt" TIMESTAMP(3) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT "EvalResult_pkey" PRIMARY KEY ("id")
+);
+
+-- CreateIndex
+CREATE INDEX "EvalRun_setId_createdAt_idx" ON "EvalRun"("setId", "createdAt");
+
+-- CreateIndex
+CREATE INDEX "EvalRun_status_idx" ON "EvalRun"("status");
+
+-- CreateIndex
+CREATE UNIQUE INDEX "EvalResult_runId_caseKey_key" ON "EvalResult"("runId", "caseKey");
+
+-- AddForeignKey
+ALTER TABLE "EvalSet" ADD CONSTRAINT "EvalSet_judgeResourceId_fkey" FOREIGN KEY ("judgeResourceId") REFERENCES "Resource"("id") ON DELETE SET NULL ON UPDATE CASCADE;
+
+-- AddForeignKey
+ALTER TABLE "EvalSet" ADD CONSTRAINT "EvalSet_createdById_fkey" FOREIGN KEY ("createdById") REFERENCES "User"("id") ON DELETE RESTRICT ON UPDATE CASCADE;
+
+-- AddForeignKey
+ALTER TABLE "EvalRun" ADD CONSTRAINT "EvalRun_setId_fkey" FOREIGN KEY ("setId") REFERENCES "EvalSet"("id") ON DELETE CASCADE ON UPDATE CASCADE;
+
+-- AddForeignKey
+ALTER TABLE "EvalRun" ADD CONSTRAINT "EvalRun_createdById_fkey" FOREIGN KEY ("createdById") REFERENCES "User"("id") ON DELETE RESTRICT ON UPDATE CASCADE;
+
+-- AddForeignKey
+ALTER TABLE "EvalResult" ADD CONSTRAINT "EvalResult_runId_fkey" FOREIGN KEY ("runId") REFERENCES "EvalRun"("id") ON DELETE CASCADE ON UPDATE CASCADE;
@@ -0,0 +1,41 @@
+-- CreateTable
+CREATE TABLE "AgentPreamble" (
+    "id" TEXT NOT NULL,
+    "instanceId" TEXT NOT NULL,
+    "agentId" TEXT NOT NULL,
+    "template" TEXT NOT NULL,
+    "enabled" BOOLEAN NOT NULL DEFAULT true,
+    "version" INTEGER NOT NULL DEFAULT 1,
+    "createdAt" TIMESTAMP(3) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "updatedAt" TIMESTAMP(3) NOT NULL,
+
+    CONSTRAINT "AgentPreamble_pkey" PRIMARY KEY ("id")
+);
+
+-- CreateTable
+CREATE TABLE "AgentPreambleVersion" (
+    "id" TEXT NOT NULL,
+    "preambleId" TEXT NOT NULL,
+    "version" INTEGER NOT NULL,
+    "template" TEXT NOT NULL,
+    "enabled" BOOLEAN NOT NULL,
+    "createdById" TEXT NOT NULL,
+    "createdAt" TIMESTAMP(3) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT "AgentPreambleVersion_pkey" PRIMARY KEY ("id")
+);
+
+-- CreateIndex
+CREATE UNIQUE INDEX "AgentPreamble_instanceId_agentId_key" ON "AgentPreamble"("instanceId", "agentId");
+
+-- CreateIndex
+CREATE UNIQUE INDEX "AgentPreambleVersion_preambleId_version_key" ON "AgentPreambleVersion"("preambleId", "version");
+
+-- AddForeignKey
+ALTER TABLE "AgentPreamble" ADD CONSTRAINT "AgentPreamble_instanceId_fkey" FOREIGN KEY ("instanceId") REFERENCES "Instance"("id") ON DELETE CASCADE ON UPDATE CASCADE;
+
+-- AddForeignKey
+ALTER TABLE "AgentPreambleVersion" ADD CONSTRAINT "AgentPreambleVersion_preambleId_fkey" FOREIGN KEY ("preambleId") REFERENCES "AgentPreamble"("id") ON DELETE CASCADE ON UPDATE CASCADE;
+
+-- AddForeignKey
+ALTER TABLE "AgentPreambleVersion" ADD CONSTRAINT "AgentPreambleVersion_createdById_fkey" FOREIGN KEY ("createdById") REFERENCES "User"("id") ON DELETE RESTRICT ON UPDATE CASCADE;
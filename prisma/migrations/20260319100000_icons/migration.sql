@@ -0,0 +1,11 @@
+-- AlterTable
+ALTER TABLE "Department" ADD COLUMN "iconFileId" TEXT;
+
+-- AlterTable
+ALTER TABLE "AgentMeta" ADD COLUMN "iconFileId" TEXT;
+
+-- AddForeignKey
+ALTER TABLE "Department" ADD CONSTRAINT "Department_iconFileId_fkey" FOREIGN KEY ("iconFileId") REFERENCES "StoredFile"("id") ON DELETE SET NULL ON UPDATE CASCADE;
+
+-- AddForeignKey
+ALTER TABLE "AgentMeta" ADD CONSTRAINT "AgentMeta_iconFileId_fkey" FOREIGN KEY ("iconFileId") REFERENCES "StoredFile"("id") ON DELETE SET NULL ON UPDATE CASCADE;
@@ -0,0 +1,25 @@
+-- CreateTable
+CREATE TABLE "GatewayEvent" (
+    "id" TEXT NOT NULL,
+    "instanceId" TEXT NOT NULL,
+    "event" TEXT NOT NULL,
+    "state" TEXT,
+    "runId" TEXT,
+    "sessionKey" TEXT,
+    "payload" JSONB NOT NULL,
+    "createdAt" TIMESTAMP(3) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT "GatewayEvent_pkey" PRIMARY KEY ("id")
+);
+
+-- CreateIndex
+CREATE INDEX "GatewayEvent_instanceId_createdAt_idx" ON "GatewayEvent"("instanceId", "createdAt");
+
+-- CreateIndex
+CREATE INDEX "GatewayEvent_runId_idx" ON "GatewayEvent"("runId");
+
+-- CreateIndex
+CREATE INDEX "GatewayEvent_createdAt_idx" ON "GatewayEvent"("createdAt");
+
+-- AddForeignKey
+ALTER TABLE "GatewayEvent" ADD CONSTRAINT "GatewayEvent_instanceId_fkey" FOREIGN KEY ("instanceId") REFERENCES "Instance"("id") ON DELETE CASCADE ON UPDATE CASCADE;
@@ -0,0 +1,22 @@
+-- CreateTable
+CREATE TABLE "SavedFilter" (
+    "id" TEXT NOT NULL,
+    "name" TEXT NOT NULL,
+    "endpoint" TEXT NOT NULL,
+    "query" JSONB NOT NULL,
+    "shared" BOOLEAN NOT NULL DEFAULT false,
+    "ownerId" TEXT NOT NULL,
+    "createdAt" TIMESTAMP(3) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "updatedAt" TIMESTAMP(3) NOT NULL,
+
+    CONSTRAINT "SavedFilter_pkey" PRIMARY KEY ("id")
+);
+
+-- CreateIndex
+CREATE UNIQUE INDEX "SavedFilter_ownerId_endpoint_name_key" ON "SavedFilter"("ownerId", "endpoint", "name");
+
+-- CreateIndex
+CREATE INDEX "SavedFilter_endpoint_shared_idx" ON "SavedFilter"("endpoint", "shared");
+
+-- AddForeignKey
+ALTER TABLE "SavedFilter" ADD CONSTRAINT "SavedFilter_ownerId_fkey" FOREIGN KEY ("ownerId") REFERENCES "User"("id") ON DELETE CASCADE ON UPDATE CASCADE;
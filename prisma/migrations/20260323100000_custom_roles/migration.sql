@@ -0,0 +1,27 @@
+-- CreateTable
+CREATE TABLE "CustomRole" (
+    "id" TEXT NOT NULL,
+    "name" TEXT NOT NULL,
+    "description" TEXT,
+    "permissions" TEXT[] DEFAULT ARRAY[]::TEXT[],
+    "createdById" TEXT,
+    "createdAt" TIMESTAMP(3) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "updatedAt" TIMESTAMP(3) NOT NULL,
+
+    CONSTRAINT "CustomRole_pkey" PRIMARY KEY ("id")
+);
+
+-- AlterTable
+ALTER TABLE "User" ADD COLUMN "customRoleId" TEXT;
+
+-- CreateIndex
+CREATE UNIQUE INDEX "CustomRole_name_key" ON "CustomRole"("name");
+
+-- CreateIndex
+CREATE INDEX "User_customRoleId_idx" ON "User"("customRoleId");
+
+-- AddForeignKey
+ALTER TABLE "User" ADD CONSTRAINT "User_customRoleId_fkey" FOREIGN KEY ("customRoleId") REFERENCES "CustomRole"("id") ON DELETE SET NULL ON UPDATE CASCADE;
+
+-- AddForeignKey
+ALTER TABLE "CustomRole" ADD CONSTRAINT "CustomRole_createdById_fkey" FOREIGN KEY ("createdById") REFERENCES "User"("id") ON DELETE SET NULL ON UPDATE CASCADE;
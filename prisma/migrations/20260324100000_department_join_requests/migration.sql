@@ -0,0 +1,33 @@
+-- CreateEnum
+CREATE TYPE "JoinRequestStatus" AS ENUM ('PENDING', 'APPROVED', 'REJECTED', 'WITHDRAWN');
+
+-- CreateTable
+CREATE TABLE "DepartmentJoinRequest" (
+    "id" TEXT NOT NULL,
+    "userId" TEXT NOT NULL,
+    "departmentId" TEXT NOT NULL,
+    "message" TEXT,
+    "status" "JoinRequestStatus" NOT NULL DEFAULT 'PENDING',
+    "reviewedById" TEXT,
+    "reviewComment" TEXT,
+    "reviewedAt" TIMESTAMP(3),
+    "createdAt" TIMESTAMP(3) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "updatedAt" TIMESTAMP(3) NOT NULL,
+
+    CONSTRAINT "DepartmentJoinRequest_pkey" PRIMARY KEY ("id")
+);
+
+-- CreateIndex
+CREATE INDEX "DepartmentJoinRequest_departmentId_status_idx" ON "DepartmentJoinRequest"("departmentId", "status");
+
+-- CreateIndex
+CREATE INDEX "DepartmentJoinRequest_userId_status_idx" ON "DepartmentJoinRequest"("userId", "status");
+
+-- AddForeignKey
+ALTER TABLE "DepartmentJoinRequest" ADD CONSTRAINT "DepartmentJoinRequest_userId_fkey" FOREIGN KEY ("userId") REFERENCES "User"("id") ON DELETE CASCADE ON UPDATE CASCADE;
+
+-- AddForeignKey
+ALTER TABLE "DepartmentJoinRequest" ADD CONSTRAINT "DepartmentJoinRequest_departmentId_fkey" FOREIGN KEY ("departmentId") REFERENCES "Department"("id") ON DELETE CASCADE ON UPDATE CASCADE;
+
+-- AddForeignKey
+ALTER TABLE "DepartmentJoinRequest" ADD CONSTRAINT "DepartmentJoinRequest_reviewedById_fkey" FOREIGN KEY ("reviewedById") REFERENCES "User"("id") ON DELETE SET NULL ON UPDATE CASCADE;
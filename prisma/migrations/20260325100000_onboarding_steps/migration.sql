@@ -0,0 +1,14 @@
+-- CreateTable
+CREATE TABLE "OnboardingStep" (
+    "userId" TEXT NOT NULL,
+    "step" TEXT NOT NULL,
+    "completedAt" TIMESTAMP(3) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT "OnboardingStep_pkey" PRIMARY KEY ("userId","step")
+);
+
+-- CreateIndex
+CREATE INDEX "OnboardingStep_step_idx" ON "OnboardingStep"("step");
+
+-- AddForeignKey
+ALTER TABLE "OnboardingStep" ADD CONSTRAINT "OnboardingStep_userId_fkey" FOREIGN KEY ("userId") REFERENCES "User"("id") ON DELETE CASCADE ON UPDATE CASCADE;
@@ -0,0 +1,31 @@
+-- CreateTable
+CREATE TABLE "UserInstanceAccess" (
+    "id" TEXT NOT NULL,
+    "userId" TEXT NOT NULL,
+    "instanceId" TEXT NOT NULL,
+    "agentIds" JSONB,
+    "grantedById" TEXT NOT NULL,
+    "expiresAt" TIMESTAMP(3),
+    "createdAt" TIMESTAMP(3) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "updatedAt" TIMESTAMP(3) NOT NULL,
+
+    CONSTRAINT "UserInstanceAccess_pkey" PRIMARY KEY ("id")
+);
+
+-- CreateIndex
+CREATE UNIQUE INDEX "UserInstanceAccess_userId_instanceId_key" ON "UserInstanceAccess"("userId", "instanceId");
+
+-- CreateIndex
+CREATE INDEX "UserInstanceAccess_instanceId_idx" ON "UserInstanceAccess"("instanceId");
+
+-- CreateIndex
+CREATE INDEX "UserInstanceAccess_expiresAt_idx" ON "UserInstanceAccess"("expiresAt");
+
+-- AddForeignKey
+ALTER TABLE "UserInstanceAccess" ADD CONSTRAINT "UserInstanceAccess_userId_fkey" FOREIGN KEY ("userId") REFERENCES "User"("id") ON DELETE CASCADE ON UPDATE CASCADE;
+
+-- AddForeignKey
+ALTER TABLE "UserInstanceAccess" ADD CONSTRAINT "UserInstanceAccess_instanceId_fkey" FOREIGN KEY ("instanceId") REFERENCES "Instance"("id") ON DELETE CASCADE ON UPDATE CASCADE;
+
+-- AddForeignKey
+ALTER TABLE "UserInstanceAccess" ADD CONSTRAINT "UserInstanceAccess_grantedById_fkey" FOREIGN KEY ("grantedById") REFERENCES "User"("id") ON DELETE RESTRICT ON UPDATE CASCADE;
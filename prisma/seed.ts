@@ -2,10 +2,13 @@ import 'dotenv/config'
 import { Pool } from 'pg'
 import { PrismaPg } from '@prisma/adapter-pg'
 import { PrismaClient } from '../src/generated/prisma'
+import { createDialectAdapter, dbDialect } from '../src/lib/db-dialect'
 import bcrypt from 'bcryptjs'
 
-const pool = new Pool({ connectionString: process.env.DATABASE_URL })
-const adapter = new PrismaPg(pool)
+const adapter =
+  dbDialect() === 'postgresql'
+    ? new PrismaPg(new Pool({ connectionString: process.env.DATABASE_URL }))
+    : createDialectAdapter(process.env.DATABASE_URL!)
 const prisma = new PrismaClient({ adapter })
 
 async function main() {
@@ -0,0 +1,64 @@
+/**
+ * Dialect Smoke Check
+ *
+ * Runs the queries whose shape differs per database dialect (lib/db-dialect)
+ * against the database in DATABASE_URL: case-insensitive search, scalar list
+ * round trips and list filters. CI runs it after creating the schema for
+ * each dialect, so a helper that only works on Postgres fails the build.
+ *
+ * Usage: npx tsx scripts/check-dialect.ts
+ *
+ * Exit code 0 = all checks pass, 1 = a check failed.
+ */
+
+import { prisma } from '@/lib/db'
+import { containsInsensitive, dbDialect, listHas, stringList } from '@/lib/db-dialect'
+
+const NAME = 'CI_Dialect_Check'
+
+const failures: string[] = []
+
+function expect(label: string, ok: boolean) {
+  console.log(`${ok ? '✅' : '❌'} ${label}`)
+  if (!ok) failures.push(label)
+}
+
+async function main() {
+  console.log(`Dialect: ${dbDialect()}`)
+  await prisma.customRole.deleteMany({ where: { name: NAME } })
+
+  const role = await prisma.customRole.create({
+    data: { name: NAME, permissions: ['chat:use', 'models:view'] },
+  })
+  try {
+    const read = await prisma.customRole.findUnique({ where: { id: role.id } })
+    expect('scalar list round trip', stringList(read?.permissions).join(',') === 'chat:use,models:view')
+
+    const lower = await prisma.customRole.findMany({ where: { name: containsInsensitive('dialect_check') } })
+    expect('containsInsensitive ignores case', lower.some((r) => r.id === role.id))
+
+    const upper = await prisma.customRole.findMany({ where: { name: containsInsensitive('CI_DIALECT') } })
+    expect('containsInsensitive matches upper case', upper.some((r) => r.id === role.id))
+
+    const has = await prisma.customRole.findMany({ where: { permissions: listHas('models:view') } })
+    expect('listHas finds a member', has.some((r) => r.id === role.id))
+
+    const hasNot = await prisma.customRole.findMany({ where: { permissions: listHas('models:manage') } })
+    expect('listHas skips a non-member', !hasNot.some((r) => r.id === role.id))
+  } finally {
+    await prisma.customRole.delete({ where: { id: role.id } })
+  }
+
+  if (failures.length > 0) {
+    console.error(`\n❌ ${failures.length} dialect check(s) failed`)
+    process.exit(1)
+  }
+  console.log(`\n✅ All dialect checks passed (${dbDialect()})`)
+}
+
+main()
+  .catch((err) => {
+    console.error(err)
+    process.exit(1)
+  })
+  .finally(() => prisma.$disconnect())
@@ -0,0 +1,71 @@
+/**
+ * User Data Coverage Check
+ *
+ * Lists every Prisma model that references a user — a User relation, or a
+ * user ID column such as userId / createdById — and compares it with
+ * USER_DATA_COVERAGE (lib/users/data-coverage). A model missing there has
+ * no decision on how data exports and erasure treat it, so CI fails until
+ * one is recorded.
+ *
+ * Usage: npx tsx scripts/check-user-data.ts
+ *
+ * Exit code 0 = every model covered, 1 = missing or stale entries.
+ */
+
+import fs from 'node:fs'
+import path from 'node:path'
+import { fileURLToPath } from 'node:url'
+import { USER_DATA_COVERAGE } from '@/lib/users/data-coverage'
+
+const __dirname = path.dirname(fileURLToPath(import.meta.url))
+const SCHEMA_FILE = path.resolve(__dirname, '../prisma/schema.prisma')
+
+// Scalar columns holding a user ID, for models that keep one without a relation
+const USER_ID_COLUMN =
+  /^(userId|ownerId|creatorId|reporterId|reviewerId|assigneeId|serviceAccountId|\w+UserId|\w+ById)$/
+
+function userModels(schema: string): Map<string, string[]> {
+  const models = new Map<string, string[]>()
+  for (const match of schema.matchAll(/^model (\w+) \{\n([\s\S]*?)^\}/gm)) {
+    const [, name, body] = match
+    if (name === 'User') continue
+    const fields: string[] = []
+    for (const line of body.split('\n')) {
+      const field = line.trim().match(/^(\w+)[ \t]+(\w+)(\[\])?\??/)
+      if (!field) continue
+      const [, fieldName, type, list] = field
+      // User[] is the other side of a relation whose key lives on User
+      if ((type === 'User' && !list) || (type === 'String' && USER_ID_COLUMN.test(fieldName))) {
+        fields.push(fieldName)
+      }
+    }
+    if (fields.length > 0) models.set(name, fields)
+  }
+  return models
+}
+
+const models = userModels(fs.readFileSync(SCHEMA_FILE, 'utf-8'))
+const covered = new Set(Object.keys(USER_DATA_COVERAGE))
+
+const missing = [...models.keys()].filter((m) => !covered.has(m))
+const stale = [...covered].filter((m) => !models.has(m))
+
+console.log(`User-referencing models: ${models.size}`)
+console.log(`Covered:                 ${covered.size}`)
+
+if (missing.length > 0) {
+  console.error(`\n❌ Models referencing a user missing from USER_DATA_COVERAGE (${missing.length}):`)
+  for (const m of missing) console.error(`  - ${m} (${models.get(m)!.join(', ')})`)
+  console.error('\nHandle them in lib/users/data-rights and record it in data-coverage.ts.')
+}
+
+if (stale.length > 0) {
+  console.error(`\n❌ USER_DATA_COVERAGE entries that no longer reference a user (${stale.length}):`)
+  for (const m of stale) console.error(`  - ${m}`)
+}
+
+if (missing.length > 0 || stale.length > 0) {
+  process.exit(1)
+}
+
+console.log('\n✅ Every user-referencing model is covered')
@@ -0,0 +1,60 @@
+/**
+ * Dialect Schema Generator
+ *
+ * Derives prisma/schema.<dialect>.prisma from the Postgres schema so there is
+ * only one schema to maintain: swaps the datasource provider, strips native
+ * type attributes (@db.*) the target doesn't support, drops Postgres-only
+ * columns (tsvector) and turns scalar lists (String[], enum lists), which
+ * only Postgres has, into nullable Json columns holding the array (read them
+ * through stringList() and filter them with listHas() from lib/db-dialect).
+ * prisma.config.ts picks the generated file from the DATABASE_URL scheme.
+ *
+ * Usage: npx tsx scripts/dialect-schema.ts <sqlite|mysql>
+ */
+
+import fs from 'node:fs'
+import path from 'node:path'
+import { fileURLToPath } from 'node:url'
+
+const __dirname = path.dirname(fileURLToPath(import.meta.url))
+const SOURCE = path.resolve(__dirname, '../prisma/schema.prisma')
+
+// Native types each dialect keeps; everything else falls back to the default mapping
+const KEPT_NATIVE_TYPES: Record<string, string[]> = {
+  sqlite: [],
+  mysql: ['Text'],
+}
+
+const dialect = process.argv[2]
+if (!dialect || !(dialect in KEPT_NATIVE_TYPES)) {
+  console.error(`Usage: npx tsx scripts/dialect-schema.ts <${Object.keys(KEPT_NATIVE_TYPES).join('|')}>`)
+  process.exit(1)
+}
+
+const SCALAR_TYPES = ['String', 'Int', 'BigInt', 'Float', 'Decimal', 'Boolean', 'DateTime']
+
+const source = fs.readFileSync(SOURCE, 'utf-8')
+const kept = KEPT_NATIVE_TYPES[dialect]
+const listTypes = new Set([...SCALAR_TYPES, ...[...source.matchAll(/^enum\s+(\w+)/gm)].map((m) => m[1])])
+
+const derived = source
+  .replace(/provider\s*=\s*"postgresql"/, `provider = "${dialect}"`)
+  .replace(/\s*@db\.(\w+)(\([^)]*\))?/g, (match, type: string) => (kept.includes(type) ? match : ''))
+  .replace(/^.*Unsupported\("tsvector"\).*\n/gm, '')
+  // Relation lists (Model[]) stay; the @default([]) goes, null reads as empty
+  .replace(
+    /^([ \t]+\w+[ \t]+)(\w+)\[\]([ \t]*)(?:@default\(\[\]\))?/gm,
+    (match, field: string, type: string, pad: string) => (listTypes.has(type) ? `${field}Json?${pad}` : match),
+  )
+
+if (!derived.includes(`provider = "${dialect}"`)) {
+  console.error('Could not find the postgresql datasource in prisma/schema.prisma')
+  process.exit(1)
+}
+
+const target = path.resolve(__dirname, `../prisma/schema.${dialect}.prisma`)
+fs.writeFileSync(
+  target,
+  `// Generated from schema.prisma by scripts/dialect-schema.ts — do not edit.\n\n${derived}`,
+)
+console.log(`Wrote ${path.relative(process.cwd(), target)}`)
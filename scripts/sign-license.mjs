@@ -0,0 +1,64 @@
+#!/usr/bin/env node
+/**
+ * Issue TeamClaw licenses (Ed25519).
+ *
+ *   node scripts/sign-license.mjs keygen
+ *   LICENSE_SIGNING_KEY=<base64 pem> node scripts/sign-license.mjs sign \
+ *     --id LIC-001 --licensee "Acme" --seats 50 --instances 5 --expires 2027-01-01 [--grace 14]
+ *
+ * The printed license goes into LICENSE_FILE (or LICENSE_KEY); the public key
+ * from keygen goes into LICENSE_PUBLIC_KEY.
+ */
+import { generateKeyPairSync, createPrivateKey, sign } from 'crypto'
+
+function arg(name) {
+  const i = process.argv.indexOf(`--${name}`)
+  return i === -1 ? undefined : process.argv[i + 1]
+}
+
+function intArg(name) {
+  const v = arg(name)
+  return v === undefined ? null : parseInt(v, 10)
+}
+
+function keygen() {
+  const { publicKey, privateKey } = generateKeyPairSync('ed25519')
+  const pub = publicKey.export({ type: 'spki', format: 'pem' })
+  const priv = privateKey.export({ type: 'pkcs8', format: 'pem' })
+  console.log('# Keep the signing key private; ship the public key with deployments:\n')
+  console.log(`LICENSE_SIGNING_KEY="${Buffer.from(priv).toString('base64')}"`)
+  console.log(`LICENSE_PUBLIC_KEY="${Buffer.from(pub).toString('base64')}"`)
+}
+
+function signLicense() {
+  const b64 = process.env.LICENSE_SIGNING_KEY
+  if (!b64) throw new Error('LICENSE_SIGNING_KEY is not set')
+  const id = arg('id')
+  const licensee = arg('licensee')
+  const expires = arg('expires')
+  if (!id || !licensee || !expires) throw new Error('--id, --licensee and --expires are required')
+
+  const payload = {
+    licenseId: id,
+    licensee,
+    maxSeats: intArg('seats'),
+    maxInstances: intArg('instances'),
+    issuedAt: new Date().toISOString(),
+    expiresAt: new Date(expires).toISOString(),
+    graceDays: intArg('grace') ?? 14,
+  }
+  const body = Buffer.from(JSON.stringify(payload)).toString('base64url')
+  const key = createPrivateKey(Buffer.from(b64, 'base64').toString('utf8'))
+  const signature = sign(null, Buffer.from(body), key).toString('base64url')
+  console.log(`${body}.${signature}`)
+}
+
+const cmd = process.argv[2]
+try {
+  if (cmd === 'keygen') keygen()
+  else if (cmd === 'sign') signLicense()
+  else console.log('Usage: sign-license.mjs keygen | sign --id ... --licensee ... --expires YYYY-MM-DD')
+} catch (err) {
+  console.error(err.message)
+  process.exit(1)
+}
@@ -1,6 +1,6 @@
 "use client"
 
-import { LOGO_SRC } from "@/lib/logo"
+import { useBranding } from "@/hooks/use-branding"
 import { ThemeToggle } from "@/components/theme-toggle"
 import { useT } from "@/stores/language-store"
 
@@ -10,6 +10,8 @@ export default function AuthLayout({
   children: React.ReactNode
 }) {
   const t = useT()
+  const branding = useBranding()
+  const banner = branding.loginBanner
   return (
     <div className="relative grid min-h-svh lg:grid-cols-2">
       {/* Theme toggle in corner */}
@@ -29,26 +31,28 @@ export default function AuthLayout({
         {/* Logo */}
         <div className="relative z-10 flex items-center gap-3">
           <div className="size-10 overflow-hidden rounded-lg bg-white/15 shadow-sm ring-1 ring-white/20">
-            <img src={LOGO_SRC} alt="TeamClaw" className="size-full object-cover" />
+            <img src={branding.logoSrc} alt={branding.productName} className="size-full object-cover" />
           </div>
           <span className="text-xl font-bold tracking-tight text-white">
-            TeamClaw
+            {branding.productName}
           </span>
         </div>
 
         {/* Tagline */}
         <div className="relative z-10 space-y-4">
           <h2 className="text-3xl leading-snug font-semibold tracking-tight text-white whitespace-pre-line">
-            {t('auth.tagline')}
+            {banner ? banner.title : t('auth.tagline')}
           </h2>
-          <p className="max-w-sm text-base leading-relaxed text-white/75">
-            {t('auth.taglineDesc')}
-          </p>
+          {(!banner || banner.subtitle) && (
+            <p className="max-w-sm text-base leading-relaxed text-white/75">
+              {banner ? banner.subtitle : t('auth.taglineDesc')}
+            </p>
+          )}
         </div>
 
         <div className="relative z-10">
           <p className="text-sm text-white/50">
-            TeamClaw &copy; {new Date().getFullYear()}
+            {branding.productName} &copy; {new Date().getFullYear()}
           </p>
         </div>
       </div>
@@ -1,15 +1,16 @@
 "use client"
 
-import { useState } from "react"
+import { useEffect, useState } from "react"
 import Link from "next/link"
 import { useRouter } from "next/navigation"
+import { useQuery } from "@tanstack/react-query"
 import { useForm } from "react-hook-form"
 import { z } from "zod/v4"
 import { zodResolver } from "@hookform/resolvers/zod"
 import { toast } from "sonner"
 import { motion } from "motion/react"
 import { Eye, EyeOff, Loader2 } from "lucide-react"
-import { LOGO_SRC } from "@/lib/logo"
+import { useBranding } from "@/hooks/use-branding"
 
 import { Button } from "@/components/ui/button"
 import { Input } from "@/components/ui/input"
@@ -23,13 +24,54 @@ import {
 } from "@/components/ui/card"
 import { useAuthStore } from "@/stores/auth-store"
 import { useT } from "@/stores/language-store"
-import { ApiError } from "@/lib/api-client"
+import { api, ApiError } from "@/lib/api-client"
+
+interface SsoConfig {
+  enabled: boolean
+  providerName: string | null
+}
 
 export default function LoginPage() {
   const router = useRouter()
   const login = useAuthStore((s) => s.login)
+  const verifyMfa = useAuthStore((s) => s.verifyMfa)
   const [showPassword, setShowPassword] = useState(false)
+  // Second step: set once the password was accepted for an MFA account
+  const [mfaToken, setMfaToken] = useState<string | null>(null)
+  const [mfaCode, setMfaCode] = useState("")
+  const [returnTo, setReturnTo] = useState("/chat")
+  const [verifying, setVerifying] = useState(false)
   const t = useT()
+  const branding = useBranding()
+  const { data: sso } = useQuery({
+    queryKey: ["auth", "oidc"],
+    queryFn: () => api.get<SsoConfig>("/api/v1/auth/oidc"),
+    staleTime: 5 * 60 * 1000,
+  })
+
+  // The SSO callback comes back here with ?sso_error= when sign-in failed,
+  // or with #mfa_token= when the account still needs its second factor
+  useEffect(() => {
+    const hash = new URLSearchParams(window.location.hash.slice(1))
+    const ssoMfaToken = hash.get("mfa_token")
+    if (ssoMfaToken) {
+      setMfaToken(ssoMfaToken)
+      const target = hash.get("return_to")
+      if (target?.startsWith("/") && !target.startsWith("//")) setReturnTo(target)
+      window.history.replaceState(null, "", "/login")
+      return
+    }
+    const ssoError = new URLSearchParams(window.location.search).get("sso_error")
+    if (!ssoError) return
+    toast.error(
+      ssoError === "no_account"
+        ? t('auth.ssoNoAccount')
+        : ssoError === "disabled"
+          ? t('auth.accountDisabled')
+          : t('auth.ssoFailed')
+    )
+    router.replace("/login")
+  }, []) // eslint-disable-line react-hooks/exhaustive-deps
 
   const loginSchema = z.object({
     email: z.email(t('auth.emailInvalid')),
@@ -47,19 +89,50 @@ export default function LoginPage() {
     defaultValues: { email: "", password: "" },
   })
 
+  function showError(error: unknown) {
+    if (error instanceof ApiError) {
+      const msg =
+        (error.data as { error?: string })?.error ?? t('auth.loginFailed')
+      toast.error(msg)
+    } else {
+      toast.error(t('auth.networkError'))
+    }
+  }
+
   async function onSubmit(data: LoginForm) {
     try {
-      await login(data.email, data.password)
+      const pending = await login(data.email, data.password)
+      if (pending) {
+        setMfaToken(pending.mfaToken)
+        return
+      }
       toast.success(t('auth.loginSuccess'))
       router.push("/chat")
     } catch (error) {
-      if (error instanceof ApiError) {
-        const msg =
-          (error.data as { error?: string })?.error ?? t('auth.loginFailed')
-        toast.error(msg)
-      } else {
-        toast.error(t('auth.networkError'))
+      showError(error)
+    }
+  }
+
+  async function onVerifyMfa(e: React.FormEvent) {
+    e.preventDefault()
+    if (!mfaToken) return
+    setVerifying(true)
+    try {
+      const res = await verifyMfa(mfaToken, mfaCode)
+      toast.success(t('auth.loginSuccess'))
+      if (res.recoveryCodesLeft !== undefined) {
+        toast.warning(t('mfa.recoveryCodesLeft', { n: res.recoveryCodesLeft }))
+      }
+      router.push(returnTo)
+    } catch (error) {
+      // An expired MFA token means starting over with the password
+      if (error instanceof ApiError && (error.data as { code?: string })?.code === "MFA_TOKEN_EXPIRED") {
+        setMfaToken(null)
+        setMfaCode("")
       }
+      showError(error)
+    } finally {
+      setVerifying(false)
     }
   }
 
@@ -72,9 +145,9 @@ export default function LoginPage() {
       {/* Mobile logo */}
       <div className="mb-8 flex items-center gap-2.5 lg:hidden">
         <div className="size-9 overflow-hidden rounded-lg">
-          <img src={LOGO_SRC} alt="TeamClaw" className="size-full object-cover" />
+          <img src={branding.logoSrc} alt={branding.productName} className="size-full object-cover" />
         </div>
-        <span className="text-lg font-bold tracking-tight">TeamClaw</span>
+        <span className="text-lg font-bold tracking-tight">{branding.productName}</span>
       </div>
 
       <Card className="border-0 shadow-none lg:border lg:shadow-sm">
@@ -85,87 +158,143 @@ export default function LoginPage() {
           <CardDescription>{t('auth.loginSubtitle')}</CardDescription>
         </CardHeader>
         <CardContent className="px-0 lg:px-6">
-          <form onSubmit={handleSubmit(onSubmit)} className="space-y-4">
-            {/* Email */}
-            <div className="space-y-2">
-              <Label htmlFor="email">{t('auth.email')}</Label>
-              <Input
-                id="email"
-                type="email"
-                placeholder="name@company.com"
-                autoComplete="email"
-                autoFocus
-                {...register("email")}
-                aria-invalid={!!errors.email}
-              />
-              {errors.email && (
-                <p className="text-destructive text-sm">
-                  {errors.email.message}
-                </p>
-              )}
-            </div>
-
-            {/* Password */}
-            <div className="space-y-2">
-              <Label htmlFor="password">{t('auth.password')}</Label>
-              <div className="relative">
+          {mfaToken ? (
+            <form onSubmit={onVerifyMfa} className="space-y-4">
+              <div className="space-y-2">
+                <Label htmlFor="mfa-code">{t('mfa.code')}</Label>
                 <Input
-                  id="password"
-                  type={showPassword ? "text" : "password"}
-                  placeholder={t('auth.passwordPlaceholder')}
-                  autoComplete="current-password"
-                  className="pr-10"
-                  {...register("password")}
-                  aria-invalid={!!errors.password}
+                  id="mfa-code"
+                  inputMode="numeric"
+                  autoComplete="one-time-code"
+                  autoFocus
+                  placeholder="123456"
+                  value={mfaCode}
+                  onChange={(e) => setMfaCode(e.target.value)}
                 />
-                <button
-                  type="button"
-                  className="text-muted-foreground hover:text-foreground absolute top-1/2 right-3 -translate-y-1/2 transition-colors"
-                  onClick={() => setShowPassword(!showPassword)}
-                  tabIndex={-1}
+                <p className="text-muted-foreground text-xs">{t('mfa.codeHint')}</p>
+              </div>
+              <Button
+                type="submit"
+                className="w-full"
+                size="lg"
+                disabled={verifying || !mfaCode.trim()}
+              >
+                {verifying && <Loader2 className="size-4 animate-spin" />}
+                {t('mfa.verify')}
+              </Button>
+              <Button
+                type="button"
+                variant="ghost"
+                className="w-full"
+                onClick={() => {
+                  setMfaToken(null)
+                  setMfaCode("")
+                }}
+              >
+                {t('mfa.backToLogin')}
+              </Button>
+            </form>
+          ) : (
+            <>
+              <form onSubmit={handleSubmit(onSubmit)} className="space-y-4">
+                {/* Email */}
+                <div className="space-y-2">
+                  <Label htmlFor="email">{t('auth.email')}</Label>
+                  <Input
+                    id="email"
+                    type="email"
+                    placeholder="name@company.com"
+                    autoComplete="email"
+                    autoFocus
+                    {...register("email")}
+                    aria-invalid={!!errors.email}
+                  />
+                  {errors.email && (
+                    <p className="text-destructive text-sm">
+                      {errors.email.message}
+                    </p>
+                  )}
+                </div>
+
+                {/* Password */}
+                <div className="space-y-2">
+                  <Label htmlFor="password">{t('auth.password')}</Label>
+                  <div className="relative">
+                    <Input
+                      id="password"
+                      type={showPassword ? "text" : "password"}
+                      placeholder={t('auth.passwordPlaceholder')}
+                      autoComplete="current-password"
+                      className="pr-10"
+                      {...register("password")}
+                      aria-invalid={!!errors.password}
+                    />
+                    <button
+                      type="button"
+                      className="text-muted-foreground hover:text-foreground absolute top-1/2 right-3 -translate-y-1/2 transition-colors"
+                      onClick={() => setShowPassword(!showPassword)}
+                      tabIndex={-1}
+                    >
+                      {showPassword ? (
+                        <EyeOff className="size-4" />
+                      ) : (
+                        <Eye className="size-4" />
+                      )}
+                    </button>
+                  </div>
+                  {errors.password && (
+                    <p className="text-destructive text-sm">
+                      {errors.password.message}
+                    </p>
+                  )}
+                </div>
+
+                {/* Submit */}
+                <Button
+                  type="submit"
+                  className="w-full"
+                  size="lg"
+                  disabled={isSubmitting}
                 >
-                  {showPassword ? (
-                    <EyeOff className="size-4" />
+                  {isSubmitting ? (
+                    <>
+                      <Loader2 className="size-4 animate-spin" />
+                      {t('auth.loggingIn')}
+                    </>
                   ) : (
-                    <Eye className="size-4" />
+                    t('auth.login')
                   )}
-                </button>
-              </div>
-              {errors.password && (
-                <p className="text-destructive text-sm">
-                  {errors.password.message}
-                </p>
-              )}
-            </div>
-
-            {/* Submit */}
-            <Button
-              type="submit"
-              className="w-full"
-              size="lg"
-              disabled={isSubmitting}
-            >
-              {isSubmitting ? (
+                </Button>
+              </form>
+
+              {/* Single sign-on */}
+              {sso?.enabled && (
                 <>
-                  <Loader2 className="size-4 animate-spin" />
-                  {t('auth.loggingIn')}
+                  <div className="text-muted-foreground my-4 flex items-center gap-3 text-xs">
+                    <div className="bg-border h-px flex-1" />
+                    {t('auth.or')}
+                    <div className="bg-border h-px flex-1" />
+                  </div>
+                  <Button variant="outline" className="w-full" size="lg" asChild>
+                    <a href="/api/v1/auth/oidc/login?returnTo=/chat">
+                      {t('auth.ssoLogin', { provider: sso.providerName ?? "SSO" })}
+                    </a>
+                  </Button>
                 </>
-              ) : (
-                t('auth.login')
               )}
-            </Button>
-          </form>
-
-          {/* Register link */}
-          <p className="text-muted-foreground mt-6 text-center text-sm">
-            {t('auth.noAccount')}{" "}
-            <Link
-              href="/register"
-              className="text-primary hover:text-primary/80 font-medium transition-colors"
-            >
-              {t('auth.registerNow')}
-            </Link>
-          </p>
+
+              {/* Register link */}
+              <p className="text-muted-foreground mt-6 text-center text-sm">
+                {t('auth.noAccount')}{" "}
+                <Link
+                  href="/register"
+                  className="text-primary hover:text-primary/80 font-medium transition-colors"
+                >
+                  {t('auth.registerNow')}
+                </Link>
+              </p>
+            </>
+          )}
         </CardContent>
       </Card>
     </motion.div>
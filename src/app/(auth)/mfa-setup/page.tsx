@@ -0,0 +1,210 @@
+"use client"
+
+import { useEffect, useState } from "react"
+import Link from "next/link"
+import { useRouter } from "next/navigation"
+import { useQuery, useQueryClient } from "@tanstack/react-query"
+import { toast } from "sonner"
+import { motion } from "motion/react"
+import { Loader2, ShieldCheck } from "lucide-react"
+
+import { Button } from "@/components/ui/button"
+import { Input } from "@/components/ui/input"
+import { Label } from "@/components/ui/label"
+import {
+  Card,
+  CardContent,
+  CardDescription,
+  CardHeader,
+  CardTitle,
+} from "@/components/ui/card"
+import { useAuthStore } from "@/stores/auth-store"
+import { useT } from "@/stores/language-store"
+import { api, ApiError } from "@/lib/api-client"
+
+interface MfaStatus {
+  enabled: boolean
+  required: boolean
+  recoveryCodesLeft: number
+}
+
+interface MfaEnrolment {
+  secret: string
+  otpauthUri: string
+}
+
+export default function MfaSetupPage() {
+  const router = useRouter()
+  const t = useT()
+  const queryClient = useQueryClient()
+  const user = useAuthStore((s) => s.user)
+  const isLoading = useAuthStore((s) => s.isLoading)
+  const fetchUser = useAuthStore((s) => s.fetchUser)
+
+  const [enrolment, setEnrolment] = useState<MfaEnrolment | null>(null)
+  const [code, setCode] = useState("")
+  const [busy, setBusy] = useState(false)
+  // Shown once, right after enrolment or regeneration
+  const [recoveryCodes, setRecoveryCodes] = useState<string[] | null>(null)
+
+  useEffect(() => {
+    fetchUser()
+  }, [fetchUser])
+
+  useEffect(() => {
+    if (!isLoading && !user) router.push("/login")
+  }, [isLoading, user, router])
+
+  const { data: status } = useQuery({
+    queryKey: ["auth", "mfa"],
+    queryFn: () => api.get<MfaStatus>("/api/v1/auth/mfa"),
+    enabled: !!user,
+  })
+
+  async function run(action: () => Promise<void>) {
+    setBusy(true)
+    try {
+      await action()
+    } catch (error) {
+      const msg =
+        error instanceof ApiError
+          ? (error.data as { error?: string })?.error ?? t('mfa.failed')
+          : t('auth.networkError')
+      toast.error(msg)
+    } finally {
+      setBusy(false)
+    }
+  }
+
+  const startSetup = () =>
+    run(async () => {
+      setEnrolment(await api.post<MfaEnrolment>("/api/v1/auth/mfa/setup"))
+      setCode("")
+    })
+
+  const confirmSetup = () =>
+    run(async () => {
+      const res = await api.put<{ recoveryCodes: string[] }>("/api/v1/auth/mfa/setup", { code })
+      setRecoveryCodes(res.recoveryCodes)
+      setEnrolment(null)
+      setCode("")
+      toast.success(t('mfa.enabled'))
+      await queryClient.invalidateQueries({ queryKey: ["auth", "mfa"] })
+      await fetchUser()
+    })
+
+  const regenerateCodes = () =>
+    run(async () => {
+      const res = await api.post<{ recoveryCodes: string[] }>("/api/v1/auth/mfa/recovery-codes", { code })
+      setRecoveryCodes(res.recoveryCodes)
+      setCode("")
+      await queryClient.invalidateQueries({ queryKey: ["auth", "mfa"] })
+    })
+
+  const disable = () =>
+    run(async () => {
+      await api.delete("/api/v1/auth/mfa", { body: { code } })
+      setCode("")
+      toast.success(t('mfa.disabled'))
+      await queryClient.invalidateQueries({ queryKey: ["auth", "mfa"] })
+    })
+
+  const codeInput = (
+    <div className="space-y-2">
+      <Label htmlFor="mfa-code">{t('mfa.code')}</Label>
+      <Input
+        id="mfa-code"
+        inputMode="numeric"
+        autoComplete="one-time-code"
+        placeholder="123456"
+        value={code}
+        onChange={(e) => setCode(e.target.value)}
+      />
+    </div>
+  )
+
+  return (
+    <motion.div
+      initial={{ opacity: 0, y: 12 }}
+      animate={{ opacity: 1, y: 0 }}
+      transition={{ duration: 0.4, ease: "easeOut" }}
+    >
+      <Card className="border-0 shadow-none lg:border lg:shadow-sm">
+        <CardHeader className="space-y-1 px-0 lg:px-6">
+          <CardTitle className="flex items-center gap-2 text-2xl font-semibold tracking-tight">
+            <ShieldCheck className="size-6" />
+            {t('mfa.title')}
+          </CardTitle>
+          <CardDescription>
+            {user?.mfaSetupRequired ? t('mfa.requiredDesc') : t('mfa.desc')}
+          </CardDescription>
+        </CardHeader>
+        <CardContent className="space-y-4 px-0 lg:px-6">
+          {!status ? (
+            <Loader2 className="text-muted-foreground size-5 animate-spin" />
+          ) : recoveryCodes ? (
+            <>
+              <p className="text-sm">{t('mfa.recoveryCodesDesc')}</p>
+              <pre className="bg-muted grid grid-cols-2 gap-1 rounded p-3 font-mono text-sm">
+                {recoveryCodes.map((c) => (
+                  <span key={c}>{c}</span>
+                ))}
+              </pre>
+              <Button className="w-full" onClick={() => setRecoveryCodes(null)}>
+                {t('mfa.savedCodes')}
+              </Button>
+            </>
+          ) : status.enabled ? (
+            <>
+              <p className="text-sm">
+                {t('mfa.statusOn', { n: status.recoveryCodesLeft })}
+              </p>
+              {codeInput}
+              <div className="flex gap-2">
+                <Button variant="outline" className="flex-1" disabled={busy || !code.trim()} onClick={regenerateCodes}>
+                  {t('mfa.regenerateCodes')}
+                </Button>
+                {!status.required && (
+                  <Button variant="destructive" className="flex-1" disabled={busy || !code.trim()} onClick={disable}>
+                    {t('mfa.disable')}
+                  </Button>
+                )}
+              </div>
+            </>
+          ) : enrolment ? (
+            <>
+              <p className="text-sm">{t('mfa.scanDesc')}</p>
+              <div className="space-y-1">
+                <p className="text-muted-foreground text-xs">{t('mfa.secret')}</p>
+                <code className="bg-muted block rounded p-2 font-mono text-sm break-all">
+                  {enrolment.secret}
+                </code>
+              </div>
+              <Button variant="outline" className="w-full" asChild>
+                <a href={enrolment.otpauthUri}>{t('mfa.openInApp')}</a>
+              </Button>
+              {codeInput}
+              <Button className="w-full" disabled={busy || !code.trim()} onClick={confirmSetup}>
+                {busy && <Loader2 className="size-4 animate-spin" />}
+                {t('mfa.confirm')}
+              </Button>
+            </>
+          ) : (
+            <Button className="w-full" disabled={busy} onClick={startSetup}>
+              {busy && <Loader2 className="size-4 animate-spin" />}
+              {t('mfa.start')}
+            </Button>
+          )}
+
+          {!user?.mfaSetupRequired && !recoveryCodes && (
+            <p className="text-center text-sm">
+              <Link href="/chat" className="text-primary hover:text-primary/80 font-medium">
+                {t('mfa.backToApp')}
+              </Link>
+            </p>
+          )}
+        </CardContent>
+      </Card>
+    </motion.div>
+  )
+}
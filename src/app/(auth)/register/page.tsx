@@ -9,7 +9,7 @@ import { zodResolver } from "@hookform/resolvers/zod"
 import { toast } from "sonner"
 import { motion } from "motion/react"
 import { Eye, EyeOff, Loader2, Check, X } from "lucide-react"
-import { LOGO_SRC } from "@/lib/logo"
+import { useBranding } from "@/hooks/use-branding"
 
 import { Button } from "@/components/ui/button"
 import { Input } from "@/components/ui/input"
@@ -86,6 +86,7 @@ export default function RegisterPage() {
   const [showPassword, setShowPassword] = useState(false)
   const [showConfirmPassword, setShowConfirmPassword] = useState(false)
   const t = useT()
+  const branding = useBranding()
 
   const registerSchema = z
     .object({
@@ -143,9 +144,9 @@ export default function RegisterPage() {
       {/* Mobile logo */}
       <div className="mb-8 flex items-center gap-2.5 lg:hidden">
         <div className="size-9 overflow-hidden rounded-lg">
-          <img src={LOGO_SRC} alt="TeamClaw" className="size-full object-cover" />
+          <img src={branding.logoSrc} alt={branding.productName} className="size-full object-cover" />
         </div>
-        <span className="text-lg font-bold tracking-tight">TeamClaw</span>
+        <span className="text-lg font-bold tracking-tight">{branding.productName}</span>
       </div>
 
       <Card className="border-0 shadow-none lg:border lg:shadow-sm">
@@ -15,14 +15,14 @@ import { useAgents } from "@/hooks/use-agents"
 import { useInstances } from "@/hooks/use-instances"
 import { useAuthStore } from "@/stores/auth-store"
 import { useT } from "@/stores/language-store"
-import { hasPermission } from "@/lib/auth/permissions"
+import { userHasPermission } from "@/lib/auth/permissions"
 import type { AgentOverview, AgentCategory } from "@/types/agent"
 
 export default function AgentsPage() {
   const t = useT()
   const user = useAuthStore((s) => s.user)
-  const canManage = user ? hasPermission(user.role, "agents:manage") : false
-  const canCreate = user ? hasPermission(user.role, "agents:create") : false
+  const canManage = user ? userHasPermission(user, "agents:manage") : false
+  const canCreate = user ? userHasPermission(user, "agents:create") : false
 
   const [instanceFilter, setInstanceFilter] = useState("all")
   const [categoryFilter, setCategoryFilter] = useState("all")
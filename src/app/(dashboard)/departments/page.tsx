@@ -13,12 +13,12 @@ import { DeptDeleteDialog } from "@/components/departments/dept-delete-dialog"
 import { DeptDetailSheet } from "@/components/departments/dept-detail-sheet"
 import { useDepartments } from "@/hooks/use-departments"
 import { useAuthStore } from "@/stores/auth-store"
-import { hasPermission } from "@/lib/auth/permissions"
+import { userHasPermission } from "@/lib/auth/permissions"
 import type { DepartmentResponse } from "@/types/department"
 
 export default function DepartmentsPage() {
   const user = useAuthStore((s) => s.user)
-  const canManage = user ? hasPermission(user.role, "departments:manage") : false
+  const canManage = user ? userHasPermission(user, "departments:manage") : false
 
   const { data, isLoading } = useDepartments()
 
@@ -12,6 +12,7 @@ import { InstanceCreateDialog } from "@/components/instances/instance-create-dia
 import { InstanceEditDialog } from "@/components/instances/instance-edit-dialog"
 import { InstanceDeleteDialog } from "@/components/instances/instance-delete-dialog"
 import { InstanceDetailSheet } from "@/components/instances/instance-detail-sheet"
+import { InstanceAnnounceDialog } from "@/components/instances/instance-announce-dialog"
 import {
   useInstances,
   useStartInstance,
@@ -20,15 +21,15 @@ import {
 } from "@/hooks/use-instances"
 import { useAuthStore } from "@/stores/auth-store"
 import { useT } from "@/stores/language-store"
-import { hasPermission } from "@/lib/auth/permissions"
+import { userHasPermission } from "@/lib/auth/permissions"
 import type { InstanceResponse } from "@/types/instance"
 
 export default function InstancesPage() {
   const t = useT()
   const user = useAuthStore((s) => s.user)
-  const canManage = user ? hasPermission(user.role, "instances:manage") : false
+  const canManage = user ? userHasPermission(user, "instances:manage") : false
 
-  const { data, isLoading } = useInstances()
+  const { data, isLoading } = useInstances({ live: true })
   const startInstance = useStartInstance()
   const stopInstance = useStopInstance()
   const restartInstance = useRestartInstance()
@@ -37,6 +38,7 @@ export default function InstancesPage() {
   const [editInstance, setEditInstance] = useState<InstanceResponse | null>(null)
   const [deleteInstance, setDeleteInstance] = useState<InstanceResponse | null>(null)
   const [detailInstance, setDetailInstance] = useState<InstanceResponse | null>(null)
+  const [announceInstance, setAnnounceInstance] = useState<InstanceResponse | null>(null)
 
   function handleStart(id: string) {
     startInstance.mutate(id, {
@@ -99,6 +101,7 @@ export default function InstancesPage() {
               onStart={handleStart}
               onStop={handleStop}
               onRestart={handleRestart}
+              onAnnounce={setAnnounceInstance}
             />
           )}
         </CardContent>
@@ -119,6 +122,11 @@ export default function InstancesPage() {
         onOpenChange={(open) => !open && setDeleteInstance(null)}
         instance={deleteInstance}
       />
+      <InstanceAnnounceDialog
+        open={!!announceInstance}
+        onOpenChange={(open) => !open && setAnnounceInstance(null)}
+        instance={announceInstance}
+      />
       <InstanceDetailSheet
         open={!!detailInstance}
         onOpenChange={(open) => !open && setDetailInstance(null)}
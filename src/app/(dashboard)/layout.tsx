@@ -26,6 +26,8 @@ export default function DashboardLayout({
   useEffect(() => {
     if (!isLoading && !user) {
       router.push("/login")
+    } else if (user?.mfaSetupRequired) {
+      router.push("/mfa-setup")
     }
   }, [isLoading, user, router])
 
@@ -40,7 +42,7 @@ export default function DashboardLayout({
     )
   }
 
-  if (!user) {
+  if (!user || user.mfaSetupRequired) {
     return null
   }
 
@@ -5,6 +5,7 @@ import { useAuthStore } from "@/stores/auth-store"
 import { useAuditLogs, type AuditLogParams } from "@/hooks/use-audit-logs"
 import { AuditLogFilters } from "@/components/audit/audit-log-filters"
 import { AuditLogTable } from "@/components/audit/audit-log-table"
+import { userHasPermission } from "@/lib/auth/permissions"
 
 export default function LogsPage() {
   const user = useAuthStore((s) => s.user)
@@ -31,7 +32,7 @@ export default function LogsPage() {
       <AuditLogFilters
         filters={filters}
         onChange={setFilters}
-        showExport={user?.role === "SYSTEM_ADMIN"}
+        showExport={user ? userHasPermission(user, "audit:view_all") : false}
         onExport={handleExport}
       />
       <AuditLogTable
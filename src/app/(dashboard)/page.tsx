@@ -3,12 +3,14 @@
 import { useEffect } from "react"
 import { useRouter } from "next/navigation"
 import { useAuthStore } from "@/stores/auth-store"
-import { useDashboardStats } from "@/hooks/use-dashboard"
+import { useDashboardStats, useLicenseStatus, useVersionAdvisory } from "@/hooks/use-dashboard"
 import { DashboardStatsRow } from "@/components/dashboard/dashboard-stats-row"
 import { DashboardInstanceHealth } from "@/components/dashboard/dashboard-instance-health"
 import { DashboardProviderChart } from "@/components/dashboard/dashboard-provider-chart"
 import { DashboardRecentActivity } from "@/components/dashboard/dashboard-recent-activity"
 import { DashboardSkeleton } from "@/components/dashboard/dashboard-skeleton"
+import { DashboardLicenseCard } from "@/components/dashboard/dashboard-license-card"
+import { DashboardVersionAdvisory } from "@/components/dashboard/dashboard-version-advisory"
 
 export default function DashboardPage() {
   const router = useRouter()
@@ -25,6 +27,10 @@ export default function DashboardPage() {
   // Wait for auth before firing API call — avoids concurrent refresh token race
   const canFetch = !isLoading && !!user && user.role !== "USER"
   const { data, isLoading: statsLoading } = useDashboardStats(canFetch)
+  const { data: license } = useLicenseStatus(canFetch && user?.role === "SYSTEM_ADMIN")
+  const { data: advisory } = useVersionAdvisory(
+    canFetch && (user?.role === "SYSTEM_ADMIN" || user?.role === "VIEWER"),
+  )
 
   // While auth is loading or user is USER (about to redirect)
   if (isLoading || !user || user.role === "USER") {
@@ -44,12 +50,16 @@ export default function DashboardPage() {
       {/* Metrics row */}
       <DashboardStatsRow stats={data.stats} />
 
+      {license && <DashboardLicenseCard license={license} />}
+
       {/* 2-col grid: Instance Health | Provider Chart */}
       <div className="grid gap-3.5 lg:grid-cols-2">
         <DashboardInstanceHealth instances={data.instanceHealth} />
         <DashboardProviderChart data={data.providerDistribution} />
       </div>
 
+      {advisory && <DashboardVersionAdvisory advisory={advisory} />}
+
       {/* Full-width: Recent Activity */}
       <DashboardRecentActivity activities={data.recentActivity} />
     </div>
@@ -13,12 +13,12 @@ import { UserDeleteDialog } from "@/components/users/user-delete-dialog"
 import { UserResetPasswordDialog } from "@/components/users/user-reset-password-dialog"
 import { useUsers } from "@/hooks/use-users"
 import { useAuthStore } from "@/stores/auth-store"
-import { hasPermission } from "@/lib/auth/permissions"
+import { userHasPermission } from "@/lib/auth/permissions"
 import type { UserResponse } from "@/types/user"
 
 export default function UsersPage() {
   const authUser = useAuthStore((s) => s.user)
-  const canManage = authUser ? hasPermission(authUser.role, "users:create") : false
+  const canManage = authUser ? userHasPermission(authUser, "users:create") : false
 
   const [search, setSearch] = useState("")
   const { data, isLoading } = useUsers({ search: search || undefined })
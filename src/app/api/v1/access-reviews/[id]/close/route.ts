@@ -0,0 +1,24 @@
+import { NextResponse } from 'next/server'
+import { prisma } from '@/lib/db'
+import { withAuth, withPermission, param } from '@/lib/middleware/auth'
+import type { AuthContext } from '@/lib/middleware/auth'
+import { closeAccessReviewCampaign } from '@/lib/access-reviews'
+
+// POST /api/v1/access-reviews/[id]/close — Close early, applying auto-revoke
+export const POST = withAuth(
+  withPermission('access_reviews:manage', async (req, ctx) => {
+    const user = ctx.user!
+    const id = param(ctx as unknown as AuthContext, 'id')
+
+    const campaign = await prisma.accessReviewCampaign.findUnique({ where: { id } })
+    if (!campaign) {
+      return NextResponse.json({ error: 'Access review not found' }, { status: 404 })
+    }
+    if (campaign.status !== 'OPEN') {
+      return NextResponse.json({ error: 'Access review is already closed' }, { status: 409 })
+    }
+
+    const result = await closeAccessReviewCampaign(campaign, user.id)
+    return NextResponse.json(result)
+  }),
+)
@@ -0,0 +1,78 @@
+import { NextResponse } from 'next/server'
+import { prisma } from '@/lib/db'
+import { withAuth, withPermission, withValidation, param } from '@/lib/middleware/auth'
+import type { AuthContext } from '@/lib/middleware/auth'
+import { decideAccessReviewItemSchema } from '@/lib/validations/access-review'
+import { decideAccessReviewItem, toItemResponse } from '@/lib/access-reviews'
+import { auditLog } from '@/lib/audit'
+
+// PUT /api/v1/access-reviews/[id]/items/[itemId] — Keep or revoke one grant / role
+export const PUT = withAuth(
+  withPermission(
+    'access_reviews:review',
+    withValidation(decideAccessReviewItemSchema, async (req, ctx) => {
+      const { user, body } = ctx as {
+        user: NonNullable<typeof ctx.user>
+        body: typeof ctx.body
+      }
+      const id = param(ctx as unknown as AuthContext, 'id')
+      const itemId = param(ctx as unknown as AuthContext, 'itemId')
+      const ipAddress = req.headers.get('x-forwarded-for') || 'unknown'
+      const userAgent = req.headers.get('user-agent') || undefined
+
+      const item = await prisma.accessReviewItem.findFirst({
+        where: { id: itemId, campaignId: id },
+        include: { campaign: true },
+      })
+      if (!item) {
+        return NextResponse.json({ error: 'Review item not found' }, { status: 404 })
+      }
+
+      // DEPT_ADMIN: own department's grants only; role items need SYSTEM_ADMIN
+      if (user.role === 'DEPT_ADMIN' && (!item.departmentId || item.departmentId !== user.departmentId)) {
+        return NextResponse.json({ error: 'Forbidden' }, { status: 403 })
+      }
+
+      try {
+        await decideAccessReviewItem(item, body.decision, user.id, body.comment)
+      } catch (err) {
+        const message = (err as Error).message
+        auditLog({
+          userId: user.id,
+          action: 'ACCESS_REVIEW_DECIDE',
+          resource: 'access_review',
+          resourceId: id,
+          details: { itemId, kind: item.kind, subjectId: item.subjectId, decision: body.decision, error: message },
+          ipAddress,
+          userAgent,
+          result: 'FAILURE',
+        })
+        const status = message === 'You cannot review your own role' ? 403 : 409
+        return NextResponse.json({ error: message }, { status })
+      }
+
+      auditLog({
+        userId: user.id,
+        action: 'ACCESS_REVIEW_DECIDE',
+        resource: 'access_review',
+        resourceId: id,
+        details: {
+          itemId,
+          kind: item.kind,
+          subjectId: item.subjectId,
+          decision: body.decision,
+          summary: item.summary as Record<string, unknown>,
+        },
+        ipAddress,
+        userAgent,
+        result: 'SUCCESS',
+      })
+
+      const updated = await prisma.accessReviewItem.findUniqueOrThrow({
+        where: { id: itemId },
+        include: { reviewer: { select: { name: true } } },
+      })
+      return NextResponse.json({ item: toItemResponse(updated) })
+    }),
+  ),
+)
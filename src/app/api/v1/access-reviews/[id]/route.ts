@@ -0,0 +1,46 @@
+import { NextResponse } from 'next/server'
+import { prisma } from '@/lib/db'
+import { withAuth, withPermission, param } from '@/lib/middleware/auth'
+import type { AuthContext } from '@/lib/middleware/auth'
+import { closeOverdueAccessReviews, getAccessReviewProgress, toCampaignResponse, toItemResponse } from '@/lib/access-reviews'
+import type { AccessReviewDecision, Prisma } from '@/generated/prisma'
+
+const DECISIONS: AccessReviewDecision[] = ['PENDING', 'KEEP', 'REVOKE', 'AUTO_REVOKED']
+
+// GET /api/v1/access-reviews/[id] — Campaign with the items the caller can review
+export const GET = withAuth(
+  withPermission('access_reviews:review', async (req, ctx) => {
+    const user = ctx.user!
+    const id = param(ctx as unknown as AuthContext, 'id')
+    await closeOverdueAccessReviews()
+
+    const campaign = await prisma.accessReviewCampaign.findUnique({
+      where: { id },
+      include: { createdBy: { select: { name: true } } },
+    })
+    if (!campaign) {
+      return NextResponse.json({ error: 'Access review not found' }, { status: 404 })
+    }
+
+    const decision = new URL(req.url).searchParams.get('decision') as AccessReviewDecision | null
+    const where: Prisma.AccessReviewItemWhereInput = { campaignId: id }
+    // DEPT_ADMIN: own department's grants only; role items are for SYSTEM_ADMIN
+    const scope = user.role === 'DEPT_ADMIN' ? (user.departmentId ?? '') : undefined
+    if (scope !== undefined) where.departmentId = scope
+    if (decision && DECISIONS.includes(decision)) where.decision = decision
+
+    const [items, progress] = await Promise.all([
+      prisma.accessReviewItem.findMany({
+        where,
+        include: { reviewer: { select: { name: true } } },
+        orderBy: [{ kind: 'asc' }, { id: 'asc' }],
+      }),
+      getAccessReviewProgress([id], scope),
+    ])
+
+    return NextResponse.json({
+      campaign: toCampaignResponse(campaign, progress.get(id)!),
+      items: items.map(toItemResponse),
+    })
+  }),
+)
@@ -0,0 +1,80 @@
+import { NextResponse } from 'next/server'
+import { prisma } from '@/lib/db'
+import { withAuth, withPermission, withValidation } from '@/lib/middleware/auth'
+import { createAccessReviewSchema } from '@/lib/validations/access-review'
+import {
+  closeOverdueAccessReviews,
+  createAccessReviewCampaign,
+  getAccessReviewProgress,
+  reviewDays,
+  toCampaignResponse,
+} from '@/lib/access-reviews'
+import { auditLog } from '@/lib/audit'
+
+// GET /api/v1/access-reviews — List campaigns with review progress
+export const GET = withAuth(
+  withPermission('access_reviews:review', async (req, ctx) => {
+    const user = ctx.user!
+    await closeOverdueAccessReviews()
+
+    const campaigns = await prisma.accessReviewCampaign.findMany({
+      include: { createdBy: { select: { name: true } } },
+      orderBy: { createdAt: 'desc' },
+      take: 100,
+    })
+
+    // DEPT_ADMIN: progress counts only their department's items
+    const scope = user.role === 'DEPT_ADMIN' ? (user.departmentId ?? '') : undefined
+    const progress = await getAccessReviewProgress(
+      campaigns.map((c) => c.id),
+      scope,
+    )
+
+    return NextResponse.json({
+      campaigns: campaigns.map((c) => toCampaignResponse(c, progress.get(c.id)!)),
+    })
+  }),
+)
+
+// POST /api/v1/access-reviews — Open a campaign over current grants and roles
+export const POST = withAuth(
+  withPermission(
+    'access_reviews:manage',
+    withValidation(createAccessReviewSchema, async (req, ctx) => {
+      const { user, body } = ctx as {
+        user: NonNullable<typeof ctx.user>
+        body: typeof ctx.body
+      }
+
+      const dueAt = body.dueAt ? new Date(body.dueAt) : new Date(Date.now() + reviewDays() * 86400_000)
+      if (dueAt.getTime() <= Date.now()) {
+        return NextResponse.json({ error: 'Due date must be in the future' }, { status: 400 })
+      }
+
+      const campaign = await createAccessReviewCampaign({
+        name: body.name,
+        dueAt,
+        autoRevoke: body.autoRevoke,
+        includeRoles: body.includeRoles,
+        createdById: user.id,
+      })
+
+      auditLog({
+        userId: user.id,
+        action: 'ACCESS_REVIEW_CREATE',
+        resource: 'access_review',
+        resourceId: campaign.id,
+        details: { name: campaign.name, dueAt: dueAt.toISOString(), items: campaign.itemCount },
+        ipAddress: req.headers.get('x-forwarded-for') || 'unknown',
+        userAgent: req.headers.get('user-agent') || undefined,
+        result: 'SUCCESS',
+      })
+
+      const progress = await getAccessReviewProgress([campaign.id])
+      return NextResponse.json(
+        { campaign: toCampaignResponse({ ...campaign, createdBy: { name: user.name } }, progress.get(campaign.id)!) },
+        { status: 201 },
+      )
+    }),
+  ),
+)
@@ -0,0 +1,27 @@
+import { NextResponse } from 'next/server'
+import { prisma } from '@/lib/db'
+import { withAuth, withPermission, param } from '@/lib/middleware/auth'
+import { endBreakGlass } from '@/lib/auth/break-glass'
+
+// DELETE /api/v1/admin/break-glass/[id] — Revoke someone's break-glass session
+export const DELETE = withAuth(
+  withPermission('break_glass:manage', async (req, ctx) => {
+    const { user } = ctx
+    const id = param(ctx, 'id')
+
+    const session = await prisma.breakGlassSession.findUnique({ where: { id } })
+    if (!session) {
+      return NextResponse.json({ error: 'Break-glass session not found' }, { status: 404 })
+    }
+
+    const ended = await endBreakGlass(session, user.id, {
+      ipAddress: req.headers.get('x-forwarded-for') || 'unknown',
+      userAgent: req.headers.get('user-agent') || undefined,
+    })
+    if (!ended) {
+      return NextResponse.json({ error: 'Break-glass session has already ended' }, { status: 409 })
+    }
+
+    return new NextResponse(null, { status: 204 })
+  }),
+)
@@ -0,0 +1,32 @@
+import { NextResponse } from 'next/server'
+import { prisma } from '@/lib/db'
+import { withAuth, withPermission } from '@/lib/middleware/auth'
+
+// GET /api/v1/admin/break-glass — Recent break-glass sessions, active first
+export const GET = withAuth(
+  withPermission('break_glass:manage', async () => {
+    const now = new Date()
+    const sessions = await prisma.breakGlassSession.findMany({
+      include: { user: { select: { name: true, email: true } } },
+      orderBy: { createdAt: 'desc' },
+      take: 100,
+    })
+
+    const result = sessions.map((s) => ({
+      id: s.id,
+      userId: s.userId,
+      userName: s.user.name,
+      userEmail: s.user.email,
+      justification: s.justification,
+      ipAddress: s.ipAddress,
+      active: !s.endedAt && s.expiresAt > now,
+      expiresAt: s.expiresAt.toISOString(),
+      endedAt: s.endedAt?.toISOString() ?? null,
+      endedById: s.endedById,
+      createdAt: s.createdAt.toISOString(),
+    }))
+    result.sort((a, b) => Number(b.active) - Number(a.active))
+
+    return NextResponse.json({ sessions: result })
+  }),
+)
@@ -0,0 +1,45 @@
+import { NextResponse } from 'next/server'
+import { withAuth, withPermission } from '@/lib/middleware/auth'
+import { invalidateAllCaches, invalidateCache, listCaches } from '@/lib/caches'
+import { auditLog } from '@/lib/audit'
+
+// GET /api/v1/admin/caches — In-process caches with their keys and hit rates
+export const GET = withAuth(
+  withPermission('monitor:caches', async () => {
+    return NextResponse.json({ caches: await listCaches() })
+  }),
+)
+
+// DELETE /api/v1/admin/caches?name=&key= — Clear one key, one cache, or
+// (without name) every cache
+export const DELETE = withAuth(
+  withPermission('monitor:caches', async (req, ctx) => {
+    const user = ctx.user!
+    const url = new URL(req.url)
+    const name = url.searchParams.get('name')
+    const key = url.searchParams.get('key') || undefined
+
+    let cleared: string[]
+    if (name) {
+      if (!(await invalidateCache(name, key))) {
+        return NextResponse.json({ error: 'Cache not found' }, { status: 404 })
+      }
+      cleared = [name]
+    } else {
+      cleared = await invalidateAllCaches()
+    }
+
+    auditLog({
+      userId: user.id,
+      action: 'CACHE_INVALIDATE',
+      resource: 'cache',
+      resourceId: name ?? undefined,
+      details: { caches: cleared, ...(key ? { key } : {}) },
+      ipAddress: req.headers.get('x-forwarded-for') || 'unknown',
+      userAgent: req.headers.get('user-agent') || undefined,
+      result: 'SUCCESS',
+    })
+
+    return NextResponse.json({ cleared, key: key ?? null })
+  }),
+)
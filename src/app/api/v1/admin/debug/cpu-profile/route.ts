@@ -0,0 +1,40 @@
+import { NextResponse } from 'next/server'
+import { withAuth, withPermission } from '@/lib/middleware/auth'
+import { captureCpuProfile, isProfilingEnabled, MAX_CPU_PROFILE_SECONDS } from '@/lib/debug/profiling'
+import { auditLog } from '@/lib/audit'
+
+// POST /api/v1/admin/debug/cpu-profile?seconds=10 — Sample the CPU and
+// download a .cpuprofile
+export const POST = withAuth(
+  withPermission('monitor:profiling', async (req, ctx) => {
+    if (!isProfilingEnabled()) {
+      return NextResponse.json({ error: 'Profiling is disabled' }, { status: 404 })
+    }
+
+    const url = new URL(req.url)
+    const requested = parseInt(url.searchParams.get('seconds') || '10') || 10
+    const seconds = Math.min(MAX_CPU_PROFILE_SECONDS, Math.max(1, requested))
+
+    const profile = await captureCpuProfile(seconds)
+    if (profile === null) {
+      return NextResponse.json({ error: 'A profile is already being captured' }, { status: 409 })
+    }
+
+    auditLog({
+      userId: ctx.user!.id,
+      action: 'PROFILE_CPU',
+      resource: 'system',
+      details: { seconds },
+      ipAddress: req.headers.get('x-forwarded-for') || 'unknown',
+      userAgent: req.headers.get('user-agent') || undefined,
+      result: 'SUCCESS',
+    })
+
+    return new NextResponse(profile, {
+      headers: {
+        'Content-Type': 'application/json',
+        'Content-Disposition': `attachment; filename="teamclaw-${process.pid}-${Date.now()}.cpuprofile"`,
+      },
+    })
+  }),
+)
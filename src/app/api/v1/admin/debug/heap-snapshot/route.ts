@@ -0,0 +1,36 @@
+import { NextResponse } from 'next/server'
+import { withAuth, withPermission } from '@/lib/middleware/auth'
+import { captureHeapSnapshot, isProfilingEnabled } from '@/lib/debug/profiling'
+import { auditLog } from '@/lib/audit'
+
+// POST /api/v1/admin/debug/heap-snapshot — Download a .heapsnapshot. Pauses
+// the process while V8 walks the heap.
+export const POST = withAuth(
+  withPermission('monitor:profiling', async (req, ctx) => {
+    if (!isProfilingEnabled()) {
+      return NextResponse.json({ error: 'Profiling is disabled' }, { status: 404 })
+    }
+
+    const stream = await captureHeapSnapshot()
+    if (!stream) {
+      return NextResponse.json({ error: 'A profile is already being captured' }, { status: 409 })
+    }
+
+    auditLog({
+      userId: ctx.user!.id,
+      action: 'PROFILE_HEAP',
+      resource: 'system',
+      details: {},
+      ipAddress: req.headers.get('x-forwarded-for') || 'unknown',
+      userAgent: req.headers.get('user-agent') || undefined,
+      result: 'SUCCESS',
+    })
+
+    return new NextResponse(stream, {
+      headers: {
+        'Content-Type': 'application/json',
+        'Content-Disposition': `attachment; filename="teamclaw-${process.pid}-${Date.now()}.heapsnapshot"`,
+      },
+    })
+  }),
+)
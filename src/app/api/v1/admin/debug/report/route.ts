@@ -0,0 +1,26 @@
+import { NextResponse } from 'next/server'
+import { withAuth, withPermission } from '@/lib/middleware/auth'
+import { getDiagnosticReport, isProfilingEnabled } from '@/lib/debug/profiling'
+import { auditLog } from '@/lib/audit'
+
+// POST /api/v1/admin/debug/report — Diagnostic report: JS and native stacks,
+// open handles and resource usage
+export const POST = withAuth(
+  withPermission('monitor:profiling', async (req, ctx) => {
+    if (!isProfilingEnabled()) {
+      return NextResponse.json({ error: 'Profiling is disabled' }, { status: 404 })
+    }
+
+    auditLog({
+      userId: ctx.user!.id,
+      action: 'PROFILE_REPORT',
+      resource: 'system',
+      details: {},
+      ipAddress: req.headers.get('x-forwarded-for') || 'unknown',
+      userAgent: req.headers.get('user-agent') || undefined,
+      result: 'SUCCESS',
+    })
+
+    return NextResponse.json(getDiagnosticReport())
+  }),
+)
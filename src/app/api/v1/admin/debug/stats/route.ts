@@ -0,0 +1,13 @@
+import { NextResponse } from 'next/server'
+import { withAuth, withPermission } from '@/lib/middleware/auth'
+import { getRuntimeStats, isProfilingEnabled } from '@/lib/debug/profiling'
+
+// GET /api/v1/admin/debug/stats — Memory, heap and event loop stats
+export const GET = withAuth(
+  withPermission('monitor:profiling', async () => {
+    if (!isProfilingEnabled()) {
+      return NextResponse.json({ error: 'Profiling is disabled' }, { status: 404 })
+    }
+    return NextResponse.json(getRuntimeStats())
+  }),
+)
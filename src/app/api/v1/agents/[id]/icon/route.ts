@@ -0,0 +1,66 @@
+import { NextResponse } from 'next/server'
+import { prisma } from '@/lib/db'
+import { withAuth, withPermission, withValidation, param } from '@/lib/middleware/auth'
+import type { AuthContext } from '@/lib/middleware/auth'
+import { setIconSchema } from '@/lib/validations/file'
+import { auditLog, diffForAudit } from '@/lib/audit'
+import { parseAgentId } from '@/lib/agents/helpers'
+import { FileRejectedError } from '@/lib/files'
+import { iconUrl, resolveIconFile } from '@/lib/files/icons'
+
+// PUT /api/v1/agents/[id]/icon — Set (or clear) the agent's icon in the agent picker
+export const PUT = withAuth(
+  withPermission(
+    'agents:manage',
+    withValidation(setIconSchema, async (req, ctx) => {
+      const { user, body } = ctx as {
+        user: NonNullable<typeof ctx.user>
+        body: typeof ctx.body
+      }
+      const id = param(ctx as unknown as AuthContext, 'id')
+      const parsed = parseAgentId(id)
+      if (!parsed) {
+        return NextResponse.json({ error: 'Invalid agent ID format' }, { status: 400 })
+      }
+
+      const instance = await prisma.instance.findUnique({ where: { id: parsed.instanceId }, select: { id: true } })
+      if (!instance) {
+        return NextResponse.json({ error: 'Instance not found' }, { status: 404 })
+      }
+
+      if (body.fileId) {
+        try {
+          await resolveIconFile(user.id, body.fileId)
+        } catch (err) {
+          if (!(err instanceof FileRejectedError)) throw err
+          return NextResponse.json({ error: err.message }, { status: 400 })
+        }
+      }
+
+      // Upsert AgentMeta (may not exist for legacy agents)
+      const existing = await prisma.agentMeta.findUnique({
+        where: { instanceId_agentId: parsed },
+        select: { iconFileId: true },
+      })
+      await prisma.agentMeta.upsert({
+        where: { instanceId_agentId: parsed },
+        update: { iconFileId: body.fileId },
+        create: { ...parsed, iconFileId: body.fileId, createdById: user.id },
+      })
+
+      auditLog({
+        userId: user.id,
+        action: 'AGENT_ICON_UPDATE',
+        resource: 'agent',
+        resourceId: id,
+        details: parsed,
+        changes: diffForAudit(existing ?? { iconFileId: null }, { iconFileId: body.fileId }),
+        ipAddress: req.headers.get('x-forwarded-for') || 'unknown',
+        userAgent: req.headers.get('user-agent') || undefined,
+        result: 'SUCCESS',
+      })
+
+      return NextResponse.json({ iconUrl: iconUrl(body.fileId) })
+    }),
+  ),
+)
@@ -0,0 +1,107 @@
+import { NextResponse } from 'next/server'
+import { prisma } from '@/lib/db'
+import { withAuth, withPermission, withValidation, param } from '@/lib/middleware/auth'
+import type { AuthContext } from '@/lib/middleware/auth'
+import { updatePreambleSchema } from '@/lib/validations/agent'
+import { auditLog, diffForAudit } from '@/lib/audit'
+import { parseAgentId } from '@/lib/agents/helpers'
+import {
+  PREAMBLE_VARIABLES,
+  savePreamble,
+  toPreambleVersionResponse,
+  unknownPreambleVariables,
+} from '@/lib/agents/preamble'
+
+// GET /api/v1/agents/[id]/preamble — The agent's managed preamble, its
+// version history and the variables templates may use
+export const GET = withAuth(
+  withPermission('agents:manage', async (_req, ctx) => {
+    const parsed = parseAgentId(param(ctx, 'id'))
+    if (!parsed) {
+      return NextResponse.json({ error: 'Invalid agent ID format' }, { status: 400 })
+    }
+
+    const preamble = await prisma.agentPreamble.findUnique({
+      where: { instanceId_agentId: parsed },
+      include: {
+        versions: {
+          include: { createdBy: { select: { name: true } } },
+          orderBy: { version: 'desc' },
+        },
+      },
+    })
+
+    return NextResponse.json({
+      preamble: preamble
+        ? {
+            template: preamble.template,
+            enabled: preamble.enabled,
+            version: preamble.version,
+            updatedAt: preamble.updatedAt.toISOString(),
+          }
+        : null,
+      versions: preamble?.versions.map(toPreambleVersionResponse) ?? [],
+      variables: PREAMBLE_VARIABLES,
+    })
+  }),
+)
+
+// PUT /api/v1/agents/[id]/preamble — Save a new version of the preamble
+export const PUT = withAuth(
+  withPermission(
+    'agents:manage',
+    withValidation(updatePreambleSchema, async (req, ctx) => {
+      const { user, body } = ctx as {
+        user: NonNullable<typeof ctx.user>
+        body: typeof ctx.body
+      }
+      const id = param(ctx as unknown as AuthContext, 'id')
+      const parsed = parseAgentId(id)
+      if (!parsed) {
+        return NextResponse.json({ error: 'Invalid agent ID format' }, { status: 400 })
+      }
+
+      const unknown = unknownPreambleVariables(body.template)
+      if (unknown.length > 0) {
+        return NextResponse.json(
+          { error: `Unknown template variables: ${unknown.map((v) => `{{${v}}}`).join(', ')}` },
+          { status: 400 },
+        )
+      }
+
+      const instance = await prisma.instance.findUnique({ where: { id: parsed.instanceId }, select: { id: true } })
+      if (!instance) {
+        return NextResponse.json({ error: 'Instance not found' }, { status: 404 })
+      }
+
+      const existing = await prisma.agentPreamble.findUnique({ where: { instanceId_agentId: parsed } })
+      const preamble = await savePreamble(
+        parsed.instanceId,
+        parsed.agentId,
+        { template: body.template, enabled: body.enabled ?? existing?.enabled ?? true },
+        user.id,
+      )
+
+      auditLog({
+        userId: user.id,
+        action: 'AGENT_PREAMBLE_UPDATE',
+        resource: 'agent',
+        resourceId: id,
+        details: { ...parsed, version: preamble.version, enabled: preamble.enabled },
+        changes: diffForAudit(existing ?? { template: '', enabled: false }, body),
+        ipAddress: req.headers.get('x-forwarded-for') || 'unknown',
+        userAgent: req.headers.get('user-agent') || undefined,
+        result: 'SUCCESS',
+      })
+
+      return NextResponse.json({
+        preamble: {
+          template: preamble.template,
+          enabled: preamble.enabled,
+          version: preamble.version,
+          updatedAt: preamble.updatedAt.toISOString(),
+        },
+      })
+    }),
+  ),
+)
@@ -4,6 +4,7 @@ import { withAuth, withPermission, withValidation } from '@/lib/middleware/auth'
 import { registry, ensureRegistryInitialized } from '@/lib/gateway/registry'
 import { updateAgentConfigSchema } from '@/lib/validations/agent'
 import { auditLog } from '@/lib/audit'
+import { findUserInstanceAccess } from '@/lib/instances/access'
 import {
   parseAgentId,
   extractAgentsConfig,
@@ -29,12 +30,7 @@ export const GET = withAuth(
 
     // Access check: non-admin users must have instance access
     if (user.role !== 'SYSTEM_ADMIN') {
-      if (!user.departmentId) {
-        return NextResponse.json({ error: 'No access to this agent' }, { status: 403 })
-      }
-      const access = await prisma.instanceAccess.findUnique({
-        where: { departmentId_instanceId: { departmentId: user.departmentId, instanceId } },
-      })
+      const access = await findUserInstanceAccess(user, instanceId)
       if (!access) {
         return NextResponse.json({ error: 'No access to this instance' }, { status: 403 })
       }
@@ -0,0 +1,113 @@
+import { NextResponse } from 'next/server'
+import { prisma } from '@/lib/db'
+import { withAuth, withPermission, withValidation, param } from '@/lib/middleware/auth'
+import type { AuthContext } from '@/lib/middleware/auth'
+import { updateCanarySchema } from '@/lib/validations/canary'
+import { auditLog, diffForAudit } from '@/lib/audit'
+import { canaryReport, toCanaryResponse } from '@/lib/agents/canary'
+
+const CANARY_INCLUDE = {
+  department: { select: { name: true } },
+  instance: { select: { name: true } },
+}
+
+// GET /api/v1/agents/canaries/[id] — A canary split with its control vs canary comparison
+export const GET = withAuth(
+  withPermission('agents:manage', async (_req, ctx) => {
+    const id = param(ctx, 'id')
+
+    const canary = await prisma.agentCanary.findUnique({ where: { id }, include: CANARY_INCLUDE })
+    if (!canary) {
+      return NextResponse.json({ error: 'Canary not found' }, { status: 404 })
+    }
+
+    return NextResponse.json({ canary: toCanaryResponse(canary), report: await canaryReport(canary) })
+  }),
+)
+
+// PUT /api/v1/agents/canaries/[id] — Change the split, rename, or pause / resume routing
+export const PUT = withAuth(
+  withPermission(
+    'agents:manage',
+    withValidation(updateCanarySchema, async (req, ctx) => {
+      const { user, body } = ctx as {
+        user: NonNullable<typeof ctx.user>
+        body: typeof ctx.body
+      }
+      const id = param(ctx as unknown as AuthContext, 'id')
+
+      const existing = await prisma.agentCanary.findUnique({ where: { id } })
+      if (!existing) {
+        return NextResponse.json({ error: 'Canary not found' }, { status: 404 })
+      }
+
+      if (body.enabled && !existing.enabled) {
+        const running = await prisma.agentCanary.findFirst({
+          where: {
+            id: { not: id },
+            departmentId: existing.departmentId,
+            instanceId: existing.instanceId,
+            controlAgentId: existing.controlAgentId,
+            enabled: true,
+          },
+          select: { id: true },
+        })
+        if (running) {
+          return NextResponse.json(
+            { error: 'A canary split is already enabled for this department and agent' },
+            { status: 409 },
+          )
+        }
+      }
+
+      // Sessions already assigned keep their arm; the change applies to new ones
+      const canary = await prisma.agentCanary.update({
+        where: { id },
+        data: body,
+        include: CANARY_INCLUDE,
+      })
+
+      auditLog({
+        userId: user.id,
+        action: 'AGENT_CANARY_UPDATE',
+        resource: 'agent_canary',
+        resourceId: id,
+        details: { name: canary.name },
+        changes: diffForAudit(existing, body),
+        ipAddress: req.headers.get('x-forwarded-for') || 'unknown',
+        userAgent: req.headers.get('user-agent') || undefined,
+        result: 'SUCCESS',
+      })
+
+      return NextResponse.json({ canary: toCanaryResponse(canary) })
+    }),
+  ),
+)
+
+// DELETE /api/v1/agents/canaries/[id] — Remove a split and its measurements
+export const DELETE = withAuth(
+  withPermission('agents:manage', async (req, ctx) => {
+    const id = param(ctx, 'id')
+
+    const existing = await prisma.agentCanary.findUnique({ where: { id } })
+    if (!existing) {
+      return NextResponse.json({ error: 'Canary not found' }, { status: 404 })
+    }
+
+    // Sessions on the canary agent stay there; they just stop being measured
+    await prisma.agentCanary.delete({ where: { id } })
+
+    auditLog({
+      userId: ctx.user.id,
+      action: 'AGENT_CANARY_DELETE',
+      resource: 'agent_canary',
+      resourceId: id,
+      details: { name: existing.name, controlAgentId: existing.controlAgentId, canaryAgentId: existing.canaryAgentId },
+      ipAddress: req.headers.get('x-forwarded-for') || 'unknown',
+      userAgent: req.headers.get('user-agent') || undefined,
+      result: 'SUCCESS',
+    })
+
+    return NextResponse.json({ success: true })
+  }),
+)
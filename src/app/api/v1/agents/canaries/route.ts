@@ -0,0 +1,92 @@
+import { NextResponse } from 'next/server'
+import { prisma } from '@/lib/db'
+import { withAuth, withPermission, withValidation } from '@/lib/middleware/auth'
+import { createCanarySchema } from '@/lib/validations/canary'
+import { auditLog } from '@/lib/audit'
+import { toCanaryResponse } from '@/lib/agents/canary'
+
+const CANARY_INCLUDE = {
+  department: { select: { name: true } },
+  instance: { select: { name: true } },
+}
+
+// GET /api/v1/agents/canaries — List canary splits
+export const GET = withAuth(
+  withPermission('agents:manage', async () => {
+    const canaries = await prisma.agentCanary.findMany({
+      include: CANARY_INCLUDE,
+      orderBy: { createdAt: 'desc' },
+    })
+    return NextResponse.json({ canaries: canaries.map(toCanaryResponse) })
+  }),
+)
+
+// POST /api/v1/agents/canaries — Route a share of a department's new sessions to a canary agent
+export const POST = withAuth(
+  withPermission(
+    'agents:manage',
+    withValidation(createCanarySchema, async (req, ctx) => {
+      const { user, body } = ctx as {
+        user: NonNullable<typeof ctx.user>
+        body: typeof ctx.body
+      }
+
+      const [department, agents] = await Promise.all([
+        prisma.department.findUnique({ where: { id: body.departmentId }, select: { id: true } }),
+        prisma.agentMeta.findMany({
+          where: { instanceId: body.instanceId, agentId: { in: [body.controlAgentId, body.canaryAgentId] } },
+          select: { agentId: true },
+        }),
+      ])
+      if (!department) {
+        return NextResponse.json({ error: 'Department not found' }, { status: 404 })
+      }
+      if (agents.length !== 2) {
+        return NextResponse.json({ error: 'Both agents must exist on the instance' }, { status: 400 })
+      }
+
+      if (body.enabled !== false) {
+        const running = await prisma.agentCanary.findFirst({
+          where: {
+            departmentId: body.departmentId,
+            instanceId: body.instanceId,
+            controlAgentId: body.controlAgentId,
+            enabled: true,
+          },
+          select: { id: true },
+        })
+        if (running) {
+          return NextResponse.json(
+            { error: 'A canary split is already enabled for this department and agent' },
+            { status: 409 },
+          )
+        }
+      }
+
+      const canary = await prisma.agentCanary.create({
+        data: { ...body, createdById: user.id },
+        include: CANARY_INCLUDE,
+      })
+
+      auditLog({
+        userId: user.id,
+        action: 'AGENT_CANARY_CREATE',
+        resource: 'agent_canary',
+        resourceId: canary.id,
+        details: {
+          name: canary.name,
+          departmentId: canary.departmentId,
+          instanceId: canary.instanceId,
+          controlAgentId: canary.controlAgentId,
+          canaryAgentId: canary.canaryAgentId,
+          percent: canary.percent,
+        },
+        ipAddress: req.headers.get('x-forwarded-for') || 'unknown',
+        userAgent: req.headers.get('user-agent') || undefined,
+        result: 'SUCCESS',
+      })
+
+      return NextResponse.json({ canary: toCanaryResponse(canary) }, { status: 201 })
+    }),
+  ),
+)
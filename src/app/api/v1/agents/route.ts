@@ -5,6 +5,9 @@ import { registry } from '@/lib/gateway/registry'
 import { ensureRegistryInitialized } from '@/lib/gateway/registry'
 import { createAgentSchema } from '@/lib/validations/agent'
 import { auditLog } from '@/lib/audit'
+import { iconUrl } from '@/lib/files/icons'
+import { canAccessInstance } from '@/lib/instances/access'
+import { parseListParams, pickFields, sortItems, type ListSpec } from '@/lib/list-params'
 import {
   extractAgentsConfig,
   resolveWorkspacePath,
@@ -19,7 +22,25 @@ import {
 import type { AgentOverview, AgentCategory } from '@/types/agent'
 import type { GatewayAgent, AgentsListResult } from '@/types/gateway'
 
-// GET /api/v1/agents — List all agents across connected instances
+// Agents come from the gateways, so they are sorted in memory
+const agentListSpec: ListSpec = {
+  sortable: {
+    id: 'id',
+    name: 'name',
+    instanceName: 'instanceName',
+    category: 'category',
+    departmentName: 'departmentName',
+    ownerName: 'ownerName',
+    isDefault: 'isDefault',
+  },
+  defaultSort: [],
+  fields: [
+    'id', 'instanceId', 'instanceName', 'name', 'workspace', 'isDefault', 'models', 'sandbox',
+    'category', 'departmentName', 'ownerName', 'iconUrl',
+  ],
+}
+
+// GET /api/v1/agents — List all agents across connected instances; ?sort= and ?fields= (lib/list-params)
 export const GET = withAuth(
   withPermission('agents:view', async (req, { user }) => {
     await ensureRegistryInitialized()
@@ -27,6 +48,8 @@ export const GET = withAuth(
     const url = new URL(req.url)
     const instanceFilter = url.searchParams.get('instanceId')
     const categoryFilter = url.searchParams.get('category') as AgentCategory | null
+    const list = parseListParams(url.searchParams, agentListSpec)
+    if (list instanceof NextResponse) return list
 
     const connectedIds = registry.getConnectedIds()
     const targetIds = instanceFilter
@@ -105,6 +128,7 @@ export const GET = withAuth(
               category: meta?.category as AgentCategory | undefined,
               departmentName: meta?.department?.name ?? null,
               ownerName: meta?.owner?.name ?? null,
+              iconUrl: iconUrl(meta?.iconFileId),
             })
           }
 
@@ -127,6 +151,7 @@ export const GET = withAuth(
                 category: meta?.category as AgentCategory | undefined,
                 departmentName: meta?.department?.name ?? null,
                 ownerName: meta?.owner?.name ?? null,
+                iconUrl: iconUrl(meta?.iconFileId),
               })
             }
           }
@@ -138,7 +163,7 @@ export const GET = withAuth(
     )
 
     return NextResponse.json({
-      agents,
+      agents: sortItems(agents, list.sort).map((a) => pickFields(a, list.fields)),
       instanceCount: targetIds.length,
       ...(errors.length > 0 ? { errors } : {}),
     })
@@ -170,21 +195,8 @@ export const POST = withAuth(
       }
 
       // For non-admins creating on gateway, verify instance access
-      if (user.role !== 'SYSTEM_ADMIN') {
-        if (!user.departmentId) {
-          return NextResponse.json({ error: 'No access to this instance' }, { status: 403 })
-        }
-        const access = await prisma.instanceAccess.findUnique({
-          where: {
-            departmentId_instanceId: {
-              departmentId: user.departmentId,
-              instanceId,
-            },
-          },
-        })
-        if (!access) {
-          return NextResponse.json({ error: 'No access to this instance' }, { status: 403 })
-        }
+      if (!(await canAccessInstance(user, instanceId))) {
+        return NextResponse.json({ error: 'No access to this instance' }, { status: 403 })
       }
 
       // Verify instance is connected
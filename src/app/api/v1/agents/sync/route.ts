@@ -0,0 +1,70 @@
+import { NextResponse } from 'next/server'
+import { prisma } from '@/lib/db'
+import { withAuth, withPermission } from '@/lib/middleware/auth'
+import { ensureRegistryInitialized } from '@/lib/gateway/registry'
+import { syncInstanceAgents, syncAllAgents } from '@/lib/agents/sync'
+import { auditLog } from '@/lib/audit'
+
+// GET /api/v1/agents/sync — Agents flagged as missing from their gateway
+export const GET = withAuth(
+  withPermission('agents:manage', async (req) => {
+    const instanceId = new URL(req.url).searchParams.get('instanceId')
+
+    const missing = await prisma.agentMeta.findMany({
+      where: {
+        missingSince: { not: null },
+        ...(instanceId ? { instanceId } : {}),
+      },
+      include: { instance: { select: { name: true } } },
+      orderBy: { missingSince: 'asc' },
+    })
+
+    return NextResponse.json({
+      missing: missing.map((m) => ({
+        id: m.id,
+        instanceId: m.instanceId,
+        instanceName: m.instance.name,
+        agentId: m.agentId,
+        category: m.category,
+        missingSince: m.missingSince!.toISOString(),
+      })),
+    })
+  }),
+)
+
+// POST /api/v1/agents/sync?instanceId= — Sync AgentMeta from gateway(s) on demand
+export const POST = withAuth(
+  withPermission('agents:manage', async (req, { user }) => {
+    await ensureRegistryInitialized()
+    const instanceId = new URL(req.url).searchParams.get('instanceId')
+
+    let response
+    if (instanceId) {
+      try {
+        response = { results: [await syncInstanceAgents(instanceId)], errors: [] }
+      } catch (err) {
+        return NextResponse.json({ error: (err as Error).message }, { status: 400 })
+      }
+    } else {
+      response = await syncAllAgents()
+    }
+
+    auditLog({
+      userId: user.id,
+      action: 'AGENT_SYNC',
+      resource: 'agent',
+      resourceId: instanceId ?? undefined,
+      details: {
+        instances: response.results.length,
+        created: response.results.reduce((n, r) => n + r.created.length, 0),
+        newlyMissing: response.results.reduce((n, r) => n + r.newlyMissing, 0),
+        failed: response.errors.length,
+      },
+      ipAddress: req.headers.get('x-forwarded-for') || 'unknown',
+      userAgent: req.headers.get('user-agent') || undefined,
+      result: response.errors.length > 0 ? 'FAILURE' : 'SUCCESS',
+    })
+
+    return NextResponse.json(response)
+  }),
+)
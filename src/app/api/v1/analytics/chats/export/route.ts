@@ -0,0 +1,60 @@
+import { NextResponse } from 'next/server'
+import { withAuth, withPermission } from '@/lib/middleware/auth'
+import { hasOrgWideView, userHasPermission } from '@/lib/auth/permissions'
+import { parseChatExportFilter, streamChatExport } from '@/lib/analytics/chat-export'
+import { auditLog } from '@/lib/audit'
+
+// GET /api/v1/analytics/chats/export — Stream chat session/message metadata
+// ?format=ndjson|csv &level=sessions|messages &from= &to= &instanceId=
+// &agentId= &userId= &departmentId= &includeContent=true (SYSTEM_ADMIN only)
+export const GET = withAuth(
+  withPermission('analytics:export', async (req, ctx) => {
+    const { user } = ctx
+    const filter = parseChatExportFilter(new URL(req.url))
+    if (typeof filter === 'string') {
+      return NextResponse.json({ error: filter }, { status: 400 })
+    }
+
+    if (filter.includeContent && !userHasPermission(user, 'analytics:export_content')) {
+      return NextResponse.json({ error: 'Insufficient permissions to export message content' }, { status: 403 })
+    }
+
+    // DEPT_ADMIN: pinned to own department
+    if (!hasOrgWideView(user.role)) {
+      if (!user.departmentId) {
+        return NextResponse.json({ error: 'No department assigned' }, { status: 403 })
+      }
+      filter.departmentId = user.departmentId
+    }
+
+    await auditLog({
+      userId: user.id,
+      action: 'ANALYTICS_EXPORT',
+      resource: 'chat_session',
+      details: {
+        format: filter.format,
+        level: filter.level,
+        from: filter.from?.toISOString() ?? null,
+        to: filter.to?.toISOString() ?? null,
+        instanceId: filter.instanceId,
+        agentId: filter.agentId,
+        userId: filter.userId,
+        departmentId: filter.departmentId,
+        includeContent: filter.includeContent,
+      },
+      ipAddress: req.headers.get('x-forwarded-for') || 'unknown',
+      userAgent: req.headers.get('user-agent') || undefined,
+      result: 'SUCCESS',
+    })
+
+    const date = new Date().toISOString().slice(0, 10)
+    const ext = filter.format === 'csv' ? 'csv' : 'ndjson'
+    return new NextResponse(streamChatExport(filter), {
+      headers: {
+        'Content-Type': filter.format === 'csv' ? 'text/csv; charset=utf-8' : 'application/x-ndjson',
+        'Content-Disposition': `attachment; filename="chat-${filter.level}-${date}.${ext}"`,
+        'Cache-Control': 'no-store',
+      },
+    })
+  }),
+)
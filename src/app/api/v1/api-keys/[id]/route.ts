@@ -0,0 +1,118 @@
+import { NextResponse } from 'next/server'
+import { prisma } from '@/lib/db'
+import { withAuth, withPermission, withValidation, param } from '@/lib/middleware/auth'
+import type { AuthContext } from '@/lib/middleware/auth'
+import { updateApiKeySchema } from '@/lib/validations/api-key'
+import { auditLog, diffForAudit } from '@/lib/audit'
+import { invalidScopes, toApiKeyResponse } from '@/lib/auth/api-keys'
+import type { AuthUser } from '@/types/auth'
+
+/** The key, if it exists and the user may manage it (its creator or a system admin) */
+async function findOwnKey(id: string, user: AuthUser) {
+  const key = await prisma.apiKey.findUnique({
+    where: { id },
+    include: { createdBy: { select: { name: true } } },
+  })
+  if (!key || (user.role !== 'SYSTEM_ADMIN' && key.createdById !== user.id)) return null
+  return key
+}
+
+// GET /api/v1/api-keys/[id] — Key detail
+export const GET = withAuth(
+  withPermission('api_keys:manage', async (_req, ctx) => {
+    const key = await findOwnKey(param(ctx, 'id'), ctx.user)
+    if (!key) {
+      return NextResponse.json({ error: 'API key not found' }, { status: 404 })
+    }
+    return NextResponse.json({ apiKey: toApiKeyResponse(key) })
+  }),
+)
+
+// PUT /api/v1/api-keys/[id] — Rename or change scopes
+export const PUT = withAuth(
+  withPermission(
+    'api_keys:manage',
+    withValidation(updateApiKeySchema, async (req, ctx) => {
+      const { user, body } = ctx as {
+        user: NonNullable<typeof ctx.user>
+        body: typeof ctx.body
+      }
+      const id = param(ctx as unknown as AuthContext, 'id')
+
+      if (user.apiKey) {
+        return NextResponse.json({ error: 'API keys cannot be changed with an API key' }, { status: 403 })
+      }
+      const existing = await findOwnKey(id, user)
+      if (!existing) {
+        return NextResponse.json({ error: 'API key not found' }, { status: 404 })
+      }
+      if (existing.revokedAt) {
+        return NextResponse.json({ error: 'API key has been revoked' }, { status: 409 })
+      }
+      const unknown = body.scopes ? invalidScopes(existing.role, body.scopes) : []
+      if (unknown.length > 0) {
+        return NextResponse.json(
+          { error: `Scopes not granted to role ${existing.role}: ${unknown.join(', ')}` },
+          { status: 400 },
+        )
+      }
+
+      const key = await prisma.apiKey.update({
+        where: { id },
+        data: body,
+        include: { createdBy: { select: { name: true } } },
+      })
+
+      auditLog({
+        userId: user.id,
+        action: 'API_KEY_UPDATE',
+        resource: 'api_key',
+        resourceId: id,
+        details: { name: key.name },
+        changes: diffForAudit(existing, body),
+        ipAddress: req.headers.get('x-forwarded-for') || 'unknown',
+        userAgent: req.headers.get('user-agent') || undefined,
+        result: 'SUCCESS',
+      })
+
+      return NextResponse.json({ apiKey: toApiKeyResponse(key) })
+    }),
+  ),
+)
+
+// DELETE /api/v1/api-keys/[id] — Revoke the key and disable its service account
+export const DELETE = withAuth(
+  withPermission('api_keys:manage', async (req, ctx) => {
+    const id = param(ctx, 'id')
+
+    const existing = await findOwnKey(id, ctx.user)
+    if (!existing) {
+      return NextResponse.json({ error: 'API key not found' }, { status: 404 })
+    }
+    if (existing.revokedAt) {
+      return NextResponse.json({ success: true })
+    }
+
+    // The row stays so audit entries by the service account remain attributable
+    await prisma.$transaction([
+      prisma.apiKey.update({ where: { id }, data: { revokedAt: new Date() } }),
+      prisma.user.update({
+        where: { id: existing.serviceAccountId },
+        data: { status: 'DISABLED' },
+      }),
+    ])
+
+    auditLog({
+      userId: ctx.user.id,
+      action: 'API_KEY_REVOKE',
+      resource: 'api_key',
+      resourceId: id,
+      details: { name: existing.name, keyPrefix: existing.keyPrefix },
+      ipAddress: req.headers.get('x-forwarded-for') || 'unknown',
+      userAgent: req.headers.get('user-agent') || undefined,
+      result: 'SUCCESS',
+    })
+
+    return NextResponse.json({ success: true })
+  }),
+)
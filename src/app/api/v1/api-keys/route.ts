@@ -0,0 +1,86 @@
+import { NextResponse } from 'next/server'
+import { prisma } from '@/lib/db'
+import { stringList } from '@/lib/db-dialect'
+import { withAuth, withPermission, withValidation } from '@/lib/middleware/auth'
+import { createApiKeySchema } from '@/lib/validations/api-key'
+import { auditLog } from '@/lib/audit'
+import { createServiceAccount } from '@/lib/users/service-accounts'
+import { canIssueRole, generateApiKey, invalidScopes, toApiKeyResponse } from '@/lib/auth/api-keys'
+import type { Role } from '@/generated/prisma'
+
+const DAY_MS = 24 * 60 * 60 * 1000
+
+// GET /api/v1/api-keys — Own keys (system admins see all)
+export const GET = withAuth(
+  withPermission('api_keys:manage', async (_req, ctx) => {
+    const keys = await prisma.apiKey.findMany({
+      where: ctx.user.role === 'SYSTEM_ADMIN' ? {} : { createdById: ctx.user.id },
+      include: { createdBy: { select: { name: true } } },
+      orderBy: { createdAt: 'desc' },
+    })
+    return NextResponse.json({ apiKeys: keys.map(toApiKeyResponse) })
+  }),
+)
+
+// POST /api/v1/api-keys — Issue a key (the key itself is only returned here)
+export const POST = withAuth(
+  withPermission(
+    'api_keys:manage',
+    withValidation(createApiKeySchema, async (req, ctx) => {
+      const { user, body } = ctx as {
+        user: NonNullable<typeof ctx.user>
+        body: typeof ctx.body
+      }
+
+      // A leaked key must not be able to mint more keys
+      if (user.apiKey) {
+        return NextResponse.json({ error: 'API keys cannot be issued with an API key' }, { status: 403 })
+      }
+
+      const role = (body.role ?? user.role) as Role
+      if (!canIssueRole(user.role, role)) {
+        return NextResponse.json({ error: 'You can only issue keys with your own role' }, { status: 403 })
+      }
+      const unknown = invalidScopes(role, body.scopes)
+      if (unknown.length > 0) {
+        return NextResponse.json(
+          { error: `Scopes not granted to role ${role}: ${unknown.join(', ')}` },
+          { status: 400 },
+        )
+      }
+
+      const { key, keyHash, keyPrefix } = generateApiKey()
+      const serviceAccountId = await createServiceAccount(`API key: ${body.name}`, 'apikey', {
+        role,
+        departmentId: user.departmentId,
+      })
+
+      const apiKey = await prisma.apiKey.create({
+        data: {
+          name: body.name,
+          keyHash,
+          keyPrefix,
+          role,
+          scopes: body.scopes,
+          serviceAccountId,
+          createdById: user.id,
+          expiresAt: body.expiresInDays ? new Date(Date.now() + body.expiresInDays * DAY_MS) : null,
+        },
+        include: { createdBy: { select: { name: true } } },
+      })
+
+      auditLog({
+        userId: user.id,
+        action: 'API_KEY_CREATE',
+        resource: 'api_key',
+        resourceId: apiKey.id,
+        details: { name: apiKey.name, role, scopes: stringList(apiKey.scopes).join(','), expiresAt: apiKey.expiresAt },
+        ipAddress: req.headers.get('x-forwarded-for') || 'unknown',
+        userAgent: req.headers.get('user-agent') || undefined,
+        result: 'SUCCESS',
+      })
+
+      return NextResponse.json({ apiKey: toApiKeyResponse(apiKey), key }, { status: 201 })
+    }),
+  ),
+)
@@ -0,0 +1,127 @@
+import { NextResponse } from 'next/server'
+import { prisma } from '@/lib/db'
+import { withAuth, withPermission, withValidation, param } from '@/lib/middleware/auth'
+import type { AuthContext } from '@/lib/middleware/auth'
+import { reviewApprovalSchema } from '@/lib/validations/approval'
+import { expireStaleApprovals, toApprovalResponse } from '@/lib/approvals'
+import type { ApprovalAction } from '@/lib/approvals'
+import { APPROVAL_EXECUTORS } from '@/lib/approvals/executors'
+import { auditLog } from '@/lib/audit'
+
+// POST /api/v1/approvals/[id]/approve — Approve and execute (second admin only)
+export const POST = withAuth(
+  withPermission(
+    'approvals:review',
+    withValidation(reviewApprovalSchema, async (req, ctx) => {
+      const { user, body } = ctx as {
+        user: NonNullable<typeof ctx.user>
+        body: typeof ctx.body
+      }
+      const id = param(ctx as unknown as AuthContext, 'id')
+      const ipAddress = req.headers.get('x-forwarded-for') || 'unknown'
+      const userAgent = req.headers.get('user-agent') || undefined
+
+      await expireStaleApprovals()
+
+      const approval = await prisma.approvalRequest.findUnique({ where: { id } })
+      if (!approval) {
+        return NextResponse.json({ error: 'Approval request not found' }, { status: 404 })
+      }
+      if (approval.status !== 'PENDING') {
+        return NextResponse.json(
+          { error: `Approval request is ${approval.status.toLowerCase()}` },
+          { status: 409 },
+        )
+      }
+      if (approval.requestedById === user.id) {
+        auditLog({
+          userId: user.id,
+          action: 'APPROVAL_APPROVE',
+          resource: 'approval',
+          resourceId: id,
+          details: { action: approval.action, reason: 'self-approval' },
+          ipAddress,
+          userAgent,
+          result: 'DENIED',
+        })
+        return NextResponse.json(
+          { error: 'A different administrator must approve this request' },
+          { status: 403 },
+        )
+      }
+
+      // Claim the request atomically so concurrent approvals execute once
+      const claimed = await prisma.approvalRequest.updateMany({
+        where: { id, status: 'PENDING' },
+        data: {
+          status: 'APPROVED',
+          reviewedById: user.id,
+          reviewComment: body.comment,
+          reviewedAt: new Date(),
+        },
+      })
+      if (claimed.count === 0) {
+        return NextResponse.json({ error: 'Approval request already reviewed' }, { status: 409 })
+      }
+
+      auditLog({
+        userId: user.id,
+        action: 'APPROVAL_APPROVE',
+        resource: 'approval',
+        resourceId: id,
+        details: { action: approval.action, requestedById: approval.requestedById },
+        ipAddress,
+        userAgent,
+        result: 'SUCCESS',
+      })
+
+      const executor = APPROVAL_EXECUTORS[approval.action as ApprovalAction]
+      try {
+        if (!executor) throw new Error(`No executor for action ${approval.action}`)
+        const details = await executor(approval.payload as Record<string, unknown>, user.id)
+
+        await prisma.approvalRequest.update({
+          where: { id },
+          data: { executedAt: new Date() },
+        })
+
+        auditLog({
+          userId: approval.requestedById,
+          action: approval.action,
+          resource: approval.resource,
+          resourceId: approval.resourceId ?? undefined,
+          details: { ...details, approvalId: id, approvedById: user.id },
+          ipAddress,
+          userAgent,
+          result: 'SUCCESS',
+        })
+      } catch (err) {
+        await prisma.approvalRequest.update({
+          where: { id },
+          data: { status: 'FAILED', error: (err as Error).message },
+        })
+
+        auditLog({
+          userId: approval.requestedById,
+          action: approval.action,
+          resource: approval.resource,
+          resourceId: approval.resourceId ?? undefined,
+          details: { approvalId: id, approvedById: user.id, error: (err as Error).message },
+          ipAddress,
+          userAgent,
+          result: 'FAILURE',
+        })
+      }
+
+      const updated = await prisma.approvalRequest.findUniqueOrThrow({
+        where: { id },
+        include: {
+          requestedBy: { select: { name: true } },
+          reviewedBy: { select: { name: true } },
+        },
+      })
+
+      return NextResponse.json({ approval: toApprovalResponse(updated) })
+    }),
+  ),
+)
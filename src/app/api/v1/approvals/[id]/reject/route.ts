@@ -0,0 +1,70 @@
+import { NextResponse } from 'next/server'
+import { prisma } from '@/lib/db'
+import { withAuth, withPermission, withValidation, param } from '@/lib/middleware/auth'
+import type { AuthContext } from '@/lib/middleware/auth'
+import { reviewApprovalSchema } from '@/lib/validations/approval'
+import { expireStaleApprovals, toApprovalResponse } from '@/lib/approvals'
+import { auditLog } from '@/lib/audit'
+
+// POST /api/v1/approvals/[id]/reject — Reject a pending request
+export const POST = withAuth(
+  withPermission(
+    'approvals:review',
+    withValidation(reviewApprovalSchema, async (req, ctx) => {
+      const { user, body } = ctx as {
+        user: NonNullable<typeof ctx.user>
+        body: typeof ctx.body
+      }
+      const id = param(ctx as unknown as AuthContext, 'id')
+
+      await expireStaleApprovals()
+
+      const approval = await prisma.approvalRequest.findUnique({ where: { id } })
+      if (!approval) {
+        return NextResponse.json({ error: 'Approval request not found' }, { status: 404 })
+      }
+
+      // The requester may withdraw their own request by rejecting it
+      const claimed = await prisma.approvalRequest.updateMany({
+        where: { id, status: 'PENDING' },
+        data: {
+          status: 'REJECTED',
+          reviewedById: user.id,
+          reviewComment: body.comment,
+          reviewedAt: new Date(),
+        },
+      })
+      if (claimed.count === 0) {
+        return NextResponse.json(
+          { error: `Approval request is ${approval.status.toLowerCase()}` },
+          { status: 409 },
+        )
+      }
+
+      auditLog({
+        userId: user.id,
+        action: 'APPROVAL_REJECT',
+        resource: 'approval',
+        resourceId: id,
+        details: {
+          action: approval.action,
+          requestedById: approval.requestedById,
+          comment: body.comment ?? null,
+        },
+        ipAddress: req.headers.get('x-forwarded-for') || 'unknown',
+        userAgent: req.headers.get('user-agent') || undefined,
+        result: 'SUCCESS',
+      })
+
+      const updated = await prisma.approvalRequest.findUniqueOrThrow({
+        where: { id },
+        include: {
+          requestedBy: { select: { name: true } },
+          reviewedBy: { select: { name: true } },
+        },
+      })
+
+      return NextResponse.json({ approval: toApprovalResponse(updated) })
+    }),
+  ),
+)
@@ -0,0 +1,66 @@
+import { NextResponse } from 'next/server'
+import { withAuth, withPermission, withValidation } from '@/lib/middleware/auth'
+import { approvalPolicySchema } from '@/lib/validations/approval'
+import { getApprovalPolicy, setApprovalPolicy, interceptForApproval, APPROVAL_ACTIONS } from '@/lib/approvals'
+import { auditLog } from '@/lib/audit'
+
+// GET /api/v1/approvals/policy — Actions requiring two-person approval
+export const GET = withAuth(
+  withPermission('approvals:review', async () => {
+    const policy = await getApprovalPolicy()
+    return NextResponse.json({ policy, availableActions: APPROVAL_ACTIONS })
+  }),
+)
+
+// PUT /api/v1/approvals/policy — Update approval policy; taking actions out of
+// it needs a second administrator's approval (APPROVAL_POLICY_RELAX)
+export const PUT = withAuth(
+  withPermission(
+    'config:manage',
+    withValidation(approvalPolicySchema, async (req, ctx) => {
+      const { user, body } = ctx as {
+        user: NonNullable<typeof ctx.user>
+        body: typeof ctx.body
+      }
+
+      const before = await getApprovalPolicy()
+      const policy = { requiredActions: [...new Set(body.requiredActions)], expiryHours: body.expiryHours }
+
+      const removed = before.requiredActions.filter((a) => !policy.requiredActions.includes(a))
+      if (removed.length > 0) {
+        const pending = await interceptForApproval(req, {
+          action: 'APPROVAL_POLICY_RELAX',
+          resource: 'system_config',
+          resourceId: 'approval_policy',
+          payload: policy,
+          summary: {
+            removed: removed.join(','),
+            after: policy.requiredActions.join(','),
+            expiryHours: policy.expiryHours,
+          },
+          requestedById: user.id,
+        })
+        if (pending) return pending
+      }
+
+      await setApprovalPolicy(policy)
+
+      auditLog({
+        userId: user.id,
+        action: 'APPROVAL_POLICY_UPDATE',
+        resource: 'system_config',
+        resourceId: 'approval_policy',
+        details: {
+          before: before.requiredActions.join(','),
+          after: policy.requiredActions.join(','),
+          expiryHours: policy.expiryHours,
+        },
+        ipAddress: req.headers.get('x-forwarded-for') || 'unknown',
+        userAgent: req.headers.get('user-agent') || undefined,
+        result: 'SUCCESS',
+      })
+
+      return NextResponse.json({ policy })
+    }),
+  ),
+)
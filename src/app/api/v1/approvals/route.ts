@@ -0,0 +1,43 @@
+import { NextResponse } from 'next/server'
+import { prisma } from '@/lib/db'
+import { withAuth, withPermission } from '@/lib/middleware/auth'
+import { expireStaleApprovals, toApprovalResponse } from '@/lib/approvals'
+import type { ApprovalStatus, Prisma } from '@/generated/prisma'
+
+const STATUSES: ApprovalStatus[] = ['PENDING', 'APPROVED', 'REJECTED', 'EXPIRED', 'FAILED']
+
+// GET /api/v1/approvals — List approval requests
+export const GET = withAuth(
+  withPermission('approvals:review', async (req) => {
+    await expireStaleApprovals()
+
+    const url = new URL(req.url)
+    const page = Math.max(1, parseInt(url.searchParams.get('page') || '1'))
+    const pageSize = Math.min(100, Math.max(1, parseInt(url.searchParams.get('pageSize') || '20')))
+    const status = url.searchParams.get('status') as ApprovalStatus | null
+
+    const where: Prisma.ApprovalRequestWhereInput = {}
+    if (status && STATUSES.includes(status)) where.status = status
+
+    const [approvals, total] = await Promise.all([
+      prisma.approvalRequest.findMany({
+        where,
+        include: {
+          requestedBy: { select: { name: true } },
+          reviewedBy: { select: { name: true } },
+        },
+        orderBy: { createdAt: 'desc' },
+        skip: (page - 1) * pageSize,
+        take: pageSize,
+      }),
+      prisma.approvalRequest.count({ where }),
+    ])
+
+    return NextResponse.json({
+      approvals: approvals.map(toApprovalResponse),
+      total,
+      page,
+      pageSize,
+    })
+  }),
+)
@@ -0,0 +1,65 @@
+import { NextResponse } from 'next/server'
+import { prisma } from '@/lib/db'
+import { withAuth, withPermission, param } from '@/lib/middleware/auth'
+import { getDisplayName } from '@/lib/utils/display-name'
+import type { AuditChanges } from '@/lib/audit'
+import type { AuditDiffResponse, AuditFieldChange } from '@/types/audit'
+
+function formatValue(value: unknown): string {
+  if (value === null || value === undefined) return '(empty)'
+  if (typeof value === 'string') return value
+  return JSON.stringify(value)
+}
+
+// GET /api/v1/audit-logs/[id]/diff — Before/after state of an update
+export const GET = withAuth(
+  withPermission('audit:view_dept', async (_req, ctx) => {
+    const { user } = ctx
+    const id = param(ctx, 'id')
+
+    const log = await prisma.auditLog.findUnique({
+      where: { id },
+      include: { user: { select: { name: true, email: true, departmentId: true } } },
+    })
+    if (!log) {
+      return NextResponse.json({ error: 'Audit log not found' }, { status: 404 })
+    }
+
+    // DEPT_ADMIN: only entries written by department members
+    if (user.role === 'DEPT_ADMIN' && log.user.departmentId !== user.departmentId) {
+      return NextResponse.json({ error: 'Audit log not found' }, { status: 404 })
+    }
+
+    const details = (log.details ?? {}) as Record<string, unknown>
+    const recorded = (details.changes ?? {}) as AuditChanges
+    const changes: AuditFieldChange[] = Object.entries(recorded).map(([field, c]) => ({
+      field,
+      before: c.before,
+      after: c.after,
+    }))
+
+    const text = changes
+      .map((c) => `- ${c.field}: ${formatValue(c.before)}\n+ ${c.field}: ${formatValue(c.after)}`)
+      .join('\n')
+
+    const response: AuditDiffResponse = {
+      log: {
+        id: log.id,
+        userId: log.userId,
+        userName: getDisplayName(log.user),
+        action: log.action,
+        resource: log.resource,
+        resourceId: log.resourceId,
+        details,
+        ipAddress: log.ipAddress,
+        userAgent: log.userAgent,
+        result: log.result,
+        createdAt: log.createdAt.toISOString(),
+      },
+      changes,
+      text,
+    }
+
+    return NextResponse.json(response)
+  }),
+)
@@ -3,7 +3,7 @@ import { prisma } from '@/lib/db'
 import { withAuth, withPermission } from '@/lib/middleware/auth'
 import { getDisplayName } from '@/lib/utils/display-name'
 
-// GET /api/v1/audit-logs/export — Export audit logs as CSV (SYSTEM_ADMIN only)
+// GET /api/v1/audit-logs/export — Export audit logs as CSV (SYSTEM_ADMIN / VIEWER)
 export const GET = withAuth(
   withPermission('audit:view_all', async (req) => {
     const url = new URL(req.url)
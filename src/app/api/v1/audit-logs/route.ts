@@ -1,24 +1,46 @@
 import { NextResponse } from 'next/server'
 import type { Prisma } from '@/generated/prisma'
 import { prisma } from '@/lib/db'
+import { containsInsensitive } from '@/lib/db-dialect'
 import { withAuth, withPermission } from '@/lib/middleware/auth'
 import { getDisplayName } from '@/lib/utils/display-name'
+import { parseListParams, pickFields, type ListSpec } from '@/lib/list-params'
+import { applySavedFilter } from '@/lib/saved-filters'
 import type { AuditLogEntry, AuditLogListResponse } from '@/types/audit'
 
-// GET /api/v1/audit-logs — List audit logs with filtering + pagination
+const auditLogListSpec: ListSpec = {
+  sortable: {
+    createdAt: 'createdAt',
+    action: 'action',
+    resource: 'resource',
+    result: 'result',
+    userName: 'user.name',
+  },
+  defaultSort: [{ field: 'createdAt', direction: 'desc' }],
+  fields: [
+    'id', 'userId', 'userName', 'action', 'resource', 'resourceId', 'details',
+    'ipAddress', 'userAgent', 'result', 'createdAt',
+  ],
+}
+
+// GET /api/v1/audit-logs — List audit logs with filtering + pagination; ?sort= and ?fields= (lib/list-params), ?savedFilter= (lib/saved-filters)
 export const GET = withAuth(
   withPermission('audit:view_dept', async (req, ctx) => {
     const { user } = ctx
     const url = new URL(req.url)
+    const query = await applySavedFilter(url.searchParams, 'audit-logs', user)
+    if (query instanceof NextResponse) return query
 
-    const page = Math.max(1, parseInt(url.searchParams.get('page') || '1'))
-    const pageSize = Math.min(100, Math.max(1, parseInt(url.searchParams.get('pageSize') || '50')))
-    const search = url.searchParams.get('search')?.trim().slice(0, 100)
-    const action = url.searchParams.get('action')
-    const resource = url.searchParams.get('resource')
-    const result = url.searchParams.get('result')
-    const startDate = url.searchParams.get('startDate')
-    const endDate = url.searchParams.get('endDate')
+    const page = Math.max(1, parseInt(query.get('page') || '1'))
+    const pageSize = Math.min(100, Math.max(1, parseInt(query.get('pageSize') || '50')))
+    const search = query.get('search')?.trim().slice(0, 100)
+    const action = query.get('action')
+    const resource = query.get('resource')
+    const result = query.get('result')
+    const startDate = query.get('startDate')
+    const endDate = query.get('endDate')
+    const list = parseListParams<Prisma.AuditLogOrderByWithRelationInput>(query, auditLogListSpec)
+    if (list instanceof NextResponse) return list
 
     const where: Prisma.AuditLogWhereInput = {}
 
@@ -43,11 +65,11 @@ export const GET = withAuth(
 
     if (search) {
       where.OR = [
-        { action: { contains: search, mode: 'insensitive' } },
-        { resource: { contains: search, mode: 'insensitive' } },
-        { resourceId: { contains: search, mode: 'insensitive' } },
-        { user: { name: { contains: search, mode: 'insensitive' } } },
-        { user: { email: { contains: search, mode: 'insensitive' } } },
+        { action: containsInsensitive(search) },
+        { resource: containsInsensitive(search) },
+        { resourceId: containsInsensitive(search) },
+        { user: { name: containsInsensitive(search) } },
+        { user: { email: containsInsensitive(search) } },
       ]
     }
 
@@ -55,7 +77,7 @@ export const GET = withAuth(
       prisma.auditLog.findMany({
         where,
         include: { user: { select: { name: true, email: true } } },
-        orderBy: { createdAt: 'desc' },
+        orderBy: list.orderBy,
         skip: (page - 1) * pageSize,
         take: pageSize,
       }),
@@ -77,7 +99,7 @@ export const GET = withAuth(
     }))
 
     const response: AuditLogListResponse = {
-      logs: items,
+      logs: items.map((item) => pickFields(item, list.fields) as AuditLogEntry),
       total,
       page,
       pageSize,
@@ -0,0 +1,115 @@
+import { NextRequest, NextResponse } from 'next/server'
+import { prisma } from '@/lib/db'
+import { withAuth, withPermission, withValidation } from '@/lib/middleware/auth'
+import { verifyPassword } from '@/lib/auth/password'
+import { getLdapConfig, verifyDirectoryPassword } from '@/lib/auth/ldap'
+import { breakGlassSchema } from '@/lib/validations/auth'
+import {
+  activateBreakGlass,
+  DEFAULT_BREAK_GLASS_MINUTES,
+  endBreakGlass,
+  findActiveBreakGlass,
+  maxBreakGlassMinutes,
+} from '@/lib/auth/break-glass'
+import { auditLog } from '@/lib/audit'
+
+function isSecure(req: NextRequest): boolean {
+  return req.headers.get('x-forwarded-proto') === 'https'
+}
+
+// POST /api/v1/auth/break-glass — Emergency SYSTEM_ADMIN access for a short window
+export const POST = withAuth(
+  withPermission(
+    'break_glass:activate',
+    withValidation(breakGlassSchema, async (req, ctx) => {
+      const { user, body } = ctx as {
+        user: NonNullable<typeof ctx.user>
+        body: typeof ctx.body
+      }
+      const ipAddress = req.headers.get('x-forwarded-for') || 'unknown'
+      const userAgent = req.headers.get('user-agent') || undefined
+
+      if (user.breakGlassId) {
+        return NextResponse.json({ error: 'Break-glass access is already active' }, { status: 409 })
+      }
+
+      const minutes = body.minutes ?? DEFAULT_BREAK_GLASS_MINUTES
+      const max = maxBreakGlassMinutes()
+      if (minutes > max) {
+        return NextResponse.json({ error: `At most ${max} minutes can be requested` }, { status: 400 })
+      }
+
+      // Re-authenticate: a stolen session alone must not be enough
+      const account = await prisma.user.findUniqueOrThrow({
+        where: { id: user.id },
+        select: { passwordHash: true, ldapDn: true },
+      })
+      const ldap = getLdapConfig()
+      let passwordValid: boolean
+      try {
+        passwordValid =
+          ldap && account.ldapDn
+            ? await verifyDirectoryPassword(ldap, account.ldapDn, body.password)
+            : await verifyPassword(body.password, account.passwordHash)
+      } catch {
+        return NextResponse.json({ error: 'Directory service is unavailable' }, { status: 503 })
+      }
+      if (!passwordValid) {
+        auditLog({
+          userId: user.id,
+          action: 'BREAK_GLASS_ACTIVATE',
+          resource: 'auth',
+          details: { reason: 'invalid password' },
+          ipAddress,
+          userAgent,
+          result: 'DENIED',
+        })
+        return NextResponse.json({ error: 'Invalid password' }, { status: 403 })
+      }
+
+      const { session, token } = await activateBreakGlass(user, body.justification, minutes, {
+        ipAddress,
+        userAgent,
+      })
+
+      const response = NextResponse.json(
+        { sessionId: session.id, expiresAt: session.expiresAt.toISOString(), accessToken: token },
+        { status: 201 },
+      )
+      // Replaces the normal access token; once it lapses the client refreshes back to its own role
+      response.cookies.set('access_token', token, {
+        httpOnly: true,
+        secure: isSecure(req),
+        sameSite: 'lax',
+        maxAge: minutes * 60,
+        path: '/',
+      })
+      return response
+    }),
+  ),
+)
+
+// DELETE /api/v1/auth/break-glass — End the caller's break-glass session early
+export const DELETE = withAuth(async (req, { user }) => {
+  if (!user.breakGlassId) {
+    return NextResponse.json({ error: 'No break-glass session is active' }, { status: 404 })
+  }
+
+  const session = await findActiveBreakGlass(user.breakGlassId, user.id)
+  if (session) {
+    await endBreakGlass(session, user.id, {
+      ipAddress: req.headers.get('x-forwarded-for') || 'unknown',
+      userAgent: req.headers.get('user-agent') || undefined,
+    })
+  }
+
+  const response = new NextResponse(null, { status: 204 })
+  response.cookies.set('access_token', '', {
+    httpOnly: true,
+    secure: isSecure(req),
+    sameSite: 'lax',
+    maxAge: 0,
+    path: '/',
+  })
+  return response
+})
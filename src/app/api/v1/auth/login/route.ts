@@ -1,7 +1,8 @@
 import { NextRequest, NextResponse } from 'next/server'
 import { createHash } from 'crypto'
 import { prisma } from '@/lib/db'
-import { signAccessToken, signRefreshToken } from '@/lib/auth/jwt'
+import { effectiveRole } from '@/lib/auth/permissions'
+import { signAccessToken, signMfaToken, signRefreshToken } from '@/lib/auth/jwt'
 import { verifyPassword } from '@/lib/auth/password'
 import { loginSchema } from '@/lib/validations/auth'
 import {
@@ -11,6 +12,10 @@ import {
   clearLoginFailures,
 } from '@/lib/redis'
 import { auditLog } from '@/lib/audit'
+import { getLdapConfig, authenticateWithDirectory, syncDirectoryUser } from '@/lib/auth/ldap'
+import { createLogger } from '@/lib/logger'
+
+const log = createLogger('auth:ldap')
 
 function getClientIp(req: NextRequest): string {
   return (
@@ -27,6 +32,13 @@ function isSecure(req: NextRequest): boolean {
   return req.headers.get('x-forwarded-proto') === 'https'
 }
 
+function findLocalUser(email: string) {
+  return prisma.user.findUnique({
+    where: { email },
+    include: { department: true },
+  })
+}
+
 export async function POST(req: NextRequest) {
   const ip = getClientIp(req)
   const userAgent = req.headers.get('user-agent') || undefined
@@ -73,13 +85,45 @@ export async function POST(req: NextRequest) {
     )
   }
 
-  // Find user
-  const user = await prisma.user.findUnique({
-    where: { email },
-    include: { department: true },
-  })
+  // Directory login (lib/auth/ldap): an entry found there decides, anyone
+  // else falls through to the local password
+  const ldap = getLdapConfig()
+  let directory: Awaited<ReturnType<typeof authenticateWithDirectory>> | null = null
+  if (ldap) {
+    try {
+      directory = await authenticateWithDirectory(ldap, email, password)
+    } catch (err) {
+      log.error('Directory lookup failed', { error: (err as Error).message })
+    }
+  }
 
-  if (!user) {
+  let user: Awaited<ReturnType<typeof findLocalUser>>
+  let synced: string[] = []
+  if (directory?.status === 'ok') {
+    const result = await syncDirectoryUser(ldap!, email, directory.entry)
+    if ('error' in result) {
+      return NextResponse.json({ error: result.error }, { status: 403 })
+    }
+    user = result.user
+    synced = result.synced
+    if (result.created) {
+      auditLog({
+        userId: user.id,
+        action: 'REGISTER',
+        resource: 'auth',
+        resourceId: user.id,
+        details: { method: 'ldap', role: user.role },
+        ipAddress: ip,
+        userAgent,
+        result: 'SUCCESS',
+      })
+    }
+  } else {
+    user = await findLocalUser(email)
+  }
+
+  // Service accounts have no usable password and never log in interactively
+  if (!user || user.isServiceAccount) {
     await recordLoginFailure(email)
     return NextResponse.json(
       { error: 'Invalid email or password' },
@@ -103,8 +147,21 @@ export async function POST(req: NextRequest) {
     )
   }
 
-  // Verify password
-  const passwordValid = await verifyPassword(password, user.passwordHash)
+  // Directory-managed accounts sign in only through the directory while it is
+  // enabled: refused when it rejects the password or no longer lists them
+  if (ldap && user.ldapDn && !directory) {
+    return NextResponse.json(
+      { error: 'Directory service is unavailable. Please try again later.' },
+      { status: 503 }
+    )
+  }
+
+  // Verify password (the directory bind already has for its entries)
+  const passwordValid =
+    directory?.status === 'ok' ||
+    (directory?.status !== 'invalid' &&
+      !(ldap && user.ldapDn) &&
+      (await verifyPassword(password, user.passwordHash)))
   if (!passwordValid) {
     await recordLoginFailure(email)
     auditLog({
@@ -125,9 +182,17 @@ export async function POST(req: NextRequest) {
   // Success: clear failures, generate tokens
   await clearLoginFailures(email)
 
+  // Second factor: no session yet, only a short-lived token for /auth/mfa/verify
+  if (user.mfaEnabled) {
+    return NextResponse.json({
+      status: 'mfa_required',
+      mfaToken: await signMfaToken(user.id),
+    })
+  }
+
   const accessToken = await signAccessToken({
     userId: user.id,
-    role: user.role,
+    role: effectiveRole(user),
   })
   const refreshToken = await signRefreshToken(user.id)
   const tokenHash = createHash('sha256').update(refreshToken).digest('hex')
@@ -152,6 +217,7 @@ export async function POST(req: NextRequest) {
     userId: user.id,
     action: 'LOGIN',
     resource: 'auth',
+    details: directory?.status === 'ok' ? { method: 'ldap', synced: synced.join(',') || 'none' } : undefined,
     ipAddress: ip,
     userAgent,
     result: 'SUCCESS',
@@ -163,7 +229,7 @@ export async function POST(req: NextRequest) {
       id: user.id,
       name: user.name,
       email: user.email,
-      role: user.role,
+      role: effectiveRole(user),
       departmentId: user.departmentId,
       departmentName: user.department?.name ?? null,
       avatar: user.avatar,
@@ -0,0 +1,34 @@
+import { NextResponse } from 'next/server'
+import { prisma } from '@/lib/db'
+import { withAuth, withValidation } from '@/lib/middleware/auth'
+import { mfaCodeSchema } from '@/lib/validations/auth'
+import { issueRecoveryCodes, verifySecondFactor } from '@/lib/auth/mfa'
+import { auditLog } from '@/lib/audit'
+
+// POST /api/v1/auth/mfa/recovery-codes — Replace the recovery codes (needs a current code)
+export const POST = withAuth(
+  withValidation(mfaCodeSchema, async (req, ctx) => {
+    const { user, body } = ctx as {
+      user: NonNullable<typeof ctx.user>
+      body: typeof ctx.body
+    }
+    const account = await prisma.user.findUniqueOrThrow({ where: { id: user.id } })
+    if (!account.mfaEnabled) {
+      return NextResponse.json({ error: 'MFA is not enabled' }, { status: 400 })
+    }
+    if (!(await verifySecondFactor(account, body.code))) {
+      return NextResponse.json({ error: 'Invalid code' }, { status: 403 })
+    }
+
+    const recoveryCodes = await issueRecoveryCodes(user.id)
+    auditLog({
+      userId: user.id,
+      action: 'MFA_RECOVERY_CODES_RESET',
+      resource: 'auth',
+      ipAddress: req.headers.get('x-forwarded-for') || 'unknown',
+      userAgent: req.headers.get('user-agent') || undefined,
+      result: 'SUCCESS',
+    })
+    return NextResponse.json({ recoveryCodes })
+  }),
+)
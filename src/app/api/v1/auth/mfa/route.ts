@@ -0,0 +1,63 @@
+import { NextResponse } from 'next/server'
+import { prisma } from '@/lib/db'
+import { withAuth, withValidation } from '@/lib/middleware/auth'
+import { mfaCodeSchema } from '@/lib/validations/auth'
+import { countRecoveryCodesLeft, resetMfa, verifySecondFactor } from '@/lib/auth/mfa'
+import { auditLog } from '@/lib/audit'
+
+// GET /api/v1/auth/mfa — The caller's MFA state
+export const GET = withAuth(async (_req, { user }) => {
+  const account = await prisma.user.findUniqueOrThrow({
+    where: { id: user.id },
+    select: { mfaEnabled: true, mfaRequired: true },
+  })
+  return NextResponse.json({
+    enabled: account.mfaEnabled,
+    required: account.mfaRequired,
+    recoveryCodesLeft: account.mfaEnabled ? await countRecoveryCodesLeft(user.id) : 0,
+  })
+})
+
+// DELETE /api/v1/auth/mfa — Turn MFA off (needs a current code)
+export const DELETE = withAuth(
+  withValidation(mfaCodeSchema, async (req, ctx) => {
+    const { user, body } = ctx as {
+      user: NonNullable<typeof ctx.user>
+      body: typeof ctx.body
+    }
+    const account = await prisma.user.findUniqueOrThrow({ where: { id: user.id } })
+    if (!account.mfaEnabled) {
+      return NextResponse.json({ error: 'MFA is not enabled' }, { status: 400 })
+    }
+    if (account.mfaRequired) {
+      return NextResponse.json({ error: 'MFA is required for this account' }, { status: 403 })
+    }
+
+    const ipAddress = req.headers.get('x-forwarded-for') || 'unknown'
+    const userAgent = req.headers.get('user-agent') || undefined
+
+    if (!(await verifySecondFactor(account, body.code))) {
+      auditLog({
+        userId: user.id,
+        action: 'MFA_DISABLE',
+        resource: 'auth',
+        details: { reason: 'invalid code' },
+        ipAddress,
+        userAgent,
+        result: 'DENIED',
+      })
+      return NextResponse.json({ error: 'Invalid code' }, { status: 403 })
+    }
+
+    await resetMfa(user.id)
+    auditLog({
+      userId: user.id,
+      action: 'MFA_DISABLE',
+      resource: 'auth',
+      ipAddress,
+      userAgent,
+      result: 'SUCCESS',
+    })
+    return NextResponse.json({ enabled: false })
+  }),
+)
@@ -0,0 +1,72 @@
+import { NextResponse } from 'next/server'
+import { prisma } from '@/lib/db'
+import { withAuth, withValidation } from '@/lib/middleware/auth'
+import { mfaCodeSchema } from '@/lib/validations/auth'
+import {
+  generateSecret,
+  issueRecoveryCodes,
+  matchTotp,
+  openSecret,
+  provisioningUri,
+  sealSecret,
+} from '@/lib/auth/mfa'
+import { auditLog } from '@/lib/audit'
+
+// POST /api/v1/auth/mfa/setup — Start enrolment: new secret + otpauth:// URI for the QR code
+export const POST = withAuth(async (_req, { user }) => {
+  const account = await prisma.user.findUniqueOrThrow({
+    where: { id: user.id },
+    select: { mfaEnabled: true },
+  })
+  if (account.mfaEnabled) {
+    return NextResponse.json({ error: 'MFA is already enabled' }, { status: 409 })
+  }
+
+  const secret = generateSecret()
+  await prisma.user.update({
+    where: { id: user.id },
+    data: { mfaSecret: sealSecret(secret), mfaLastStep: null },
+  })
+  return NextResponse.json({ secret, otpauthUri: provisioningUri(user.email, secret) })
+})
+
+// PUT /api/v1/auth/mfa/setup — Finish enrolment with a code from the app; returns the recovery codes once
+export const PUT = withAuth(
+  withValidation(mfaCodeSchema, async (req, ctx) => {
+    const { user, body } = ctx as {
+      user: NonNullable<typeof ctx.user>
+      body: typeof ctx.body
+    }
+    const account = await prisma.user.findUniqueOrThrow({
+      where: { id: user.id },
+      select: { mfaEnabled: true, mfaSecret: true },
+    })
+    if (account.mfaEnabled) {
+      return NextResponse.json({ error: 'MFA is already enabled' }, { status: 409 })
+    }
+    if (!account.mfaSecret) {
+      return NextResponse.json({ error: 'Start MFA setup first' }, { status: 400 })
+    }
+
+    const step = matchTotp(openSecret(account.mfaSecret), body.code)
+    if (step === null) {
+      return NextResponse.json({ error: 'Invalid code' }, { status: 400 })
+    }
+
+    await prisma.user.update({
+      where: { id: user.id },
+      data: { mfaEnabled: true, mfaLastStep: step },
+    })
+    const recoveryCodes = await issueRecoveryCodes(user.id)
+
+    auditLog({
+      userId: user.id,
+      action: 'MFA_ENABLE',
+      resource: 'auth',
+      ipAddress: req.headers.get('x-forwarded-for') || 'unknown',
+      userAgent: req.headers.get('user-agent') || undefined,
+      result: 'SUCCESS',
+    })
+    return NextResponse.json({ enabled: true, recoveryCodes })
+  }),
+)
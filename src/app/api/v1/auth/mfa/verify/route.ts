@@ -0,0 +1,162 @@
+import { NextRequest, NextResponse } from 'next/server'
+import { createHash } from 'crypto'
+import { prisma } from '@/lib/db'
+import { effectiveRole } from '@/lib/auth/permissions'
+import { signAccessToken, signRefreshToken, verifyMfaToken } from '@/lib/auth/jwt'
+import { verifySecondFactor, countRecoveryCodesLeft } from '@/lib/auth/mfa'
+import { mfaVerifySchema } from '@/lib/validations/auth'
+import {
+  checkRateLimit,
+  checkLoginLockout,
+  recordLoginFailure,
+  clearLoginFailures,
+} from '@/lib/redis'
+import { auditLog } from '@/lib/audit'
+
+function getClientIp(req: NextRequest): string {
+  return (
+    req.headers.get('x-forwarded-for')?.split(',')[0]?.trim() ||
+    req.headers.get('x-real-ip') ||
+    '127.0.0.1'
+  )
+}
+
+function isSecure(req: NextRequest): boolean {
+  return req.headers.get('x-forwarded-proto') === 'https'
+}
+
+// POST /api/v1/auth/mfa/verify — Second login step: MFA token + code → session cookies
+export async function POST(req: NextRequest) {
+  const ip = getClientIp(req)
+  const userAgent = req.headers.get('user-agent') || undefined
+
+  const rateResult = await checkRateLimit(`rate:${ip}:mfa`, 10, 60)
+  if (!rateResult.allowed) {
+    return NextResponse.json(
+      { error: 'Too many requests. Please try again later.' },
+      { status: 429 }
+    )
+  }
+
+  let body: unknown
+  try {
+    body = await req.json()
+  } catch {
+    return NextResponse.json({ error: 'Invalid JSON body' }, { status: 400 })
+  }
+
+  const parsed = mfaVerifySchema.safeParse(body)
+  if (!parsed.success) {
+    return NextResponse.json(
+      {
+        error: 'Validation failed',
+        details: parsed.error.issues.map((i) => ({
+          path: i.path.join('.'),
+          message: i.message,
+        })),
+      },
+      { status: 400 }
+    )
+  }
+
+  const token = await verifyMfaToken(parsed.data.mfaToken)
+  if (!token) {
+    return NextResponse.json({ error: 'MFA session expired, please sign in again', code: 'MFA_TOKEN_EXPIRED' }, { status: 401 })
+  }
+
+  const user = await prisma.user.findUnique({
+    where: { id: token.userId },
+    include: { department: true },
+  })
+  if (!user || user.status !== 'ACTIVE' || !user.mfaEnabled) {
+    return NextResponse.json({ error: 'MFA session expired, please sign in again', code: 'MFA_TOKEN_EXPIRED' }, { status: 401 })
+  }
+
+  // Wrong codes count towards the same lockout as wrong passwords
+  const lockout = await checkLoginLockout(user.email)
+  if (lockout.locked) {
+    return NextResponse.json(
+      { error: 'Account temporarily locked due to too many failed attempts. Try again later.' },
+      { status: 423 }
+    )
+  }
+
+  const method = await verifySecondFactor(user, parsed.data.code)
+  if (!method) {
+    await recordLoginFailure(user.email)
+    auditLog({
+      userId: user.id,
+      action: 'LOGIN',
+      resource: 'auth',
+      ipAddress: ip,
+      userAgent,
+      result: 'FAILURE',
+      details: { reason: 'Invalid MFA code' },
+    })
+    return NextResponse.json({ error: 'Invalid code' }, { status: 401 })
+  }
+
+  await clearLoginFailures(user.email)
+
+  const accessToken = await signAccessToken({
+    userId: user.id,
+    role: effectiveRole(user),
+  })
+  const refreshToken = await signRefreshToken(user.id)
+  const tokenHash = createHash('sha256').update(refreshToken).digest('hex')
+
+  await prisma.refreshToken.create({
+    data: {
+      userId: user.id,
+      tokenHash,
+      expiresAt: new Date(Date.now() + 7 * 24 * 60 * 60 * 1000),
+    },
+  })
+
+  await prisma.user.update({
+    where: { id: user.id },
+    data: { lastLoginAt: new Date() },
+  })
+
+  auditLog({
+    userId: user.id,
+    action: 'LOGIN',
+    resource: 'auth',
+    details: { mfa: method },
+    ipAddress: ip,
+    userAgent,
+    result: 'SUCCESS',
+  })
+
+  const response = NextResponse.json({
+    user: {
+      id: user.id,
+      name: user.name,
+      email: user.email,
+      role: effectiveRole(user),
+      departmentId: user.departmentId,
+      departmentName: user.department?.name ?? null,
+      avatar: user.avatar,
+    },
+    // Tell the user when they are running out of recovery codes
+    ...(method === 'recovery' ? { recoveryCodesLeft: await countRecoveryCodesLeft(user.id) } : {}),
+  })
+
+  response.cookies.set('access_token', accessToken, {
+    httpOnly: true,
+    secure: isSecure(req),
+    sameSite: 'lax',
+    maxAge: 10800, // 180 minutes
+    path: '/',
+  })
+
+  response.cookies.set('refresh_token', refreshToken, {
+    httpOnly: true,
+    secure: isSecure(req),
+    sameSite: 'lax',
+    maxAge: 604800, // 7 days
+    path: '/api/v1/auth',
+  })
+
+  return response
+}
@@ -0,0 +1,158 @@
+import { NextRequest, NextResponse } from 'next/server'
+import { createHash } from 'crypto'
+import { prisma } from '@/lib/db'
+import { effectiveRole } from '@/lib/auth/permissions'
+import { signAccessToken, signMfaToken, signRefreshToken } from '@/lib/auth/jwt'
+import {
+  getOidcConfig,
+  completeLogin,
+  decodeLoginState,
+  resolveOidcUser,
+  OIDC_STATE_COOKIE,
+} from '@/lib/auth/oidc'
+import { auditLog } from '@/lib/audit'
+import { createLogger } from '@/lib/logger'
+
+const log = createLogger('auth:oidc')
+
+function getClientIp(req: NextRequest): string {
+  return (
+    req.headers.get('x-forwarded-for')?.split(',')[0]?.trim() ||
+    req.headers.get('x-real-ip') ||
+    '127.0.0.1'
+  )
+}
+
+function isSecure(req: NextRequest): boolean {
+  return req.headers.get('x-forwarded-proto') === 'https'
+}
+
+/** Back to the login page with an error code it can show */
+function loginError(req: NextRequest, code: string): NextResponse {
+  const response = NextResponse.redirect(new URL(`/login?sso_error=${code}`, req.url))
+  response.cookies.delete({ name: OIDC_STATE_COOKIE, path: '/api/v1/auth/oidc' })
+  return response
+}
+
+// GET /api/v1/auth/oidc/callback — Provider redirect target: sign the user in
+export async function GET(req: NextRequest) {
+  const ip = getClientIp(req)
+  const userAgent = req.headers.get('user-agent') || undefined
+
+  const config = getOidcConfig()
+  if (!config) {
+    return NextResponse.json({ error: 'SSO is not configured' }, { status: 404 })
+  }
+
+  const params = req.nextUrl.searchParams
+  const loginState = decodeLoginState(req.cookies.get(OIDC_STATE_COOKIE)?.value)
+  if (params.get('error')) return loginError(req, 'provider')
+  const code = params.get('code')
+  if (!code || !loginState || params.get('state') !== loginState.state) {
+    return loginError(req, 'state')
+  }
+
+  let resolved: Awaited<ReturnType<typeof resolveOidcUser>>
+  try {
+    const identity = await completeLogin(config, code, loginState)
+    resolved = await resolveOidcUser(config, identity)
+  } catch (err) {
+    log.warn('SSO login failed', { error: (err as Error).message })
+    return loginError(req, 'failed')
+  }
+
+  if ('error' in resolved) {
+    log.warn('SSO login refused', { reason: resolved.error })
+    return loginError(req, 'no_account')
+  }
+
+  const { user, created, linked } = resolved
+  if (user.status !== 'ACTIVE') {
+    auditLog({
+      userId: user.id,
+      action: 'LOGIN',
+      resource: 'auth',
+      ipAddress: ip,
+      userAgent,
+      result: 'FAILURE',
+      details: { method: 'oidc', reason: 'Account not active' },
+    })
+    return loginError(req, 'disabled')
+  }
+
+  // Second factor as for password login: the login page finishes through
+  // /auth/mfa/verify. The token goes in the fragment, which is never sent to
+  // a server, so it stays out of access logs
+  if (user.mfaEnabled) {
+    const fragment = new URLSearchParams({
+      mfa_token: await signMfaToken(user.id),
+      return_to: loginState.returnTo,
+    })
+    const response = NextResponse.redirect(new URL(`/login#${fragment}`, req.url))
+    response.cookies.delete({ name: OIDC_STATE_COOKIE, path: '/api/v1/auth/oidc' })
+    return response
+  }
+
+  const accessToken = await signAccessToken({
+    userId: user.id,
+    role: effectiveRole(user),
+  })
+  const refreshToken = await signRefreshToken(user.id)
+  const tokenHash = createHash('sha256').update(refreshToken).digest('hex')
+
+  await prisma.refreshToken.create({
+    data: {
+      userId: user.id,
+      tokenHash,
+      expiresAt: new Date(Date.now() + 7 * 24 * 60 * 60 * 1000),
+    },
+  })
+
+  await prisma.user.update({
+    where: { id: user.id },
+    data: { lastLoginAt: new Date() },
+  })
+
+  if (created) {
+    auditLog({
+      userId: user.id,
+      action: 'REGISTER',
+      resource: 'auth',
+      resourceId: user.id,
+      details: { method: 'oidc', role: user.role },
+      ipAddress: ip,
+      userAgent,
+      result: 'SUCCESS',
+    })
+  }
+  auditLog({
+    userId: user.id,
+    action: 'LOGIN',
+    resource: 'auth',
+    details: { method: 'oidc', linked },
+    ipAddress: ip,
+    userAgent,
+    result: 'SUCCESS',
+  })
+
+  const response = NextResponse.redirect(new URL(loginState.returnTo, req.url))
+  response.cookies.delete({ name: OIDC_STATE_COOKIE, path: '/api/v1/auth/oidc' })
+
+  response.cookies.set('access_token', accessToken, {
+    httpOnly: true,
+    secure: isSecure(req),
+    sameSite: 'lax',
+    maxAge: 10800, // 180 minutes
+    path: '/',
+  })
+
+  response.cookies.set('refresh_token', refreshToken, {
+    httpOnly: true,
+    secure: isSecure(req),
+    sameSite: 'lax',
+    maxAge: 604800, // 7 days
+    path: '/api/v1/auth',
+  })
+
+  return response
+}
@@ -0,0 +1,50 @@
+import { NextRequest, NextResponse } from 'next/server'
+import {
+  getOidcConfig,
+  buildAuthorizationUrl,
+  encodeLoginState,
+  safeReturnTo,
+  OIDC_STATE_COOKIE,
+} from '@/lib/auth/oidc'
+import { checkRateLimit } from '@/lib/redis'
+
+function getClientIp(req: NextRequest): string {
+  return (
+    req.headers.get('x-forwarded-for')?.split(',')[0]?.trim() ||
+    req.headers.get('x-real-ip') ||
+    '127.0.0.1'
+  )
+}
+
+// GET /api/v1/auth/oidc/login?returnTo=/chat — Redirect to the SSO provider
+export async function GET(req: NextRequest) {
+  const config = getOidcConfig()
+  if (!config) {
+    return NextResponse.json({ error: 'SSO is not configured' }, { status: 404 })
+  }
+
+  const rateResult = await checkRateLimit(`rate:${getClientIp(req)}:oidc`, 20, 60)
+  if (!rateResult.allowed) {
+    return NextResponse.json({ error: 'Too many requests. Please try again later.' }, { status: 429 })
+  }
+
+  const redirectUri = config.redirectUri ?? new URL('/api/v1/auth/oidc/callback', req.nextUrl.origin).toString()
+  const returnTo = safeReturnTo(req.nextUrl.searchParams.get('returnTo'))
+
+  let authorization: Awaited<ReturnType<typeof buildAuthorizationUrl>>
+  try {
+    authorization = await buildAuthorizationUrl(config, redirectUri, returnTo)
+  } catch (err) {
+    return NextResponse.json({ error: (err as Error).message }, { status: 502 })
+  }
+
+  const response = NextResponse.redirect(authorization.url)
+  response.cookies.set(OIDC_STATE_COOKIE, encodeLoginState(authorization.loginState), {
+    httpOnly: true,
+    secure: req.headers.get('x-forwarded-proto') === 'https',
+    sameSite: 'lax', // sent on the provider's top-level redirect back
+    maxAge: 600,
+    path: '/api/v1/auth/oidc',
+  })
+  return response
+}
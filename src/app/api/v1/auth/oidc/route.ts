@@ -0,0 +1,8 @@
+import { NextResponse } from 'next/server'
+import { getOidcConfig } from '@/lib/auth/oidc'
+
+// GET /api/v1/auth/oidc — Public: whether the login page offers SSO
+export async function GET() {
+  const config = getOidcConfig()
+  return NextResponse.json({ enabled: !!config, providerName: config?.providerName ?? null })
+}
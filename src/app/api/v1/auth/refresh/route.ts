@@ -1,6 +1,7 @@
 import { NextRequest, NextResponse } from 'next/server'
 import { createHash } from 'crypto'
 import { prisma } from '@/lib/db'
+import { effectiveRole } from '@/lib/auth/permissions'
 import {
   signAccessToken,
   signRefreshToken,
@@ -78,7 +79,7 @@ export async function POST(req: NextRequest) {
 
   const newAccessToken = await signAccessToken({
     userId: user.id,
-    role: user.role,
+    role: effectiveRole(user),
   })
   const newRefreshToken = await signRefreshToken(user.id)
   const newTokenHash = createHash('sha256')
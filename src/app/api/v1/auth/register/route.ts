@@ -6,6 +6,7 @@ import { hashPassword } from '@/lib/auth/password'
 import { registerSchema } from '@/lib/validations/auth'
 import { checkRateLimit } from '@/lib/redis'
 import { auditLog } from '@/lib/audit'
+import { enforceLicenseLimit } from '@/lib/license'
 
 function getClientIp(req: NextRequest): string {
   return (
@@ -73,6 +74,9 @@ export async function POST(req: NextRequest) {
     )
   }
 
+  const blocked = await enforceLicenseLimit('seat')
+  if (blocked) return blocked
+
   // Create user
   const passwordHash = await hashPassword(password)
   const user = await prisma.user.create({
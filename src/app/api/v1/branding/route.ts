@@ -0,0 +1,11 @@
+import { NextRequest, NextResponse } from 'next/server'
+import { getBranding } from '@/lib/branding'
+
+// GET /api/v1/branding — Public: the login page needs it before authentication.
+// Updates live under /api/v1/settings/branding, outside the public prefix.
+export async function GET(_req: NextRequest) {
+  const branding = await getBranding()
+  return NextResponse.json(branding, {
+    headers: { 'Cache-Control': 'public, max-age=60' },
+  })
+}
@@ -0,0 +1,20 @@
+import { NextResponse } from 'next/server'
+import { withAuth, withPermission } from '@/lib/middleware/auth'
+import { canAccessInstance } from '@/lib/instances/access'
+import { activeAnnouncements, toChatAnnouncement } from '@/lib/instances/announcements'
+
+// GET /api/v1/chat/announcements?instanceId= — Notices in effect for an instance the user chats with
+export const GET = withAuth(
+  withPermission('chat:use', async (req, ctx) => {
+    const instanceId = req.nextUrl.searchParams.get('instanceId')
+    if (!instanceId) {
+      return NextResponse.json({ error: 'instanceId is required' }, { status: 400 })
+    }
+    if (!(await canAccessInstance(ctx.user, instanceId))) {
+      return NextResponse.json({ announcements: [] })
+    }
+
+    const announcements = await activeAnnouncements(instanceId)
+    return NextResponse.json({ announcements: announcements.map(toChatAnnouncement) })
+  }),
+)
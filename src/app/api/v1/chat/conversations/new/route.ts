@@ -1,10 +1,11 @@
 import { NextResponse } from 'next/server'
 import { z } from 'zod'
 import { prisma } from '@/lib/db'
-import { Prisma } from '@/generated/prisma'
 import { withAuth, withPermission } from '@/lib/middleware/auth'
 import { registry, ensureRegistryInitialized } from '@/lib/gateway/registry'
-import { archiveSession } from '@/lib/chat/snapshot-helpers'
+import { archiveSession, archiveStoredMessages } from '@/lib/chat/snapshot-helpers'
+import { findUserInstanceAccess, grantAllowsAgent } from '@/lib/instances/access'
+import { assignCanaryArm, chatSessionKey } from '@/lib/agents/canary'
 
 const bodySchema = z.object({
   instanceId: z.string().min(1),
@@ -33,22 +34,11 @@ export const POST = withAuth(
 
     // Permission check
     if (user.role !== 'SYSTEM_ADMIN') {
-      if (!user.departmentId) {
-        return NextResponse.json({ error: 'No access to this agent' }, { status: 403 })
-      }
-      const access = await prisma.instanceAccess.findUnique({
-        where: {
-          departmentId_instanceId: {
-            departmentId: user.departmentId,
-            instanceId,
-          },
-        },
-      })
+      const access = await findUserInstanceAccess(user, instanceId)
       if (!access) {
         return NextResponse.json({ error: 'No access to this instance' }, { status: 403 })
       }
-      const allowedIds = access.agentIds as string[] | null
-      if (allowedIds && !allowedIds.includes(agentId)) {
+      if (!grantAllowsAgent(access, agentId)) {
         return NextResponse.json({ error: 'No access to this agent' }, { status: 403 })
       }
     }
@@ -66,23 +56,25 @@ export const POST = withAuth(
       if (client) {
         await archiveSession(activeSession.id, instanceId, agentId, user.id, client)
       } else {
-        // No client — just mark inactive + clear liveMessages
+        // No client — archive what was stored as it streamed, then mark inactive
+        await archiveStoredMessages(activeSession.id)
         await prisma.chatSession.update({
           where: { id: activeSession.id },
-          data: { isActive: false, liveMessages: Prisma.DbNull },
+          data: { isActive: false },
         })
       }
     }
 
-    // Create new active session
-    const sessionKey = `agent:${agentId}:tc:${user.id}`
+    // Create new active session, on the canary agent if a split routes it there
+    const canary = await assignCanaryArm(user.departmentId, instanceId, agentId)
     const newSession = await prisma.chatSession.create({
       data: {
         userId: user.id,
         instanceId,
         agentId,
-        sessionId: sessionKey,
+        sessionId: chatSessionKey(user.id, agentId, canary?.routedAgentId),
         isActive: true,
+        ...canary,
       },
       include: { instance: { select: { name: true } } },
     })
@@ -98,6 +90,7 @@ export const POST = withAuth(
         lastMessageAt: null,
         messageCount: 0,
         isActive: true,
+        closedReason: null,
         createdAt: newSession.createdAt.toISOString(),
       },
     })
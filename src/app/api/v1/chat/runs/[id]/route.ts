@@ -0,0 +1,27 @@
+import { NextResponse } from 'next/server'
+import { prisma } from '@/lib/db'
+import { withAuth, withPermission, param } from '@/lib/middleware/auth'
+import { registry, ensureRegistryInitialized } from '@/lib/gateway/registry'
+
+// DELETE /api/v1/chat/runs/[id] — Stop a running chat run. Closing the stream
+// alone does not: the run keeps going so the client can re-attach.
+export const DELETE = withAuth(
+  withPermission('chat:use', async (_req, ctx) => {
+    const id = param(ctx, 'id')
+
+    const run = await prisma.chatRun.findUnique({ where: { id } })
+    if (!run || run.userId !== ctx.user.id) {
+      return NextResponse.json({ error: 'Run not found or already finished' }, { status: 404 })
+    }
+
+    await ensureRegistryInitialized()
+    const client = registry.getClient(run.instanceId)
+    if (!client) {
+      return NextResponse.json({ error: 'Instance not connected' }, { status: 502 })
+    }
+
+    // The run's stream reports the abort and ends
+    await client.request('chat.abort', { sessionKey: run.sessionKey, runId: run.id })
+    return NextResponse.json({ ok: true })
+  }),
+)
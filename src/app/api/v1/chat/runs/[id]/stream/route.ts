@@ -0,0 +1,42 @@
+import { NextResponse } from 'next/server'
+import { withAuth, withPermission, param } from '@/lib/middleware/auth'
+import { getRunOwner, followRun } from '@/lib/chat/run-journal'
+import { createSseWriter } from '@/lib/chat/stream-guard'
+
+// GET /api/v1/chat/runs/[id]/stream?after=<event id> — Re-attach to a chat
+// run whose stream broke: the journaled events after `after`, then the rest
+// live until the run ends. Same SSE format as POST /api/v1/chat/send.
+// `from` is accepted for `after`, and without either the Last-Event-ID
+// header an EventSource sends on reconnect is used.
+export const GET = withAuth(
+  withPermission('chat:use', async (req, ctx) => {
+    const id = param(ctx, 'id')
+
+    const owner = await getRunOwner(id)
+    if (!owner || owner !== ctx.user.id) {
+      return NextResponse.json({ error: 'Run not found or expired' }, { status: 404 })
+    }
+
+    const query = req.nextUrl.searchParams
+    const cursor = query.get('after') ?? query.get('from') ?? req.headers.get('last-event-id')
+    const after = Math.max(0, parseInt(cursor ?? '', 10) || 0)
+
+    const { readable, writable } = new TransformStream<Uint8Array, Uint8Array>()
+    const sse = createSseWriter(writable, () => stop())
+    const stop = followRun(
+      id,
+      after,
+      ({ id: eventId, event }) => sse.write(event, eventId),
+      () => sse.close(),
+    )
+    req.signal.addEventListener('abort', () => stop(), { once: true })
+
+    return new NextResponse(readable, {
+      headers: {
+        'Content-Type': 'text/event-stream',
+        'Cache-Control': 'no-cache',
+        Connection: 'keep-alive',
+      },
+    })
+  }),
+)
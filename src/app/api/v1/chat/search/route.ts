@@ -0,0 +1,25 @@
+import { NextResponse } from 'next/server'
+import { withAuth, withPermission } from '@/lib/middleware/auth'
+import { searchChatHistory, MIN_QUERY_LENGTH, MAX_QUERY_LENGTH } from '@/lib/chat/search'
+import type { ChatSearchResponse } from '@/types/chat'
+
+// GET /api/v1/chat/search?q= — Full-text search over the current user's chat history
+export const GET = withAuth(
+  withPermission('chat:use', async (req, { user }) => {
+    const url = new URL(req.url)
+    const q = (url.searchParams.get('q') || '').trim()
+    if (q.length < MIN_QUERY_LENGTH || q.length > MAX_QUERY_LENGTH) {
+      return NextResponse.json(
+        { error: `Query must be ${MIN_QUERY_LENGTH} to ${MAX_QUERY_LENGTH} characters` },
+        { status: 400 },
+      )
+    }
+
+    const page = Math.max(1, parseInt(url.searchParams.get('page') || '1'))
+    const pageSize = Math.min(50, Math.max(1, parseInt(url.searchParams.get('pageSize') || '20')))
+
+    const { results, total } = await searchChatHistory(user.id, q, page, pageSize)
+    const response: ChatSearchResponse = { results, total, page, pageSize }
+    return NextResponse.json(response)
+  }),
+)
@@ -3,19 +3,46 @@ import { extname } from 'path'
 import { NextRequest, NextResponse } from 'next/server'
 import { prisma } from '@/lib/db'
 import { registry, ensureRegistryInitialized } from '@/lib/gateway/registry'
+import { fetchChatHistory } from '@/lib/gateway/history'
 import { sendMessageSchema } from '@/lib/validations/chat'
 import { verifyAccessToken } from '@/lib/auth/jwt'
+import { effectiveRole } from '@/lib/auth/permissions'
 import { dockerManager } from '@/lib/docker/manager'
 import { buildSessionInputPath, buildSessionOutputPath, buildCurrentSessionLinkPath, buildCurrentSessionTarget } from '@/lib/session-files/helpers'
-import { archiveSession, saveLiveSnapshot, extractContentBlocks } from '@/lib/chat/snapshot-helpers'
+import {
+  archiveSession,
+  archiveStoredMessages,
+  saveLiveSnapshot,
+  extractContentBlocks,
+  stripFinalTags,
+  wrapWelcomeContext,
+  wrapPreamble,
+} from '@/lib/chat/snapshot-helpers'
+import { recordLiveMessage } from '@/lib/chat/live-messages'
+import { generateSessionTitle } from '@/lib/chat/titles'
 import { MIME_BY_EXT, extractMediaPaths, extractFileProtocolPaths, readImageAsDataUrl } from '@/lib/chat/image-helpers'
+import { findUserInstanceAccess, grantAllowsAgent } from '@/lib/instances/access'
+import { findResidencyViolation, residencyErrorResponse } from '@/lib/instances/residency'
+import { createSseWriter, guardRun } from '@/lib/chat/stream-guard'
+import { createRunJournal } from '@/lib/chat/run-journal'
+import { acquireChatSlot, ChatQueueTimeoutError, type ChatSlot } from '@/lib/chat/concurrency'
+import { acquireSessionLock, SessionBusyError, type SessionLock } from '@/lib/chat/session-lock'
+import { loadEgressPolicy, checkToolCall, describeViolation, recordEgressViolation } from '@/lib/egress-policy'
+import { requiresApproval, requestToolApproval, GATEWAY_APPROVAL_TOOL, type ToolApproval } from '@/lib/chat/tool-approvals'
+import { auditLog } from '@/lib/audit'
+import { withTracing } from '@/lib/middleware/tracing'
+import { mfaSetupResponse } from '@/lib/middleware/auth'
+import { runInSpan, startSpan } from '@/lib/tracing'
+import { createToolRecorder } from '@/lib/chat/tool-invocations'
+import { createToolProgressThrottle, partialOutputText } from '@/lib/chat/tool-progress'
+import { sendWithRetry } from '@/lib/chat/send-retry'
+import { getToolOutputRedactor } from '@/lib/chat/redaction'
+import { onAnnouncement } from '@/lib/instances/announcements'
+import { buildPreamble } from '@/lib/agents/preamble'
+import { FileRejectedError, readStoredFile } from '@/lib/files'
+import { assignCanaryArm, chatSessionKey, recordCanaryRun, type CanaryRunOutcome } from '@/lib/agents/canary'
 import type { ChatStreamEvent, ChatContentBlock } from '@/types/chat'
-import type { ChatHistoryResult, ChatHistoryMessage } from '@/types/gateway'
-import { Prisma } from '@/generated/prisma'
-
-function encodeSSE(event: ChatStreamEvent): string {
-  return `data: ${JSON.stringify(event)}\n\n`
-}
+import type { ChatHistoryMessage } from '@/types/gateway'
 
 function extractTextFromMessage(message: unknown): string {
   if (!message || typeof message !== 'object') return ''
@@ -93,7 +120,6 @@ async function switchActiveSession(
   instanceId: string,
   agentId: string,
   targetSessionId: string,
-  sessionKey: string,
 ) {
   const activeSession = await prisma.chatSession.findFirst({
     where: { userId, instanceId, agentId, isActive: true },
@@ -106,9 +132,10 @@ async function switchActiveSession(
     if (client) {
       await archiveSession(activeSession.id, instanceId, agentId, userId, client)
     } else {
+      await archiveStoredMessages(activeSession.id)
       await prisma.chatSession.update({
         where: { id: activeSession.id },
-        data: { isActive: false, liveMessages: Prisma.DbNull },
+        data: { isActive: false },
       })
     }
   }
@@ -116,12 +143,76 @@ async function switchActiveSession(
   // Activate target session
   await prisma.chatSession.update({
     where: { id: targetSessionId },
-    data: { isActive: true },
+    data: { isActive: true, closedReason: null, closedAt: null },
+  })
+}
+
+/**
+ * Make the session the message goes to the active one (switching from an
+ * archived one if targeted), creating it when there is none.
+ */
+async function openChatSession(
+  user: { id: string; departmentId: string | null },
+  instanceId: string,
+  agentId: string,
+  targetSessionId: string | undefined,
+) {
+  // --- Handle session switching if targeting a specific (possibly inactive) session ---
+  if (targetSessionId) {
+    const targetSession = await prisma.chatSession.findUnique({
+      where: { id: targetSessionId },
+    })
+    if (
+      targetSession &&
+      targetSession.userId === user.id &&
+      targetSession.instanceId === instanceId &&
+      targetSession.agentId === agentId &&
+      !targetSession.isActive
+    ) {
+      await switchActiveSession(user.id, instanceId, agentId, targetSessionId)
+    }
+  }
+
+  // A user with no sessions at all is starting their first conversation
+  const isFirstChat = (await prisma.chatSession.count({ where: { userId: user.id } })) === 0
+
+  // A new session may be routed to a canary agent; only used if one is created
+  const canary = await assignCanaryArm(user.departmentId, instanceId, agentId)
+
+  // --- Find or create ChatSession (atomic to prevent race conditions) ---
+  const session = await prisma.$transaction(async (tx) => {
+    const existing = await tx.chatSession.findFirst({
+      where: { userId: user.id, instanceId, agentId, isActive: true },
+    })
+    if (existing) {
+      await tx.chatSession.update({
+        where: { id: existing.id },
+        data: {
+          sessionId: chatSessionKey(user.id, agentId, existing.routedAgentId),
+          lastMessageAt: new Date(),
+          messageCount: { increment: 1 },
+        },
+      })
+      return existing
+    }
+    return tx.chatSession.create({
+      data: {
+        userId: user.id,
+        instanceId,
+        agentId,
+        sessionId: chatSessionKey(user.id, agentId, canary?.routedAgentId),
+        lastMessageAt: new Date(),
+        messageCount: 1,
+        isActive: true,
+        ...canary,
+      },
+    })
   })
+  return { session, isFirstChat }
 }
 
 // POST /api/v1/chat/send — SSE streaming endpoint
-export async function POST(req: NextRequest) {
+export const POST = withTracing(async (req: NextRequest) => {
   // --- Auth (inline, because SSE needs the stream setup before returning) ---
   let userId = req.headers.get('x-user-id')
 
@@ -144,14 +235,31 @@ export async function POST(req: NextRequest) {
 
   const user = await prisma.user.findUnique({
     where: { id: userId },
-    select: { id: true, role: true, departmentId: true, status: true },
+    select: {
+      id: true,
+      name: true,
+      email: true,
+      role: true,
+      roleExpiresAt: true,
+      baseRole: true,
+      departmentId: true,
+      status: true,
+      mfaRequired: true,
+      mfaEnabled: true,
+      department: { select: { name: true, description: true, chatPriority: true, welcomePrompt: true } },
+    },
   })
 
   if (!user || user.status !== 'ACTIVE') {
     return NextResponse.json({ error: 'User not found or disabled' }, { status: 401 })
   }
 
-  const userRole = user.role // Always use DB role, never trust header
+  const mfaBlocked = mfaSetupResponse(user, req)
+  if (mfaBlocked) return mfaBlocked
+
+  // Always use the DB role, never trust the header; an expired temporary
+  // role no longer counts even before the expiry job reverts it
+  const userRole = effectiveRole(user)
 
   // --- Validate body ---
   let body: unknown
@@ -173,19 +281,8 @@ export async function POST(req: NextRequest) {
 
   // --- Permission check ---
   if (userRole !== 'SYSTEM_ADMIN') {
-    if (!user.departmentId) {
-      return NextResponse.json({ error: 'No access to this agent' }, { status: 403 })
-    }
-
-    // Layer 1: Instance access (department-level)
-    const access = await prisma.instanceAccess.findUnique({
-      where: {
-        departmentId_instanceId: {
-          departmentId: user.departmentId,
-          instanceId,
-        },
-      },
-    })
+    // Layer 1: Instance access (the department's grant or the user's own)
+    const access = await findUserInstanceAccess(user, instanceId)
 
     if (!access) {
       return NextResponse.json({ error: 'No access to this instance' }, { status: 403 })
@@ -198,19 +295,49 @@ export async function POST(req: NextRequest) {
 
     if (agentMeta) {
       const { isAgentVisible } = await import('@/lib/agents/helpers')
-      const authUser = { id: user.id, role: userRole ?? user.role, departmentId: user.departmentId, name: '', email: '', departmentName: null, avatar: null }
+      const authUser = { id: user.id, role: userRole, departmentId: user.departmentId, name: '', email: '', departmentName: null, avatar: null }
       if (!isAgentVisible(agentMeta, authUser)) {
         return NextResponse.json({ error: 'No access to this agent' }, { status: 403 })
       }
     } else {
-      // Fallback: legacy agentIds check from InstanceAccess
-      const allowedIds = access.agentIds as string[] | null
-      if (allowedIds && !allowedIds.includes(agentId)) {
+      // Fallback: legacy agentIds check from the grants
+      if (!grantAllowsAgent(access, agentId)) {
         return NextResponse.json({ error: 'No access to this agent' }, { status: 403 })
       }
     }
   }
 
+  // Data residency: the department's messages only go to instances in its
+  // allowed regions (applies to admins' own departments too)
+  if (user.departmentId) {
+    const violation = await findResidencyViolation(user.departmentId, [instanceId])
+    if (violation) return residencyErrorResponse(violation)
+  }
+
+  // --- Attachments: uploaded files (POST /api/v1/files) or inline base64 ---
+  let messageAttachments: { fileName: string; mimeType: string; content: string }[]
+  try {
+    messageAttachments = await Promise.all(
+      (attachments ?? []).map(async (a) => {
+        if (!('fileId' in a)) return { fileName: a.name, mimeType: a.mimeType, content: a.content }
+        const file = await prisma.storedFile.findUnique({ where: { id: a.fileId } })
+        if (!file || file.userId !== user.id) throw new FileRejectedError('Attachment not found')
+        return { fileName: file.name, mimeType: file.mimeType, content: (await readStoredFile(file)).toString('base64') }
+      }),
+    )
+  } catch (err) {
+    const notFound = err instanceof FileRejectedError
+    return NextResponse.json(
+      { error: notFound ? err.message : 'Could not read attachment' },
+      { status: notFound ? 400 : 502 },
+    )
+  }
+
+  // Department egress policy, checked against each tool call of the run
+  const egressPolicy = user.departmentId ? await loadEgressPolicy(user.departmentId) : null
+  // Tool outputs are scrubbed before they are streamed or recorded
+  const redactToolOutput = getToolOutputRedactor(user.departmentId)
+
   // --- Ensure registry ---
   await ensureRegistryInitialized()
 
@@ -220,88 +347,128 @@ export async function POST(req: NextRequest) {
     return NextResponse.json({ error: 'Instance not connected' }, { status: 502 })
   }
 
-  // --- Build session key ---
-  const sessionKey = `agent:${agentId}:tc:${user.id}`
   const idempotencyKey = randomUUID()
 
-  // --- Handle session switching if targeting a specific (possibly inactive) session ---
-  if (targetSessionId) {
-    const targetSession = await prisma.chatSession.findUnique({
-      where: { id: targetSessionId },
-    })
-    if (
-      targetSession &&
-      targetSession.userId === user.id &&
-      targetSession.instanceId === instanceId &&
-      targetSession.agentId === agentId &&
-      !targetSession.isActive
-    ) {
-      await switchActiveSession(user.id, instanceId, agentId, targetSessionId, sessionKey)
-    }
+  // --- One run at a time per session (a second tab gets 409, or waits in queue mode) ---
+  let sessionLock: SessionLock
+  try {
+    sessionLock = await acquireSessionLock({ userId: user.id, instanceId, agentId }, idempotencyKey, req.signal)
+  } catch (err) {
+    if (!(err instanceof SessionBusyError)) throw err
+    return NextResponse.json({ error: err.message, runId: err.runId }, { status: 409 })
   }
 
-  // --- Find or create ChatSession (atomic to prevent race conditions) ---
-  const session = await prisma.$transaction(async (tx) => {
-    const existing = await tx.chatSession.findFirst({
-      where: { userId: user.id, instanceId, agentId, isActive: true },
-    })
-    if (existing) {
-      await tx.chatSession.update({
-        where: { id: existing.id },
-        data: {
-          sessionId: sessionKey,
-          lastMessageAt: new Date(),
-          messageCount: { increment: 1 },
-        },
-      })
-      return existing
-    }
-    return tx.chatSession.create({
-      data: {
-        userId: user.id,
-        instanceId,
-        agentId,
-        sessionId: sessionKey,
-        lastMessageAt: new Date(),
-        messageCount: 1,
-        isActive: true,
-      },
-    })
-  })
+  // Nothing else gives the lock back until the stream's cleanup() exists
+  let opened: Awaited<ReturnType<typeof openChatSession>>
+  try {
+    opened = await openChatSession(user, instanceId, agentId, targetSessionId)
+  } catch (err) {
+    sessionLock.release()
+    throw err
+  }
+  const { session, isFirstChat } = opened
   const existingSession = session
   const chatSessionId = session.id
 
+  // --- Build session key (the canary agent's, when the session was routed to it) ---
+  const sessionKey = chatSessionKey(user.id, agentId, session.routedAgentId)
+
+  // Transcript of the tool calls this run makes
+  const tools = createToolRecorder({
+    chatSessionId,
+    runId: idempotencyKey,
+    userId: user.id,
+    departmentId: user.departmentId,
+    instanceId,
+    agentId,
+  })
+
+  // Events are journaled so the client can re-attach if its stream breaks
+  const journal = createRunJournal({
+    runId: idempotencyKey,
+    chatSessionId,
+    userId: user.id,
+    instanceId,
+    agentId,
+    sessionKey,
+  })
+
+  // Tracing: the run outlives the HTTP span, which ends once streaming starts;
+  // gateway requests made for it are its children
+  const runSpan = startSpan('chat.run', {
+    attributes: {
+      'teamclaw.run_id': idempotencyKey,
+      'teamclaw.chat_session_id': chatSessionId,
+      'teamclaw.instance_id': instanceId,
+      'teamclaw.agent_id': agentId,
+    },
+  })
+
   // --- SSE Stream ---
-  const { readable, writable } = new TransformStream()
-  const writer = writable.getWriter()
-  const encoder = new TextEncoder()
+  const { readable, writable } = new TransformStream<Uint8Array, Uint8Array>()
+  // A client that disconnects mid-run can re-attach, so the run keeps being
+  // followed (and journaled) until it ends
+  const sse = createSseWriter(writable, () => {})
+  let ended = false
+
+  // Run measurements for sessions under a canary split
+  let sentAt: number | null = null
+  let firstTextAt: number | null = null
+  let outcome: CanaryRunOutcome | null = null
 
-  let closed = false
   let lastTextContent = ''
   let lastThinkingContent = ''
   let lastImageCount = 0
   const pendingImageReads: Promise<void>[] = []
 
+  // While a tool approval is pending, events are held back (errors excepted)
+  // and either flushed on approval or dropped on denial
+  let holds = 0
+  const held: ChatStreamEvent[] = []
+  const approvals = new Set<ToolApproval>()
+  let approvalQueue: Promise<void> = Promise.resolve()
+  let gatewayApproved = 0
+
+  function emit(event: ChatStreamEvent) {
+    if (event.type === 'text' && firstTextAt === null) firstTextAt = Date.now()
+    if (event.type === 'done' || event.type === 'aborted' || event.type === 'error') outcome ??= event.type
+    if (event.type === 'error') runSpan.setError(event.error)
+    sse.write(event, journal.append(event))
+  }
+
   function write(event: ChatStreamEvent) {
-    if (closed) return
-    writer.write(encoder.encode(encodeSSE(event))).catch(() => {
-      closed = true
-    })
+    if (holds > 0 && event.type !== 'error') {
+      held.push(event)
+      return
+    }
+    emit(event)
   }
 
+  // Partial output of running tools, throttled per call
+  const toolProgress = createToolProgressThrottle(write)
+
   // Send session ID as the first event so the frontend can track this session
-  write({ type: 'session', sessionId: chatSessionId })
+  write({ type: 'session', sessionId: chatSessionId, runId: idempotencyKey })
 
   async function close() {
-    if (closed) return
+    if (sse.closed) return
     // Wait for any pending image reads to complete before closing
     if (pendingImageReads.length > 0) {
       await Promise.allSettled(pendingImageReads)
     }
-    closed = true
-    writer.close().catch(() => {})
+    sse.close()
   }
 
+  // Force-complete runs the gateway never finishes (or goes quiet on)
+  const runGuard = guardRun((reason) => {
+    write({
+      type: 'error',
+      error: reason === 'deadline' ? 'Agent run exceeded the time limit' : 'Agent stopped responding',
+    })
+    client!.request('chat.abort', { sessionKey, runId: idempotencyKey }).catch(() => {})
+    cleanup()
+  })
+
   /**
    * After streaming ends, fetch chat.history to find generated images.
    * Gateway doesn't emit tool agent events, so images in tool results
@@ -316,11 +483,7 @@ export async function POST(req: NextRequest) {
 
     // 2. Fetch chat.history and scan tool results for MEDIA: paths
     try {
-      const rawResult = await client!.request('chat.history', {
-        sessionKey,
-        limit: 50,
-      }, 10_000) // 10s timeout for history fetch
-      const historyResult = rawResult as ChatHistoryResult
+      const historyResult = await fetchChatHistory(client!, sessionKey, 50, 10_000)
       const messages = historyResult.messages ?? []
 
       // Scan only the last few messages (this run's output)
@@ -362,11 +525,20 @@ export async function POST(req: NextRequest) {
     }
   }
 
+  function saveReply(text: string, thinking: string): Promise<void> {
+    return recordLiveMessage(chatSessionId, idempotencyKey, {
+      role: 'assistant',
+      content: stripFinalTags(text),
+      thinking,
+    }).catch((err) => console.error('[live-messages] Save failed:', err))
+  }
+
   const unsubChat = client.on('chat', (payload: unknown) => {
-    if (closed) return
+    if (ended) return
     const evt = payload as Record<string, unknown> | undefined
     if (!evt) return
     if (evt.runId !== idempotencyKey) return
+    runGuard.touch()
 
     const state = evt.state as string
 
@@ -412,18 +584,30 @@ export async function POST(req: NextRequest) {
         write({ type: 'image', imageUrl: images[i].url, mimeType: images[i].mimeType, alt: images[i].alt })
       }
 
+      // Store the reply as streamed; the post-run auto-snapshot replaces it
+      // with the gateway's transcript (fire-and-forget)
+      const replySaved = saveReply(textContent || lastTextContent, thinkingContent || lastThinkingContent)
+      // First exchange of an untitled session: name it in the background
+      if (!session.title) {
+        generateSessionTitle(chatSessionId, message, stripFinalTags(textContent || lastTextContent)).catch((err) =>
+          console.error('[titles] Title generation failed:', err),
+        )
+      }
+      const snapshot = () =>
+        replySaved
+          .then(() => saveLiveSnapshot(chatSessionId, client!, sessionKey))
+          .catch((err) => console.error('[live-snapshot] Save failed:', err))
+
       // After streaming completes, fetch chat.history to find images in tool results.
       // Gateway doesn't emit tool agent events, so we must check history for MEDIA:/file:///paths.
-      fetchAndEmitImages(textContent).then(() => {
-        write({ type: 'done' })
-        // Post-run auto-snapshot (fire-and-forget)
-        saveLiveSnapshot(chatSessionId, client!, sessionKey).catch((err) =>
-          console.error('[live-snapshot] Save failed:', err),
-        )
+      // A run can finish while a prompt is still open: settle it first
+      approvalQueue.then(() => fetchAndEmitImages(textContent)).then(() => {
+        write(doneEvent())
+        void snapshot()
         cleanup()
       }).catch(() => {
-        write({ type: 'done' })
-        saveLiveSnapshot(chatSessionId, client!, sessionKey).catch(() => {})
+        write(doneEvent())
+        void snapshot()
         cleanup()
       })
     } else if (state === 'error') {
@@ -433,16 +617,19 @@ export async function POST(req: NextRequest) {
       })
       cleanup()
     } else if (state === 'aborted') {
-      write({ type: 'error', error: 'Conversation aborted' })
+      // What streamed before the stop stands
+      if (lastTextContent) void saveReply(lastTextContent, lastThinkingContent)
+      write({ type: 'aborted' })
       cleanup()
     }
   })
 
   const unsubAgent = client.on('agent', (payload: unknown) => {
-    if (closed) return
+    if (ended) return
     const evt = payload as Record<string, unknown> | undefined
     if (!evt) return
     if (evt.runId !== idempotencyKey) return
+    runGuard.touch()
 
     const stream = evt.stream as string | undefined
 
@@ -450,18 +637,52 @@ export async function POST(req: NextRequest) {
       const data = (evt.data ?? {}) as Record<string, unknown>
       const phase = data.phase as string
       const toolName = String(data.name ?? 'tool')
+      const toolCallId = typeof data.toolCallId === 'string' ? data.toolCallId : null
 
       if (phase === 'start') {
+        // Blocked tool: its result is never relayed and the run is aborted
+        const violation = egressPolicy && checkToolCall(egressPolicy, toolName, data.args)
+        if (violation) {
+          tools.record(toolName, data.args, 'BLOCKED')
+          write({ type: 'error', error: describeViolation(violation) })
+          client!.request('chat.abort', { sessionKey, runId: idempotencyKey }).catch(() => {})
+          void recordEgressViolation(violation, {
+            userId: user.id,
+            departmentId: egressPolicy!.departmentId,
+            instanceId,
+            agentId,
+            chatSessionId,
+            ipAddress: req.headers.get('x-forwarded-for') || 'unknown',
+            userAgent: req.headers.get('user-agent') || undefined,
+          })
+          cleanup()
+          return
+        }
+
+        tools.start(toolName, toolCallId, data.args)
         write({
           type: 'tool_call',
           toolName,
           toolInput: data.args ?? {},
         })
+
+        if (requiresApproval(toolName)) {
+          // Already confirmed through the gateway's own exec approval
+          if (toolName === GATEWAY_APPROVAL_TOOL && gatewayApproved > 0) gatewayApproved--
+          else askToolApproval(toolName, data.args ?? {}, { toolCallId })
+        }
+      } else if (phase === 'update') {
+        const partial = partialOutputText(data.partialResult)
+        if (partial) toolProgress.push(toolName, toolCallId, redactToolOutput(partial))
       } else if (phase === 'result') {
+        // Tools that stream their output may not repeat it in the result
+        const lastPartial = toolProgress.finish(toolName, toolCallId)
+        const output = redactToolOutput(data.result ?? lastPartial ?? null)
+        tools.finish(toolName, toolCallId, output, data.isError === true)
         write({
           type: 'tool_result',
           toolName,
-          toolOutput: data.result ?? null,
+          toolOutput: output,
         })
 
         // Detect image file paths in tool output (e.g. "MEDIA: /path/to/image.png")
@@ -484,14 +705,137 @@ export async function POST(req: NextRequest) {
     }
   })
 
+  // Instance announcements go out at once, even while events are held for an approval
+  const unsubAnnouncements = onAnnouncement(instanceId, (announcement) => {
+    if (!ended) emit({ type: 'system', announcement })
+  })
+
+  // The gateway pauses exec commands that need approval and asks its operators
+  const unsubExecApproval = client.on('exec.approval.requested', (payload: unknown) => {
+    if (ended) return
+    const evt = payload as { id?: string; request?: { command?: string; cwd?: string; sessionKey?: string } } | undefined
+    if (!evt?.id || evt.request?.sessionKey !== sessionKey) return
+    runGuard.touch()
+    askToolApproval(GATEWAY_APPROVAL_TOOL, { command: evt.request.command, cwd: evt.request.cwd }, { gatewayApprovalId: evt.id })
+  })
+
+  /**
+   * Pause the stream until the user confirms a tool call. Prompts are asked
+   * one at a time. Gateway approvals get the answer forwarded; a denied call
+   * the gateway did not pause for has already started, so the run is aborted.
+   */
+  function askToolApproval(
+    toolName: string,
+    toolInput: unknown,
+    { toolCallId = null, gatewayApprovalId }: { toolCallId?: string | null; gatewayApprovalId?: string },
+  ) {
+    holds++
+    approvalQueue = approvalQueue.then(async () => {
+      if (ended) return
+      const approval = requestToolApproval(user!.id)
+      approvals.add(approval)
+      emit({
+        type: 'tool_approval_required',
+        approvalId: approval.id,
+        toolName,
+        toolInput,
+        expiresAt: approval.expiresAt.toISOString(),
+      })
+      const outcome = await approval.outcome
+      approvals.delete(approval)
+      if (ended) return
+      runGuard.touch()
+
+      if (gatewayApprovalId) {
+        client!
+          .request('exec.approval.resolve', {
+            id: gatewayApprovalId,
+            decision: outcome === 'approve' ? 'allow-once' : 'deny',
+          })
+          .catch(() => {})
+        if (outcome === 'approve') gatewayApproved++
+      }
+
+      auditLog({
+        userId: user!.id,
+        action: 'CHAT_TOOL_APPROVAL',
+        resource: 'chat_session',
+        resourceId: chatSessionId,
+        details: { instanceId, agentId, toolName, outcome, viaGateway: !!gatewayApprovalId },
+        ipAddress: req.headers.get('x-forwarded-for') || 'unknown',
+        userAgent: req.headers.get('user-agent') || undefined,
+        result: outcome === 'approve' ? 'SUCCESS' : 'DENIED',
+      })
+
+      if (outcome === 'approve' || gatewayApprovalId) {
+        // The gateway tells the agent about a denied command itself
+        if (outcome !== 'approve') tools.record(toolName, toolInput, 'DENIED')
+        if (--holds === 0) for (const e of held.splice(0)) emit(e)
+        return
+      }
+
+      tools.close(toolName, toolCallId, 'DENIED')
+      held.length = 0
+      write({
+        type: 'error',
+        error: outcome === 'timeout'
+          ? `Tool "${toolName}" was not confirmed in time; the run was stopped`
+          : `Tool "${toolName}" was denied; the run was stopped`,
+      })
+      client!.request('chat.abort', { sessionKey, runId: idempotencyKey }).catch(() => {})
+      cleanup()
+    })
+  }
+
+  function doneEvent(): ChatStreamEvent {
+    return sse.dropped > 0 ? { type: 'done', droppedEvents: sse.dropped } : { type: 'done' }
+  }
+
+  // Instance run slot, held from the send until the stream ends
+  let slot: ChatSlot | null = null
+
   async function cleanup() {
+    ended = true
+    if (sentAt !== null) {
+      recordCanaryRun(session, {
+        outcome: outcome ?? 'done',
+        firstTokenMs: firstTextAt === null ? null : Math.max(0, firstTextAt - sentAt),
+        durationMs: Date.now() - sentAt,
+      })
+      sentAt = null
+    }
+    slot?.release()
+    sessionLock.release()
+    runGuard.stop()
+    toolProgress.clear()
     unsubChat()
     unsubAgent()
+    unsubExecApproval()
+    unsubAnnouncements()
+    for (const approval of approvals) approval.cancel()
+    tools.abortOpen()
     await close()
+    journal.end()
+    runSpan.setAttribute('teamclaw.chat.outcome', outcome ?? 'done')
+    runSpan.end()
+  }
+
+  // --- First conversation ever: prepend the department's welcome context ---
+  let finalMessage = message
+  if (isFirstChat && user.department?.welcomePrompt) {
+    finalMessage = wrapWelcomeContext(user.department.welcomePrompt, message)
+  }
+
+  // --- Managed preamble of the agent that receives the message ---
+  const preamble = await buildPreamble(instanceId, session.routedAgentId ?? agentId, user).catch((err) => {
+    console.error('[preamble] Render failed:', err)
+    return null
+  })
+  if (preamble) {
+    finalMessage = wrapPreamble(preamble, finalMessage)
   }
 
   // --- Auto-attach session images as base64 (non-blocking, no text injection) ---
-  const finalMessage = message
   const sessionFileAttachments: { fileName: string; mimeType: string; content: string }[] = []
   const SESSION_IMAGE_EXTS: Record<string, string> = {
     '.png': 'image/png', '.jpg': 'image/jpeg', '.jpeg': 'image/jpeg',
@@ -557,16 +901,55 @@ export async function POST(req: NextRequest) {
   }
 
   const mappedAttachments = [
-    ...(attachments?.map(a => ({ fileName: a.name, mimeType: a.mimeType, content: a.content })) ?? []),
+    ...messageAttachments,
     ...sessionFileAttachments,
   ]
 
-  adapter
-    .sendMessage(client, sessionKey, finalMessage, idempotencyKey, {
-      attachments: mappedAttachments.length > 0 ? mappedAttachments : undefined,
+  // Wait for a run slot on the instance; the department's tier decides the
+  // place in the queue. The response is already streaming meanwhile.
+  const priority = user.department?.chatPriority ?? 'NORMAL'
+  acquireChatSlot(instanceId, priority, {
+    signal: req.signal,
+    onQueued: (position) => write({ type: 'queued', position }),
+  })
+    .then((acquired) => {
+      slot = acquired
+      // Gave up while queued: the run never started
+      if (sse.closed) return cleanup()
+      // Brief gateway reconnects are retried with the same idempotencyKey
+      return sendWithRetry(
+        client,
+        () =>
+          runInSpan(runSpan, () =>
+            adapter.sendMessage(client, sessionKey, finalMessage, idempotencyKey, {
+              attachments: mappedAttachments.length > 0 ? mappedAttachments : undefined,
+            }),
+          ),
+        {
+          onReconnecting: (attempt, maxAttempts) => {
+            runGuard.touch()
+            write({ type: 'reconnecting', attempt, maxAttempts })
+          },
+          isCancelled: () => ended,
+        },
+      )
+        .then(() => {
+          sentAt = Date.now()
+          journal.track()
+          recordLiveMessage(chatSessionId, idempotencyKey, { role: 'user', content: message }).catch((err) =>
+            console.error('[live-messages] Save failed:', err),
+          )
+        })
+        .catch((err: Error) => {
+          write({ type: 'error', error: err.message || 'Failed to send message' })
+          cleanup()
+        })
     })
-    .catch((err: Error) => {
-      write({ type: 'error', error: err.message || 'Failed to send message' })
+    .catch((err) => {
+      write({
+        type: 'error',
+        error: err instanceof ChatQueueTimeoutError ? err.message : 'Request cancelled while queued',
+      })
       cleanup()
     })
 
@@ -577,4 +960,4 @@ export async function POST(req: NextRequest) {
       Connection: 'keep-alive',
     },
   })
-}
+})
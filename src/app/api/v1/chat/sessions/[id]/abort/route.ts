@@ -0,0 +1,41 @@
+import { NextResponse } from 'next/server'
+import { prisma } from '@/lib/db'
+import { withAuth, withPermission, param } from '@/lib/middleware/auth'
+import { registry, ensureRegistryInitialized } from '@/lib/gateway/registry'
+import { chatSessionKey } from '@/lib/agents/canary'
+
+// POST /api/v1/chat/sessions/[id]/abort — Stop the session's run in progress.
+// Streams attached to the run receive an `aborted` event and end.
+export const POST = withAuth(
+  withPermission('chat:use', async (_req, ctx) => {
+    const id = param(ctx, 'id')
+
+    const session = await prisma.chatSession.findUnique({ where: { id } })
+    if (!session) {
+      return NextResponse.json({ error: 'Session not found' }, { status: 404 })
+    }
+    if (session.userId !== ctx.user.id) {
+      return NextResponse.json({ error: 'No access to this session' }, { status: 403 })
+    }
+    if (!session.isActive) {
+      return NextResponse.json({ error: 'Session is archived' }, { status: 400 })
+    }
+
+    await ensureRegistryInitialized()
+    const client = registry.getClient(session.instanceId)
+    if (!client) {
+      return NextResponse.json({ error: 'Instance not connected' }, { status: 502 })
+    }
+
+    // The run ID is the chat.send idempotency key. A run is recorded once the
+    // gateway accepts it; before that, abort whatever runs on the session key.
+    const run = await prisma.chatRun.findFirst({
+      where: { chatSessionId: id },
+      orderBy: { startedAt: 'desc' },
+    })
+    const sessionKey = run?.sessionKey ?? chatSessionKey(session.userId, session.agentId, session.routedAgentId)
+    await client.request('chat.abort', { sessionKey, ...(run ? { runId: run.id } : {}) })
+
+    return NextResponse.json({ ok: true, runId: run?.id ?? null })
+  }),
+)
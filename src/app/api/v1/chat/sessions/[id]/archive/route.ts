@@ -0,0 +1,48 @@
+import { NextResponse } from 'next/server'
+import { prisma } from '@/lib/db'
+import { withAuth, withPermission, param } from '@/lib/middleware/auth'
+import { registry, ensureRegistryInitialized } from '@/lib/gateway/registry'
+import { archiveSession, archiveStoredMessages } from '@/lib/chat/snapshot-helpers'
+import { auditLog } from '@/lib/audit'
+
+// POST /api/v1/chat/sessions/[id]/archive — snapshot the session, delete the
+// OpenClaw session and mark it inactive; it stays in the history list
+export const POST = withAuth(
+  withPermission('chat:use', async (req, ctx) => {
+    const id = param(ctx, 'id')
+
+    const session = await prisma.chatSession.findUnique({ where: { id } })
+    if (!session) {
+      return NextResponse.json({ error: 'Session not found' }, { status: 404 })
+    }
+    if (session.userId !== ctx.user.id) {
+      return NextResponse.json({ error: 'No access to this session' }, { status: 403 })
+    }
+    if (!session.isActive) {
+      return NextResponse.json({ error: 'Session is already archived' }, { status: 400 })
+    }
+
+    await ensureRegistryInitialized().catch(() => {})
+    const client = registry.getClient(session.instanceId)
+    if (client) {
+      await archiveSession(id, session.instanceId, session.agentId, session.userId, client)
+    } else {
+      // Gateway offline — archive what was stored as it streamed
+      await archiveStoredMessages(id)
+      await prisma.chatSession.update({ where: { id }, data: { isActive: false } })
+    }
+
+    auditLog({
+      userId: ctx.user.id,
+      action: 'CHAT_SESSION_ARCHIVE',
+      resource: 'chat_session',
+      resourceId: id,
+      details: { instanceId: session.instanceId, agentId: session.agentId, gatewayReached: !!client },
+      ipAddress: req.headers.get('x-forwarded-for') || 'unknown',
+      userAgent: req.headers.get('user-agent') || undefined,
+      result: 'SUCCESS',
+    })
+
+    return NextResponse.json({ success: true })
+  }),
+)
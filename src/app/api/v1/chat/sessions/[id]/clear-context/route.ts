@@ -3,7 +3,6 @@ import { prisma } from '@/lib/db'
 import { withAuth, withPermission, param } from '@/lib/middleware/auth'
 import { registry, ensureRegistryInitialized } from '@/lib/gateway/registry'
 import { archiveSession } from '@/lib/chat/snapshot-helpers'
-import { Prisma } from '@/generated/prisma'
 
 // POST /api/v1/chat/sessions/[id]/clear-context — snapshot messages and reset OpenClaw session
 export const POST = withAuth(
@@ -38,12 +37,6 @@ export const POST = withAuth(
       // Archive: snapshot messages + delete OpenClaw session (keeps DB session active)
       await archiveSession(id, session.instanceId, session.agentId, session.userId, client, { keepActive: true })
 
-      // Clear liveMessages since context was reset
-      await prisma.chatSession.update({
-        where: { id },
-        data: { liveMessages: Prisma.DbNull },
-      })
-
       return NextResponse.json({ success: true })
     } catch (err) {
       const message = err instanceof Error ? err.message : 'Failed to clear context'
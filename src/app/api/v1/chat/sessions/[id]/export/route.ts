@@ -0,0 +1,53 @@
+import { NextResponse } from 'next/server'
+import { prisma } from '@/lib/db'
+import { withAuth, withPermission, param } from '@/lib/middleware/auth'
+import { auditLog } from '@/lib/audit'
+import { buildSessionTranscript, transcriptToMarkdown } from '@/lib/chat/export'
+
+// GET /api/v1/chat/sessions/[id]/export?format=md|json — The whole session as
+// one downloadable transcript: archived batches, then the current context
+export const GET = withAuth(
+  withPermission('chat:use', async (req, ctx) => {
+    const id = param(ctx, 'id')
+    const format = new URL(req.url).searchParams.get('format') ?? 'md'
+    if (format !== 'md' && format !== 'json') {
+      return NextResponse.json({ error: 'format must be md or json' }, { status: 400 })
+    }
+
+    const session = await prisma.chatSession.findUnique({
+      where: { id },
+      include: { instance: { select: { name: true } } },
+    })
+    if (!session) {
+      return NextResponse.json({ error: 'Session not found' }, { status: 404 })
+    }
+    if (session.userId !== ctx.user.id) {
+      return NextResponse.json({ error: 'No access to this session' }, { status: 403 })
+    }
+
+    const transcript = await buildSessionTranscript(session)
+
+    auditLog({
+      userId: ctx.user.id,
+      action: 'CHAT_SESSION_EXPORT',
+      resource: 'chat_session',
+      resourceId: id,
+      details: { format, messages: transcript.messages.length },
+      ipAddress: req.headers.get('x-forwarded-for') || 'unknown',
+      userAgent: req.headers.get('user-agent') || undefined,
+      result: 'SUCCESS',
+    })
+
+    const filename = `chat-${id}-${transcript.exportedAt.slice(0, 10)}.${format}`
+    return new Response(
+      format === 'md' ? transcriptToMarkdown(transcript) : JSON.stringify(transcript, null, 2),
+      {
+        headers: {
+          'Content-Type': format === 'md' ? 'text/markdown; charset=utf-8' : 'application/json; charset=utf-8',
+          'Content-Disposition': `attachment; filename="${filename}"`,
+          'Cache-Control': 'no-store',
+        },
+      },
+    )
+  }),
+)
@@ -1,6 +1,6 @@
 import { NextRequest, NextResponse } from 'next/server'
 import { prisma } from '@/lib/db'
-import { resolveRequestUserId } from '@/lib/middleware/auth'
+import { mfaSetupResponse, resolveRequestUserId } from '@/lib/middleware/auth'
 import { dockerManager } from '@/lib/docker/manager'
 import {
   buildSessionInputPath,
@@ -23,6 +23,15 @@ export async function GET(
   if (!userId) {
     return NextResponse.json({ error: 'Unauthorized' }, { status: 401 })
   }
+  const user = await prisma.user.findUnique({
+    where: { id: userId },
+    select: { status: true, mfaRequired: true, mfaEnabled: true },
+  })
+  if (!user || user.status !== 'ACTIVE') {
+    return NextResponse.json({ error: 'Unauthorized' }, { status: 401 })
+  }
+  const mfaBlocked = mfaSetupResponse(user, req)
+  if (mfaBlocked) return mfaBlocked
 
   // --- Validate session ownership ---
   const session = await prisma.chatSession.findUnique({ where: { id } })
@@ -1,9 +1,9 @@
 import { extname } from 'path'
 import { NextResponse } from 'next/server'
 import { prisma } from '@/lib/db'
-import { Prisma } from '@/generated/prisma'
 import { withAuth, withPermission, param } from '@/lib/middleware/auth'
 import { registry, ensureRegistryInitialized } from '@/lib/gateway/registry'
+import { fetchChatHistory } from '@/lib/gateway/history'
 import {
   extractText,
   extractThinking,
@@ -11,11 +11,18 @@ import {
   stripUserMetadata,
   stripFinalTags,
   splitThinkingFallback,
-  persistLiveAsSnapshot,
+  archiveStoredMessages,
+  snapshotRowsToBatches,
 } from '@/lib/chat/snapshot-helpers'
+import { getLiveMessages } from '@/lib/chat/live-messages'
+import { decryptSnapshots } from '@/lib/chat/snapshot-crypto'
+import { getSessionSummary } from '@/lib/chat/compaction'
+import { getToolOutputRedactor, type ToolOutputRedactor } from '@/lib/chat/redaction'
+import { chatSessionKey } from '@/lib/agents/canary'
+import { parseRenderMode, withRenderedHtml } from '@/lib/markdown'
 import { MIME_BY_EXT, extractMediaPaths, extractFileProtocolPaths, readImageAsDataUrl } from '@/lib/chat/image-helpers'
-import type { ChatHistoryResult, ChatHistoryMessage } from '@/types/gateway'
-import type { ChatMessage, ChatToolCall, ChatSnapshotBatch, ChatHistoryResponse, ChatContentBlock } from '@/types/chat'
+import type { ChatHistoryMessage } from '@/types/gateway'
+import type { ChatMessage, ChatToolCall, ChatHistoryResponse, ChatContentBlock, ChatSnapshotBatch } from '@/types/chat'
 
 /**
  * Strip MEDIA:/Image saved:/file:/// references from assistant text.
@@ -37,7 +44,10 @@ function stripMediaReferences(text: string): string {
  */
 interface PendingImage { messageIndex: number; path: string }
 
-function transformMessages(raw: ChatHistoryMessage[]): { messages: ChatMessage[]; pendingImages: PendingImage[] } {
+function transformMessages(
+  raw: ChatHistoryMessage[],
+  redact: ToolOutputRedactor,
+): { messages: ChatMessage[]; pendingImages: PendingImage[] } {
   const result: ChatMessage[] = []
   const pendingImages: PendingImage[] = []
 
@@ -87,7 +97,7 @@ function transformMessages(raw: ChatHistoryMessage[]): { messages: ChatMessage[]
         const tc: ChatToolCall = {
           toolName: msg.toolName ?? 'tool',
           toolInput: null,
-          toolOutput: outputText,
+          toolOutput: redact(outputText),
         }
         last.toolCalls = [...(last.toolCalls ?? []), tc]
 
@@ -114,14 +124,46 @@ function transformMessages(raw: ChatHistoryMessage[]): { messages: ChatMessage[]
   return { messages: result, pendingImages }
 }
 
+const DEFAULT_PAGE_SIZE = 100
+const MAX_PAGE_SIZE = 500
+
+/** A cursor query param: a timeline position, or null when absent; NaN when malformed */
+function cursorParam(url: URL, name: string): number | null {
+  const raw = url.searchParams.get(name)
+  if (raw === null) return null
+  return /^\d+$/.test(raw) ? parseInt(raw, 10) : NaN
+}
+
 // GET /api/v1/chat/sessions/[id]/history — load snapshots + current messages
+//
+// One page of the session's timeline: its snapshot messages, oldest first,
+// followed by the live messages. Positions on that timeline are the cursors:
+// ?before=<page.start> pages back, ?after=<page.end> forward; without either
+// the newest page is returned. ?limit sets the page size (default 100, max
+// 500). Pages that lie entirely within the snapshots do not touch the gateway.
+// Sessions with compacted history (lib/chat/compaction) may carry a summary.
+// ?render=html adds a sanitized HTML rendering of each message's content
 export const GET = withAuth(
-  withPermission('chat:use', async (_req, ctx) => {
+  withPermission('chat:use', async (req, ctx) => {
     const id = param(ctx, 'id')
     if (!id) {
       return NextResponse.json({ error: 'Missing session ID' }, { status: 400 })
     }
 
+    const url = new URL(req.url)
+    const before = cursorParam(url, 'before')
+    const after = cursorParam(url, 'after')
+    if (Number.isNaN(before) || Number.isNaN(after)) {
+      return NextResponse.json({ error: 'Invalid cursor' }, { status: 400 })
+    }
+    if (before !== null && after !== null) {
+      return NextResponse.json({ error: 'Use either before or after, not both' }, { status: 400 })
+    }
+    const limit = Math.min(
+      Math.max(parseInt(url.searchParams.get('limit') ?? '', 10) || DEFAULT_PAGE_SIZE, 1),
+      MAX_PAGE_SIZE,
+    )
+
     const session = await prisma.chatSession.findUnique({ where: { id } })
 
     if (!session) {
@@ -132,112 +174,133 @@ export const GET = withAuth(
       return NextResponse.json({ error: 'No access to this session' }, { status: 403 })
     }
 
-    // 1. Load snapshot messages from DB
-    const snapshotRows = await prisma.chatMessageSnapshot.findMany({
-      where: { chatSessionId: id },
-      orderBy: [{ createdAt: 'asc' }, { orderIndex: 'asc' }],
-    })
-
-    // 2. Group by batchId
-    const batchMap = new Map<string, { createdAt: string; messages: ChatMessage[] }>()
-    for (const row of snapshotRows) {
-      if (!batchMap.has(row.batchId)) {
-        batchMap.set(row.batchId, {
-          createdAt: row.createdAt.toISOString(),
-          messages: [],
-        })
-      }
-      const batch = batchMap.get(row.batchId)!
-      batch.messages.push({
-        id: row.id,
-        role: row.role as 'user' | 'assistant',
-        content: row.content,
-        ...(row.contentBlocks ? { contentBlocks: row.contentBlocks as unknown as ChatContentBlock[] } : {}),
-        ...(row.thinking ? { thinking: row.thinking } : {}),
-        ...(row.toolCalls ? { toolCalls: row.toolCalls as unknown as ChatToolCall[] } : {}),
-        createdAt: row.createdAt.toISOString(),
-      })
-    }
+    const [snapshotCount, summary] = await Promise.all([
+      prisma.chatMessageSnapshot.count({ where: { chatSessionId: id } }),
+      getSessionSummary(id),
+    ])
 
-    const snapshots: ChatSnapshotBatch[] = Array.from(batchMap.entries()).map(
-      ([batchId, data]) => ({
-        batchId,
-        createdAt: data.createdAt,
-        messages: data.messages,
-      }),
-    )
-
-    // 3. If session is active, load current messages from OpenClaw
-    let currentMessages: ChatMessage[] = []
+    // 1. If session is active and the page reaches past the snapshots, load
+    //    current messages from OpenClaw, or the ones stored as they streamed
+    //    when it cannot be reached
+    let liveMessages: ChatMessage[] = []
+    let pendingImages: PendingImage[] = []
+    // Live messages of a session found destroyed, shown as a snapshot batch
+    let recovered: ChatSnapshotBatch | null = null
     let connectionStatus: 'ok' | 'unreachable' = 'ok'
     let sessionIsActive = session.isActive
 
-    if (session.isActive) {
+    if (session.isActive && (before === null || before > snapshotCount)) {
       try {
         await ensureRegistryInitialized()
         const client = registry.getClient(session.instanceId)
-        if (client) {
-          const sessionKey = `agent:${session.agentId}:tc:${session.userId}`
-          const rawResult = await client.request('chat.history', { sessionKey, limit: 200 }, 10_000)
-          const historyResult = rawResult as ChatHistoryResult
-          const { messages: msgs, pendingImages } = transformMessages(historyResult.messages ?? [])
-
-          // Load image files referenced in tool results
-          if (pendingImages.length > 0) {
-            const loaded = await Promise.all(
-              pendingImages.map(async ({ messageIndex, path: p }) => ({
-                messageIndex,
-                dataUrl: await readImageAsDataUrl(p),
-                mimeType: MIME_BY_EXT[extname(p).toLowerCase()] || 'image/png',
-              })),
-            )
-            for (const { messageIndex, dataUrl, mimeType } of loaded) {
-              if (!dataUrl) continue
-              const msg = msgs[messageIndex]
-              if (msg?.role === 'assistant') {
-                const blocks: ChatContentBlock[] = [...(msg.contentBlocks ?? [])]
-                blocks.push({ type: 'image', imageUrl: dataUrl, mimeType })
-                msg.contentBlocks = blocks
-              }
-            }
-          }
-
-          currentMessages = msgs
-        }
+        if (!client) throw new Error('Instance not connected')
+        const sessionKey = chatSessionKey(session.userId, session.agentId, session.routedAgentId)
+        const historyResult = await fetchChatHistory(client, sessionKey, 200, 10_000)
+        const transformed = transformMessages(
+          historyResult.messages ?? [],
+          getToolOutputRedactor(ctx.user.departmentId),
+        )
+        liveMessages = transformed.messages
+        pendingImages = transformed.pendingImages
 
         // Stale session detection: gateway responded but session was destroyed (SIGUSR1 restart).
         // Skip for very recently created sessions — the gateway may not have received the
         // first chat.send yet (race: SSE session event arrives before gateway processes message).
         const sessionAgeMs = Date.now() - session.createdAt.getTime()
-        if (currentMessages.length === 0 && sessionAgeMs > 30_000) {
-          if (session.liveMessages) {
-            // Recover messages from liveMessages auto-snapshot
-            snapshots.push({
+        if (liveMessages.length === 0 && sessionAgeMs > 30_000) {
+          const stored = await getLiveMessages(id)
+          if (stored.length > 0) {
+            // Recover the messages stored as the session streamed
+            recovered = {
               batchId: `recovered-${id}`,
               createdAt: session.updatedAt.toISOString(),
-              messages: session.liveMessages as unknown as ChatMessage[],
-            })
-            // Persist as permanent snapshot (fire-and-forget)
-            persistLiveAsSnapshot(id, session.liveMessages as unknown as ChatMessage[]).catch(() => {})
+              messages: stored,
+            }
           }
-          // Mark session inactive + clear liveMessages
+          // Persist as permanent snapshot, then mark session inactive
+          await archiveStoredMessages(id).catch(() => {})
           await prisma.chatSession.update({
             where: { id },
-            data: { isActive: false, liveMessages: Prisma.DbNull },
+            data: { isActive: false },
           }).catch(() => {})
           sessionIsActive = false
         }
       } catch {
-        // Gateway unreachable / timeout — show warning, keep session active for retry
+        // Gateway unreachable / timeout — show warning and the stored messages,
+        // keep session active for retry
         connectionStatus = 'unreachable'
+        liveMessages = await getLiveMessages(id).catch(() => [])
+        pendingImages = []
+      }
+    }
+
+    // 2. The page window on the timeline
+    const tail = recovered?.messages ?? liveMessages
+    const total = snapshotCount + tail.length
+    let start: number
+    let end: number
+    if (after !== null) {
+      start = Math.min(after, total)
+      end = Math.min(start + limit, total)
+    } else {
+      end = Math.min(before ?? total, total)
+      start = Math.max(0, end - limit)
+    }
+
+    // 3. Snapshot messages in the window, grouped by batchId
+    const snapshotRows = start < snapshotCount
+      ? await decryptSnapshots(
+          await prisma.chatMessageSnapshot.findMany({
+            where: { chatSessionId: id },
+            // id breaks ties so that pages never overlap
+            orderBy: [{ createdAt: 'asc' }, { orderIndex: 'asc' }, { id: 'asc' }],
+            skip: start,
+            take: Math.min(end, snapshotCount) - start,
+          }),
+        )
+      : []
+    const snapshots = snapshotRowsToBatches(snapshotRows)
+
+    // 4. Live (or recovered) messages in the window
+    const tailStart = Math.max(0, start - snapshotCount)
+    const tailEnd = Math.max(0, end - snapshotCount)
+    const tailSlice = tail.slice(tailStart, tailEnd)
+    if (recovered && tailSlice.length > 0) {
+      snapshots.push({ ...recovered, messages: tailSlice })
+    }
+    const currentMessages = recovered ? [] : tailSlice
+
+    // Load image files referenced in tool results, for the messages on this page
+    const pageImages = recovered
+      ? []
+      : pendingImages.filter(({ messageIndex }) => messageIndex >= tailStart && messageIndex < tailEnd)
+    if (pageImages.length > 0) {
+      const loaded = await Promise.all(
+        pageImages.map(async ({ messageIndex, path: p }) => ({
+          messageIndex,
+          dataUrl: await readImageAsDataUrl(p),
+          mimeType: MIME_BY_EXT[extname(p).toLowerCase()] || 'image/png',
+        })),
+      )
+      for (const { messageIndex, dataUrl, mimeType } of loaded) {
+        if (!dataUrl) continue
+        const msg = liveMessages[messageIndex]
+        if (msg?.role === 'assistant') {
+          const blocks: ChatContentBlock[] = [...(msg.contentBlocks ?? [])]
+          blocks.push({ type: 'image', imageUrl: dataUrl, mimeType })
+          msg.contentBlocks = blocks
+        }
       }
     }
 
+    const render = parseRenderMode(req.url)
     const response: ChatHistoryResponse = {
-      snapshots,
-      currentMessages,
+      snapshots: snapshots.map((b) => ({ ...b, messages: withRenderedHtml(b.messages, render) })),
+      currentMessages: withRenderedHtml(currentMessages, render),
       isActive: sessionIsActive,
       ...(connectionStatus !== 'ok' ? { connectionStatus } : {}),
+      page: { start, end, total, hasOlder: start > 0 },
+      ...(summary ? { summary } : {}),
     }
 
     return NextResponse.json(response)
@@ -0,0 +1,73 @@
+import { NextResponse } from 'next/server'
+import { prisma } from '@/lib/db'
+import { Prisma } from '@/generated/prisma'
+import { withAuth, withPermission, withValidation, param } from '@/lib/middleware/auth'
+import type { AuthContext } from '@/lib/middleware/auth'
+import { createSupportTicketSchema } from '@/lib/validations/support-ticket'
+import { auditLog } from '@/lib/audit'
+import {
+  collectDiagnostics,
+  forwardTicket,
+  ticketAssignee,
+  toTicketResponse,
+  TICKET_INCLUDE,
+} from '@/lib/support'
+
+// POST /api/v1/chat/sessions/[id]/report — Report a problem with a response
+// of the session; files a support ticket for the instance's owning admin
+export const POST = withAuth(
+  withPermission(
+    'chat:use',
+    withValidation(createSupportTicketSchema, async (req, ctx) => {
+      const { user, body } = ctx as {
+        user: NonNullable<typeof ctx.user>
+        body: typeof ctx.body
+      }
+      const id = param(ctx as unknown as AuthContext, 'id')
+
+      const session = await prisma.chatSession.findUnique({ where: { id } })
+      if (!session) {
+        return NextResponse.json({ error: 'Session not found' }, { status: 404 })
+      }
+      if (session.userId !== user.id) {
+        return NextResponse.json({ error: 'No access to this session' }, { status: 403 })
+      }
+
+      const [diagnostics, assigneeId] = await Promise.all([
+        collectDiagnostics(session.instanceId, id),
+        ticketAssignee(session.instanceId),
+      ])
+
+      const ticket = await prisma.supportTicket.create({
+        data: {
+          reporterId: user.id,
+          chatSessionId: id,
+          instanceId: session.instanceId,
+          agentId: session.agentId,
+          messageId: body.messageId,
+          messageContent: body.messageContent,
+          category: body.category,
+          description: body.description || null,
+          diagnostics: diagnostics as unknown as Prisma.InputJsonValue,
+          assigneeId,
+        },
+        include: TICKET_INCLUDE,
+      })
+      const response = toTicketResponse(ticket)
+      void forwardTicket(response)
+
+      auditLog({
+        userId: user.id,
+        action: 'SUPPORT_TICKET_CREATE',
+        resource: 'support_ticket',
+        resourceId: ticket.id,
+        details: { chatSessionId: id, instanceId: session.instanceId, category: body.category, assigneeId },
+        ipAddress: req.headers.get('x-forwarded-for') || 'unknown',
+        userAgent: req.headers.get('user-agent') || undefined,
+        result: 'SUCCESS',
+      })
+
+      return NextResponse.json({ ticket: response }, { status: 201 })
+    }),
+  ),
+)
@@ -1,13 +1,17 @@
 import { NextResponse } from 'next/server'
 import { prisma } from '@/lib/db'
-import { withAuth, withPermission, param } from '@/lib/middleware/auth'
+import { withAuth, withPermission, withValidation, param } from '@/lib/middleware/auth'
+import type { AuthContext } from '@/lib/middleware/auth'
+import { renameSessionSchema } from '@/lib/validations/chat'
 import { registry, ensureRegistryInitialized } from '@/lib/gateway/registry'
 import { dockerManager } from '@/lib/docker/manager'
 import { buildSessionBasePath } from '@/lib/session-files/helpers'
+import { auditLog } from '@/lib/audit'
 
-// DELETE /api/v1/chat/sessions/[id] — delete a chat session
+// DELETE /api/v1/chat/sessions/[id] — delete a chat session; its snapshots,
+// live messages and shares go with it
 export const DELETE = withAuth(
-  withPermission('chat:use', async (_req, ctx) => {
+  withPermission('chat:use', async (req, ctx) => {
     const id = param(ctx, 'id')
     if (!id) {
       return NextResponse.json({ error: 'Missing session ID' }, { status: 400 })
@@ -25,13 +29,16 @@ export const DELETE = withAuth(
       return NextResponse.json({ error: 'No access to delete this session' }, { status: 403 })
     }
 
-    // Try to delete the gateway session (best-effort)
+    // Try to delete the gateway session (best-effort). Only an active session
+    // owns it: an archived one's key may serve the user's current session.
+    let gatewayDeleted = false
     try {
       await ensureRegistryInitialized()
       const adapter = registry.getAdapter(session.instanceId)
       const client = registry.getClient(session.instanceId)
-      if (adapter && client) {
+      if (session.isActive && adapter && client) {
         await adapter.deleteSession(client, session.sessionId)
+        gatewayDeleted = true
       }
     } catch {
       // Gateway might be offline — continue with DB deletion
@@ -53,6 +60,54 @@ export const DELETE = withAuth(
 
     await prisma.chatSession.delete({ where: { id } })
 
+    auditLog({
+      userId: ctx.user.id,
+      action: 'CHAT_SESSION_DELETE',
+      resource: 'chat_session',
+      resourceId: id,
+      details: {
+        instanceId: session.instanceId,
+        agentId: session.agentId,
+        title: session.title,
+        messageCount: session.messageCount,
+        wasActive: session.isActive,
+        gatewayDeleted,
+      },
+      ipAddress: req.headers.get('x-forwarded-for') || 'unknown',
+      userAgent: req.headers.get('user-agent') || undefined,
+      result: 'SUCCESS',
+    })
+
     return new NextResponse(null, { status: 204 })
   }),
 )
+
+// PATCH /api/v1/chat/sessions/[id] — rename a chat session
+export const PATCH = withAuth(
+  withPermission(
+    'chat:use',
+    withValidation(renameSessionSchema, async (_req, ctx) => {
+      const { user, body } = ctx as {
+        user: NonNullable<typeof ctx.user>
+        body: typeof ctx.body
+      }
+      const id = param(ctx as unknown as AuthContext, 'id')
+
+      const session = await prisma.chatSession.findUnique({ where: { id }, select: { userId: true } })
+      if (!session) {
+        return NextResponse.json({ error: 'Session not found' }, { status: 404 })
+      }
+      if (session.userId !== user.id) {
+        return NextResponse.json({ error: 'No access to this session' }, { status: 403 })
+      }
+
+      const updated = await prisma.chatSession.update({
+        where: { id },
+        data: { title: body.title },
+        select: { id: true, title: true },
+      })
+
+      return NextResponse.json({ session: updated })
+    }),
+  ),
+)
@@ -0,0 +1,88 @@
+import { NextResponse } from 'next/server'
+import { prisma } from '@/lib/db'
+import { withAuth, withPermission, withValidation, param } from '@/lib/middleware/auth'
+import type { AuthContext } from '@/lib/middleware/auth'
+import { createSessionShareSchema } from '@/lib/validations/chat'
+import { hashPassword } from '@/lib/auth/password'
+import { generateShareToken, toShareResponse } from '@/lib/chat/shares'
+import { auditLog } from '@/lib/audit'
+
+// GET /api/v1/chat/sessions/[id]/shares — List share links for own session
+export const GET = withAuth(
+  withPermission('chat:use', async (_req, ctx) => {
+    const id = param(ctx, 'id')
+
+    const session = await prisma.chatSession.findUnique({ where: { id }, select: { userId: true } })
+    if (!session) {
+      return NextResponse.json({ error: 'Session not found' }, { status: 404 })
+    }
+    if (session.userId !== ctx.user.id) {
+      return NextResponse.json({ error: 'No access to this session' }, { status: 403 })
+    }
+
+    const shares = await prisma.sessionShare.findMany({
+      where: { chatSessionId: id },
+      orderBy: { createdAt: 'desc' },
+    })
+    return NextResponse.json({ shares: shares.map((s) => toShareResponse(s)) })
+  }),
+)
+
+// POST /api/v1/chat/sessions/[id]/shares — Create a public read-only link
+// The link shows snapshots that exist now; later messages are not exposed.
+export const POST = withAuth(
+  withPermission(
+    'chat:use',
+    withValidation(createSessionShareSchema, async (req, ctx) => {
+      const { user, body } = ctx as {
+        user: NonNullable<typeof ctx.user>
+        body: typeof ctx.body
+      }
+      const id = param(ctx as unknown as AuthContext, 'id')
+
+      const session = await prisma.chatSession.findUnique({ where: { id } })
+      if (!session) {
+        return NextResponse.json({ error: 'Session not found' }, { status: 404 })
+      }
+      if (session.userId !== user.id) {
+        return NextResponse.json({ error: 'No access to this session' }, { status: 403 })
+      }
+
+      const snapshotCount = await prisma.chatMessageSnapshot.count({ where: { chatSessionId: id } })
+      if (snapshotCount === 0) {
+        return NextResponse.json(
+          { error: 'Session has no saved messages to share yet' },
+          { status: 400 },
+        )
+      }
+
+      const { token, tokenHash } = generateShareToken()
+      const share = await prisma.sessionShare.create({
+        data: {
+          tokenHash,
+          chatSessionId: id,
+          createdById: user.id,
+          passwordHash: body.password ? await hashPassword(body.password) : null,
+          expiresAt: new Date(Date.now() + body.expiresInHours * 3600000),
+        },
+      })
+
+      auditLog({
+        userId: user.id,
+        action: 'SESSION_SHARE_CREATE',
+        resource: 'chat_session',
+        resourceId: id,
+        details: {
+          shareId: share.id,
+          expiresAt: share.expiresAt.toISOString(),
+          passwordProtected: !!body.password,
+        },
+        ipAddress: req.headers.get('x-forwarded-for') || 'unknown',
+        userAgent: req.headers.get('user-agent') || undefined,
+        result: 'SUCCESS',
+      })
+
+      return NextResponse.json({ share: toShareResponse(share, token) }, { status: 201 })
+    }),
+  ),
+)
@@ -0,0 +1,33 @@
+import { NextResponse } from 'next/server'
+import { prisma } from '@/lib/db'
+import { withAuth, withPermission, param } from '@/lib/middleware/auth'
+import { toToolInvocationEntries } from '@/lib/chat/tool-invocations'
+
+const MAX_ROWS = 500
+
+// GET /api/v1/chat/sessions/[id]/tool-invocations — Tool calls recorded in a session (?runId= for one reply)
+export const GET = withAuth(
+  withPermission('chat:use', async (req, ctx) => {
+    const id = param(ctx, 'id')
+    const runId = new URL(req.url).searchParams.get('runId')
+
+    const session = await prisma.chatSession.findUnique({
+      where: { id },
+      select: { userId: true },
+    })
+    if (!session) {
+      return NextResponse.json({ error: 'Session not found' }, { status: 404 })
+    }
+    if (session.userId !== ctx.user.id) {
+      return NextResponse.json({ error: 'No access to this session' }, { status: 403 })
+    }
+
+    const rows = await prisma.toolInvocation.findMany({
+      where: { chatSessionId: id, ...(runId ? { runId } : {}) },
+      orderBy: { startedAt: 'asc' },
+      take: MAX_ROWS,
+    })
+
+    return NextResponse.json({ invocations: await toToolInvocationEntries(rows) })
+  }),
+)
@@ -0,0 +1,40 @@
+import { NextResponse } from 'next/server'
+import { prisma } from '@/lib/db'
+import { withAuth, withPermission, param } from '@/lib/middleware/auth'
+import { toShareResponse } from '@/lib/chat/shares'
+import { auditLog } from '@/lib/audit'
+
+// DELETE /api/v1/chat/shares/[id] — Revoke a share link (owner or SYSTEM_ADMIN)
+export const DELETE = withAuth(
+  withPermission('chat:use', async (req, ctx) => {
+    const id = param(ctx, 'id')
+
+    const share = await prisma.sessionShare.findUnique({ where: { id } })
+    if (!share) {
+      return NextResponse.json({ error: 'Share not found' }, { status: 404 })
+    }
+    if (share.createdById !== ctx.user.id && ctx.user.role !== 'SYSTEM_ADMIN') {
+      return NextResponse.json({ error: 'No access to this share' }, { status: 403 })
+    }
+
+    const revoked = share.revokedAt
+      ? share
+      : await prisma.sessionShare.update({
+          where: { id },
+          data: { revokedAt: new Date() },
+        })
+
+    auditLog({
+      userId: ctx.user.id,
+      action: 'SESSION_SHARE_REVOKE',
+      resource: 'chat_session',
+      resourceId: share.chatSessionId,
+      details: { shareId: id },
+      ipAddress: req.headers.get('x-forwarded-for') || 'unknown',
+      userAgent: req.headers.get('user-agent') || undefined,
+      result: 'SUCCESS',
+    })
+
+    return NextResponse.json({ share: toShareResponse(revoked) })
+  }),
+)
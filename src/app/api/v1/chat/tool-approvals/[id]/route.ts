@@ -0,0 +1,29 @@
+import { NextResponse } from 'next/server'
+import { withAuth, withPermission, withValidation, param } from '@/lib/middleware/auth'
+import type { AuthContext } from '@/lib/middleware/auth'
+import { toolApprovalDecisionSchema } from '@/lib/validations/chat'
+import { resolveToolApproval } from '@/lib/chat/tool-approvals'
+
+// POST /api/v1/chat/tool-approvals/[id] — Answer a tool confirmation prompt
+// from a running chat stream. The stream records the decision in the audit log.
+export const POST = withAuth(
+  withPermission(
+    'chat:use',
+    withValidation(toolApprovalDecisionSchema, async (_req, ctx) => {
+      const { user, body } = ctx as {
+        user: NonNullable<typeof ctx.user>
+        body: typeof ctx.body
+      }
+      const id = param(ctx as unknown as AuthContext, 'id')
+
+      const result = resolveToolApproval(id, user.id, body.decision)
+      if (result === 'forbidden') {
+        return NextResponse.json({ error: 'Not your tool approval' }, { status: 403 })
+      }
+      if (result === 'not_found') {
+        return NextResponse.json({ error: 'Tool approval not found or already answered' }, { status: 404 })
+      }
+      return NextResponse.json({ ok: true })
+    }),
+  ),
+)
@@ -0,0 +1,15 @@
+import { NextResponse } from 'next/server'
+import { withAuth, withPermission } from '@/lib/middleware/auth'
+import { refreshDashboardStats } from '@/lib/dashboard/stats'
+
+// POST /api/v1/dashboard/refresh — Recompute dashboard counters now instead of
+// waiting for the next background refresh
+export const POST = withAuth(
+  withPermission('monitor:refresh_stats', async () => {
+    const scopes = await refreshDashboardStats()
+    if (scopes === null) {
+      return NextResponse.json({ error: 'Refresh already running' }, { status: 409 })
+    }
+    return NextResponse.json({ scopes, refreshedAt: new Date().toISOString() })
+  }),
+)
@@ -2,8 +2,9 @@ import { NextResponse } from 'next/server'
 import { prisma } from '@/lib/db'
 import { withAuth, withPermission } from '@/lib/middleware/auth'
 import { getDisplayName } from '@/lib/utils/display-name'
-import { getProvider } from '@/lib/resources/providers'
-import type { DashboardResponse, InstanceHealthCard, ProviderDistribution, RecentActivity } from '@/types/dashboard'
+import { getDashboardStats, REFRESH_INTERVAL_MS } from '@/lib/dashboard/stats'
+import { getDepartmentInstanceIds } from '@/lib/instances/access'
+import type { DashboardResponse, InstanceHealthCard, RecentActivity } from '@/types/dashboard'
 
 // GET /api/v1/dashboard — Dashboard aggregated stats
 export const GET = withAuth(
@@ -13,48 +14,11 @@ export const GET = withAuth(
     // DEPT_ADMIN: scope to accessible instances
     let instanceFilter: { id?: { in: string[] } } | undefined
     if (user.role === 'DEPT_ADMIN' && user.departmentId) {
-      const access = await prisma.instanceAccess.findMany({
-        where: { departmentId: user.departmentId },
-        select: { instanceId: true },
-      })
-      instanceFilter = { id: { in: access.map((a) => a.instanceId) } }
+      instanceFilter = { id: { in: await getDepartmentInstanceIds(user.departmentId) } }
     }
 
-    const [
-      totalInstances,
-      onlineInstances,
-      totalUsers,
-      activeUsers,
-      totalSessions,
-      totalResources,
-      totalSkills,
-      instances,
-      recentLogs,
-    ] = await Promise.all([
-      prisma.instance.count({ where: instanceFilter }),
-      prisma.instance.count({ where: { ...instanceFilter, status: 'ONLINE' } }),
-      user.role === 'SYSTEM_ADMIN'
-        ? prisma.user.count({ where: { status: 'ACTIVE' } })
-        : prisma.user.count({
-            where: { status: 'ACTIVE', departmentId: user.departmentId },
-          }),
-      user.role === 'SYSTEM_ADMIN'
-        ? prisma.user.count({
-            where: {
-              status: 'ACTIVE',
-              lastLoginAt: { gte: new Date(Date.now() - 7 * 86400000) },
-            },
-          })
-        : prisma.user.count({
-            where: {
-              status: 'ACTIVE',
-              departmentId: user.departmentId,
-              lastLoginAt: { gte: new Date(Date.now() - 7 * 86400000) },
-            },
-          }),
-      prisma.chatSession.count(),
-      user.role === 'SYSTEM_ADMIN' ? prisma.resource.count() : Promise.resolve(0),
-      prisma.skill.count(),
+    const [{ stats, providerDistribution, refreshedAt }, instances, recentLogs] = await Promise.all([
+      getDashboardStats(user),
       prisma.instance.findMany({
         where: instanceFilter,
         select: {
@@ -91,21 +55,6 @@ export const GET = withAuth(
       }
     })
 
-    // Build provider distribution (SYSTEM_ADMIN only)
-    let providerDistribution: ProviderDistribution[] = []
-    if (user.role === 'SYSTEM_ADMIN') {
-      const grouped = await prisma.resource.groupBy({
-        by: ['provider'],
-        _count: { id: true },
-        orderBy: { _count: { id: 'desc' } },
-      })
-      providerDistribution = grouped.map((g) => ({
-        provider: g.provider,
-        providerName: getProvider(g.provider)?.name ?? g.provider,
-        count: g._count.id,
-      }))
-    }
-
     // Build recent activity
     const recentActivity: RecentActivity[] = recentLogs.map((log) => ({
       id: log.id,
@@ -119,20 +68,17 @@ export const GET = withAuth(
     }))
 
     const response: DashboardResponse = {
-      stats: {
-        totalInstances,
-        onlineInstances,
-        totalUsers,
-        activeUsers,
-        totalSessions,
-        totalResources,
-        totalSkills,
-      },
+      stats,
+      statsRefreshedAt: refreshedAt.toISOString(),
       instanceHealth,
       providerDistribution,
       recentActivity,
     }
 
-    return NextResponse.json(response)
+    // Counters only change when the refresh job runs
+    const maxAge = Math.max(0, Math.floor((refreshedAt.getTime() + REFRESH_INTERVAL_MS - Date.now()) / 1000))
+    return NextResponse.json(response, {
+      headers: { 'Cache-Control': `private, max-age=${maxAge}` },
+    })
   }),
 )
@@ -0,0 +1,77 @@
+import { NextResponse } from 'next/server'
+import { prisma } from '@/lib/db'
+import { withAuth, withPermission, withValidation, param } from '@/lib/middleware/auth'
+import type { AuthContext } from '@/lib/middleware/auth'
+import { reviewJoinRequestSchema } from '@/lib/validations/department'
+import { auditLog } from '@/lib/audit'
+import {
+  JOIN_REQUEST_INCLUDE,
+  approveJoinRequest,
+  canReview,
+  toJoinRequestResponse,
+} from '@/lib/users/join-requests'
+
+// PUT /api/v1/department-join-requests/[id] — Approve (assigns the department) or reject
+export const PUT = withAuth(
+  withPermission(
+    'departments:review_joins',
+    withValidation(reviewJoinRequestSchema, async (req, ctx) => {
+      const { user, body } = ctx as {
+        user: NonNullable<typeof ctx.user>
+        body: typeof ctx.body
+      }
+      const id = param(ctx as unknown as AuthContext, 'id')
+
+      const existing = await prisma.departmentJoinRequest.findUnique({ where: { id } })
+      if (!existing || !canReview(user, existing)) {
+        return NextResponse.json({ error: 'Join request not found' }, { status: 404 })
+      }
+      if (existing.status !== 'PENDING') {
+        return NextResponse.json({ error: `Join request is already ${existing.status.toLowerCase()}` }, { status: 409 })
+      }
+
+      const comment = body.comment || null
+      let request
+      if (body.decision === 'approve') {
+        request = await approveJoinRequest(existing, user.id, comment)
+        if (!request) {
+          return NextResponse.json({ error: 'The user already belongs to a department' }, { status: 409 })
+        }
+      } else {
+        request = await prisma.departmentJoinRequest.update({
+          where: { id },
+          data: { status: 'REJECTED', reviewedById: user.id, reviewComment: comment, reviewedAt: new Date() },
+          include: JOIN_REQUEST_INCLUDE,
+        })
+      }
+
+      auditLog({
+        userId: user.id,
+        action: body.decision === 'approve' ? 'DEPARTMENT_JOIN_APPROVE' : 'DEPARTMENT_JOIN_REJECT',
+        resource: 'user',
+        resourceId: existing.userId,
+        details: { requestId: id, departmentId: existing.departmentId, departmentName: request.department.name },
+        ipAddress: req.headers.get('x-forwarded-for') || 'unknown',
+        userAgent: req.headers.get('user-agent') || undefined,
+        result: 'SUCCESS',
+      })
+
+      return NextResponse.json({ request: toJoinRequestResponse(request) })
+    }),
+  ),
+)
+
+// DELETE /api/v1/department-join-requests/[id] — Withdraw one's own pending request
+export const DELETE = withAuth(async (_req, ctx) => {
+  const id = param(ctx, 'id')
+  const existing = await prisma.departmentJoinRequest.findUnique({ where: { id } })
+  if (!existing || existing.userId !== ctx.user.id) {
+    return NextResponse.json({ error: 'Join request not found' }, { status: 404 })
+  }
+  if (existing.status !== 'PENDING') {
+    return NextResponse.json({ error: `Join request is already ${existing.status.toLowerCase()}` }, { status: 409 })
+  }
+
+  await prisma.departmentJoinRequest.update({ where: { id }, data: { status: 'WITHDRAWN' } })
+  return NextResponse.json({ success: true })
+})
@@ -0,0 +1,20 @@
+import { NextResponse } from 'next/server'
+import { prisma } from '@/lib/db'
+import { withAuth } from '@/lib/middleware/auth'
+import { iconUrl } from '@/lib/files/icons'
+
+// GET /api/v1/department-join-requests/departments — Departments a user can ask to join
+export const GET = withAuth(async () => {
+  const departments = await prisma.department.findMany({
+    select: { id: true, name: true, description: true, iconFileId: true },
+    orderBy: { name: 'asc' },
+  })
+  return NextResponse.json({
+    departments: departments.map((d) => ({
+      id: d.id,
+      name: d.name,
+      description: d.description,
+      iconUrl: iconUrl(d.iconFileId),
+    })),
+  })
+})
@@ -0,0 +1,81 @@
+import { NextResponse } from 'next/server'
+import { prisma } from '@/lib/db'
+import { withAuth, withValidation } from '@/lib/middleware/auth'
+import { createJoinRequestSchema } from '@/lib/validations/department'
+import { auditLog } from '@/lib/audit'
+import {
+  JOIN_REQUEST_INCLUDE,
+  announceJoinRequest,
+  reviewableWhere,
+  toJoinRequestResponse,
+} from '@/lib/users/join-requests'
+import type { JoinRequestStatus, Prisma } from '@/generated/prisma'
+
+const STATUSES: JoinRequestStatus[] = ['PENDING', 'APPROVED', 'REJECTED', 'WITHDRAWN']
+
+// GET /api/v1/department-join-requests?status= — Own requests, plus those the user may review
+export const GET = withAuth(async (req, ctx) => {
+  const status = new URL(req.url).searchParams.get('status')
+  if (status && !STATUSES.includes(status as JoinRequestStatus)) {
+    return NextResponse.json({ error: 'Invalid status' }, { status: 400 })
+  }
+
+  const reviewable = reviewableWhere(ctx.user)
+  const where: Prisma.DepartmentJoinRequestWhereInput = {
+    OR: [{ userId: ctx.user.id }, ...(reviewable ? [reviewable] : [])],
+    ...(status ? { status: status as JoinRequestStatus } : {}),
+  }
+  const requests = await prisma.departmentJoinRequest.findMany({
+    where,
+    include: JOIN_REQUEST_INCLUDE,
+    orderBy: { createdAt: 'desc' },
+    take: 200,
+  })
+  return NextResponse.json({ requests: requests.map(toJoinRequestResponse) })
+})
+
+// POST /api/v1/department-join-requests — Ask to join a department (users without one)
+export const POST = withAuth(
+  withValidation(createJoinRequestSchema, async (req, ctx) => {
+    const { user, body } = ctx as {
+      user: NonNullable<typeof ctx.user>
+      body: typeof ctx.body
+    }
+
+    if (user.departmentId) {
+      return NextResponse.json({ error: 'You already belong to a department' }, { status: 400 })
+    }
+    if (user.apiKey) {
+      return NextResponse.json({ error: 'API keys cannot request department membership' }, { status: 403 })
+    }
+    const department = await prisma.department.findUnique({ where: { id: body.departmentId }, select: { id: true } })
+    if (!department) {
+      return NextResponse.json({ error: 'Department not found' }, { status: 404 })
+    }
+    const pending = await prisma.departmentJoinRequest.findFirst({
+      where: { userId: user.id, departmentId: body.departmentId, status: 'PENDING' },
+    })
+    if (pending) {
+      return NextResponse.json({ error: 'A request to join this department is already pending' }, { status: 409 })
+    }
+
+    const request = await prisma.departmentJoinRequest.create({
+      data: { userId: user.id, departmentId: body.departmentId, message: body.message || null },
+      include: JOIN_REQUEST_INCLUDE,
+    })
+    announceJoinRequest(request)
+
+    auditLog({
+      userId: user.id,
+      action: 'DEPARTMENT_JOIN_REQUEST',
+      resource: 'department',
+      resourceId: body.departmentId,
+      details: { requestId: request.id, departmentName: request.department.name },
+      ipAddress: req.headers.get('x-forwarded-for') || 'unknown',
+      userAgent: req.headers.get('user-agent') || undefined,
+      result: 'SUCCESS',
+    })
+
+    return NextResponse.json({ request: toJoinRequestResponse(request) }, { status: 201 })
+  }),
+)
@@ -0,0 +1,82 @@
+import { NextResponse } from 'next/server'
+import { prisma } from '@/lib/db'
+import { withAuth, withPermission, withValidation, param } from '@/lib/middleware/auth'
+import type { AuthContext } from '@/lib/middleware/auth'
+import { departmentChatDefaultsSchema } from '@/lib/validations/department'
+import { auditLog, diffForAudit } from '@/lib/audit'
+import { findInstanceAccess, grantAllowsAgent } from '@/lib/instances/access'
+import type { DepartmentChatDefaults } from '@/types/department'
+
+// ─── PUT /api/v1/departments/[id]/chat-defaults — Default agent + welcome prompt
+
+export const PUT = withAuth(
+  withPermission(
+    'departments:chat_defaults',
+    withValidation(departmentChatDefaultsSchema, async (req, ctx) => {
+      const { user, body } = ctx as {
+        user: NonNullable<typeof ctx.user>
+        body: typeof ctx.body
+      }
+      const id = param(ctx as unknown as AuthContext, 'id')
+
+      // DEPT_ADMIN can only configure their own department
+      if (user.role === 'DEPT_ADMIN' && user.departmentId !== id) {
+        return NextResponse.json({ error: 'No permission to modify other departments' }, { status: 403 })
+      }
+
+      const existing = await prisma.department.findUnique({ where: { id } })
+      if (!existing) {
+        return NextResponse.json({ error: 'Department not found' }, { status: 404 })
+      }
+
+      // The default agent must be reachable by the department's members
+      if (body.defaultInstanceId && body.defaultAgentId) {
+        const access = await findInstanceAccess(id, body.defaultInstanceId)
+        if (!access) {
+          return NextResponse.json(
+            { error: 'Department has no access to this instance' },
+            { status: 400 },
+          )
+        }
+        if (!grantAllowsAgent(access, body.defaultAgentId)) {
+          return NextResponse.json(
+            { error: 'Department has no access to this agent' },
+            { status: 400 },
+          )
+        }
+      }
+
+      const department = await prisma.department.update({
+        where: { id },
+        data: {
+          defaultInstanceId: body.defaultInstanceId,
+          defaultAgentId: body.defaultAgentId,
+          welcomePrompt: body.welcomePrompt?.trim() || null,
+        },
+      })
+
+      auditLog({
+        userId: user.id,
+        action: 'DEPARTMENT_CHAT_DEFAULTS_UPDATE',
+        resource: 'department',
+        resourceId: id,
+        details: { name: department.name },
+        changes: diffForAudit(existing, {
+          defaultInstanceId: department.defaultInstanceId,
+          defaultAgentId: department.defaultAgentId,
+          welcomePrompt: department.welcomePrompt,
+        }),
+        ipAddress: req.headers.get('x-forwarded-for') || 'unknown',
+        userAgent: req.headers.get('user-agent') || undefined,
+        result: 'SUCCESS',
+      })
+
+      const chatDefaults: DepartmentChatDefaults = {
+        defaultInstanceId: department.defaultInstanceId,
+        defaultAgentId: department.defaultAgentId,
+        welcomePrompt: department.welcomePrompt,
+      }
+      return NextResponse.json({ chatDefaults })
+    }),
+  ),
+)
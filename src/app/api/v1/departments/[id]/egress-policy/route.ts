@@ -0,0 +1,84 @@
+import { NextResponse } from 'next/server'
+import { prisma } from '@/lib/db'
+import { withAuth, withPermission, withValidation, param } from '@/lib/middleware/auth'
+import type { AuthContext } from '@/lib/middleware/auth'
+import { egressPolicySchema } from '@/lib/validations/department'
+import { auditLog, diffForAudit } from '@/lib/audit'
+import { toEgressPolicyResponse } from '@/lib/egress-policy'
+import { Prisma } from '@/generated/prisma'
+
+// ─── GET /api/v1/departments/[id]/egress-policy — Tool / domain policy ──
+
+export const GET = withAuth(
+  withPermission('departments:view', async (_req, ctx) => {
+    const id = param(ctx, 'id')
+
+    // DEPT_ADMIN can only view their own department's policy
+    if (ctx.user.role === 'DEPT_ADMIN' && ctx.user.departmentId !== id) {
+      return NextResponse.json({ error: 'No permission to view other department details' }, { status: 403 })
+    }
+
+    const department = await prisma.department.findUnique({
+      where: { id },
+      select: { id: true, egressPolicy: true },
+    })
+    if (!department) {
+      return NextResponse.json({ error: 'Department not found' }, { status: 404 })
+    }
+
+    return NextResponse.json({ egressPolicy: toEgressPolicyResponse(id, department.egressPolicy) })
+  }),
+)
+
+// ─── PUT /api/v1/departments/[id]/egress-policy — Replace the policy ──
+
+export const PUT = withAuth(
+  withPermission(
+    'departments:egress_policy',
+    withValidation(egressPolicySchema, async (req, ctx) => {
+      const { user, body } = ctx as {
+        user: NonNullable<typeof ctx.user>
+        body: typeof ctx.body
+      }
+      const id = param(ctx as unknown as AuthContext, 'id')
+
+      const department = await prisma.department.findUnique({
+        where: { id },
+        select: { name: true, egressPolicy: true },
+      })
+      if (!department) {
+        return NextResponse.json({ error: 'Department not found' }, { status: 404 })
+      }
+
+      // Json? columns: null clears a list back to "no limit"
+      const data = {
+        enabled: body.enabled,
+        allowedTools: body.allowedTools ?? Prisma.DbNull,
+        deniedTools: body.deniedTools ?? Prisma.DbNull,
+        allowedDomains: body.allowedDomains ?? Prisma.DbNull,
+        deniedDomains: body.deniedDomains ?? Prisma.DbNull,
+        updatedById: user.id,
+      }
+      const policy = await prisma.egressPolicy.upsert({
+        where: { departmentId: id },
+        create: { departmentId: id, ...data },
+        update: data,
+      })
+
+      const after = toEgressPolicyResponse(id, policy)
+      auditLog({
+        userId: user.id,
+        action: 'DEPARTMENT_EGRESS_POLICY_UPDATE',
+        resource: 'department',
+        resourceId: id,
+        details: { name: department.name },
+        changes: diffForAudit({ ...toEgressPolicyResponse(id, department.egressPolicy) }, body),
+        ipAddress: req.headers.get('x-forwarded-for') || 'unknown',
+        userAgent: req.headers.get('user-agent') || undefined,
+        result: 'SUCCESS',
+      })
+
+      return NextResponse.json({ egressPolicy: after })
+    }),
+  ),
+)
@@ -0,0 +1,53 @@
+import { NextResponse } from 'next/server'
+import { prisma } from '@/lib/db'
+import { withAuth, withPermission, withValidation, param } from '@/lib/middleware/auth'
+import type { AuthContext } from '@/lib/middleware/auth'
+import { setIconSchema } from '@/lib/validations/file'
+import { auditLog, diffForAudit } from '@/lib/audit'
+import { FileRejectedError } from '@/lib/files'
+import { iconUrl, resolveIconFile } from '@/lib/files/icons'
+
+// ─── PUT /api/v1/departments/[id]/icon — Set (or clear) the department icon
+
+export const PUT = withAuth(
+  withPermission(
+    'departments:manage',
+    withValidation(setIconSchema, async (req, ctx) => {
+      const { user, body } = ctx as {
+        user: NonNullable<typeof ctx.user>
+        body: typeof ctx.body
+      }
+      const id = param(ctx as unknown as AuthContext, 'id')
+
+      const existing = await prisma.department.findUnique({ where: { id }, select: { name: true, iconFileId: true } })
+      if (!existing) {
+        return NextResponse.json({ error: 'Department not found' }, { status: 404 })
+      }
+
+      if (body.fileId) {
+        try {
+          await resolveIconFile(user.id, body.fileId)
+        } catch (err) {
+          if (!(err instanceof FileRejectedError)) throw err
+          return NextResponse.json({ error: err.message }, { status: 400 })
+        }
+      }
+
+      await prisma.department.update({ where: { id }, data: { iconFileId: body.fileId } })
+
+      auditLog({
+        userId: user.id,
+        action: 'DEPARTMENT_ICON_UPDATE',
+        resource: 'department',
+        resourceId: id,
+        details: { name: existing.name },
+        changes: diffForAudit(existing, { iconFileId: body.fileId }),
+        ipAddress: req.headers.get('x-forwarded-for') || 'unknown',
+        userAgent: req.headers.get('user-agent') || undefined,
+        result: 'SUCCESS',
+      })
+
+      return NextResponse.json({ iconUrl: iconUrl(body.fileId) })
+    }),
+  ),
+)
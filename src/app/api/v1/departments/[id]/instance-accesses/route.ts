@@ -0,0 +1,168 @@
+import { NextResponse } from 'next/server'
+import { prisma } from '@/lib/db'
+import { withAuth, withPermission, withValidation, param } from '@/lib/middleware/auth'
+import type { AuthContext } from '@/lib/middleware/auth'
+import { bulkGrantAccessSchema, bulkRevokeAccessSchema } from '@/lib/validations/instance-access'
+import { bulkGrantInstanceAccess, bulkRevokeInstanceAccess } from '@/lib/instances/access'
+import { auditLog } from '@/lib/audit'
+import { interceptForApproval } from '@/lib/approvals'
+import { findResidencyViolation, residencyErrorResponse } from '@/lib/instances/residency'
+
+// ─── POST /api/v1/departments/[id]/instance-accesses — Bulk grant ──
+// All grants are applied in one transaction, or none are.
+
+export const POST = withAuth(
+  withPermission(
+    'instance_access:manage',
+    withValidation(bulkGrantAccessSchema, async (req, ctx) => {
+      const { user, body } = ctx as {
+        user: NonNullable<typeof ctx.user>
+        body: typeof ctx.body
+      }
+      const id = param(ctx as unknown as AuthContext, 'id')
+
+      const department = await prisma.department.findUnique({ where: { id } })
+      if (!department) {
+        return NextResponse.json({ error: 'Department not found' }, { status: 404 })
+      }
+
+      const instanceIds = body.grants.map((g) => g.instanceId)
+      const instances = await prisma.instance.findMany({
+        where: { id: { in: instanceIds } },
+        select: { id: true, name: true },
+      })
+      const names = new Map(instances.map((i) => [i.id, i.name]))
+      const missing = instanceIds.filter((i) => !names.has(i))
+      if (missing.length > 0) {
+        return NextResponse.json({ error: 'Instance not found', instanceIds: missing }, { status: 404 })
+      }
+
+      const violation = await findResidencyViolation(id, instanceIds)
+      if (violation) return residencyErrorResponse(violation)
+
+      const pending = await interceptForApproval(req, {
+        action: 'INSTANCE_ACCESS_GRANT',
+        resource: 'instance_access',
+        payload: { departmentId: id, grants: body.grants },
+        summary: {
+          departmentName: department.name,
+          instanceName: [...names.values()].join(', '),
+          instanceCount: names.size,
+        },
+        requestedById: user.id,
+      })
+      if (pending) return pending
+
+      await bulkGrantInstanceAccess(id, body.grants, user.id)
+
+      const grants = await prisma.instanceAccess.findMany({
+        where: { departmentId: id, instanceId: { in: instanceIds } },
+        include: {
+          instance: { select: { name: true, status: true } },
+          grantedBy: { select: { name: true } },
+        },
+      })
+
+      for (const g of grants) {
+        auditLog({
+          userId: user.id,
+          action: 'INSTANCE_ACCESS_GRANT',
+          resource: 'instance_access',
+          resourceId: g.id,
+          details: {
+            departmentName: department.name,
+            instanceName: g.instance.name,
+            expiresAt: g.expiresAt?.toISOString() ?? null,
+            bulk: true,
+          },
+          ipAddress: req.headers.get('x-forwarded-for') || 'unknown',
+          userAgent: req.headers.get('user-agent') || undefined,
+          result: 'SUCCESS',
+        })
+      }
+
+      return NextResponse.json(
+        {
+          grants: grants.map((g) => ({
+            id: g.id,
+            departmentId: g.departmentId,
+            departmentName: department.name,
+            instanceId: g.instanceId,
+            instanceName: g.instance.name,
+            instanceStatus: g.instance.status,
+            agentIds: g.agentIds as string[] | null,
+            expiresAt: g.expiresAt?.toISOString() ?? null,
+            grantedByName: g.grantedBy.name,
+            createdAt: g.createdAt.toISOString(),
+            updatedAt: g.updatedAt.toISOString(),
+          })),
+        },
+        { status: 201 },
+      )
+    }),
+  ),
+)
+
+// ─── DELETE /api/v1/departments/[id]/instance-accesses — Bulk revoke
+// Body: { instanceIds }. Instances without a grant are ignored.
+
+export const DELETE = withAuth(
+  withPermission(
+    'instance_access:manage',
+    withValidation(bulkRevokeAccessSchema, async (req, ctx) => {
+      const { user, body } = ctx as {
+        user: NonNullable<typeof ctx.user>
+        body: typeof ctx.body
+      }
+      const id = param(ctx as unknown as AuthContext, 'id')
+
+      const department = await prisma.department.findUnique({ where: { id } })
+      if (!department) {
+        return NextResponse.json({ error: 'Department not found' }, { status: 404 })
+      }
+
+      const existing = await prisma.instanceAccess.findMany({
+        where: { departmentId: id, instanceId: { in: body.instanceIds } },
+        include: { instance: { select: { name: true } } },
+      })
+      if (existing.length === 0) {
+        return NextResponse.json({ revoked: 0 })
+      }
+
+      const pending = await interceptForApproval(req, {
+        action: 'INSTANCE_ACCESS_REVOKE',
+        resource: 'instance_access',
+        payload: { departmentId: id, instanceIds: existing.map((g) => g.instanceId) },
+        summary: {
+          departmentName: department.name,
+          instanceName: existing.map((g) => g.instance.name).join(', '),
+          instanceCount: existing.length,
+        },
+        requestedById: user.id,
+      })
+      if (pending) return pending
+
+      const revoked = await bulkRevokeInstanceAccess(id, existing.map((g) => g.instanceId))
+
+      for (const g of existing) {
+        auditLog({
+          userId: user.id,
+          action: 'INSTANCE_ACCESS_REVOKE',
+          resource: 'instance_access',
+          resourceId: g.id,
+          details: {
+            departmentName: department.name,
+            instanceName: g.instance.name,
+            expiresAt: g.expiresAt?.toISOString() ?? null,
+            bulk: true,
+          },
+          ipAddress: req.headers.get('x-forwarded-for') || 'unknown',
+          userAgent: req.headers.get('user-agent') || undefined,
+          result: 'SUCCESS',
+        })
+      }
+
+      return NextResponse.json({ revoked })
+    }),
+  ),
+)
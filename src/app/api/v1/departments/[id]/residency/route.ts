@@ -0,0 +1,98 @@
+import { NextResponse } from 'next/server'
+import { prisma } from '@/lib/db'
+import { withAuth, withPermission, withValidation, param } from '@/lib/middleware/auth'
+import type { AuthContext } from '@/lib/middleware/auth'
+import { departmentResidencySchema } from '@/lib/validations/department'
+import { auditLog, diffForAudit } from '@/lib/audit'
+import { parseAllowedRegions } from '@/lib/instances/residency'
+import { Prisma } from '@/generated/prisma'
+
+/** Granted instances outside the allowed regions (chat to them is refused) */
+async function blockedGrants(departmentId: string, allowedRegions: string[] | null) {
+  if (!allowedRegions) return []
+  const grants = await prisma.instanceAccess.findMany({
+    where: {
+      departmentId,
+      instance: { OR: [{ region: null }, { region: { notIn: allowedRegions } }] },
+    },
+    include: { instance: { select: { name: true, region: true } } },
+  })
+  return grants.map((g) => ({
+    grantId: g.id,
+    instanceId: g.instanceId,
+    instanceName: g.instance.name,
+    instanceRegion: g.instance.region,
+  }))
+}
+
+// ─── GET /api/v1/departments/[id]/residency — Allowed instance regions ──
+
+export const GET = withAuth(
+  withPermission('departments:view', async (_req, ctx) => {
+    const id = param(ctx, 'id')
+
+    // DEPT_ADMIN can only view their own department's rules
+    if (ctx.user.role === 'DEPT_ADMIN' && ctx.user.departmentId !== id) {
+      return NextResponse.json({ error: 'No permission to view other department details' }, { status: 403 })
+    }
+
+    const department = await prisma.department.findUnique({
+      where: { id },
+      select: { allowedRegions: true },
+    })
+    if (!department) {
+      return NextResponse.json({ error: 'Department not found' }, { status: 404 })
+    }
+
+    const allowedRegions = parseAllowedRegions(department.allowedRegions)
+    return NextResponse.json({ allowedRegions, blockedGrants: await blockedGrants(id, allowedRegions) })
+  }),
+)
+
+// ─── PUT /api/v1/departments/[id]/residency — Replace allowed regions ──
+// Existing grants outside the new regions are kept but refused at chat time;
+// they are listed in the response so they can be revoked.
+
+export const PUT = withAuth(
+  withPermission(
+    'departments:residency',
+    withValidation(departmentResidencySchema, async (req, ctx) => {
+      const { user, body } = ctx as {
+        user: NonNullable<typeof ctx.user>
+        body: typeof ctx.body
+      }
+      const id = param(ctx as unknown as AuthContext, 'id')
+
+      const department = await prisma.department.findUnique({
+        where: { id },
+        select: { name: true, allowedRegions: true },
+      })
+      if (!department) {
+        return NextResponse.json({ error: 'Department not found' }, { status: 404 })
+      }
+
+      const allowedRegions = body.allowedRegions ? [...new Set(body.allowedRegions)] : null
+      await prisma.department.update({
+        where: { id },
+        data: { allowedRegions: allowedRegions ?? Prisma.DbNull },
+      })
+
+      auditLog({
+        userId: user.id,
+        action: 'DEPARTMENT_RESIDENCY_UPDATE',
+        resource: 'department',
+        resourceId: id,
+        details: { name: department.name },
+        changes: diffForAudit(
+          { allowedRegions: parseAllowedRegions(department.allowedRegions) },
+          { allowedRegions },
+        ),
+        ipAddress: req.headers.get('x-forwarded-for') || 'unknown',
+        userAgent: req.headers.get('user-agent') || undefined,
+        result: 'SUCCESS',
+      })
+
+      return NextResponse.json({ allowedRegions, blockedGrants: await blockedGrants(id, allowedRegions) })
+    }),
+  ),
+)
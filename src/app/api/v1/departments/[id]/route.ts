@@ -2,7 +2,8 @@ import { NextResponse } from 'next/server'
 import { prisma } from '@/lib/db'
 import { withAuth, withPermission, withValidation, param } from '@/lib/middleware/auth'
 import { updateDepartmentSchema } from '@/lib/validations/department'
-import { auditLog } from '@/lib/audit'
+import { auditLog, diffForAudit } from '@/lib/audit'
+import { iconUrl } from '@/lib/files/icons'
 
 // ─── GET /api/v1/departments/[id] — Department detail ──────────────
 
@@ -58,10 +59,17 @@ export const GET = withAuth(
         id: department.id,
         name: department.name,
         description: department.description,
+        chatPriority: department.chatPriority,
+        iconUrl: iconUrl(department.iconFileId),
         userCount: department._count.users,
         accessCount: department._count.instanceAccess,
         createdAt: department.createdAt.toISOString(),
         updatedAt: department.updatedAt.toISOString(),
+        chatDefaults: {
+          defaultInstanceId: department.defaultInstanceId,
+          defaultAgentId: department.defaultAgentId,
+          welcomePrompt: department.welcomePrompt,
+        },
         users: department.users.map((u) => ({
           id: u.id,
           name: u.name,
@@ -76,6 +84,7 @@ export const GET = withAuth(
           instanceName: a.instance.name,
           instanceStatus: a.instance.status,
           agentIds: a.agentIds as string[] | null,
+          expiresAt: a.expiresAt?.toISOString() ?? null,
           grantedByName: a.grantedBy.name,
           createdAt: a.createdAt.toISOString(),
         })),
@@ -117,6 +126,7 @@ export const PUT = withAuth(
         data: {
           ...(body.name !== undefined ? { name: body.name } : {}),
           ...(body.description !== undefined ? { description: body.description } : {}),
+          ...(body.chatPriority !== undefined ? { chatPriority: body.chatPriority } : {}),
         },
         include: {
           _count: {
@@ -134,6 +144,11 @@ export const PUT = withAuth(
         resource: 'department',
         resourceId: id,
         details: { name: department.name },
+        changes: diffForAudit(existing, {
+          name: body.name,
+          description: body.description,
+          chatPriority: body.chatPriority,
+        }),
         ipAddress: req.headers.get('x-forwarded-for') || 'unknown',
         userAgent: req.headers.get('user-agent') || undefined,
         result: 'SUCCESS',
@@ -144,6 +159,8 @@ export const PUT = withAuth(
           id: department.id,
           name: department.name,
           description: department.description,
+          chatPriority: department.chatPriority,
+          iconUrl: iconUrl(department.iconFileId),
           userCount: department._count.users,
           accessCount: department._count.instanceAccess,
           createdAt: department.createdAt.toISOString(),
@@ -0,0 +1,85 @@
+import { NextResponse } from 'next/server'
+import { prisma } from '@/lib/db'
+import { withAuth, withPermission, withValidation, param } from '@/lib/middleware/auth'
+import type { AuthContext } from '@/lib/middleware/auth'
+import { updateDepartmentRedactionSchema } from '@/lib/validations/chat'
+import { auditLog, diffForAudit } from '@/lib/audit'
+import {
+  getToolRedactionConfig,
+  loadToolRedactionConfig,
+  saveDepartmentRedactionRules,
+} from '@/lib/chat/redaction'
+
+// ─── GET /api/v1/departments/[id]/tool-redaction — Department redaction rules ──
+
+export const GET = withAuth(
+  withPermission('departments:view', async (_req, ctx) => {
+    const id = param(ctx, 'id')
+
+    // DEPT_ADMIN can only view their own department's rules
+    if (ctx.user.role === 'DEPT_ADMIN' && ctx.user.departmentId !== id) {
+      return NextResponse.json({ error: 'No permission to view other department details' }, { status: 403 })
+    }
+
+    const department = await prisma.department.findUnique({ where: { id }, select: { id: true } })
+    if (!department) {
+      return NextResponse.json({ error: 'Department not found' }, { status: 404 })
+    }
+
+    const config = getToolRedactionConfig()
+    return NextResponse.json({
+      rules: config.departments[id] ?? [],
+      globalRules: config.rules,
+      builtin: config.builtin,
+    })
+  }),
+)
+
+// ─── PUT /api/v1/departments/[id]/tool-redaction — Replace department rules ──
+// Department rules only add to the global ones; they cannot switch any off.
+
+export const PUT = withAuth(
+  withPermission(
+    'departments:tool_redaction',
+    withValidation(updateDepartmentRedactionSchema, async (req, ctx) => {
+      const { user, body } = ctx as {
+        user: NonNullable<typeof ctx.user>
+        body: typeof ctx.body
+      }
+      const id = param(ctx as unknown as AuthContext, 'id')
+
+      // DEPT_ADMIN can only configure their own department
+      if (user.role === 'DEPT_ADMIN' && user.departmentId !== id) {
+        return NextResponse.json({ error: 'No permission to modify other departments' }, { status: 403 })
+      }
+
+      const department = await prisma.department.findUnique({ where: { id }, select: { name: true } })
+      if (!department) {
+        return NextResponse.json({ error: 'Department not found' }, { status: 404 })
+      }
+
+      await loadToolRedactionConfig()
+      const before = getToolRedactionConfig().departments[id] ?? []
+      await saveDepartmentRedactionRules(id, body.rules)
+
+      auditLog({
+        userId: user.id,
+        action: 'DEPARTMENT_TOOL_REDACTION_UPDATE',
+        resource: 'department',
+        resourceId: id,
+        details: { name: department.name, ruleCount: body.rules.length },
+        changes: diffForAudit({ rules: before }, body),
+        ipAddress: req.headers.get('x-forwarded-for') || 'unknown',
+        userAgent: req.headers.get('user-agent') || undefined,
+        result: 'SUCCESS',
+      })
+
+      const config = getToolRedactionConfig()
+      return NextResponse.json({
+        rules: config.departments[id] ?? [],
+        globalRules: config.rules,
+        builtin: config.builtin,
+      })
+    }),
+  ),
+)
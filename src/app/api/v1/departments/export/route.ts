@@ -0,0 +1,31 @@
+import { NextResponse } from 'next/server'
+import { withAuth, withPermission } from '@/lib/middleware/auth'
+import { parseExportFormat, queueExport, toExportJobResponse } from '@/lib/exports'
+import { auditLog } from '@/lib/audit'
+
+// POST /api/v1/departments/export?format=csv|xlsx — Departments with member counts
+// (DEPT_ADMIN: own department). Built in the background; poll
+// GET /api/v1/exports/[id] for the download.
+export const POST = withAuth(
+  withPermission('departments:view', async (req, { user }) => {
+    const format = parseExportFormat(new URL(req.url).searchParams.get('format'))
+    if (!format) {
+      return NextResponse.json({ error: 'format must be csv or xlsx' }, { status: 400 })
+    }
+
+    const job = await queueExport('departments', format, user)
+
+    auditLog({
+      userId: user.id,
+      action: 'DEPARTMENT_EXPORT',
+      resource: 'export',
+      resourceId: job.id,
+      details: { format, departmentId: job.departmentId },
+      ipAddress: req.headers.get('x-forwarded-for') || 'unknown',
+      userAgent: req.headers.get('user-agent') || undefined,
+      result: 'SUCCESS',
+    })
+
+    return NextResponse.json({ job: toExportJobResponse(job) }, { status: 202 })
+  }),
+)
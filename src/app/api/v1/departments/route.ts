@@ -3,6 +3,7 @@ import { prisma } from '@/lib/db'
 import { withAuth, withPermission, withValidation } from '@/lib/middleware/auth'
 import { createDepartmentSchema } from '@/lib/validations/department'
 import { auditLog } from '@/lib/audit'
+import { iconUrl } from '@/lib/files/icons'
 
 // ─── GET /api/v1/departments — List departments ────────────────────
 
@@ -25,6 +26,8 @@ export const GET = withAuth(
         id: d.id,
         name: d.name,
         description: d.description,
+        chatPriority: d.chatPriority,
+        iconUrl: iconUrl(d.iconFileId),
         userCount: d._count.users,
         accessCount: d._count.instanceAccess,
         createdAt: d.createdAt.toISOString(),
@@ -65,6 +68,7 @@ export const POST = withAuth(
         data: {
           name: body.name,
           description: body.description,
+          chatPriority: body.chatPriority,
         },
         include: {
           _count: {
@@ -93,6 +97,8 @@ export const POST = withAuth(
             id: department.id,
             name: department.name,
             description: department.description,
+            chatPriority: department.chatPriority,
+            iconUrl: null,
             userCount: department._count.users,
             accessCount: department._count.instanceAccess,
             createdAt: department.createdAt.toISOString(),
@@ -0,0 +1,113 @@
+import { NextResponse } from 'next/server'
+import { prisma } from '@/lib/db'
+import type { Prisma } from '@/generated/prisma'
+import { withAuth, withPermission, withValidation, param } from '@/lib/middleware/auth'
+import type { AuthContext } from '@/lib/middleware/auth'
+import { updateEvalSetSchema } from '@/lib/validations/eval'
+import { auditLog, diffForAudit } from '@/lib/audit'
+import { checkJudge, normalizeCases, toEvalSetResponse } from '@/lib/agents/evals'
+import type { EvalCase } from '@/types/agent'
+
+const include = {
+  createdBy: { select: { name: true } },
+  judgeResource: { select: { name: true } },
+} as const
+
+// GET /api/v1/evals/[id] — A golden set with its cases
+export const GET = withAuth(
+  withPermission('agents:manage', async (_req, ctx) => {
+    const id = param(ctx, 'id')
+
+    const set = await prisma.evalSet.findUnique({ where: { id }, include })
+    if (!set) {
+      return NextResponse.json({ error: 'Eval set not found' }, { status: 404 })
+    }
+
+    return NextResponse.json({ set: toEvalSetResponse(set) })
+  }),
+)
+
+// PUT /api/v1/evals/[id] — Rename, change the judge or replace the cases;
+// past runs keep the prompts they ran
+export const PUT = withAuth(
+  withPermission(
+    'agents:manage',
+    withValidation(updateEvalSetSchema, async (req, ctx) => {
+      const { user, body } = ctx as {
+        user: NonNullable<typeof ctx.user>
+        body: typeof ctx.body
+      }
+      const id = param(ctx as unknown as AuthContext, 'id')
+
+      const existing = await prisma.evalSet.findUnique({ where: { id } })
+      if (!existing) {
+        return NextResponse.json({ error: 'Eval set not found' }, { status: 404 })
+      }
+
+      const cases = body.cases ? normalizeCases(body.cases) : (existing.cases as unknown as EvalCase[])
+      const judgeResourceId = body.judgeResourceId !== undefined ? body.judgeResourceId : existing.judgeResourceId
+      const judgeError = await checkJudge(cases, judgeResourceId)
+      if (judgeError) {
+        return NextResponse.json({ error: judgeError }, { status: 400 })
+      }
+
+      const set = await prisma.evalSet.update({
+        where: { id },
+        data: {
+          name: body.name,
+          description: body.description,
+          judgeResourceId,
+          judgeModel: body.judgeModel,
+          ...(body.cases ? { cases: cases as unknown as Prisma.InputJsonValue } : {}),
+        },
+        include,
+      })
+
+      const { cases: _cases, ...fields } = body
+      auditLog({
+        userId: user.id,
+        action: 'EVAL_SET_UPDATE',
+        resource: 'eval_set',
+        resourceId: id,
+        details: { name: set.name, ...(body.cases ? { cases: cases.length } : {}) },
+        changes: diffForAudit(existing, fields),
+        ipAddress: req.headers.get('x-forwarded-for') || 'unknown',
+        userAgent: req.headers.get('user-agent') || undefined,
+        result: 'SUCCESS',
+      })
+
+      return NextResponse.json({ set: toEvalSetResponse(set) })
+    }),
+  ),
+)
+
+// DELETE /api/v1/evals/[id] — Remove a golden set and all its runs
+export const DELETE = withAuth(
+  withPermission('agents:manage', async (req, ctx) => {
+    const id = param(ctx, 'id')
+
+    const existing = await prisma.evalSet.findUnique({ where: { id } })
+    if (!existing) {
+      return NextResponse.json({ error: 'Eval set not found' }, { status: 404 })
+    }
+    const active = await prisma.evalRun.count({ where: { setId: id, status: { in: ['QUEUED', 'RUNNING'] } } })
+    if (active > 0) {
+      return NextResponse.json({ error: 'A run of this set is in progress' }, { status: 409 })
+    }
+
+    await prisma.evalSet.delete({ where: { id } })
+
+    auditLog({
+      userId: ctx.user.id,
+      action: 'EVAL_SET_DELETE',
+      resource: 'eval_set',
+      resourceId: id,
+      details: { name: existing.name },
+      ipAddress: req.headers.get('x-forwarded-for') || 'unknown',
+      userAgent: req.headers.get('user-agent') || undefined,
+      result: 'SUCCESS',
+    })
+
+    return NextResponse.json({ success: true })
+  }),
+)
@@ -0,0 +1,49 @@
+import { NextResponse } from 'next/server'
+import { prisma } from '@/lib/db'
+import { withAuth, withPermission, param } from '@/lib/middleware/auth'
+import { compareEvalRuns, findBaselineRun, toEvalRunResponse } from '@/lib/agents/evals'
+
+// GET /api/v1/evals/[id]/runs/[runId] — A run with its per-case results,
+// compared against ?baseline=<runId> (default: the agent's previous
+// completed run of the set)
+export const GET = withAuth(
+  withPermission('agents:manage', async (req, ctx) => {
+    const setId = param(ctx, 'id')
+    const runId = param(ctx, 'runId')
+
+    const run = await prisma.evalRun.findUnique({
+      where: { id: runId },
+      include: { createdBy: { select: { name: true } } },
+    })
+    if (!run || run.setId !== setId) {
+      return NextResponse.json({ error: 'Eval run not found' }, { status: 404 })
+    }
+
+    const baselineId = new URL(req.url).searchParams.get('baseline')
+    const baseline = baselineId
+      ? await prisma.evalRun.findFirst({ where: { id: baselineId, setId } })
+      : await findBaselineRun(run)
+    if (baselineId && !baseline) {
+      return NextResponse.json({ error: 'Baseline run not found' }, { status: 404 })
+    }
+
+    const results = await prisma.evalResult.findMany({
+      where: { runId },
+      orderBy: { createdAt: 'asc' },
+    })
+
+    return NextResponse.json({
+      run: toEvalRunResponse(run),
+      results: results.map((r) => ({
+        caseKey: r.caseKey,
+        prompt: r.prompt,
+        scorer: r.scorer,
+        reply: r.reply,
+        passed: r.passed,
+        reason: r.reason,
+        durationMs: r.durationMs,
+      })),
+      comparison: baseline && run.status === 'COMPLETED' ? await compareEvalRuns(run, baseline) : null,
+    })
+  }),
+)
@@ -0,0 +1,75 @@
+import { NextResponse } from 'next/server'
+import { prisma } from '@/lib/db'
+import { withAuth, withPermission, withValidation, param } from '@/lib/middleware/auth'
+import type { AuthContext } from '@/lib/middleware/auth'
+import { startEvalRunSchema } from '@/lib/validations/eval'
+import { auditLog } from '@/lib/audit'
+import { hasActiveEvalRun, startEvalRun, toEvalRunResponse } from '@/lib/agents/evals'
+import type { EvalCase } from '@/types/agent'
+
+// GET /api/v1/evals/[id]/runs — Runs of a golden set, newest first
+export const GET = withAuth(
+  withPermission('agents:manage', async (_req, ctx) => {
+    const id = param(ctx, 'id')
+
+    const runs = await prisma.evalRun.findMany({
+      where: { setId: id },
+      include: { createdBy: { select: { name: true } } },
+      orderBy: { createdAt: 'desc' },
+      take: 100,
+    })
+    return NextResponse.json({ runs: runs.map(toEvalRunResponse) })
+  }),
+)
+
+// POST /api/v1/evals/[id]/runs — Run the set against an agent; the run
+// proceeds in the background, poll it for progress
+export const POST = withAuth(
+  withPermission(
+    'agents:manage',
+    withValidation(startEvalRunSchema, async (req, ctx) => {
+      const { user, body } = ctx as {
+        user: NonNullable<typeof ctx.user>
+        body: typeof ctx.body
+      }
+      const id = param(ctx as unknown as AuthContext, 'id')
+
+      const set = await prisma.evalSet.findUnique({ where: { id } })
+      if (!set) {
+        return NextResponse.json({ error: 'Eval set not found' }, { status: 404 })
+      }
+      const instance = await prisma.instance.findUnique({ where: { id: body.instanceId }, select: { id: true } })
+      if (!instance) {
+        return NextResponse.json({ error: 'Instance not found' }, { status: 404 })
+      }
+      if (await hasActiveEvalRun(id, body.instanceId, body.agentId)) {
+        return NextResponse.json({ error: 'This set is already running against the agent' }, { status: 409 })
+      }
+
+      const run = await prisma.evalRun.create({
+        data: {
+          setId: id,
+          instanceId: body.instanceId,
+          agentId: body.agentId,
+          caseCount: (set.cases as unknown as EvalCase[]).length,
+          createdById: user.id,
+        },
+        include: { createdBy: { select: { name: true } } },
+      })
+      startEvalRun(run.id)
+
+      auditLog({
+        userId: user.id,
+        action: 'EVAL_RUN_START',
+        resource: 'eval_run',
+        resourceId: run.id,
+        details: { setId: id, setName: set.name, instanceId: body.instanceId, agentId: body.agentId },
+        ipAddress: req.headers.get('x-forwarded-for') || 'unknown',
+        userAgent: req.headers.get('user-agent') || undefined,
+        result: 'SUCCESS',
+      })
+
+      return NextResponse.json({ run: toEvalRunResponse(run) }, { status: 202 })
+    }),
+  ),
+)
@@ -0,0 +1,64 @@
+import { NextResponse } from 'next/server'
+import { prisma } from '@/lib/db'
+import type { Prisma } from '@/generated/prisma'
+import { withAuth, withPermission, withValidation } from '@/lib/middleware/auth'
+import { createEvalSetSchema } from '@/lib/validations/eval'
+import { auditLog } from '@/lib/audit'
+import { checkJudge, normalizeCases, toEvalSetResponse } from '@/lib/agents/evals'
+
+const include = {
+  createdBy: { select: { name: true } },
+  judgeResource: { select: { name: true } },
+} as const
+
+// GET /api/v1/evals — List golden sets
+export const GET = withAuth(
+  withPermission('agents:manage', async () => {
+    const sets = await prisma.evalSet.findMany({ include, orderBy: { createdAt: 'desc' } })
+    return NextResponse.json({ sets: sets.map(toEvalSetResponse) })
+  }),
+)
+
+// POST /api/v1/evals — Upload a golden set of prompt / expected-result cases
+export const POST = withAuth(
+  withPermission(
+    'agents:manage',
+    withValidation(createEvalSetSchema, async (req, ctx) => {
+      const { user, body } = ctx as {
+        user: NonNullable<typeof ctx.user>
+        body: typeof ctx.body
+      }
+
+      const cases = normalizeCases(body.cases)
+      const judgeError = await checkJudge(cases, body.judgeResourceId ?? null)
+      if (judgeError) {
+        return NextResponse.json({ error: judgeError }, { status: 400 })
+      }
+
+      const set = await prisma.evalSet.create({
+        data: {
+          name: body.name,
+          description: body.description,
+          cases: cases as unknown as Prisma.InputJsonValue,
+          judgeResourceId: body.judgeResourceId ?? null,
+          judgeModel: body.judgeModel ?? null,
+          createdById: user.id,
+        },
+        include,
+      })
+
+      auditLog({
+        userId: user.id,
+        action: 'EVAL_SET_CREATE',
+        resource: 'eval_set',
+        resourceId: set.id,
+        details: { name: set.name, cases: cases.length },
+        ipAddress: req.headers.get('x-forwarded-for') || 'unknown',
+        userAgent: req.headers.get('user-agent') || undefined,
+        result: 'SUCCESS',
+      })
+
+      return NextResponse.json({ set: toEvalSetResponse(set) }, { status: 201 })
+    }),
+  ),
+)
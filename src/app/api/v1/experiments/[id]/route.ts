@@ -0,0 +1,56 @@
+import { NextResponse } from 'next/server'
+import { prisma } from '@/lib/db'
+import { withAuth, withPermission, param } from '@/lib/middleware/auth'
+import { auditLog } from '@/lib/audit'
+import { experimentResults, toExperimentResponse } from '@/lib/agents/experiments'
+
+// GET /api/v1/experiments/[id] — An experiment with its results summary
+// (live while running, frozen once stopped)
+export const GET = withAuth(
+  withPermission('agents:manage', async (_req, ctx) => {
+    const id = param(ctx, 'id')
+
+    const experiment = await prisma.experiment.findUnique({
+      where: { id },
+      include: { createdBy: { select: { name: true } } },
+    })
+    if (!experiment) {
+      return NextResponse.json({ error: 'Experiment not found' }, { status: 404 })
+    }
+
+    return NextResponse.json({
+      experiment: toExperimentResponse(experiment),
+      results: await experimentResults(experiment),
+    })
+  }),
+)
+
+// DELETE /api/v1/experiments/[id] — Remove an experiment that is not running
+export const DELETE = withAuth(
+  withPermission('agents:manage', async (req, ctx) => {
+    const id = param(ctx, 'id')
+
+    const existing = await prisma.experiment.findUnique({ where: { id } })
+    if (!existing) {
+      return NextResponse.json({ error: 'Experiment not found' }, { status: 404 })
+    }
+    if (existing.status === 'RUNNING') {
+      return NextResponse.json({ error: 'Stop the experiment first' }, { status: 409 })
+    }
+
+    await prisma.experiment.delete({ where: { id } })
+
+    auditLog({
+      userId: ctx.user.id,
+      action: 'EXPERIMENT_DELETE',
+      resource: 'experiment',
+      resourceId: id,
+      details: { name: existing.name },
+      ipAddress: req.headers.get('x-forwarded-for') || 'unknown',
+      userAgent: req.headers.get('user-agent') || undefined,
+      result: 'SUCCESS',
+    })
+
+    return NextResponse.json({ success: true })
+  }),
+)
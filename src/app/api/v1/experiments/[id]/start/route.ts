@@ -0,0 +1,66 @@
+import { NextResponse } from 'next/server'
+import { prisma } from '@/lib/db'
+import { withAuth, withPermission, param } from '@/lib/middleware/auth'
+import { auditLog } from '@/lib/audit'
+import { startExperiment, toExperimentResponse } from '@/lib/agents/experiments'
+
+// POST /api/v1/experiments/[id]/start — Record agent versions, enable the canary split
+export const POST = withAuth(
+  withPermission('agents:manage', async (req, ctx) => {
+    const id = param(ctx, 'id')
+
+    const experiment = await prisma.experiment.findUnique({ where: { id } })
+    if (!experiment) {
+      return NextResponse.json({ error: 'Experiment not found' }, { status: 404 })
+    }
+    if (experiment.status !== 'DRAFT') {
+      return NextResponse.json({ error: 'Experiment was already started' }, { status: 409 })
+    }
+
+    const canary = experiment.canaryId
+      ? await prisma.agentCanary.findUnique({ where: { id: experiment.canaryId } })
+      : null
+    if (!canary) {
+      return NextResponse.json({ error: 'The experiment\'s canary split no longer exists' }, { status: 409 })
+    }
+
+    // One experiment per split at a time, and the split must be the one routing
+    const [running, otherSplit] = await Promise.all([
+      prisma.experiment.findFirst({ where: { canaryId: canary.id, status: 'RUNNING' }, select: { id: true } }),
+      prisma.agentCanary.findFirst({
+        where: {
+          id: { not: canary.id },
+          departmentId: canary.departmentId,
+          instanceId: canary.instanceId,
+          controlAgentId: canary.controlAgentId,
+          enabled: true,
+        },
+        select: { id: true },
+      }),
+    ])
+    if (running) {
+      return NextResponse.json({ error: 'Another experiment is running on this canary split' }, { status: 409 })
+    }
+    if (otherSplit) {
+      return NextResponse.json(
+        { error: 'A different canary split is enabled for this department and agent' },
+        { status: 409 },
+      )
+    }
+
+    const started = await startExperiment(experiment)
+
+    auditLog({
+      userId: ctx.user.id,
+      action: 'EXPERIMENT_START',
+      resource: 'experiment',
+      resourceId: id,
+      details: { name: experiment.name, canaryId: canary.id, percent: canary.percent },
+      ipAddress: req.headers.get('x-forwarded-for') || 'unknown',
+      userAgent: req.headers.get('user-agent') || undefined,
+      result: 'SUCCESS',
+    })
+
+    return NextResponse.json({ experiment: toExperimentResponse(started) })
+  }),
+)
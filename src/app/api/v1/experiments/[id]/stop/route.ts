@@ -0,0 +1,45 @@
+import { NextResponse } from 'next/server'
+import { prisma } from '@/lib/db'
+import { withAuth, withPermission, withValidation, param } from '@/lib/middleware/auth'
+import type { AuthContext } from '@/lib/middleware/auth'
+import { stopExperiment, toExperimentResponse } from '@/lib/agents/experiments'
+import { stopExperimentSchema } from '@/lib/validations/experiment'
+import { auditLog } from '@/lib/audit'
+
+// POST /api/v1/experiments/[id]/stop — Freeze the results; the canary split is
+// disabled unless { keepCanary: true } (e.g. to roll the change out further)
+export const POST = withAuth(
+  withPermission(
+    'agents:manage',
+    withValidation(stopExperimentSchema, async (req, ctx) => {
+      const { user, body } = ctx as {
+        user: NonNullable<typeof ctx.user>
+        body: typeof ctx.body
+      }
+      const id = param(ctx as unknown as AuthContext, 'id')
+
+      const experiment = await prisma.experiment.findUnique({ where: { id } })
+      if (!experiment) {
+        return NextResponse.json({ error: 'Experiment not found' }, { status: 404 })
+      }
+      if (experiment.status !== 'RUNNING') {
+        return NextResponse.json({ error: 'Experiment is not running' }, { status: 409 })
+      }
+
+      const stopped = await stopExperiment(experiment, body.keepCanary === true)
+
+      auditLog({
+        userId: user.id,
+        action: 'EXPERIMENT_STOP',
+        resource: 'experiment',
+        resourceId: id,
+        details: { name: experiment.name, keepCanary: body.keepCanary === true },
+        ipAddress: req.headers.get('x-forwarded-for') || 'unknown',
+        userAgent: req.headers.get('user-agent') || undefined,
+        result: 'SUCCESS',
+      })
+
+      return NextResponse.json({ experiment: toExperimentResponse(stopped), results: stopped.results })
+    }),
+  ),
+)
@@ -0,0 +1,61 @@
+import { NextResponse } from 'next/server'
+import { prisma } from '@/lib/db'
+import { withAuth, withPermission, withValidation } from '@/lib/middleware/auth'
+import { createExperimentSchema } from '@/lib/validations/experiment'
+import { auditLog } from '@/lib/audit'
+import { toExperimentResponse } from '@/lib/agents/experiments'
+
+// GET /api/v1/experiments — List experiments, newest first
+export const GET = withAuth(
+  withPermission('agents:manage', async () => {
+    const experiments = await prisma.experiment.findMany({
+      include: { createdBy: { select: { name: true } } },
+      orderBy: { createdAt: 'desc' },
+    })
+    return NextResponse.json({ experiments: experiments.map(toExperimentResponse) })
+  }),
+)
+
+// POST /api/v1/experiments — Define an experiment on a canary split; it starts with /start
+export const POST = withAuth(
+  withPermission(
+    'agents:manage',
+    withValidation(createExperimentSchema, async (req, ctx) => {
+      const { user, body } = ctx as {
+        user: NonNullable<typeof ctx.user>
+        body: typeof ctx.body
+      }
+
+      const canary = await prisma.agentCanary.findUnique({ where: { id: body.canaryId } })
+      if (!canary) {
+        return NextResponse.json({ error: 'Canary not found' }, { status: 404 })
+      }
+
+      const experiment = await prisma.experiment.create({
+        data: {
+          name: body.name,
+          hypothesis: body.hypothesis,
+          canaryId: canary.id,
+          instanceId: canary.instanceId,
+          controlAgentId: canary.controlAgentId,
+          canaryAgentId: canary.canaryAgentId,
+          createdById: user.id,
+        },
+        include: { createdBy: { select: { name: true } } },
+      })
+
+      auditLog({
+        userId: user.id,
+        action: 'EXPERIMENT_CREATE',
+        resource: 'experiment',
+        resourceId: experiment.id,
+        details: { name: experiment.name, canaryId: canary.id },
+        ipAddress: req.headers.get('x-forwarded-for') || 'unknown',
+        userAgent: req.headers.get('user-agent') || undefined,
+        result: 'SUCCESS',
+      })
+
+      return NextResponse.json({ experiment: toExperimentResponse(experiment) }, { status: 201 })
+    }),
+  ),
+)
@@ -0,0 +1,13 @@
+import { NextResponse } from 'next/server'
+import { prisma } from '@/lib/db'
+import { withAuth, param } from '@/lib/middleware/auth'
+import { toExportJobResponse } from '@/lib/exports'
+
+// GET /api/v1/exports/[id] — Status of one's own export, with a download URL once it completed
+export const GET = withAuth(async (_req, ctx) => {
+  const job = await prisma.exportJob.findUnique({ where: { id: param(ctx, 'id') } })
+  if (!job || job.createdById !== ctx.user.id) {
+    return NextResponse.json({ error: 'Export not found' }, { status: 404 })
+  }
+  return NextResponse.json({ job: toExportJobResponse(job) })
+})
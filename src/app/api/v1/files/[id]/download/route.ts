@@ -0,0 +1,36 @@
+import { NextRequest, NextResponse } from 'next/server'
+import { prisma } from '@/lib/db'
+import { readStoredFile, verifyFileSignature } from '@/lib/files'
+
+// GET /api/v1/files/[id]/download?expires=&sig= — Public: the signed URL
+// from the upload / file details is the credential
+export async function GET(req: NextRequest, { params }: { params: Promise<{ id: string }> }) {
+  const { id } = await params
+  const query = req.nextUrl.searchParams
+  if (!verifyFileSignature(id, query.get('expires'), query.get('sig'))) {
+    return NextResponse.json({ error: 'Invalid or expired link' }, { status: 403 })
+  }
+
+  const file = await prisma.storedFile.findUnique({ where: { id } })
+  if (!file) {
+    return NextResponse.json({ error: 'File not found' }, { status: 404 })
+  }
+
+  let data: Buffer
+  try {
+    data = await readStoredFile(file)
+  } catch {
+    return NextResponse.json({ error: 'File content is unavailable' }, { status: 404 })
+  }
+  // Inline only what the browser renders safely; everything else downloads
+  const inline = file.mimeType.startsWith('image/') && file.mimeType !== 'image/svg+xml'
+  return new NextResponse(new Uint8Array(data), {
+    headers: {
+      'Content-Type': file.mimeType,
+      'Content-Length': String(data.length),
+      'Content-Disposition': `${inline ? 'inline' : 'attachment'}; filename*=UTF-8''${encodeURIComponent(file.name)}`,
+      'X-Content-Type-Options': 'nosniff',
+      'Cache-Control': 'private, max-age=300',
+    },
+  })
+}
@@ -0,0 +1,27 @@
+import { NextResponse } from 'next/server'
+import { prisma } from '@/lib/db'
+import { withAuth, withPermission, param } from '@/lib/middleware/auth'
+import { deleteStoredFile, toFileResponse } from '@/lib/files'
+
+// GET /api/v1/files/[id] — An uploaded file's details with a fresh signed URL
+export const GET = withAuth(
+  withPermission('chat:use', async (_req, ctx) => {
+    const file = await prisma.storedFile.findUnique({ where: { id: param(ctx, 'id') } })
+    if (!file || file.userId !== ctx.user.id) {
+      return NextResponse.json({ error: 'File not found' }, { status: 404 })
+    }
+    return NextResponse.json({ file: toFileResponse(file) })
+  }),
+)
+
+// DELETE /api/v1/files/[id] — Delete an uploaded file
+export const DELETE = withAuth(
+  withPermission('chat:use', async (_req, ctx) => {
+    const file = await prisma.storedFile.findUnique({ where: { id: param(ctx, 'id') } })
+    if (!file || file.userId !== ctx.user.id) {
+      return NextResponse.json({ error: 'File not found' }, { status: 404 })
+    }
+    await deleteStoredFile(file)
+    return new NextResponse(null, { status: 204 })
+  }),
+)
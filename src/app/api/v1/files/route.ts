@@ -0,0 +1,29 @@
+import { NextResponse } from 'next/server'
+import { withAuth, withPermission } from '@/lib/middleware/auth'
+import { FileRejectedError, saveFile, toFileResponse } from '@/lib/files'
+import { createLogger } from '@/lib/logger'
+
+const log = createLogger('files')
+
+// POST /api/v1/files — Upload a file (multipart, field "file") to reference
+// from chat messages; returns its id and a signed download URL
+export const POST = withAuth(
+  withPermission('chat:use', async (req, ctx) => {
+    const formData = await req.formData().catch(() => null)
+    const file = formData?.get('file')
+    if (!(file instanceof File)) {
+      return NextResponse.json({ error: 'Missing file' }, { status: 400 })
+    }
+
+    try {
+      const stored = await saveFile(ctx.user.id, file)
+      return NextResponse.json({ file: toFileResponse(stored) }, { status: 201 })
+    } catch (err) {
+      if (err instanceof FileRejectedError) {
+        return NextResponse.json({ error: err.message }, { status: 400 })
+      }
+      log.error('Upload failed', { userId: ctx.user.id, error: (err as Error).message })
+      return NextResponse.json({ error: 'Upload failed' }, { status: 500 })
+    }
+  }),
+)
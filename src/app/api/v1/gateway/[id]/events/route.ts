@@ -0,0 +1,53 @@
+import { NextResponse } from 'next/server'
+import { prisma } from '@/lib/db'
+import { withAuth, withPermission, param } from '@/lib/middleware/auth'
+import { toGatewayEventResponse } from '@/lib/gateway/event-log'
+import type { Prisma } from '@/generated/prisma'
+
+const DEFAULT_LIMIT = 100
+const MAX_LIMIT = 500
+
+// GET /api/v1/gateway/[id]/events — Recorded gateway events of an instance,
+// newest first. Filters: ?event=chat|agent|shutdown|connection, ?state=,
+// ?runId=, ?sessionKey=, ?since= / ?before= (ISO times), ?limit= (max 500).
+// Page back with ?before=<nextBefore>.
+export const GET = withAuth(
+  withPermission('instances:manage', async (req, ctx) => {
+    const instanceId = param(ctx, 'id')
+    const query = new URL(req.url).searchParams
+
+    const instance = await prisma.instance.findUnique({ where: { id: instanceId }, select: { id: true } })
+    if (!instance) {
+      return NextResponse.json({ error: 'Instance not found' }, { status: 404 })
+    }
+
+    const since = query.get('since') ? new Date(query.get('since')!) : null
+    const before = query.get('before') ? new Date(query.get('before')!) : null
+    if ((since && isNaN(since.getTime())) || (before && isNaN(before.getTime()))) {
+      return NextResponse.json({ error: 'since and before must be ISO timestamps' }, { status: 400 })
+    }
+    const limit = Math.min(MAX_LIMIT, Math.max(1, parseInt(query.get('limit') ?? '', 10) || DEFAULT_LIMIT))
+
+    const where: Prisma.GatewayEventWhereInput = {
+      instanceId,
+      ...(query.get('event') ? { event: query.get('event')! } : {}),
+      ...(query.get('state') ? { state: query.get('state')! } : {}),
+      ...(query.get('runId') ? { runId: query.get('runId')! } : {}),
+      ...(query.get('sessionKey') ? { sessionKey: query.get('sessionKey')! } : {}),
+      ...(since || before
+        ? { createdAt: { ...(since ? { gte: since } : {}), ...(before ? { lt: before } : {}) } }
+        : {}),
+    }
+
+    const events = await prisma.gatewayEvent.findMany({
+      where,
+      orderBy: { createdAt: 'desc' },
+      take: limit,
+    })
+
+    return NextResponse.json({
+      events: events.map(toGatewayEventResponse),
+      nextBefore: events.length === limit ? events[events.length - 1].createdAt.toISOString() : null,
+    })
+  }),
+)
@@ -0,0 +1,110 @@
+import { NextRequest, NextResponse } from 'next/server'
+import { prisma } from '@/lib/db'
+import { auditLog } from '@/lib/audit'
+import { checkRateLimit } from '@/lib/redis'
+import {
+  decryptIntegrationSecret,
+  processIntegrationEvent,
+  verifyIntegrationRequest,
+} from '@/lib/integrations'
+import { renderTemplate } from '@/lib/utils/template'
+
+const MAX_BODY_BYTES = 256 * 1024
+
+// Requests per endpoint per minute, signed or not
+const RATE_LIMIT_PER_MINUTE = Number(process.env.INTEGRATION_RATE_LIMIT_PER_MINUTE) || 60
+
+const AUTH_FAILURES = {
+  invalid: { status: 401, error: 'Invalid signature' },
+  stale: { status: 401, error: 'Signature timestamp is outside the allowed window' },
+  replayed: { status: 409, error: 'Request was already received' },
+} as const
+
+// POST /api/v1/hooks/[slug] — Inbound integration event (public, secret-authenticated)
+// Accepted events are processed in the background; the response carries the
+// event and session IDs so the sender can correlate.
+export async function POST(
+  req: NextRequest,
+  { params }: { params: Promise<{ slug: string }> },
+) {
+  const { slug } = await params
+  const ip = req.headers.get('x-forwarded-for') || 'unknown'
+
+  const endpoint = await prisma.integrationEndpoint.findUnique({ where: { slug } })
+  if (!endpoint || !endpoint.enabled) {
+    return NextResponse.json({ error: 'Not found' }, { status: 404 })
+  }
+
+  const limit = await checkRateLimit(`integration:${endpoint.id}`, RATE_LIMIT_PER_MINUTE, 60)
+  if (!limit.allowed) {
+    return NextResponse.json(
+      { error: 'Too many requests' },
+      { status: 429, headers: { 'Retry-After': String(limit.resetAt - Math.floor(Date.now() / 1000)) } },
+    )
+  }
+
+  const rawBody = await req.text()
+  if (Buffer.byteLength(rawBody) > MAX_BODY_BYTES) {
+    return NextResponse.json({ error: 'Payload too large' }, { status: 413 })
+  }
+
+  const verdict = await verifyIntegrationRequest(
+    endpoint.id,
+    decryptIntegrationSecret(endpoint),
+    rawBody,
+    req.headers,
+  )
+  if (verdict !== 'ok') {
+    const failure = AUTH_FAILURES[verdict]
+    auditLog({
+      userId: endpoint.serviceAccountId,
+      action: 'INTEGRATION_TRIGGER',
+      resource: 'integration',
+      resourceId: endpoint.id,
+      details: { slug, reason: failure.error },
+      ipAddress: ip,
+      userAgent: req.headers.get('user-agent') || undefined,
+      result: 'FAILURE',
+    })
+    return NextResponse.json({ error: failure.error }, { status: failure.status })
+  }
+
+  let payload: unknown
+  try {
+    payload = rawBody ? JSON.parse(rawBody) : {}
+  } catch {
+    return NextResponse.json({ error: 'Invalid JSON body' }, { status: 400 })
+  }
+
+  const prompt = renderTemplate(endpoint.promptTemplate, payload).trim()
+  if (!prompt) {
+    return NextResponse.json({ error: 'Template rendered an empty prompt' }, { status: 422 })
+  }
+
+  const [event] = await prisma.$transaction([
+    prisma.integrationEvent.create({
+      data: { endpointId: endpoint.id, sourceIp: ip },
+    }),
+    prisma.integrationEndpoint.update({
+      where: { id: endpoint.id },
+      data: { lastTriggeredAt: new Date() },
+    }),
+  ])
+
+  processIntegrationEvent(endpoint, event.id, prompt).catch((err) =>
+    console.error(`[integrations] Event ${event.id} failed:`, err),
+  )
+
+  auditLog({
+    userId: endpoint.serviceAccountId,
+    action: 'INTEGRATION_TRIGGER',
+    resource: 'integration',
+    resourceId: endpoint.id,
+    details: { slug, eventId: event.id },
+    ipAddress: ip,
+    userAgent: req.headers.get('user-agent') || undefined,
+    result: 'SUCCESS',
+  })
+
+  return NextResponse.json({ eventId: event.id, status: 'accepted' }, { status: 202 })
+}
@@ -2,10 +2,12 @@ import { NextResponse } from 'next/server'
 import { prisma } from '@/lib/db'
 import { withAuth, withPermission, withValidation, param } from '@/lib/middleware/auth'
 import { updateAccessSchema } from '@/lib/validations/instance-access'
-import { auditLog } from '@/lib/audit'
+import { auditLog, diffForAudit } from '@/lib/audit'
+import { interceptForApproval } from '@/lib/approvals'
+import { expiryValue } from '@/lib/instances/access'
 import { Prisma } from '@/generated/prisma'
 
-// ─── PUT /api/v1/instance-access/[id] — Update agentIds ────────────
+// ─── PUT /api/v1/instance-access/[id] — Update agentIds / expiry ───
 
 export const PUT = withAuth(
   withPermission(
@@ -31,9 +33,11 @@ export const PUT = withAuth(
       const grant = await prisma.instanceAccess.update({
         where: { id },
         data: {
-          agentIds: body.agentIds !== null
-            ? (body.agentIds as unknown as Prisma.InputJsonValue)
-            : Prisma.DbNull,
+          agentIds: body.agentIds !== undefined
+            ? (body.agentIds as unknown as Prisma.InputJsonValue ?? Prisma.DbNull)
+            : undefined,
+          expiresAt: expiryValue(body.expiresAt),
+          expiryNotifiedAt: body.expiresAt !== undefined ? null : undefined,
         },
         include: {
           department: { select: { name: true } },
@@ -51,6 +55,10 @@ export const PUT = withAuth(
           departmentName: existing.department.name,
           instanceName: existing.instance.name,
         },
+        changes: diffForAudit(existing, {
+          agentIds: body.agentIds,
+          expiresAt: expiryValue(body.expiresAt),
+        }),
         ipAddress: req.headers.get('x-forwarded-for') || 'unknown',
         userAgent: req.headers.get('user-agent') || undefined,
         result: 'SUCCESS',
@@ -65,6 +73,7 @@ export const PUT = withAuth(
           instanceName: grant.instance.name,
           instanceStatus: grant.instance.status,
           agentIds: grant.agentIds as string[] | null,
+          expiresAt: grant.expiresAt?.toISOString() ?? null,
           grantedByName: grant.grantedBy.name,
           createdAt: grant.createdAt.toISOString(),
           updatedAt: grant.updatedAt.toISOString(),
@@ -93,6 +102,19 @@ export const DELETE = withAuth(
       return NextResponse.json({ error: 'Access grant not found' }, { status: 404 })
     }
 
+    const pending = await interceptForApproval(req, {
+      action: 'INSTANCE_ACCESS_REVOKE',
+      resource: 'instance_access',
+      resourceId: id,
+      payload: { grantId: id },
+      summary: {
+        departmentName: existing.department.name,
+        instanceName: existing.instance.name,
+      },
+      requestedById: user.id,
+    })
+    if (pending) return pending
+
     await prisma.instanceAccess.delete({ where: { id } })
 
     auditLog({
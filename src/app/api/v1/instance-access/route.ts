@@ -3,6 +3,9 @@ import { prisma } from '@/lib/db'
 import { withAuth, withPermission, withValidation } from '@/lib/middleware/auth'
 import { grantAccessSchema } from '@/lib/validations/instance-access'
 import { auditLog } from '@/lib/audit'
+import { interceptForApproval } from '@/lib/approvals'
+import { expiryValue } from '@/lib/instances/access'
+import { findResidencyViolation, residencyErrorResponse } from '@/lib/instances/residency'
 import { Prisma } from '@/generated/prisma'
 
 // ─── GET /api/v1/instance-access — List access grants ──────────────
@@ -36,6 +39,7 @@ export const GET = withAuth(
       instanceName: g.instance.name,
       instanceStatus: g.instance.status,
       agentIds: g.agentIds as string[] | null,
+      expiresAt: g.expiresAt?.toISOString() ?? null,
       grantedByName: g.grantedBy.name,
       createdAt: g.createdAt.toISOString(),
       updatedAt: g.updatedAt.toISOString(),
@@ -72,6 +76,27 @@ export const POST = withAuth(
         return NextResponse.json({ error: 'Instance not found' }, { status: 404 })
       }
 
+      const violation = await findResidencyViolation(body.departmentId, [body.instanceId])
+      if (violation) return residencyErrorResponse(violation)
+
+      const pending = await interceptForApproval(req, {
+        action: 'INSTANCE_ACCESS_GRANT',
+        resource: 'instance_access',
+        payload: {
+          departmentId: body.departmentId,
+          instanceId: body.instanceId,
+          agentIds: body.agentIds,
+          expiresAt: body.expiresAt,
+        },
+        summary: {
+          departmentName: department.name,
+          instanceName: instance.name,
+          expiresAt: body.expiresAt ?? null,
+        },
+        requestedById: user.id,
+      })
+      if (pending) return pending
+
       // Upsert on unique(departmentId, instanceId)
       const grant = await prisma.instanceAccess.upsert({
         where: {
@@ -84,6 +109,8 @@ export const POST = withAuth(
           agentIds: body.agentIds !== undefined
             ? (body.agentIds as unknown as Prisma.InputJsonValue ?? Prisma.DbNull)
             : undefined,
+          expiresAt: expiryValue(body.expiresAt),
+          expiryNotifiedAt: body.expiresAt !== undefined ? null : undefined,
           grantedById: user.id,
         },
         create: {
@@ -92,6 +119,7 @@ export const POST = withAuth(
           agentIds: body.agentIds != null
             ? (body.agentIds as unknown as Prisma.InputJsonValue)
             : undefined,
+          expiresAt: expiryValue(body.expiresAt),
           grantedById: user.id,
         },
         include: {
@@ -109,6 +137,7 @@ export const POST = withAuth(
         details: {
           departmentName: department.name,
           instanceName: instance.name,
+          expiresAt: grant.expiresAt?.toISOString() ?? null,
         },
         ipAddress: req.headers.get('x-forwarded-for') || 'unknown',
         userAgent: req.headers.get('user-agent') || undefined,
@@ -125,6 +154,7 @@ export const POST = withAuth(
             instanceName: grant.instance.name,
             instanceStatus: grant.instance.status,
             agentIds: grant.agentIds as string[] | null,
+            expiresAt: grant.expiresAt?.toISOString() ?? null,
             grantedByName: grant.grantedBy.name,
             createdAt: grant.createdAt.toISOString(),
             updatedAt: grant.updatedAt.toISOString(),
@@ -0,0 +1,57 @@
+import { NextResponse } from 'next/server'
+import { prisma } from '@/lib/db'
+import { withAuth, withPermission, param } from '@/lib/middleware/auth'
+import { auditLog } from '@/lib/audit'
+import { interceptForApproval } from '@/lib/approvals'
+
+// ─── DELETE /api/v1/instance-access/users/[id] — Revoke a user grant ─
+
+export const DELETE = withAuth(
+  withPermission('instance_access:manage', async (req, ctx) => {
+    const { user } = ctx
+    const id = param(ctx, 'id')
+
+    const existing = await prisma.userInstanceAccess.findUnique({
+      where: { id },
+      include: {
+        user: { select: { name: true } },
+        instance: { select: { name: true } },
+      },
+    })
+
+    if (!existing) {
+      return NextResponse.json({ error: 'Access grant not found' }, { status: 404 })
+    }
+
+    const pending = await interceptForApproval(req, {
+      action: 'INSTANCE_ACCESS_REVOKE',
+      resource: 'instance_access',
+      resourceId: id,
+      payload: { userGrantId: id },
+      summary: {
+        userName: existing.user.name,
+        instanceName: existing.instance.name,
+      },
+      requestedById: user.id,
+    })
+    if (pending) return pending
+
+    await prisma.userInstanceAccess.delete({ where: { id } })
+
+    auditLog({
+      userId: user.id,
+      action: 'INSTANCE_ACCESS_REVOKE',
+      resource: 'instance_access',
+      resourceId: id,
+      details: {
+        userName: existing.user.name,
+        instanceName: existing.instance.name,
+      },
+      ipAddress: req.headers.get('x-forwarded-for') || 'unknown',
+      userAgent: req.headers.get('user-agent') || undefined,
+      result: 'SUCCESS',
+    })
+
+    return new NextResponse(null, { status: 204 })
+  }),
+)
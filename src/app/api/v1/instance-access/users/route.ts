@@ -0,0 +1,104 @@
+import { NextResponse } from 'next/server'
+import { prisma } from '@/lib/db'
+import { withAuth, withPermission, withValidation } from '@/lib/middleware/auth'
+import { grantUserAccessSchema } from '@/lib/validations/instance-access'
+import { auditLog } from '@/lib/audit'
+import { interceptForApproval } from '@/lib/approvals'
+import { grantUserInstanceAccess, toUserGrantResponse, USER_GRANT_INCLUDE } from '@/lib/instances/access'
+import { findResidencyViolation, residencyErrorResponse } from '@/lib/instances/residency'
+import type { Prisma } from '@/generated/prisma'
+
+// ─── GET /api/v1/instance-access/users — List user grants ──────────
+
+export const GET = withAuth(
+  withPermission('instance_access:manage', async (req) => {
+    const url = new URL(req.url)
+    const userId = url.searchParams.get('userId')
+    const instanceId = url.searchParams.get('instanceId')
+
+    const where: Prisma.UserInstanceAccessWhereInput = {
+      ...(userId ? { userId } : {}),
+      ...(instanceId ? { instanceId } : {}),
+    }
+
+    const grants = await prisma.userInstanceAccess.findMany({
+      where,
+      include: USER_GRANT_INCLUDE,
+      orderBy: { createdAt: 'desc' },
+    })
+
+    return NextResponse.json({ grants: grants.map(toUserGrantResponse) })
+  }),
+)
+
+// ─── POST /api/v1/instance-access/users — Grant a user access ──────
+
+export const POST = withAuth(
+  withPermission(
+    'instance_access:manage',
+    withValidation(grantUserAccessSchema, async (req, ctx) => {
+      const { user, body } = ctx as {
+        user: NonNullable<typeof ctx.user>
+        body: typeof ctx.body
+      }
+
+      const grantee = await prisma.user.findUnique({
+        where: { id: body.userId },
+        select: { name: true, departmentId: true },
+      })
+      if (!grantee) {
+        return NextResponse.json({ error: 'User not found' }, { status: 404 })
+      }
+
+      const instance = await prisma.instance.findUnique({
+        where: { id: body.instanceId },
+      })
+      if (!instance) {
+        return NextResponse.json({ error: 'Instance not found' }, { status: 404 })
+      }
+
+      // The user's messages are still bound by their department's residency rules
+      if (grantee.departmentId) {
+        const violation = await findResidencyViolation(grantee.departmentId, [body.instanceId])
+        if (violation) return residencyErrorResponse(violation)
+      }
+
+      const pending = await interceptForApproval(req, {
+        action: 'INSTANCE_ACCESS_GRANT',
+        resource: 'instance_access',
+        payload: {
+          userId: body.userId,
+          instanceId: body.instanceId,
+          agentIds: body.agentIds,
+          expiresAt: body.expiresAt,
+        },
+        summary: {
+          userName: grantee.name,
+          instanceName: instance.name,
+          expiresAt: body.expiresAt ?? null,
+        },
+        requestedById: user.id,
+      })
+      if (pending) return pending
+
+      const grant = await grantUserInstanceAccess(body.userId, body, user.id)
+
+      auditLog({
+        userId: user.id,
+        action: 'INSTANCE_ACCESS_GRANT',
+        resource: 'instance_access',
+        resourceId: grant.id,
+        details: {
+          userName: grantee.name,
+          instanceName: instance.name,
+          expiresAt: grant.expiresAt?.toISOString() ?? null,
+        },
+        ipAddress: req.headers.get('x-forwarded-for') || 'unknown',
+        userAgent: req.headers.get('user-agent') || undefined,
+        result: 'SUCCESS',
+      })
+
+      return NextResponse.json({ grant: toUserGrantResponse(grant) }, { status: 201 })
+    }),
+  ),
+)
@@ -0,0 +1,91 @@
+import { NextResponse } from 'next/server'
+import { prisma } from '@/lib/db'
+import { stringList } from '@/lib/db-dialect'
+import { withAuth, withPermission, withValidation, param } from '@/lib/middleware/auth'
+import type { AuthContext } from '@/lib/middleware/auth'
+import { updateDelegationSchema } from '@/lib/validations/instance-access'
+import { auditLog, diffForAudit } from '@/lib/audit'
+import { delegationInclude, toDelegationResponse } from '@/lib/instances/delegation'
+
+// ─── PUT /api/v1/instance-delegations/[id] — Replace delegated actions
+
+export const PUT = withAuth(
+  withPermission(
+    'instance_access:manage',
+    withValidation(updateDelegationSchema, async (req, ctx) => {
+      const { user, body } = ctx as {
+        user: NonNullable<typeof ctx.user>
+        body: typeof ctx.body
+      }
+      const id = param(ctx as unknown as AuthContext, 'id')
+
+      const existing = await prisma.instanceDelegation.findUnique({
+        where: { id },
+        include: delegationInclude,
+      })
+      if (!existing) {
+        return NextResponse.json({ error: 'Delegation not found' }, { status: 404 })
+      }
+
+      const actions = [...new Set(body.actions)]
+      const delegation = await prisma.instanceDelegation.update({
+        where: { id },
+        data: { actions, grantedById: user.id },
+        include: delegationInclude,
+      })
+
+      auditLog({
+        userId: user.id,
+        action: 'INSTANCE_DELEGATION_UPDATE',
+        resource: 'instance_delegation',
+        resourceId: id,
+        details: {
+          departmentName: existing.department.name,
+          instanceName: existing.instance.name,
+        },
+        changes: diffForAudit(existing, { actions }),
+        ipAddress: req.headers.get('x-forwarded-for') || 'unknown',
+        userAgent: req.headers.get('user-agent') || undefined,
+        result: 'SUCCESS',
+      })
+
+      return NextResponse.json({ delegation: toDelegationResponse(delegation) })
+    }),
+  ),
+)
+
+// ─── DELETE /api/v1/instance-delegations/[id] — Revoke delegation ──
+
+export const DELETE = withAuth(
+  withPermission('instance_access:manage', async (req, ctx) => {
+    const { user } = ctx
+    const id = param(ctx, 'id')
+
+    const existing = await prisma.instanceDelegation.findUnique({
+      where: { id },
+      include: delegationInclude,
+    })
+    if (!existing) {
+      return NextResponse.json({ error: 'Delegation not found' }, { status: 404 })
+    }
+
+    await prisma.instanceDelegation.delete({ where: { id } })
+
+    auditLog({
+      userId: user.id,
+      action: 'INSTANCE_DELEGATION_REVOKE',
+      resource: 'instance_delegation',
+      resourceId: id,
+      details: {
+        departmentName: existing.department.name,
+        instanceName: existing.instance.name,
+        actions: stringList(existing.actions).join(','),
+      },
+      ipAddress: req.headers.get('x-forwarded-for') || 'unknown',
+      userAgent: req.headers.get('user-agent') || undefined,
+      result: 'SUCCESS',
+    })
+
+    return new NextResponse(null, { status: 204 })
+  }),
+)
@@ -0,0 +1,95 @@
+import { NextResponse } from 'next/server'
+import { prisma } from '@/lib/db'
+import { withAuth, withPermission, withValidation } from '@/lib/middleware/auth'
+import { delegateInstanceSchema } from '@/lib/validations/instance-access'
+import { auditLog } from '@/lib/audit'
+import { delegationInclude, toDelegationResponse } from '@/lib/instances/delegation'
+
+// ─── GET /api/v1/instance-delegations — List container delegations ──
+
+export const GET = withAuth(
+  withPermission('instance_access:manage', async (req) => {
+    const url = new URL(req.url)
+    const departmentId = url.searchParams.get('departmentId')
+    const instanceId = url.searchParams.get('instanceId')
+
+    const delegations = await prisma.instanceDelegation.findMany({
+      where: {
+        ...(departmentId ? { departmentId } : {}),
+        ...(instanceId ? { instanceId } : {}),
+      },
+      include: delegationInclude,
+      orderBy: { createdAt: 'desc' },
+    })
+
+    return NextResponse.json({ delegations: delegations.map(toDelegationResponse) })
+  }),
+)
+
+// ─── POST /api/v1/instance-delegations — Delegate container actions ─
+
+export const POST = withAuth(
+  withPermission(
+    'instance_access:manage',
+    withValidation(delegateInstanceSchema, async (req, ctx) => {
+      const { user, body } = ctx as {
+        user: NonNullable<typeof ctx.user>
+        body: typeof ctx.body
+      }
+
+      const department = await prisma.department.findUnique({
+        where: { id: body.departmentId },
+      })
+      if (!department) {
+        return NextResponse.json({ error: 'Department not found' }, { status: 404 })
+      }
+
+      const instance = await prisma.instance.findUnique({
+        where: { id: body.instanceId },
+      })
+      if (!instance) {
+        return NextResponse.json({ error: 'Instance not found' }, { status: 404 })
+      }
+
+      const actions = [...new Set(body.actions)]
+
+      // Upsert on unique(departmentId, instanceId)
+      const delegation = await prisma.instanceDelegation.upsert({
+        where: {
+          departmentId_instanceId: {
+            departmentId: body.departmentId,
+            instanceId: body.instanceId,
+          },
+        },
+        update: { actions, grantedById: user.id },
+        create: {
+          departmentId: body.departmentId,
+          instanceId: body.instanceId,
+          actions,
+          grantedById: user.id,
+        },
+        include: delegationInclude,
+      })
+
+      auditLog({
+        userId: user.id,
+        action: 'INSTANCE_DELEGATION_GRANT',
+        resource: 'instance_delegation',
+        resourceId: delegation.id,
+        details: {
+          departmentName: department.name,
+          instanceName: instance.name,
+          actions: actions.join(','),
+        },
+        ipAddress: req.headers.get('x-forwarded-for') || 'unknown',
+        userAgent: req.headers.get('user-agent') || undefined,
+        result: 'SUCCESS',
+      })
+
+      return NextResponse.json(
+        { delegation: toDelegationResponse(delegation) },
+        { status: 201 },
+      )
+    }),
+  ),
+)
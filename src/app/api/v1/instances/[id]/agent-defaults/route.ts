@@ -1,10 +1,10 @@
 import { NextResponse } from 'next/server'
-import { prisma } from '@/lib/db'
 import { withAuth, withPermission, withValidation } from '@/lib/middleware/auth'
 import { registry, ensureRegistryInitialized } from '@/lib/gateway/registry'
 import { updateAgentDefaultsSchema } from '@/lib/validations/agent'
 import { auditLog } from '@/lib/audit'
 import { extractAgentsConfig } from '@/lib/agents/helpers'
+import { canAccessInstance } from '@/lib/instances/access'
 
 // GET /api/v1/instances/[id]/agent-defaults — Read agents.defaults
 export const GET = withAuth(
@@ -13,16 +13,8 @@ export const GET = withAuth(
     const instanceId = params!.id as string
 
     // Non-admin users must have instance access
-    if (user.role !== 'SYSTEM_ADMIN') {
-      if (!user.departmentId) {
-        return NextResponse.json({ error: 'No access to this instance' }, { status: 403 })
-      }
-      const access = await prisma.instanceAccess.findUnique({
-        where: { departmentId_instanceId: { departmentId: user.departmentId, instanceId } },
-      })
-      if (!access) {
-        return NextResponse.json({ error: 'No access to this instance' }, { status: 403 })
-      }
+    if (!(await canAccessInstance(user, instanceId))) {
+      return NextResponse.json({ error: 'No access to this instance' }, { status: 403 })
     }
 
     const adapter = registry.getAdapter(instanceId)
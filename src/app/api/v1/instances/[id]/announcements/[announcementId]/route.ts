@@ -0,0 +1,41 @@
+import { NextResponse } from 'next/server'
+import { prisma } from '@/lib/db'
+import { withAuth, withPermission, param } from '@/lib/middleware/auth'
+import { auditLog } from '@/lib/audit'
+import { canControlInstance } from '@/lib/instances/delegation'
+
+// DELETE /api/v1/instances/[id]/announcements/[announcementId] — Take an announcement down early
+export const DELETE = withAuth(
+  withPermission('instances:control', async (req, ctx) => {
+    const id = param(ctx, 'id')
+    const announcementId = param(ctx, 'announcementId')
+
+    const announcement = await prisma.instanceAnnouncement.findUnique({ where: { id: announcementId } })
+    if (!announcement || announcement.instanceId !== id) {
+      return NextResponse.json({ error: 'Announcement not found' }, { status: 404 })
+    }
+    if (!(await canControlInstance(ctx.user, id, 'RESTART'))) {
+      return NextResponse.json({ error: 'Action not delegated for this instance' }, { status: 403 })
+    }
+
+    if (announcement.expiresAt > new Date()) {
+      await prisma.instanceAnnouncement.update({
+        where: { id: announcementId },
+        data: { expiresAt: new Date() },
+      })
+    }
+
+    auditLog({
+      userId: ctx.user.id,
+      action: 'INSTANCE_ANNOUNCE_END',
+      resource: 'instance',
+      resourceId: id,
+      details: { announcementId },
+      ipAddress: req.headers.get('x-forwarded-for') || 'unknown',
+      userAgent: req.headers.get('user-agent') || undefined,
+      result: 'SUCCESS',
+    })
+
+    return NextResponse.json({ success: true })
+  }),
+)
@@ -0,0 +1,73 @@
+import { NextResponse } from 'next/server'
+import { prisma } from '@/lib/db'
+import { withAuth, withPermission, withValidation, param } from '@/lib/middleware/auth'
+import type { AuthContext } from '@/lib/middleware/auth'
+import { createAnnouncementSchema } from '@/lib/validations/announcement'
+import { auditLog } from '@/lib/audit'
+import { canControlInstance } from '@/lib/instances/delegation'
+import { emitAnnouncement, toChatAnnouncement } from '@/lib/instances/announcements'
+
+// GET /api/v1/instances/[id]/announcements — Recent announcements, expired ones included
+export const GET = withAuth(
+  withPermission('instances:view', async (_req, ctx) => {
+    const id = param(ctx, 'id')
+
+    const announcements = await prisma.instanceAnnouncement.findMany({
+      where: { instanceId: id },
+      include: { createdBy: { select: { name: true } } },
+      orderBy: { createdAt: 'desc' },
+      take: 20,
+    })
+    return NextResponse.json({ announcements: announcements.map(toChatAnnouncement) })
+  }),
+)
+
+// POST /api/v1/instances/[id]/announcements — Announce to everyone chatting
+// with the instance; open chat streams get it immediately. Whoever may
+// restart the instance may announce it.
+export const POST = withAuth(
+  withPermission(
+    'instances:control',
+    withValidation(createAnnouncementSchema, async (req, ctx) => {
+      const { user, body } = ctx as {
+        user: NonNullable<typeof ctx.user>
+        body: typeof ctx.body
+      }
+      const id = param(ctx as unknown as AuthContext, 'id')
+
+      const instance = await prisma.instance.findUnique({ where: { id }, select: { id: true, name: true } })
+      if (!instance) {
+        return NextResponse.json({ error: 'Instance not found' }, { status: 404 })
+      }
+      if (!(await canControlInstance(user, id, 'RESTART'))) {
+        return NextResponse.json({ error: 'Action not delegated for this instance' }, { status: 403 })
+      }
+
+      const announcement = await prisma.instanceAnnouncement.create({
+        data: {
+          instanceId: id,
+          message: body.message,
+          level: body.level,
+          expiresAt: new Date(Date.now() + body.durationMinutes * 60_000),
+          createdById: user.id,
+        },
+        include: { createdBy: { select: { name: true } } },
+      })
+      const response = toChatAnnouncement(announcement)
+      emitAnnouncement(response)
+
+      auditLog({
+        userId: user.id,
+        action: 'INSTANCE_ANNOUNCE',
+        resource: 'instance',
+        resourceId: id,
+        details: { name: instance.name, level: body.level, durationMinutes: body.durationMinutes },
+        ipAddress: req.headers.get('x-forwarded-for') || 'unknown',
+        userAgent: req.headers.get('user-agent') || undefined,
+        result: 'SUCCESS',
+      })
+
+      return NextResponse.json({ announcement: response }, { status: 201 })
+    }),
+  ),
+)
@@ -0,0 +1,76 @@
+import { NextResponse } from 'next/server'
+import { prisma } from '@/lib/db'
+import { withAuth, withPermission, param } from '@/lib/middleware/auth'
+import { getDisplayName } from '@/lib/utils/display-name'
+import type { Prisma } from '@/generated/prisma'
+import type { AuditLogEntry, AuditLogListResponse } from '@/types/audit'
+
+const logInclude = { user: { select: { name: true, email: true } } } as const
+
+// Upper bound on rows scanned when filtering by a changed field
+const FIELD_SCAN_LIMIT = 1000
+
+// GET /api/v1/instances/[id]/audit — Change history of one instance
+// ?field=gatewayUrl narrows to entries that changed that field.
+export const GET = withAuth(
+  withPermission('audit:view_all', async (req, ctx) => {
+    const id = param(ctx, 'id')
+    const url = new URL(req.url)
+    const page = Math.max(1, parseInt(url.searchParams.get('page') || '1'))
+    const pageSize = Math.min(100, Math.max(1, parseInt(url.searchParams.get('pageSize') || '50')))
+    const field = url.searchParams.get('field')?.trim()
+
+    const where = { resource: 'instance', resourceId: id }
+
+    // Deleted instances keep their history, so only 404 when nothing is known
+    const instance = await prisma.instance.findUnique({ where: { id }, select: { id: true } })
+    if (!instance && !(await prisma.auditLog.findFirst({ where, select: { id: true } }))) {
+      return NextResponse.json({ error: 'Instance not found' }, { status: 404 })
+    }
+
+    let logs: Prisma.AuditLogGetPayload<{ include: typeof logInclude }>[]
+    let total: number
+    if (field) {
+      const scanned = await prisma.auditLog.findMany({
+        where,
+        include: logInclude,
+        orderBy: { createdAt: 'desc' },
+        take: FIELD_SCAN_LIMIT,
+      })
+      const matching = scanned.filter((log) => {
+        const changes = (log.details as Record<string, unknown> | null)?.changes
+        return !!changes && typeof changes === 'object' && field in changes
+      })
+      total = matching.length
+      logs = matching.slice((page - 1) * pageSize, page * pageSize)
+    } else {
+      ;[logs, total] = await Promise.all([
+        prisma.auditLog.findMany({
+          where,
+          include: logInclude,
+          orderBy: { createdAt: 'desc' },
+          skip: (page - 1) * pageSize,
+          take: pageSize,
+        }),
+        prisma.auditLog.count({ where }),
+      ])
+    }
+
+    const items: AuditLogEntry[] = logs.map((log) => ({
+      id: log.id,
+      userId: log.userId,
+      userName: getDisplayName(log.user),
+      action: log.action,
+      resource: log.resource,
+      resourceId: log.resourceId,
+      details: log.details as Record<string, unknown> | null,
+      ipAddress: log.ipAddress,
+      userAgent: log.userAgent,
+      result: log.result,
+      createdAt: log.createdAt.toISOString(),
+    }))
+
+    const response: AuditLogListResponse = { logs: items, total, page, pageSize }
+    return NextResponse.json(response)
+  }),
+)
@@ -2,6 +2,7 @@ import { NextResponse } from 'next/server'
 import { prisma } from '@/lib/db'
 import { withAuth, withPermission } from '@/lib/middleware/auth'
 import { registry, ensureRegistryInitialized } from '@/lib/gateway/registry'
+import { transitionInstanceStatus } from '@/lib/instances/status'
 import { dockerManager } from '@/lib/docker'
 import type { Prisma } from '@/generated/prisma'
 
@@ -45,14 +46,10 @@ export const GET = withAuth(
       }
 
       // Update DB with latest health data
-      await prisma.instance.update({
-        where: { id },
-        data: {
-          lastHealthCheck: new Date(),
-          healthData: healthData as Prisma.InputJsonValue,
-          version: version || undefined,
-          status: 'ONLINE',
-        },
+      await transitionInstanceStatus(id, 'checked_ok', {
+        lastHealthCheck: new Date(),
+        healthData: healthData as Prisma.InputJsonValue,
+        version: version || undefined,
       })
 
       return NextResponse.json({
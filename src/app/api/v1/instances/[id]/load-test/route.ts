@@ -0,0 +1,80 @@
+import { NextResponse } from 'next/server'
+import { prisma } from '@/lib/db'
+import { withAuth, withPermission, withValidation, param } from '@/lib/middleware/auth'
+import type { AuthContext } from '@/lib/middleware/auth'
+import { loadTestSchema } from '@/lib/validations/instance'
+import { startLoadTest, getLoadTest, cancelLoadTest } from '@/lib/instances/load-test'
+import { auditLog } from '@/lib/audit'
+
+// GET /api/v1/instances/[id]/load-test — Current or last load test with live stats
+export const GET = withAuth(
+  withPermission('instances:manage', async (_req, ctx) => {
+    return NextResponse.json({ job: getLoadTest(param(ctx, 'id')) })
+  }),
+)
+
+// POST /api/v1/instances/[id]/load-test — Start replaying synthetic conversations
+export const POST = withAuth(
+  withPermission(
+    'instances:manage',
+    withValidation(loadTestSchema, async (req, ctx) => {
+      const { user, body } = ctx as {
+        user: NonNullable<typeof ctx.user>
+        body: typeof ctx.body
+      }
+      const id = param(ctx as unknown as AuthContext, 'id')
+
+      const instance = await prisma.instance.findUnique({
+        where: { id },
+        select: { id: true, name: true },
+      })
+      if (!instance) {
+        return NextResponse.json({ error: 'Instance not found' }, { status: 404 })
+      }
+
+      let job
+      try {
+        job = await startLoadTest(id, body, user.id)
+      } catch (err) {
+        return NextResponse.json({ error: (err as Error).message }, { status: 409 })
+      }
+      if (!job) {
+        return NextResponse.json(
+          { error: 'A load test is already running on this instance' },
+          { status: 409 },
+        )
+      }
+
+      auditLog({
+        userId: user.id,
+        action: 'INSTANCE_LOAD_TEST',
+        resource: 'instance',
+        resourceId: id,
+        details: {
+          name: instance.name,
+          jobId: job.id,
+          agentId: body.agentId,
+          conversations: body.conversations,
+          concurrency: body.concurrency,
+          turnsPerConversation: body.turnsPerConversation,
+        },
+        ipAddress: req.headers.get('x-forwarded-for') || 'unknown',
+        userAgent: req.headers.get('user-agent') || undefined,
+        result: 'SUCCESS',
+      })
+
+      return NextResponse.json({ job }, { status: 202 })
+    }),
+  ),
+)
+
+// DELETE /api/v1/instances/[id]/load-test — Cancel the running load test
+export const DELETE = withAuth(
+  withPermission('instances:manage', async (_req, ctx) => {
+    const job = cancelLoadTest(param(ctx, 'id'))
+    if (!job) {
+      return NextResponse.json({ error: 'No load test running' }, { status: 404 })
+    }
+    return NextResponse.json({ job })
+  }),
+)
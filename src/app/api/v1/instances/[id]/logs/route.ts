@@ -2,6 +2,8 @@ import { NextResponse } from 'next/server'
 import { prisma } from '@/lib/db'
 import { withAuth, withPermission } from '@/lib/middleware/auth'
 import { dockerManager } from '@/lib/docker'
+import { canControlInstance } from '@/lib/instances/delegation'
+import { findInstanceAccess } from '@/lib/instances/access'
 
 // GET /api/v1/instances/[id]/logs — Container logs
 export const GET = withAuth(
@@ -15,12 +17,10 @@ export const GET = withAuth(
       return NextResponse.json({ error: 'Instance not found' }, { status: 404 })
     }
 
-    // DEPT_ADMIN must have instance access for their department
+    // DEPT_ADMIN must have instance access or a LOGS delegation for their department
     if (user.role === 'DEPT_ADMIN' && user.departmentId) {
-      const access = await prisma.instanceAccess.findUnique({
-        where: { departmentId_instanceId: { departmentId: user.departmentId, instanceId: id } },
-      })
-      if (!access) {
+      const access = await findInstanceAccess(user.departmentId, id)
+      if (!access && !(await canControlInstance(user, id, 'LOGS'))) {
         return NextResponse.json({ error: 'No access to this instance' }, { status: 403 })
       }
     }
@@ -3,12 +3,15 @@ import { prisma } from '@/lib/db'
 import { withAuth, withPermission } from '@/lib/middleware/auth'
 import { decrypt } from '@/lib/auth/encryption'
 import { registry, ensureRegistryInitialized, resolveGatewayUrl } from '@/lib/gateway/registry'
+import { transitionInstanceStatus } from '@/lib/instances/status'
 import { dockerManager } from '@/lib/docker'
 import { auditLog } from '@/lib/audit'
+import { canControlInstance } from '@/lib/instances/delegation'
+import { applyContainerConfig, initializeContainer } from '@/lib/instances/container-config'
 
 // POST /api/v1/instances/[id]/restart — Restart container + reconnect gateway
 export const POST = withAuth(
-  withPermission('instances:manage', async (req, { user, params }) => {
+  withPermission('instances:control', async (req, { user, params }) => {
     const id = params!.id as string
 
     const instance = await prisma.instance.findUnique({ where: { id } })
@@ -16,15 +19,28 @@ export const POST = withAuth(
       return NextResponse.json({ error: 'Instance not found' }, { status: 404 })
     }
 
+    if (!(await canControlInstance(user, id, 'RESTART'))) {
+      return NextResponse.json({ error: 'Action not delegated for this instance' }, { status: 403 })
+    }
+
     await ensureRegistryInitialized()
 
     // Disconnect gateway first
     await registry.disconnect(id)
 
     // Restart Docker container if managed
-    if (instance.containerId) {
+    let containerId = instance.containerId
+    if (containerId) {
       try {
-        await dockerManager.restartContainer(instance.containerId)
+        // Changed start parameters recreate the container, which then only needs starting
+        const container = await applyContainerConfig({ ...instance, containerId })
+        containerId = container.containerId
+        if (container.recreated) {
+          await dockerManager.startContainer(containerId)
+          await initializeContainer(containerId, container.profile, instance.name)
+        } else {
+          await dockerManager.restartContainer(containerId)
+        }
       } catch (err) {
         return NextResponse.json(
           { error: `Failed to restart container:${(err as Error).message}` },
@@ -43,19 +59,16 @@ export const POST = withAuth(
 
       // Extract version from Docker container OCI labels
       let version: string | undefined
-      if (instance.containerId) {
+      if (containerId) {
         try {
-          const info = await dockerManager.inspectContainer(instance.containerId)
+          const info = await dockerManager.inspectContainer(containerId)
           version = info.version
         } catch {
           // Non-fatal
         }
       }
 
-      await prisma.instance.update({
-        where: { id },
-        data: { status: 'ONLINE', ...(version ? { version } : {}) },
-      })
+      await transitionInstanceStatus(id, 'started', version ? { version } : {})
 
       auditLog({
         userId: user.id,
@@ -70,10 +83,7 @@ export const POST = withAuth(
 
       return NextResponse.json({ status: 'restarted' })
     } catch (err) {
-      await prisma.instance.update({
-        where: { id },
-        data: { status: 'ERROR' },
-      })
+      await transitionInstanceStatus(id, 'start_failed')
 
       auditLog({
         userId: user.id,
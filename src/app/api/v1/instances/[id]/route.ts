@@ -2,12 +2,40 @@ import { NextRequest, NextResponse } from 'next/server'
 import { prisma } from '@/lib/db'
 import { withAuth, withPermission, withValidation } from '@/lib/middleware/auth'
 import { updateInstanceSchema } from '@/lib/validations/instance'
-import { encrypt } from '@/lib/auth/encryption'
-import { auditLog } from '@/lib/audit'
-import { registry } from '@/lib/gateway/registry'
+import { decrypt, encrypt } from '@/lib/auth/encryption'
+import { auditLog, diffForAudit, REDACTED } from '@/lib/audit'
 import type { Prisma } from '@/generated/prisma'
-import { dockerManager } from '@/lib/docker'
-import { cleanupInstanceFiles } from '@/lib/docker/config-generator'
+import { destroyInstance } from '@/lib/instances/lifecycle'
+import { interceptForApproval } from '@/lib/approvals'
+import { resolveGatewayUrl } from '@/lib/gateway/registry'
+import { probeGateway } from '@/lib/gateway/handshake'
+import { assertDestinationAllowed, DestinationDeniedError } from '@/lib/destination-policy'
+import type { GatewayHandshakeResult } from '@/types/instance'
+
+type DockerEnv = Record<string, string>
+type DockerEnvConfig = { env?: DockerEnv; overrides?: Record<string, { env?: DockerEnv } | undefined> }
+
+/**
+ * Docker env values are often API keys and passwords: the audited config keeps
+ * the variable names only, and the values (top level and per-environment
+ * overrides) are compared separately as a secret field.
+ */
+function splitDockerEnv(config: unknown): { config: unknown; env: unknown } {
+  if (!config || typeof config !== 'object') return { config, env: undefined }
+  const c = config as DockerEnvConfig
+  const redact = (env?: DockerEnv) =>
+    env && Object.fromEntries(Object.keys(env).map((k) => [k, REDACTED]))
+  const overrides = c.overrides && Object.fromEntries(
+    Object.entries(c.overrides).map(([name, o]) => [name, o && { ...o, env: redact(o.env) }]),
+  )
+  return {
+    config: { ...c, env: redact(c.env), overrides },
+    env: {
+      env: c.env ?? null,
+      overrides: Object.fromEntries(Object.entries(c.overrides ?? {}).map(([name, o]) => [name, o?.env ?? null])),
+    },
+  }
+}
 
 // GET /api/v1/instances/[id] — Instance detail
 export const GET = withAuth(
@@ -18,6 +46,7 @@ export const GET = withAuth(
         id: true,
         name: true,
         description: true,
+        region: true,
         gatewayUrl: true,
         containerId: true,
         containerName: true,
@@ -68,13 +97,43 @@ export const PUT = withAuth(
         }
       }
 
+      if (body.gatewayUrl && body.gatewayUrl !== existing.gatewayUrl) {
+        try {
+          await assertDestinationAllowed(body.gatewayUrl)
+        } catch (err) {
+          if (err instanceof DestinationDeniedError) {
+            return NextResponse.json({ error: err.message }, { status: 400 })
+          }
+          throw err
+        }
+      }
+
+      // ?validate=true: handshake with the resulting URL / token before saving
+      let handshake: GatewayHandshakeResult | undefined
+      if (new URL(req.url).searchParams.get('validate') === 'true') {
+        const url = resolveGatewayUrl({
+          gatewayUrl: body.gatewayUrl ?? existing.gatewayUrl,
+          dockerConfig: existing.dockerConfig,
+        })
+        handshake = await probeGateway(url, body.gatewayToken ?? decrypt(existing.gatewayToken))
+        if (!handshake.ok) {
+          return NextResponse.json(
+            { error: `Gateway handshake failed: ${handshake.error!.message}`, handshake },
+            { status: 422 },
+          )
+        }
+      }
+
       const updateData: Prisma.InstanceUpdateInput = {}
       if (body.name !== undefined) updateData.name = body.name
       if (body.description !== undefined) updateData.description = body.description
+      if (body.region !== undefined) updateData.region = body.region
       if (body.gatewayUrl !== undefined) updateData.gatewayUrl = body.gatewayUrl
       if (body.gatewayToken !== undefined) updateData.gatewayToken = encrypt(body.gatewayToken)
       if (body.docker !== undefined) {
-        updateData.dockerConfig = body.docker as unknown as Prisma.InputJsonValue
+        // hostPort is assigned at creation and needed to recreate the container
+        const hostPort = (existing.dockerConfig as { hostPort?: number } | null)?.hostPort
+        updateData.dockerConfig = { ...body.docker, hostPort } as unknown as Prisma.InputJsonValue
         if (body.docker.imageName) updateData.imageName = body.docker.imageName
       }
 
@@ -85,6 +144,7 @@ export const PUT = withAuth(
           id: true,
           name: true,
           description: true,
+          region: true,
           gatewayUrl: true,
           containerId: true,
           containerName: true,
@@ -100,18 +160,38 @@ export const PUT = withAuth(
         },
       })
 
+      const dockerBefore = splitDockerEnv(existing.dockerConfig)
+      const dockerAfter = body.docker !== undefined ? splitDockerEnv(body.docker) : undefined
       auditLog({
         userId: user.id,
         action: 'INSTANCE_UPDATE',
         resource: 'instance',
         resourceId: id,
         details: { name: instance.name },
+        changes: diffForAudit(
+          {
+            ...existing,
+            dockerConfig: dockerBefore.config,
+            dockerEnv: dockerBefore.env,
+          },
+          {
+            name: body.name,
+            description: body.description,
+            region: body.region,
+            gatewayUrl: body.gatewayUrl,
+            gatewayToken: body.gatewayToken,
+            imageName: body.docker?.imageName,
+            dockerConfig: dockerAfter?.config,
+            dockerEnv: dockerAfter?.env,
+          },
+          ['gatewayToken', 'dockerEnv'],
+        ),
         ipAddress: req.headers.get('x-forwarded-for') || 'unknown',
         userAgent: req.headers.get('user-agent') || undefined,
         result: 'SUCCESS',
       })
 
-      return NextResponse.json({ instance })
+      return NextResponse.json({ instance, ...(handshake ? { handshake } : {}) })
     }),
   ),
 )
@@ -126,38 +206,24 @@ export const DELETE = withAuth(
       return NextResponse.json({ error: 'Instance not found' }, { status: 404 })
     }
 
-    // Disconnect from gateway
-    await registry.disconnect(id)
-
-    // Stop and remove container if managed
-    if (instance.containerId) {
-      try {
-        await dockerManager.stopContainer(instance.containerId)
-      } catch {
-        // Container may already be stopped
-      }
-      try {
-        await dockerManager.removeContainer(instance.containerId, true)
-      } catch {
-        // Container may not exist
-      }
-    }
-
-    await prisma.instance.delete({ where: { id } })
+    const pending = await interceptForApproval(req, {
+      action: 'INSTANCE_DELETE',
+      resource: 'instance',
+      resourceId: id,
+      payload: { instanceId: id },
+      summary: { name: instance.name },
+      requestedById: user.id,
+    })
+    if (pending) return pending
 
-    // Clean up host data directory
-    try {
-      await cleanupInstanceFiles(instance.name)
-    } catch {
-      // Non-fatal: log but don't fail the delete
-    }
+    const deleted = await destroyInstance(instance)
 
     auditLog({
       userId: user.id,
       action: 'INSTANCE_DELETE',
       resource: 'instance',
       resourceId: id,
-      details: { name: instance.name },
+      details: { name: instance.name, sessionsClosed: deleted.sessionsClosed, ...deleted.removed },
       ipAddress: req.headers.get('x-forwarded-for') || 'unknown',
       userAgent: req.headers.get('user-agent') || undefined,
       result: 'SUCCESS',
@@ -0,0 +1,58 @@
+import { NextResponse } from 'next/server'
+import { prisma } from '@/lib/db'
+import { withAuth, withPermission } from '@/lib/middleware/auth'
+import { smokeTestSchema } from '@/lib/validations/instance'
+import { runSmokeTest } from '@/lib/instances/smoke-test'
+import { auditLog } from '@/lib/audit'
+
+// POST /api/v1/instances/[id]/smoke-test — Scripted post-provisioning validation
+export const POST = withAuth(
+  withPermission('instances:manage', async (req, { user, params }) => {
+    const id = params!.id as string
+
+    // Body is optional: an empty POST runs with defaults
+    const raw = await req.text()
+    let body: unknown = {}
+    if (raw.trim()) {
+      try {
+        body = JSON.parse(raw)
+      } catch {
+        return NextResponse.json({ error: 'Invalid request body' }, { status: 400 })
+      }
+    }
+
+    const parsed = smokeTestSchema.safeParse(body)
+    if (!parsed.success) {
+      return NextResponse.json(
+        { error: 'Validation failed', details: parsed.error.issues },
+        { status: 400 },
+      )
+    }
+
+    const instance = await prisma.instance.findUnique({ where: { id } })
+    if (!instance) {
+      return NextResponse.json({ error: 'Instance not found' }, { status: 404 })
+    }
+
+    const report = await runSmokeTest(instance, parsed.data)
+    const failed = report.steps.filter((s) => s.status === 'fail').map((s) => s.name)
+
+    auditLog({
+      userId: user.id,
+      action: 'INSTANCE_SMOKE_TEST',
+      resource: 'instance',
+      resourceId: id,
+      details: {
+        name: instance.name,
+        agentId: report.agentId,
+        durationMs: report.durationMs,
+        failed: failed.join(',') || null,
+      },
+      ipAddress: req.headers.get('x-forwarded-for') || 'unknown',
+      userAgent: req.headers.get('user-agent') || undefined,
+      result: report.passed ? 'SUCCESS' : 'FAILURE',
+    })
+
+    return NextResponse.json(report)
+  }),
+)
@@ -3,13 +3,16 @@ import { prisma } from '@/lib/db'
 import { withAuth, withPermission } from '@/lib/middleware/auth'
 import { decrypt } from '@/lib/auth/encryption'
 import { registry, ensureRegistryInitialized, resolveGatewayUrl } from '@/lib/gateway/registry'
+import { transitionInstanceStatus } from '@/lib/instances/status'
 import { dockerManager } from '@/lib/docker'
 import { auditLog } from '@/lib/audit'
+import { canControlInstance } from '@/lib/instances/delegation'
+import { applyContainerConfig, initializeContainer } from '@/lib/instances/container-config'
 import type { DockerConfig } from '@/types/instance'
 
 // POST /api/v1/instances/[id]/start — Start container + connect gateway
 export const POST = withAuth(
-  withPermission('instances:manage', async (req, { user, params }) => {
+  withPermission('instances:control', async (req, { user, params }) => {
     const id = params!.id as string
 
     const instance = await prisma.instance.findUnique({ where: { id } })
@@ -17,12 +20,30 @@ export const POST = withAuth(
       return NextResponse.json({ error: 'Instance not found' }, { status: 404 })
     }
 
+    if (!(await canControlInstance(user, id, 'START'))) {
+      return NextResponse.json({ error: 'Action not delegated for this instance' }, { status: 403 })
+    }
+
     await ensureRegistryInitialized()
 
     // Start Docker container if managed
-    if (instance.containerId) {
+    let containerId = instance.containerId
+    if (containerId) {
+      // Apply the current start parameters (environment overrides, sandbox profile);
+      // a container created with other ones is recreated
+      let container: Awaited<ReturnType<typeof applyContainerConfig>>
       try {
-        await dockerManager.startContainer(instance.containerId)
+        container = await applyContainerConfig({ ...instance, containerId })
+        containerId = container.containerId
+      } catch (err) {
+        return NextResponse.json(
+          { error: `Failed to apply container config:${(err as Error).message}` },
+          { status: 500 },
+        )
+      }
+
+      try {
+        await dockerManager.startContainer(containerId)
       } catch (err) {
         const msg = (err as Error).message
         // Ignore "already started" errors
@@ -34,6 +55,8 @@ export const POST = withAuth(
         }
       }
 
+      if (container.recreated) await initializeContainer(containerId, container.profile, instance.name)
+
       // Wait briefly for container to initialize
       await new Promise((r) => setTimeout(r, 2000))
     }
@@ -45,19 +68,16 @@ export const POST = withAuth(
 
       // Extract version from Docker container OCI labels
       let version: string | undefined
-      if (instance.containerId) {
+      if (containerId) {
         try {
-          const info = await dockerManager.inspectContainer(instance.containerId)
+          const info = await dockerManager.inspectContainer(containerId)
           version = info.version
         } catch {
           // Non-fatal: container inspect can fail
         }
       }
 
-      await prisma.instance.update({
-        where: { id },
-        data: { status: 'ONLINE', ...(version ? { version } : {}) },
-      })
+      await transitionInstanceStatus(id, 'started', version ? { version } : {})
 
       auditLog({
         userId: user.id,
@@ -72,10 +92,7 @@ export const POST = withAuth(
 
       return NextResponse.json({ status: 'started' })
     } catch (err) {
-      await prisma.instance.update({
-        where: { id },
-        data: { status: 'ERROR' },
-      })
+      await transitionInstanceStatus(id, 'start_failed')
 
       auditLog({
         userId: user.id,
@@ -2,12 +2,14 @@ import { NextResponse } from 'next/server'
 import { prisma } from '@/lib/db'
 import { withAuth, withPermission } from '@/lib/middleware/auth'
 import { registry } from '@/lib/gateway/registry'
+import { transitionInstanceStatus } from '@/lib/instances/status'
 import { dockerManager } from '@/lib/docker'
 import { auditLog } from '@/lib/audit'
+import { canControlInstance } from '@/lib/instances/delegation'
 
 // POST /api/v1/instances/[id]/stop — Disconnect gateway + stop container
 export const POST = withAuth(
-  withPermission('instances:manage', async (req, { user, params }) => {
+  withPermission('instances:control', async (req, { user, params }) => {
     const id = params!.id as string
 
     const instance = await prisma.instance.findUnique({ where: { id } })
@@ -15,6 +17,10 @@ export const POST = withAuth(
       return NextResponse.json({ error: 'Instance not found' }, { status: 404 })
     }
 
+    if (!(await canControlInstance(user, id, 'STOP'))) {
+      return NextResponse.json({ error: 'Action not delegated for this instance' }, { status: 403 })
+    }
+
     // Disconnect from gateway
     await registry.disconnect(id)
 
@@ -33,10 +39,7 @@ export const POST = withAuth(
       }
     }
 
-    await prisma.instance.update({
-      where: { id },
-      data: { status: 'OFFLINE' },
-    })
+    await transitionInstanceStatus(id, 'stopped')
 
     auditLog({
       userId: user.id,
@@ -1,10 +1,15 @@
 import { NextRequest, NextResponse } from 'next/server'
-import path from 'path'
 import { prisma } from '@/lib/db'
+import { containsInsensitive } from '@/lib/db-dialect'
 import { withAuth, withPermission, withValidation } from '@/lib/middleware/auth'
 import { createInstanceSchema } from '@/lib/validations/instance'
 import { encrypt } from '@/lib/auth/encryption'
-import { registry, ensureRegistryInitialized } from '@/lib/gateway/registry'
+import { registry, ensureRegistryInitialized, resolveGatewayUrl } from '@/lib/gateway/registry'
+import { probeGateway } from '@/lib/gateway/handshake'
+import { assertDestinationAllowed, DestinationDeniedError } from '@/lib/destination-policy'
+import { transitionInstanceStatus } from '@/lib/instances/status'
+import { getInstancesLiveInfo } from '@/lib/instances/live'
+import { parseListParams, pickFields, type ListSpec } from '@/lib/list-params'
 import { dockerManager } from '@/lib/docker'
 import {
   generateGatewayToken,
@@ -12,8 +17,13 @@ import {
   cleanupInstanceFiles,
 } from '@/lib/docker/config-generator'
 import type { ModelProviderConfig } from '@/lib/docker/config-generator'
+import { getSandboxProfile } from '@/lib/docker/sandbox-profiles'
+import { resolveDockerConfig } from '@/lib/docker/environments'
+import { buildContainerOptions, initializeContainer, sandboxProfileError } from '@/lib/instances/container-config'
 import { auditLog } from '@/lib/audit'
+import { enforceLicenseLimit } from '@/lib/license'
 import type { InstanceStatus, Prisma } from '@/generated/prisma'
+import type { DockerConfig, GatewayHandshakeResult } from '@/types/instance'
 
 const GATEWAY_PORT = 18789          // Container-internal gateway port (fixed)
 const BASE_HOST_PORT = 18800        // Host port range starts here (avoids conflict with local OpenClaw on 18789)
@@ -25,6 +35,7 @@ const instanceSelectFields = {
   id: true,
   name: true,
   description: true,
+  region: true,
   gatewayUrl: true,
   containerId: true,
   containerName: true,
@@ -39,6 +50,20 @@ const instanceSelectFields = {
   updatedAt: true,
 } as const
 
+const instanceListSpec: ListSpec = {
+  sortable: {
+    name: 'name',
+    status: 'status',
+    region: 'region',
+    version: 'version',
+    lastHealthCheck: 'lastHealthCheck',
+    createdAt: 'createdAt',
+    updatedAt: 'updatedAt',
+  },
+  defaultSort: [{ field: 'createdAt', direction: 'desc' }],
+  fields: [...Object.keys(instanceSelectFields), 'live'],
+}
+
 // ─── Helpers ─────────────────────────────────────────────────────────
 
 /** Find the next available host port for gateway binding (serialized to prevent races). */
@@ -121,18 +146,21 @@ export const GET = withAuth(
     const pageSize = Math.min(100, Math.max(1, parseInt(url.searchParams.get('pageSize') || '20')))
     const statusFilter = url.searchParams.get('status') as InstanceStatus | null
     const search = url.searchParams.get('search') || ''
+    const include = (url.searchParams.get('include') || '').split(',')
+    const list = parseListParams<Prisma.InstanceOrderByWithRelationInput>(url.searchParams, instanceListSpec)
+    if (list instanceof NextResponse) return list
 
     const where = {
       ...(statusFilter ? { status: statusFilter } : {}),
       ...(search
-        ? { name: { contains: search, mode: 'insensitive' as const } }
+        ? { name: containsInsensitive(search) }
         : {}),
     }
 
     const [instances, total] = await Promise.all([
       prisma.instance.findMany({
         where,
-        orderBy: { createdAt: 'desc' },
+        orderBy: list.orderBy,
         skip: (page - 1) * pageSize,
         take: pageSize,
         select: instanceSelectFields,
@@ -140,7 +168,23 @@ export const GET = withAuth(
       prisma.instance.count({ where }),
     ])
 
-    return NextResponse.json({ instances, total, page, pageSize })
+    // ?include=live: connection, server version and container state in the same response
+    if (include.includes('live')) {
+      const live = await getInstancesLiveInfo(instances)
+      return NextResponse.json({
+        instances: instances.map((i) => pickFields({ ...i, live: live.get(i.id) }, list.fields)),
+        total,
+        page,
+        pageSize,
+      })
+    }
+
+    return NextResponse.json({
+      instances: instances.map((i) => pickFields(i, list.fields)),
+      total,
+      page,
+      pageSize,
+    })
   }),
 )
 
@@ -163,7 +207,16 @@ export const POST = withAuth(
         return NextResponse.json({ error: 'Instance name already exists' }, { status: 409 })
       }
 
+      const blocked = await enforceLicenseLimit('instance')
+      if (blocked) return blocked
+
       if (mode === 'docker') {
+        if (validateRequested(req)) {
+          return NextResponse.json(
+            { error: 'validate=true only applies to external instances' },
+            { status: 400 },
+          )
+        }
         return await createDockerInstance(req, user, body)
       } else {
         return await createExternalInstance(req, user, body)
@@ -172,6 +225,11 @@ export const POST = withAuth(
   ),
 )
 
+/** ?validate=true: handshake with the gateway before anything is persisted */
+function validateRequested(req: NextRequest): boolean {
+  return new URL(req.url).searchParams.get('validate') === 'true'
+}
+
 // ─── Docker Mode ─────────────────────────────────────────────────────
 
 async function createDockerInstance(
@@ -180,18 +238,22 @@ async function createDockerInstance(
   body: {
     name: string
     description?: string
-    docker?: {
-      imageName?: string
-      env?: Record<string, string>
-      restartPolicy?: 'no' | 'always' | 'unless-stopped' | 'on-failure'
-      memoryLimit?: number
-    }
+    region?: string | null
+    docker?: DockerConfig
     modelProvider?: { name: string; apiKey: string; api?: string; baseUrl?: string }
     defaultAgentId?: string
   },
 ) {
   const { name, description } = body
 
+  // Template + overrides for the environment this deployment runs in
+  const resolved = resolveDockerConfig(body.docker)
+  const sandbox = getSandboxProfile(resolved.sandboxProfile)
+  const sandboxError = sandboxProfileError(sandbox)
+  if (sandboxError) {
+    return NextResponse.json({ error: sandboxError }, { status: 400 })
+  }
+
   // 1. Generate gateway token
   const gatewayToken = generateGatewayToken()
 
@@ -207,7 +269,7 @@ async function createDockerInstance(
       gatewayPort: GATEWAY_PORT,
       modelProvider,
       defaultAgentId: body.defaultAgentId || 'main',
-      env: body.docker?.env,
+      env: resolved.env,
       hostDataDir: 'resolve', // resolved to dataDir inside initializeInstanceFiles
     })
     dataDir = result.dataDir
@@ -245,32 +307,16 @@ async function createDockerInstance(
   const containerName = `teamclaw-${name}`
   let containerId: string
   try {
-    const workspaceHostPath = path.join(dataDir, 'workspace')
-    containerId = await dockerManager.createContainer({
-      name: containerName,
-      imageName,
-      volumes: {
-        [dataDir]: '/home/node/.openclaw',
-        [workspaceHostPath]: '/workspace',
-      },
-      // Extra binds for sandbox support (Docker-in-Docker):
-      // 1. Mount workspace at its host path so OpenClaw sandbox can bind-mount
-      //    workspace into sandbox containers using host-resolvable paths.
-      // 2. Mount Docker socket for sandbox container management.
-      extraBinds: [
-        `${workspaceHostPath}:${workspaceHostPath}`,
-        '/var/run/docker.sock:/var/run/docker.sock',
-      ],
-      portBindings: {
-        [`${GATEWAY_PORT}`]: String(hostPort),
-      },
-      env: {
-        OPENCLAW_GATEWAY_TOKEN: gatewayToken,
-        ...body.docker?.env,
-      },
-      restartPolicy: body.docker?.restartPolicy || 'unless-stopped',
-      memoryLimit: body.docker?.memoryLimit,
-    })
+    containerId = await dockerManager.createContainer(
+      buildContainerOptions({
+        containerName,
+        imageName,
+        dataDir,
+        hostPort,
+        gatewayToken,
+        dockerConfig: body.docker,
+      }),
+    )
   } catch (err) {
     await cleanupInstanceFiles(name).catch(() => {})
     return NextResponse.json(
@@ -283,18 +329,9 @@ async function createDockerInstance(
   try {
     await dockerManager.startContainer(containerId)
 
-    // Fix common env issues (pip3 PATH, etc.) — always runs, independent of sandbox.
-    await dockerManager.initContainerEnv(containerId).catch(() => {})
-
-    // Install Docker CLI and set up permissions for sandbox mode (Docker-in-Docker).
-    // Must run after start (needs running container) and requires restart for group changes.
-    try {
-      await dockerManager.initSandboxSupport(containerId)
-      await dockerManager.restartContainer(containerId)
-    } catch (sandboxErr) {
-      // Non-fatal: instance works without sandbox, log and continue
-      console.warn(`[instance:create] Sandbox init failed for ${name}:`, (sandboxErr as Error).message)
-    }
+    // Fix common env issues (pip3 PATH, etc.) and, when the profile mounts the
+    // Docker socket, install the Docker CLI for sandbox mode (Docker-in-Docker).
+    await initializeContainer(containerId, sandbox, name)
   } catch (err) {
     // Keep container for debugging — create DB record with ERROR status
     const gatewayUrl = buildGatewayUrl(containerName, hostPort)
@@ -302,6 +339,7 @@ async function createDockerInstance(
       data: {
         name,
         description,
+        region: body.region,
         gatewayUrl,
         gatewayToken: encrypt(gatewayToken),
         containerId,
@@ -338,6 +376,7 @@ async function createDockerInstance(
     data: {
       name,
       description,
+      region: body.region,
       gatewayUrl,
       gatewayToken: encrypt(gatewayToken),
       containerId,
@@ -355,10 +394,7 @@ async function createDockerInstance(
 
   try {
     await registry.connect(instance.id, gatewayUrl, gatewayToken)
-    await prisma.instance.update({
-      where: { id: instance.id },
-      data: { status: 'ONLINE' },
-    })
+    await transitionInstanceStatus(instance.id, 'started')
   } catch (err) {
     // Container is running but gateway connection failed — stays OFFLINE
     // The start endpoint or health service can recover later
@@ -393,6 +429,7 @@ async function createExternalInstance(
   body: {
     name: string
     description?: string
+    region?: string | null
     gatewayUrl?: string
     gatewayToken?: string
     docker?: {
@@ -410,11 +447,32 @@ async function createExternalInstance(
     )
   }
 
+  try {
+    await assertDestinationAllowed(gatewayUrl)
+  } catch (err) {
+    if (err instanceof DestinationDeniedError) {
+      return NextResponse.json({ error: err.message }, { status: 400 })
+    }
+    throw err
+  }
+
+  let handshake: GatewayHandshakeResult | undefined
+  if (validateRequested(req)) {
+    handshake = await probeGateway(resolveGatewayUrl({ gatewayUrl, dockerConfig: null }), gatewayToken)
+    if (!handshake.ok) {
+      return NextResponse.json(
+        { error: `Gateway handshake failed: ${handshake.error!.message}`, handshake },
+        { status: 422 },
+      )
+    }
+  }
+
   // Create DB record first (OFFLINE), then try connecting
   const instance = await prisma.instance.create({
     data: {
       name,
       description,
+      region: body.region,
       gatewayUrl,
       gatewayToken: encrypt(gatewayToken),
       imageName: body.docker?.imageName || 'alpine/openclaw:latest',
@@ -427,10 +485,7 @@ async function createExternalInstance(
   // Try connecting with the real instance ID directly
   try {
     await registry.connect(instance.id, gatewayUrl, gatewayToken)
-    await prisma.instance.update({
-      where: { id: instance.id },
-      data: { status: 'ONLINE' },
-    })
+    await transitionInstanceStatus(instance.id, 'started')
   } catch (err) {
     console.error(`[instance:create] External gateway connect failed for ${name}:`, (err as Error).message)
   }
@@ -451,5 +506,5 @@ async function createExternalInstance(
     result: 'SUCCESS',
   })
 
-  return NextResponse.json({ instance: updated ?? instance }, { status: 201 })
+  return NextResponse.json({ instance: updated ?? instance, ...(handshake ? { handshake } : {}) }, { status: 201 })
 }
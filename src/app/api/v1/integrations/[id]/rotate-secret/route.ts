@@ -0,0 +1,37 @@
+import { NextResponse } from 'next/server'
+import { prisma } from '@/lib/db'
+import { withAuth, withPermission, param } from '@/lib/middleware/auth'
+import { encrypt } from '@/lib/auth/encryption'
+import { auditLog } from '@/lib/audit'
+import { generateIntegrationSecret } from '@/lib/integrations'
+
+// POST /api/v1/integrations/[id]/rotate-secret — Issue a new secret (old one stops working)
+export const POST = withAuth(
+  withPermission('integrations:manage', async (req, ctx) => {
+    const id = param(ctx, 'id')
+
+    const existing = await prisma.integrationEndpoint.findUnique({ where: { id } })
+    if (!existing) {
+      return NextResponse.json({ error: 'Integration not found' }, { status: 404 })
+    }
+
+    const secret = generateIntegrationSecret()
+    await prisma.integrationEndpoint.update({
+      where: { id },
+      data: { secret: encrypt(secret) },
+    })
+
+    auditLog({
+      userId: ctx.user.id,
+      action: 'INTEGRATION_ROTATE_SECRET',
+      resource: 'integration',
+      resourceId: id,
+      details: { slug: existing.slug },
+      ipAddress: req.headers.get('x-forwarded-for') || 'unknown',
+      userAgent: req.headers.get('user-agent') || undefined,
+      result: 'SUCCESS',
+    })
+
+    return NextResponse.json({ secret })
+  }),
+)
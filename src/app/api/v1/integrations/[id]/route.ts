@@ -0,0 +1,111 @@
+import { NextResponse } from 'next/server'
+import { prisma } from '@/lib/db'
+import { withAuth, withPermission, withValidation, param } from '@/lib/middleware/auth'
+import type { AuthContext } from '@/lib/middleware/auth'
+import { updateIntegrationSchema } from '@/lib/validations/integration'
+import { auditLog, diffForAudit } from '@/lib/audit'
+import { toIntegrationResponse } from '@/lib/integrations'
+
+// GET /api/v1/integrations/[id] — Endpoint detail with recent events
+export const GET = withAuth(
+  withPermission('integrations:manage', async (_req, ctx) => {
+    const id = param(ctx, 'id')
+
+    const endpoint = await prisma.integrationEndpoint.findUnique({
+      where: { id },
+      include: {
+        instance: { select: { name: true } },
+        events: { orderBy: { createdAt: 'desc' }, take: 50 },
+      },
+    })
+    if (!endpoint) {
+      return NextResponse.json({ error: 'Integration not found' }, { status: 404 })
+    }
+
+    return NextResponse.json({
+      integration: toIntegrationResponse(endpoint),
+      events: endpoint.events.map((e) => ({
+        id: e.id,
+        status: e.status,
+        chatSessionId: e.chatSessionId,
+        error: e.error,
+        sourceIp: e.sourceIp,
+        createdAt: e.createdAt.toISOString(),
+        completedAt: e.completedAt?.toISOString() ?? null,
+      })),
+    })
+  }),
+)
+
+// PUT /api/v1/integrations/[id] — Update target agent, template, or enabled flag
+export const PUT = withAuth(
+  withPermission(
+    'integrations:manage',
+    withValidation(updateIntegrationSchema, async (req, ctx) => {
+      const { user, body } = ctx as {
+        user: NonNullable<typeof ctx.user>
+        body: typeof ctx.body
+      }
+      const id = param(ctx as unknown as AuthContext, 'id')
+
+      const existing = await prisma.integrationEndpoint.findUnique({ where: { id } })
+      if (!existing) {
+        return NextResponse.json({ error: 'Integration not found' }, { status: 404 })
+      }
+
+      const endpoint = await prisma.integrationEndpoint.update({
+        where: { id },
+        data: body,
+        include: { instance: { select: { name: true } } },
+      })
+
+      auditLog({
+        userId: user.id,
+        action: 'INTEGRATION_UPDATE',
+        resource: 'integration',
+        resourceId: id,
+        details: { slug: endpoint.slug },
+        changes: diffForAudit(existing, body),
+        ipAddress: req.headers.get('x-forwarded-for') || 'unknown',
+        userAgent: req.headers.get('user-agent') || undefined,
+        result: 'SUCCESS',
+      })
+
+      return NextResponse.json({ integration: toIntegrationResponse(endpoint) })
+    }),
+  ),
+)
+
+// DELETE /api/v1/integrations/[id] — Remove endpoint and disable its service account
+export const DELETE = withAuth(
+  withPermission('integrations:manage', async (req, ctx) => {
+    const id = param(ctx, 'id')
+
+    const existing = await prisma.integrationEndpoint.findUnique({ where: { id } })
+    if (!existing) {
+      return NextResponse.json({ error: 'Integration not found' }, { status: 404 })
+    }
+
+    // Keep the service account so its sessions remain attributable
+    await prisma.$transaction([
+      prisma.integrationEndpoint.delete({ where: { id } }),
+      prisma.user.update({
+        where: { id: existing.serviceAccountId },
+        data: { status: 'DISABLED' },
+      }),
+    ])
+
+    auditLog({
+      userId: ctx.user.id,
+      action: 'INTEGRATION_DELETE',
+      resource: 'integration',
+      resourceId: id,
+      details: { slug: existing.slug },
+      ipAddress: req.headers.get('x-forwarded-for') || 'unknown',
+      userAgent: req.headers.get('user-agent') || undefined,
+      result: 'SUCCESS',
+    })
+
+    return NextResponse.json({ success: true })
+  }),
+)
@@ -0,0 +1,81 @@
+import { NextResponse } from 'next/server'
+import { prisma } from '@/lib/db'
+import { withAuth, withPermission, withValidation } from '@/lib/middleware/auth'
+import { createIntegrationSchema } from '@/lib/validations/integration'
+import { encrypt } from '@/lib/auth/encryption'
+import { auditLog } from '@/lib/audit'
+import { createServiceAccount } from '@/lib/users/service-accounts'
+import { generateIntegrationSecret, toIntegrationResponse } from '@/lib/integrations'
+
+// GET /api/v1/integrations — List inbound integration endpoints
+export const GET = withAuth(
+  withPermission('integrations:manage', async () => {
+    const endpoints = await prisma.integrationEndpoint.findMany({
+      include: { instance: { select: { name: true } } },
+      orderBy: { createdAt: 'desc' },
+    })
+    return NextResponse.json({ integrations: endpoints.map(toIntegrationResponse) })
+  }),
+)
+
+// POST /api/v1/integrations — Create an endpoint (secret is only returned here)
+export const POST = withAuth(
+  withPermission(
+    'integrations:manage',
+    withValidation(createIntegrationSchema, async (req, ctx) => {
+      const { user, body } = ctx as {
+        user: NonNullable<typeof ctx.user>
+        body: typeof ctx.body
+      }
+
+      const [instance, slugTaken] = await Promise.all([
+        prisma.instance.findUnique({ where: { id: body.instanceId }, select: { id: true } }),
+        prisma.integrationEndpoint.findUnique({ where: { slug: body.slug }, select: { id: true } }),
+      ])
+      if (!instance) {
+        return NextResponse.json({ error: 'Instance not found' }, { status: 404 })
+      }
+      if (slugTaken) {
+        return NextResponse.json({ error: 'Slug already in use' }, { status: 409 })
+      }
+
+      const secret = generateIntegrationSecret()
+      const serviceAccountId = await createServiceAccount(
+        `Integration: ${body.name}`,
+        `integration-${body.slug}`,
+      )
+
+      const endpoint = await prisma.integrationEndpoint.create({
+        data: {
+          name: body.name,
+          slug: body.slug,
+          description: body.description,
+          instanceId: body.instanceId,
+          agentId: body.agentId,
+          promptTemplate: body.promptTemplate,
+          enabled: body.enabled ?? true,
+          secret: encrypt(secret),
+          serviceAccountId,
+          createdById: user.id,
+        },
+        include: { instance: { select: { name: true } } },
+      })
+
+      auditLog({
+        userId: user.id,
+        action: 'INTEGRATION_CREATE',
+        resource: 'integration',
+        resourceId: endpoint.id,
+        details: { slug: endpoint.slug, instanceId: endpoint.instanceId, agentId: endpoint.agentId },
+        ipAddress: req.headers.get('x-forwarded-for') || 'unknown',
+        userAgent: req.headers.get('user-agent') || undefined,
+        result: 'SUCCESS',
+      })
+
+      return NextResponse.json(
+        { integration: toIntegrationResponse(endpoint), secret },
+        { status: 201 },
+      )
+    }),
+  ),
+)
@@ -0,0 +1,32 @@
+import { NextResponse } from 'next/server'
+import { withAuth, withPermission } from '@/lib/middleware/auth'
+import { getLicenseStatus, loadLicense } from '@/lib/license'
+import { auditLog } from '@/lib/audit'
+
+// GET /api/v1/license — License state and seat/instance usage
+export const GET = withAuth(
+  withPermission('settings:license', async () => {
+    return NextResponse.json(await getLicenseStatus())
+  }),
+)
+
+// POST /api/v1/license — Re-read the license file (e.g. after a renewal)
+export const POST = withAuth(
+  withPermission('settings:license', async (req, { user }) => {
+    loadLicense()
+    const status = await getLicenseStatus()
+
+    auditLog({
+      userId: user.id,
+      action: 'LICENSE_RELOAD',
+      resource: 'system_config',
+      resourceId: 'license',
+      details: { state: status.state, licenseId: status.licenseId ?? '' },
+      ipAddress: req.headers.get('x-forwarded-for') || 'unknown',
+      userAgent: req.headers.get('user-agent') || undefined,
+      result: status.state === 'INVALID' ? 'FAILURE' : 'SUCCESS',
+    })
+
+    return NextResponse.json(status)
+  }),
+)
@@ -0,0 +1,54 @@
+import { NextRequest, NextResponse } from 'next/server'
+import { createHash, timingSafeEqual } from 'crypto'
+import { prisma } from '@/lib/db'
+import { registry } from '@/lib/gateway/registry'
+import { latencyToPrometheus } from '@/lib/gateway/latency'
+import { probesToPrometheus } from '@/lib/probes'
+import { auditQueueToPrometheus } from '@/lib/audit-queue'
+import { chatStreamToPrometheus } from '@/lib/chat/stream-guard'
+import { chatQueueToPrometheus } from '@/lib/chat/concurrency'
+
+function tokenMatches(provided: string, expected: string): boolean {
+  // Compare digests so differing lengths don't leak through timing
+  const a = createHash('sha256').update(provided).digest()
+  const b = createHash('sha256').update(expected).digest()
+  return timingSafeEqual(a, b)
+}
+
+// GET /api/v1/metrics — Prometheus scrape endpoint.
+// Public path (scrapers have no session); requires Bearer METRICS_TOKEN and
+// is disabled when the token is not configured.
+export async function GET(req: NextRequest) {
+  const expected = process.env.METRICS_TOKEN
+  if (!expected) {
+    return NextResponse.json({ error: 'Not found' }, { status: 404 })
+  }
+
+  const auth = req.headers.get('authorization') ?? ''
+  if (!auth.startsWith('Bearer ') || !tokenMatches(auth.slice(7), expected)) {
+    return NextResponse.json({ error: 'Unauthorized' }, { status: 401 })
+  }
+
+  const stats = registry.latency.stats()
+  const [instances, probes, auditQueue] = await Promise.all([
+    prisma.instance.findMany({
+      where: { id: { in: [...new Set(stats.map((s) => s.instanceId))] } },
+      select: { id: true, name: true },
+    }),
+    prisma.syntheticProbe.findMany({
+      where: { enabled: true },
+      include: { instance: { select: { name: true } } },
+    }),
+    auditQueueToPrometheus(),
+  ])
+
+  const body =
+    latencyToPrometheus(stats, new Map(instances.map((i) => [i.id, i.name]))) +
+    probesToPrometheus(probes) +
+    auditQueue +
+    chatStreamToPrometheus() +
+    chatQueueToPrometheus()
+  return new NextResponse(body, {
+    headers: { 'Content-Type': 'text/plain; version=0.0.4; charset=utf-8' },
+  })
+}
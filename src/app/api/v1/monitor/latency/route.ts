@@ -0,0 +1,16 @@
+import { NextResponse } from 'next/server'
+import { withAuth, withPermission } from '@/lib/middleware/auth'
+import { registry } from '@/lib/gateway/registry'
+import { sloObjective } from '@/lib/gateway/latency'
+
+// GET /api/v1/monitor/latency?instanceId=&method= — Rolling gateway latency and SLO burn rate
+export const GET = withAuth(
+  withPermission('monitor:view', async (req) => {
+    const url = new URL(req.url)
+    const stats = registry.latency.stats({
+      instanceId: url.searchParams.get('instanceId') || undefined,
+      method: url.searchParams.get('method') || undefined,
+    })
+    return NextResponse.json({ objective: sloObjective(), stats })
+  }),
+)
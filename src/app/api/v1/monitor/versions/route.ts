@@ -0,0 +1,10 @@
+import { NextResponse } from 'next/server'
+import { withAuth, withPermission } from '@/lib/middleware/auth'
+import { getVersionAdvisory } from '@/lib/instances/versions'
+
+// GET /api/v1/monitor/versions — OpenClaw version inventory and upgrade advisories
+export const GET = withAuth(
+  withPermission('monitor:view', async () => {
+    return NextResponse.json(await getVersionAdvisory())
+  }),
+)
@@ -0,0 +1,43 @@
+import { NextResponse } from 'next/server'
+import { prisma } from '@/lib/db'
+import { withAuth, withPermission, param } from '@/lib/middleware/auth'
+import { canManageDepartmentChannels, toNotificationLogResponse } from '@/lib/notifications'
+
+// GET /api/v1/notification-channels/[id]/logs?status=&page=&pageSize= — Delivery history
+export const GET = withAuth(
+  withPermission('notifications:manage', async (req, ctx) => {
+    const id = param(ctx, 'id')
+
+    const channel = await prisma.notificationChannel.findUnique({ where: { id } })
+    if (!channel || !canManageDepartmentChannels(ctx.user, channel.departmentId)) {
+      return NextResponse.json({ error: 'Channel not found' }, { status: 404 })
+    }
+
+    const url = new URL(req.url)
+    const page = Math.max(1, parseInt(url.searchParams.get('page') || '1'))
+    const pageSize = Math.min(100, Math.max(1, parseInt(url.searchParams.get('pageSize') || '20')))
+    const status = url.searchParams.get('status')
+
+    const where = {
+      channelId: id,
+      ...(status === 'SENT' || status === 'FAILED' ? { status: status as 'SENT' | 'FAILED' } : {}),
+    }
+
+    const [logs, total] = await Promise.all([
+      prisma.notificationLog.findMany({
+        where,
+        orderBy: { createdAt: 'desc' },
+        skip: (page - 1) * pageSize,
+        take: pageSize,
+      }),
+      prisma.notificationLog.count({ where }),
+    ])
+
+    return NextResponse.json({
+      logs: logs.map(toNotificationLogResponse),
+      total,
+      page,
+      pageSize,
+    })
+  }),
+)
@@ -0,0 +1,84 @@
+import { NextResponse } from 'next/server'
+import { prisma } from '@/lib/db'
+import { withAuth, withPermission, withValidation, param } from '@/lib/middleware/auth'
+import type { AuthContext } from '@/lib/middleware/auth'
+import { updateNotificationChannelSchema } from '@/lib/validations/notification'
+import { encrypt } from '@/lib/auth/encryption'
+import { auditLog, diffForAudit } from '@/lib/audit'
+import { isAllowedWebhookUrl } from '@/lib/notifications/connectors'
+import { canManageDepartmentChannels, toChannelResponse } from '@/lib/notifications'
+
+// PUT /api/v1/notification-channels/[id] — Update name, URL, template, or enabled flag
+export const PUT = withAuth(
+  withPermission(
+    'notifications:manage',
+    withValidation(updateNotificationChannelSchema, async (req, ctx) => {
+      const { user, body } = ctx as {
+        user: NonNullable<typeof ctx.user>
+        body: typeof ctx.body
+      }
+      const id = param(ctx as unknown as AuthContext, 'id')
+
+      const existing = await prisma.notificationChannel.findUnique({ where: { id } })
+      if (!existing || !canManageDepartmentChannels(user, existing.departmentId)) {
+        return NextResponse.json({ error: 'Channel not found' }, { status: 404 })
+      }
+      if (body.webhookUrl && !isAllowedWebhookUrl(existing.type, body.webhookUrl)) {
+        return NextResponse.json({ error: 'Webhook URL does not match the channel type' }, { status: 400 })
+      }
+
+      const channel = await prisma.notificationChannel.update({
+        where: { id },
+        data: {
+          name: body.name,
+          template: body.template,
+          enabled: body.enabled,
+          webhookUrl: body.webhookUrl ? encrypt(body.webhookUrl) : undefined,
+        },
+        include: { department: { select: { name: true } } },
+      })
+
+      auditLog({
+        userId: user.id,
+        action: 'NOTIFICATION_CHANNEL_UPDATE',
+        resource: 'notification_channel',
+        resourceId: id,
+        details: { name: channel.name },
+        changes: diffForAudit(existing, body, ['webhookUrl']),
+        ipAddress: req.headers.get('x-forwarded-for') || 'unknown',
+        userAgent: req.headers.get('user-agent') || undefined,
+        result: 'SUCCESS',
+      })
+
+      return NextResponse.json({ channel: toChannelResponse(channel) })
+    }),
+  ),
+)
+
+// DELETE /api/v1/notification-channels/[id] — Remove a channel and its delivery log
+export const DELETE = withAuth(
+  withPermission('notifications:manage', async (req, ctx) => {
+    const { user } = ctx
+    const id = param(ctx, 'id')
+
+    const existing = await prisma.notificationChannel.findUnique({ where: { id } })
+    if (!existing || !canManageDepartmentChannels(user, existing.departmentId)) {
+      return NextResponse.json({ error: 'Channel not found' }, { status: 404 })
+    }
+
+    await prisma.notificationChannel.delete({ where: { id } })
+
+    auditLog({
+      userId: user.id,
+      action: 'NOTIFICATION_CHANNEL_DELETE',
+      resource: 'notification_channel',
+      resourceId: id,
+      details: { name: existing.name, type: existing.type },
+      ipAddress: req.headers.get('x-forwarded-for') || 'unknown',
+      userAgent: req.headers.get('user-agent') || undefined,
+      result: 'SUCCESS',
+    })
+
+    return NextResponse.json({ success: true })
+  }),
+)
@@ -0,0 +1,40 @@
+import { NextResponse } from 'next/server'
+import { prisma } from '@/lib/db'
+import { withAuth, withPermission, withValidation, param } from '@/lib/middleware/auth'
+import type { AuthContext } from '@/lib/middleware/auth'
+import { testNotificationSchema } from '@/lib/validations/notification'
+import {
+  canManageDepartmentChannels,
+  deliverNotification,
+  toNotificationLogResponse,
+} from '@/lib/notifications'
+
+// POST /api/v1/notification-channels/[id]/test — Send a test message
+export const POST = withAuth(
+  withPermission(
+    'notifications:manage',
+    withValidation(testNotificationSchema, async (_req, ctx) => {
+      const { user, body } = ctx as {
+        user: NonNullable<typeof ctx.user>
+        body: typeof ctx.body
+      }
+      const id = param(ctx as unknown as AuthContext, 'id')
+
+      const channel = await prisma.notificationChannel.findUnique({
+        where: { id },
+        include: { department: { select: { name: true } } },
+      })
+      if (!channel || !canManageDepartmentChannels(user, channel.departmentId)) {
+        return NextResponse.json({ error: 'Channel not found' }, { status: 404 })
+      }
+
+      const log = await deliverNotification(channel, {
+        title: body.title || 'TeamClaw test notification',
+        text: body.text || `Test message sent by ${user.name}.`,
+        source: 'test',
+      })
+
+      return NextResponse.json({ log: toNotificationLogResponse(log) })
+    }),
+  ),
+)
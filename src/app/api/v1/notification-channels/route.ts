@@ -0,0 +1,78 @@
+import { NextResponse } from 'next/server'
+import { prisma } from '@/lib/db'
+import { withAuth, withPermission, withValidation } from '@/lib/middleware/auth'
+import { createNotificationChannelSchema } from '@/lib/validations/notification'
+import { encrypt } from '@/lib/auth/encryption'
+import { auditLog } from '@/lib/audit'
+import { isAllowedWebhookUrl } from '@/lib/notifications/connectors'
+import { canManageDepartmentChannels, toChannelResponse } from '@/lib/notifications'
+
+// GET /api/v1/notification-channels — List channels (DEPT_ADMIN: own department)
+export const GET = withAuth(
+  withPermission('notifications:manage', async (req, { user }) => {
+    const departmentId = new URL(req.url).searchParams.get('departmentId')
+
+    const where =
+      user.role === 'SYSTEM_ADMIN'
+        ? departmentId ? { departmentId } : {}
+        : { departmentId: user.departmentId ?? '' }
+
+    const channels = await prisma.notificationChannel.findMany({
+      where,
+      include: { department: { select: { name: true } } },
+      orderBy: { createdAt: 'desc' },
+    })
+    return NextResponse.json({ channels: channels.map(toChannelResponse) })
+  }),
+)
+
+// POST /api/v1/notification-channels — Create a Teams / WeCom channel
+export const POST = withAuth(
+  withPermission(
+    'notifications:manage',
+    withValidation(createNotificationChannelSchema, async (req, ctx) => {
+      const { user, body } = ctx as {
+        user: NonNullable<typeof ctx.user>
+        body: typeof ctx.body
+      }
+
+      if (!canManageDepartmentChannels(user, body.departmentId)) {
+        return NextResponse.json({ error: 'No permission for this department' }, { status: 403 })
+      }
+      if (!isAllowedWebhookUrl(body.type, body.webhookUrl)) {
+        return NextResponse.json({ error: 'Webhook URL does not match the channel type' }, { status: 400 })
+      }
+
+      const department = await prisma.department.findUnique({ where: { id: body.departmentId } })
+      if (!department) {
+        return NextResponse.json({ error: 'Department not found' }, { status: 404 })
+      }
+
+      const channel = await prisma.notificationChannel.create({
+        data: {
+          name: body.name,
+          type: body.type,
+          departmentId: body.departmentId,
+          webhookUrl: encrypt(body.webhookUrl),
+          template: body.template ?? null,
+          enabled: body.enabled ?? true,
+          createdById: user.id,
+        },
+        include: { department: { select: { name: true } } },
+      })
+
+      auditLog({
+        userId: user.id,
+        action: 'NOTIFICATION_CHANNEL_CREATE',
+        resource: 'notification_channel',
+        resourceId: channel.id,
+        details: { name: channel.name, type: channel.type, departmentId: channel.departmentId },
+        ipAddress: req.headers.get('x-forwarded-for') || 'unknown',
+        userAgent: req.headers.get('user-agent') || undefined,
+        result: 'SUCCESS',
+      })
+
+      return NextResponse.json({ channel: toChannelResponse(channel) }, { status: 201 })
+    }),
+  ),
+)
@@ -0,0 +1,92 @@
+import { NextResponse } from 'next/server'
+import { prisma } from '@/lib/db'
+import { Prisma } from '@/generated/prisma'
+import { withAuth, withPermission, withValidation, param } from '@/lib/middleware/auth'
+import type { AuthContext } from '@/lib/middleware/auth'
+import { updateProbeSchema } from '@/lib/validations/probe'
+import { auditLog, diffForAudit } from '@/lib/audit'
+import { checkProbeSchema, toProbeResponse } from '@/lib/probes'
+
+// PUT /api/v1/probes/[id] — Update prompt, assertions, budget, schedule or enabled flag
+export const PUT = withAuth(
+  withPermission(
+    'probes:manage',
+    withValidation(updateProbeSchema, async (req, ctx) => {
+      const { user, body } = ctx as {
+        user: NonNullable<typeof ctx.user>
+        body: typeof ctx.body
+      }
+      const id = param(ctx as unknown as AuthContext, 'id')
+
+      const existing = await prisma.syntheticProbe.findUnique({ where: { id } })
+      if (!existing) {
+        return NextResponse.json({ error: 'Probe not found' }, { status: 404 })
+      }
+
+      if (body.expectJsonSchema) {
+        const schemaError = checkProbeSchema(body.expectJsonSchema)
+        if (schemaError) {
+          return NextResponse.json({ error: schemaError }, { status: 400 })
+        }
+      }
+
+      const { expectJsonSchema, ...rest } = body
+      const probe = await prisma.syntheticProbe.update({
+        where: { id },
+        data: {
+          ...rest,
+          expectJsonSchema:
+            expectJsonSchema === null
+              ? Prisma.DbNull
+              : (expectJsonSchema as Prisma.InputJsonValue | undefined),
+          // Assertions changed: start a fresh failure streak
+          ...(body.expectRegex !== undefined || expectJsonSchema !== undefined || body.prompt
+            ? { consecutiveFailures: 0 }
+            : {}),
+        },
+        include: { instance: { select: { name: true } } },
+      })
+
+      auditLog({
+        userId: user.id,
+        action: 'PROBE_UPDATE',
+        resource: 'probe',
+        resourceId: id,
+        details: { name: probe.name },
+        changes: diffForAudit(existing, body),
+        ipAddress: req.headers.get('x-forwarded-for') || 'unknown',
+        userAgent: req.headers.get('user-agent') || undefined,
+        result: 'SUCCESS',
+      })
+
+      return NextResponse.json({ probe: toProbeResponse(probe) })
+    }),
+  ),
+)
+
+// DELETE /api/v1/probes/[id] — Remove a probe
+export const DELETE = withAuth(
+  withPermission('probes:manage', async (req, ctx) => {
+    const id = param(ctx, 'id')
+
+    const existing = await prisma.syntheticProbe.findUnique({ where: { id } })
+    if (!existing) {
+      return NextResponse.json({ error: 'Probe not found' }, { status: 404 })
+    }
+
+    await prisma.syntheticProbe.delete({ where: { id } })
+
+    auditLog({
+      userId: ctx.user.id,
+      action: 'PROBE_DELETE',
+      resource: 'probe',
+      resourceId: id,
+      details: { name: existing.name },
+      ipAddress: req.headers.get('x-forwarded-for') || 'unknown',
+      userAgent: req.headers.get('user-agent') || undefined,
+      result: 'SUCCESS',
+    })
+
+    return NextResponse.json({ success: true })
+  }),
+)
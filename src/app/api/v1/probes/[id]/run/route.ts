@@ -0,0 +1,24 @@
+import { NextResponse } from 'next/server'
+import { prisma } from '@/lib/db'
+import { withAuth, withPermission, param } from '@/lib/middleware/auth'
+import { runProbe, toProbeResponse } from '@/lib/probes'
+
+// POST /api/v1/probes/[id]/run — Run a probe now (also updates its state and alerts)
+export const POST = withAuth(
+  withPermission('probes:manage', async (_req, ctx) => {
+    const id = param(ctx, 'id')
+
+    const probe = await prisma.syntheticProbe.findUnique({ where: { id } })
+    if (!probe) {
+      return NextResponse.json({ error: 'Probe not found' }, { status: 404 })
+    }
+
+    const result = await runProbe(probe)
+    const updated = await prisma.syntheticProbe.findUniqueOrThrow({
+      where: { id },
+      include: { instance: { select: { name: true } } },
+    })
+
+    return NextResponse.json({ result, probe: toProbeResponse(updated) })
+  }),
+)
@@ -0,0 +1,68 @@
+import { NextResponse } from 'next/server'
+import { prisma } from '@/lib/db'
+import type { Prisma } from '@/generated/prisma'
+import { withAuth, withPermission, withValidation } from '@/lib/middleware/auth'
+import { createProbeSchema } from '@/lib/validations/probe'
+import { auditLog } from '@/lib/audit'
+import { checkProbeSchema, toProbeResponse } from '@/lib/probes'
+
+// GET /api/v1/probes — List synthetic probes with their latest result
+export const GET = withAuth(
+  withPermission('monitor:view', async () => {
+    const probes = await prisma.syntheticProbe.findMany({
+      include: { instance: { select: { name: true } } },
+      orderBy: { createdAt: 'desc' },
+    })
+    return NextResponse.json({ probes: probes.map(toProbeResponse) })
+  }),
+)
+
+// POST /api/v1/probes — Create a probe; the scheduler picks it up on its next tick
+export const POST = withAuth(
+  withPermission(
+    'probes:manage',
+    withValidation(createProbeSchema, async (req, ctx) => {
+      const { user, body } = ctx as {
+        user: NonNullable<typeof ctx.user>
+        body: typeof ctx.body
+      }
+
+      if (body.expectJsonSchema) {
+        const schemaError = checkProbeSchema(body.expectJsonSchema)
+        if (schemaError) {
+          return NextResponse.json({ error: schemaError }, { status: 400 })
+        }
+      }
+
+      const instance = await prisma.instance.findUnique({
+        where: { id: body.instanceId },
+        select: { id: true },
+      })
+      if (!instance) {
+        return NextResponse.json({ error: 'Instance not found' }, { status: 404 })
+      }
+
+      const probe = await prisma.syntheticProbe.create({
+        data: {
+          ...body,
+          expectJsonSchema: (body.expectJsonSchema ?? undefined) as Prisma.InputJsonValue | undefined,
+          createdById: user.id,
+        },
+        include: { instance: { select: { name: true } } },
+      })
+
+      auditLog({
+        userId: user.id,
+        action: 'PROBE_CREATE',
+        resource: 'probe',
+        resourceId: probe.id,
+        details: { name: probe.name, instanceId: probe.instanceId, agentId: probe.agentId },
+        ipAddress: req.headers.get('x-forwarded-for') || 'unknown',
+        userAgent: req.headers.get('user-agent') || undefined,
+        result: 'SUCCESS',
+      })
+
+      return NextResponse.json({ probe: toProbeResponse(probe) }, { status: 201 })
+    }),
+  ),
+)
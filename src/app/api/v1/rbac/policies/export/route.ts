@@ -0,0 +1,23 @@
+import { NextResponse } from 'next/server'
+import { withAuth, withPermission } from '@/lib/middleware/auth'
+import { exportPolicies, policiesToCsv } from '@/lib/auth/policy-store'
+
+// GET /api/v1/rbac/policies/export?format=json|csv — Effective role policy set
+export const GET = withAuth(
+  withPermission('settings:rbac', async (req) => {
+    const format = new URL(req.url).searchParams.get('format') === 'csv' ? 'csv' : 'json'
+    const doc = exportPolicies()
+
+    if (format === 'csv') {
+      return new NextResponse(policiesToCsv(doc), {
+        headers: {
+          'Content-Type': 'text/csv; charset=utf-8',
+          'Content-Disposition': 'attachment; filename="rbac-policies.csv"',
+        },
+      })
+    }
+    return NextResponse.json(doc, {
+      headers: { 'Content-Disposition': 'attachment; filename="rbac-policies.json"' },
+    })
+  }),
+)
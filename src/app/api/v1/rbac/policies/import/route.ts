@@ -0,0 +1,68 @@
+import { NextResponse } from 'next/server'
+import { withAuth, withPermission } from '@/lib/middleware/auth'
+import { diffPolicies, parsePolicies, savePolicies } from '@/lib/auth/policy-store'
+import { auditLog } from '@/lib/audit'
+import { interceptForApproval } from '@/lib/approvals'
+import type { AuditChanges } from '@/lib/audit'
+import type { PolicyImportResult } from '@/types/rbac'
+
+// POST /api/v1/rbac/policies/import?dryRun=true — Preview or apply a policy set.
+// Body is a JSON export or CSV (Content-Type: text/csv or ?format=csv).
+export const POST = withAuth(
+  withPermission('settings:rbac', async (req, { user }) => {
+    const url = new URL(req.url)
+    const dryRun = url.searchParams.get('dryRun') === 'true'
+    const format =
+      url.searchParams.get('format') === 'csv' || req.headers.get('content-type')?.includes('text/csv')
+        ? 'csv'
+        : 'json'
+
+    // Size is capped by the body limit middleware
+    const text = await req.text()
+
+    const { next, errors } = parsePolicies(text, format)
+    const changes = diffPolicies(next)
+    const result: PolicyImportResult = { dryRun, changes, errors }
+
+    // Nothing is applied while any entry is invalid
+    if (errors.length > 0) return NextResponse.json(result, { status: 400 })
+    if (dryRun || changes.length === 0) return NextResponse.json(result)
+
+    // The whole set goes into the payload: permissions it omits reset to the defaults
+    const pending = await interceptForApproval(req, {
+      action: 'RBAC_POLICY_CHANGE',
+      resource: 'system_config',
+      resourceId: 'rbac_policies',
+      payload: {
+        policies: Object.entries(next).map(([permission, roles]) => ({ permission, roles })),
+      },
+      summary: {
+        format,
+        changed: changes.length,
+        permissions: changes.map((c) => c.permission).join(','),
+      },
+      requestedById: user.id,
+    })
+    if (pending) return pending
+
+    await savePolicies(next)
+
+    const auditChanges: AuditChanges = {}
+    for (const c of changes) {
+      auditChanges[c.permission] = { before: c.before, after: c.after }
+    }
+    auditLog({
+      userId: user.id,
+      action: 'RBAC_POLICY_IMPORT',
+      resource: 'system_config',
+      resourceId: 'rbac_policies',
+      details: { format, changed: changes.length },
+      changes: auditChanges,
+      ipAddress: req.headers.get('x-forwarded-for') || 'unknown',
+      userAgent: req.headers.get('user-agent') || undefined,
+      result: 'SUCCESS',
+    })
+
+    return NextResponse.json(result)
+  }),
+)
@@ -0,0 +1,28 @@
+import { NextResponse } from 'next/server'
+import { withAuth, withPermission } from '@/lib/middleware/auth'
+import { getOverriddenPermissions } from '@/lib/auth/permissions'
+import { broadcastPolicyReload, loadPolicyOverrides } from '@/lib/auth/policy-store'
+import { auditLog } from '@/lib/audit'
+
+// POST /api/v1/rbac/reload — Re-read the stored role overrides on every replica
+// (after editing SystemConfig directly, or if a replica missed a change)
+export const POST = withAuth(
+  withPermission('settings:rbac', async (req, { user }) => {
+    await loadPolicyOverrides()
+    await broadcastPolicyReload()
+
+    const overridden = getOverriddenPermissions()
+    auditLog({
+      userId: user.id,
+      action: 'RBAC_POLICY_RELOAD',
+      resource: 'system_config',
+      resourceId: 'rbac_policies',
+      details: { overridden: overridden.length },
+      ipAddress: req.headers.get('x-forwarded-for') || 'unknown',
+      userAgent: req.headers.get('user-agent') || undefined,
+      result: 'SUCCESS',
+    })
+
+    return NextResponse.json({ success: true, overridden })
+  }),
+)
@@ -0,0 +1,112 @@
+import { NextResponse } from 'next/server'
+import { prisma } from '@/lib/db'
+import { withAuth, withPermission, withValidation, param } from '@/lib/middleware/auth'
+import type { AuthContext } from '@/lib/middleware/auth'
+import { updateCustomRoleSchema } from '@/lib/validations/rbac'
+import { auditLog, diffForAudit } from '@/lib/audit'
+import { interceptForApproval } from '@/lib/approvals'
+import { checkRolePermissions, isBuiltInRoleName, toCustomRoleResponse } from '@/lib/auth/custom-roles'
+
+const include = {
+  createdBy: { select: { name: true } },
+  _count: { select: { users: true } },
+} as const
+
+// PUT /api/v1/rbac/roles/[id] — Rename, describe, or change the permissions of a custom role
+export const PUT = withAuth(
+  withPermission(
+    'settings:rbac',
+    withValidation(updateCustomRoleSchema, async (req, ctx) => {
+      const { user, body } = ctx as {
+        user: NonNullable<typeof ctx.user>
+        body: typeof ctx.body
+      }
+      const id = param(ctx as unknown as AuthContext, 'id')
+
+      const existing = await prisma.customRole.findUnique({ where: { id } })
+      if (!existing) {
+        return NextResponse.json({ error: 'Role not found' }, { status: 404 })
+      }
+      if (body.name !== undefined && body.name !== existing.name) {
+        if (isBuiltInRoleName(body.name)) {
+          return NextResponse.json({ error: `${body.name} is a built-in role` }, { status: 400 })
+        }
+        const clash = await prisma.customRole.findUnique({ where: { name: body.name } })
+        if (clash) {
+          return NextResponse.json({ error: `Role "${body.name}" already exists` }, { status: 409 })
+        }
+      }
+      if (body.permissions) {
+        const invalid = checkRolePermissions(body.permissions)
+        if (invalid) {
+          return NextResponse.json({ error: invalid }, { status: 400 })
+        }
+      }
+
+      const data = {
+        name: body.name,
+        description: body.description,
+        permissions: body.permissions ? [...new Set(body.permissions)] : undefined,
+      }
+
+      // New permissions reach every holder of the role at once
+      if (
+        data.permissions &&
+        (data.permissions.length !== existing.permissions.length ||
+          data.permissions.some((p) => !existing.permissions.includes(p)))
+      ) {
+        const pending = await interceptForApproval(req, {
+          action: 'RBAC_POLICY_CHANGE',
+          resource: 'custom_role',
+          resourceId: id,
+          payload: { roleId: id, ...data },
+          summary: { name: existing.name, permissions: data.permissions.join(',') },
+          requestedById: user.id,
+        })
+        if (pending) return pending
+      }
+      const role = await prisma.customRole.update({ where: { id }, data, include })
+
+      auditLog({
+        userId: user.id,
+        action: 'CUSTOM_ROLE_UPDATE',
+        resource: 'custom_role',
+        resourceId: id,
+        details: { name: role.name, users: role._count.users },
+        changes: diffForAudit(existing, data),
+        ipAddress: req.headers.get('x-forwarded-for') || 'unknown',
+        userAgent: req.headers.get('user-agent') || undefined,
+        result: 'SUCCESS',
+      })
+
+      return NextResponse.json({ role: toCustomRoleResponse(role) })
+    }),
+  ),
+)
+
+// DELETE /api/v1/rbac/roles/[id] — Remove a custom role; its users keep only their built-in role
+export const DELETE = withAuth(
+  withPermission('settings:rbac', async (req, ctx) => {
+    const id = param(ctx, 'id')
+
+    const existing = await prisma.customRole.findUnique({ where: { id }, include })
+    if (!existing) {
+      return NextResponse.json({ error: 'Role not found' }, { status: 404 })
+    }
+
+    await prisma.customRole.delete({ where: { id } })
+
+    auditLog({
+      userId: ctx.user.id,
+      action: 'CUSTOM_ROLE_DELETE',
+      resource: 'custom_role',
+      resourceId: id,
+      details: { name: existing.name, users: existing._count.users },
+      ipAddress: req.headers.get('x-forwarded-for') || 'unknown',
+      userAgent: req.headers.get('user-agent') || undefined,
+      result: 'SUCCESS',
+    })
+
+    return NextResponse.json({ success: true })
+  }),
+)
@@ -0,0 +1,76 @@
+import { NextResponse } from 'next/server'
+import { prisma } from '@/lib/db'
+import { stringList } from '@/lib/db-dialect'
+import { withAuth, withPermission, withValidation } from '@/lib/middleware/auth'
+import { createCustomRoleSchema } from '@/lib/validations/rbac'
+import { auditLog } from '@/lib/audit'
+import {
+  checkRolePermissions,
+  grantablePermissions,
+  isBuiltInRoleName,
+  toCustomRoleResponse,
+} from '@/lib/auth/custom-roles'
+
+const include = {
+  createdBy: { select: { name: true } },
+  _count: { select: { users: true } },
+} as const
+
+// GET /api/v1/rbac/roles — Custom roles, plus the permissions they may grant
+export const GET = withAuth(
+  withPermission('settings:rbac', async () => {
+    const roles = await prisma.customRole.findMany({ include, orderBy: { name: 'asc' } })
+    return NextResponse.json({
+      roles: roles.map(toCustomRoleResponse),
+      grantablePermissions: grantablePermissions(),
+    })
+  }),
+)
+
+// POST /api/v1/rbac/roles — Define a custom role
+export const POST = withAuth(
+  withPermission(
+    'settings:rbac',
+    withValidation(createCustomRoleSchema, async (req, ctx) => {
+      const { user, body } = ctx as {
+        user: NonNullable<typeof ctx.user>
+        body: typeof ctx.body
+      }
+
+      if (isBuiltInRoleName(body.name)) {
+        return NextResponse.json({ error: `${body.name} is a built-in role` }, { status: 400 })
+      }
+      const invalid = checkRolePermissions(body.permissions)
+      if (invalid) {
+        return NextResponse.json({ error: invalid }, { status: 400 })
+      }
+      const existing = await prisma.customRole.findUnique({ where: { name: body.name } })
+      if (existing) {
+        return NextResponse.json({ error: `Role "${body.name}" already exists` }, { status: 409 })
+      }
+
+      const role = await prisma.customRole.create({
+        data: {
+          name: body.name,
+          description: body.description ?? null,
+          permissions: [...new Set(body.permissions)],
+          createdById: user.id,
+        },
+        include,
+      })
+
+      auditLog({
+        userId: user.id,
+        action: 'CUSTOM_ROLE_CREATE',
+        resource: 'custom_role',
+        resourceId: role.id,
+        details: { name: role.name, permissions: stringList(role.permissions).join(',') },
+        ipAddress: req.headers.get('x-forwarded-for') || 'unknown',
+        userAgent: req.headers.get('user-agent') || undefined,
+        result: 'SUCCESS',
+      })
+
+      return NextResponse.json({ role: toCustomRoleResponse(role) }, { status: 201 })
+    }),
+  ),
+)
@@ -2,7 +2,7 @@ import { NextResponse } from 'next/server'
 import type { Prisma } from '@/generated/prisma'
 import { prisma } from '@/lib/db'
 import { withAuth, withPermission, withValidation, param } from '@/lib/middleware/auth'
-import { auditLog } from '@/lib/audit'
+import { auditLog, diffForAudit } from '@/lib/audit'
 import { updateResourceSchema } from '@/lib/validations/resource'
 import { encryptCredential, maskCredential, decryptCredential } from '@/lib/resources/credential-utils'
 import { syncProviderToInstances } from '@/lib/config-editor/provider-sync'
@@ -127,6 +127,19 @@ export const PUT = withAuth(
         resource: 'resource',
         resourceId: id,
         details: { name: updated.name },
+        changes: diffForAudit(
+          resource,
+          {
+            name: body.name,
+            type: body.type,
+            provider: body.provider,
+            description: body.description,
+            config: body.config,
+            isDefault: body.isDefault,
+            credentials: body.apiKey,
+          },
+          ['credentials'],
+        ),
         ipAddress: req.headers.get('x-forwarded-for') || 'unknown',
         userAgent: req.headers.get('user-agent') || undefined,
         result: 'SUCCESS',
@@ -1,6 +1,7 @@
 import { NextResponse } from 'next/server'
 import type { Prisma } from '@/generated/prisma'
 import { prisma } from '@/lib/db'
+import { containsInsensitive } from '@/lib/db-dialect'
 import { withAuth, withPermission, withValidation } from '@/lib/middleware/auth'
 import { auditLog } from '@/lib/audit'
 import { createResourceSchema } from '@/lib/validations/resource'
@@ -28,8 +29,8 @@ export const GET = withAuth(
       const q = search.trim().slice(0, 100)
       if (q) {
         where.OR = [
-          { name: { contains: q, mode: 'insensitive' } },
-          { description: { contains: q, mode: 'insensitive' } },
+          { name: containsInsensitive(q) },
+          { description: containsInsensitive(q) },
         ]
       }
     }
@@ -0,0 +1,73 @@
+import { NextResponse } from 'next/server'
+import type { Prisma } from '@/generated/prisma'
+import { prisma } from '@/lib/db'
+import { withAuth, withValidation, param } from '@/lib/middleware/auth'
+import type { AuthContext } from '@/lib/middleware/auth'
+import { updateSavedFilterSchema } from '@/lib/validations/saved-filter'
+import { findVisibleFilter, toSavedFilterResponse, unknownFilterParams, type SavedFilterEndpoint } from '@/lib/saved-filters'
+
+// GET /api/v1/saved-filters/[id] — One own or shared filter
+export const GET = withAuth(async (_req, ctx) => {
+  const filter = await findVisibleFilter(param(ctx, 'id'), ctx.user)
+  if (!filter) {
+    return NextResponse.json({ error: 'Saved filter not found' }, { status: 404 })
+  }
+  return NextResponse.json({ filter: toSavedFilterResponse(filter) })
+})
+
+// PUT /api/v1/saved-filters/[id] — Rename, replace the parameters, or share (owner only)
+export const PUT = withAuth(
+  withValidation(updateSavedFilterSchema, async (_req, ctx) => {
+    const { user, body } = ctx as {
+      user: NonNullable<typeof ctx.user>
+      body: typeof ctx.body
+    }
+    const id = param(ctx as unknown as AuthContext, 'id')
+
+    const existing = await prisma.savedFilter.findUnique({ where: { id } })
+    if (!existing || existing.ownerId !== user.id) {
+      return NextResponse.json({ error: 'Saved filter not found' }, { status: 404 })
+    }
+    if (body.query) {
+      const unknown = unknownFilterParams(existing.endpoint as SavedFilterEndpoint, body.query)
+      if (unknown.length > 0) {
+        return NextResponse.json(
+          { error: `Parameters not taken by ${existing.endpoint}: ${unknown.join(', ')}` },
+          { status: 400 },
+        )
+      }
+    }
+    if (body.name && body.name !== existing.name) {
+      const clash = await prisma.savedFilter.findUnique({
+        where: { ownerId_endpoint_name: { ownerId: user.id, endpoint: existing.endpoint, name: body.name } },
+      })
+      if (clash) {
+        return NextResponse.json({ error: `A filter named "${body.name}" already exists` }, { status: 409 })
+      }
+    }
+
+    const filter = await prisma.savedFilter.update({
+      where: { id },
+      data: {
+        name: body.name,
+        query: body.query as Prisma.InputJsonValue | undefined,
+        shared: body.shared,
+      },
+      include: { owner: { select: { name: true } } },
+    })
+    return NextResponse.json({ filter: toSavedFilterResponse(filter) })
+  }),
+)
+
+// DELETE /api/v1/saved-filters/[id] — Owner only; system admins may remove shared filters
+export const DELETE = withAuth(async (_req, ctx) => {
+  const id = param(ctx, 'id')
+  const existing = await prisma.savedFilter.findUnique({ where: { id } })
+  const allowed = existing && (existing.ownerId === ctx.user.id || (existing.shared && ctx.user.role === 'SYSTEM_ADMIN'))
+  if (!allowed) {
+    return NextResponse.json({ error: 'Saved filter not found' }, { status: 404 })
+  }
+
+  await prisma.savedFilter.delete({ where: { id } })
+  return NextResponse.json({ success: true })
+})
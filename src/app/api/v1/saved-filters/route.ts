@@ -0,0 +1,72 @@
+import { NextResponse } from 'next/server'
+import type { Prisma } from '@/generated/prisma'
+import { prisma } from '@/lib/db'
+import { withAuth, withValidation } from '@/lib/middleware/auth'
+import { createSavedFilterSchema } from '@/lib/validations/saved-filter'
+import {
+  SAVED_FILTER_ENDPOINTS,
+  canUseEndpoint,
+  toSavedFilterResponse,
+  unknownFilterParams,
+  type SavedFilterEndpoint,
+} from '@/lib/saved-filters'
+
+// GET /api/v1/saved-filters — Own filters plus shared ones, for endpoints the user may list; ?endpoint= narrows
+export const GET = withAuth(async (req, ctx) => {
+  const endpoint = new URL(req.url).searchParams.get('endpoint')
+  if (endpoint && !(endpoint in SAVED_FILTER_ENDPOINTS)) {
+    return NextResponse.json({ error: `Unknown endpoint "${endpoint}"` }, { status: 400 })
+  }
+  const endpoints = (endpoint ? [endpoint] : Object.keys(SAVED_FILTER_ENDPOINTS)) as SavedFilterEndpoint[]
+  const usable = endpoints.filter((e) => canUseEndpoint(ctx.user, e))
+
+  const filters = await prisma.savedFilter.findMany({
+    where: {
+      endpoint: { in: usable },
+      OR: [{ ownerId: ctx.user.id }, { shared: true }],
+    },
+    include: { owner: { select: { name: true } } },
+    orderBy: [{ endpoint: 'asc' }, { name: 'asc' }],
+  })
+  return NextResponse.json({ filters: filters.map(toSavedFilterResponse) })
+})
+
+// POST /api/v1/saved-filters — Save a filter for a list endpoint
+export const POST = withAuth(
+  withValidation(createSavedFilterSchema, async (_req, ctx) => {
+    const { user, body } = ctx as {
+      user: NonNullable<typeof ctx.user>
+      body: typeof ctx.body
+    }
+
+    if (!canUseEndpoint(user, body.endpoint)) {
+      return NextResponse.json({ error: '权限不足' }, { status: 403 })
+    }
+    const unknown = unknownFilterParams(body.endpoint, body.query)
+    if (unknown.length > 0) {
+      return NextResponse.json(
+        { error: `Parameters not taken by ${body.endpoint}: ${unknown.join(', ')}` },
+        { status: 400 },
+      )
+    }
+
+    const existing = await prisma.savedFilter.findUnique({
+      where: { ownerId_endpoint_name: { ownerId: user.id, endpoint: body.endpoint, name: body.name } },
+    })
+    if (existing) {
+      return NextResponse.json({ error: `A filter named "${body.name}" already exists` }, { status: 409 })
+    }
+
+    const filter = await prisma.savedFilter.create({
+      data: {
+        name: body.name,
+        endpoint: body.endpoint,
+        query: body.query as Prisma.InputJsonValue,
+        shared: body.shared ?? false,
+        ownerId: user.id,
+      },
+      include: { owner: { select: { name: true } } },
+    })
+    return NextResponse.json({ filter: toSavedFilterResponse(filter) }, { status: 201 })
+  }),
+)
@@ -0,0 +1,24 @@
+import { NextResponse } from 'next/server'
+import { withAuth } from '@/lib/middleware/auth'
+import { searchAll, SEARCH_TYPES } from '@/lib/search'
+import type { SearchResponse, SearchResultType } from '@/types/search'
+
+// GET /api/v1/search?q=&types=user,skill&limit= — Org-wide search
+// Each type is filtered by the caller's own permissions.
+export const GET = withAuth(async (req, { user }) => {
+  const url = new URL(req.url)
+  const q = url.searchParams.get('q')?.trim().slice(0, 100) ?? ''
+  const limit = Math.min(50, Math.max(1, parseInt(url.searchParams.get('limit') || '10')))
+  const types = (url.searchParams.get('types') ?? '')
+    .split(',')
+    .filter((t): t is SearchResultType => (SEARCH_TYPES as string[]).includes(t))
+
+  if (q.length < 2) {
+    return NextResponse.json({ error: 'Query must be at least 2 characters' }, { status: 400 })
+  }
+
+  const results = await searchAll(q, user, { types, limit })
+
+  const response: SearchResponse = { query: q, results }
+  return NextResponse.json(response)
+})
@@ -0,0 +1,42 @@
+import { NextResponse } from 'next/server'
+import { withAuth, withPermission, withValidation } from '@/lib/middleware/auth'
+import { updateAuditSamplingSchema } from '@/lib/validations/audit'
+import { getAuditSamplingRules, saveAuditSamplingRules, UNSAMPLED_RESOURCES } from '@/lib/audit-sampling'
+import { auditLog, diffForAudit } from '@/lib/audit'
+
+// GET /api/v1/settings/audit-sampling — Current sampling / exclusion rules
+export const GET = withAuth(
+  withPermission('settings:audit_sampling', async () => {
+    return NextResponse.json({ rules: getAuditSamplingRules(), unsampledResources: UNSAMPLED_RESOURCES })
+  }),
+)
+
+// PUT /api/v1/settings/audit-sampling — Replace the rule list (first match wins)
+export const PUT = withAuth(
+  withPermission(
+    'settings:audit_sampling',
+    withValidation(updateAuditSamplingSchema, async (req, ctx) => {
+      const { user, body } = ctx as {
+        user: NonNullable<typeof ctx.user>
+        body: typeof ctx.body
+      }
+
+      const before = getAuditSamplingRules()
+      await saveAuditSamplingRules(body.rules)
+
+      auditLog({
+        userId: user.id,
+        action: 'AUDIT_SAMPLING_UPDATE',
+        resource: 'system_config',
+        resourceId: 'audit_sampling',
+        details: { ruleCount: body.rules.length },
+        changes: diffForAudit({ rules: before }, body),
+        ipAddress: req.headers.get('x-forwarded-for') || 'unknown',
+        userAgent: req.headers.get('user-agent') || undefined,
+        result: 'SUCCESS',
+      })
+
+      return NextResponse.json({ rules: getAuditSamplingRules(), unsampledResources: UNSAMPLED_RESOURCES })
+    }),
+  ),
+)
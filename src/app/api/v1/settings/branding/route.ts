@@ -0,0 +1,42 @@
+import { NextResponse } from 'next/server'
+import { withAuth, withPermission, withValidation } from '@/lib/middleware/auth'
+import { brandingSchema } from '@/lib/validations/branding'
+import { getBranding, setBranding } from '@/lib/branding'
+import { auditLog, diffForAudit } from '@/lib/audit'
+
+// PUT /api/v1/settings/branding — Replace branding settings
+export const PUT = withAuth(
+  withPermission(
+    'settings:branding',
+    withValidation(brandingSchema, async (req, ctx) => {
+      const { user, body } = ctx as {
+        user: NonNullable<typeof ctx.user>
+        body: typeof ctx.body
+      }
+
+      const before = await getBranding()
+      await setBranding(body)
+
+      const changes = diffForAudit(
+        before as unknown as Record<string, unknown>,
+        body as unknown as Record<string, unknown>,
+      )
+      // Data URLs are large and not useful in an audit trail
+      if (changes.logoUrl) changes.logoUrl = { before: '(logo)', after: '(logo)' }
+
+      auditLog({
+        userId: user.id,
+        action: 'BRANDING_UPDATE',
+        resource: 'system_config',
+        resourceId: 'branding',
+        details: { productName: body.productName },
+        changes,
+        ipAddress: req.headers.get('x-forwarded-for') || 'unknown',
+        userAgent: req.headers.get('user-agent') || undefined,
+        result: 'SUCCESS',
+      })
+
+      return NextResponse.json(await getBranding())
+    }),
+  ),
+)
@@ -0,0 +1,54 @@
+import { NextResponse } from 'next/server'
+import { withAuth, withPermission } from '@/lib/middleware/auth'
+import {
+  encryptExistingSnapshots,
+  getSnapshotEncryptionStatus,
+  isSnapshotEncryptionEnabled,
+} from '@/lib/chat/snapshot-crypto'
+import { auditLog } from '@/lib/audit'
+import { createLogger } from '@/lib/logger'
+
+const log = createLogger('chat:encryption')
+
+// GET /api/v1/settings/encryption — Snapshot encryption state and progress
+export const GET = withAuth(
+  withPermission('settings:encryption', async () => {
+    return NextResponse.json(await getSnapshotEncryptionStatus())
+  }),
+)
+
+// POST /api/v1/settings/encryption — Encrypt existing plaintext snapshots in the background
+export const POST = withAuth(
+  withPermission('settings:encryption', async (req, { user }) => {
+    if (!isSnapshotEncryptionEnabled()) {
+      return NextResponse.json(
+        { error: 'Snapshot encryption is disabled (set SNAPSHOT_ENCRYPTION=true)' },
+        { status: 400 },
+      )
+    }
+
+    const status = await getSnapshotEncryptionStatus()
+    if (status.migrationRunning) {
+      return NextResponse.json({ error: 'Migration already running' }, { status: 409 })
+    }
+
+    encryptExistingSnapshots()
+      .then((count) => {
+        if (count !== null) log.info('Snapshot encryption migration finished', { count })
+      })
+      .catch((err) => log.error('Snapshot encryption migration failed', { err }))
+
+    auditLog({
+      userId: user.id,
+      action: 'SNAPSHOT_ENCRYPTION_MIGRATE',
+      resource: 'system_config',
+      resourceId: 'snapshot_encryption',
+      details: { plaintext: status.plaintext },
+      ipAddress: req.headers.get('x-forwarded-for') || 'unknown',
+      userAgent: req.headers.get('user-agent') || undefined,
+      result: 'SUCCESS',
+    })
+
+    return NextResponse.json({ ...status, migrationRunning: true }, { status: 202 })
+  }),
+)
@@ -0,0 +1,46 @@
+import { NextResponse } from 'next/server'
+import { withAuth, withPermission, withValidation } from '@/lib/middleware/auth'
+import { updateLoggingSchema } from '@/lib/validations/logging'
+import { getLoggingConfig } from '@/lib/logger'
+import { saveLogLevels } from '@/lib/logger/config'
+import { auditLog, diffForAudit } from '@/lib/audit'
+
+// GET /api/v1/settings/logging — Current levels, known modules, and active sinks
+export const GET = withAuth(
+  withPermission('settings:logging', async () => {
+    return NextResponse.json(getLoggingConfig())
+  }),
+)
+
+// PUT /api/v1/settings/logging — Change log levels without a restart
+export const PUT = withAuth(
+  withPermission(
+    'settings:logging',
+    withValidation(updateLoggingSchema, async (req, ctx) => {
+      const { user, body } = ctx as {
+        user: NonNullable<typeof ctx.user>
+        body: typeof ctx.body
+      }
+
+      const before = getLoggingConfig()
+      await saveLogLevels(body.defaultLevel, body.moduleLevels)
+
+      auditLog({
+        userId: user.id,
+        action: 'LOGGING_UPDATE',
+        resource: 'system_config',
+        resourceId: 'log_levels',
+        details: { defaultLevel: body.defaultLevel },
+        changes: diffForAudit(
+          { defaultLevel: before.defaultLevel, moduleLevels: before.moduleLevels },
+          body,
+        ),
+        ipAddress: req.headers.get('x-forwarded-for') || 'unknown',
+        userAgent: req.headers.get('user-agent') || undefined,
+        result: 'SUCCESS',
+      })
+
+      return NextResponse.json(getLoggingConfig())
+    }),
+  ),
+)
@@ -0,0 +1,43 @@
+import { NextResponse } from 'next/server'
+import { withAuth, withPermission, withValidation } from '@/lib/middleware/auth'
+import { updateToolRedactionSchema } from '@/lib/validations/chat'
+import { getToolRedactionConfig, loadToolRedactionConfig, saveToolRedactionConfig } from '@/lib/chat/redaction'
+import { auditLog, diffForAudit } from '@/lib/audit'
+
+// GET /api/v1/settings/tool-redaction — Global rules and per-department overview
+export const GET = withAuth(
+  withPermission('settings:tool_redaction', async () => {
+    return NextResponse.json(getToolRedactionConfig())
+  }),
+)
+
+// PUT /api/v1/settings/tool-redaction — Replace the global rules (department rules are kept)
+export const PUT = withAuth(
+  withPermission(
+    'settings:tool_redaction',
+    withValidation(updateToolRedactionSchema, async (req, ctx) => {
+      const { user, body } = ctx as {
+        user: NonNullable<typeof ctx.user>
+        body: typeof ctx.body
+      }
+
+      await loadToolRedactionConfig()
+      const before = getToolRedactionConfig()
+      await saveToolRedactionConfig({ ...before, builtin: body.builtin, rules: body.rules })
+
+      auditLog({
+        userId: user.id,
+        action: 'TOOL_REDACTION_UPDATE',
+        resource: 'system_config',
+        resourceId: 'tool_output_redaction',
+        details: { builtin: body.builtin, ruleCount: body.rules.length },
+        changes: diffForAudit({ builtin: before.builtin, rules: before.rules }, body),
+        ipAddress: req.headers.get('x-forwarded-for') || 'unknown',
+        userAgent: req.headers.get('user-agent') || undefined,
+        result: 'SUCCESS',
+      })
+
+      return NextResponse.json(getToolRedactionConfig())
+    }),
+  ),
+)
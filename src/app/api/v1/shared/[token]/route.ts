@@ -0,0 +1,86 @@
+import { NextRequest, NextResponse } from 'next/server'
+import { prisma } from '@/lib/db'
+import { checkRateLimit } from '@/lib/redis'
+import { verifyPassword } from '@/lib/auth/password'
+import { viewSessionShareSchema } from '@/lib/validations/chat'
+import { hashShareToken, shareState } from '@/lib/chat/shares'
+import { snapshotRowsToBatches } from '@/lib/chat/snapshot-helpers'
+import { decryptSnapshots } from '@/lib/chat/snapshot-crypto'
+import type { SharedSessionResponse } from '@/types/chat'
+
+const PASSWORD_ATTEMPTS = 10
+const PASSWORD_WINDOW_SEC = 15 * 60
+
+// POST /api/v1/shared/[token] — Public, read-only view of a shared session
+// POST rather than GET so the optional password travels in the body.
+export async function POST(
+  req: NextRequest,
+  { params }: { params: Promise<{ token: string }> },
+) {
+  const { token } = await params
+
+  let body: unknown = {}
+  try {
+    body = await req.json()
+  } catch {
+    // Empty body is fine for unprotected shares
+  }
+  const parsed = viewSessionShareSchema.safeParse(body)
+  if (!parsed.success) {
+    return NextResponse.json({ error: 'Invalid request body' }, { status: 400 })
+  }
+
+  const tokenHash = hashShareToken(token)
+  const share = await prisma.sessionShare.findUnique({
+    where: { tokenHash },
+    include: {
+      chatSession: {
+        include: { instance: { select: { name: true } } },
+      },
+      createdBy: { select: { name: true } },
+    },
+  })
+
+  // Same response for unknown, expired, and revoked links
+  if (!share || shareState(share) !== 'active') {
+    return NextResponse.json({ error: 'This link is invalid or has expired' }, { status: 404 })
+  }
+
+  if (share.passwordHash) {
+    const { password } = parsed.data
+    if (!password) {
+      return NextResponse.json({ error: 'Password required', passwordRequired: true }, { status: 401 })
+    }
+    const limit = await checkRateLimit(`share_password:${tokenHash}`, PASSWORD_ATTEMPTS, PASSWORD_WINDOW_SEC)
+    if (!limit.allowed) {
+      return NextResponse.json({ error: 'Too many attempts. Try again later.' }, { status: 429 })
+    }
+    if (!(await verifyPassword(password, share.passwordHash))) {
+      return NextResponse.json({ error: 'Incorrect password', passwordRequired: true }, { status: 401 })
+    }
+  }
+
+  // Only snapshots that existed when the link was created
+  const rows = await decryptSnapshots(
+    await prisma.chatMessageSnapshot.findMany({
+      where: { chatSessionId: share.chatSessionId, createdAt: { lte: share.createdAt } },
+      orderBy: [{ createdAt: 'asc' }, { orderIndex: 'asc' }],
+    }),
+  )
+
+  await prisma.sessionShare.update({
+    where: { id: share.id },
+    data: { viewCount: { increment: 1 }, lastViewedAt: new Date() },
+  })
+
+  const response: SharedSessionResponse = {
+    title: share.chatSession.title,
+    agentId: share.chatSession.agentId,
+    instanceName: share.chatSession.instance.name,
+    sharedBy: share.createdBy.name,
+    sharedAt: share.createdAt.toISOString(),
+    expiresAt: share.expiresAt.toISOString(),
+    snapshots: snapshotRowsToBatches(rows),
+  }
+  return NextResponse.json(response)
+}
@@ -1,5 +1,6 @@
 import { NextResponse } from 'next/server'
 import { prisma } from '@/lib/db'
+import { stringList } from '@/lib/db-dialect'
 import { withAuth, withPermission, withValidation, param } from '@/lib/middleware/auth'
 import { auditLog } from '@/lib/audit'
 import { updateSkillSchema } from '@/lib/validations/skill'
@@ -46,7 +47,7 @@ export const GET = withAuth(
       category: skill.category as SkillCategory,
       source: skill.source as SkillSource,
       version: skill.version,
-      tags: skill.tags,
+      tags: stringList(skill.tags),
       creatorName: skill.creator.name,
       departments: skill.departments.map((d) => ({ id: d.id, name: d.name })),
       installationCount: skill._count.installations,
@@ -190,7 +191,7 @@ export const PUT = withAuth(
         category: updated.category,
         source: updated.source,
         version: updated.version,
-        tags: updated.tags,
+        tags: stringList(updated.tags),
         creatorName: updated.creator.name,
         departments: updated.departments.map((d) => ({ id: d.id, name: d.name })),
         installationCount: updated._count.installations,
@@ -1,14 +1,34 @@
 import { NextResponse } from 'next/server'
 import type { Prisma } from '@/generated/prisma'
 import { prisma } from '@/lib/db'
+import { containsInsensitive, listHas, stringList } from '@/lib/db-dialect'
 import { withAuth, withPermission, withValidation } from '@/lib/middleware/auth'
 import { auditLog } from '@/lib/audit'
 import { createSkillSchema } from '@/lib/validations/skill'
+import { parseListParams, pickFields, type ListSpec } from '@/lib/list-params'
 import { isSkillVisible, canCreateSkillWithCategory, getDefaultSkillCategory } from '@/lib/skills/permissions'
 import { ensureSkillDir, generateDefaultSkillMd, writeSkillFile, parseFrontmatter } from '@/lib/skills/fs'
 import type { SkillOverview, SkillListResponse, SkillCategory } from '@/types/skill'
 
-// GET /api/v1/skills — List skills with pagination and filtering
+const skillListSpec: ListSpec = {
+  sortable: {
+    name: 'name',
+    slug: 'slug',
+    category: 'category',
+    source: 'source',
+    version: 'version',
+    creatorName: 'creator.name',
+    createdAt: 'createdAt',
+    updatedAt: 'updatedAt',
+  },
+  defaultSort: [{ field: 'updatedAt', direction: 'desc' }],
+  fields: [
+    'id', 'slug', 'name', 'description', 'emoji', 'category', 'source', 'version', 'tags',
+    'creatorName', 'departments', 'installationCount', 'createdAt', 'updatedAt',
+  ],
+}
+
+// GET /api/v1/skills — List skills with pagination and filtering; ?sort= and ?fields= (lib/list-params)
 export const GET = withAuth(
   withPermission('skills:develop', async (req, { user }) => {
     const url = new URL(req.url)
@@ -18,17 +38,19 @@ export const GET = withAuth(
     const source = url.searchParams.get('source') as 'LOCAL' | 'CLAWHUB' | null
     const tag = url.searchParams.get('tag')
     const search = url.searchParams.get('search')
+    const list = parseListParams<Prisma.SkillOrderByWithRelationInput>(url.searchParams, skillListSpec)
+    if (list instanceof NextResponse) return list
 
     // Build where clause
     const where: Prisma.SkillWhereInput = {}
     if (category) where.category = category
     if (source) where.source = source
-    if (tag) where.tags = { has: tag }
+    if (tag) where.tags = listHas(tag)
     if (search) {
       where.OR = [
-        { name: { contains: search, mode: 'insensitive' } },
-        { slug: { contains: search, mode: 'insensitive' } },
-        { description: { contains: search, mode: 'insensitive' } },
+        { name: containsInsensitive(search) },
+        { slug: containsInsensitive(search) },
+        { description: containsInsensitive(search) },
       ]
     }
 
@@ -40,7 +62,7 @@ export const GET = withAuth(
           departments: { select: { id: true, name: true } },
           _count: { select: { installations: true } },
         },
-        orderBy: { updatedAt: 'desc' },
+        orderBy: list.orderBy,
         skip: (page - 1) * pageSize,
         take: pageSize,
       }),
@@ -59,7 +81,7 @@ export const GET = withAuth(
         category: skill.category as SkillCategory,
         source: skill.source as 'LOCAL' | 'CLAWHUB',
         version: skill.version,
-        tags: skill.tags,
+        tags: stringList(skill.tags),
         creatorName: skill.creator.name,
         departments: skill.departments.map((d) => ({ id: d.id, name: d.name })),
         installationCount: skill._count.installations,
@@ -68,7 +90,7 @@ export const GET = withAuth(
       }))
 
     const response: SkillListResponse = {
-      skills: visibleSkills,
+      skills: visibleSkills.map((s) => pickFields(s, list.fields) as SkillOverview),
       total,
       page,
       pageSize,
@@ -171,7 +193,7 @@ export const POST = withAuth(
           category: skill.category,
           source: skill.source,
           version: skill.version,
-          tags: skill.tags,
+          tags: stringList(skill.tags),
           creatorName: skill.creator.name,
           departments: skill.departments.map((d) => ({ id: d.id, name: d.name })),
           installationCount: skill._count.installations,
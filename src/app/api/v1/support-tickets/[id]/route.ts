@@ -0,0 +1,85 @@
+import { NextResponse } from 'next/server'
+import { prisma } from '@/lib/db'
+import { withAuth, withPermission, withValidation, param } from '@/lib/middleware/auth'
+import type { AuthContext } from '@/lib/middleware/auth'
+import { updateSupportTicketSchema } from '@/lib/validations/support-ticket'
+import { auditLog, diffForAudit } from '@/lib/audit'
+import { hasPermission } from '@/lib/auth/permissions'
+import { toTicketResponse, TICKET_INCLUDE } from '@/lib/support'
+import type { Prisma } from '@/generated/prisma'
+
+// GET /api/v1/support-tickets/[id] — A ticket with its diagnostics
+export const GET = withAuth(
+  withPermission('support:manage', async (_req, ctx) => {
+    const ticket = await prisma.supportTicket.findUnique({
+      where: { id: param(ctx, 'id') },
+      include: TICKET_INCLUDE,
+    })
+    if (!ticket) {
+      return NextResponse.json({ error: 'Ticket not found' }, { status: 404 })
+    }
+    return NextResponse.json({ ticket: toTicketResponse(ticket) })
+  }),
+)
+
+// PUT /api/v1/support-tickets/[id] — Resolve, reopen or reassign a ticket
+export const PUT = withAuth(
+  withPermission(
+    'support:manage',
+    withValidation(updateSupportTicketSchema, async (req, ctx) => {
+      const { user, body } = ctx as {
+        user: NonNullable<typeof ctx.user>
+        body: typeof ctx.body
+      }
+      const id = param(ctx as unknown as AuthContext, 'id')
+
+      const existing = await prisma.supportTicket.findUnique({ where: { id } })
+      if (!existing) {
+        return NextResponse.json({ error: 'Ticket not found' }, { status: 404 })
+      }
+
+      if (body.assigneeId) {
+        const assignee = await prisma.user.findUnique({
+          where: { id: body.assigneeId },
+          select: { role: true, status: true },
+        })
+        if (!assignee || assignee.status !== 'ACTIVE' || !hasPermission(assignee.role, 'support:manage')) {
+          return NextResponse.json({ error: 'Assignee cannot work support tickets' }, { status: 400 })
+        }
+      }
+
+      const data: Prisma.SupportTicketUncheckedUpdateInput = {}
+      if (body.assigneeId !== undefined) data.assigneeId = body.assigneeId
+      if (body.resolution !== undefined) data.resolution = body.resolution || null
+      if (body.status === 'RESOLVED' && existing.status !== 'RESOLVED') {
+        data.status = 'RESOLVED'
+        data.resolvedById = user.id
+        data.resolvedAt = new Date()
+      } else if (body.status === 'OPEN' && existing.status !== 'OPEN') {
+        data.status = 'OPEN'
+        data.resolvedById = null
+        data.resolvedAt = null
+      }
+
+      const ticket = await prisma.supportTicket.update({ where: { id }, data, include: TICKET_INCLUDE })
+
+      auditLog({
+        userId: user.id,
+        action: 'SUPPORT_TICKET_UPDATE',
+        resource: 'support_ticket',
+        resourceId: id,
+        details: { instanceId: existing.instanceId },
+        changes: diffForAudit(existing, {
+          status: ticket.status,
+          assigneeId: ticket.assigneeId,
+          resolution: ticket.resolution,
+        }),
+        ipAddress: req.headers.get('x-forwarded-for') || 'unknown',
+        userAgent: req.headers.get('user-agent') || undefined,
+        result: 'SUCCESS',
+      })
+
+      return NextResponse.json({ ticket: toTicketResponse(ticket) })
+    }),
+  ),
+)
@@ -0,0 +1,32 @@
+import { NextResponse } from 'next/server'
+import { prisma } from '@/lib/db'
+import { withAuth, withPermission } from '@/lib/middleware/auth'
+import { toTicketResponse, TICKET_INCLUDE } from '@/lib/support'
+import type { Prisma, SupportTicketStatus } from '@/generated/prisma'
+
+const STATUSES: SupportTicketStatus[] = ['OPEN', 'RESOLVED']
+
+// GET /api/v1/support-tickets?status=&assignee=me&instanceId= — Support tickets, newest first
+export const GET = withAuth(
+  withPermission('support:manage', async (req, ctx) => {
+    const url = new URL(req.url)
+    const status = url.searchParams.get('status')
+    const instanceId = url.searchParams.get('instanceId')
+    if (status && !STATUSES.includes(status as SupportTicketStatus)) {
+      return NextResponse.json({ error: 'Invalid status' }, { status: 400 })
+    }
+
+    const where: Prisma.SupportTicketWhereInput = {
+      ...(status ? { status: status as SupportTicketStatus } : {}),
+      ...(instanceId ? { instanceId } : {}),
+      ...(url.searchParams.get('assignee') === 'me' ? { assigneeId: ctx.user.id } : {}),
+    }
+    const tickets = await prisma.supportTicket.findMany({
+      where,
+      include: TICKET_INCLUDE,
+      orderBy: { createdAt: 'desc' },
+      take: 200,
+    })
+    return NextResponse.json({ tickets: tickets.map(toTicketResponse) })
+  }),
+)
@@ -0,0 +1,17 @@
+import { NextResponse } from 'next/server'
+import { withAuth, withPermission } from '@/lib/middleware/auth'
+import { buildSyncResponse, decodeCursor } from '@/lib/sync'
+
+// GET /api/v1/sync?since=<cursor> — Sessions, messages, agents and notifications
+// changed since the cursor (everything when omitted), for mobile/offline clients
+export const GET = withAuth(
+  withPermission('chat:use', async (req, { user }) => {
+    const raw = new URL(req.url).searchParams.get('since')
+    const since = raw ? decodeCursor(raw) : null
+    if (raw && !since) {
+      return NextResponse.json({ error: 'Invalid cursor' }, { status: 400 })
+    }
+
+    return NextResponse.json(await buildSyncResponse(user, since))
+  }),
+)
@@ -0,0 +1,74 @@
+import { NextResponse } from 'next/server'
+import type { Prisma, ToolInvocationStatus } from '@/generated/prisma'
+import { prisma } from '@/lib/db'
+import { withAuth, withPermission } from '@/lib/middleware/auth'
+import { toToolInvocationEntries } from '@/lib/chat/tool-invocations'
+import type { ToolInvocationListResponse } from '@/types/chat'
+
+const STATUSES = new Set<string>(['RUNNING', 'SUCCESS', 'ERROR', 'BLOCKED', 'DENIED', 'ABORTED'])
+
+// GET /api/v1/tool-invocations — What agents executed, with filtering + pagination
+export const GET = withAuth(
+  withPermission('audit:view_dept', async (req, ctx) => {
+    const { user } = ctx
+    const url = new URL(req.url)
+
+    const page = Math.max(1, parseInt(url.searchParams.get('page') || '1'))
+    const pageSize = Math.min(100, Math.max(1, parseInt(url.searchParams.get('pageSize') || '50')))
+    const name = url.searchParams.get('name')
+    const status = url.searchParams.get('status')
+    const instanceId = url.searchParams.get('instanceId')
+    const agentId = url.searchParams.get('agentId')
+    const userId = url.searchParams.get('userId')
+    const chatSessionId = url.searchParams.get('chatSessionId')
+    const startDate = url.searchParams.get('startDate')
+    const endDate = url.searchParams.get('endDate')
+
+    const where: Prisma.ToolInvocationWhereInput = {}
+
+    // DEPT_ADMIN: scope to department members only
+    if (user.role === 'DEPT_ADMIN' && user.departmentId) {
+      const deptUsers = await prisma.user.findMany({
+        where: { departmentId: user.departmentId },
+        select: { id: true },
+      })
+      where.userId = { in: deptUsers.map((u) => u.id) }
+    }
+
+    if (userId) where.AND = [{ userId }]
+    if (name) where.name = name
+    if (status && STATUSES.has(status)) where.status = status as ToolInvocationStatus
+    if (instanceId) where.instanceId = instanceId
+    if (agentId) where.agentId = agentId
+    if (chatSessionId) where.chatSessionId = chatSessionId
+
+    if (startDate || endDate) {
+      where.startedAt = {}
+      if (startDate) where.startedAt.gte = new Date(startDate)
+      if (endDate) where.startedAt.lte = new Date(endDate)
+    }
+
+    const [rows, total] = await Promise.all([
+      prisma.toolInvocation.findMany({
+        where,
+        orderBy: { startedAt: 'desc' },
+        skip: (page - 1) * pageSize,
+        take: pageSize,
+      }),
+      prisma.toolInvocation.count({ where }),
+    ])
+
+    const users = await prisma.user.findMany({
+      where: { id: { in: [...new Set(rows.map((r) => r.userId))] } },
+      select: { id: true, name: true },
+    })
+
+    const response: ToolInvocationListResponse = {
+      invocations: await toToolInvocationEntries(rows, new Map(users.map((u) => [u.id, u.name]))),
+      total,
+      page,
+      pageSize,
+    }
+    return NextResponse.json(response)
+  }),
+)
@@ -0,0 +1,52 @@
+import { NextResponse } from 'next/server'
+import { withAuth, param } from '@/lib/middleware/auth'
+import { userHasPermission } from '@/lib/auth/permissions'
+import { collectUserData, buildDataExportArchive } from '@/lib/users/data-rights'
+import { auditLog } from '@/lib/audit'
+import { parseRenderMode } from '@/lib/markdown'
+
+// GET /api/v1/users/[id]/data-export — Personal data archive (self or SYSTEM_ADMIN)
+// ?format=json returns the export inline instead of a .tar.gz
+// ?render=html adds sanitized HTML renderings of message content
+export const GET = withAuth(async (req, ctx) => {
+  const { user } = ctx
+  const id = param(ctx, 'id')
+
+  if (id !== user.id && !userHasPermission(user, 'users:data_rights')) {
+    return NextResponse.json({ error: 'Insufficient permissions' }, { status: 403 })
+  }
+
+  const data = await collectUserData(id, parseRenderMode(req.url))
+  if (!data) {
+    return NextResponse.json({ error: 'User not found' }, { status: 404 })
+  }
+
+  const format = new URL(req.url).searchParams.get('format') === 'json' ? 'json' : 'tar.gz'
+
+  auditLog({
+    userId: user.id,
+    action: 'DATA_EXPORT',
+    resource: 'compliance',
+    resourceId: id,
+    details: {
+      format,
+      sessions: data.sessions.length,
+      auditEntries: data.auditEntries.length,
+    },
+    ipAddress: req.headers.get('x-forwarded-for') || 'unknown',
+    userAgent: req.headers.get('user-agent') || undefined,
+    result: 'SUCCESS',
+  })
+
+  if (format === 'json') {
+    return NextResponse.json(data)
+  }
+
+  const archive = await buildDataExportArchive(data)
+  return new NextResponse(new Uint8Array(archive), {
+    headers: {
+      'Content-Type': 'application/gzip',
+      'Content-Disposition': `attachment; filename="user-data-${id}.tar.gz"`,
+    },
+  })
+})
@@ -0,0 +1,66 @@
+import { NextResponse } from 'next/server'
+import { prisma } from '@/lib/db'
+import { withAuth, withPermission, withValidation, param } from '@/lib/middleware/auth'
+import type { AuthContext } from '@/lib/middleware/auth'
+import { eraseUserSchema } from '@/lib/validations/user'
+import { eraseUserData } from '@/lib/users/data-rights'
+import { auditLog } from '@/lib/audit'
+
+// POST /api/v1/users/[id]/erasure — Erase personal data (irreversible)
+export const POST = withAuth(
+  withPermission(
+    'users:data_rights',
+    withValidation(eraseUserSchema, async (req, ctx) => {
+      const { user, body } = ctx as {
+        user: NonNullable<typeof ctx.user>
+        body: typeof ctx.body
+      }
+      const id = param(ctx as unknown as AuthContext, 'id')
+
+      if (id === user.id) {
+        return NextResponse.json({ error: 'Cannot erase your own account' }, { status: 400 })
+      }
+
+      const target = await prisma.user.findUnique({ where: { id } })
+      if (!target) {
+        return NextResponse.json({ error: 'User not found' }, { status: 404 })
+      }
+
+      // Typed confirmation guards against erasing the wrong account
+      if (body.confirmEmail !== target.email) {
+        return NextResponse.json(
+          { error: 'Confirmation email does not match the user' },
+          { status: 400 },
+        )
+      }
+
+      const report = await eraseUserData(id)
+
+      // The compliance record itself carries no personal data
+      auditLog({
+        userId: user.id,
+        action: 'DATA_ERASURE',
+        resource: 'compliance',
+        resourceId: id,
+        details: {
+          reason: body.reason ?? null,
+          sessionsDeleted: report.sessionsDeleted,
+          filesDeleted: report.filesDeleted,
+          refreshTokensRevoked: report.refreshTokensRevoked,
+          agentsReleased: report.agentsReleased,
+          auditEntriesPseudonymized: report.auditEntriesPseudonymized,
+          supportTicketsRedacted: report.supportTicketsRedacted,
+          apiKeysRevoked: report.apiKeysRevoked,
+          savedFiltersDeleted: report.savedFiltersDeleted,
+          exportJobsDeleted: report.exportJobsDeleted,
+          breakGlassSessionsRedacted: report.breakGlassSessionsRedacted,
+        },
+        ipAddress: req.headers.get('x-forwarded-for') || 'unknown',
+        userAgent: req.headers.get('user-agent') || undefined,
+        result: 'SUCCESS',
+      })
+
+      return NextResponse.json({ report })
+    }),
+  ),
+)
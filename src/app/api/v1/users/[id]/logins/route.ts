@@ -0,0 +1,61 @@
+import { NextResponse } from 'next/server'
+import { prisma } from '@/lib/db'
+import { withAuth, param } from '@/lib/middleware/auth'
+import { userHasPermission } from '@/lib/auth/permissions'
+import { parseUserAgent, lookupGeoIp } from '@/lib/auth/login-history'
+import type { LoginHistoryEntry, LoginHistoryResponse } from '@/types/user'
+
+// GET /api/v1/users/[id]/logins — Login history (SYSTEM_ADMIN or self)
+export const GET = withAuth(async (req, ctx) => {
+  const { user } = ctx
+  const id = param(ctx, 'id')
+
+  if (id !== user.id && !userHasPermission(user, 'users:view_logins')) {
+    return NextResponse.json({ error: 'Insufficient permissions' }, { status: 403 })
+  }
+
+  const url = new URL(req.url)
+  const page = Math.max(1, parseInt(url.searchParams.get('page') || '1'))
+  const pageSize = Math.min(100, Math.max(1, parseInt(url.searchParams.get('pageSize') || '20')))
+  const result = url.searchParams.get('result')
+
+  const target = await prisma.user.findUnique({ where: { id }, select: { id: true } })
+  if (!target) {
+    return NextResponse.json({ error: 'User not found' }, { status: 404 })
+  }
+
+  const where = {
+    userId: id,
+    action: 'LOGIN',
+    ...(result === 'SUCCESS' || result === 'FAILURE' ? { result } : {}),
+  }
+
+  const [logs, total] = await Promise.all([
+    prisma.auditLog.findMany({
+      where,
+      orderBy: { createdAt: 'desc' },
+      skip: (page - 1) * pageSize,
+      take: pageSize,
+    }),
+    prisma.auditLog.count({ where }),
+  ])
+
+  const logins: LoginHistoryEntry[] = await Promise.all(
+    logs.map(async (log) => {
+      const details = log.details as Record<string, unknown> | null
+      return {
+        id: log.id,
+        time: log.createdAt.toISOString(),
+        ipAddress: log.ipAddress,
+        device: parseUserAgent(log.userAgent),
+        userAgent: log.userAgent,
+        success: log.result === 'SUCCESS',
+        reason: typeof details?.reason === 'string' ? details.reason : null,
+        location: await lookupGeoIp(log.ipAddress),
+      }
+    }),
+  )
+
+  const response: LoginHistoryResponse = { logins, total, page, pageSize }
+  return NextResponse.json(response)
+})
@@ -0,0 +1,34 @@
+import { NextResponse } from 'next/server'
+import { prisma } from '@/lib/db'
+import { withAuth, withPermission, param } from '@/lib/middleware/auth'
+import { resetMfa } from '@/lib/auth/mfa'
+import { auditLog } from '@/lib/audit'
+
+// DELETE /api/v1/users/[id]/mfa — Admin resets a user's MFA (lost device); they enrol again
+export const DELETE = withAuth(
+  withPermission('users:reset_mfa', async (req, ctx) => {
+    const id = param(ctx, 'id')
+
+    const existing = await prisma.user.findUnique({ where: { id } })
+    if (!existing) {
+      return NextResponse.json({ error: 'User not found' }, { status: 404 })
+    }
+
+    await resetMfa(id)
+    // Sessions opened with the old factor end too
+    await prisma.refreshToken.deleteMany({ where: { userId: id } })
+
+    auditLog({
+      userId: ctx.user.id,
+      action: 'USER_RESET_MFA',
+      resource: 'user',
+      resourceId: id,
+      details: { targetEmail: existing.email },
+      ipAddress: req.headers.get('x-forwarded-for') || 'unknown',
+      userAgent: req.headers.get('user-agent') || undefined,
+      result: 'SUCCESS',
+    })
+
+    return NextResponse.json({ message: 'MFA reset' })
+  }),
+)
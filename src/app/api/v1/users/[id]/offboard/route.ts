@@ -0,0 +1,66 @@
+import { NextResponse } from 'next/server'
+import { prisma } from '@/lib/db'
+import { withAuth, withPermission, withValidation, param } from '@/lib/middleware/auth'
+import type { AuthContext } from '@/lib/middleware/auth'
+import { offboardUserSchema } from '@/lib/validations/user'
+import { offboardUser } from '@/lib/users/offboarding'
+import { auditLog } from '@/lib/audit'
+
+// POST /api/v1/users/[id]/offboard — Disable user and clean up what they own
+export const POST = withAuth(
+  withPermission(
+    'users:delete',
+    withValidation(offboardUserSchema, async (req, ctx) => {
+      const { user, body } = ctx as {
+        user: NonNullable<typeof ctx.user>
+        body: typeof ctx.body
+      }
+      const id = param(ctx as unknown as AuthContext, 'id')
+
+      if (id === user.id) {
+        return NextResponse.json({ error: 'Cannot offboard your own account' }, { status: 400 })
+      }
+
+      const target = await prisma.user.findUnique({ where: { id } })
+      if (!target) {
+        return NextResponse.json({ error: 'User not found' }, { status: 404 })
+      }
+
+      if (body.transferToUserId) {
+        if (body.transferToUserId === id) {
+          return NextResponse.json(
+            { error: 'Cannot transfer ownership to the offboarded user' },
+            { status: 400 },
+          )
+        }
+        const recipient = await prisma.user.findUnique({ where: { id: body.transferToUserId } })
+        if (!recipient || recipient.status !== 'ACTIVE') {
+          return NextResponse.json({ error: 'Transfer recipient not found or inactive' }, { status: 400 })
+        }
+      }
+
+      const report = await offboardUser(id, { transferToUserId: body.transferToUserId })
+
+      auditLog({
+        userId: user.id,
+        action: 'USER_OFFBOARD',
+        resource: 'user',
+        resourceId: id,
+        details: {
+          name: target.name,
+          email: target.email,
+          refreshTokensRevoked: report.refreshTokensRevoked,
+          sessionsArchived: report.sessionsArchived.length,
+          agentsTransferred: report.agentsTransferred.length,
+          skillsTransferred: report.skillsTransferred.length,
+          transferredTo: report.transferredTo,
+        },
+        ipAddress: req.headers.get('x-forwarded-for') || 'unknown',
+        userAgent: req.headers.get('user-agent') || undefined,
+        result: 'SUCCESS',
+      })
+
+      return NextResponse.json({ report })
+    }),
+  ),
+)
@@ -1,9 +1,19 @@
 import { NextResponse } from 'next/server'
 import { prisma } from '@/lib/db'
+import { stringList } from '@/lib/db-dialect'
 import { withAuth, withPermission, withValidation } from '@/lib/middleware/auth'
 import { updateUserSchema } from '@/lib/validations/user'
-import { hasPermission } from '@/lib/auth/permissions'
-import { auditLog } from '@/lib/audit'
+import { userHasPermission } from '@/lib/auth/permissions'
+import { auditLog, diffForAudit } from '@/lib/audit'
+import {
+  interceptForApproval,
+  isRoleElevation,
+  requiresApproval,
+  type ApprovalAction,
+} from '@/lib/approvals'
+import { enforceLicenseLimit } from '@/lib/license'
+import { roleExpiryData } from '@/lib/access-expiry'
+import { applyDepartmentChange } from '@/lib/users/department-change'
 import type { Prisma } from '@/generated/prisma'
 
 const userSelectFields = {
@@ -12,19 +22,29 @@ const userSelectFields = {
   name: true,
   avatar: true,
   role: true,
+  roleExpiresAt: true,
+  baseRole: true,
   departmentId: true,
   department: { select: { name: true } },
+  customRoleId: true,
+  customRole: { select: { name: true } },
   status: true,
+  mfaEnabled: true,
+  mfaRequired: true,
   lastLoginAt: true,
   createdAt: true,
   updatedAt: true,
 } as const
 
-function mapUser(u: { department?: { name: string } | null } & Record<string, unknown>) {
+function mapUser(
+  u: { department?: { name: string } | null; customRole?: { name: string } | null } & Record<string, unknown>,
+) {
   return {
     ...u,
     departmentName: u.department?.name ?? null,
     department: undefined,
+    customRoleName: u.customRole?.name ?? null,
+    customRole: undefined,
   }
 }
 
@@ -33,7 +53,7 @@ export const GET = withAuth(async (_req, { user, params }) => {
   const id = params?.id as string
 
   // Allow self-access or users:list permission
-  if (id !== user.id && !hasPermission(user.role, 'users:list')) {
+  if (id !== user.id && !userHasPermission(user, 'users:list')) {
     return NextResponse.json({ error: 'Insufficient permissions' }, { status: 403 })
   }
 
@@ -67,13 +87,21 @@ export const PUT = withAuth(
       }
 
       // Cannot change own role
-      if (id === user.id && body.role !== undefined) {
+      if (id === user.id && (body.role !== undefined || body.roleExpiresAt !== undefined)) {
         return NextResponse.json(
           { error: 'Cannot modify your own role' },
           { status: 400 },
         )
       }
 
+      // An expiry on its own extends / ends an existing temporary role
+      if (body.roleExpiresAt && body.role === undefined && !existing.roleExpiresAt) {
+        return NextResponse.json(
+          { error: 'User has no temporary role; set role together with roleExpiresAt' },
+          { status: 400 },
+        )
+      }
+
       // Validate departmentId if provided
       if (body.departmentId) {
         const dept = await prisma.department.findUnique({
@@ -84,7 +112,116 @@ export const PUT = withAuth(
         }
       }
 
-      const updateData: Prisma.UserUpdateInput = {}
+      const changed: Record<string, boolean> = {
+        name: body.name !== undefined && body.name !== existing.name,
+        role: body.role !== undefined && body.role !== existing.role,
+        roleExpiresAt:
+          body.roleExpiresAt !== undefined &&
+          (body.roleExpiresAt ? new Date(body.roleExpiresAt).getTime() : null) !==
+            (existing.roleExpiresAt?.getTime() ?? null),
+        departmentId:
+          body.departmentId !== undefined && (body.departmentId || null) !== existing.departmentId,
+        status: body.status !== undefined && body.status !== existing.status,
+        mfaRequired: body.mfaRequired !== undefined && body.mfaRequired !== existing.mfaRequired,
+        customRoleId:
+          body.customRoleId !== undefined && (body.customRoleId || null) !== existing.customRoleId,
+      }
+      const reactivating = body.status === 'ACTIVE' && existing.status !== 'ACTIVE'
+      const elevating =
+        (body.role !== undefined && isRoleElevation(existing.role, body.role)) ||
+        // Reactivating an account restores the role it holds
+        (reactivating && isRoleElevation('USER', body.role ?? existing.role))
+      const assigningCustomRole = changed.customRoleId && !!body.customRoleId
+
+      // A change behind the two-person rule is filed on its own: the approval
+      // only carries that change, so anything sent with it would be lost
+      const gated: { action: ApprovalAction; fields: string[] }[] = []
+      if (elevating && (await requiresApproval('USER_ROLE_ELEVATE'))) {
+        gated.push({
+          action: 'USER_ROLE_ELEVATE',
+          fields: ['role', 'roleExpiresAt', ...(reactivating ? ['status'] : [])],
+        })
+      }
+      if (assigningCustomRole && (await requiresApproval('USER_CUSTOM_ROLE_ASSIGN'))) {
+        gated.push({ action: 'USER_CUSTOM_ROLE_ASSIGN', fields: ['customRoleId'] })
+      }
+      if (gated.length > 1) {
+        return NextResponse.json(
+          { error: 'Role and custom role changes each need approval; submit them separately' },
+          { status: 400 },
+        )
+      }
+      if (gated.length === 1) {
+        const { action, fields } = gated[0]
+        const others = Object.keys(changed).filter((f) => changed[f] && !fields.includes(f))
+        if (others.length > 0) {
+          return NextResponse.json(
+            { error: `${action} needs approval; submit ${others.join(', ')} in a separate request` },
+            { status: 400 },
+          )
+        }
+      }
+
+      // Custom roles are assigned like roles: not to oneself, and behind approval when required
+      if (changed.customRoleId) {
+        if (id === user.id) {
+          return NextResponse.json({ error: 'Cannot modify your own role' }, { status: 400 })
+        }
+        if (body.customRoleId) {
+          const customRole = await prisma.customRole.findUnique({ where: { id: body.customRoleId } })
+          if (!customRole) {
+            return NextResponse.json({ error: 'Custom role not found' }, { status: 400 })
+          }
+          const pending = await interceptForApproval(req, {
+            action: 'USER_CUSTOM_ROLE_ASSIGN',
+            resource: 'user',
+            resourceId: id,
+            payload: { userId: id, customRoleId: customRole.id },
+            summary: {
+              name: existing.name,
+              customRole: customRole.name,
+              permissions: stringList(customRole.permissions).join(','),
+            },
+            requestedById: user.id,
+          })
+          if (pending) return pending
+        }
+      }
+
+      // Reactivating a user takes a seat
+      if (body.status === 'ACTIVE' && existing.status !== 'ACTIVE' && !existing.isServiceAccount) {
+        const blocked = await enforceLicenseLimit('seat')
+        if (blocked) return blocked
+      }
+
+      // Role elevation may be gated behind a second admin's approval
+      if (elevating) {
+        const role = body.role ?? existing.role
+        const pending = await interceptForApproval(req, {
+          action: 'USER_ROLE_ELEVATE',
+          resource: 'user',
+          resourceId: id,
+          payload: {
+            userId: id,
+            role,
+            roleExpiresAt: body.roleExpiresAt,
+            ...(reactivating ? { status: 'ACTIVE' } : {}),
+          },
+          summary: {
+            name: existing.name,
+            fromRole: existing.role,
+            toRole: role,
+            roleExpiresAt: body.roleExpiresAt ?? null,
+            reactivate: reactivating,
+          },
+          requestedById: user.id,
+        })
+        if (pending) return pending
+      }
+
+      const updateData: Prisma.UserUpdateInput = {
+        ...roleExpiryData(existing, body.roleExpiresAt, body.role !== undefined),
+      }
       if (body.name !== undefined) updateData.name = body.name
       if (body.role !== undefined) updateData.role = body.role
       if (body.departmentId !== undefined) {
@@ -93,6 +230,10 @@ export const PUT = withAuth(
           : { disconnect: true }
       }
       if (body.status !== undefined) updateData.status = body.status
+      if (body.mfaRequired !== undefined) updateData.mfaRequired = body.mfaRequired
+      if (body.customRoleId !== undefined) {
+        updateData.customRole = body.customRoleId ? { connect: { id: body.customRoleId } } : { disconnect: true }
+      }
 
       const updated = await prisma.user.update({
         where: { id },
@@ -100,12 +241,38 @@ export const PUT = withAuth(
         select: userSelectFields,
       })
 
+      // Sessions on agents the new department cannot use are archived now
+      // rather than failing mid-conversation
+      const departmentChange =
+        body.departmentId !== undefined && (body.departmentId || null) !== existing.departmentId
+          ? await applyDepartmentChange(id, existing.departmentId)
+          : null
+
       auditLog({
         userId: user.id,
         action: 'USER_UPDATE',
         resource: 'user',
         resourceId: id,
-        details: { name: updated.name },
+        details: {
+          name: updated.name,
+          ...(departmentChange
+            ? {
+                sessionsArchived: departmentChange.sessionsArchived.length,
+                sharesRevoked: departmentChange.sharesRevoked,
+              }
+            : {}),
+        },
+        changes: diffForAudit(existing, {
+          name: body.name,
+          role: body.role,
+          roleExpiresAt: body.roleExpiresAt !== undefined
+            ? (body.roleExpiresAt ? new Date(body.roleExpiresAt) : null)
+            : undefined,
+          departmentId: body.departmentId,
+          status: body.status,
+          mfaRequired: body.mfaRequired,
+          customRoleId: body.customRoleId,
+        }),
         ipAddress: req.headers.get('x-forwarded-for') || 'unknown',
         userAgent: req.headers.get('user-agent') || undefined,
         result: 'SUCCESS',
@@ -0,0 +1,47 @@
+import { NextResponse } from 'next/server'
+import { prisma } from '@/lib/db'
+import { withAuth, withPermission, param } from '@/lib/middleware/auth'
+import { auditLogCritical } from '@/lib/audit'
+import { authorizeSessionInspection, loadInspectedSession } from '@/lib/users/session-inspection'
+
+// GET /api/v1/users/[id]/sessions/[sessionId]?reason= — Transcript of a user's session, for compliance review
+export const GET = withAuth(
+  withPermission('users:inspect_sessions', async (req, ctx) => {
+    const id = param(ctx, 'id')
+    const sessionId = param(ctx, 'sessionId')
+
+    const target = await prisma.user.findUnique({
+      where: { id },
+      select: { id: true, name: true, email: true },
+    })
+    if (!target) {
+      return NextResponse.json({ error: 'User not found' }, { status: 404 })
+    }
+
+    const auth = await authorizeSessionInspection(req, ctx.user, target)
+    if (!auth.ok) return auth.response
+
+    const transcript = await loadInspectedSession(id, sessionId)
+    if (!transcript) {
+      return NextResponse.json({ error: 'Session not found' }, { status: 404 })
+    }
+
+    await auditLogCritical({
+      userId: ctx.user.id,
+      action: 'SESSION_INSPECT_TRANSCRIPT',
+      resource: 'compliance',
+      resourceId: id,
+      details: {
+        targetName: target.name,
+        chatSessionId: sessionId,
+        reason: auth.reason,
+        approvalId: auth.approvalId,
+      },
+      ipAddress: req.headers.get('x-forwarded-for') || 'unknown',
+      userAgent: req.headers.get('user-agent') || undefined,
+      result: 'SUCCESS',
+    })
+
+    return NextResponse.json(transcript)
+  }),
+)
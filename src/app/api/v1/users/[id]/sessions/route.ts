@@ -0,0 +1,43 @@
+import { NextResponse } from 'next/server'
+import { prisma } from '@/lib/db'
+import { withAuth, withPermission, param } from '@/lib/middleware/auth'
+import { auditLogCritical } from '@/lib/audit'
+import { authorizeSessionInspection, listUserSessions } from '@/lib/users/session-inspection'
+
+// GET /api/v1/users/[id]/sessions?reason= — A user's sessions, for compliance review
+export const GET = withAuth(
+  withPermission('users:inspect_sessions', async (req, ctx) => {
+    const id = param(ctx, 'id')
+
+    const target = await prisma.user.findUnique({
+      where: { id },
+      select: { id: true, name: true, email: true },
+    })
+    if (!target) {
+      return NextResponse.json({ error: 'User not found' }, { status: 404 })
+    }
+
+    const auth = await authorizeSessionInspection(req, ctx.user, target)
+    if (!auth.ok) return auth.response
+
+    const sessions = await listUserSessions(id)
+
+    await auditLogCritical({
+      userId: ctx.user.id,
+      action: 'SESSION_INSPECT_LIST',
+      resource: 'compliance',
+      resourceId: id,
+      details: {
+        targetName: target.name,
+        reason: auth.reason,
+        approvalId: auth.approvalId,
+        sessions: sessions.length,
+      },
+      ipAddress: req.headers.get('x-forwarded-for') || 'unknown',
+      userAgent: req.headers.get('user-agent') || undefined,
+      result: 'SUCCESS',
+    })
+
+    return NextResponse.json({ sessions })
+  }),
+)
@@ -0,0 +1,21 @@
+import { NextResponse } from 'next/server'
+import { withAuth, param } from '@/lib/middleware/auth'
+import {
+  completeOnboardingStep,
+  getOnboardingProgress,
+  isOnboardingStep,
+  onboardingSteps,
+} from '@/lib/users/onboarding'
+
+// POST /api/v1/users/me/onboarding/[step] — Mark a step done; detected steps
+// (e.g. join_department) are only accepted once they really are done
+export const POST = withAuth(async (_req, ctx) => {
+  const step = param(ctx, 'step')
+  if (!isOnboardingStep(step) || !onboardingSteps().includes(step)) {
+    return NextResponse.json({ error: 'Unknown onboarding step' }, { status: 404 })
+  }
+  if (!(await completeOnboardingStep(ctx.user.id, step))) {
+    return NextResponse.json({ error: 'The step is not done yet' }, { status: 409 })
+  }
+  return NextResponse.json({ onboarding: await getOnboardingProgress(ctx.user.id) })
+})
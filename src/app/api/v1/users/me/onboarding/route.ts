@@ -0,0 +1,8 @@
+import { NextResponse } from 'next/server'
+import { withAuth } from '@/lib/middleware/auth'
+import { getOnboardingProgress } from '@/lib/users/onboarding'
+
+// GET /api/v1/users/me/onboarding — The current user's onboarding checklist and progress
+export const GET = withAuth(async (_req, { user }) => {
+  return NextResponse.json({ onboarding: await getOnboardingProgress(user.id) })
+})
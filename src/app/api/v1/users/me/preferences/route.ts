@@ -0,0 +1,20 @@
+import { NextResponse } from 'next/server'
+import { withAuth, withValidation } from '@/lib/middleware/auth'
+import { updatePreferencesSchema } from '@/lib/validations/user'
+import { getPreferences, updatePreferences } from '@/lib/users/preferences'
+
+// GET /api/v1/users/me/preferences — The current user's preferences, defaults filled in
+export const GET = withAuth(async (_req, { user }) => {
+  return NextResponse.json({ preferences: await getPreferences(user.id) })
+})
+
+// PATCH /api/v1/users/me/preferences — Set some keys; null resets a key to its default
+export const PATCH = withAuth(
+  withValidation(updatePreferencesSchema, async (_req, ctx) => {
+    const { user, body } = ctx as {
+      user: NonNullable<typeof ctx.user>
+      body: typeof ctx.body
+    }
+    return NextResponse.json({ preferences: await updatePreferences(user.id, body) })
+  }),
+)
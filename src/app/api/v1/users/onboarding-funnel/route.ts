@@ -0,0 +1,16 @@
+import { NextResponse } from 'next/server'
+import { withAuth, withPermission } from '@/lib/middleware/auth'
+import { getOnboardingFunnel } from '@/lib/users/onboarding'
+
+// GET /api/v1/users/onboarding-funnel?departmentId= — How many active users
+// completed each onboarding step (DEPT_ADMIN: own department)
+export const GET = withAuth(
+  withPermission('users:list', async (req, { user }) => {
+    const requested = new URL(req.url).searchParams.get('departmentId') || null
+    if (user.role === 'DEPT_ADMIN' && !user.departmentId) {
+      return NextResponse.json({ error: 'You do not belong to a department' }, { status: 403 })
+    }
+    const departmentId = user.role === 'DEPT_ADMIN' ? user.departmentId : requested
+    return NextResponse.json({ funnel: await getOnboardingFunnel(departmentId) })
+  }),
+)
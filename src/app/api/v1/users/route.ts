@@ -1,9 +1,20 @@
 import { NextResponse } from 'next/server'
 import bcryptjs from 'bcryptjs'
 import { prisma } from '@/lib/db'
+import { containsInsensitive } from '@/lib/db-dialect'
 import { withAuth, withPermission, withValidation } from '@/lib/middleware/auth'
 import { createUserSchema } from '@/lib/validations/user'
 import { auditLog } from '@/lib/audit'
+import { enforceLicenseLimit } from '@/lib/license'
+import {
+  createApprovalRequest,
+  isRoleElevation,
+  requiresApproval,
+  toApprovalResponse,
+} from '@/lib/approvals'
+import { parseListParams, pickFields, type ListSpec } from '@/lib/list-params'
+import { applySavedFilter } from '@/lib/saved-filters'
+import type { Prisma } from '@/generated/prisma'
 
 const userSelectFields = {
   id: true,
@@ -11,23 +22,52 @@ const userSelectFields = {
   name: true,
   avatar: true,
   role: true,
+  roleExpiresAt: true,
+  baseRole: true,
   departmentId: true,
   department: { select: { name: true } },
+  customRoleId: true,
+  customRole: { select: { name: true } },
   status: true,
+  mfaEnabled: true,
+  mfaRequired: true,
   lastLoginAt: true,
   createdAt: true,
   updatedAt: true,
 } as const
 
-// GET /api/v1/users — Paginated list with search
+const userListSpec: ListSpec = {
+  sortable: {
+    name: 'name',
+    email: 'email',
+    role: 'role',
+    status: 'status',
+    departmentName: 'department.name',
+    lastLoginAt: 'lastLoginAt',
+    createdAt: 'createdAt',
+    updatedAt: 'updatedAt',
+  },
+  defaultSort: [{ field: 'createdAt', direction: 'desc' }],
+  fields: [
+    ...Object.keys(userSelectFields).filter((f) => f !== 'department' && f !== 'customRole'),
+    'departmentName',
+    'customRoleName',
+  ],
+}
+
+// GET /api/v1/users — Paginated list with search; ?sort= and ?fields= (lib/list-params), ?savedFilter= (lib/saved-filters)
 export const GET = withAuth(
   withPermission('users:list', async (req, { user }) => {
     const url = new URL(req.url)
-    const page = Math.max(1, parseInt(url.searchParams.get('page') || '1'))
-    const pageSize = Math.min(100, Math.max(1, parseInt(url.searchParams.get('pageSize') || '20')))
-    const search = url.searchParams.get('search') || ''
-    const statusFilter = url.searchParams.get('status') || ''
-    const departmentId = url.searchParams.get('departmentId') || ''
+    const query = await applySavedFilter(url.searchParams, 'users', user)
+    if (query instanceof NextResponse) return query
+    const page = Math.max(1, parseInt(query.get('page') || '1'))
+    const pageSize = Math.min(100, Math.max(1, parseInt(query.get('pageSize') || '20')))
+    const search = query.get('search') || ''
+    const statusFilter = query.get('status') || ''
+    const departmentId = query.get('departmentId') || ''
+    const list = parseListParams<Prisma.UserOrderByWithRelationInput>(query, userListSpec)
+    if (list instanceof NextResponse) return list
 
     const where: Record<string, unknown> = {}
 
@@ -46,15 +86,15 @@ export const GET = withAuth(
 
     if (search) {
       where.OR = [
-        { name: { contains: search, mode: 'insensitive' } },
-        { email: { contains: search, mode: 'insensitive' } },
+        { name: containsInsensitive(search) },
+        { email: containsInsensitive(search) },
       ]
     }
 
     const [users, total] = await Promise.all([
       prisma.user.findMany({
         where,
-        orderBy: { createdAt: 'desc' },
+        orderBy: list.orderBy,
         skip: (page - 1) * pageSize,
         take: pageSize,
         select: userSelectFields,
@@ -62,11 +102,18 @@ export const GET = withAuth(
       prisma.user.count({ where }),
     ])
 
-    const mapped = users.map((u) => ({
-      ...u,
-      departmentName: u.department?.name ?? null,
-      department: undefined,
-    }))
+    const mapped = users.map((u) =>
+      pickFields(
+        {
+          ...u,
+          departmentName: u.department?.name ?? null,
+          department: undefined,
+          customRoleName: u.customRole?.name ?? null,
+          customRole: undefined,
+        },
+        list.fields,
+      ),
+    )
 
     return NextResponse.json({ users: mapped, total, page, pageSize })
   }),
@@ -90,6 +137,9 @@ export const POST = withAuth(
         return NextResponse.json({ error: 'Email already registered' }, { status: 409 })
       }
 
+      const blocked = await enforceLicenseLimit('seat')
+      if (blocked) return blocked
+
       // Validate departmentId if provided
       if (body.departmentId) {
         const dept = await prisma.department.findUnique({
@@ -102,12 +152,17 @@ export const POST = withAuth(
 
       const passwordHash = await bcryptjs.hash(body.password, 12)
 
+      // Creating an account with an elevated role is an elevation too: the
+      // account starts as USER and gets the role once a second admin approves
+      const elevationPending =
+        isRoleElevation('USER', body.role) && (await requiresApproval('USER_ROLE_ELEVATE'))
+
       const created = await prisma.user.create({
         data: {
           email: body.email,
           name: body.name,
           passwordHash,
-          role: body.role,
+          role: elevationPending ? 'USER' : body.role,
           departmentId: body.departmentId || null,
         },
         select: userSelectFields,
@@ -124,12 +179,30 @@ export const POST = withAuth(
         action: 'USER_CREATE',
         resource: 'user',
         resourceId: created.id,
-        details: { email: body.email, name: body.name, role: body.role },
+        details: { email: body.email, name: body.name, role: created.role },
         ipAddress: req.headers.get('x-forwarded-for') || 'unknown',
         userAgent: req.headers.get('user-agent') || undefined,
         result: 'SUCCESS',
       })
 
+      if (elevationPending) {
+        const approval = await createApprovalRequest({
+          action: 'USER_ROLE_ELEVATE',
+          resource: 'user',
+          resourceId: created.id,
+          payload: { userId: created.id, role: body.role },
+          summary: { name: created.name, fromRole: 'USER', toRole: body.role },
+          reason: new URL(req.url).searchParams.get('reason')?.slice(0, 1000) || undefined,
+          requestedById: user.id,
+          ipAddress: req.headers.get('x-forwarded-for') || 'unknown',
+          userAgent: req.headers.get('user-agent') || undefined,
+        })
+        return NextResponse.json(
+          { status: 'pending_approval', user: mapped, approval: toApprovalResponse(approval) },
+          { status: 202 },
+        )
+      }
+
       return NextResponse.json({ user: mapped }, { status: 201 })
     }),
   ),
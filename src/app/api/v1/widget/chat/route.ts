@@ -0,0 +1,55 @@
+import { NextRequest, NextResponse } from 'next/server'
+import { checkRateLimit } from '@/lib/redis'
+import { widgetMessageSchema } from '@/lib/validations/widget'
+import { authenticateWidget, sendWidgetMessage, widgetPreflight } from '@/lib/widgets'
+
+// Widget-wide ceiling on top of the per-visitor limit
+const WIDGET_TOTAL_PER_MINUTE = 300
+
+export async function OPTIONS(req: NextRequest) {
+  return widgetPreflight(req)
+}
+
+// POST /api/v1/widget/chat — Send a message from an embedded widget (widget token auth)
+export async function POST(req: NextRequest) {
+  const auth = await authenticateWidget(req)
+  if ('error' in auth) return auth.error
+  const { widget, headers } = auth
+
+  let body: unknown
+  try {
+    body = await req.json()
+  } catch {
+    return NextResponse.json({ error: 'Invalid request body' }, { status: 400, headers })
+  }
+  const parsed = widgetMessageSchema.safeParse(body)
+  if (!parsed.success) {
+    return NextResponse.json(
+      { error: 'Validation failed', details: parsed.error.issues },
+      { status: 400, headers },
+    )
+  }
+  const { visitorId, message } = parsed.data
+
+  const [visitorLimit, widgetLimit] = await Promise.all([
+    checkRateLimit(`widget:${widget.id}:${visitorId}`, widget.rateLimitPerMinute, 60),
+    checkRateLimit(`widget:${widget.id}`, WIDGET_TOTAL_PER_MINUTE, 60),
+  ])
+  if (!visitorLimit.allowed || !widgetLimit.allowed) {
+    const resetAt = Math.max(visitorLimit.resetAt, widgetLimit.resetAt)
+    return NextResponse.json(
+      { error: 'Rate limit exceeded' },
+      {
+        status: 429,
+        headers: { ...headers, 'Retry-After': String(Math.max(1, resetAt - Math.floor(Date.now() / 1000))) },
+      },
+    )
+  }
+
+  try {
+    const result = await sendWidgetMessage(widget, visitorId, message)
+    return NextResponse.json(result, { headers })
+  } catch (err) {
+    return NextResponse.json({ error: (err as Error).message }, { status: 502, headers })
+  }
+}
@@ -0,0 +1,26 @@
+import { NextRequest, NextResponse } from 'next/server'
+import { authenticateWidget, getWidgetHistory, isValidVisitorId, widgetPreflight } from '@/lib/widgets'
+import { parseRenderMode, withRenderedHtml } from '@/lib/markdown'
+
+export async function OPTIONS(req: NextRequest) {
+  return widgetPreflight(req)
+}
+
+// GET /api/v1/widget/history?visitorId=&render=html — Conversation so far for one visitor
+export async function GET(req: NextRequest) {
+  const auth = await authenticateWidget(req)
+  if ('error' in auth) return auth.error
+  const { widget, headers } = auth
+
+  const visitorId = new URL(req.url).searchParams.get('visitorId')
+  if (!isValidVisitorId(visitorId)) {
+    return NextResponse.json({ error: 'Invalid visitorId' }, { status: 400, headers })
+  }
+
+  try {
+    const messages = withRenderedHtml(await getWidgetHistory(widget, visitorId), parseRenderMode(req.url))
+    return NextResponse.json({ messages }, { headers })
+  } catch (err) {
+    return NextResponse.json({ error: (err as Error).message }, { status: 502, headers })
+  }
+}
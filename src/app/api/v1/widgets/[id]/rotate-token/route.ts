@@ -0,0 +1,36 @@
+import { NextResponse } from 'next/server'
+import { prisma } from '@/lib/db'
+import { withAuth, withPermission, param } from '@/lib/middleware/auth'
+import { auditLog } from '@/lib/audit'
+import { generateWidgetToken } from '@/lib/widgets'
+
+// POST /api/v1/widgets/[id]/rotate-token — Issue a new token (old one stops working)
+export const POST = withAuth(
+  withPermission('widgets:manage', async (req, ctx) => {
+    const id = param(ctx, 'id')
+
+    const existing = await prisma.chatWidget.findUnique({ where: { id } })
+    if (!existing) {
+      return NextResponse.json({ error: 'Widget not found' }, { status: 404 })
+    }
+
+    const { token, tokenHash, tokenPrefix } = generateWidgetToken()
+    await prisma.chatWidget.update({
+      where: { id },
+      data: { tokenHash, tokenPrefix },
+    })
+
+    auditLog({
+      userId: ctx.user.id,
+      action: 'WIDGET_ROTATE_TOKEN',
+      resource: 'widget',
+      resourceId: id,
+      details: { name: existing.name },
+      ipAddress: req.headers.get('x-forwarded-for') || 'unknown',
+      userAgent: req.headers.get('user-agent') || undefined,
+      result: 'SUCCESS',
+    })
+
+    return NextResponse.json({ token })
+  }),
+)
@@ -0,0 +1,82 @@
+import { NextResponse } from 'next/server'
+import { prisma } from '@/lib/db'
+import { withAuth, withPermission, withValidation, param } from '@/lib/middleware/auth'
+import type { AuthContext } from '@/lib/middleware/auth'
+import { updateWidgetSchema } from '@/lib/validations/widget'
+import { auditLog, diffForAudit } from '@/lib/audit'
+import { toWidgetResponse } from '@/lib/widgets'
+
+// PUT /api/v1/widgets/[id] — Update name, origins, rate limit, or enabled flag
+export const PUT = withAuth(
+  withPermission(
+    'widgets:manage',
+    withValidation(updateWidgetSchema, async (req, ctx) => {
+      const { user, body } = ctx as {
+        user: NonNullable<typeof ctx.user>
+        body: typeof ctx.body
+      }
+      const id = param(ctx as unknown as AuthContext, 'id')
+
+      const existing = await prisma.chatWidget.findUnique({ where: { id } })
+      if (!existing) {
+        return NextResponse.json({ error: 'Widget not found' }, { status: 404 })
+      }
+
+      const widget = await prisma.chatWidget.update({
+        where: { id },
+        data: {
+          ...body,
+          allowedOrigins: body.allowedOrigins ? [...new Set(body.allowedOrigins)] : undefined,
+        },
+        include: { instance: { select: { name: true } } },
+      })
+
+      auditLog({
+        userId: user.id,
+        action: 'WIDGET_UPDATE',
+        resource: 'widget',
+        resourceId: id,
+        details: { name: widget.name },
+        changes: diffForAudit(existing, body),
+        ipAddress: req.headers.get('x-forwarded-for') || 'unknown',
+        userAgent: req.headers.get('user-agent') || undefined,
+        result: 'SUCCESS',
+      })
+
+      return NextResponse.json({ widget: toWidgetResponse(widget) })
+    }),
+  ),
+)
+
+// DELETE /api/v1/widgets/[id] — Remove widget and disable its service account
+export const DELETE = withAuth(
+  withPermission('widgets:manage', async (req, ctx) => {
+    const id = param(ctx, 'id')
+
+    const existing = await prisma.chatWidget.findUnique({ where: { id } })
+    if (!existing) {
+      return NextResponse.json({ error: 'Widget not found' }, { status: 404 })
+    }
+
+    await prisma.$transaction([
+      prisma.chatWidget.delete({ where: { id } }),
+      prisma.user.update({
+        where: { id: existing.serviceAccountId },
+        data: { status: 'DISABLED' },
+      }),
+    ])
+
+    auditLog({
+      userId: ctx.user.id,
+      action: 'WIDGET_DELETE',
+      resource: 'widget',
+      resourceId: id,
+      details: { name: existing.name },
+      ipAddress: req.headers.get('x-forwarded-for') || 'unknown',
+      userAgent: req.headers.get('user-agent') || undefined,
+      result: 'SUCCESS',
+    })
+
+    return NextResponse.json({ success: true })
+  }),
+)
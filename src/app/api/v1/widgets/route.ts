@@ -0,0 +1,80 @@
+import { NextResponse } from 'next/server'
+import { prisma } from '@/lib/db'
+import { stringList } from '@/lib/db-dialect'
+import { withAuth, withPermission, withValidation } from '@/lib/middleware/auth'
+import { createWidgetSchema } from '@/lib/validations/widget'
+import { auditLog } from '@/lib/audit'
+import { createServiceAccount } from '@/lib/users/service-accounts'
+import { generateWidgetToken, toWidgetResponse } from '@/lib/widgets'
+
+// GET /api/v1/widgets — List embeddable chat widgets
+export const GET = withAuth(
+  withPermission('widgets:manage', async () => {
+    const widgets = await prisma.chatWidget.findMany({
+      include: { instance: { select: { name: true } } },
+      orderBy: { createdAt: 'desc' },
+    })
+    return NextResponse.json({ widgets: widgets.map(toWidgetResponse) })
+  }),
+)
+
+// POST /api/v1/widgets — Create a widget bound to one agent (token is only returned here)
+export const POST = withAuth(
+  withPermission(
+    'widgets:manage',
+    withValidation(createWidgetSchema, async (req, ctx) => {
+      const { user, body } = ctx as {
+        user: NonNullable<typeof ctx.user>
+        body: typeof ctx.body
+      }
+
+      const instance = await prisma.instance.findUnique({
+        where: { id: body.instanceId },
+        select: { id: true },
+      })
+      if (!instance) {
+        return NextResponse.json({ error: 'Instance not found' }, { status: 404 })
+      }
+
+      const { token, tokenHash, tokenPrefix } = generateWidgetToken()
+      const serviceAccountId = await createServiceAccount(
+        `Widget: ${body.name}`,
+        'widget',
+      )
+
+      const widget = await prisma.chatWidget.create({
+        data: {
+          name: body.name,
+          instanceId: body.instanceId,
+          agentId: body.agentId,
+          allowedOrigins: [...new Set(body.allowedOrigins)],
+          rateLimitPerMinute: body.rateLimitPerMinute,
+          enabled: body.enabled ?? true,
+          tokenHash,
+          tokenPrefix,
+          serviceAccountId,
+          createdById: user.id,
+        },
+        include: { instance: { select: { name: true } } },
+      })
+
+      auditLog({
+        userId: user.id,
+        action: 'WIDGET_CREATE',
+        resource: 'widget',
+        resourceId: widget.id,
+        details: {
+          name: widget.name,
+          instanceId: widget.instanceId,
+          agentId: widget.agentId,
+          allowedOrigins: stringList(widget.allowedOrigins).join(','),
+        },
+        ipAddress: req.headers.get('x-forwarded-for') || 'unknown',
+        userAgent: req.headers.get('user-agent') || undefined,
+        result: 'SUCCESS',
+      })
+
+      return NextResponse.json({ widget: toWidgetResponse(widget), token }, { status: 201 })
+    }),
+  ),
+)
@@ -0,0 +1,135 @@
+"use client"
+
+import { use, useCallback, useEffect, useState } from "react"
+import { Bot, Loader2, Lock } from "lucide-react"
+import { Button } from "@/components/ui/button"
+import { Input } from "@/components/ui/input"
+import { ThemeToggle } from "@/components/theme-toggle"
+import { ChatMessageBubble } from "@/components/chat/chat-message-bubble"
+import { ChatAssistantMessage } from "@/components/chat/chat-assistant-message"
+import { LOGO_SRC } from "@/lib/logo"
+import { useT } from "@/stores/language-store"
+import type { SharedSessionResponse } from "@/types/chat"
+
+type ViewState =
+  | { kind: "loading" }
+  | { kind: "password"; error: boolean }
+  | { kind: "invalid" }
+  | { kind: "ready"; data: SharedSessionResponse }
+
+export default function SharedSessionPage({
+  params,
+}: {
+  params: Promise<{ token: string }>
+}) {
+  const { token } = use(params)
+  const t = useT()
+  const [state, setState] = useState<ViewState>({ kind: "loading" })
+  const [password, setPassword] = useState("")
+  const [submitting, setSubmitting] = useState(false)
+
+  const load = useCallback(
+    async (pw?: string) => {
+      const res = await fetch(`/api/v1/shared/${encodeURIComponent(token)}`, {
+        method: "POST",
+        headers: { "Content-Type": "application/json" },
+        body: JSON.stringify(pw ? { password: pw } : {}),
+      })
+      if (res.ok) {
+        setState({ kind: "ready", data: (await res.json()) as SharedSessionResponse })
+        return
+      }
+      const body = (await res.json().catch(() => ({}))) as { passwordRequired?: boolean }
+      if (body.passwordRequired) {
+        setState({ kind: "password", error: !!pw })
+      } else {
+        setState({ kind: "invalid" })
+      }
+    },
+    [token],
+  )
+
+  useEffect(() => {
+    load().catch(() => setState({ kind: "invalid" }))
+  }, [load])
+
+  async function handleSubmit(e: React.FormEvent) {
+    e.preventDefault()
+    setSubmitting(true)
+    await load(password).catch(() => setState({ kind: "invalid" }))
+    setSubmitting(false)
+  }
+
+  return (
+    <div className="bg-background min-h-svh">
+      <header className="flex h-14 items-center gap-3 border-b px-4">
+        <div className="size-7 overflow-hidden rounded-md">
+          <img src={LOGO_SRC} alt="TeamClaw" className="size-full object-cover" />
+        </div>
+        <span className="text-sm font-semibold">TeamClaw</span>
+        {state.kind === "ready" && (
+          <span className="text-muted-foreground text-xs">{t("share.readOnly")}</span>
+        )}
+        <div className="ml-auto">
+          <ThemeToggle />
+        </div>
+      </header>
+
+      {state.kind === "loading" && (
+        <div className="flex justify-center py-24">
+          <Loader2 className="text-muted-foreground size-5 animate-spin" />
+        </div>
+      )}
+
+      {state.kind === "invalid" && (
+        <p className="text-muted-foreground py-24 text-center text-sm">{t("share.invalid")}</p>
+      )}
+
+      {state.kind === "password" && (
+        <form onSubmit={handleSubmit} className="mx-auto flex max-w-sm flex-col gap-3 py-24">
+          <div className="flex items-center gap-2 text-sm font-medium">
+            <Lock className="size-4" />
+            {t("share.passwordRequired")}
+          </div>
+          <Input
+            type="password"
+            autoFocus
+            value={password}
+            placeholder={t("share.passwordPlaceholder")}
+            onChange={(e) => setPassword(e.target.value)}
+            aria-invalid={state.error}
+          />
+          <Button type="submit" disabled={!password || submitting}>
+            {submitting && <Loader2 className="mr-2 size-4 animate-spin" />}
+            {t("share.view")}
+          </Button>
+        </form>
+      )}
+
+      {state.kind === "ready" && (
+        <main className="mx-auto flex max-w-3xl flex-col gap-4 px-4 py-6">
+          <div className="border-b pb-4">
+            <h1 className="flex items-center gap-2 text-lg font-semibold">
+              <Bot className="text-muted-foreground size-5" />
+              {state.data.title || state.data.agentId}
+            </h1>
+            <p className="text-muted-foreground mt-1 text-xs">
+              {state.data.instanceName} / {state.data.agentId} ·{" "}
+              {t("share.sharedBy", { name: state.data.sharedBy })} ·{" "}
+              {t("share.expiresAt", { time: new Date(state.data.expiresAt).toLocaleString() })}
+            </p>
+          </div>
+          {state.data.snapshots.flatMap((batch) =>
+            batch.messages.map((message) =>
+              message.role === "user" ? (
+                <ChatMessageBubble key={message.id} message={message} />
+              ) : (
+                <ChatAssistantMessage key={message.id} message={message} isStreaming={false} />
+              ),
+            ),
+          )}
+        </main>
+      )}
+    </div>
+  )
+}
@@ -22,7 +22,7 @@ import { AgentFilesTab } from "./agent-files-tab"
 import { CATEGORY_CONFIG } from "./agent-card"
 import { useAgent, useClassifyAgent } from "@/hooks/use-agents"
 import { useAuthStore } from "@/stores/auth-store"
-import { hasPermission } from "@/lib/auth/permissions"
+import { userHasPermission } from "@/lib/auth/permissions"
 import { Bot, Copy, Loader2, Star, Trash2, Tags } from "lucide-react"
 import { toast } from "sonner"
 import { useT } from "@/stores/language-store"
@@ -50,7 +50,7 @@ export function AgentDetailSheet({
   const { data: detail, isLoading } = useAgent(agent ? compositeId : null)
   const classify = useClassifyAgent(compositeId)
   const currentUser = useAuthStore((s) => s.user)
-  const canClassify = currentUser ? hasPermission(currentUser.role, "agents:classify") : false
+  const canClassify = currentUser ? userHasPermission(currentUser, "agents:classify") : false
 
   const [classifyOpen, setClassifyOpen] = useState(false)
   const [newCategory, setNewCategory] = useState<AgentCategory | "">("")
@@ -2,7 +2,7 @@
 
 import Link from "next/link"
 import { usePathname, useRouter } from "next/navigation"
-import { LOGO_SRC } from "@/lib/logo"
+import { useBranding } from "@/hooks/use-branding"
 import {
   LayoutDashboard,
   MessageSquare,
@@ -15,6 +15,7 @@ import {
   ScrollText,
   LogOut,
   UserCircle,
+  ShieldCheck,
   ChevronsUpDown,
 } from "lucide-react"
 
@@ -49,6 +50,7 @@ export function AppSidebar() {
   const user = useAuthStore((s) => s.user)
   const logout = useAuthStore((s) => s.logout)
   const t = useT()
+  const branding = useBranding()
 
   const navGroups = [
     {
@@ -109,11 +111,11 @@ export function AppSidebar() {
             <SidebarMenuButton size="lg" asChild>
               <Link href="/">
                 <div className="flex size-8 items-center justify-center">
-                  <img src={LOGO_SRC} alt="TeamClaw" className="size-6 rounded-md object-cover" />
+                  <img src={branding.logoSrc} alt={branding.productName} className="size-6 rounded-md object-cover" />
                 </div>
                 <div className="grid flex-1 text-left leading-tight">
                   <span className="truncate text-sm font-semibold">
-                    TeamClaw
+                    {branding.productName}
                   </span>
                   <span className="text-muted-foreground truncate text-xs">
                     {t('nav.subtitle')}
@@ -204,6 +206,10 @@ export function AppSidebar() {
                   <UserCircle className="mr-2 size-4" />
                   {t('nav.profile')}
                 </DropdownMenuItem>
+                <DropdownMenuItem onClick={() => router.push("/mfa-setup")}>
+                  <ShieldCheck className="mr-2 size-4" />
+                  {t('mfa.title')}
+                </DropdownMenuItem>
                 <DropdownMenuSeparator />
                 <DropdownMenuItem
                   variant="destructive"
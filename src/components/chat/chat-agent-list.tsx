@@ -1,7 +1,7 @@
 "use client"
 
-import { useState } from "react"
-import { Bot, Loader2, Plus, Globe, Building2, UserCircle } from "lucide-react"
+import { useEffect, useState } from "react"
+import { Bot, Loader2, Plus, Globe, Building2, UserCircle, Star } from "lucide-react"
 import { Button } from "@/components/ui/button"
 import {
   Dialog,
@@ -13,6 +13,7 @@ import {
 } from "@/components/ui/dialog"
 import { useQueryClient } from "@tanstack/react-query"
 import { useChatAgents, useChatSessions, useNewConversation, chatKeys } from "@/hooks/use-chat"
+import { usePreferences } from "@/hooks/use-preferences"
 import { useChatStore } from "@/stores/chat-store"
 import { toast } from "sonner"
 import { cn } from "@/lib/utils"
@@ -29,6 +30,7 @@ export function ChatAgentList() {
   const t = useT()
   const { data: agents, isLoading } = useChatAgents()
   const { data: sessions } = useChatSessions()
+  const { data: prefs, isPending: prefsPending } = usePreferences()
   const selectedAgent = useChatStore((s) => s.selectedAgent)
   const setSelectedAgent = useChatStore((s) => s.setSelectedAgent)
   const clearMessages = useChatStore((s) => s.clearMessages)
@@ -58,6 +60,17 @@ export function ChatAgentList() {
     setSelectedAgent(agent)
   }
 
+  // Open the user's preferred agent; new users (no conversations yet) land
+  // on their department's default agent
+  useEffect(() => {
+    if (selectedAgent || !agents || !sessions || prefsPending) return
+    const preferred = prefs?.defaultAgent
+      ? agents.find((a) => a.instanceId === prefs.defaultAgent!.instanceId && a.agentId === prefs.defaultAgent!.agentId)
+      : undefined
+    const defaultAgent = preferred ?? (sessions.length === 0 ? agents.find((a) => a.isDefault) : undefined)
+    if (defaultAgent) setSelectedAgent(defaultAgent)
+  }, [agents, sessions, prefs, prefsPending, selectedAgent, setSelectedAgent])
+
   function handleNewConversation() {
     if (!confirmAgent) return
     newConversation.mutate(
@@ -127,8 +140,18 @@ export function ChatAgentList() {
                   onClick={() => handleSelect(agent)}
                   onKeyDown={(e) => { if (e.key === "Enter" || e.key === " ") handleSelect(agent) }}
                 >
-                  <Bot className="size-4 shrink-0" />
+                  {agent.iconUrl ? (
+                    // eslint-disable-next-line @next/next/no-img-element
+                    <img src={agent.iconUrl} alt="" className="size-4 shrink-0 rounded object-cover" />
+                  ) : (
+                    <Bot className="size-4 shrink-0" />
+                  )}
                   <span className="min-w-0 flex-1 truncate">{agent.agentName}</span>
+                  {agent.isDefault && (
+                    <span className="shrink-0" title={t('chat.defaultAgent')}>
+                      <Star className="size-3 fill-amber-400 text-amber-400" />
+                    </span>
+                  )}
                   {agent.category && agent.category !== "DEFAULT" && (() => {
                     const Icon = CATEGORY_ICONS[agent.category]
                     return <span className="shrink-0" aria-label={agent.category === "DEPARTMENT" ? t('chat.department') : t('chat.personal')}><Icon className="size-3 text-muted-foreground/60" /></span>
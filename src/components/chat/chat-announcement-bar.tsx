@@ -0,0 +1,71 @@
+"use client"
+
+import { useState } from "react"
+import { Megaphone, X } from "lucide-react"
+import { cn } from "@/lib/utils"
+import { useChatStore } from "@/stores/chat-store"
+import { useChatAnnouncements } from "@/hooks/use-chat"
+import { useT } from "@/stores/language-store"
+import type { ChatAnnouncement } from "@/types/chat"
+
+export function ChatAnnouncementBar({ instanceId }: { instanceId: string }) {
+  const t = useT()
+  const { data: polled } = useChatAnnouncements(instanceId)
+  const streamed = useChatStore((s) => s.streamAnnouncements)
+  const [dismissed, setDismissed] = useState<string[]>([])
+
+  const now = Date.now()
+  const byId = new Map<string, ChatAnnouncement>()
+  for (const a of [...(polled ?? []), ...streamed]) {
+    if (a.instanceId === instanceId) byId.set(a.id, a)
+  }
+  const announcements = [...byId.values()].filter(
+    (a) => !dismissed.includes(a.id) && new Date(a.expiresAt).getTime() > now,
+  )
+
+  if (announcements.length === 0) return null
+
+  return (
+    <div className="space-y-1 px-4 pt-2">
+      {announcements.map((a) => {
+        const warning = a.level === "WARNING"
+        return (
+          <div
+            key={a.id}
+            className={cn(
+              "flex items-start gap-2 rounded-md border px-3 py-2",
+              warning
+                ? "border-amber-200 bg-amber-50 dark:border-amber-900 dark:bg-amber-950/30"
+                : "border-blue-200 bg-blue-50 dark:border-blue-900 dark:bg-blue-950/30",
+            )}
+          >
+            <Megaphone
+              className={cn(
+                "mt-0.5 size-3.5 shrink-0",
+                warning ? "text-amber-600 dark:text-amber-400" : "text-blue-600 dark:text-blue-400",
+              )}
+            />
+            <div className="flex-1 text-xs">
+              <p className={cn("whitespace-pre-wrap", warning ? "text-amber-800 dark:text-amber-200" : "text-blue-800 dark:text-blue-200")}>
+                {a.message}
+              </p>
+              <p className="text-muted-foreground mt-0.5 text-[11px]">
+                {a.createdByName
+                  ? t("chat.announcementFrom", { name: a.createdByName })
+                  : t("chat.announcement")}
+              </p>
+            </div>
+            <button
+              type="button"
+              className="text-muted-foreground hover:text-foreground"
+              onClick={() => setDismissed((ids) => [...ids, a.id])}
+              aria-label={t("chat.announcementDismiss")}
+            >
+              <X className="size-3.5" />
+            </button>
+          </div>
+        )
+      })}
+    </div>
+  )
+}
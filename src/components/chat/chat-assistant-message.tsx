@@ -1,12 +1,17 @@
 "use client"
 
-import { Bot } from "lucide-react"
+import { useState } from "react"
+import { Bot, Flag } from "lucide-react"
 import type { ChatMessage } from "@/types/chat"
+import { useChatStore } from "@/stores/chat-store"
+import { useT } from "@/stores/language-store"
+import { usePreferences } from "@/hooks/use-preferences"
 import { ChatThinkingBlock } from "./chat-thinking-block"
 import { ChatToolCallBlock } from "./chat-tool-call-block"
 import { ChatTextBlock } from "./chat-text-block"
 import { ChatErrorBlock } from "./chat-error-block"
 import { ChatImageBlock } from "./chat-image-block"
+import { ChatReportDialog } from "./chat-report-dialog"
 
 interface ChatAssistantMessageProps {
   message: ChatMessage
@@ -17,6 +22,11 @@ export function ChatAssistantMessage({
   message,
   isStreaming,
 }: ChatAssistantMessageProps) {
+  const t = useT()
+  const streamStatus = useChatStore((s) => s.streamStatus)
+  const sessionId = useChatStore((s) => s.activeSessionId)
+  const [reportOpen, setReportOpen] = useState(false)
+  const display = usePreferences().data?.chatDisplay
   const hasContent = message.content || message.thinking || message.toolCalls?.length || message.contentBlocks?.length
 
   return (
@@ -26,10 +36,10 @@ export function ChatAssistantMessage({
           <Bot className="size-3.5" />
         </div>
         <div className="flex min-w-0 flex-col gap-2">
-          {message.thinking && (
+          {message.thinking && display?.showThinking !== false && (
             <ChatThinkingBlock content={message.thinking} />
           )}
-          {message.toolCalls?.map((tc, i) => (
+          {display?.showToolCalls !== false && message.toolCalls?.map((tc, i) => (
             <ChatToolCallBlock key={i} toolCall={tc} />
           ))}
           {message.content && (
@@ -47,10 +57,35 @@ export function ChatAssistantMessage({
               <span className="bg-foreground/60 size-1.5 animate-bounce rounded-full [animation-delay:300ms]" />
             </div>
           )}
+          {isStreaming && streamStatus && (
+            <p className="text-muted-foreground text-xs">
+              {streamStatus.type === "queued"
+                ? t('chat.queued', { position: streamStatus.position })
+                : t('chat.reconnecting', { attempt: streamStatus.attempt, max: streamStatus.maxAttempts })}
+            </p>
+          )}
           {isStreaming && message.content && (
             <span className="bg-foreground inline-block size-2 animate-pulse rounded-sm" />
           )}
           {message.error && <ChatErrorBlock error={message.error} />}
+          {!isStreaming && sessionId && (message.content || message.error) && (
+            <>
+              <button
+                type="button"
+                className="text-muted-foreground hover:text-foreground flex w-fit items-center gap-1 text-[11px]"
+                onClick={() => setReportOpen(true)}
+              >
+                <Flag className="size-3" />
+                {t("chat.reportProblem")}
+              </button>
+              <ChatReportDialog
+                sessionId={sessionId}
+                message={message}
+                open={reportOpen}
+                onOpenChange={setReportOpen}
+              />
+            </>
+          )}
         </div>
       </div>
     </div>
@@ -1,7 +1,7 @@
 "use client"
 
 import { useState } from "react"
-import { PanelLeftClose, PanelLeft, RotateCcw, Bot, Loader2 } from "lucide-react"
+import { PanelLeftClose, PanelLeft, RotateCcw, Bot, Loader2, Share2, Download } from "lucide-react"
 import { Button } from "@/components/ui/button"
 import { Badge } from "@/components/ui/badge"
 import {
@@ -12,9 +12,16 @@ import {
   DialogHeader,
   DialogTitle,
 } from "@/components/ui/dialog"
+import {
+  DropdownMenu,
+  DropdownMenuContent,
+  DropdownMenuItem,
+  DropdownMenuTrigger,
+} from "@/components/ui/dropdown-menu"
 import { useChatStore } from "@/stores/chat-store"
 import { useClearContext } from "@/hooks/use-chat"
 import { useT } from "@/stores/language-store"
+import { ChatShareDialog } from "./chat-share-dialog"
 import { toast } from "sonner"
 
 export function ChatHeader() {
@@ -24,8 +31,19 @@ export function ChatHeader() {
   const sidebarOpen = useChatStore((s) => s.sidebarOpen)
   const setSidebarOpen = useChatStore((s) => s.setSidebarOpen)
   const [confirmOpen, setConfirmOpen] = useState(false)
+  const [shareOpen, setShareOpen] = useState(false)
   const clearContext = useClearContext()
 
+  function handleExport(format: "md" | "json") {
+    if (!activeSessionId) return
+    const a = document.createElement("a")
+    a.href = `/api/v1/chat/sessions/${activeSessionId}/export?format=${format}`
+    a.download = `chat-${activeSessionId}.${format}`
+    document.body.appendChild(a)
+    a.click()
+    document.body.removeChild(a)
+  }
+
   function handleClearContext() {
     if (!activeSessionId) return
     clearContext.mutate(activeSessionId, {
@@ -64,7 +82,32 @@ export function ChatHeader() {
             <Badge variant="outline" className="text-xs">
               {selectedAgent.instanceName}
             </Badge>
-            <div className="ml-auto">
+            <div className="ml-auto flex items-center gap-1">
+              <Button
+                variant="ghost"
+                size="sm"
+                disabled={!activeSessionId}
+                onClick={() => setShareOpen(true)}
+              >
+                <Share2 className="mr-1 size-3.5" />
+                {t('chat.share')}
+              </Button>
+              <DropdownMenu>
+                <DropdownMenuTrigger asChild>
+                  <Button variant="ghost" size="sm" disabled={!activeSessionId}>
+                    <Download className="mr-1 size-3.5" />
+                    {t('chat.export')}
+                  </Button>
+                </DropdownMenuTrigger>
+                <DropdownMenuContent align="end">
+                  <DropdownMenuItem onClick={() => handleExport("md")}>
+                    {t('chat.exportMarkdown')}
+                  </DropdownMenuItem>
+                  <DropdownMenuItem onClick={() => handleExport("json")}>
+                    {t('chat.exportJson')}
+                  </DropdownMenuItem>
+                </DropdownMenuContent>
+              </DropdownMenu>
               <Button
                 variant="ghost"
                 size="sm"
@@ -109,6 +152,14 @@ export function ChatHeader() {
           </DialogFooter>
         </DialogContent>
       </Dialog>
+
+      {activeSessionId && (
+        <ChatShareDialog
+          sessionId={activeSessionId}
+          open={shareOpen}
+          onOpenChange={setShareOpen}
+        />
+      )}
     </>
   )
 }
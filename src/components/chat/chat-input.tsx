@@ -1,10 +1,11 @@
 "use client"
 
 import { useState, useRef, useCallback } from "react"
-import { Send, Square, Paperclip, X, FileText } from "lucide-react"
+import { Send, Square, Paperclip, X, FileText, Loader2 } from "lucide-react"
 import { Button } from "@/components/ui/button"
 import { Textarea } from "@/components/ui/textarea"
 import { useChatStore } from "@/stores/chat-store"
+import { uploadChatFile } from "@/lib/chat-stream"
 import { useT } from "@/stores/language-store"
 
 const IMAGE_MAX_SIZE = 10 * 1024 * 1024  // 10MB
@@ -13,11 +14,12 @@ const IMAGE_TYPES = new Set(["image/jpeg", "image/png", "image/gif", "image/webp
 const FILE_ACCEPT = "image/*,.pdf,.txt,.md,.csv,.json,.html"
 
 interface PendingFile {
+  key: string
   name: string
   mimeType: string
   size: number
-  content: string   // pure base64 (for API)
-  dataUrl: string   // data URL (for preview)
+  fileId: string | null  // set once the upload finished
+  dataUrl: string        // object URL (for preview)
 }
 
 export function ChatInput() {
@@ -29,16 +31,19 @@ export function ChatInput() {
 
   const selectedAgent = useChatStore((s) => s.selectedAgent)
   const isStreaming = useChatStore((s) => s.isStreaming)
-  const abortController = useChatStore((s) => s.abortController)
+  const stopStreaming = useChatStore((s) => s.stopStreaming)
   const sendMessage = useChatStore((s) => s.sendMessage)
   const activeSessionId = useChatStore((s) => s.activeSessionId)
+  const uploading = pendingFiles.some(f => f.fileId === null)
 
   const handleSend = useCallback(() => {
     const text = input.trim()
-    if ((!text && pendingFiles.length === 0) || !selectedAgent || isStreaming) return
+    if ((!text && pendingFiles.length === 0) || !selectedAgent || isStreaming || uploading) return
 
     const message = text || t('chat.attachment')
-    const attachments = pendingFiles.length > 0 ? pendingFiles : undefined
+    const attachments = pendingFiles.length > 0
+      ? pendingFiles.map(f => ({ ...f, fileId: f.fileId! }))
+      : undefined
 
     setInput("")
     setPendingFiles([])
@@ -58,10 +63,10 @@ export function ChatInput() {
     if (fileInputRef.current) {
       fileInputRef.current.value = ""
     }
-  }, [input, pendingFiles, selectedAgent, isStreaming, sendMessage, activeSessionId])
+  }, [input, pendingFiles, uploading, selectedAgent, isStreaming, sendMessage, activeSessionId])
 
   function handleStop() {
-    abortController?.abort()
+    stopStreaming()
   }
 
   function handleKeyDown(e: React.KeyboardEvent<HTMLTextAreaElement>) {
@@ -96,28 +101,35 @@ export function ChatInput() {
         break
       }
 
-      const reader = new FileReader()
-      reader.onload = () => {
-        const dataUrl = reader.result as string
-        const base64 = dataUrl.split(",")[1]
-        setPendingFiles(prev => {
-          if (prev.length >= 5) return prev
-          return [...prev, {
-            name: file.name,
-            mimeType: file.type || "application/octet-stream",
-            size: file.size,
-            content: base64,
-            dataUrl,
-          }]
+      // Uploaded right away; the message only carries the file id
+      const key = crypto.randomUUID()
+      setPendingFiles(prev => {
+        if (prev.length >= 5) return prev
+        return [...prev, {
+          key,
+          name: file.name,
+          mimeType: file.type || "application/octet-stream",
+          size: file.size,
+          fileId: null,
+          dataUrl: URL.createObjectURL(file),
+        }]
+      })
+      uploadChatFile(file)
+        .then(({ id }) => {
+          setPendingFiles(prev => prev.map(f => (f.key === key ? { ...f, fileId: id } : f)))
+        })
+        .catch((err: Error) => {
+          alert(t('chat.uploadFailed', { name: file.name, error: err.message }))
+          setPendingFiles(prev => prev.filter(f => f.key !== key))
         })
-      }
-      reader.readAsDataURL(file)
     }
     // Reset so the same file can be selected again
     e.target.value = ""
   }
 
   function removeFile(index: number) {
+    const removed = pendingFiles[index]
+    if (removed) URL.revokeObjectURL(removed.dataUrl)
     setPendingFiles(prev => prev.filter((_, i) => i !== index))
   }
 
@@ -134,7 +146,12 @@ export function ChatInput() {
         {pendingFiles.length > 0 && (
           <div className="mb-2 flex gap-2 overflow-x-auto pb-1">
             {pendingFiles.map((file, i) => (
-              <div key={i} className="group relative shrink-0">
+              <div key={file.key} className="group relative shrink-0">
+                {file.fileId === null && (
+                  <div className="bg-background/60 absolute inset-0 z-10 flex items-center justify-center rounded-lg">
+                    <Loader2 className="size-4 animate-spin" />
+                  </div>
+                )}
                 {file.mimeType.startsWith("image/") ? (
                   <img
                     src={file.dataUrl}
@@ -208,7 +225,7 @@ export function ChatInput() {
               size="icon"
               className="shrink-0"
               onClick={handleSend}
-              disabled={!input.trim() && pendingFiles.length === 0 || !selectedAgent}
+              disabled={!input.trim() && pendingFiles.length === 0 || !selectedAgent || uploading}
             >
               <Send className="size-4" />
             </Button>
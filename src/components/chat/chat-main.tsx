@@ -7,6 +7,8 @@ import { chatKeys, useChatSessions, useChatHistory } from "@/hooks/use-chat"
 import { ChatHeader } from "./chat-header"
 import { ChatMessageList } from "./chat-message-list"
 import { ChatInput } from "./chat-input"
+import { ChatToolApprovalBar } from "./chat-tool-approval-bar"
+import { ChatAnnouncementBar } from "./chat-announcement-bar"
 import { ChatWelcome } from "./chat-welcome"
 import type { ChatMessage, ChatSnapshotBatch } from "@/types/chat"
 
@@ -36,8 +38,14 @@ export function ChatMain() {
     : null
 
   // Fetch history when we have a matching session
-  const { data: historyData, isLoading: isLoadingHistoryQuery, dataUpdatedAt } =
-    useChatHistory(matchingSession?.id ?? null)
+  const {
+    data: historyData,
+    isLoading: isLoadingHistoryQuery,
+    dataUpdatedAt,
+    hasNextPage: hasOlderHistory,
+    fetchNextPage: fetchOlderHistory,
+    isFetchingNextPage: isLoadingOlder,
+  } = useChatHistory(matchingSession?.id ?? null)
 
   // Track which session + data version we've already loaded to avoid
   // redundant re-applies while still picking up background refetch results.
@@ -105,7 +113,13 @@ export function ChatMain() {
   return (
     <div className="flex flex-1 flex-col overflow-hidden">
       <ChatHeader />
-      <ChatMessageList />
+      <ChatAnnouncementBar instanceId={selectedAgent.instanceId} />
+      <ChatMessageList
+        onLoadOlder={hasOlderHistory && !isStreaming ? () => fetchOlderHistory() : undefined}
+        isLoadingOlder={isLoadingOlder}
+        summary={hasOlderHistory ? undefined : historyData?.summary}
+      />
+      <ChatToolApprovalBar />
       <ChatInput />
     </div>
   )
@@ -2,11 +2,13 @@
 
 import { useRef, useEffect, useState, useCallback } from "react"
 import { Loader2 } from "lucide-react"
+import { Button } from "@/components/ui/button"
 import { ScrollArea } from "@/components/ui/scroll-area"
 import { useChatStore } from "@/stores/chat-store"
 import { useT } from "@/stores/language-store"
 import { ChatMessageBubble } from "./chat-message-bubble"
 import { ChatAssistantMessage } from "./chat-assistant-message"
+import type { ChatHistorySummary } from "@/types/chat"
 
 const SEPARATOR_PREFIX = "__separator__:"
 
@@ -44,7 +46,15 @@ function HistoryLoadingSkeleton() {
   )
 }
 
-export function ChatMessageList() {
+interface ChatMessageListProps {
+  /** Set while older history pages remain to be loaded */
+  onLoadOlder?: () => void
+  isLoadingOlder?: boolean
+  /** Summary of compacted history, shown above the oldest message */
+  summary?: ChatHistorySummary
+}
+
+export function ChatMessageList({ onLoadOlder, isLoadingOlder, summary }: ChatMessageListProps) {
   const t = useT()
   const messages = useChatStore((s) => s.messages)
   const isStreaming = useChatStore((s) => s.isStreaming)
@@ -86,6 +96,22 @@ export function ChatMessageList() {
             {t('chat.gatewayUnreachable')}
           </div>
         )}
+        {onLoadOlder && (
+          <div className="flex justify-center">
+            <Button variant="ghost" size="sm" disabled={isLoadingOlder} onClick={onLoadOlder}>
+              {isLoadingOlder && <Loader2 className="size-4 animate-spin" />}
+              {t('chat.loadOlder')}
+            </Button>
+          </div>
+        )}
+        {summary && (
+          <details className="bg-muted/50 rounded-md border px-4 py-2 text-sm">
+            <summary className="text-muted-foreground cursor-pointer text-xs font-medium">
+              {t('chat.historySummary', { n: summary.messagesSummarized })}
+            </summary>
+            <p className="mt-2 whitespace-pre-wrap">{summary.text}</p>
+          </details>
+        )}
         {messages.map((msg) => {
           // Check if this is a separator message
           const separatorType = isSeparator(msg.content)
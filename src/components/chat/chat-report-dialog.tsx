@@ -0,0 +1,119 @@
+"use client"
+
+import { useState } from "react"
+import { Loader2 } from "lucide-react"
+import { Button } from "@/components/ui/button"
+import { Label } from "@/components/ui/label"
+import { Textarea } from "@/components/ui/textarea"
+import {
+  Select,
+  SelectContent,
+  SelectItem,
+  SelectTrigger,
+  SelectValue,
+} from "@/components/ui/select"
+import {
+  Dialog,
+  DialogContent,
+  DialogDescription,
+  DialogFooter,
+  DialogHeader,
+  DialogTitle,
+} from "@/components/ui/dialog"
+import { useReportMessage } from "@/hooks/use-chat"
+import { useT } from "@/stores/language-store"
+import { toast } from "sonner"
+import type { ChatMessage } from "@/types/chat"
+import type { SupportTicketCategory } from "@/types/support"
+
+const CATEGORY_OPTIONS = [
+  { value: "INCORRECT", key: "chat.reportIncorrect" },
+  { value: "UNSAFE", key: "chat.reportUnsafe" },
+  { value: "BROKEN", key: "chat.reportBroken" },
+  { value: "OTHER", key: "chat.reportOther" },
+] as const
+
+interface ChatReportDialogProps {
+  sessionId: string
+  message: ChatMessage
+  open: boolean
+  onOpenChange: (open: boolean) => void
+}
+
+export function ChatReportDialog({ sessionId, message, open, onOpenChange }: ChatReportDialogProps) {
+  const t = useT()
+  const [category, setCategory] = useState<SupportTicketCategory>("INCORRECT")
+  const [description, setDescription] = useState("")
+  const report = useReportMessage(sessionId)
+
+  function handleSubmit() {
+    report.mutate(
+      {
+        messageId: message.id,
+        messageContent: message.content || message.error || "",
+        category,
+        description: description.trim() || undefined,
+      },
+      {
+        onSuccess: () => {
+          toast.success(t("chat.reportSent"))
+          setDescription("")
+          onOpenChange(false)
+        },
+        onError: () => {
+          toast.error(t("chat.reportFailed"))
+        },
+      },
+    )
+  }
+
+  return (
+    <Dialog open={open} onOpenChange={onOpenChange}>
+      <DialogContent className="sm:max-w-[480px]">
+        <DialogHeader>
+          <DialogTitle>{t("chat.reportTitle")}</DialogTitle>
+          <DialogDescription>{t("chat.reportDesc")}</DialogDescription>
+        </DialogHeader>
+
+        <div className="grid gap-3">
+          <div className="grid gap-1.5">
+            <Label className="text-xs">{t("chat.reportCategory")}</Label>
+            <Select value={category} onValueChange={(v) => setCategory(v as SupportTicketCategory)}>
+              <SelectTrigger className="text-[13px]">
+                <SelectValue />
+              </SelectTrigger>
+              <SelectContent>
+                {CATEGORY_OPTIONS.map((o) => (
+                  <SelectItem key={o.value} value={o.value}>
+                    {t(o.key)}
+                  </SelectItem>
+                ))}
+              </SelectContent>
+            </Select>
+          </div>
+          <div className="grid gap-1.5">
+            <Label className="text-xs">{t("chat.reportDescription")}</Label>
+            <Textarea
+              value={description}
+              onChange={(e) => setDescription(e.target.value)}
+              placeholder={t("chat.reportPlaceholder")}
+              maxLength={5000}
+              rows={4}
+              className="text-[13px]"
+            />
+          </div>
+        </div>
+
+        <DialogFooter>
+          <Button variant="outline" onClick={() => onOpenChange(false)}>
+            {t("cancel")}
+          </Button>
+          <Button onClick={handleSubmit} disabled={report.isPending}>
+            {report.isPending && <Loader2 className="mr-2 size-4 animate-spin" />}
+            {t("chat.reportSubmit")}
+          </Button>
+        </DialogFooter>
+      </DialogContent>
+    </Dialog>
+  )
+}
@@ -1,9 +1,18 @@
 "use client"
 
-import { MessageSquare, Trash2, Loader2 } from "lucide-react"
+import { useState } from "react"
+import { MessageSquare, Trash2, Loader2, Pencil } from "lucide-react"
 import { Button } from "@/components/ui/button"
+import { Input } from "@/components/ui/input"
+import {
+  Dialog,
+  DialogContent,
+  DialogFooter,
+  DialogHeader,
+  DialogTitle,
+} from "@/components/ui/dialog"
 import { useQueryClient } from "@tanstack/react-query"
-import { useChatSessions, useDeleteChatSession, chatKeys } from "@/hooks/use-chat"
+import { useChatSessions, useDeleteChatSession, useRenameChatSession, chatKeys } from "@/hooks/use-chat"
 import { useChatStore } from "@/stores/chat-store"
 import { useT } from "@/stores/language-store"
 import { toast } from "sonner"
@@ -14,6 +23,8 @@ export function ChatSessionList() {
   const t = useT()
   const { data: sessions, isLoading } = useChatSessions()
   const deleteMutation = useDeleteChatSession()
+  const renameMutation = useRenameChatSession()
+  const [renaming, setRenaming] = useState<{ id: string; title: string } | null>(null)
   const setSelectedAgent = useChatStore((s) => s.setSelectedAgent)
   const clearMessages = useChatStore((s) => s.clearMessages)
   const activeSessionId = useChatStore((s) => s.activeSessionId)
@@ -46,6 +57,17 @@ export function ChatSessionList() {
     })
   }
 
+  function handleRename() {
+    if (!renaming?.title.trim()) return
+    renameMutation.mutate(
+      { id: renaming.id, title: renaming.title.trim() },
+      {
+        onSuccess: () => setRenaming(null),
+        onError: () => toast.error(t('operationFailed')),
+      },
+    )
+  }
+
   if (isLoading) {
     return (
       <div className="flex items-center justify-center py-4">
@@ -63,50 +85,92 @@ export function ChatSessionList() {
   }
 
   return (
-    <div className="flex flex-col gap-0.5">
-      {sessions.map((session) => {
-        const isCurrentSession = activeSessionId === session.id
-        return (
-          <div
-            key={session.id}
-            className={cn(
-              "hover:bg-accent group flex cursor-pointer items-center gap-2 rounded-md px-2 py-1.5",
-              isCurrentSession && "bg-accent",
-            )}
-            onClick={() => handleSelect(session)}
-          >
-            <MessageSquare className="text-muted-foreground size-3.5 shrink-0" />
-            <div className="min-w-0 flex-1">
-              <div className="flex items-center gap-1.5">
-                <p className={cn(
-                  "truncate text-sm",
-                  !session.isActive && "text-muted-foreground",
-                )}>
-                  {session.title || session.agentName || session.agentId}
+    <>
+      <div className="flex flex-col gap-0.5">
+        {sessions.map((session) => {
+          const isCurrentSession = activeSessionId === session.id
+          return (
+            <div
+              key={session.id}
+              className={cn(
+                "hover:bg-accent group flex cursor-pointer items-center gap-2 rounded-md px-2 py-1.5",
+                isCurrentSession && "bg-accent",
+              )}
+              onClick={() => handleSelect(session)}
+            >
+              <MessageSquare className="text-muted-foreground size-3.5 shrink-0" />
+              <div className="min-w-0 flex-1">
+                <div className="flex items-center gap-1.5">
+                  <p className={cn(
+                    "truncate text-sm",
+                    !session.isActive && "text-muted-foreground",
+                  )}>
+                    {session.title || session.agentName || session.agentId}
+                  </p>
+                  {session.isActive && (
+                    <span className="size-1.5 shrink-0 rounded-full bg-green-500" />
+                  )}
+                </div>
+                <p className="text-muted-foreground truncate text-[10px]">
+                  {session.instanceName}
+                  {session.lastMessageAt && (
+                    <> &middot; {formatRelative(session.lastMessageAt, t)}</>
+                  )}
                 </p>
-                {session.isActive && (
-                  <span className="size-1.5 shrink-0 rounded-full bg-green-500" />
+                {session.closedReason === "department_change" && (
+                  <p className="truncate text-[10px] text-amber-600 dark:text-amber-400">
+                    {t('chat.closedDepartmentChange')}
+                  </p>
                 )}
               </div>
-              <p className="text-muted-foreground truncate text-[10px]">
-                {session.instanceName}
-                {session.lastMessageAt && (
-                  <> &middot; {formatRelative(session.lastMessageAt, t)}</>
-                )}
-              </p>
+              <Button
+                variant="ghost"
+                size="icon"
+                className="size-6 shrink-0 opacity-0 group-hover:opacity-100"
+                onClick={(e) => {
+                  e.stopPropagation()
+                  setRenaming({ id: session.id, title: session.title ?? "" })
+                }}
+              >
+                <Pencil className="size-3" />
+              </Button>
+              <Button
+                variant="ghost"
+                size="icon"
+                className="size-6 shrink-0 opacity-0 group-hover:opacity-100"
+                onClick={(e) => handleDelete(session.id, e)}
+              >
+                <Trash2 className="size-3" />
+              </Button>
             </div>
-            <Button
-              variant="ghost"
-              size="icon"
-              className="size-6 shrink-0 opacity-0 group-hover:opacity-100"
-              onClick={(e) => handleDelete(session.id, e)}
-            >
-              <Trash2 className="size-3" />
+          )
+        })}
+      </div>
+
+      <Dialog open={!!renaming} onOpenChange={(open) => !open && setRenaming(null)}>
+        <DialogContent className="sm:max-w-[400px]">
+          <DialogHeader>
+            <DialogTitle>{t('chat.renameSession')}</DialogTitle>
+          </DialogHeader>
+          <Input
+            value={renaming?.title ?? ""}
+            onChange={(e) => renaming && setRenaming({ ...renaming, title: e.target.value })}
+            onKeyDown={(e) => e.key === "Enter" && handleRename()}
+            maxLength={100}
+            autoFocus
+          />
+          <DialogFooter>
+            <Button variant="outline" onClick={() => setRenaming(null)}>
+              {t('cancel')}
+            </Button>
+            <Button onClick={handleRename} disabled={!renaming?.title.trim() || renameMutation.isPending}>
+              {renameMutation.isPending && <Loader2 className="mr-2 size-4 animate-spin" />}
+              {t('save')}
             </Button>
-          </div>
-        )
-      })}
-    </div>
+          </DialogFooter>
+        </DialogContent>
+      </Dialog>
+    </>
   )
 }
 
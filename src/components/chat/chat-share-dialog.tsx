@@ -0,0 +1,189 @@
+"use client"
+
+import { useState } from "react"
+import { Copy, Link2, Loader2 } from "lucide-react"
+import { Button } from "@/components/ui/button"
+import { Badge } from "@/components/ui/badge"
+import { Input } from "@/components/ui/input"
+import { Label } from "@/components/ui/label"
+import {
+  Select,
+  SelectContent,
+  SelectItem,
+  SelectTrigger,
+  SelectValue,
+} from "@/components/ui/select"
+import {
+  Dialog,
+  DialogContent,
+  DialogDescription,
+  DialogFooter,
+  DialogHeader,
+  DialogTitle,
+} from "@/components/ui/dialog"
+import {
+  useSessionShares,
+  useCreateSessionShare,
+  useRevokeSessionShare,
+} from "@/hooks/use-chat"
+import { useT } from "@/stores/language-store"
+import { toast } from "sonner"
+import type { SessionShareInfo } from "@/types/chat"
+
+const EXPIRY_OPTIONS = [
+  { hours: 24, key: "chat.shareExpiry1d" },
+  { hours: 24 * 7, key: "chat.shareExpiry7d" },
+  { hours: 24 * 30, key: "chat.shareExpiry30d" },
+] as const
+
+const STATE_KEYS = {
+  active: "chat.shareStateActive",
+  expired: "chat.shareStateExpired",
+  revoked: "chat.shareStateRevoked",
+} as const
+
+interface ChatShareDialogProps {
+  sessionId: string
+  open: boolean
+  onOpenChange: (open: boolean) => void
+}
+
+export function ChatShareDialog({ sessionId, open, onOpenChange }: ChatShareDialogProps) {
+  const t = useT()
+  const [expiresInHours, setExpiresInHours] = useState("168")
+  const [password, setPassword] = useState("")
+  const [createdUrl, setCreatedUrl] = useState<string | null>(null)
+  const { data } = useSessionShares(open ? sessionId : null)
+  const createShare = useCreateSessionShare(sessionId)
+  const revokeShare = useRevokeSessionShare(sessionId)
+
+  async function copy(url: string) {
+    await navigator.clipboard.writeText(url)
+    toast.success(t("chat.shareCopied"))
+  }
+
+  function handleCreate() {
+    createShare.mutate(
+      {
+        expiresInHours: Number(expiresInHours),
+        password: password || undefined,
+      },
+      {
+        onSuccess: ({ share }) => {
+          const url = `${window.location.origin}${share.url}`
+          setCreatedUrl(url)
+          setPassword("")
+          copy(url).catch(() => {})
+        },
+        onError: () => {
+          toast.error(t("chat.shareFailed"))
+        },
+      },
+    )
+  }
+
+  function handleRevoke(share: SessionShareInfo) {
+    revokeShare.mutate(share.id, {
+      onSuccess: () => toast.success(t("chat.shareRevoked")),
+    })
+  }
+
+  function handleOpenChange(next: boolean) {
+    if (!next) setCreatedUrl(null)
+    onOpenChange(next)
+  }
+
+  const shares = data?.shares ?? []
+
+  return (
+    <Dialog open={open} onOpenChange={handleOpenChange}>
+      <DialogContent className="sm:max-w-[480px]">
+        <DialogHeader>
+          <DialogTitle>{t("chat.shareTitle")}</DialogTitle>
+          <DialogDescription>{t("chat.shareDesc")}</DialogDescription>
+        </DialogHeader>
+
+        <div className="grid gap-3">
+          <div className="grid grid-cols-2 gap-3">
+            <div className="grid gap-1.5">
+              <Label className="text-xs">{t("chat.shareExpiry")}</Label>
+              <Select value={expiresInHours} onValueChange={setExpiresInHours}>
+                <SelectTrigger className="text-[13px]">
+                  <SelectValue />
+                </SelectTrigger>
+                <SelectContent>
+                  {EXPIRY_OPTIONS.map((o) => (
+                    <SelectItem key={o.hours} value={String(o.hours)}>
+                      {t(o.key)}
+                    </SelectItem>
+                  ))}
+                </SelectContent>
+              </Select>
+            </div>
+            <div className="grid gap-1.5">
+              <Label className="text-xs">{t("chat.sharePassword")}</Label>
+              <Input
+                type="password"
+                value={password}
+                onChange={(e) => setPassword(e.target.value)}
+                className="text-[13px]"
+              />
+            </div>
+          </div>
+
+          {createdUrl && (
+            <div className="flex items-center gap-2">
+              <Input readOnly value={createdUrl} className="font-mono text-xs" />
+              <Button variant="outline" size="icon" onClick={() => copy(createdUrl)}>
+                <Copy className="size-4" />
+              </Button>
+            </div>
+          )}
+
+          {shares.length > 0 && (
+            <div className="grid gap-1.5">
+              <Label className="text-xs">{t("chat.shareExisting")}</Label>
+              <div className="max-h-48 divide-y overflow-y-auto rounded-md border">
+                {shares.map((share) => (
+                  <div key={share.id} className="flex items-center gap-2 px-3 py-2 text-xs">
+                    <Link2 className="text-muted-foreground size-3.5 shrink-0" />
+                    <span className="text-muted-foreground">
+                      {new Date(share.createdAt).toLocaleDateString()}
+                    </span>
+                    <Badge variant={share.state === "active" ? "default" : "secondary"} className="text-[10px]">
+                      {t(STATE_KEYS[share.state])}
+                    </Badge>
+                    <span className="text-muted-foreground ml-auto">
+                      {t("chat.shareViews", { count: share.viewCount })}
+                    </span>
+                    {share.state === "active" && (
+                      <Button
+                        variant="ghost"
+                        size="sm"
+                        className="h-6 px-2 text-xs"
+                        disabled={revokeShare.isPending}
+                        onClick={() => handleRevoke(share)}
+                      >
+                        {t("chat.shareRevoke")}
+                      </Button>
+                    )}
+                  </div>
+                ))}
+              </div>
+            </div>
+          )}
+        </div>
+
+        <DialogFooter>
+          <Button variant="outline" onClick={() => handleOpenChange(false)}>
+            {t("cancel")}
+          </Button>
+          <Button onClick={handleCreate} disabled={createShare.isPending}>
+            {createShare.isPending && <Loader2 className="mr-2 size-4 animate-spin" />}
+            {t("chat.shareCreate")}
+          </Button>
+        </DialogFooter>
+      </DialogContent>
+    </Dialog>
+  )
+}
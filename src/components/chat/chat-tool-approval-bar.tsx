@@ -0,0 +1,44 @@
+"use client"
+
+import { ShieldAlert } from "lucide-react"
+import { Button } from "@/components/ui/button"
+import { useChatStore } from "@/stores/chat-store"
+import { useT } from "@/stores/language-store"
+
+export function ChatToolApprovalBar() {
+  const t = useT()
+  const pending = useChatStore((s) => s.pendingToolApproval)
+  const answer = useChatStore((s) => s.answerToolApproval)
+
+  if (!pending) return null
+
+  const input =
+    typeof pending.toolInput === "string"
+      ? pending.toolInput
+      : JSON.stringify(pending.toolInput, null, 2)
+
+  return (
+    <div className="mx-4 mb-2 rounded-md border border-amber-200 bg-amber-50 px-3 py-2 dark:border-amber-900 dark:bg-amber-950/30">
+      <div className="flex items-center gap-2">
+        <ShieldAlert className="size-3.5 shrink-0 text-amber-600 dark:text-amber-400" />
+        <p className="flex-1 text-xs text-amber-800 dark:text-amber-200">
+          {t("chat.toolApprovalRequired", { tool: pending.toolName })}
+        </p>
+        <Button size="sm" variant="outline" className="h-7 text-xs" onClick={() => answer("deny")}>
+          {t("chat.toolApprovalDeny")}
+        </Button>
+        <Button size="sm" className="h-7 text-xs" onClick={() => answer("approve")}>
+          {t("chat.toolApprovalApprove")}
+        </Button>
+      </div>
+      {input && input !== "{}" && (
+        <pre className="bg-muted mt-2 max-h-40 overflow-auto rounded p-2 text-xs">
+          <code>{input}</code>
+        </pre>
+      )}
+      <p className="mt-1 text-[11px] text-amber-700/80 dark:text-amber-300/70">
+        {t("chat.toolApprovalHint")}
+      </p>
+    </div>
+  )
+}
@@ -1,7 +1,7 @@
 "use client"
 
 import { useState } from "react"
-import { ChevronDown, Wrench } from "lucide-react"
+import { ChevronDown, Loader2, Wrench } from "lucide-react"
 import { cn } from "@/lib/utils"
 import type { ChatToolCall } from "@/types/chat"
 
@@ -21,6 +21,7 @@ export function ChatToolCallBlock({ toolCall }: ChatToolCallBlockProps) {
       >
         <Wrench className="size-3" />
         <span className="font-mono">{toolCall.toolName}</span>
+        {toolCall.toolProgress != null && <Loader2 className="size-3 animate-spin" />}
         <ChevronDown
           className={cn(
             "ml-auto size-3 transition-transform",
@@ -44,6 +45,16 @@ export function ChatToolCallBlock({ toolCall }: ChatToolCallBlockProps) {
               </pre>
             </div>
           )}
+          {toolCall.toolOutput == null && toolCall.toolProgress != null && (
+            <div>
+              <p className="text-muted-foreground mb-1 text-[10px] font-medium uppercase">
+                Output (running)
+              </p>
+              <pre className="bg-muted max-h-64 overflow-auto rounded p-2 text-xs">
+                <code>{toolCall.toolProgress}</code>
+              </pre>
+            </div>
+          )}
           {toolCall.toolOutput != null && (
             <div>
               <p className="text-muted-foreground mb-1 text-[10px] font-medium uppercase">
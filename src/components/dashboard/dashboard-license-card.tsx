@@ -0,0 +1,77 @@
+"use client"
+
+import { useT } from "@/stores/language-store"
+import type { LicenseState, LicenseStatus, LicenseUsage } from "@/types/license"
+import type { TranslationKey } from "@/locales/zh-CN"
+
+interface LicenseCardProps {
+  license: LicenseStatus
+}
+
+const STATE_STYLES: Record<Exclude<LicenseState, "UNLICENSED">, { labelKey: TranslationKey; badge: string }> = {
+  VALID: { labelKey: "dashboard.licenseStateValid", badge: "bg-emerald-500/10 text-emerald-500" },
+  GRACE: { labelKey: "dashboard.licenseStateGrace", badge: "bg-amber-500/10 text-amber-500" },
+  EXPIRED: { labelKey: "dashboard.licenseStateExpired", badge: "bg-red-500/10 text-red-500" },
+  INVALID: { labelKey: "dashboard.licenseStateInvalid", badge: "bg-red-500/10 text-red-500" },
+}
+
+function UsageBar({ label, usage }: { label: string; usage: LicenseUsage }) {
+  const t = useT()
+  const pct = usage.limit ? Math.min(100, (usage.used / usage.limit) * 100) : 0
+  const full = usage.limit != null && usage.used >= usage.limit
+  return (
+    <div className="space-y-1.5">
+      <div className="flex items-center justify-between text-xs">
+        <span className="text-muted-foreground font-medium">{label}</span>
+        <span className="font-mono">
+          {usage.used} / {usage.limit ?? t("dashboard.licenseUnlimited")}
+        </span>
+      </div>
+      {usage.limit != null && (
+        <div className="bg-muted h-1.5 overflow-hidden rounded dark:bg-black/30">
+          <div
+            className={`h-full rounded ${full ? "bg-red-500" : "bg-indigo-500"}`}
+            style={{ width: `${pct}%` }}
+          />
+        </div>
+      )}
+    </div>
+  )
+}
+
+export function DashboardLicenseCard({ license }: LicenseCardProps) {
+  const t = useT()
+
+  // Community deployments have nothing to show
+  if (license.state === "UNLICENSED") return null
+
+  const style = STATE_STYLES[license.state]
+  const formatDate = (iso: string) => new Date(iso).toLocaleDateString()
+
+  return (
+    <div className="bg-card rounded-[10px] border p-5 opacity-0 animate-[cardIn_0.5s_ease_0.3s_forwards]">
+      <div className="mb-4 flex items-center justify-between">
+        <span className="text-muted-foreground text-[13px] font-semibold">
+          {t("dashboard.license")}
+          {license.licensee && <span className="ml-2 font-normal">{license.licensee}</span>}
+        </span>
+        <span className={`rounded px-2 py-0.5 text-[11px] font-medium ${style.badge}`}>
+          {t(style.labelKey)}
+        </span>
+      </div>
+
+      <div className="grid gap-4 sm:grid-cols-2">
+        <UsageBar label={t("dashboard.licenseSeats")} usage={license.seats} />
+        <UsageBar label={t("dashboard.licenseInstances")} usage={license.instances} />
+      </div>
+
+      <p className="text-muted-foreground mt-4 text-xs">
+        {license.state === "VALID" && license.expiresAt &&
+          t("dashboard.licenseExpires", { date: formatDate(license.expiresAt) })}
+        {license.state === "GRACE" && license.graceEndsAt &&
+          t("dashboard.licenseGraceEnds", { date: formatDate(license.graceEndsAt) })}
+        {(license.state === "EXPIRED" || license.state === "INVALID") && t("dashboard.licenseBlocked")}
+      </p>
+    </div>
+  )
+}
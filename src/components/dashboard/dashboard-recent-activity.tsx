@@ -57,6 +57,8 @@ const ACTION_LABEL_KEYS: Record<string, TranslationKey> = {
   INSTANCE_RESTART: "dashboard.action.INSTANCE_RESTART",
   INSTANCE_CONFIG_PATCH: "dashboard.action.INSTANCE_CONFIG_PATCH",
   INSTANCE_DASHBOARD: "dashboard.action.INSTANCE_DASHBOARD",
+  INSTANCE_SMOKE_TEST: "dashboard.action.INSTANCE_SMOKE_TEST",
+  INSTANCE_LOAD_TEST: "dashboard.action.INSTANCE_LOAD_TEST",
   USER_CREATE: "dashboard.action.USER_CREATE",
   USER_UPDATE: "dashboard.action.USER_UPDATE",
   USER_DELETE: "dashboard.action.USER_DELETE",
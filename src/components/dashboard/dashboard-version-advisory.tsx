@@ -0,0 +1,91 @@
+"use client"
+
+import { ArrowUpCircle } from "lucide-react"
+import { useT } from "@/stores/language-store"
+import type { VersionAdvisory } from "@/types/dashboard"
+
+interface VersionAdvisoryProps {
+  advisory: VersionAdvisory
+}
+
+export function DashboardVersionAdvisory({ advisory }: VersionAdvisoryProps) {
+  const t = useT()
+
+  if (advisory.inventory.length === 0) return null
+
+  return (
+    <div className="bg-card rounded-[10px] border p-5 opacity-0 animate-[cardIn_0.5s_ease_0.3s_forwards]">
+      <div className="mb-4 flex items-center justify-between">
+        <span className="text-muted-foreground flex items-center gap-1.5 text-[13px] font-semibold">
+          <ArrowUpCircle className="size-3.5" />
+          {t("dashboard.versions")}
+        </span>
+        <span className="bg-muted rounded px-2 py-0.5 font-mono text-[11px] text-muted-foreground">
+          {advisory.latest
+            ? t("dashboard.versionsLatest", { version: advisory.latest })
+            : t("dashboard.versionsLatestUnknown")}
+        </span>
+      </div>
+
+      {/* Inventory: version → instance count */}
+      <div className="flex flex-wrap gap-1.5">
+        {advisory.inventory.map((entry) => (
+          <span
+            key={entry.version ?? "unknown"}
+            className={`rounded px-2 py-0.5 font-mono text-[11px] ${
+              advisory.latest && entry.version === advisory.latest
+                ? "bg-emerald-500/10 text-emerald-500"
+                : "bg-muted text-muted-foreground"
+            }`}
+          >
+            {entry.version ? `v${entry.version}` : t("dashboard.versionsUnreported")} × {entry.count}
+          </span>
+        ))}
+      </div>
+
+      {advisory.feedError && (
+        <p className="text-muted-foreground mt-3 text-xs">
+          {t("dashboard.versionsFeedError", { error: advisory.feedError })}
+        </p>
+      )}
+
+      {advisory.latest && (
+        <div className="mt-4 space-y-2">
+          {advisory.outdated.length === 0 ? (
+            <p className="text-muted-foreground text-xs">{t("dashboard.versionsUpToDate")}</p>
+          ) : (
+            advisory.outdated.map((inst) => (
+              <details key={inst.id} className="rounded-lg border bg-muted/40 px-3.5 py-2.5 dark:bg-white/[0.03]">
+                <summary className="flex cursor-pointer items-center justify-between text-[12.5px]">
+                  <span className="font-semibold text-foreground">{inst.name}</span>
+                  <span className="font-mono text-[11px] text-amber-500">
+                    v{inst.version} → v{advisory.latest}
+                  </span>
+                </summary>
+                {inst.changelog.length === 0 ? (
+                  <p className="text-muted-foreground mt-2 text-xs">{t("dashboard.versionsNoChangelog")}</p>
+                ) : (
+                  <ul className="mt-2 space-y-2">
+                    {inst.changelog.map((r) => (
+                      <li key={r.version} className="text-xs">
+                        <span className="font-mono font-semibold">v{r.version}</span>
+                        {r.date && (
+                          <span className="text-muted-foreground ml-2">
+                            {new Date(r.date).toLocaleDateString()}
+                          </span>
+                        )}
+                        {r.notes && (
+                          <p className="text-muted-foreground mt-1 line-clamp-4 whitespace-pre-line">{r.notes}</p>
+                        )}
+                      </li>
+                    ))}
+                  </ul>
+                )}
+              </details>
+            ))
+          )}
+        </div>
+      )}
+    </div>
+  )
+}
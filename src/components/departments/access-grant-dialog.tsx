@@ -42,6 +42,7 @@ export function AccessGrantDialog({
   const [limitAgents, setLimitAgents] = useState(false)
   const [agentInput, setAgentInput] = useState("")
   const [agentIds, setAgentIds] = useState<string[]>([])
+  const [expiresAt, setExpiresAt] = useState("")
 
   const t = useT()
   const { data: instanceData } = useInstances()
@@ -54,6 +55,7 @@ export function AccessGrantDialog({
     setLimitAgents(false)
     setAgentInput("")
     setAgentIds([])
+    setExpiresAt("")
   }
 
   function addAgent() {
@@ -83,6 +85,7 @@ export function AccessGrantDialog({
         departmentId,
         instanceId,
         agentIds: limitAgents ? agentIds : null,
+        expiresAt: expiresAt ? new Date(expiresAt).toISOString() : null,
       })
       toast.success(t('dept.grantSuccess'))
       reset()
@@ -194,6 +197,18 @@ export function AccessGrantDialog({
             )}
           </div>
 
+          <div className="space-y-2">
+            <Label htmlFor="access-expiry" className="text-[13px]">{t('dept.accessExpiry')}</Label>
+            <Input
+              id="access-expiry"
+              type="datetime-local"
+              value={expiresAt}
+              onChange={(e) => setExpiresAt(e.target.value)}
+              className="text-[13px]"
+            />
+            <p className="text-[12px] text-muted-foreground">{t('dept.accessExpiryHint')}</p>
+          </div>
+
           <DialogFooter className="pt-2">
             <Button
               type="button"
@@ -16,6 +16,7 @@ interface AccessGrant {
   instanceName: string
   instanceStatus: string
   agentIds: string[] | null
+  expiresAt: string | null
   grantedByName: string
   createdAt: string
 }
@@ -112,6 +113,12 @@ export function DeptAccessTab({
                     </span>
                     <span className="text-muted-foreground/40">|</span>
                     <span>{t('dept.grantedBy', { name: grant.grantedByName })}</span>
+                    {grant.expiresAt && (
+                      <>
+                        <span className="text-muted-foreground/40">|</span>
+                        <span>{t('dept.expiresAt', { date: new Date(grant.expiresAt).toLocaleString() })}</span>
+                      </>
+                    )}
                   </div>
                 </div>
                 {canManage && (
@@ -1,6 +1,6 @@
 "use client"
 
-import { useEffect, useState } from "react"
+import { useEffect, useRef, useState } from "react"
 import {
   Dialog,
   DialogContent,
@@ -13,9 +13,16 @@ import { Button } from "@/components/ui/button"
 import { Input } from "@/components/ui/input"
 import { Label } from "@/components/ui/label"
 import { Textarea } from "@/components/ui/textarea"
-import { Loader2, Pencil } from "lucide-react"
+import {
+  Select,
+  SelectContent,
+  SelectItem,
+  SelectTrigger,
+  SelectValue,
+} from "@/components/ui/select"
+import { Building2, Loader2, Pencil } from "lucide-react"
 import { toast } from "sonner"
-import { useUpdateDepartment } from "@/hooks/use-departments"
+import { useSetDepartmentIcon, useUpdateDepartment } from "@/hooks/use-departments"
 import type { DepartmentResponse } from "@/types/department"
 import { useT } from "@/stores/language-store"
 
@@ -33,16 +40,35 @@ export function DeptEditDialog({
   const t = useT()
   const [name, setName] = useState("")
   const [description, setDescription] = useState("")
+  const [chatPriority, setChatPriority] = useState<DepartmentResponse["chatPriority"]>("NORMAL")
+
+  const [iconUrl, setIconUrl] = useState<string | null>(null)
+  const iconInputRef = useRef<HTMLInputElement>(null)
 
   const updateDept = useUpdateDepartment(department?.id ?? "")
+  const setIcon = useSetDepartmentIcon(department?.id ?? "")
 
   useEffect(() => {
     if (department) {
       setName(department.name)
       setDescription(department.description || "")
+      setChatPriority(department.chatPriority ?? "NORMAL")
+      setIconUrl(department.iconUrl ?? null)
     }
   }, [department])
 
+  // The icon is saved as soon as it is picked, independently of the form
+  async function handleIcon(file: File | null) {
+    try {
+      const { iconUrl } = await setIcon.mutateAsync(file)
+      setIconUrl(iconUrl)
+    } catch (err) {
+      const message =
+        (err as { data?: { error?: string } })?.data?.error || (err as Error).message || t('operationFailed')
+      toast.error(message)
+    }
+  }
+
   async function handleSubmit(e: React.FormEvent) {
     e.preventDefault()
     if (!department) return
@@ -51,6 +77,7 @@ export function DeptEditDialog({
       await updateDept.mutateAsync({
         name,
         description: description || null,
+        chatPriority,
       })
       toast.success(t('dept.updatedMsg'))
       onOpenChange(false)
@@ -101,6 +128,71 @@ export function DeptEditDialog({
               rows={2}
             />
           </div>
+          <div className="space-y-2">
+            <Label htmlFor="edit-dept-priority" className="text-[13px]">
+              {t('dept.chatPriority')}
+            </Label>
+            <Select
+              value={chatPriority}
+              onValueChange={(v) => setChatPriority(v as DepartmentResponse["chatPriority"])}
+            >
+              <SelectTrigger id="edit-dept-priority" className="text-[13px]">
+                <SelectValue />
+              </SelectTrigger>
+              <SelectContent>
+                <SelectItem value="HIGH">{t('dept.chatPriorityHigh')}</SelectItem>
+                <SelectItem value="NORMAL">{t('dept.chatPriorityNormal')}</SelectItem>
+                <SelectItem value="LOW">{t('dept.chatPriorityLow')}</SelectItem>
+              </SelectContent>
+            </Select>
+            <p className="text-[11px] text-muted-foreground">{t('dept.chatPriorityHint')}</p>
+          </div>
+          <div className="space-y-2">
+            <Label className="text-[13px]">{t('dept.icon')}</Label>
+            <div className="flex items-center gap-3">
+              <div className="flex size-9 shrink-0 items-center justify-center overflow-hidden rounded-lg bg-muted">
+                {iconUrl ? (
+                  // eslint-disable-next-line @next/next/no-img-element
+                  <img src={iconUrl} alt="" className="size-full object-cover" />
+                ) : (
+                  <Building2 className="size-4 text-muted-foreground" />
+                )}
+              </div>
+              <input
+                ref={iconInputRef}
+                type="file"
+                accept="image/png,image/jpeg,image/webp,image/gif"
+                className="hidden"
+                onChange={(e) => {
+                  const file = e.target.files?.[0]
+                  e.target.value = ""
+                  if (file) handleIcon(file)
+                }}
+              />
+              <Button
+                type="button"
+                variant="outline"
+                size="sm"
+                disabled={setIcon.isPending}
+                onClick={() => iconInputRef.current?.click()}
+              >
+                {setIcon.isPending && <Loader2 className="mr-2 size-3.5 animate-spin" />}
+                {t('dept.iconUpload')}
+              </Button>
+              {iconUrl && (
+                <Button
+                  type="button"
+                  variant="ghost"
+                  size="sm"
+                  disabled={setIcon.isPending}
+                  onClick={() => handleIcon(null)}
+                >
+                  {t('dept.iconRemove')}
+                </Button>
+              )}
+            </div>
+            <p className="text-[11px] text-muted-foreground">{t('dept.iconHint')}</p>
+          </div>
           <DialogFooter className="pt-2">
             <Button
               type="button"
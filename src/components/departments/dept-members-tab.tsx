@@ -24,6 +24,7 @@ const roleLabelKeys: Record<string, TranslationKey> = {
   SYSTEM_ADMIN: "user.roleSystemAdmin",
   DEPT_ADMIN: "user.roleDeptAdmin",
   USER: "user.roleUser",
+  VIEWER: "user.roleViewer",
 }
 
 const statusColors: Record<string, string> = {
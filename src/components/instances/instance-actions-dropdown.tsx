@@ -16,6 +16,7 @@ import {
   Pencil,
   Trash2,
   ExternalLink,
+  Megaphone,
 } from "lucide-react"
 import { useT } from "@/stores/language-store"
 import type { InstanceResponse } from "@/types/instance"
@@ -29,6 +30,7 @@ interface InstanceActionsDropdownProps {
   onStart: () => void
   onStop: () => void
   onRestart: () => void
+  onAnnounce: () => void
 }
 
 export function InstanceActionsDropdown({
@@ -40,6 +42,7 @@ export function InstanceActionsDropdown({
   onStart,
   onStop,
   onRestart,
+  onAnnounce,
 }: InstanceActionsDropdownProps) {
   const t = useT()
   const isOnline = instance.status === "ONLINE"
@@ -83,6 +86,10 @@ export function InstanceActionsDropdown({
                 {t('restart')}
               </DropdownMenuItem>
             )}
+            <DropdownMenuItem onClick={onAnnounce} className="gap-2 text-[13px]">
+              <Megaphone className="size-3.5 opacity-60" />
+              {t('instance.announce')}
+            </DropdownMenuItem>
             <DropdownMenuSeparator />
             <DropdownMenuItem onClick={onEdit} className="gap-2 text-[13px]">
               <Pencil className="size-3.5 opacity-60" />
@@ -0,0 +1,156 @@
+"use client"
+
+import { useState } from "react"
+import {
+  Dialog,
+  DialogContent,
+  DialogDescription,
+  DialogFooter,
+  DialogHeader,
+  DialogTitle,
+} from "@/components/ui/dialog"
+import {
+  Select,
+  SelectContent,
+  SelectItem,
+  SelectTrigger,
+  SelectValue,
+} from "@/components/ui/select"
+import { Button } from "@/components/ui/button"
+import { Label } from "@/components/ui/label"
+import { Textarea } from "@/components/ui/textarea"
+import { Loader2, Megaphone } from "lucide-react"
+import { toast } from "sonner"
+import { useCreateInstanceAnnouncement } from "@/hooks/use-instances"
+import { useT } from "@/stores/language-store"
+import type { InstanceResponse } from "@/types/instance"
+
+const DURATIONS = [15, 60, 240, 1440]
+
+interface InstanceAnnounceDialogProps {
+  open: boolean
+  onOpenChange: (open: boolean) => void
+  instance: InstanceResponse | null
+}
+
+export function InstanceAnnounceDialog({
+  open,
+  onOpenChange,
+  instance,
+}: InstanceAnnounceDialogProps) {
+  const t = useT()
+  const [message, setMessage] = useState("")
+  const [level, setLevel] = useState<"INFO" | "WARNING">("INFO")
+  const [durationMinutes, setDurationMinutes] = useState(60)
+
+  const announce = useCreateInstanceAnnouncement(instance?.id ?? "")
+
+  function reset() {
+    setMessage("")
+    setLevel("INFO")
+    setDurationMinutes(60)
+  }
+
+  async function handleSubmit(e: React.FormEvent) {
+    e.preventDefault()
+    if (!instance) return
+
+    try {
+      await announce.mutateAsync({ message, level, durationMinutes })
+      toast.success(t('instance.announceSent', { name: instance.name }))
+      reset()
+      onOpenChange(false)
+    } catch (err) {
+      const message =
+        (err as { data?: { error?: string } })?.data?.error || t('instance.announceFailed')
+      toast.error(message)
+    }
+  }
+
+  return (
+    <Dialog open={open} onOpenChange={onOpenChange}>
+      <DialogContent className="sm:max-w-[460px]">
+        <DialogHeader>
+          <div className="flex items-center gap-3">
+            <div className="flex size-9 items-center justify-center rounded-lg bg-gradient-to-br from-primary/20 via-primary/10 to-primary/5 ring-1 ring-black/[0.04] dark:ring-white/[0.08]">
+              <Megaphone className="size-4 text-primary" />
+            </div>
+            <div>
+              <DialogTitle className="text-base">{t('instance.announceTitle')}</DialogTitle>
+              <DialogDescription className="text-[13px]">
+                {t('instance.announceDesc', { name: instance?.name ?? '' })}
+              </DialogDescription>
+            </div>
+          </div>
+        </DialogHeader>
+
+        <form onSubmit={handleSubmit} className="space-y-4 pt-1">
+          <div className="space-y-2">
+            <Label className="text-[13px]">{t('instance.announceMessage')}</Label>
+            <Textarea
+              value={message}
+              onChange={(e) => setMessage(e.target.value)}
+              placeholder={t('instance.announcePlaceholder')}
+              maxLength={2000}
+              rows={4}
+            />
+          </div>
+
+          <div className="grid grid-cols-2 gap-3">
+            <div className="space-y-2">
+              <Label className="text-[13px]">{t('instance.announceLevel')}</Label>
+              <Select value={level} onValueChange={(v) => setLevel(v as "INFO" | "WARNING")}>
+                <SelectTrigger>
+                  <SelectValue />
+                </SelectTrigger>
+                <SelectContent>
+                  <SelectItem value="INFO">{t('instance.announceLevelInfo')}</SelectItem>
+                  <SelectItem value="WARNING">{t('instance.announceLevelWarning')}</SelectItem>
+                </SelectContent>
+              </Select>
+            </div>
+            <div className="space-y-2">
+              <Label className="text-[13px]">{t('instance.announceDuration')}</Label>
+              <Select
+                value={String(durationMinutes)}
+                onValueChange={(v) => setDurationMinutes(Number(v))}
+              >
+                <SelectTrigger>
+                  <SelectValue />
+                </SelectTrigger>
+                <SelectContent>
+                  {DURATIONS.map((minutes) => (
+                    <SelectItem key={minutes} value={String(minutes)}>
+                      {minutes < 60
+                        ? t('instance.announceMinutes', { n: minutes })
+                        : t('instance.announceHours', { n: minutes / 60 })}
+                    </SelectItem>
+                  ))}
+                </SelectContent>
+              </Select>
+            </div>
+          </div>
+
+          <DialogFooter className="pt-2">
+            <Button
+              type="button"
+              variant="outline"
+              onClick={() => onOpenChange(false)}
+            >
+              {t('cancel')}
+            </Button>
+            <Button
+              type="submit"
+              disabled={announce.isPending || !message.trim()}
+            >
+              {announce.isPending && (
+                <Loader2 className="mr-2 size-4 animate-spin" />
+              )}
+              {t('instance.announceSend')}
+            </Button>
+          </DialogFooter>
+        </form>
+      </DialogContent>
+    </Dialog>
+  )
+}
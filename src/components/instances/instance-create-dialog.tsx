@@ -27,6 +27,7 @@ import { Loader2, Server, ChevronDown } from "lucide-react"
 import { toast } from "sonner"
 import { useT } from "@/stores/language-store"
 import { useCreateInstance } from "@/hooks/use-instances"
+import { normalizeGatewayUrl } from "@/lib/gateway/url"
 
 interface InstanceCreateDialogProps {
   open: boolean
@@ -55,6 +56,7 @@ export function InstanceCreateDialog({
   const [showAdvanced, setShowAdvanced] = useState(false)
   const [memoryLimit, setMemoryLimit] = useState("")
   const [restartPolicy, setRestartPolicy] = useState("unless-stopped")
+  const [sandboxProfile, setSandboxProfile] = useState("standard")
 
   // External mode fields
   const [gatewayUrl, setGatewayUrl] = useState("")
@@ -93,6 +95,7 @@ export function InstanceCreateDialog({
       if (imageName) docker.imageName = imageName
       if (memoryLimit) docker.memoryLimit = parseInt(memoryLimit, 10) * 1024 * 1024 // MB → bytes
       if (restartPolicy !== "unless-stopped") docker.restartPolicy = restartPolicy
+      if (sandboxProfile !== "standard") docker.sandboxProfile = sandboxProfile
       if (Object.keys(docker).length > 0) payload.docker = docker
 
       if (useCustomApiKey && apiKey) {
@@ -127,7 +130,8 @@ export function InstanceCreateDialog({
   }
 
   const isDockerValid = name.length >= 2
-  const isExternalValid = name.length >= 2 && gatewayUrl.length > 0 && gatewayToken.length > 0
+  const gatewayUrlCheck = gatewayUrl ? normalizeGatewayUrl(gatewayUrl) : null
+  const isExternalValid = name.length >= 2 && !!gatewayUrlCheck?.ok && gatewayToken.length > 0
   const isValid = mode === "docker" ? isDockerValid : isExternalValid
 
   return (
@@ -318,6 +322,24 @@ export function InstanceCreateDialog({
                       </SelectContent>
                     </Select>
                   </div>
+                  <div className="space-y-2">
+                    <Label htmlFor="sandboxProfile" className="text-[12px]">
+                      {t('instance.sandboxProfile')}
+                    </Label>
+                    <Select value={sandboxProfile} onValueChange={setSandboxProfile}>
+                      <SelectTrigger id="sandboxProfile" className="text-[13px]">
+                        <SelectValue />
+                      </SelectTrigger>
+                      <SelectContent>
+                        <SelectItem value="standard">{t('instance.sandboxStandard')}</SelectItem>
+                        <SelectItem value="hardened">{t('instance.sandboxHardened')}</SelectItem>
+                        <SelectItem value="isolated">{t('instance.sandboxIsolated')}</SelectItem>
+                      </SelectContent>
+                    </Select>
+                    <p className="text-[11px] text-muted-foreground">
+                      {t('instance.sandboxProfileHint')}
+                    </p>
+                  </div>
                 </div>
               )}
             </div>
@@ -337,7 +359,11 @@ export function InstanceCreateDialog({
                   onChange={(e) => setGatewayUrl(e.target.value)}
                   className="font-mono text-[13px]"
                   required={mode === "external"}
+                  aria-invalid={gatewayUrlCheck?.ok === false}
                 />
+                {gatewayUrlCheck && !gatewayUrlCheck.ok && (
+                  <p className="text-[12px] text-destructive">{gatewayUrlCheck.error}</p>
+                )}
               </div>
               <div className="space-y-2">
                 <Label htmlFor="gatewayToken" className="text-[13px]">
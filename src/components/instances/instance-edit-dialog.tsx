@@ -16,6 +16,7 @@ import { Textarea } from "@/components/ui/textarea"
 import { Loader2, Pencil } from "lucide-react"
 import { toast } from "sonner"
 import { useUpdateInstance } from "@/hooks/use-instances"
+import { normalizeGatewayUrl } from "@/lib/gateway/url"
 import { useT } from "@/stores/language-store"
 import type { InstanceResponse } from "@/types/instance"
 
@@ -37,6 +38,7 @@ export function InstanceEditDialog({
   const [gatewayToken, setGatewayToken] = useState("")
 
   const updateInstance = useUpdateInstance(instance?.id ?? "")
+  const gatewayUrlCheck = normalizeGatewayUrl(gatewayUrl)
 
   useEffect(() => {
     if (instance) {
@@ -117,7 +119,11 @@ export function InstanceEditDialog({
               onChange={(e) => setGatewayUrl(e.target.value)}
               className="font-mono text-[13px]"
               required
+              aria-invalid={!gatewayUrlCheck.ok}
             />
+            {!gatewayUrlCheck.ok && gatewayUrl && (
+              <p className="text-[12px] text-destructive">{gatewayUrlCheck.error}</p>
+            )}
           </div>
           <div className="space-y-2">
             <Label htmlFor="edit-gatewayToken" className="text-[13px]">
@@ -139,7 +145,7 @@ export function InstanceEditDialog({
             >
               {t('cancel')}
             </Button>
-            <Button type="submit" disabled={updateInstance.isPending}>
+            <Button type="submit" disabled={updateInstance.isPending || !gatewayUrlCheck.ok}>
               {updateInstance.isPending && (
                 <Loader2 className="mr-2 size-4 animate-spin" />
               )}
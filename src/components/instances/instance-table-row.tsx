@@ -43,6 +43,7 @@ interface InstanceTableRowProps {
   onStart: (id: string) => void
   onStop: (id: string) => void
   onRestart: (id: string) => void
+  onAnnounce: (instance: InstanceResponse) => void
 }
 
 function useFormatRelativeTime() {
@@ -89,7 +90,7 @@ function getChannelCount(healthData: Record<string, unknown> | null): number | n
 }
 
 function getVersionDisplay(instance: InstanceResponse): string | null {
-  return instance.version || null
+  return instance.version || instance.live?.serverVersion || null
 }
 
 export function InstanceTableRow({
@@ -102,6 +103,7 @@ export function InstanceTableRow({
   onStart,
   onStop,
   onRestart,
+  onAnnounce,
 }: InstanceTableRowProps) {
   const t = useT()
   const formatRelativeTime = useFormatRelativeTime()
@@ -224,6 +226,7 @@ export function InstanceTableRow({
             onStart={() => onStart(instance.id)}
             onStop={() => onStop(instance.id)}
             onRestart={() => onRestart(instance.id)}
+            onAnnounce={() => onAnnounce(instance)}
           />
         </div>
       </TableCell>
@@ -20,6 +20,7 @@ interface InstanceTableProps {
   onStart: (id: string) => void
   onStop: (id: string) => void
   onRestart: (id: string) => void
+  onAnnounce: (instance: InstanceResponse) => void
 }
 
 const headClass =
@@ -34,6 +35,7 @@ export function InstanceTable({
   onStart,
   onStop,
   onRestart,
+  onAnnounce,
 }: InstanceTableProps) {
   const t = useT()
   return (
@@ -64,6 +66,7 @@ export function InstanceTable({
             onStart={onStart}
             onStop={onStop}
             onRestart={onRestart}
+            onAnnounce={onAnnounce}
           />
         ))}
       </TableBody>
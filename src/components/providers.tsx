@@ -1,10 +1,15 @@
 "use client"
 
-import { ThemeProvider } from "next-themes"
+import { ThemeProvider, useTheme } from "next-themes"
 import { QueryClient, QueryClientProvider } from "@tanstack/react-query"
 import { Toaster } from "sonner"
 import { useState, useEffect } from "react"
 import { useLanguageStore } from "@/stores/language-store"
+import { useAuthStore } from "@/stores/auth-store"
+import { useBranding } from "@/hooks/use-branding"
+import { usePreferences, useUpdatePreferences } from "@/hooks/use-preferences"
+import type { UpdatePreferencesInput } from "@/lib/validations/user"
+import type { BrandingPalette } from "@/types/branding"
 
 const REFRESH_INTERVAL = 150 * 60 * 1000 // 150 min (~83% of 180 min token lifetime)
 const LOCK_KEY = 'teamclaw_auth_refresh_ts'
@@ -34,6 +39,60 @@ function LanguageSync() {
   return null
 }
 
+// Locale and theme follow the user across devices: the saved preferences
+// win on load, later changes on this device are saved back
+function PreferencesSync() {
+  const userId = useAuthStore((s) => s.user?.id)
+  return userId ? <PreferencesSyncFor key={userId} /> : null
+}
+
+function PreferencesSyncFor() {
+  const { data: prefs } = usePreferences()
+  const { mutate: savePreferences } = useUpdatePreferences()
+  const language = useLanguageStore((s) => s.language)
+  const setLanguage = useLanguageStore((s) => s.setLanguage)
+  const { theme, setTheme } = useTheme()
+  const [applied, setApplied] = useState(false)
+
+  useEffect(() => {
+    if (!prefs || applied) return
+    setLanguage(prefs.locale)
+    setTheme(prefs.theme)
+    setApplied(true)
+  }, [prefs, applied, setLanguage, setTheme])
+
+  useEffect(() => {
+    if (!prefs || !applied) return
+    const patch: UpdatePreferencesInput = {}
+    if (language !== prefs.locale) patch.locale = language
+    if (theme && theme !== prefs.theme) patch.theme = theme as UpdatePreferencesInput["theme"]
+    if (Object.keys(patch).length > 0) savePreferences(patch)
+  }, [language, theme, prefs, applied, savePreferences])
+
+  return null
+}
+
+/** primaryForeground → --primary-foreground */
+function paletteToCss(palette: BrandingPalette): string {
+  return Object.entries(palette)
+    .filter(([, value]) => value)
+    .map(([key, value]) => `--${key.replace(/[A-Z]/g, (c) => `-${c.toLowerCase()}`)}: ${value};`)
+    .join(" ")
+}
+
+// Overrides theme variables from the deployment's branding settings
+function BrandingSync() {
+  const { colors, darkColors } = useBranding()
+  const light = paletteToCss(colors)
+  const dark = paletteToCss(darkColors)
+  if (!light && !dark) return null
+  return (
+    <style>
+      {`${light ? `:root { ${light} }` : ""}${dark ? ` .dark { ${dark} }` : ""}`}
+    </style>
+  )
+}
+
 export function Providers({ children }: { children: React.ReactNode }) {
   const [queryClient] = useState(
     () =>
@@ -52,6 +111,8 @@ export function Providers({ children }: { children: React.ReactNode }) {
       <QueryClientProvider client={queryClient}>
         <AuthRefresh />
         <LanguageSync />
+        <PreferencesSync />
+        <BrandingSync />
         {children}
         <Toaster
           position="top-center"
@@ -74,7 +74,7 @@ export function UserCreateDialog({
         email,
         name,
         password,
-        role: role as "SYSTEM_ADMIN" | "DEPT_ADMIN" | "USER",
+        role: role as "SYSTEM_ADMIN" | "DEPT_ADMIN" | "USER" | "VIEWER",
         departmentId: departmentId || undefined,
       })
       toast.success(t('user.createdMsg'))
@@ -161,6 +161,7 @@ export function UserCreateDialog({
               </SelectTrigger>
               <SelectContent>
                 <SelectItem value="USER">{t('user.roleUser')}</SelectItem>
+                <SelectItem value="VIEWER">{t('user.roleViewer')}</SelectItem>
                 <SelectItem value="DEPT_ADMIN">{t('user.roleDeptAdmin')}</SelectItem>
                 <SelectItem value="SYSTEM_ADMIN">{t('user.roleSystemAdmin')}</SelectItem>
               </SelectContent>
@@ -20,9 +20,10 @@ import {
 import { Button } from "@/components/ui/button"
 import { Input } from "@/components/ui/input"
 import { Label } from "@/components/ui/label"
+import { Switch } from "@/components/ui/switch"
 import { Loader2, Pencil } from "lucide-react"
 import { toast } from "sonner"
-import { useUpdateUser } from "@/hooks/use-users"
+import { useUpdateUser, useResetUserMfa } from "@/hooks/use-users"
 import { api } from "@/lib/api-client"
 import type { UserResponse } from "@/types/user"
 import { useT } from "@/stores/language-store"
@@ -47,9 +48,11 @@ export function UserEditDialog({
   const [role, setRole] = useState("USER")
   const [departmentId, setDepartmentId] = useState("")
   const [status, setStatus] = useState("ACTIVE")
+  const [mfaRequired, setMfaRequired] = useState(false)
 
   const t = useT()
   const updateUser = useUpdateUser(user?.id ?? "")
+  const resetMfa = useResetUserMfa(user?.id ?? "")
 
   const { data: deptData } = useQuery({
     queryKey: ["departments", "list-all"],
@@ -66,6 +69,7 @@ export function UserEditDialog({
       setRole(user.role)
       setDepartmentId(user.departmentId ?? "")
       setStatus(user.status)
+      setMfaRequired(user.mfaRequired)
     }
   }, [user])
 
@@ -80,13 +84,15 @@ export function UserEditDialog({
       if ((departmentId || null) !== (user.departmentId ?? null))
         payload.departmentId = departmentId || null
       if (status !== user.status) payload.status = status
+      if (mfaRequired !== user.mfaRequired) payload.mfaRequired = mfaRequired
 
       await updateUser.mutateAsync(
         payload as {
           name?: string
-          role?: "SYSTEM_ADMIN" | "DEPT_ADMIN" | "USER"
+          role?: "SYSTEM_ADMIN" | "DEPT_ADMIN" | "USER" | "VIEWER"
           departmentId?: string | null
           status?: "ACTIVE" | "DISABLED"
+          mfaRequired?: boolean
         },
       )
       toast.success(t('user.updatedMsg'))
@@ -98,6 +104,18 @@ export function UserEditDialog({
     }
   }
 
+  async function handleResetMfa() {
+    try {
+      await resetMfa.mutateAsync()
+      toast.success(t('user.mfaResetMsg'))
+      onOpenChange(false)
+    } catch (err) {
+      const message =
+        (err as { data?: { error?: string } })?.data?.error || t('user.resetFailed')
+      toast.error(message)
+    }
+  }
+
   return (
     <Dialog open={open} onOpenChange={onOpenChange}>
       <DialogContent className="sm:max-w-[480px]">
@@ -138,6 +156,7 @@ export function UserEditDialog({
               </SelectTrigger>
               <SelectContent>
                 <SelectItem value="USER">{t('user.roleUser')}</SelectItem>
+                <SelectItem value="VIEWER">{t('user.roleViewer')}</SelectItem>
                 <SelectItem value="DEPT_ADMIN">{t('user.roleDeptAdmin')}</SelectItem>
                 <SelectItem value="SYSTEM_ADMIN">{t('user.roleSystemAdmin')}</SelectItem>
               </SelectContent>
@@ -182,6 +201,42 @@ export function UserEditDialog({
             </Select>
           </div>
 
+          <div className="flex items-center justify-between rounded-md border px-3 py-2.5">
+            <div>
+              <Label htmlFor="edit-mfa-required" className="text-[13px] font-medium">
+                {t('user.mfaRequired')}
+              </Label>
+              <p className="text-[12px] text-muted-foreground">
+                {t('user.mfaRequiredDesc')}
+              </p>
+            </div>
+            <Switch
+              id="edit-mfa-required"
+              checked={mfaRequired}
+              onCheckedChange={setMfaRequired}
+            />
+          </div>
+
+          {user?.mfaEnabled && (
+            <div className="flex items-center justify-between gap-3 rounded-md border px-3 py-2.5">
+              <p className="text-[12px] text-muted-foreground">
+                {t('user.resetMfaDesc')}
+              </p>
+              <Button
+                type="button"
+                variant="outline"
+                size="sm"
+                disabled={resetMfa.isPending}
+                onClick={handleResetMfa}
+              >
+                {resetMfa.isPending && (
+                  <Loader2 className="mr-2 size-4 animate-spin" />
+                )}
+                {t('user.resetMfa')}
+              </Button>
+            </div>
+          )}
+
           <DialogFooter className="pt-2">
             <Button
               type="button"
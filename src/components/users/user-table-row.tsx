@@ -30,12 +30,14 @@ const ROLE_LABEL_KEYS: Record<string, TranslationKey> = {
   SYSTEM_ADMIN: "user.roleSystemAdmin",
   DEPT_ADMIN: "user.roleDeptAdmin",
   USER: "user.roleUser",
+  VIEWER: "user.roleViewer",
 }
 
 const ROLE_COLORS: Record<string, string> = {
   SYSTEM_ADMIN: "bg-red-500/10 text-red-700 dark:text-red-400 border-red-500/20",
   DEPT_ADMIN: "bg-blue-500/10 text-blue-700 dark:text-blue-400 border-blue-500/20",
   USER: "bg-zinc-500/10 text-zinc-600 dark:text-zinc-400 border-zinc-500/20",
+  VIEWER: "bg-amber-500/10 text-amber-700 dark:text-amber-400 border-amber-500/20",
 }
 
 function getInitial(name: string): string {
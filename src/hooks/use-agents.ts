@@ -7,7 +7,7 @@ import {
 } from "@tanstack/react-query"
 import { api } from "@/lib/api-client"
 import { chatKeys } from "./use-chat"
-import type { AgentListResponse, AgentDetail, AgentDefaultsResponse, FileContentResponse } from "@/types/agent"
+import type { AgentListResponse, AgentDetail, AgentDefaultsResponse, FileContentResponse, AgentSyncResult } from "@/types/agent"
 import type { WorkspaceFileEntry } from "@/types/gateway"
 import type { CreateAgentInput, UpdateAgentConfigInput, UpdateAgentDefaultsInput, CloneAgentInput, ClassifyAgentInput } from "@/lib/validations/agent"
 
@@ -165,6 +165,22 @@ export function useCloneAgent() {
   })
 }
 
+export function useSyncAgents() {
+  const qc = useQueryClient()
+  return useMutation({
+    mutationFn: (instanceId?: string) =>
+      api.post<{ results: AgentSyncResult[]; errors: { instanceId: string; error: string }[] }>(
+        instanceId
+          ? `/api/v1/agents/sync?instanceId=${encodeURIComponent(instanceId)}`
+          : "/api/v1/agents/sync",
+      ),
+    onSuccess: () => {
+      qc.invalidateQueries({ queryKey: agentKeys.lists() })
+      qc.invalidateQueries({ queryKey: chatKeys.agents() })
+    },
+  })
+}
+
 export function useClassifyAgent(id: string) {
   const qc = useQueryClient()
   return useMutation({
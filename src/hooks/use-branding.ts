@@ -0,0 +1,41 @@
+"use client"
+
+import { useQuery, useMutation, useQueryClient } from "@tanstack/react-query"
+import { api } from "@/lib/api-client"
+import { LOGO_SRC } from "@/lib/logo"
+import type { Branding } from "@/types/branding"
+import type { BrandingInput } from "@/lib/validations/branding"
+
+export const brandingKeys = {
+  all: ["branding"] as const,
+}
+
+const FALLBACK: Branding = {
+  productName: "TeamClaw",
+  logoUrl: null,
+  colors: {},
+  darkColors: {},
+  loginBanner: null,
+}
+
+/** Deployment branding; falls back to the built-in look until loaded */
+export function useBranding() {
+  const { data } = useQuery({
+    queryKey: brandingKeys.all,
+    queryFn: () => api.get<Branding>("/api/v1/branding"),
+    staleTime: 5 * 60 * 1000,
+  })
+  const branding = data ?? FALLBACK
+  return { ...branding, logoSrc: branding.logoUrl ?? LOGO_SRC }
+}
+
+export function useUpdateBranding() {
+  const queryClient = useQueryClient()
+  return useMutation({
+    mutationFn: (input: BrandingInput) =>
+      api.put<Branding>("/api/v1/settings/branding", input),
+    onSuccess: (data) => {
+      queryClient.setQueryData(brandingKeys.all, data)
+    },
+  })
+}
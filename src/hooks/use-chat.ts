@@ -2,12 +2,24 @@
 
 import {
   useQuery,
+  useInfiniteQuery,
   useMutation,
   useQueryClient,
 } from "@tanstack/react-query"
 import { api } from "@/lib/api-client"
 import { useAuthStore } from "@/stores/auth-store"
-import type { ChatAgentInfo, ChatSessionResponse, ChatHistoryResponse } from "@/types/chat"
+import type {
+  ChatAgentInfo,
+  ChatAnnouncement,
+  ChatSessionResponse,
+  ChatHistoryResponse,
+  ChatMessage,
+  ChatSnapshotBatch,
+  SessionShareInfo,
+} from "@/types/chat"
+import type { CreateSessionShareInput } from "@/lib/validations/chat"
+import type { CreateSupportTicketInput } from "@/lib/validations/support-ticket"
+import type { SupportTicketResponse } from "@/types/support"
 
 // ─── Query Key Factory ───────────────────────────────────────────────
 
@@ -17,6 +29,10 @@ export const chatKeys = {
   sessions: () => [...chatKeys.all, "sessions"] as const,
   history: (sessionId: string | null) =>
     [...chatKeys.all, "history", sessionId] as const,
+  shares: (sessionId: string | null) =>
+    [...chatKeys.all, "shares", sessionId] as const,
+  announcements: (instanceId: string | null) =>
+    [...chatKeys.all, "announcements", instanceId] as const,
 }
 
 // ─── Agents ──────────────────────────────────────────────────────────
@@ -41,20 +57,68 @@ export function useChatSessions() {
     queryFn: () =>
       api.get<{ sessions: ChatSessionResponse[] }>("/api/v1/chat/sessions"),
     enabled: !!user,
+    // Titles are generated in the background after a session's first exchange
+    refetchInterval: (query) =>
+      query.state.data?.sessions.some(
+        (s) => !s.title && s.lastMessageAt && Date.now() - new Date(s.lastMessageAt).getTime() < 2 * 60_000,
+      )
+        ? 5_000
+        : false,
     select: (data) => data.sessions,
   })
 }
 
 // ─── History ────────────────────────────────────────────────────────
 
+/**
+ * Session history, loaded newest page first; fetchNextPage loads the page
+ * before the oldest one loaded. `data` is all loaded pages merged.
+ */
 export function useChatHistory(sessionId: string | null) {
-  return useQuery({
+  return useInfiniteQuery({
     queryKey: chatKeys.history(sessionId),
-    queryFn: () =>
+    queryFn: ({ pageParam }) =>
       api.get<ChatHistoryResponse>(
-        `/api/v1/chat/sessions/${sessionId}/history`,
+        `/api/v1/chat/sessions/${sessionId}/history${pageParam !== null ? `?before=${pageParam}` : ""}`,
       ),
+    initialPageParam: null as number | null,
+    getNextPageParam: (last) => (last.page?.hasOlder ? last.page.start : undefined),
     enabled: !!sessionId,
+    select: (data) => mergeHistoryPages(data.pages),
+  })
+}
+
+/** Oldest page first; a snapshot batch split across pages is joined again */
+function mergeHistoryPages(pages: ChatHistoryResponse[]): ChatHistoryResponse {
+  const snapshots: ChatSnapshotBatch[] = []
+  const currentMessages: ChatMessage[] = []
+  for (const page of [...pages].reverse()) {
+    for (const batch of page.snapshots) {
+      const last = snapshots[snapshots.length - 1]
+      if (last?.batchId === batch.batchId) {
+        snapshots[snapshots.length - 1] = { ...last, messages: [...last.messages, ...batch.messages] }
+      } else {
+        snapshots.push(batch)
+      }
+    }
+    currentMessages.push(...page.currentMessages)
+  }
+  return { ...pages[0], snapshots, currentMessages }
+}
+
+// ─── Announcements ───────────────────────────────────────────────────
+
+/** Notices in effect for the instance; ones made mid-run also arrive on the stream */
+export function useChatAnnouncements(instanceId: string | null) {
+  return useQuery({
+    queryKey: chatKeys.announcements(instanceId),
+    queryFn: () =>
+      api.get<{ announcements: ChatAnnouncement[] }>(
+        `/api/v1/chat/announcements?instanceId=${instanceId}`,
+      ),
+    enabled: !!instanceId,
+    refetchInterval: 60_000,
+    select: (data) => data.announcements,
   })
 }
 
@@ -70,6 +134,19 @@ export function useDeleteChatSession() {
   })
 }
 
+// ─── Rename Session ─────────────────────────────────────────────────
+
+export function useRenameChatSession() {
+  const qc = useQueryClient()
+  return useMutation({
+    mutationFn: ({ id, title }: { id: string; title: string }) =>
+      api.patch<{ session: { id: string; title: string } }>(`/api/v1/chat/sessions/${id}`, { title }),
+    onSuccess: () => {
+      qc.invalidateQueries({ queryKey: chatKeys.sessions() })
+    },
+  })
+}
+
 // ─── Clear Context ──────────────────────────────────────────────────
 
 export function useClearContext() {
@@ -99,3 +176,45 @@ export function useNewConversation() {
     },
   })
 }
+
+// ─── Share Links ────────────────────────────────────────────────────
+
+export function useSessionShares(sessionId: string | null) {
+  return useQuery({
+    queryKey: chatKeys.shares(sessionId),
+    queryFn: () =>
+      api.get<{ shares: SessionShareInfo[] }>(`/api/v1/chat/sessions/${sessionId}/shares`),
+    enabled: !!sessionId,
+  })
+}
+
+export function useCreateSessionShare(sessionId: string) {
+  const qc = useQueryClient()
+  return useMutation({
+    mutationFn: (body: CreateSessionShareInput) =>
+      api.post<{ share: SessionShareInfo }>(`/api/v1/chat/sessions/${sessionId}/shares`, body),
+    onSuccess: () => {
+      qc.invalidateQueries({ queryKey: chatKeys.shares(sessionId) })
+    },
+  })
+}
+
+export function useRevokeSessionShare(sessionId: string) {
+  const qc = useQueryClient()
+  return useMutation({
+    mutationFn: (shareId: string) =>
+      api.delete<{ share: SessionShareInfo }>(`/api/v1/chat/shares/${shareId}`),
+    onSuccess: () => {
+      qc.invalidateQueries({ queryKey: chatKeys.shares(sessionId) })
+    },
+  })
+}
+
+// ─── Problem Reports ────────────────────────────────────────────────
+
+export function useReportMessage(sessionId: string) {
+  return useMutation({
+    mutationFn: (body: CreateSupportTicketInput) =>
+      api.post<{ ticket: SupportTicketResponse }>(`/api/v1/chat/sessions/${sessionId}/report`, body),
+  })
+}
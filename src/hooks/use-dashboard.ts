@@ -2,11 +2,14 @@
 
 import { useQuery } from "@tanstack/react-query"
 import { api } from "@/lib/api-client"
-import type { DashboardResponse } from "@/types/dashboard"
+import type { DashboardResponse, VersionAdvisory } from "@/types/dashboard"
+import type { LicenseStatus } from "@/types/license"
 
 export const dashboardKeys = {
   all: ["dashboard"] as const,
   stats: () => [...dashboardKeys.all, "stats"] as const,
+  license: () => [...dashboardKeys.all, "license"] as const,
+  versions: () => [...dashboardKeys.all, "versions"] as const,
 }
 
 export function useDashboardStats(enabled = true) {
@@ -17,3 +20,21 @@ export function useDashboardStats(enabled = true) {
     enabled,
   })
 }
+
+export function useLicenseStatus(enabled = true) {
+  return useQuery({
+    queryKey: dashboardKeys.license(),
+    queryFn: () => api.get<LicenseStatus>("/api/v1/license"),
+    staleTime: 5 * 60 * 1000,
+    enabled,
+  })
+}
+
+export function useVersionAdvisory(enabled = true) {
+  return useQuery({
+    queryKey: dashboardKeys.versions(),
+    queryFn: () => api.get<VersionAdvisory>("/api/v1/monitor/versions"),
+    staleTime: 10 * 60 * 1000,
+    enabled,
+  })
+}
@@ -6,6 +6,7 @@ import {
   useQueryClient,
 } from "@tanstack/react-query"
 import { api } from "@/lib/api-client"
+import { uploadChatFile } from "@/lib/chat-stream"
 import type {
   DepartmentResponse,
   DepartmentDetailResponse,
@@ -76,6 +77,21 @@ export function useUpdateDepartment(id: string) {
   })
 }
 
+/** Upload a new department icon, or clear it with null */
+export function useSetDepartmentIcon(id: string) {
+  const qc = useQueryClient()
+  return useMutation({
+    mutationFn: async (file: File | null) => {
+      const fileId = file ? (await uploadChatFile(file)).id : null
+      return api.put<{ iconUrl: string | null }>(`/api/v1/departments/${id}/icon`, { fileId })
+    },
+    onSuccess: () => {
+      qc.invalidateQueries({ queryKey: departmentKeys.lists() })
+      qc.invalidateQueries({ queryKey: departmentKeys.detail(id) })
+    },
+  })
+}
+
 export function useDeleteDepartment() {
   const qc = useQueryClient()
   return useMutation({
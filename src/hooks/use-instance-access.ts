@@ -7,7 +7,11 @@ import {
 } from "@tanstack/react-query"
 import { api } from "@/lib/api-client"
 import { departmentKeys } from "./use-departments"
-import type { GrantAccessInput, UpdateAccessInput } from "@/lib/validations/instance-access"
+import type {
+  GrantAccessInput,
+  GrantUserAccessInput,
+  UpdateAccessInput,
+} from "@/lib/validations/instance-access"
 
 // ─── Types ───────────────────────────────────────────────────────────
 
@@ -19,6 +23,22 @@ export interface InstanceAccessGrant {
   instanceName: string
   instanceStatus: string
   agentIds: string[] | null
+  expiresAt: string | null
+  grantedByName: string
+  createdAt: string
+  updatedAt: string
+}
+
+export interface UserInstanceAccessGrant {
+  id: string
+  userId: string
+  userName: string
+  userEmail: string
+  instanceId: string
+  instanceName: string
+  instanceStatus: string
+  agentIds: string[] | null
+  expiresAt: string | null
   grantedByName: string
   createdAt: string
   updatedAt: string
@@ -31,6 +51,9 @@ export const instanceAccessKeys = {
   lists: () => [...instanceAccessKeys.all, "list"] as const,
   list: (params?: { departmentId?: string; instanceId?: string }) =>
     [...instanceAccessKeys.lists(), params ?? {}] as const,
+  userLists: () => [...instanceAccessKeys.all, "users"] as const,
+  userList: (params?: { userId?: string; instanceId?: string }) =>
+    [...instanceAccessKeys.userLists(), params ?? {}] as const,
 }
 
 // ─── List ────────────────────────────────────────────────────────────
@@ -96,3 +119,48 @@ export function useRevokeAccess() {
     },
   })
 }
+
+// ─── User Grants ─────────────────────────────────────────────────────
+
+export function useUserInstanceAccessList(params?: {
+  userId?: string
+  instanceId?: string
+}) {
+  const searchParams = new URLSearchParams()
+  if (params?.userId) searchParams.set("userId", params.userId)
+  if (params?.instanceId) searchParams.set("instanceId", params.instanceId)
+
+  const qs = searchParams.toString()
+  const endpoint = `/api/v1/instance-access/users${qs ? `?${qs}` : ""}`
+
+  return useQuery({
+    queryKey: instanceAccessKeys.userList(params),
+    queryFn: () => api.get<{ grants: UserInstanceAccessGrant[] }>(endpoint),
+    enabled: !!(params?.userId || params?.instanceId),
+  })
+}
+
+export function useGrantUserAccess() {
+  const qc = useQueryClient()
+  return useMutation({
+    mutationFn: (data: GrantUserAccessInput) =>
+      api.post<{ grant: UserInstanceAccessGrant }>(
+        "/api/v1/instance-access/users",
+        data,
+      ),
+    onSuccess: () => {
+      qc.invalidateQueries({ queryKey: instanceAccessKeys.userLists() })
+    },
+  })
+}
+
+export function useRevokeUserAccess() {
+  const qc = useQueryClient()
+  return useMutation({
+    mutationFn: (id: string) =>
+      api.delete(`/api/v1/instance-access/users/${id}`),
+    onSuccess: () => {
+      qc.invalidateQueries({ queryKey: instanceAccessKeys.userLists() })
+    },
+  })
+}
@@ -16,6 +16,8 @@ import type {
   UpdateInstanceInput,
   UpdateInstanceConfigInput,
 } from "@/types/instance"
+import type { ChatAnnouncement } from "@/types/chat"
+import type { CreateAnnouncementInput } from "@/lib/validations/announcement"
 
 // ─── Query Key Factory ───────────────────────────────────────────────
 
@@ -38,12 +40,15 @@ export function useInstances(params?: {
   pageSize?: number
   status?: string
   search?: string
+  /** Merge connection and container state into each instance */
+  live?: boolean
 }) {
   const searchParams = new URLSearchParams()
   if (params?.page) searchParams.set("page", String(params.page))
   if (params?.pageSize) searchParams.set("pageSize", String(params.pageSize))
   if (params?.status) searchParams.set("status", params.status)
   if (params?.search) searchParams.set("search", params.search)
+  if (params?.live) searchParams.set("include", "live")
 
   const qs = searchParams.toString()
   const endpoint = `/api/v1/instances${qs ? `?${qs}` : ""}`
@@ -53,6 +58,7 @@ export function useInstances(params?: {
       page: params?.page?.toString(),
       status: params?.status,
       search: params?.search,
+      include: params?.live ? "live" : undefined,
     }),
     queryFn: () => api.get<InstanceListResponse>(endpoint),
     refetchInterval: 30_000,
@@ -178,6 +184,13 @@ export function useRestartInstance() {
   })
 }
 
+export function useCreateInstanceAnnouncement(id: string) {
+  return useMutation({
+    mutationFn: (data: CreateAnnouncementInput) =>
+      api.post<{ announcement: ChatAnnouncement }>(`/api/v1/instances/${id}/announcements`, data),
+  })
+}
+
 export function useUpdateInstanceConfig(id: string) {
   const qc = useQueryClient()
   return useMutation({
@@ -0,0 +1,34 @@
+"use client"
+
+import { useQuery, useMutation, useQueryClient } from "@tanstack/react-query"
+import { api } from "@/lib/api-client"
+import type { UserPreferences } from "@/types/user"
+import type { UpdatePreferencesInput } from "@/lib/validations/user"
+
+export const preferenceKeys = {
+  all: ["preferences"] as const,
+}
+
+/** The current user's preferences (defaults filled in by the server) */
+export function usePreferences(enabled = true) {
+  return useQuery({
+    queryKey: preferenceKeys.all,
+    queryFn: () =>
+      api.get<{ preferences: UserPreferences }>("/api/v1/users/me/preferences").then((r) => r.preferences),
+    staleTime: 5 * 60 * 1000,
+    enabled,
+  })
+}
+
+export function useUpdatePreferences() {
+  const queryClient = useQueryClient()
+  return useMutation({
+    mutationFn: (input: UpdatePreferencesInput) =>
+      api
+        .patch<{ preferences: UserPreferences }>("/api/v1/users/me/preferences", input)
+        .then((r) => r.preferences),
+    onSuccess: (preferences) => {
+      queryClient.setQueryData(preferenceKeys.all, preferences)
+    },
+  })
+}
@@ -102,3 +102,14 @@ export function useResetUserPassword(id: string) {
       api.post<{ message: string }>(`/api/v1/users/${id}/reset-password`, data),
   })
 }
+
+export function useResetUserMfa(id: string) {
+  const qc = useQueryClient()
+  return useMutation({
+    mutationFn: () => api.delete<{ message: string }>(`/api/v1/users/${id}/mfa`),
+    onSuccess: () => {
+      qc.invalidateQueries({ queryKey: userKeys.lists() })
+      qc.invalidateQueries({ queryKey: userKeys.detail(id) })
+    },
+  })
+}
@@ -0,0 +1,33 @@
+export async function register() {
+  // Node-only startup work; the edge runtime (middleware) has no fs/crypto
+  if (process.env.NEXT_RUNTIME !== 'nodejs') return
+
+  const { initTracing } = await import('@/lib/tracing')
+  initTracing()
+
+  const { loadPersistedLogLevels } = await import('@/lib/logger/config')
+  await loadPersistedLogLevels().catch(console.error)
+
+  const { loadAuditSamplingRules } = await import('@/lib/audit-sampling')
+  await loadAuditSamplingRules().catch(console.error)
+
+  const { loadToolRedactionConfig, startRedactionSync } = await import('@/lib/chat/redaction')
+  await loadToolRedactionConfig().catch(console.error)
+  startRedactionSync()
+
+  const { loadPolicyOverrides, startPolicySync } = await import('@/lib/auth/policy-store')
+  await loadPolicyOverrides().catch(console.error)
+  startPolicySync()
+
+  const { initLicense } = await import('@/lib/license')
+  await initLicense().catch(console.error)
+
+  // Unlike the gateway jobs this one runs without DOCKER_NETWORK too:
+  // temporary roles and grants must be reverted in every deployment
+  const { startAccessExpiry } = await import('@/lib/access-expiry')
+  startAccessExpiry()
+
+  // Not awaited: reconnecting to the gateways can take a while
+  const { resumeInterruptedRuns } = await import('@/lib/chat/run-recovery')
+  resumeInterruptedRuns().catch(console.error)
+}
@@ -0,0 +1,225 @@
+import { prisma } from '@/lib/db'
+import { auditLog } from '@/lib/audit'
+import { notifyDepartment } from '@/lib/notifications'
+import { createLogger } from '@/lib/logger'
+import type { Prisma, User } from '@/generated/prisma'
+
+// Time-boxed access. InstanceAccess / UserInstanceAccess.expiresAt and
+// User.roleExpiresAt stop counting the moment they pass (activeGrantWhere /
+// effectiveRole); this job then removes the grant or reverts the role to
+// baseRole, and warns the grantee's department ACCESS_EXPIRY_NOTICE_HOURS
+// beforehand (default 72; department grants and roles only).
+
+const DEFAULT_NOTICE_HOURS = 72
+const INTERVAL_MS = 5 * 60_000
+// Every app process runs the job; the first to take this lock does the tick
+const REDIS_LOCK_KEY = 'access-expiry:tick'
+const REDIS_LOCK_TTL_MS = INTERVAL_MS - 30_000
+
+const log = createLogger('access-expiry')
+
+const globalForAccessExpiry = globalThis as unknown as {
+  accessExpiryTimer?: ReturnType<typeof setInterval> | null
+  accessExpiryRunning?: boolean
+}
+
+function noticeMs(): number {
+  const n = parseInt(process.env.ACCESS_EXPIRY_NOTICE_HOURS ?? '', 10)
+  return (Number.isFinite(n) && n >= 0 ? n : DEFAULT_NOTICE_HOURS) * 3600_000
+}
+
+/**
+ * User update fields for a role change. An expiry makes the new role
+ * temporary, reverting to the role held before the (first) elevation; null,
+ * or a role change without an expiry, makes the role permanent.
+ */
+export function roleExpiryData(
+  current: Pick<User, 'role' | 'baseRole' | 'roleExpiresAt'>,
+  roleExpiresAt: string | null | undefined,
+  roleChanged = true,
+): Prisma.UserUpdateInput {
+  if (roleExpiresAt) {
+    return {
+      roleExpiresAt: new Date(roleExpiresAt),
+      baseRole: current.roleExpiresAt ? current.baseRole : current.role,
+      roleExpiryNotifiedAt: null,
+    }
+  }
+  if (roleExpiresAt === null || roleChanged) {
+    return { roleExpiresAt: null, baseRole: null, roleExpiryNotifiedAt: null }
+  }
+  return {}
+}
+
+const stamp = (d: Date) => d.toISOString().slice(0, 16).replace('T', ' ')
+
+// ─── Expiry ─────────────────────────────────────────────────────────
+
+async function expireGrants(): Promise<void> {
+  const now = new Date()
+  const expired = await prisma.instanceAccess.findMany({
+    where: { expiresAt: { lte: now } },
+    include: {
+      department: { select: { name: true } },
+      instance: { select: { name: true } },
+    },
+  })
+
+  for (const g of expired) {
+    // Re-check the expiry: the grant may have been extended meanwhile
+    const { count } = await prisma.instanceAccess.deleteMany({ where: { id: g.id, expiresAt: { lte: now } } })
+    if (count === 0) continue
+
+    auditLog({
+      userId: g.grantedById,
+      action: 'INSTANCE_ACCESS_EXPIRE',
+      resource: 'instance_access',
+      resourceId: g.id,
+      details: {
+        departmentName: g.department.name,
+        instanceName: g.instance.name,
+        expiresAt: g.expiresAt!.toISOString(),
+      },
+      ipAddress: 'system',
+      result: 'SUCCESS',
+    })
+    await notifyDepartment(g.departmentId, {
+      title: `Access to ${g.instance.name} has expired`,
+      text: `The temporary grant of instance ${g.instance.name} to ${g.department.name} ended at ${stamp(g.expiresAt!)} UTC.`,
+      source: `instance_access:${g.id}`,
+    })
+  }
+  if (expired.length > 0) log.info('Expired instance access grants', { count: expired.length })
+
+  const expiredUserGrants = await prisma.userInstanceAccess.findMany({
+    where: { expiresAt: { lte: now } },
+    include: {
+      user: { select: { name: true } },
+      instance: { select: { name: true } },
+    },
+  })
+  for (const g of expiredUserGrants) {
+    const { count } = await prisma.userInstanceAccess.deleteMany({ where: { id: g.id, expiresAt: { lte: now } } })
+    if (count === 0) continue
+
+    auditLog({
+      userId: g.grantedById,
+      action: 'INSTANCE_ACCESS_EXPIRE',
+      resource: 'instance_access',
+      resourceId: g.id,
+      details: {
+        userName: g.user.name,
+        instanceName: g.instance.name,
+        expiresAt: g.expiresAt!.toISOString(),
+      },
+      ipAddress: 'system',
+      result: 'SUCCESS',
+    })
+  }
+  if (expiredUserGrants.length > 0) log.info('Expired user instance grants', { count: expiredUserGrants.length })
+}
+
+async function expireRoles(): Promise<void> {
+  const now = new Date()
+  const expired = await prisma.user.findMany({
+    where: { roleExpiresAt: { lte: now } },
+    select: { id: true, name: true, role: true, baseRole: true, roleExpiresAt: true, departmentId: true },
+  })
+
+  for (const u of expired) {
+    const to = u.baseRole ?? 'USER'
+    const { count } = await prisma.user.updateMany({
+      where: { id: u.id, roleExpiresAt: { lte: now } },
+      data: { role: to, roleExpiresAt: null, baseRole: null, roleExpiryNotifiedAt: null },
+    })
+    if (count === 0) continue
+
+    auditLog({
+      userId: u.id,
+      action: 'USER_ROLE_EXPIRE',
+      resource: 'user',
+      resourceId: u.id,
+      details: { name: u.name },
+      changes: { role: { before: u.role, after: to } },
+      ipAddress: 'system',
+      result: 'SUCCESS',
+    })
+    if (u.departmentId) {
+      await notifyDepartment(u.departmentId, {
+        title: `Temporary ${u.role} role of ${u.name} has expired`,
+        text: `${u.name} is back to ${to} since ${stamp(u.roleExpiresAt!)} UTC.`,
+        source: `user:${u.id}`,
+      })
+    }
+  }
+  if (expired.length > 0) log.info('Reverted temporary roles', { count: expired.length })
+}
+
+// ─── Advance notice ─────────────────────────────────────────────────
+
+async function sendNotices(): Promise<void> {
+  const now = new Date()
+  const soon = { gt: now, lte: new Date(now.getTime() + noticeMs()) }
+
+  const grants = await prisma.instanceAccess.findMany({
+    where: { expiresAt: soon, expiryNotifiedAt: null },
+    include: {
+      department: { select: { name: true } },
+      instance: { select: { name: true } },
+    },
+  })
+  for (const g of grants) {
+    await notifyDepartment(g.departmentId, {
+      title: `Access to ${g.instance.name} expires ${stamp(g.expiresAt!)} UTC`,
+      text: `${g.department.name}'s temporary grant of instance ${g.instance.name} ends at ${stamp(g.expiresAt!)} UTC. Ask an administrator to extend it if it is still needed.`,
+      source: `instance_access:${g.id}`,
+    })
+    await prisma.instanceAccess.update({ where: { id: g.id }, data: { expiryNotifiedAt: now } })
+  }
+
+  const users = await prisma.user.findMany({
+    where: { roleExpiresAt: soon, roleExpiryNotifiedAt: null, departmentId: { not: null } },
+    select: { id: true, name: true, role: true, baseRole: true, roleExpiresAt: true, departmentId: true },
+  })
+  for (const u of users) {
+    await notifyDepartment(u.departmentId!, {
+      title: `Temporary ${u.role} role of ${u.name} expires ${stamp(u.roleExpiresAt!)} UTC`,
+      text: `${u.name} returns to ${u.baseRole ?? 'USER'} at ${stamp(u.roleExpiresAt!)} UTC.`,
+      source: `user:${u.id}`,
+    })
+    await prisma.user.update({ where: { id: u.id }, data: { roleExpiryNotifiedAt: now } })
+  }
+}
+
+/** Whether this process should run the tick; without Redis every process does */
+async function claimTick(): Promise<boolean> {
+  try {
+    const { redis } = await import('@/lib/redis')
+    return (await redis.set(REDIS_LOCK_KEY, String(process.pid), 'PX', REDIS_LOCK_TTL_MS, 'NX')) === 'OK'
+  } catch (err) {
+    log.warn('Could not take the access expiry lock', { error: (err as Error).message })
+    return true
+  }
+}
+
+async function tick(): Promise<void> {
+  if (globalForAccessExpiry.accessExpiryRunning) return
+  globalForAccessExpiry.accessExpiryRunning = true
+  try {
+    if (!(await claimTick())) return
+    await expireGrants()
+    await expireRoles()
+    await sendNotices()
+  } catch (err) {
+    log.error('Access expiry tick failed', { err })
+  } finally {
+    globalForAccessExpiry.accessExpiryRunning = false
+  }
+}
+
+/** Start the expiry / notice job (idempotent across hot reloads) */
+export function startAccessExpiry(): void {
+  if (globalForAccessExpiry.accessExpiryTimer) return
+  void tick()
+  globalForAccessExpiry.accessExpiryTimer = setInterval(() => void tick(), INTERVAL_MS)
+}
@@ -0,0 +1,343 @@
+import { prisma } from '@/lib/db'
+import { auditLog } from '@/lib/audit'
+import { notifyDepartment } from '@/lib/notifications'
+import { createLogger } from '@/lib/logger'
+import type { AccessReviewCampaign, AccessReviewItem, Prisma } from '@/generated/prisma'
+import type {
+  AccessReviewCampaignResponse,
+  AccessReviewItemResponse,
+  AccessReviewProgress,
+} from '@/types/access-review'
+
+// Access recertification. A campaign snapshots every InstanceAccess grant
+// (reviewed by the DEPT_ADMINs of the granted department) and every elevated
+// role assignment (reviewed by SYSTEM_ADMINs). Reviewers record keep/revoke;
+// revokes apply immediately. Departments with open items get reminders as
+// the deadline approaches, and when it passes the campaign closes — grants
+// nobody reviewed are revoked if the campaign has autoRevoke. Unreviewed
+// role assignments are left alone (demoting admins unattended could lock
+// everyone out); they stay PENDING in the closed campaign for follow-up.
+//
+// ACCESS_REVIEW_QUARTERLY=true — open a campaign automatically in the first
+//   days of each quarter (Jan/Apr/Jul/Oct), due ACCESS_REVIEW_DAYS later.
+
+export const DEFAULT_REVIEW_DAYS = 14
+const REMIND_WITHIN_MS = 3 * 86400_000
+const REMIND_EVERY_MS = 86400_000
+const SCHEDULER_INTERVAL_MS = 60 * 60_000
+
+const log = createLogger('access-reviews')
+
+const globalForAccessReviews = globalThis as unknown as {
+  accessReviewTimer?: ReturnType<typeof setInterval> | null
+  accessReviewTickRunning?: boolean
+}
+
+export function reviewDays(): number {
+  const n = parseInt(process.env.ACCESS_REVIEW_DAYS ?? '', 10)
+  return Number.isFinite(n) && n > 0 ? n : DEFAULT_REVIEW_DAYS
+}
+
+// ─── Campaign creation ──────────────────────────────────────────────
+
+/** Snapshot current grants and elevated roles into a new campaign */
+export async function createAccessReviewCampaign(opts: {
+  name: string
+  dueAt: Date
+  autoRevoke: boolean
+  includeRoles: boolean
+  createdById: string | null
+}): Promise<AccessReviewCampaign & { itemCount: number }> {
+  const [grants, users] = await Promise.all([
+    prisma.instanceAccess.findMany({
+      include: {
+        department: { select: { name: true } },
+        instance: { select: { name: true } },
+      },
+    }),
+    opts.includeRoles
+      ? prisma.user.findMany({
+          where: { status: 'ACTIVE', role: { not: 'USER' } },
+          select: { id: true, name: true, email: true, role: true, department: { select: { name: true } } },
+        })
+      : Promise.resolve([]),
+  ])
+
+  const items: Omit<Prisma.AccessReviewItemCreateManyInput, 'campaignId'>[] = [
+    ...grants.map((g) => ({
+      kind: 'instance_access',
+      subjectId: g.id,
+      departmentId: g.departmentId,
+      summary: {
+        departmentName: g.department.name,
+        instanceId: g.instanceId,
+        instanceName: g.instance.name,
+        agentIds: g.agentIds as string[] | null,
+      },
+    })),
+    ...users.map((u) => ({
+      kind: 'role',
+      subjectId: u.id,
+      departmentId: null,
+      summary: { userName: u.name, email: u.email, role: u.role, departmentName: u.department?.name ?? null },
+    })),
+  ]
+
+  const campaign = await prisma.$transaction(async (tx) => {
+    const c = await tx.accessReviewCampaign.create({
+      data: { name: opts.name, dueAt: opts.dueAt, autoRevoke: opts.autoRevoke, createdById: opts.createdById },
+    })
+    if (items.length > 0) {
+      await tx.accessReviewItem.createMany({ data: items.map((i) => ({ ...i, campaignId: c.id })) })
+    }
+    return c
+  })
+
+  return { ...campaign, itemCount: items.length }
+}
+
+// ─── Decisions ──────────────────────────────────────────────────────
+
+/** Carry out a revoke: delete the grant, or demote the user to USER */
+async function applyRevoke(item: AccessReviewItem): Promise<void> {
+  if (item.kind === 'instance_access') {
+    // Already gone (revoked elsewhere) is fine
+    await prisma.instanceAccess.deleteMany({ where: { id: item.subjectId } })
+    return
+  }
+
+  const user = await prisma.user.findUnique({ where: { id: item.subjectId }, select: { role: true } })
+  if (!user || user.role === 'USER') return
+  if (user.role === 'SYSTEM_ADMIN') {
+    const admins = await prisma.user.count({ where: { role: 'SYSTEM_ADMIN', status: 'ACTIVE' } })
+    if (admins <= 1) throw new Error('Cannot demote the last active system administrator')
+  }
+  await prisma.user.update({
+    where: { id: item.subjectId },
+    data: { role: 'USER', roleExpiresAt: null, baseRole: null, roleExpiryNotifiedAt: null },
+  })
+}
+
+/**
+ * Record a reviewer's decision. Throws with a user-facing message when the
+ * item can't be decided (campaign closed, already decided, own role).
+ */
+export async function decideAccessReviewItem(
+  item: AccessReviewItem & { campaign: AccessReviewCampaign },
+  decision: 'KEEP' | 'REVOKE',
+  reviewerId: string,
+  comment: string | undefined,
+): Promise<AccessReviewItem> {
+  if (item.campaign.status !== 'OPEN') throw new Error('Campaign is closed')
+  if (item.kind === 'role' && item.subjectId === reviewerId) {
+    throw new Error('You cannot review your own role')
+  }
+
+  // Claim atomically so two reviewers can't both decide
+  const claimed = await prisma.accessReviewItem.updateMany({
+    where: { id: item.id, decision: 'PENDING' },
+    data: { decision, reviewerId, comment, decidedAt: new Date() },
+  })
+  if (claimed.count === 0) throw new Error('Item has already been reviewed')
+
+  if (decision === 'REVOKE') {
+    try {
+      await applyRevoke(item)
+    } catch (err) {
+      // Put it back so it can be retried
+      await prisma.accessReviewItem.update({
+        where: { id: item.id },
+        data: { decision: 'PENDING', reviewerId: null, comment: null, decidedAt: null },
+      })
+      throw err
+    }
+  }
+
+  return prisma.accessReviewItem.findUniqueOrThrow({ where: { id: item.id } })
+}
+
+// ─── Deadline handling ──────────────────────────────────────────────
+
+/** Close a campaign, auto-revoking still-pending grants if configured */
+export async function closeAccessReviewCampaign(
+  campaign: AccessReviewCampaign,
+  closedById: string | null,
+): Promise<{ autoRevoked: number; unreviewed: number }> {
+  const claimed = await prisma.accessReviewCampaign.updateMany({
+    where: { id: campaign.id, status: 'OPEN' },
+    data: { status: 'CLOSED', closedAt: new Date() },
+  })
+  if (claimed.count === 0) return { autoRevoked: 0, unreviewed: 0 }
+
+  const pending = await prisma.accessReviewItem.findMany({
+    where: { campaignId: campaign.id, decision: 'PENDING' },
+  })
+
+  let autoRevoked = 0
+  if (campaign.autoRevoke) {
+    for (const item of pending.filter((i) => i.kind === 'instance_access')) {
+      await applyRevoke(item)
+      await prisma.accessReviewItem.update({
+        where: { id: item.id },
+        data: { decision: 'AUTO_REVOKED', decidedAt: new Date() },
+      })
+      autoRevoked++
+    }
+  }
+
+  const details = { name: campaign.name, autoRevoked, unreviewed: pending.length - autoRevoked, manual: !!closedById }
+  // Audit entries need a user; scheduler-created campaigns closing on their own only get logged
+  const actorId = closedById ?? campaign.createdById
+  if (actorId) {
+    auditLog({
+      userId: actorId,
+      action: 'ACCESS_REVIEW_CLOSE',
+      resource: 'access_review',
+      resourceId: campaign.id,
+      details,
+      ipAddress: 'system',
+      result: 'SUCCESS',
+    })
+  } else {
+    log.info('Access review closed', { campaignId: campaign.id, ...details })
+  }
+
+  return { autoRevoked, unreviewed: pending.length - autoRevoked }
+}
+
+/** Close every open campaign past its deadline. Called lazily and by the scheduler. */
+export async function closeOverdueAccessReviews(): Promise<number> {
+  const overdue = await prisma.accessReviewCampaign.findMany({
+    where: { status: 'OPEN', dueAt: { lt: new Date() } },
+  })
+  for (const c of overdue) {
+    await closeAccessReviewCampaign(c, null).catch((err) =>
+      log.error('Failed to close access review', { campaignId: c.id, err }),
+    )
+  }
+  return overdue.length
+}
+
+/** Daily reminder to each department with pending items, in the last days before the deadline */
+async function sendReminders(): Promise<void> {
+  const now = Date.now()
+  const campaigns = await prisma.accessReviewCampaign.findMany({
+    where: {
+      status: 'OPEN',
+      dueAt: { lt: new Date(now + REMIND_WITHIN_MS) },
+      OR: [{ lastRemindedAt: null }, { lastRemindedAt: { lt: new Date(now - REMIND_EVERY_MS) } }],
+    },
+  })
+
+  for (const c of campaigns) {
+    const pending = await prisma.accessReviewItem.groupBy({
+      by: ['departmentId'],
+      where: { campaignId: c.id, decision: 'PENDING', departmentId: { not: null } },
+      _count: { id: true },
+    })
+    for (const p of pending) {
+      await notifyDepartment(p.departmentId!, {
+        title: `Access review "${c.name}" due ${c.dueAt.toISOString().slice(0, 10)}`,
+        text:
+          `${p._count.id} instance access grant(s) for your department still need review.` +
+          (c.autoRevoke ? ' Unreviewed grants are revoked at the deadline.' : ''),
+        source: `access_review:${c.id}`,
+      })
+    }
+    await prisma.accessReviewCampaign.update({ where: { id: c.id }, data: { lastRemindedAt: new Date() } })
+  }
+}
+
+/** Open this quarter's campaign if quarterly reviews are on and none exists yet */
+async function ensureQuarterlyCampaign(): Promise<void> {
+  if (process.env.ACCESS_REVIEW_QUARTERLY !== 'true') return
+  const now = new Date()
+  const quarterStart = new Date(now.getFullYear(), Math.floor(now.getMonth() / 3) * 3, 1)
+  const existing = await prisma.accessReviewCampaign.count({ where: { createdAt: { gte: quarterStart } } })
+  if (existing > 0) return
+
+  const quarter = Math.floor(now.getMonth() / 3) + 1
+  const campaign = await createAccessReviewCampaign({
+    name: `${now.getFullYear()} Q${quarter} access review`,
+    dueAt: new Date(now.getTime() + reviewDays() * 86400_000),
+    autoRevoke: true,
+    includeRoles: true,
+    createdById: null,
+  })
+  log.info('Opened quarterly access review', { campaignId: campaign.id, items: campaign.itemCount })
+}
+
+async function tick(): Promise<void> {
+  if (globalForAccessReviews.accessReviewTickRunning) return
+  globalForAccessReviews.accessReviewTickRunning = true
+  try {
+    await closeOverdueAccessReviews()
+    await sendReminders()
+    await ensureQuarterlyCampaign()
+  } catch (err) {
+    log.error('Access review tick failed', { err })
+  } finally {
+    globalForAccessReviews.accessReviewTickRunning = false
+  }
+}
+
+/** Start the hourly deadline / reminder job (idempotent across hot reloads) */
+export function startAccessReviewScheduler(): void {
+  if (globalForAccessReviews.accessReviewTimer) return
+  void tick()
+  globalForAccessReviews.accessReviewTimer = setInterval(() => void tick(), SCHEDULER_INTERVAL_MS)
+}
+
+// ─── Responses ──────────────────────────────────────────────────────
+
+export async function getAccessReviewProgress(
+  campaignIds: string[],
+  departmentId?: string,
+): Promise<Map<string, AccessReviewProgress>> {
+  const grouped = await prisma.accessReviewItem.groupBy({
+    by: ['campaignId', 'decision'],
+    where: { campaignId: { in: campaignIds }, ...(departmentId ? { departmentId } : {}) },
+    _count: { id: true },
+  })
+  const progress = new Map<string, AccessReviewProgress>()
+  for (const id of campaignIds) progress.set(id, { total: 0, pending: 0, kept: 0, revoked: 0 })
+  for (const g of grouped) {
+    const p = progress.get(g.campaignId)!
+    p.total += g._count.id
+    if (g.decision === 'PENDING') p.pending += g._count.id
+    else if (g.decision === 'KEEP') p.kept += g._count.id
+    else p.revoked += g._count.id
+  }
+  return progress
+}
+
+export function toCampaignResponse(
+  c: AccessReviewCampaign & { createdBy?: { name: string } | null },
+  progress: AccessReviewProgress,
+): AccessReviewCampaignResponse {
+  return {
+    id: c.id,
+    name: c.name,
+    status: c.status,
+    dueAt: c.dueAt.toISOString(),
+    autoRevoke: c.autoRevoke,
+    createdByName: c.createdBy?.name ?? null,
+    closedAt: c.closedAt?.toISOString() ?? null,
+    createdAt: c.createdAt.toISOString(),
+    progress,
+  }
+}
+
+export function toItemResponse(i: AccessReviewItem & { reviewer?: { name: string } | null }): AccessReviewItemResponse {
+  return {
+    id: i.id,
+    kind: i.kind as AccessReviewItemResponse['kind'],
+    subjectId: i.subjectId,
+    departmentId: i.departmentId,
+    summary: i.summary as Record<string, unknown>,
+    decision: i.decision,
+    reviewerName: i.reviewer?.name ?? null,
+    comment: i.comment,
+    decidedAt: i.decidedAt?.toISOString() ?? null,
+  }
+}
@@ -0,0 +1,133 @@
+import { prisma } from '@/lib/db'
+import { percentile } from '@/lib/gateway/latency'
+import { createLogger } from '@/lib/logger'
+import type { AgentCanary, CanaryArm } from '@/generated/prisma'
+import type { CanaryArmStats, CanaryReport } from '@/types/agent'
+
+// Canary routing: while a canary split is enabled, `percent`% of a
+// department's new sessions with the control agent are served by the canary
+// agent (same instance) instead. The session keeps the agent the user picked
+// as agentId and records its arm; routedAgentId is the agent actually serving
+// it, under its own gateway session key. Sessions of both arms record the
+// outcome and timing of each run (CanaryRun), which together with the support
+// tickets filed on them make up the comparison report.
+
+const log = createLogger('agents:canary')
+
+/** Gateway session key of a user's session with an agent */
+export function chatSessionKey(userId: string, agentId: string, routedAgentId?: string | null): string {
+  return routedAgentId ? `agent:${routedAgentId}:tc:${userId}:canary` : `agent:${agentId}:tc:${userId}`
+}
+
+export interface CanaryAssignment {
+  canaryId: string
+  canaryArm: CanaryArm
+  routedAgentId: string | null
+}
+
+/** Arm for a new session, or null when no enabled split covers it */
+export async function assignCanaryArm(
+  departmentId: string | null,
+  instanceId: string,
+  agentId: string,
+): Promise<CanaryAssignment | null> {
+  if (!departmentId) return null
+  const canary = await prisma.agentCanary.findFirst({
+    where: { departmentId, instanceId, controlAgentId: agentId, enabled: true },
+    orderBy: { createdAt: 'desc' },
+  })
+  if (!canary) return null
+  return Math.random() * 100 < canary.percent
+    ? { canaryId: canary.id, canaryArm: 'CANARY', routedAgentId: canary.canaryAgentId }
+    : { canaryId: canary.id, canaryArm: 'CONTROL', routedAgentId: null }
+}
+
+export type CanaryRunOutcome = 'done' | 'aborted' | 'error'
+
+/** Record a finished run of a session under a split (fire-and-forget) */
+export function recordCanaryRun(
+  session: { id: string; canaryId: string | null; canaryArm: CanaryArm | null },
+  run: { outcome: CanaryRunOutcome; firstTokenMs: number | null; durationMs: number },
+): void {
+  if (!session.canaryId || !session.canaryArm) return
+  prisma.canaryRun
+    .create({ data: { canaryId: session.canaryId, chatSessionId: session.id, arm: session.canaryArm, ...run } })
+    .catch((err) => log.warn('Could not record canary run', { chatSessionId: session.id, error: (err as Error).message }))
+}
+
+// ─── Report ─────────────────────────────────────────────────────────
+
+const rate = (n: number, total: number) => (total > 0 ? Math.round((n / total) * 1000) / 1000 : 0)
+
+function summarize(values: number[]): { avg: number; p50: number; p95: number } {
+  const sorted = [...values].sort((a, b) => a - b)
+  const avg = sorted.length > 0 ? Math.round(sorted.reduce((sum, v) => sum + v, 0) / sorted.length) : 0
+  return { avg, p50: percentile(sorted, 0.5), p95: percentile(sorted, 0.95) }
+}
+
+export interface ReportWindow {
+  from: Date
+  to: Date | null
+}
+
+async function armStats(canary: AgentCanary, arm: CanaryArm, window: ReportWindow | null): Promise<CanaryArmStats> {
+  const createdAt = window ? { gte: window.from, ...(window.to ? { lte: window.to } : {}) } : undefined
+  const [sessions, runs, reports] = await Promise.all([
+    prisma.chatSession.count({ where: { canaryId: canary.id, canaryArm: arm, createdAt } }),
+    prisma.canaryRun.findMany({
+      where: { canaryId: canary.id, arm, createdAt },
+      select: { outcome: true, firstTokenMs: true, durationMs: true },
+    }),
+    prisma.supportTicket.count({ where: { chatSession: { canaryId: canary.id, canaryArm: arm }, createdAt } }),
+  ])
+
+  return {
+    agentId: arm === 'CANARY' ? canary.canaryAgentId : canary.controlAgentId,
+    sessions,
+    runs: runs.length,
+    abortRate: rate(runs.filter((r) => r.outcome === 'aborted').length, runs.length),
+    errorRate: rate(runs.filter((r) => r.outcome === 'error').length, runs.length),
+    firstTokenMs: summarize(runs.flatMap((r) => (r.firstTokenMs === null ? [] : [r.firstTokenMs]))),
+    durationMs: summarize(runs.filter((r) => r.outcome === 'done').map((r) => r.durationMs)),
+    reports,
+    reportsPerSession: rate(reports, sessions),
+  }
+}
+
+/**
+ * Compare the two arms of a split, over all sessions assigned under it or
+ * over the sessions, runs and reports of a time window
+ */
+export async function canaryReport(canary: AgentCanary, window: ReportWindow | null = null): Promise<CanaryReport> {
+  const [control, canaryArm] = await Promise.all([
+    armStats(canary, 'CONTROL', window),
+    armStats(canary, 'CANARY', window),
+  ])
+  return {
+    canaryId: canary.id,
+    percent: canary.percent,
+    since: (window?.from ?? canary.createdAt).toISOString(),
+    control,
+    canary: canaryArm,
+  }
+}
+
+export function toCanaryResponse(
+  c: AgentCanary & { department?: { name: string } | null; instance?: { name: string } | null },
+) {
+  return {
+    id: c.id,
+    name: c.name,
+    departmentId: c.departmentId,
+    departmentName: c.department?.name ?? null,
+    instanceId: c.instanceId,
+    instanceName: c.instance?.name ?? null,
+    controlAgentId: c.controlAgentId,
+    canaryAgentId: c.canaryAgentId,
+    percent: c.percent,
+    enabled: c.enabled,
+    createdById: c.createdById,
+    createdAt: c.createdAt.toISOString(),
+    updatedAt: c.updatedAt.toISOString(),
+  }
+}
@@ -0,0 +1,307 @@
+import { createHash } from 'crypto'
+import { prisma } from '@/lib/db'
+import { registry, ensureRegistryInitialized } from '@/lib/gateway/registry'
+import { runThrowawayPrompt } from '@/lib/chat/run'
+import { completeWithResource } from '@/lib/resources/completion'
+import { captureAgentVersion } from '@/lib/agents/experiments'
+import { createLogger } from '@/lib/logger'
+import type { EvalRun, EvalSet, Prisma, Resource } from '@/generated/prisma'
+import type { AgentVersion, EvalCase, EvalComparison } from '@/types/agent'
+
+// Golden-set evaluations: a set of prompts, each with what a good reply must
+// satisfy, run against an agent in throwaway sessions. Replies are scored
+//
+//   exact — equal to the expected text (surrounding whitespace ignored)
+//   regex — match the expected pattern (JS RegExp source)
+//   judge — the set's judge resource (a MODEL resource) decides whether the
+//           reply meets the expected criteria
+//
+// A run records the agent's version when it started, so runs of one set can
+// be compared case by case across versions: which cases regressed, which
+// were fixed. Case keys tie results together; they default to a hash of the
+// prompt, so re-uploading a set keeps the history of unchanged cases.
+
+const log = createLogger('agents:evals')
+
+const MAX_CONCURRENT = 3
+const CASE_TIMEOUT_MS = 5 * 60_000
+const JUDGE_MAX_TOKENS = 300
+
+const globalForEvals = globalThis as unknown as {
+  evalRunsInFlight?: Set<string>
+}
+
+const inFlight = (globalForEvals.evalRunsInFlight ??= new Set<string>())
+
+/** Fill in missing case keys; a prompt that repeats gets a numbered key */
+export function normalizeCases(cases: (Omit<EvalCase, 'key'> & { key?: string })[]): EvalCase[] {
+  const used = new Set(cases.flatMap((c) => (c.key ? [c.key] : [])))
+  return cases.map((c) => {
+    if (c.key) return { ...c, key: c.key }
+    const base = createHash('sha256').update(c.prompt).digest('hex').slice(0, 12)
+    let key = base
+    for (let n = 2; used.has(key); n++) key = `${base}-${n}`
+    used.add(key)
+    return { ...c, key }
+  })
+}
+
+/** Returns an error message when judge cases have no usable judge resource */
+export async function checkJudge(cases: EvalCase[], judgeResourceId: string | null): Promise<string | null> {
+  if (judgeResourceId) {
+    const resource = await prisma.resource.findUnique({ where: { id: judgeResourceId }, select: { type: true } })
+    if (!resource) return 'Judge resource not found'
+    if (resource.type !== 'MODEL') return 'The judge resource must be a model resource'
+  } else if (cases.some((c) => c.scorer === 'judge')) {
+    return 'Cases scored by a judge need a judge resource'
+  }
+  return null
+}
+
+// ─── Scoring ────────────────────────────────────────────────────────
+
+interface Judge {
+  resource: Resource
+  model: string | null
+}
+
+interface Score {
+  passed: boolean
+  reason: string | null
+}
+
+function judgePrompt(c: EvalCase, reply: string): string {
+  return [
+    'You are grading an AI assistant\'s reply against criteria.',
+    'Reply with JSON only: {"pass": true or false, "reason": "one sentence"}.',
+    '',
+    'User message:',
+    c.prompt,
+    '',
+    'Criteria the reply must meet:',
+    c.expected,
+    '',
+    'Assistant reply:',
+    reply,
+  ].join('\n')
+}
+
+function parseVerdict(text: string): Score {
+  const fenced = text.match(/```(?:json)?\s*([\s\S]*?)```/)
+  const raw = fenced ? fenced[1] : text.slice(text.indexOf('{'), text.lastIndexOf('}') + 1)
+  try {
+    const verdict = JSON.parse(raw) as { pass?: unknown; reason?: unknown }
+    if (typeof verdict.pass !== 'boolean') throw new Error()
+    return { passed: verdict.pass, reason: typeof verdict.reason === 'string' ? verdict.reason : null }
+  } catch {
+    return { passed: false, reason: `Unreadable judge verdict: ${text.slice(0, 200)}` }
+  }
+}
+
+async function scoreReply(c: EvalCase, reply: string, judge: Judge | null): Promise<Score> {
+  switch (c.scorer) {
+    case 'exact':
+      return reply.trim() === c.expected.trim()
+        ? { passed: true, reason: null }
+        : { passed: false, reason: 'Reply differs from the expected text' }
+    case 'regex':
+      return new RegExp(c.expected).test(reply)
+        ? { passed: true, reason: null }
+        : { passed: false, reason: `Reply does not match /${c.expected}/` }
+    case 'judge': {
+      if (!judge) return { passed: false, reason: 'The set has no judge resource' }
+      const verdict = await completeWithResource(judge.resource, judgePrompt(c, reply), {
+        model: judge.model,
+        maxTokens: JUDGE_MAX_TOKENS,
+      })
+      return parseVerdict(verdict)
+    }
+  }
+}
+
+// ─── Runs ───────────────────────────────────────────────────────────
+
+async function runCase(
+  run: EvalRun,
+  c: EvalCase,
+  judge: Judge | null,
+): Promise<Prisma.EvalResultCreateManyInput> {
+  const base = { runId: run.id, caseKey: c.key, prompt: c.prompt, scorer: c.scorer }
+  const client = registry.getClient(run.instanceId)
+  const adapter = registry.getAdapter(run.instanceId)
+  if (!client || !adapter || !client.isConnected()) {
+    return { ...base, passed: false, reason: 'Instance not connected' }
+  }
+
+  let result: { text: string; durationMs: number }
+  try {
+    result = await runThrowawayPrompt(client, adapter, run.agentId, c.prompt, {
+      timeoutMs: CASE_TIMEOUT_MS,
+      tag: 'eval',
+    })
+  } catch (err) {
+    return { ...base, passed: false, reason: (err as Error).message }
+  }
+
+  const { text: reply, durationMs } = result
+  try {
+    return { ...base, reply, durationMs, ...(await scoreReply(c, reply, judge)) }
+  } catch (err) {
+    return { ...base, reply, durationMs, passed: false, reason: `Scoring failed: ${(err as Error).message}` }
+  }
+}
+
+async function executeEvalRun(runId: string): Promise<void> {
+  const run = await prisma.evalRun.findUniqueOrThrow({
+    where: { id: runId },
+    include: { set: { include: { judgeResource: true } } },
+  })
+  const cases = run.set.cases as unknown as EvalCase[]
+  const judge = run.set.judgeResource ? { resource: run.set.judgeResource, model: run.set.judgeModel } : null
+
+  await ensureRegistryInitialized()
+  const agentVersion = await captureAgentVersion(run.instanceId, run.agentId)
+  await prisma.evalRun.update({
+    where: { id: runId },
+    data: {
+      status: 'RUNNING',
+      startedAt: new Date(),
+      caseCount: cases.length,
+      agentVersion: agentVersion as unknown as Prisma.InputJsonValue,
+    },
+  })
+
+  for (let i = 0; i < cases.length; i += MAX_CONCURRENT) {
+    const results = await Promise.all(cases.slice(i, i + MAX_CONCURRENT).map((c) => runCase(run, c, judge)))
+    const passed = results.filter((r) => r.passed).length
+    await prisma.$transaction([
+      prisma.evalResult.createMany({ data: results }),
+      prisma.evalRun.update({
+        where: { id: runId },
+        data: { passed: { increment: passed }, failed: { increment: results.length - passed } },
+      }),
+    ])
+  }
+
+  await prisma.evalRun.update({ where: { id: runId }, data: { status: 'COMPLETED', finishedAt: new Date() } })
+  log.info('Eval run finished', { runId, setId: run.setId, agentId: run.agentId, cases: cases.length })
+}
+
+/** Run a queued eval run in the background */
+export function startEvalRun(runId: string): void {
+  if (inFlight.has(runId)) return
+  inFlight.add(runId)
+  executeEvalRun(runId)
+    .catch(async (err) => {
+      log.error('Eval run failed', { runId, error: (err as Error).message })
+      await prisma.evalRun
+        .update({ where: { id: runId }, data: { status: 'FAILED', error: (err as Error).message, finishedAt: new Date() } })
+        .catch(() => {})
+    })
+    .finally(() => inFlight.delete(runId))
+}
+
+/** Whether the set has a run in progress for the agent */
+export async function hasActiveEvalRun(setId: string, instanceId: string, agentId: string): Promise<boolean> {
+  const active = await prisma.evalRun.count({
+    where: { setId, instanceId, agentId, status: { in: ['QUEUED', 'RUNNING'] } },
+  })
+  return active > 0
+}
+
+/** Runs the previous process was working on cannot be picked up again. Called once at startup. */
+export async function failInterruptedEvalRuns(): Promise<void> {
+  const { count } = await prisma.evalRun.updateMany({
+    where: { status: { in: ['QUEUED', 'RUNNING'] }, id: { notIn: [...inFlight] } },
+    data: { status: 'FAILED', error: 'Interrupted by a server restart', finishedAt: new Date() },
+  })
+  if (count > 0) log.warn('Marked interrupted eval runs as failed', { count })
+}
+
+// ─── Comparison ─────────────────────────────────────────────────────
+
+/** The last completed run of the same set against the same agent before this one */
+export async function findBaselineRun(run: EvalRun): Promise<EvalRun | null> {
+  return prisma.evalRun.findFirst({
+    where: {
+      setId: run.setId,
+      instanceId: run.instanceId,
+      agentId: run.agentId,
+      status: 'COMPLETED',
+      createdAt: { lt: run.createdAt },
+    },
+    orderBy: { createdAt: 'desc' },
+  })
+}
+
+export async function compareEvalRuns(run: EvalRun, baseline: EvalRun): Promise<EvalComparison> {
+  const [current, previous] = await Promise.all(
+    [run.id, baseline.id].map((runId) =>
+      prisma.evalResult.findMany({ where: { runId }, select: { caseKey: true, passed: true } }),
+    ),
+  )
+  const before = new Map(previous.map((r) => [r.caseKey, r.passed]))
+
+  const regressed: string[] = []
+  const fixed: string[] = []
+  const added: string[] = []
+  for (const { caseKey, passed } of current) {
+    const was = before.get(caseKey)
+    if (was === undefined) added.push(caseKey)
+    else if (was && !passed) regressed.push(caseKey)
+    else if (!was && passed) fixed.push(caseKey)
+  }
+
+  const passRate = (r: EvalRun) => (r.caseCount ? r.passed / r.caseCount : 0)
+  const version = (r: EvalRun) => (r.agentVersion as AgentVersion | null)?.hash ?? null
+  return {
+    baselineRunId: baseline.id,
+    versionChanged: version(run) !== version(baseline),
+    regressed,
+    fixed,
+    added,
+    passRateDelta: Math.round((passRate(run) - passRate(baseline)) * 1000) / 1000,
+  }
+}
+
+// ─── Responses ──────────────────────────────────────────────────────
+
+export function toEvalSetResponse(
+  s: EvalSet & { createdBy?: { name: string } | null; judgeResource?: { name: string } | null },
+) {
+  const cases = s.cases as unknown as EvalCase[]
+  return {
+    id: s.id,
+    name: s.name,
+    description: s.description,
+    caseCount: cases.length,
+    cases,
+    judgeResourceId: s.judgeResourceId,
+    judgeResourceName: s.judgeResource?.name ?? null,
+    judgeModel: s.judgeModel,
+    createdById: s.createdById,
+    createdByName: s.createdBy?.name ?? null,
+    createdAt: s.createdAt.toISOString(),
+    updatedAt: s.updatedAt.toISOString(),
+  }
+}
+
+export function toEvalRunResponse(r: EvalRun & { createdBy?: { name: string } | null }) {
+  return {
+    id: r.id,
+    setId: r.setId,
+    instanceId: r.instanceId,
+    agentId: r.agentId,
+    status: r.status,
+    agentVersion: (r.agentVersion ?? null) as AgentVersion | null,
+    caseCount: r.caseCount,
+    passed: r.passed,
+    failed: r.failed,
+    error: r.error,
+    startedAt: r.startedAt?.toISOString() ?? null,
+    finishedAt: r.finishedAt?.toISOString() ?? null,
+    createdById: r.createdById,
+    createdByName: r.createdBy?.name ?? null,
+    createdAt: r.createdAt.toISOString(),
+  }
+}
@@ -0,0 +1,178 @@
+import { createHash } from 'crypto'
+import { prisma } from '@/lib/db'
+import { registry, ensureRegistryInitialized } from '@/lib/gateway/registry'
+import { dockerManager } from '@/lib/docker/manager'
+import {
+  extractAgentsConfig,
+  resolveWorkspacePath,
+  containerWorkspacePath,
+  listAgentWorkspaceFiles,
+  sanitizeAgentEntry,
+} from '@/lib/agents/helpers'
+import { canaryReport } from '@/lib/agents/canary'
+import { createLogger } from '@/lib/logger'
+import type { Experiment, Prisma } from '@/generated/prisma'
+import type { AgentVersion, ExperimentResults } from '@/types/agent'
+
+// Experiments: a change to an agent, measured. An experiment runs on a
+// canary split (lib/agents/canary) — the control agent as it is, the canary
+// agent with the change. Starting one records both agents' versions (config
+// entry plus a hash of each prompt file) and enables the split; stopping it
+// records the versions again and freezes the comparison over the window in
+// between. An agent edited mid-run shows up in `changedDuringRun`, since its
+// numbers then mix two versions.
+
+const log = createLogger('agents:experiments')
+
+// Prompt files larger than this are listed without a hash
+const MAX_PROMPT_FILE_BYTES = 512 * 1024
+
+const sha256 = (data: string | Buffer) => createHash('sha256').update(data).digest('hex')
+
+/** Current version of an agent; config and files are empty when the instance cannot be reached */
+export async function captureAgentVersion(instanceId: string, agentId: string): Promise<AgentVersion> {
+  let config: Record<string, unknown> | null = null
+  const promptFiles: AgentVersion['promptFiles'] = []
+
+  try {
+    await ensureRegistryInitialized()
+    const client = registry.getClient(instanceId)
+    const adapter = registry.getAdapter(instanceId)
+    if (client && adapter) {
+      const { defaults, list } = extractAgentsConfig((await adapter.getConfig(client)).config)
+      const entry = list.find((a) => a.id === agentId)
+      if (entry) {
+        config = sanitizeAgentEntry(entry)
+        const instance = await prisma.instance.findUnique({ where: { id: instanceId }, select: { containerId: true } })
+        if (instance?.containerId) {
+          const workspace = resolveWorkspacePath(entry, defaults)
+          const files = await listAgentWorkspaceFiles(instance.containerId, workspace)
+          for (const f of files.filter((f) => f.type === 'file' && f.name.endsWith('.md'))) {
+            const content =
+              f.size <= MAX_PROMPT_FILE_BYTES
+                ? await dockerManager
+                    .downloadFileFromContainer(instance.containerId, `${containerWorkspacePath(workspace)}/${f.path}`)
+                    .catch(() => null)
+                : null
+            promptFiles.push({ name: f.name, sha256: content ? sha256(content) : '', size: f.size })
+          }
+          promptFiles.sort((a, b) => a.name.localeCompare(b.name))
+        }
+      }
+    }
+  } catch (err) {
+    log.warn('Could not capture agent version', { instanceId, agentId, error: (err as Error).message })
+  }
+
+  return {
+    agentId,
+    hash: sha256(JSON.stringify({ config, promptFiles })),
+    config,
+    promptFiles,
+    capturedAt: new Date().toISOString(),
+  }
+}
+
+async function captureVersions(exp: Experiment): Promise<AgentVersion[]> {
+  return Promise.all([
+    captureAgentVersion(exp.instanceId, exp.controlAgentId),
+    captureAgentVersion(exp.instanceId, exp.canaryAgentId),
+  ])
+}
+
+/**
+ * The comparison over the experiment's window: frozen once stopped, computed
+ * live while running (against the current agent versions)
+ */
+export async function experimentResults(exp: Experiment): Promise<ExperimentResults | null> {
+  if (exp.results) return exp.results as unknown as ExperimentResults
+  if (exp.status !== 'RUNNING' || !exp.startedAt) return null
+  return computeResults(exp, await captureVersions(exp), null)
+}
+
+async function computeResults(
+  exp: Experiment,
+  endVersions: AgentVersion[],
+  endedAt: Date | null,
+): Promise<ExperimentResults | null> {
+  const canary = exp.canaryId ? await prisma.agentCanary.findUnique({ where: { id: exp.canaryId } }) : null
+  if (!canary || !exp.startedAt) return null
+
+  const report = await canaryReport(canary, { from: exp.startedAt, to: endedAt })
+  const start = (exp.startVersions ?? []) as unknown as AgentVersion[]
+  const changedDuringRun = endVersions
+    .filter((v) => start.find((s) => s.agentId === v.agentId)?.hash !== v.hash)
+    .map((v) => v.agentId)
+
+  const diff = (a: number, b: number) => Math.round((a - b) * 1000) / 1000
+  return {
+    report,
+    deltas: {
+      abortRate: diff(report.canary.abortRate, report.control.abortRate),
+      errorRate: diff(report.canary.errorRate, report.control.errorRate),
+      firstTokenP50Ms: report.canary.firstTokenMs.p50 - report.control.firstTokenMs.p50,
+      durationP50Ms: report.canary.durationMs.p50 - report.control.durationMs.p50,
+      reportsPerSession: diff(report.canary.reportsPerSession, report.control.reportsPerSession),
+    },
+    changedDuringRun,
+    computedAt: new Date().toISOString(),
+  }
+}
+
+/** Record the agents' versions, enable the split and open the window */
+export async function startExperiment(exp: Experiment): Promise<Experiment> {
+  const startVersions = await captureVersions(exp)
+  const [updated] = await prisma.$transaction([
+    prisma.experiment.update({
+      where: { id: exp.id },
+      data: {
+        status: 'RUNNING',
+        startedAt: new Date(),
+        startVersions: startVersions as unknown as Prisma.InputJsonValue,
+      },
+    }),
+    prisma.agentCanary.update({ where: { id: exp.canaryId! }, data: { enabled: true } }),
+  ])
+  return updated
+}
+
+/** Close the window, freeze the results and (unless kept) disable the split */
+export async function stopExperiment(exp: Experiment, keepCanary: boolean): Promise<Experiment> {
+  const endedAt = new Date()
+  const endVersions = await captureVersions(exp)
+  const results = await computeResults(exp, endVersions, endedAt)
+
+  const updated = await prisma.experiment.update({
+    where: { id: exp.id },
+    data: {
+      status: 'STOPPED',
+      endedAt,
+      endVersions: endVersions as unknown as Prisma.InputJsonValue,
+      ...(results ? { results: results as unknown as Prisma.InputJsonValue } : {}),
+    },
+  })
+  if (!keepCanary && exp.canaryId) {
+    await prisma.agentCanary.updateMany({ where: { id: exp.canaryId }, data: { enabled: false } })
+  }
+  return updated
+}
+
+export function toExperimentResponse(e: Experiment & { createdBy?: { name: string } | null }) {
+  return {
+    id: e.id,
+    name: e.name,
+    hypothesis: e.hypothesis,
+    canaryId: e.canaryId,
+    instanceId: e.instanceId,
+    controlAgentId: e.controlAgentId,
+    canaryAgentId: e.canaryAgentId,
+    status: e.status,
+    startVersions: (e.startVersions ?? null) as AgentVersion[] | null,
+    endVersions: (e.endVersions ?? null) as AgentVersion[] | null,
+    startedAt: e.startedAt?.toISOString() ?? null,
+    endedAt: e.endedAt?.toISOString() ?? null,
+    createdById: e.createdById,
+    createdByName: e.createdBy?.name ?? null,
+    createdAt: e.createdAt.toISOString(),
+  }
+}
@@ -0,0 +1,96 @@
+import { prisma } from '@/lib/db'
+import { getBranding } from '@/lib/branding'
+import type { AgentPreamble, AgentPreambleVersion } from '@/generated/prisma'
+
+// Managed preambles: text TeamClaw prepends (server-side, in
+// <teamclaw-context> tags) to every message sent to an agent, so governance
+// text and context no longer depend on editing each agent's workspace by
+// hand. The template may use these variables:
+
+export const PREAMBLE_VARIABLES = {
+  company: 'Product / company name from branding',
+  department: 'Name of the user\'s department',
+  department_description: 'Description of the user\'s department',
+  user_name: 'The user\'s name',
+  user_email: 'The user\'s email',
+  user_role: 'The user\'s TeamClaw role',
+  agent: 'The agent ID',
+  date: 'Today\'s date (YYYY-MM-DD, UTC)',
+} as const
+
+type PreambleVariable = keyof typeof PREAMBLE_VARIABLES
+
+const VARIABLE_RE = /\{\{\s*(\w+)\s*\}\}/g
+
+/** Placeholders in a template that are not known variables */
+export function unknownPreambleVariables(template: string): string[] {
+  const names = [...template.matchAll(VARIABLE_RE)].map((m) => m[1])
+  return [...new Set(names.filter((n) => !(n in PREAMBLE_VARIABLES)))]
+}
+
+export function renderPreamble(template: string, values: Record<PreambleVariable, string>): string {
+  return template.replace(VARIABLE_RE, (match, name: string) =>
+    name in values ? values[name as PreambleVariable] : match,
+  )
+}
+
+export interface PreambleUser {
+  name: string
+  email: string
+  role: string
+  department: { name: string; description: string | null } | null
+}
+
+/** The agent's rendered preamble for this user, or null when it has none (or it is off) */
+export async function buildPreamble(instanceId: string, agentId: string, user: PreambleUser): Promise<string | null> {
+  const preamble = await prisma.agentPreamble.findUnique({
+    where: { instanceId_agentId: { instanceId, agentId } },
+    select: { template: true, enabled: true },
+  })
+  if (!preamble?.enabled || !preamble.template.trim()) return null
+
+  const { productName } = await getBranding()
+  return renderPreamble(preamble.template, {
+    company: productName,
+    department: user.department?.name ?? '',
+    department_description: user.department?.description ?? '',
+    user_name: user.name,
+    user_email: user.email,
+    user_role: user.role,
+    agent: agentId,
+    date: new Date().toISOString().slice(0, 10),
+  }).trim()
+}
+
+/** Save a new version of the agent's preamble */
+export async function savePreamble(
+  instanceId: string,
+  agentId: string,
+  data: { template: string; enabled: boolean },
+  userId: string,
+): Promise<AgentPreamble> {
+  return prisma.$transaction(async (tx) => {
+    const existing = await tx.agentPreamble.findUnique({ where: { instanceId_agentId: { instanceId, agentId } } })
+    const preamble = existing
+      ? await tx.agentPreamble.update({
+          where: { id: existing.id },
+          data: { ...data, version: existing.version + 1 },
+        })
+      : await tx.agentPreamble.create({ data: { instanceId, agentId, ...data } })
+    await tx.agentPreambleVersion.create({
+      data: { preambleId: preamble.id, version: preamble.version, ...data, createdById: userId },
+    })
+    return preamble
+  })
+}
+
+export function toPreambleVersionResponse(v: AgentPreambleVersion & { createdBy?: { name: string } | null }) {
+  return {
+    version: v.version,
+    template: v.template,
+    enabled: v.enabled,
+    createdById: v.createdById,
+    createdByName: v.createdBy?.name ?? null,
+    createdAt: v.createdAt.toISOString(),
+  }
+}
@@ -0,0 +1,102 @@
+import { prisma } from '@/lib/db'
+import { registry, ensureRegistryInitialized } from '@/lib/gateway/registry'
+import { extractAgentsConfig, autoRegisterAgents } from './helpers'
+import { createLogger } from '@/lib/logger'
+import type { AgentSyncResult } from '@/types/agent'
+
+const SYNC_INTERVAL_MS = 10 * 60_000
+const MAX_CONCURRENT = 5
+
+const log = createLogger('agents:sync')
+
+const globalForAgentSync = globalThis as unknown as {
+  agentSyncTimer?: ReturnType<typeof setInterval> | null
+}
+
+/**
+ * Reconcile AgentMeta rows with the agents a gateway reports.
+ * New agents get DEFAULT metas; metas whose agent disappeared are flagged
+ * with missingSince (never deleted, so classification survives a redeploy),
+ * and flags are cleared when the agent comes back.
+ */
+export async function syncInstanceAgents(instanceId: string): Promise<AgentSyncResult> {
+  const adapter = registry.getAdapter(instanceId)
+  const client = registry.getClient(instanceId)
+  if (!adapter || !client || !client.isConnected()) {
+    throw new Error('Instance not connected')
+  }
+
+  const [configResult, agentsResult] = await Promise.all([
+    adapter.getConfig(client),
+    adapter.getAgents(client),
+  ])
+  const { list } = extractAgentsConfig(configResult.config)
+
+  const gatewayIds = new Set<string>()
+  for (const entry of list) gatewayIds.add(entry.id)
+  for (const live of agentsResult.agents) gatewayIds.add(live.id)
+  const ids = [...gatewayIds]
+
+  const created = await autoRegisterAgents(instanceId, ids)
+
+  const now = new Date()
+  const [restored, missing] = await Promise.all([
+    prisma.agentMeta.updateMany({
+      where: { instanceId, agentId: { in: ids }, missingSince: { not: null } },
+      data: { missingSince: null },
+    }),
+    prisma.agentMeta.updateMany({
+      where: { instanceId, agentId: { notIn: ids }, missingSince: null },
+      data: { missingSince: now },
+    }),
+  ])
+
+  return {
+    instanceId,
+    agentCount: ids.length,
+    created: created.map((m) => m.agentId),
+    restored: restored.count,
+    newlyMissing: missing.count,
+  }
+}
+
+/** Sync every connected instance; failures are reported per instance */
+export async function syncAllAgents(): Promise<{
+  results: AgentSyncResult[]
+  errors: { instanceId: string; error: string }[]
+}> {
+  await ensureRegistryInitialized()
+
+  const ids = registry.getConnectedIds()
+  const results: AgentSyncResult[] = []
+  const errors: { instanceId: string; error: string }[] = []
+
+  for (let i = 0; i < ids.length; i += MAX_CONCURRENT) {
+    const batch = ids.slice(i, i + MAX_CONCURRENT)
+    await Promise.allSettled(
+      batch.map(async (instanceId) => {
+        try {
+          results.push(await syncInstanceAgents(instanceId))
+        } catch (err) {
+          errors.push({ instanceId, error: (err as Error).message })
+        }
+      }),
+    )
+  }
+
+  return { results, errors }
+}
+
+/** Start the periodic sync job (idempotent across hot reloads) */
+export function startAgentSync(): void {
+  if (globalForAgentSync.agentSyncTimer) return
+  globalForAgentSync.agentSyncTimer = setInterval(() => {
+    syncAllAgents()
+      .then(({ errors }) => {
+        for (const e of errors) {
+          log.error('Instance sync failed', { instanceId: e.instanceId, error: e.error })
+        }
+      })
+      .catch(console.error)
+  }, SYNC_INTERVAL_MS)
+}
@@ -0,0 +1,214 @@
+import type { Prisma } from '@/generated/prisma'
+import { prisma } from '@/lib/db'
+import { decryptSnapshots } from '@/lib/chat/snapshot-crypto'
+
+// Streaming chat analytics export for BI ingestion. Rows are read in
+// id-ordered cursor batches and written out as they arrive, so memory stays
+// flat regardless of how much history matches. Message text (and session
+// titles, which are derived from it) is only included when the caller asked
+// for it and holds analytics:export_content.
+
+const BATCH_SIZE = 500
+
+export type ChatExportFormat = 'ndjson' | 'csv'
+export type ChatExportLevel = 'sessions' | 'messages'
+
+export interface ChatExportFilter {
+  format: ChatExportFormat
+  level: ChatExportLevel
+  from: Date | null
+  to: Date | null
+  instanceId: string | null
+  agentId: string | null
+  userId: string | null
+  departmentId: string | null
+  includeContent: boolean
+}
+
+const SESSION_COLUMNS = [
+  'id', 'userId', 'userEmail', 'departmentId', 'instanceId', 'instanceName', 'agentId',
+  'messageCount', 'isActive', 'createdAt', 'lastMessageAt',
+] as const
+const MESSAGE_COLUMNS = [
+  'id', 'chatSessionId', 'userId', 'departmentId', 'instanceId', 'agentId', 'batchId',
+  'orderIndex', 'role', 'contentLength', 'hasThinking', 'toolCallCount', 'createdAt',
+] as const
+
+type Row = Record<string, string | number | boolean | null>
+
+function parseDate(value: string | null): Date | null | undefined {
+  if (!value) return null
+  const d = new Date(value)
+  return isNaN(d.getTime()) ? undefined : d
+}
+
+/**
+ * Read export options from the query string. Returns an error message for
+ * invalid input. `from`/`to` bound session createdAt (level=sessions) or
+ * message createdAt (level=messages).
+ */
+export function parseChatExportFilter(url: URL): ChatExportFilter | string {
+  const q = url.searchParams
+  const format = q.get('format') ?? 'ndjson'
+  const level = q.get('level') ?? 'sessions'
+  if (format !== 'ndjson' && format !== 'csv') return 'format must be ndjson or csv'
+  if (level !== 'sessions' && level !== 'messages') return 'level must be sessions or messages'
+
+  const from = parseDate(q.get('from'))
+  const to = parseDate(q.get('to'))
+  if (from === undefined || to === undefined) return 'from/to must be ISO dates'
+
+  return {
+    format,
+    level,
+    from,
+    to,
+    instanceId: q.get('instanceId'),
+    agentId: q.get('agentId'),
+    userId: q.get('userId'),
+    departmentId: q.get('departmentId'),
+    includeContent: q.get('includeContent') === 'true',
+  }
+}
+
+function sessionWhere(f: ChatExportFilter): Prisma.ChatSessionWhereInput {
+  return {
+    ...(f.instanceId ? { instanceId: f.instanceId } : {}),
+    ...(f.agentId ? { agentId: f.agentId } : {}),
+    ...(f.userId ? { userId: f.userId } : {}),
+    ...(f.departmentId ? { user: { departmentId: f.departmentId } } : {}),
+  }
+}
+
+function dateRange(f: ChatExportFilter): Prisma.DateTimeFilter | undefined {
+  if (!f.from && !f.to) return undefined
+  return { ...(f.from ? { gte: f.from } : {}), ...(f.to ? { lte: f.to } : {}) }
+}
+
+async function* sessionRows(f: ChatExportFilter): AsyncGenerator<Row[]> {
+  const createdAt = dateRange(f)
+  const where: Prisma.ChatSessionWhereInput = { ...sessionWhere(f), ...(createdAt ? { createdAt } : {}) }
+  let cursor: string | null = null
+
+  for (;;) {
+    const rows = await prisma.chatSession.findMany({
+      where,
+      include: {
+        user: { select: { email: true, departmentId: true } },
+        instance: { select: { name: true } },
+      },
+      orderBy: { id: 'asc' },
+      take: BATCH_SIZE,
+      ...(cursor ? { cursor: { id: cursor }, skip: 1 } : {}),
+    })
+    if (rows.length === 0) return
+
+    yield rows.map((s) => ({
+      id: s.id,
+      userId: s.userId,
+      userEmail: s.user.email,
+      departmentId: s.user.departmentId,
+      instanceId: s.instanceId,
+      instanceName: s.instance.name,
+      agentId: s.agentId,
+      messageCount: s.messageCount,
+      isActive: s.isActive,
+      createdAt: s.createdAt.toISOString(),
+      lastMessageAt: s.lastMessageAt?.toISOString() ?? null,
+      ...(f.includeContent ? { title: s.title } : {}),
+    }))
+    if (rows.length < BATCH_SIZE) return
+    cursor = rows[rows.length - 1].id
+  }
+}
+
+async function* messageRows(f: ChatExportFilter): AsyncGenerator<Row[]> {
+  const createdAt = dateRange(f)
+  const where: Prisma.ChatMessageSnapshotWhereInput = {
+    chatSession: sessionWhere(f),
+    ...(createdAt ? { createdAt } : {}),
+  }
+  let cursor: string | null = null
+
+  for (;;) {
+    const rows = await prisma.chatMessageSnapshot.findMany({
+      where,
+      include: {
+        chatSession: {
+          select: { userId: true, instanceId: true, agentId: true, user: { select: { departmentId: true } } },
+        },
+      },
+      orderBy: { id: 'asc' },
+      take: BATCH_SIZE,
+      ...(cursor ? { cursor: { id: cursor }, skip: 1 } : {}),
+    })
+    if (rows.length === 0) return
+
+    // Sealed rows need opening even for metadata: lengths and tool call
+    // counts are meaningless on ciphertext
+    const opened = await decryptSnapshots(rows)
+    yield opened.map((m) => ({
+      id: m.id,
+      chatSessionId: m.chatSessionId,
+      userId: m.chatSession.userId,
+      departmentId: m.chatSession.user.departmentId,
+      instanceId: m.chatSession.instanceId,
+      agentId: m.chatSession.agentId,
+      batchId: m.batchId,
+      orderIndex: m.orderIndex,
+      role: m.role,
+      contentLength: m.content.length,
+      hasThinking: !!m.thinking,
+      toolCallCount: Array.isArray(m.toolCalls) ? m.toolCalls.length : 0,
+      createdAt: m.createdAt.toISOString(),
+      ...(f.includeContent ? { content: m.content } : {}),
+    }))
+    if (rows.length < BATCH_SIZE) return
+    cursor = rows[rows.length - 1].id
+  }
+}
+
+function csvCell(value: Row[string]): string {
+  if (value === null) return ''
+  const s = String(value)
+  return /[",\n\r]/.test(s) ? `"${s.replace(/"/g, '""')}"` : s
+}
+
+async function* encode(f: ChatExportFilter): AsyncGenerator<string> {
+  const batches = f.level === 'sessions' ? sessionRows(f) : messageRows(f)
+
+  if (f.format === 'ndjson') {
+    for await (const rows of batches) {
+      yield rows.map((r) => JSON.stringify(r)).join('\n') + '\n'
+    }
+    return
+  }
+
+  const columns: string[] = [...(f.level === 'sessions' ? SESSION_COLUMNS : MESSAGE_COLUMNS)]
+  if (f.includeContent) columns.push(f.level === 'sessions' ? 'title' : 'content')
+  yield '\uFEFF' + columns.join(',') + '\n'
+  for await (const rows of batches) {
+    yield rows.map((r) => columns.map((c) => csvCell(r[c] ?? null)).join(',')).join('\n') + '\n'
+  }
+}
+
+/** Pull-based stream: the next batch is only queried once the client has read the last */
+export function streamChatExport(f: ChatExportFilter): ReadableStream<Uint8Array> {
+  const encoder = new TextEncoder()
+  const chunks = encode(f)
+
+  return new ReadableStream<Uint8Array>({
+    async pull(controller) {
+      try {
+        const { value, done } = await chunks.next()
+        if (done) controller.close()
+        else controller.enqueue(encoder.encode(value))
+      } catch (err) {
+        controller.error(err)
+      }
+    },
+    async cancel() {
+      await chunks.return(undefined)
+    },
+  })
+}
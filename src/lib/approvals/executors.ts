@@ -0,0 +1,233 @@
+import { prisma } from '@/lib/db'
+import { stringList } from '@/lib/db-dialect'
+import { Prisma } from '@/generated/prisma'
+import type { Role } from '@/generated/prisma'
+import { destroyInstance } from '@/lib/instances/lifecycle'
+import {
+  bulkGrantInstanceAccess,
+  bulkRevokeInstanceAccess,
+  expiryValue,
+  grantUserInstanceAccess,
+  type BulkGrant,
+} from '@/lib/instances/access'
+import { roleExpiryData } from '@/lib/access-expiry'
+import { assertResidencyAllowed } from '@/lib/instances/residency'
+import { diffPolicies, parsePolicies, savePolicies } from '@/lib/auth/policy-store'
+import { checkRolePermissions } from '@/lib/auth/custom-roles'
+import { APPROVAL_ACTIONS, getApprovalPolicy, setApprovalPolicy, type ApprovalAction } from './index'
+
+type AuditDetails = Record<string, string | number | boolean | null>
+
+/**
+ * Applies an approved action. Returns audit details for the executed
+ * action; throwing marks the approval FAILED.
+ */
+type ApprovalExecutor = (payload: Record<string, unknown>, approverId: string) => Promise<AuditDetails>
+
+export const APPROVAL_EXECUTORS: Record<ApprovalAction, ApprovalExecutor> = {
+  INSTANCE_DELETE: async (payload) => {
+    const instance = await prisma.instance.findUnique({
+      where: { id: payload.instanceId as string },
+    })
+    if (!instance) throw new Error('Instance not found')
+    const deleted = await destroyInstance(instance)
+    return { name: instance.name, sessionsClosed: deleted.sessionsClosed, chatSessions: deleted.removed.chatSessions }
+  },
+
+  INSTANCE_ACCESS_GRANT: async (payload, approverId) => {
+    // User grant (POST /instance-access/users)
+    if (typeof payload.userId === 'string') {
+      const grantee = await prisma.user.findUnique({
+        where: { id: payload.userId },
+        select: { departmentId: true },
+      })
+      if (!grantee) throw new Error('User not found')
+      if (grantee.departmentId) {
+        await assertResidencyAllowed(grantee.departmentId, [payload.instanceId as string])
+      }
+      const grant = await grantUserInstanceAccess(payload.userId, payload as unknown as BulkGrant, approverId)
+      return { grantId: grant.id, userName: grant.user.name, instanceName: grant.instance.name }
+    }
+
+    const departmentId = payload.departmentId as string
+    // Bulk grant (POST /departments/:id/instance-accesses)
+    if (Array.isArray(payload.grants)) {
+      // Residency rules may have changed while the request was pending
+      await assertResidencyAllowed(departmentId, (payload.grants as BulkGrant[]).map((g) => g.instanceId))
+      const ids = await bulkGrantInstanceAccess(departmentId, payload.grants as BulkGrant[], approverId)
+      return { departmentId, count: ids.length }
+    }
+
+    const instanceId = payload.instanceId as string
+    await assertResidencyAllowed(departmentId, [instanceId])
+    const agentIds = payload.agentIds as string[] | null | undefined
+    const expiresAt = expiryValue(payload.expiresAt as string | null | undefined)
+
+    const grant = await prisma.instanceAccess.upsert({
+      where: { departmentId_instanceId: { departmentId, instanceId } },
+      update: {
+        agentIds: agentIds !== undefined
+          ? (agentIds as unknown as Prisma.InputJsonValue ?? Prisma.DbNull)
+          : undefined,
+        expiresAt,
+        expiryNotifiedAt: expiresAt !== undefined ? null : undefined,
+        grantedById: approverId,
+      },
+      create: {
+        departmentId,
+        instanceId,
+        agentIds: agentIds != null ? (agentIds as unknown as Prisma.InputJsonValue) : undefined,
+        expiresAt,
+        grantedById: approverId,
+      },
+      include: {
+        department: { select: { name: true } },
+        instance: { select: { name: true } },
+      },
+    })
+    return {
+      grantId: grant.id,
+      departmentName: grant.department.name,
+      instanceName: grant.instance.name,
+    }
+  },
+
+  INSTANCE_ACCESS_REVOKE: async (payload) => {
+    // Bulk revoke (DELETE /departments/:id/instance-accesses)
+    if (Array.isArray(payload.instanceIds)) {
+      const departmentId = payload.departmentId as string
+      const count = await bulkRevokeInstanceAccess(departmentId, payload.instanceIds as string[])
+      return { departmentId, count }
+    }
+
+    // User grant (DELETE /instance-access/users/:id)
+    if (typeof payload.userGrantId === 'string') {
+      const grant = await prisma.userInstanceAccess.findUnique({
+        where: { id: payload.userGrantId },
+        include: {
+          user: { select: { name: true } },
+          instance: { select: { name: true } },
+        },
+      })
+      if (!grant) throw new Error('Access grant not found')
+      await prisma.userInstanceAccess.delete({ where: { id: grant.id } })
+      return { userName: grant.user.name, instanceName: grant.instance.name }
+    }
+
+    const grant = await prisma.instanceAccess.findUnique({
+      where: { id: payload.grantId as string },
+      include: {
+        department: { select: { name: true } },
+        instance: { select: { name: true } },
+      },
+    })
+    if (!grant) throw new Error('Access grant not found')
+    await prisma.instanceAccess.delete({ where: { id: grant.id } })
+    return {
+      departmentName: grant.department.name,
+      instanceName: grant.instance.name,
+    }
+  },
+
+  USER_ROLE_ELEVATE: async (payload) => {
+    const target = await prisma.user.findUnique({ where: { id: payload.userId as string } })
+    if (!target) throw new Error('User not found')
+    const role = payload.role as Role
+    // Reactivating a user who holds an elevated role is filed with status
+    const status = payload.status === 'ACTIVE' ? ('ACTIVE' as const) : undefined
+    await prisma.user.update({
+      where: { id: target.id },
+      data: {
+        role,
+        status,
+        ...roleExpiryData(
+          target,
+          payload.roleExpiresAt as string | null | undefined,
+          role !== target.role,
+        ),
+      },
+    })
+    return {
+      name: target.name,
+      fromRole: target.role,
+      toRole: role,
+      roleExpiresAt: (payload.roleExpiresAt as string | null | undefined) ?? null,
+      reactivated: status === 'ACTIVE',
+    }
+  },
+
+  USER_CUSTOM_ROLE_ASSIGN: async (payload) => {
+    const target = await prisma.user.findUnique({
+      where: { id: payload.userId as string },
+      include: { customRole: { select: { name: true } } },
+    })
+    if (!target) throw new Error('User not found')
+    const role = await prisma.customRole.findUnique({ where: { id: payload.customRoleId as string } })
+    if (!role) throw new Error('Custom role not found')
+    await prisma.user.update({ where: { id: target.id }, data: { customRoleId: role.id } })
+    return { name: target.name, fromCustomRole: target.customRole?.name ?? null, toCustomRole: role.name }
+  },
+
+  // Nothing to run: the executed approval is what opens the read window
+  // (lib/users/session-inspection.ts)
+  SESSION_INSPECT: async (payload) => {
+    const target = await prisma.user.findUnique({
+      where: { id: payload.userId as string },
+      select: { name: true, email: true },
+    })
+    if (!target) throw new Error('User not found')
+    return { name: target.name, email: target.email }
+  },
+
+  // Policy change that takes actions out of the two-person rule
+  // (PUT /approvals/policy); replaces the policy as requested
+  APPROVAL_POLICY_RELAX: async (payload) => {
+    const before = await getApprovalPolicy()
+    const requiredActions = (payload.requiredActions as string[]).filter((a): a is ApprovalAction =>
+      (APPROVAL_ACTIONS as readonly string[]).includes(a),
+    )
+    await setApprovalPolicy({ requiredActions, expiryHours: payload.expiryHours as number })
+    return {
+      before: before.requiredActions.join(','),
+      after: requiredActions.join(','),
+      expiryHours: payload.expiryHours as number,
+    }
+  },
+
+  RBAC_POLICY_CHANGE: async (payload) => {
+    // Custom role update (PUT /rbac/roles/:id)
+    if (typeof payload.roleId === 'string') {
+      const role = await prisma.customRole.findUnique({ where: { id: payload.roleId } })
+      if (!role) throw new Error('Custom role not found')
+      const permissions = payload.permissions as string[]
+      // Grantability may have changed while the request was pending
+      const invalid = checkRolePermissions(permissions)
+      if (invalid) throw new Error(invalid)
+      const name = (payload.name as string | undefined) ?? role.name
+      if (name !== role.name) {
+        const clash = await prisma.customRole.findUnique({ where: { name } })
+        if (clash) throw new Error(`Role "${name}" already exists`)
+      }
+      await prisma.customRole.update({
+        where: { id: role.id },
+        data: { name, description: payload.description as string | null | undefined, permissions },
+      })
+      return {
+        name,
+        before: stringList(role.permissions).join(','),
+        after: permissions.join(','),
+      }
+    }
+
+    // Policy import (POST /rbac/policies/import); validated again in full
+    const doc = JSON.stringify({ version: 1, policies: payload.policies })
+    const { next, errors } = parsePolicies(doc, 'json')
+    if (errors.length > 0) throw new Error(errors.join('; '))
+    const changes = diffPolicies(next)
+    await savePolicies(next)
+    return {
+      changed: changes.length,
+      permissions: changes.map((c) => c.permission).join(','),
+    }
+  },
+}
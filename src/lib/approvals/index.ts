@@ -0,0 +1,220 @@
+import { NextRequest, NextResponse } from 'next/server'
+import { prisma } from '@/lib/db'
+import { auditLog } from '@/lib/audit'
+import { Prisma } from '@/generated/prisma'
+import type { ApprovalRequest } from '@/generated/prisma'
+
+/** Actions that can be placed behind the two-person rule */
+export const APPROVAL_ACTIONS = [
+  'INSTANCE_DELETE',
+  'INSTANCE_ACCESS_GRANT',
+  'INSTANCE_ACCESS_REVOKE',
+  'USER_ROLE_ELEVATE',
+  'USER_CUSTOM_ROLE_ASSIGN',
+  'SESSION_INSPECT',
+  'APPROVAL_POLICY_RELAX',
+  'RBAC_POLICY_CHANGE',
+] as const
+
+export type ApprovalAction = (typeof APPROVAL_ACTIONS)[number]
+
+export interface ApprovalPolicy {
+  requiredActions: ApprovalAction[]
+  expiryHours: number
+}
+
+/** SystemConfig key holding the ApprovalPolicy */
+export const APPROVAL_POLICY_KEY = 'approval_policy'
+
+const DEFAULT_POLICY: ApprovalPolicy = {
+  requiredActions: [],
+  expiryHours: 24,
+}
+
+export async function getApprovalPolicy(): Promise<ApprovalPolicy> {
+  const row = await prisma.systemConfig.findUnique({ where: { key: APPROVAL_POLICY_KEY } })
+  const value = (row?.value ?? {}) as Partial<ApprovalPolicy>
+  return {
+    requiredActions: Array.isArray(value.requiredActions)
+      ? value.requiredActions.filter((a): a is ApprovalAction =>
+          (APPROVAL_ACTIONS as readonly string[]).includes(a),
+        )
+      : DEFAULT_POLICY.requiredActions,
+    expiryHours:
+      typeof value.expiryHours === 'number' && value.expiryHours > 0
+        ? value.expiryHours
+        : DEFAULT_POLICY.expiryHours,
+  }
+}
+
+export async function setApprovalPolicy(policy: ApprovalPolicy): Promise<void> {
+  await prisma.systemConfig.upsert({
+    where: { key: APPROVAL_POLICY_KEY },
+    update: { value: policy as unknown as Prisma.InputJsonValue },
+    create: {
+      key: APPROVAL_POLICY_KEY,
+      value: policy as unknown as Prisma.InputJsonValue,
+      description: 'Actions that require a second SYSTEM_ADMIN to approve',
+    },
+  })
+}
+
+// Behind the two-person rule whatever the policy says: otherwise one admin
+// could switch the rule off for every other action
+const ALWAYS_REQUIRED: readonly ApprovalAction[] = ['APPROVAL_POLICY_RELAX']
+
+// Also required while any of these is: changing what a role may do is
+// another way of elevating everyone who holds it
+const IMPLIED_BY: Partial<Record<ApprovalAction, readonly ApprovalAction[]>> = {
+  RBAC_POLICY_CHANGE: ['USER_ROLE_ELEVATE', 'USER_CUSTOM_ROLE_ASSIGN'],
+}
+
+/** Check whether an action is currently configured to require approval */
+export async function requiresApproval(action: ApprovalAction): Promise<boolean> {
+  if (ALWAYS_REQUIRED.includes(action)) return true
+  const policy = await getApprovalPolicy()
+  return [action, ...(IMPLIED_BY[action] ?? [])].some((a) => policy.requiredActions.includes(a))
+}
+
+/**
+ * Record a pending approval instead of executing the action.
+ * The payload must contain everything the executor needs, since the
+ * requester's context is gone by the time the request is approved.
+ */
+export async function createApprovalRequest(params: {
+  action: ApprovalAction
+  resource: string
+  resourceId?: string
+  payload: Record<string, unknown>
+  summary?: Record<string, string | number | boolean | null>
+  reason?: string
+  requestedById: string
+  ipAddress: string
+  userAgent?: string
+}): Promise<ApprovalRequest> {
+  const policy = await getApprovalPolicy()
+
+  const approval = await prisma.approvalRequest.create({
+    data: {
+      action: params.action,
+      resource: params.resource,
+      resourceId: params.resourceId,
+      payload: params.payload as Prisma.InputJsonValue,
+      summary: params.summary ?? undefined,
+      reason: params.reason,
+      requestedById: params.requestedById,
+      expiresAt: new Date(Date.now() + policy.expiryHours * 3600000),
+    },
+  })
+
+  auditLog({
+    userId: params.requestedById,
+    action: 'APPROVAL_REQUEST',
+    resource: 'approval',
+    resourceId: approval.id,
+    details: {
+      action: params.action,
+      target: params.resourceId ?? null,
+      ...params.summary,
+    },
+    ipAddress: params.ipAddress,
+    userAgent: params.userAgent,
+    result: 'SUCCESS',
+  })
+
+  return approval
+}
+
+/**
+ * Route helper: if the action needs approval, file the request and return
+ * a 202 response for the caller to send; otherwise return null and let the
+ * route execute the action directly.
+ */
+export async function interceptForApproval(
+  req: NextRequest,
+  params: Omit<Parameters<typeof createApprovalRequest>[0], 'reason' | 'ipAddress' | 'userAgent'>,
+): Promise<NextResponse | null> {
+  if (!(await requiresApproval(params.action))) return null
+
+  const approval = await createApprovalRequest({
+    ...params,
+    reason: new URL(req.url).searchParams.get('reason')?.slice(0, 1000) || undefined,
+    ipAddress: req.headers.get('x-forwarded-for') || 'unknown',
+    userAgent: req.headers.get('user-agent') || undefined,
+  })
+
+  return NextResponse.json(
+    { status: 'pending_approval', approval: toApprovalResponse(approval) },
+    { status: 202 },
+  )
+}
+
+/**
+ * Mark overdue PENDING requests as EXPIRED. Called lazily from the
+ * approval routes so no background timer is needed.
+ */
+export async function expireStaleApprovals(): Promise<number> {
+  const stale = await prisma.approvalRequest.findMany({
+    where: { status: 'PENDING', expiresAt: { lt: new Date() } },
+    select: { id: true, action: true, requestedById: true },
+  })
+  if (stale.length === 0) return 0
+
+  await prisma.approvalRequest.updateMany({
+    where: { id: { in: stale.map((s) => s.id) }, status: 'PENDING' },
+    data: { status: 'EXPIRED' },
+  })
+
+  for (const s of stale) {
+    auditLog({
+      userId: s.requestedById,
+      action: 'APPROVAL_EXPIRE',
+      resource: 'approval',
+      resourceId: s.id,
+      details: { action: s.action },
+      ipAddress: 'system',
+      result: 'FAILURE',
+    })
+  }
+
+  return stale.length
+}
+
+/** Role rank used to decide whether a role change is an elevation */
+const ROLE_RANK: Record<string, number> = {
+  USER: 0,
+  VIEWER: 1,
+  DEPT_ADMIN: 2,
+  SYSTEM_ADMIN: 3,
+}
+
+export function isRoleElevation(from: string, to: string): boolean {
+  return (ROLE_RANK[to] ?? 0) > (ROLE_RANK[from] ?? 0)
+}
+
+export function toApprovalResponse(
+  a: ApprovalRequest & {
+    requestedBy?: { name: string } | null
+    reviewedBy?: { name: string } | null
+  },
+) {
+  return {
+    id: a.id,
+    action: a.action,
+    resource: a.resource,
+    resourceId: a.resourceId,
+    summary: a.summary as Record<string, unknown> | null,
+    reason: a.reason,
+    status: a.status,
+    requestedById: a.requestedById,
+    requestedByName: a.requestedBy?.name ?? null,
+    reviewedById: a.reviewedById,
+    reviewedByName: a.reviewedBy?.name ?? null,
+    reviewComment: a.reviewComment,
+    reviewedAt: a.reviewedAt?.toISOString() ?? null,
+    executedAt: a.executedAt?.toISOString() ?? null,
+    error: a.error,
+    expiresAt: a.expiresAt.toISOString(),
+    createdAt: a.createdAt.toISOString(),
+  }
+}
@@ -0,0 +1,221 @@
+import { randomUUID } from 'crypto'
+import { prisma } from '@/lib/db'
+import { skipDuplicatesOption } from '@/lib/db-dialect'
+import { createLogger } from '@/lib/logger'
+import type { Prisma } from '@/generated/prisma'
+
+// Write-behind buffer for audit entries: auditLog() enqueues and returns, a
+// single writer drains the queue with createMany every flush interval (or as
+// soon as a batch fills). The queue is bounded; entries arriving while it is
+// full are dropped and counted. A best-effort flush runs on shutdown.
+//
+// AUDIT_QUEUE_MAX             — max buffered entries (default 10000)
+// AUDIT_QUEUE_BATCH_SIZE      — rows per insert (default 200)
+// AUDIT_QUEUE_FLUSH_MS        — flush interval (default 1000)
+// AUDIT_QUEUE_PERSIST=redis   — buffer in a Redis list instead of memory, so
+//   entries survive a crash and are written by whichever process flushes
+//   next. Entries carry their id, so a batch replayed after a failed trim
+//   is skipped rather than duplicated (Postgres only; SQLite has no
+//   skipDuplicates).
+
+const REDIS_KEY = 'audit:queue'
+const REDIS_LOCK_KEY = 'audit:queue:flush'
+const REDIS_LOCK_TTL_MS = 30_000
+const SHUTDOWN_FLUSH_TIMEOUT_MS = 5_000
+
+const log = createLogger('audit:queue')
+
+type Entry = Prisma.AuditLogCreateManyInput & { id: string; createdAt: Date }
+
+export interface AuditQueueStats {
+  backend: 'memory' | 'redis'
+  depth: number
+  enqueued: number
+  written: number
+  dropped: number
+  failedWrites: number
+}
+
+const globalForAuditQueue = globalThis as unknown as {
+  auditQueue?: Entry[]
+  auditQueueTimer?: ReturnType<typeof setInterval> | null
+  auditQueueFlushing?: boolean
+  auditQueueStats?: Omit<AuditQueueStats, 'backend' | 'depth'>
+  auditQueueShutdownHooked?: boolean
+}
+
+const queue = (globalForAuditQueue.auditQueue ??= [])
+const counters = (globalForAuditQueue.auditQueueStats ??= { enqueued: 0, written: 0, dropped: 0, failedWrites: 0 })
+
+function intEnv(name: string, fallback: number): number {
+  const n = parseInt(process.env[name] ?? '', 10)
+  return Number.isFinite(n) && n > 0 ? n : fallback
+}
+
+// Only loaded in Redis mode, so the default setup opens no extra connection
+const getRedis = () => import('@/lib/redis').then((m) => m.redis)
+
+const maxQueue = () => intEnv('AUDIT_QUEUE_MAX', 10_000)
+const batchSize = () => intEnv('AUDIT_QUEUE_BATCH_SIZE', 200)
+const useRedis = () => process.env.AUDIT_QUEUE_PERSIST === 'redis'
+
+function drop(reason: string): void {
+  counters.dropped++
+  // One warning per 100 drops keeps an overflow from flooding the logs too
+  if (counters.dropped % 100 === 1) log.warn('Audit queue full, dropping entries', { reason, dropped: counters.dropped })
+}
+
+function pushMemory(entry: Entry): void {
+  if (queue.length >= maxQueue()) return drop('memory')
+  queue.push(entry)
+  if (queue.length >= batchSize()) void flushAuditQueue()
+}
+
+async function pushRedis(entry: Entry): Promise<void> {
+  const redis = await getRedis()
+  const len = await redis.rpush(REDIS_KEY, JSON.stringify(entry))
+  if (len > maxQueue()) {
+    // Drop the newest, matching the in-memory policy
+    await redis.rpop(REDIS_KEY)
+    return drop('redis')
+  }
+  if (len >= batchSize()) void flushAuditQueue()
+}
+
+/** Buffer one entry for the batch writer */
+export function enqueueAudit(data: Prisma.AuditLogCreateManyInput): void {
+  const entry: Entry = { ...data, id: randomUUID(), createdAt: new Date() }
+  counters.enqueued++
+  startWriter()
+
+  if (useRedis()) {
+    pushRedis(entry).catch((err) => {
+      log.warn('Redis audit queue unavailable, buffering in memory', { err })
+      pushMemory(entry)
+    })
+  } else {
+    pushMemory(entry)
+  }
+}
+
+async function writeBatch(batch: Entry[]): Promise<void> {
+  await prisma.auditLog.createMany({ data: batch, ...skipDuplicatesOption() })
+  counters.written += batch.length
+}
+
+async function flushMemory(): Promise<boolean> {
+  const batch = queue.splice(0, batchSize())
+  if (batch.length === 0) return false
+  try {
+    await writeBatch(batch)
+  } catch (err) {
+    counters.failedWrites++
+    // Put it back in front; anything beyond the cap is lost
+    queue.unshift(...batch)
+    const excess = queue.length - maxQueue()
+    if (excess > 0) queue.splice(maxQueue(), excess).forEach(() => drop('memory'))
+    throw err
+  }
+  return queue.length > 0
+}
+
+async function flushRedis(): Promise<boolean> {
+  const redis = await getRedis()
+  const size = batchSize()
+  const raw = await redis.lrange(REDIS_KEY, 0, size - 1)
+  if (raw.length === 0) return false
+  const batch = raw.map((r) => {
+    const e = JSON.parse(r) as Entry
+    return { ...e, createdAt: new Date(e.createdAt) }
+  })
+  try {
+    await writeBatch(batch)
+  } catch (err) {
+    counters.failedWrites++
+    throw err
+  }
+  await redis.ltrim(REDIS_KEY, raw.length, -1)
+  return raw.length === size
+}
+
+/** Processes share the Redis list; only one may read-write-trim at a time */
+async function flushRedisLocked(): Promise<void> {
+  const redis = await getRedis()
+  if (!(await redis.set(REDIS_LOCK_KEY, String(process.pid), 'PX', REDIS_LOCK_TTL_MS, 'NX'))) return
+  try {
+    while (await flushRedis());
+  } finally {
+    await redis.del(REDIS_LOCK_KEY)
+  }
+}
+
+/** Drain the queue in batches until empty or a write fails */
+export async function flushAuditQueue(): Promise<void> {
+  if (globalForAuditQueue.auditQueueFlushing) return
+  globalForAuditQueue.auditQueueFlushing = true
+  try {
+    // Entries that fell back to memory while Redis was down go first
+    while (await flushMemory());
+    if (useRedis()) await flushRedisLocked()
+  } catch (err) {
+    log.error('Failed to write audit batch', { err })
+  } finally {
+    globalForAuditQueue.auditQueueFlushing = false
+  }
+}
+
+function hookShutdown(): void {
+  if (globalForAuditQueue.auditQueueShutdownHooked) return
+  globalForAuditQueue.auditQueueShutdownHooked = true
+
+  const flushWithTimeout = () =>
+    Promise.race([flushAuditQueue(), new Promise((resolve) => setTimeout(resolve, SHUTDOWN_FLUSH_TIMEOUT_MS))])
+
+  process.once('beforeExit', () => void flushWithTimeout())
+  for (const signal of ['SIGTERM', 'SIGINT'] as const) {
+    process.once(signal, () => {
+      flushWithTimeout().finally(() => {
+        // Only exit ourselves when nothing else (e.g. the Next server) handles the signal
+        if (process.listenerCount(signal) === 0) process.exit(0)
+      })
+    })
+  }
+}
+
+function startWriter(): void {
+  if (globalForAuditQueue.auditQueueTimer) return
+  globalForAuditQueue.auditQueueTimer = setInterval(() => void flushAuditQueue(), intEnv('AUDIT_QUEUE_FLUSH_MS', 1000))
+  globalForAuditQueue.auditQueueTimer.unref?.()
+  hookShutdown()
+}
+
+export async function getAuditQueueStats(): Promise<AuditQueueStats> {
+  const redisDepth = useRedis() ? await getRedis().then((r) => r.llen(REDIS_KEY)).catch(() => 0) : 0
+  return {
+    backend: useRedis() ? 'redis' : 'memory',
+    depth: queue.length + redisDepth,
+    ...counters,
+  }
+}
+
+/** Prometheus counters / gauge for the audit write buffer */
+export async function auditQueueToPrometheus(): Promise<string> {
+  const s = await getAuditQueueStats()
+  return [
+    '# HELP teamclaw_audit_queue_depth Audit entries waiting to be written',
+    '# TYPE teamclaw_audit_queue_depth gauge',
+    `teamclaw_audit_queue_depth{backend="${s.backend}"} ${s.depth}`,
+    '# HELP teamclaw_audit_enqueued_total Audit entries accepted into the queue',
+    '# TYPE teamclaw_audit_enqueued_total counter',
+    `teamclaw_audit_enqueued_total ${s.enqueued}`,
+    '# HELP teamclaw_audit_written_total Audit entries written to the database',
+    '# TYPE teamclaw_audit_written_total counter',
+    `teamclaw_audit_written_total ${s.written}`,
+    '# HELP teamclaw_audit_dropped_total Audit entries dropped because the queue was full',
+    '# TYPE teamclaw_audit_dropped_total counter',
+    `teamclaw_audit_dropped_total ${s.dropped}`,
+    '# HELP teamclaw_audit_failed_writes_total Failed audit batch inserts (retried)',
+    '# TYPE teamclaw_audit_failed_writes_total counter',
+    `teamclaw_audit_failed_writes_total ${s.failedWrites}`,
+  ].join('\n') + '\n'
+}
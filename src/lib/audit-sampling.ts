@@ -0,0 +1,68 @@
+import { prisma } from '@/lib/db'
+import { Prisma } from '@/generated/prisma'
+
+// Audit sampling: high-volume resources (chat, gateway proxy) can flood the
+// audit table. Rules are matched in order, first hit wins; unmatched entries
+// are always kept. Kept entries from a sampled rule record `sampleRate` in
+// their details so counts can be scaled back up.
+
+/** SystemConfig key holding audit sampling rules */
+export const AUDIT_SAMPLING_KEY = 'audit_sampling'
+
+export interface AuditSamplingRule {
+  resource: string          // exact resource, or "*"
+  action?: string           // exact action, "PREFIX_*", or omitted for any
+  sampleRate: number        // 0–1 share of SUCCESS entries kept; 0 excludes them
+  keepFailures: boolean     // FAILURE / DENIED entries bypass sampling
+}
+
+/** Never sampled, so changes to the audit trail itself stay auditable */
+export const UNSAMPLED_RESOURCES = ['system_config', 'auth']
+
+const globalForSampling = globalThis as unknown as {
+  auditSamplingRules?: AuditSamplingRule[]
+}
+
+export function getAuditSamplingRules(): AuditSamplingRule[] {
+  return globalForSampling.auditSamplingRules ?? []
+}
+
+function matches(rule: AuditSamplingRule, action: string, resource: string): boolean {
+  if (rule.resource !== '*' && rule.resource !== resource) return false
+  if (!rule.action) return true
+  return rule.action.endsWith('*') ? action.startsWith(rule.action.slice(0, -1)) : rule.action === action
+}
+
+/**
+ * Decide whether an entry is written. Returns null to drop it, otherwise
+ * the sample rate it was kept at (1 = not sampled).
+ */
+export function sampleAudit(action: string, resource: string, result: string): number | null {
+  if (UNSAMPLED_RESOURCES.includes(resource)) return 1
+  const rule = getAuditSamplingRules().find((r) => matches(r, action, resource))
+  if (!rule) return 1
+
+  if (rule.sampleRate >= 1 || (result !== 'SUCCESS' && rule.keepFailures)) return 1
+  return Math.random() < rule.sampleRate ? rule.sampleRate : null
+}
+
+/** Apply rules saved through the admin endpoint */
+export async function loadAuditSamplingRules(): Promise<void> {
+  const row = await prisma.systemConfig.findUnique({ where: { key: AUDIT_SAMPLING_KEY } })
+  const stored = row?.value as { rules?: AuditSamplingRule[] } | undefined
+  globalForSampling.auditSamplingRules = Array.isArray(stored?.rules) ? stored.rules : []
+}
+
+export async function saveAuditSamplingRules(rules: AuditSamplingRule[]): Promise<void> {
+  const value = { rules } as unknown as Prisma.InputJsonValue
+  await prisma.systemConfig.upsert({
+    where: { key: AUDIT_SAMPLING_KEY },
+    update: { value },
+    create: {
+      key: AUDIT_SAMPLING_KEY,
+      value,
+      description: 'Audit log sampling / exclusion rules for high-volume resources',
+    },
+  })
+  globalForSampling.auditSamplingRules = rules
+}
@@ -1,35 +1,153 @@
+import { createHmac } from 'crypto'
+import { isIPv4, isIPv6 } from 'net'
+import { sampleAudit } from '@/lib/audit-sampling'
+import { enqueueAudit } from '@/lib/audit-queue'
 import { prisma } from '@/lib/db'
+import type { Prisma } from '@/generated/prisma'
+
+export type AuditDetails = Record<string, string | number | boolean | null>
+
+/** Field-level before/after state captured for update operations */
+export type AuditChanges = Record<string, { before: unknown; after: unknown }>
+
+/** Placeholder recorded instead of secret values (tokens, credentials) */
+export const REDACTED = '[redacted]'
+
+// ─── Privacy ─────────────────────────────────────────────────────────
+//
+// AUDIT_IP_MODE:  full (default) | truncate | hash | drop
+//   truncate — zero the host part (IPv4 /24, IPv6 /48)
+//   hash     — HMAC-SHA256 keyed by AUDIT_IP_HASH_KEY (or ENCRYPTION_KEY),
+//              so repeat visits still correlate without storing the address
+// AUDIT_DROP_USER_AGENT=true — never persist user agents
+
+type IpMode = 'full' | 'truncate' | 'hash' | 'drop'
+
+function ipMode(): IpMode {
+  const mode = process.env.AUDIT_IP_MODE
+  return mode === 'truncate' || mode === 'hash' || mode === 'drop' ? mode : 'full'
+}
+
+function truncateIp(ip: string): string {
+  if (isIPv4(ip)) return ip.split('.').slice(0, 3).concat('0').join('.')
+  if (isIPv6(ip)) {
+    // Expand "::" so the first three hextets are positional
+    const [head, tail = ''] = ip.split('::')
+    const headParts = head ? head.split(':') : []
+    const tailParts = tail ? tail.split(':') : []
+    const fill = Array(Math.max(0, 8 - headParts.length - tailParts.length)).fill('0')
+    const full = ip.includes('::') ? [...headParts, ...fill, ...tailParts] : headParts
+    return `${full.slice(0, 3).join(':')}::`
+  }
+  return ip
+}
+
+/** Apply the configured IP policy. Non-IP placeholders pass through. */
+export function anonymizeIp(ip: string): string {
+  // x-forwarded-for may carry a proxy chain — keep only the client
+  const client = ip.split(',')[0].trim()
+  if (!isIPv4(client) && !isIPv6(client)) return client
+
+  switch (ipMode()) {
+    case 'truncate':
+      return truncateIp(client)
+    case 'hash': {
+      const key = process.env.AUDIT_IP_HASH_KEY || process.env.ENCRYPTION_KEY || ''
+      return `h:${createHmac('sha256', key).update(client).digest('hex').slice(0, 32)}`
+    }
+    case 'drop':
+      return 'redacted'
+    default:
+      return client
+  }
+}
+
+export function anonymizeUserAgent(ua: string | null | undefined): string | undefined {
+  if (!ua || process.env.AUDIT_DROP_USER_AGENT === 'true') return undefined
+  return ua
+}
 
 /**
- * Write an audit log entry. Best-effort: failures are logged to console
- * but do not block the calling request. This is an intentional tradeoff
- * for performance — security-critical actions should verify the audit
- * write succeeded if compliance requirements demand it.
+ * Write an audit log entry. Best-effort: entries are buffered and written
+ * in batches by lib/audit-queue, so the calling request never waits on the
+ * insert. This is an intentional tradeoff for performance — enable
+ * AUDIT_QUEUE_PERSIST=redis if entries must survive a crash.
+ *
+ * IP address and user agent are anonymized per the privacy settings above
+ * before they are stored. Entries may be dropped by the sampling rules in
+ * lib/audit-sampling; sampled entries carry `sampleRate` in their details.
  */
 export function auditLog(params: {
   userId: string
   action: string
   resource: string
   resourceId?: string
-  details?: Record<string, string | number | boolean | null>
+  details?: AuditDetails
+  changes?: AuditChanges
   ipAddress: string
   userAgent?: string
   result: 'SUCCESS' | 'FAILURE' | 'DENIED'
 }): void {
-  prisma.auditLog
-    .create({
-      data: {
-        userId: params.userId,
-        action: params.action,
-        resource: params.resource,
-        resourceId: params.resourceId,
-        details: params.details ?? undefined,
-        ipAddress: params.ipAddress,
-        userAgent: params.userAgent,
-        result: params.result,
-      },
-    })
-    .catch((err) => {
-      console.error('Failed to write audit log:', err)
-    })
+  const sampleRate = sampleAudit(params.action, params.resource, params.result)
+  if (sampleRate === null) return
+
+  const hasChanges = !!params.changes && Object.keys(params.changes).length > 0
+  const baseDetails = sampleRate < 1 ? { ...params.details, sampleRate } : params.details
+  const details = hasChanges
+    ? ({ ...baseDetails, changes: params.changes } as Prisma.InputJsonValue)
+    : baseDetails
+
+  enqueueAudit({
+    userId: params.userId,
+    action: params.action,
+    resource: params.resource,
+    resourceId: params.resourceId,
+    details: details ?? undefined,
+    ipAddress: anonymizeIp(params.ipAddress),
+    userAgent: anonymizeUserAgent(params.userAgent),
+    result: params.result,
+  })
+}
+
+/**
+ * Write a high-severity entry (break-glass use and the like) straight to the
+ * database: never sampled, never left sitting in the queue. Tagged with
+ * `severity: "critical"` so reviews and SIEM exports can filter on it.
+ */
+export async function auditLogCritical(params: Omit<Parameters<typeof auditLog>[0], 'changes'>): Promise<void> {
+  await prisma.auditLog.create({
+    data: {
+      userId: params.userId,
+      action: params.action,
+      resource: params.resource,
+      resourceId: params.resourceId,
+      details: { ...params.details, severity: 'critical' },
+      ipAddress: anonymizeIp(params.ipAddress),
+      userAgent: anonymizeUserAgent(params.userAgent),
+      result: params.result,
+    },
+  })
+}
+
+/**
+ * Compute the fields that differ between two snapshots of a record.
+ * Only keys present in `after` are compared, so callers can pass the
+ * patch body directly. Secret fields record that they changed, never
+ * their values.
+ */
+export function diffForAudit(
+  before: Record<string, unknown>,
+  after: Record<string, unknown>,
+  secretFields: string[] = [],
+): AuditChanges {
+  const changes: AuditChanges = {}
+  for (const [field, next] of Object.entries(after)) {
+    if (next === undefined) continue
+    const prev = before[field]
+    if (JSON.stringify(prev ?? null) === JSON.stringify(next ?? null)) continue
+    changes[field] = secretFields.includes(field)
+      ? { before: REDACTED, after: REDACTED }
+      : { before: prev ?? null, after: next ?? null }
+  }
+  return changes
 }
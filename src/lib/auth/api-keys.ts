@@ -0,0 +1,98 @@
+import { createHash, randomBytes } from 'crypto'
+import { prisma } from '@/lib/db'
+import { stringList } from '@/lib/db-dialect'
+import { hasPermission, ROUTE_PERMISSIONS } from '@/lib/auth/permissions'
+import type { ApiKey, Role } from '@/generated/prisma'
+
+// API keys for machine callers (CI systems, bots): `Authorization: Bearer
+// tck_…` on any API route, no login or token refresh. Each key has its own
+// service account, so requests are attributed to the key in the audit log.
+//
+//   role    — the RBAC role requests run with; anyone but a SYSTEM_ADMIN can
+//             only issue keys with their own role (and department)
+//   scopes  — permission names (ROUTE_PERMISSIONS) the key is limited to,
+//             on top of its role; empty means everything the role grants
+//   expiry  — keys stop working at expiresAt, or once revoked
+//
+// Only the SHA-256 of a key is stored; the key is shown once, on creation.
+
+export const API_KEY_PREFIX = 'tck_'
+
+// lastUsedAt is written at most this often per key
+const LAST_USED_INTERVAL_MS = 60_000
+
+export function generateApiKey(): { key: string; keyHash: string; keyPrefix: string } {
+  const key = API_KEY_PREFIX + randomBytes(32).toString('base64url')
+  return { key, keyHash: hashApiKey(key), keyPrefix: key.slice(0, 12) }
+}
+
+export function hashApiKey(key: string): string {
+  return createHash('sha256').update(key).digest('hex')
+}
+
+export function isApiKey(token: string): boolean {
+  return token.startsWith(API_KEY_PREFIX)
+}
+
+export interface ApiKeyIdentity {
+  id: string
+  userId: string
+  role: Role
+  scopes: string[]
+}
+
+/** The key's identity, or null when it is unknown, revoked or expired */
+export async function authenticateApiKey(key: string, ipAddress: string): Promise<ApiKeyIdentity | null> {
+  const apiKey = await prisma.apiKey.findUnique({ where: { keyHash: hashApiKey(key) } })
+  if (!apiKey || apiKey.revokedAt) return null
+  if (apiKey.expiresAt && apiKey.expiresAt.getTime() <= Date.now()) return null
+
+  if (!apiKey.lastUsedAt || Date.now() - apiKey.lastUsedAt.getTime() > LAST_USED_INTERVAL_MS) {
+    prisma.apiKey
+      .update({ where: { id: apiKey.id }, data: { lastUsedAt: new Date(), lastUsedIp: ipAddress } })
+      .catch(() => {})
+  }
+
+  return { id: apiKey.id, userId: apiKey.serviceAccountId, role: apiKey.role, scopes: stringList(apiKey.scopes) }
+}
+
+/** Whether a key's scopes cover a permission (its role is checked separately) */
+export function scopesAllow(scopes: string[], permission: string): boolean {
+  return scopes.length === 0 || scopes.includes(permission)
+}
+
+/** Roles a user may issue keys with */
+export function canIssueRole(issuerRole: string, role: Role): boolean {
+  return issuerRole === 'SYSTEM_ADMIN' || issuerRole === role
+}
+
+/** Scopes that are unknown or that the role does not grant */
+export function invalidScopes(role: Role, scopes: string[]): string[] {
+  return scopes.filter((s) => !ROUTE_PERMISSIONS[s] || !hasPermission(role, s))
+}
+
+export type ApiKeyStatus = 'active' | 'expired' | 'revoked'
+
+export function apiKeyStatus(key: Pick<ApiKey, 'expiresAt' | 'revokedAt'>): ApiKeyStatus {
+  if (key.revokedAt) return 'revoked'
+  if (key.expiresAt && key.expiresAt.getTime() <= Date.now()) return 'expired'
+  return 'active'
+}
+
+export function toApiKeyResponse(key: ApiKey & { createdBy?: { name: string } | null }) {
+  return {
+    id: key.id,
+    name: key.name,
+    keyPrefix: key.keyPrefix,
+    role: key.role,
+    scopes: stringList(key.scopes),
+    status: apiKeyStatus(key),
+    createdById: key.createdById,
+    createdByName: key.createdBy?.name ?? null,
+    expiresAt: key.expiresAt?.toISOString() ?? null,
+    lastUsedAt: key.lastUsedAt?.toISOString() ?? null,
+    lastUsedIp: key.lastUsedIp,
+    revokedAt: key.revokedAt?.toISOString() ?? null,
+    createdAt: key.createdAt.toISOString(),
+  }
+}
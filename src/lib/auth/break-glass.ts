@@ -0,0 +1,122 @@
+import { prisma } from '@/lib/db'
+import { signAccessToken } from '@/lib/auth/jwt'
+import { auditLogCritical } from '@/lib/audit'
+import { notifyDepartment } from '@/lib/notifications'
+import { createLogger } from '@/lib/logger'
+import type { BreakGlassSession } from '@/generated/prisma'
+import type { AuthUser } from '@/types/auth'
+
+// "Break glass" emergency access. A permitted user states why, and gets an
+// access token that withAuth treats as SYSTEM_ADMIN until the session
+// expires or is ended — the user's stored role never changes, and other
+// sessions of the same user stay unprivileged. Activation is written to the
+// audit trail immediately (critical severity) and every other active
+// SYSTEM_ADMIN is alerted through their department's notification channels.
+//
+// BREAK_GLASS_MAX_MINUTES — longest window that can be requested (default 60)
+
+export const DEFAULT_BREAK_GLASS_MINUTES = 30
+
+const log = createLogger('break-glass')
+
+export function maxBreakGlassMinutes(): number {
+  const n = parseInt(process.env.BREAK_GLASS_MAX_MINUTES ?? '', 10)
+  return Number.isFinite(n) && n > 0 ? n : 60
+}
+
+/** The session behind a break-glass token, if it is still in force for this user */
+export function findActiveBreakGlass(id: string, userId: string): Promise<BreakGlassSession | null> {
+  return prisma.breakGlassSession.findFirst({
+    where: { id, userId, endedAt: null, expiresAt: { gt: new Date() } },
+  })
+}
+
+/** Alert every other active SYSTEM_ADMIN; never throws */
+async function alertAdmins(user: AuthUser, session: BreakGlassSession): Promise<void> {
+  const admins = await prisma.user.findMany({
+    where: { role: 'SYSTEM_ADMIN', status: 'ACTIVE', id: { not: user.id }, departmentId: { not: null } },
+    select: { departmentId: true },
+  })
+  const departmentIds = [...new Set(admins.map((a) => a.departmentId!))]
+  const until = session.expiresAt.toISOString().slice(0, 16).replace('T', ' ')
+
+  await Promise.allSettled(
+    departmentIds.map((id) =>
+      notifyDepartment(id, {
+        title: `Break-glass access activated by ${user.name}`,
+        text:
+          `${user.name} (${user.email}) has emergency SYSTEM_ADMIN access until ${until} UTC.\n` +
+          `Justification: ${session.justification}\n` +
+          `If this is unexpected, end it with DELETE /api/v1/admin/break-glass/${session.id}.`,
+        source: `break_glass:${session.id}`,
+      }),
+    ),
+  )
+}
+
+/** Open a session and return the elevated access token for it */
+export async function activateBreakGlass(
+  user: AuthUser,
+  justification: string,
+  minutes: number,
+  request: { ipAddress: string; userAgent?: string },
+): Promise<{ session: BreakGlassSession; token: string }> {
+  const session = await prisma.breakGlassSession.create({
+    data: {
+      userId: user.id,
+      justification,
+      expiresAt: new Date(Date.now() + minutes * 60_000),
+      ipAddress: request.ipAddress,
+    },
+  })
+
+  await auditLogCritical({
+    userId: user.id,
+    action: 'BREAK_GLASS_ACTIVATE',
+    resource: 'auth',
+    resourceId: session.id,
+    details: {
+      justification,
+      minutes,
+      role: user.role,
+      expiresAt: session.expiresAt.toISOString(),
+    },
+    ipAddress: request.ipAddress,
+    userAgent: request.userAgent,
+    result: 'SUCCESS',
+  })
+  log.warn('Break-glass access activated', { userId: user.id, sessionId: session.id, minutes })
+
+  void alertAdmins(user, session).catch((err) => log.error('Break-glass alert failed', { err }))
+
+  const token = await signAccessToken(
+    { userId: user.id, role: 'SYSTEM_ADMIN', breakGlassId: session.id },
+    session.expiresAt,
+  )
+  return { session, token }
+}
+
+/** End a session early. Returns false if it had already ended or expired. */
+export async function endBreakGlass(
+  session: BreakGlassSession,
+  endedById: string,
+  request: { ipAddress: string; userAgent?: string },
+): Promise<boolean> {
+  const { count } = await prisma.breakGlassSession.updateMany({
+    where: { id: session.id, endedAt: null, expiresAt: { gt: new Date() } },
+    data: { endedAt: new Date(), endedById },
+  })
+  if (count === 0) return false
+
+  await auditLogCritical({
+    userId: endedById,
+    action: 'BREAK_GLASS_END',
+    resource: 'auth',
+    resourceId: session.id,
+    details: { sessionUserId: session.userId, self: endedById === session.userId },
+    ipAddress: request.ipAddress,
+    userAgent: request.userAgent,
+    result: 'SUCCESS',
+  })
+  return true
+}
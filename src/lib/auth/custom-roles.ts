@@ -0,0 +1,52 @@
+import { Role } from '@/generated/prisma'
+import { stringList } from '@/lib/db-dialect'
+import { ROUTE_PERMISSIONS, isGrantablePermission } from './permissions'
+import type { CustomRole } from '@/generated/prisma'
+
+// Custom roles: named sets of permissions an admin defines beyond the
+// built-in roles, e.g. an AUDITOR that may read the audit log and nothing
+// else. A user keeps their built-in role and may be assigned one custom role
+// on top; withPermission (userHasPermission) grants what either allows.
+// Assignments take effect on the user's next request.
+//
+// Department-scoped permissions cannot be granted (isGrantablePermission):
+// resource checks and handlers narrow to the caller's department only when
+// the caller is a DEPT_ADMIN, so any permission DEPT_ADMIN holds would give
+// a custom role organisation-wide data. API keys never carry the custom role
+// of their owner.
+
+const BUILT_IN_ROLES = new Set<string>(Object.values(Role))
+
+/** Permissions a custom role can be given, sorted */
+export function grantablePermissions(): string[] {
+  return Object.keys(ROUTE_PERMISSIONS).filter(isGrantablePermission).sort()
+}
+
+/** Why the permission list is unusable, or null */
+export function checkRolePermissions(permissions: string[]): string | null {
+  const unknown = permissions.filter((p) => !ROUTE_PERMISSIONS[p])
+  if (unknown.length > 0) return `Unknown permissions: ${unknown.join(', ')}`
+  const withheld = permissions.filter((p) => !isGrantablePermission(p))
+  if (withheld.length > 0) return `Permissions that cannot be granted by a custom role: ${withheld.join(', ')}`
+  return null
+}
+
+export function isBuiltInRoleName(name: string): boolean {
+  return BUILT_IN_ROLES.has(name)
+}
+
+export function toCustomRoleResponse(
+  r: CustomRole & { createdBy?: { name: string } | null; _count?: { users: number } },
+) {
+  return {
+    id: r.id,
+    name: r.name,
+    description: r.description,
+    permissions: stringList(r.permissions).sort(),
+    userCount: r._count?.users ?? null,
+    createdById: r.createdById,
+    createdByName: r.createdBy?.name ?? null,
+    createdAt: r.createdAt.toISOString(),
+    updatedAt: r.updatedAt.toISOString(),
+  }
+}
@@ -0,0 +1,90 @@
+import { createCipheriv, createDecipheriv, randomBytes } from 'crypto'
+import { prisma } from '@/lib/db'
+import { registerCache } from '@/lib/caches'
+import { encrypt, decrypt } from './encryption'
+
+// Envelope encryption: each department gets its own AES-256-GCM data key,
+// stored wrapped with the master ENCRYPTION_KEY. Rotating the master key
+// only requires re-wrapping DataKey rows, not re-encrypting the data.
+
+const ALGORITHM = 'aes-256-gcm'
+
+const globalForDataKeys = globalThis as unknown as {
+  dataKeys?: Map<string, Buffer>
+  activeDataKeyIds?: Map<string, string>
+}
+
+const keyCache = (globalForDataKeys.dataKeys ??= new Map())
+const activeKeyIds = (globalForDataKeys.activeDataKeyIds ??= new Map())
+
+// Keys are listed by DataKey id only; clearing forces a re-unwrap from the DB
+const keyCounter = registerCache('data-keys', {
+  description: 'Unwrapped department data keys by id',
+  keys: () => [...keyCache.keys()],
+  clear: (id) => {
+    if (id) {
+      keyCache.delete(id)
+      for (const [dept, activeId] of activeKeyIds) if (activeId === id) activeKeyIds.delete(dept)
+    } else {
+      keyCache.clear()
+      activeKeyIds.clear()
+    }
+  },
+})
+
+function unwrap(wrappedKey: string): Buffer {
+  return Buffer.from(decrypt(wrappedKey), 'hex')
+}
+
+/** Resolve a data key by id (for decryption; retired keys included) */
+export async function getDataKey(id: string): Promise<Buffer> {
+  const cached = keyCache.get(id)
+  if (cached) {
+    keyCounter.hit()
+    return cached
+  }
+  keyCounter.miss()
+  const row = await prisma.dataKey.findUnique({ where: { id } })
+  if (!row) throw new Error(`Data key ${id} not found`)
+  const key = unwrap(row.wrappedKey)
+  keyCache.set(id, key)
+  return key
+}
+
+/** The key new data for a department is sealed with; created on first use */
+export async function getActiveDataKey(departmentId: string | null): Promise<{ id: string; key: Buffer }> {
+  const cacheKey = departmentId ?? ''
+  const cachedId = activeKeyIds.get(cacheKey)
+  if (cachedId) return { id: cachedId, key: await getDataKey(cachedId) }
+
+  let row = await prisma.dataKey.findFirst({
+    where: { departmentId, retiredAt: null },
+    orderBy: { createdAt: 'desc' },
+  })
+  if (!row) {
+    row = await prisma.dataKey.create({
+      data: { departmentId, wrappedKey: encrypt(randomBytes(32).toString('hex')) },
+    })
+  }
+
+  const key = unwrap(row.wrappedKey)
+  keyCache.set(row.id, key)
+  activeKeyIds.set(cacheKey, row.id)
+  return { id: row.id, key }
+}
+
+/** Encrypt with a data key → "iv:tag:ciphertext" (base64) */
+export function sealData(key: Buffer, plaintext: string): string {
+  const iv = randomBytes(12)
+  const cipher = createCipheriv(ALGORITHM, key, iv)
+  const ciphertext = Buffer.concat([cipher.update(plaintext, 'utf8'), cipher.final()])
+  return `${iv.toString('base64')}:${cipher.getAuthTag().toString('base64')}:${ciphertext.toString('base64')}`
+}
+
+export function openData(key: Buffer, sealed: string): string {
+  const [iv, tag, ciphertext] = sealed.split(':')
+  if (!iv || !tag || ciphertext === undefined) throw new Error('Invalid sealed format')
+  const decipher = createDecipheriv(ALGORITHM, key, Buffer.from(iv, 'base64'))
+  decipher.setAuthTag(Buffer.from(tag, 'base64'))
+  return Buffer.concat([decipher.update(Buffer.from(ciphertext, 'base64')), decipher.final()]).toString('utf8')
+}
@@ -4,6 +4,7 @@ const ALG = 'RS256'
 const ISSUER = 'teamclaw'
 const ACCESS_EXPIRY = '180m'
 const REFRESH_EXPIRY = '7d'
+const MFA_EXPIRY = '5m'
 
 let privateKey: CryptoKey | null = null
 let publicKey: CryptoKey | null = null
@@ -26,16 +27,25 @@ async function getPublicKey(): Promise<CryptoKey> {
   return publicKey
 }
 
-export async function signAccessToken(payload: {
-  userId: string
-  role: string
-}): Promise<string> {
+export async function signAccessToken(
+  payload: {
+    userId: string
+    role: string
+    /** Break-glass session the token elevates (see lib/auth/break-glass) */
+    breakGlassId?: string
+  },
+  expiresAt?: Date,
+): Promise<string> {
   const key = await getPrivateKey()
-  return new SignJWT({ userId: payload.userId, role: payload.role })
+  return new SignJWT({
+    userId: payload.userId,
+    role: payload.role,
+    ...(payload.breakGlassId ? { breakGlassId: payload.breakGlassId } : {}),
+  })
     .setProtectedHeader({ alg: ALG })
     .setIssuer(ISSUER)
     .setIssuedAt()
-    .setExpirationTime(ACCESS_EXPIRY)
+    .setExpirationTime(expiresAt ?? ACCESS_EXPIRY)
     .sign(key)
 }
 
@@ -49,14 +59,43 @@ export async function signRefreshToken(userId: string): Promise<string> {
     .sign(key)
 }
 
+/**
+ * Intermediate token after a correct password when the account has MFA on:
+ * only /auth/mfa/verify accepts it, in exchange for the access / refresh pair.
+ */
+export async function signMfaToken(userId: string): Promise<string> {
+  const key = await getPrivateKey()
+  return new SignJWT({ userId, purpose: 'mfa' })
+    .setProtectedHeader({ alg: ALG })
+    .setIssuer(ISSUER)
+    .setIssuedAt()
+    .setExpirationTime(MFA_EXPIRY)
+    .sign(key)
+}
+
+export async function verifyMfaToken(token: string): Promise<{ userId: string } | null> {
+  try {
+    const key = await getPublicKey()
+    const { payload } = await jwtVerify(token, key, { issuer: ISSUER })
+    if (!payload.userId || payload.purpose !== 'mfa') return null
+    return { userId: payload.userId as string }
+  } catch {
+    return null
+  }
+}
+
 export async function verifyAccessToken(
   token: string
-): Promise<{ userId: string; role: string } | null> {
+): Promise<{ userId: string; role: string; breakGlassId?: string } | null> {
   try {
     const key = await getPublicKey()
     const { payload } = await jwtVerify(token, key, { issuer: ISSUER })
-    if (!payload.userId || !payload.role) return null
-    return { userId: payload.userId as string, role: payload.role as string }
+    if (!payload.userId || !payload.role || payload.purpose) return null
+    return {
+      userId: payload.userId as string,
+      role: payload.role as string,
+      breakGlassId: (payload.breakGlassId as string | undefined) || undefined,
+    }
   } catch {
     return null
   }
@@ -68,7 +107,7 @@ export async function verifyRefreshToken(
   try {
     const key = await getPublicKey()
     const { payload } = await jwtVerify(token, key, { issuer: ISSUER })
-    if (!payload.userId) return null
+    if (!payload.userId || payload.purpose) return null
     return { userId: payload.userId as string }
   } catch {
     return null
@@ -0,0 +1,276 @@
+import net from 'net'
+import tls from 'tls'
+
+// Minimal LDAPv3 client (RFC 4511): simple bind and subtree search, which is
+// all directory login needs. Speaks BER directly over ldap:// or ldaps://.
+
+// ─── BER encoding ───────────────────────────────────────────────────
+
+function encodeLength(length: number): Buffer {
+  if (length < 0x80) return Buffer.from([length])
+  const bytes: number[] = []
+  for (let n = length; n > 0; n >>= 8) bytes.unshift(n & 0xff)
+  return Buffer.from([0x80 | bytes.length, ...bytes])
+}
+
+function tlv(tag: number, value: Buffer): Buffer {
+  return Buffer.concat([Buffer.from([tag]), encodeLength(value.length), value])
+}
+
+const seq = (tag: number, ...items: Buffer[]) => tlv(tag, Buffer.concat(items))
+const str = (value: string, tag = 0x04) => tlv(tag, Buffer.from(value, 'utf8'))
+const bool = (value: boolean) => tlv(0x01, Buffer.from([value ? 0xff : 0x00]))
+
+function int(value: number, tag = 0x02): Buffer {
+  const bytes: number[] = []
+  let n = value
+  do {
+    bytes.unshift(n & 0xff)
+    n >>= 8
+  } while (n > 0)
+  if (bytes[0] & 0x80) bytes.unshift(0) // keep it positive
+  return tlv(tag, Buffer.from(bytes))
+}
+
+// ─── BER decoding ───────────────────────────────────────────────────
+
+interface Element {
+  tag: number
+  value: Buffer
+  /** Bytes consumed including the header */
+  size: number
+}
+
+/** One element at `offset`, or null while the buffer holds only part of it */
+function readElement(buf: Buffer, offset = 0): Element | null {
+  if (buf.length < offset + 2) return null
+  const tag = buf[offset]
+  let length = buf[offset + 1]
+  let header = 2
+  if (length & 0x80) {
+    const count = length & 0x7f
+    if (count === 0 || count > 4) throw new Error('Unsupported BER length')
+    if (buf.length < offset + 2 + count) return null
+    length = 0
+    for (let i = 0; i < count; i++) length = length * 256 + buf[offset + 2 + i]
+    header += count
+  }
+  if (buf.length < offset + header + length) return null
+  return { tag, value: buf.subarray(offset + header, offset + header + length), size: header + length }
+}
+
+function children(value: Buffer): Element[] {
+  const out: Element[] = []
+  for (let offset = 0; offset < value.length; ) {
+    const el = readElement(value, offset)
+    if (!el) throw new Error('Truncated BER element')
+    out.push(el)
+    offset += el.size
+  }
+  return out
+}
+
+function readInt(value: Buffer): number {
+  let n = 0
+  for (const b of value) n = n * 256 + b
+  return n
+}
+
+// ─── Filters (RFC 4515) ─────────────────────────────────────────────
+
+/** Escape a value for use inside a filter string */
+export function escapeFilterValue(value: string): string {
+  return value.replace(/[\\*()\0]/g, (c) => `\\${c.charCodeAt(0).toString(16).padStart(2, '0')}`)
+}
+
+function unescapeFilterValue(value: string): Buffer {
+  const bytes: number[] = []
+  for (let i = 0; i < value.length; i++) {
+    if (value[i] === '\\') {
+      bytes.push(parseInt(value.slice(i + 1, i + 3), 16))
+      i += 2
+    } else {
+      bytes.push(...Buffer.from(value[i], 'utf8'))
+    }
+  }
+  return Buffer.from(bytes)
+}
+
+/** Encode a filter string; supports &, |, !, equality and presence (attr=*) */
+export function encodeFilter(filter: string): Buffer {
+  let pos = 0
+
+  const parse = (): Buffer => {
+    if (filter[pos] !== '(') throw new Error(`Invalid LDAP filter at ${pos}: ${filter}`)
+    pos++
+    const op = filter[pos]
+    let out: Buffer
+    if (op === '&' || op === '|') {
+      pos++
+      const parts: Buffer[] = []
+      while (filter[pos] === '(') parts.push(parse())
+      out = seq(op === '&' ? 0xa0 : 0xa1, ...parts)
+    } else if (op === '!') {
+      pos++
+      out = seq(0xa2, parse())
+    } else {
+      const end = filter.indexOf(')', pos)
+      if (end < 0) throw new Error(`Unterminated LDAP filter: ${filter}`)
+      const item = filter.slice(pos, end)
+      const eq = item.indexOf('=')
+      if (eq <= 0) throw new Error(`Invalid LDAP filter item: ${item}`)
+      const attr = item.slice(0, eq)
+      const value = item.slice(eq + 1)
+      if (value === '*') out = str(attr, 0x87)
+      else if (value.includes('*')) throw new Error('Substring filters are not supported')
+      else out = seq(0xa3, str(attr), tlv(0x04, unescapeFilterValue(value)))
+      pos = end
+    }
+    if (filter[pos] !== ')') throw new Error(`Invalid LDAP filter at ${pos}: ${filter}`)
+    pos++
+    return out
+  }
+
+  const encoded = parse()
+  if (pos !== filter.length) throw new Error(`Trailing characters in LDAP filter: ${filter}`)
+  return encoded
+}
+
+// ─── Client ─────────────────────────────────────────────────────────
+
+export interface LdapEntry {
+  dn: string
+  attributes: Record<string, string[]>
+}
+
+export class LdapError extends Error {
+  constructor(
+    message: string,
+    public readonly resultCode?: number,
+  ) {
+    super(message)
+    this.name = 'LdapError'
+  }
+}
+
+/** LDAP result code for a wrong DN / password */
+export const INVALID_CREDENTIALS = 49
+
+interface Pending {
+  resolve: (ops: Element[]) => void
+  reject: (err: Error) => void
+  ops: Element[]
+  /** Protocol op tag that ends the response */
+  doneTag: number
+}
+
+export class LdapClient {
+  private buffer = Buffer.alloc(0)
+  private nextId = 1
+  private pending = new Map<number, Pending>()
+
+  private constructor(private readonly socket: net.Socket) {
+    socket.on('data', (chunk) => this.onData(chunk))
+    socket.on('error', (err) => this.failAll(err))
+    socket.on('close', () => this.failAll(new LdapError('LDAP connection closed')))
+  }
+
+  static connect(url: string, opts: { timeoutMs: number; rejectUnauthorized: boolean }): Promise<LdapClient> {
+    const parsed = new URL(url)
+    const secure = parsed.protocol === 'ldaps:'
+    if (!secure && parsed.protocol !== 'ldap:') throw new LdapError(`Unsupported LDAP URL: ${url}`)
+    const port = Number(parsed.port) || (secure ? 636 : 389)
+
+    return new Promise((resolve, reject) => {
+      const socket = secure
+        ? tls.connect({ host: parsed.hostname, port, servername: parsed.hostname, rejectUnauthorized: opts.rejectUnauthorized })
+        : net.connect({ host: parsed.hostname, port })
+      socket.setTimeout(opts.timeoutMs, () => socket.destroy(new LdapError('LDAP request timed out')))
+      socket.once(secure ? 'secureConnect' : 'connect', () => resolve(new LdapClient(socket)))
+      socket.once('error', reject)
+    })
+  }
+
+  /** Simple bind; rejects with LdapError(resultCode 49) on bad credentials */
+  async bind(dn: string, password: string): Promise<void> {
+    // An empty password is an unauthenticated bind, which servers accept
+    if (!password) throw new LdapError('Empty password', INVALID_CREDENTIALS)
+    const [res] = await this.request(seq(0x60, int(3), str(dn), str(password, 0x80)), 0x61)
+    checkResult(res)
+  }
+
+  async search(baseDn: string, filter: string, attributes: string[], sizeLimit = 2): Promise<LdapEntry[]> {
+    const op = seq(
+      0x63,
+      str(baseDn),
+      int(2, 0x0a), // wholeSubtree
+      int(0, 0x0a), // neverDerefAliases
+      int(sizeLimit),
+      int(10), // time limit, seconds
+      bool(false),
+      encodeFilter(filter),
+      seq(0x30, ...attributes.map((a) => str(a))),
+    )
+    const ops = await this.request(op, 0x65)
+    checkResult(ops[ops.length - 1])
+
+    return ops
+      .filter((el) => el.tag === 0x64)
+      .map((el) => {
+        const [name, attrs] = children(el.value)
+        const attributes: Record<string, string[]> = {}
+        for (const attr of children(attrs.value)) {
+          const [type, vals] = children(attr.value)
+          attributes[type.value.toString('utf8').toLowerCase()] = children(vals.value).map((v) => v.value.toString('utf8'))
+        }
+        return { dn: name.value.toString('utf8'), attributes }
+      })
+  }
+
+  close(): void {
+    if (this.socket.destroyed) return
+    this.socket.end(seq(0x30, int(this.nextId++), Buffer.from([0x42, 0x00]))) // UnbindRequest
+  }
+
+  private request(op: Buffer, doneTag: number): Promise<Element[]> {
+    const id = this.nextId++
+    return new Promise((resolve, reject) => {
+      this.pending.set(id, { resolve, reject, ops: [], doneTag })
+      this.socket.write(seq(0x30, int(id), op))
+    })
+  }
+
+  private onData(chunk: Buffer): void {
+    this.buffer = Buffer.concat([this.buffer, chunk])
+    try {
+      for (let el = readElement(this.buffer); el; el = readElement(this.buffer)) {
+        this.buffer = this.buffer.subarray(el.size)
+        const [id, op] = children(el.value)
+        const pending = this.pending.get(readInt(id.value))
+        if (!pending) continue
+        pending.ops.push(op)
+        if (op.tag === pending.doneTag) {
+          this.pending.delete(readInt(id.value))
+          pending.resolve(pending.ops)
+        }
+      }
+    } catch (err) {
+      this.socket.destroy(err as Error)
+    }
+  }
+
+  private failAll(err: Error): void {
+    for (const pending of this.pending.values()) pending.reject(err)
+    this.pending.clear()
+  }
+}
+
+/** Throw unless an LDAPResult carries resultCode success (0) */
+function checkResult(op: Element | undefined): void {
+  if (!op) throw new LdapError('Empty LDAP response')
+  const [code, , message] = children(op.value)
+  const resultCode = readInt(code.value)
+  if (resultCode !== 0) {
+    throw new LdapError(message?.value.toString('utf8') || `LDAP error ${resultCode}`, resultCode)
+  }
+}
@@ -0,0 +1,264 @@
+import { randomBytes } from 'crypto'
+import { prisma } from '@/lib/db'
+import { hashPassword } from '@/lib/auth/password'
+import { enforceLicenseLimit } from '@/lib/license'
+import { applyDepartmentChange } from '@/lib/users/department-change'
+import { createLogger } from '@/lib/logger'
+import { LdapClient, LdapError, INVALID_CREDENTIALS, escapeFilterValue } from '@/lib/auth/ldap-client'
+import type { Role, User, Department } from '@/generated/prisma'
+
+// LDAP / Active Directory login. With LDAP_ENABLED=true the login route
+// looks the email up in the directory first:
+//
+//   found     → the password is checked by binding as the entry's DN; the
+//               local account is created or updated from the entry (name,
+//               department, role) and marked directory-managed (ldapDn)
+//   not found → the local password flow, so local-only accounts such as the
+//               initial admin keep working; a directory-managed account that
+//               has left the directory is refused
+//
+// While the directory is unreachable only local-only accounts can sign in.
+// With LDAP disabled everyone uses the local password flow.
+//
+// LDAP_URL                  — ldap://host:389 or ldaps://host:636
+// LDAP_BIND_DN / _PASSWORD  — service account used for the lookup (empty:
+//                             anonymous search)
+// LDAP_BASE_DN              — search base, e.g. "DC=corp,DC=example,DC=com"
+// LDAP_USER_FILTER          — {email} is replaced by the escaped login email
+//                             (default "(|(mail={email})(userPrincipalName={email}))")
+// LDAP_NAME_ATTRIBUTE       — display name (default "displayName")
+// LDAP_DEPARTMENT_ATTRIBUTE — matched against department names, case-
+//                             insensitively (default "department"; empty
+//                             leaves departments alone)
+// LDAP_ROLE_GROUPS          — JSON map of group DN or CN → role, e.g.
+//                             {"TeamClaw Admins":"SYSTEM_ADMIN"}; the highest
+//                             matching role wins, LDAP_DEFAULT_ROLE otherwise.
+//                             Unset leaves roles to TeamClaw.
+// LDAP_DEFAULT_ROLE         — default USER
+// LDAP_AUTO_PROVISION       — create accounts on first login (default true)
+// LDAP_TLS_REJECT_UNAUTHORIZED — verify ldaps:// certificates (default true)
+// LDAP_TIMEOUT_MS           — per-connection timeout (default 5000)
+
+const log = createLogger('auth:ldap')
+
+// Highest privilege first
+const ROLE_ORDER: Role[] = ['SYSTEM_ADMIN', 'DEPT_ADMIN', 'USER', 'VIEWER']
+
+export interface LdapConfig {
+  url: string
+  bindDn: string
+  bindPassword: string
+  baseDn: string
+  userFilter: string
+  nameAttribute: string
+  departmentAttribute: string
+  roleGroups: Map<string, Role> | null
+  defaultRole: Role
+  autoProvision: boolean
+  rejectUnauthorized: boolean
+  timeoutMs: number
+}
+
+function parseRoleGroups(raw: string | undefined): Map<string, Role> | null {
+  if (!raw) return null
+  try {
+    const groups = new Map<string, Role>()
+    for (const [group, role] of Object.entries(JSON.parse(raw) as Record<string, string>)) {
+      if (ROLE_ORDER.includes(role as Role)) groups.set(group.toLowerCase(), role as Role)
+      else log.warn('Ignoring LDAP_ROLE_GROUPS entry with unknown role', { group, role })
+    }
+    return groups
+  } catch {
+    log.error('LDAP_ROLE_GROUPS is not valid JSON; roles are not synced')
+    return null
+  }
+}
+
+export function getLdapConfig(): LdapConfig | null {
+  if (process.env.LDAP_ENABLED !== 'true') return null
+  const url = process.env.LDAP_URL
+  const baseDn = process.env.LDAP_BASE_DN
+  if (!url || !baseDn) {
+    log.error('LDAP_ENABLED is set but LDAP_URL or LDAP_BASE_DN is missing')
+    return null
+  }
+  const role = process.env.LDAP_DEFAULT_ROLE as Role | undefined
+  const timeout = parseInt(process.env.LDAP_TIMEOUT_MS ?? '', 10)
+  return {
+    url,
+    bindDn: process.env.LDAP_BIND_DN ?? '',
+    bindPassword: process.env.LDAP_BIND_PASSWORD ?? '',
+    baseDn,
+    userFilter: process.env.LDAP_USER_FILTER || '(|(mail={email})(userPrincipalName={email}))',
+    nameAttribute: (process.env.LDAP_NAME_ATTRIBUTE || 'displayName').toLowerCase(),
+    departmentAttribute: (process.env.LDAP_DEPARTMENT_ATTRIBUTE ?? 'department').toLowerCase(),
+    roleGroups: parseRoleGroups(process.env.LDAP_ROLE_GROUPS),
+    defaultRole: role && ROLE_ORDER.includes(role) ? role : 'USER',
+    autoProvision: process.env.LDAP_AUTO_PROVISION !== 'false',
+    rejectUnauthorized: process.env.LDAP_TLS_REJECT_UNAUTHORIZED !== 'false',
+    timeoutMs: Number.isFinite(timeout) && timeout > 0 ? timeout : 5000,
+  }
+}
+
+// ─── Authentication ─────────────────────────────────────────────────
+
+export interface DirectoryUser {
+  dn: string
+  name: string | null
+  department: string | null
+  groups: string[]
+}
+
+export type DirectoryAuthResult =
+  | { status: 'ok'; entry: DirectoryUser }
+  | { status: 'invalid' }
+  | { status: 'not_found' }
+
+/** Look the email up and bind as the entry. Throws when the directory cannot be reached. */
+export async function authenticateWithDirectory(
+  config: LdapConfig,
+  email: string,
+  password: string,
+): Promise<DirectoryAuthResult> {
+  const client = await LdapClient.connect(config.url, {
+    timeoutMs: config.timeoutMs,
+    rejectUnauthorized: config.rejectUnauthorized,
+  })
+  try {
+    if (config.bindDn) await client.bind(config.bindDn, config.bindPassword)
+
+    const attributes = ['memberOf', config.nameAttribute]
+    if (config.departmentAttribute) attributes.push(config.departmentAttribute)
+    const entries = await client.search(
+      config.baseDn,
+      config.userFilter.replaceAll('{email}', escapeFilterValue(email)),
+      attributes,
+    )
+    if (entries.length === 0) return { status: 'not_found' }
+    if (entries.length > 1) {
+      log.warn('LDAP filter matched several entries; refusing login', { email, count: entries.length })
+      return { status: 'invalid' }
+    }
+
+    const [entry] = entries
+    try {
+      await client.bind(entry.dn, password)
+    } catch (err) {
+      if (err instanceof LdapError && err.resultCode === INVALID_CREDENTIALS) return { status: 'invalid' }
+      throw err
+    }
+
+    const first = (attr: string) => entry.attributes[attr]?.[0]?.trim() || null
+    return {
+      status: 'ok',
+      entry: {
+        dn: entry.dn,
+        name: first(config.nameAttribute),
+        department: config.departmentAttribute ? first(config.departmentAttribute) : null,
+        groups: entry.attributes['memberof'] ?? [],
+      },
+    }
+  } finally {
+    client.close()
+  }
+}
+
+/** Re-check a directory-managed user's password (e.g. break-glass re-authentication) */
+export async function verifyDirectoryPassword(config: LdapConfig, dn: string, password: string): Promise<boolean> {
+  const client = await LdapClient.connect(config.url, {
+    timeoutMs: config.timeoutMs,
+    rejectUnauthorized: config.rejectUnauthorized,
+  })
+  try {
+    await client.bind(dn, password)
+    return true
+  } catch (err) {
+    if (err instanceof LdapError && err.resultCode === INVALID_CREDENTIALS) return false
+    throw err
+  } finally {
+    client.close()
+  }
+}
+
+// ─── Account sync ───────────────────────────────────────────────────
+
+/** Role for the entry's groups, or null when roles are not synced */
+function roleForGroups(config: LdapConfig, groups: string[]): Role | null {
+  if (!config.roleGroups) return null
+  const matched = new Set<Role>()
+  for (const dn of groups) {
+    const cn = /^cn=([^,]+)/i.exec(dn)?.[1]
+    const role = config.roleGroups.get(dn.toLowerCase()) ?? (cn ? config.roleGroups.get(cn.toLowerCase()) : undefined)
+    if (role) matched.add(role)
+  }
+  return ROLE_ORDER.find((r) => matched.has(r)) ?? config.defaultRole
+}
+
+async function departmentIdFor(name: string | null): Promise<string | null | undefined> {
+  if (!name) return undefined
+  // Few departments, and case-insensitive equality differs per database dialect
+  const departments = await prisma.department.findMany({ select: { id: true, name: true } })
+  const department = departments.find((d) => d.name.toLowerCase() === name.toLowerCase())
+  if (!department) log.warn('Directory department has no TeamClaw department', { department: name })
+  return department?.id
+}
+
+export type DirectoryAccount = User & { department: Department | null }
+
+/**
+ * Create or update the local account for an authenticated directory entry.
+ * `synced` lists the fields that changed, for the login audit entry.
+ */
+export async function syncDirectoryUser(
+  config: LdapConfig,
+  email: string,
+  entry: DirectoryUser,
+): Promise<{ user: DirectoryAccount; created: boolean; synced: string[] } | { error: string }> {
+  const include = { department: true } as const
+  const role = roleForGroups(config, entry.groups)
+  const departmentId = config.departmentAttribute ? await departmentIdFor(entry.department) : undefined
+
+  const existing =
+    (await prisma.user.findUnique({ where: { ldapDn: entry.dn }, include })) ??
+    (await prisma.user.findUnique({ where: { email }, include }))
+
+  if (!existing) {
+    if (!config.autoProvision) return { error: 'No account exists for this directory user' }
+    if (await enforceLicenseLimit('seat')) return { error: 'License seat limit reached' }
+    const user = await prisma.user.create({
+      data: {
+        email,
+        name: (entry.name ?? email.split('@')[0]).slice(0, 50),
+        passwordHash: await hashPassword(randomBytes(32).toString('hex')),
+        role: role ?? config.defaultRole,
+        departmentId: departmentId ?? null,
+        ldapDn: entry.dn,
+      },
+      include,
+    })
+    return { user, created: true, synced: [] }
+  }
+
+  if (existing.isServiceAccount) return { error: 'Service accounts cannot sign in' }
+
+  const data: { name?: string; ldapDn?: string; role?: Role; baseRole?: Role; departmentId?: string } = {}
+  if (existing.ldapDn !== entry.dn) data.ldapDn = entry.dn
+  if (entry.name && entry.name.slice(0, 50) !== existing.name) data.name = entry.name.slice(0, 50)
+  if (role) {
+    // During a temporary elevation the directory role is where it reverts to
+    if (existing.roleExpiresAt) {
+      if (existing.baseRole !== role) data.baseRole = role
+    } else if (existing.role !== role) {
+      data.role = role
+    }
+  }
+  if (departmentId && departmentId !== existing.departmentId) data.departmentId = departmentId
+
+  const synced = Object.keys(data).filter((k) => k !== 'ldapDn')
+  if (Object.keys(data).length === 0) return { user: existing, created: false, synced }
+
+  const user = await prisma.user.update({ where: { id: existing.id }, data, include })
+  if (data.departmentId) await applyDepartmentChange(user.id, existing.departmentId)
+  if (synced.length > 0) log.info('Synced account from directory', { userId: user.id, fields: synced })
+  return { user, created: false, synced }
+}
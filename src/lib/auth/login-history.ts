@@ -0,0 +1,131 @@
+/**
+ * Login history helpers: user-agent parsing and optional geo-IP enrichment.
+ */
+
+import { isIP } from 'net'
+import { registerCache } from '@/lib/caches'
+import type { DeviceInfo, GeoLocation } from '@/types/user'
+
+/** Pluggable geo-IP lookup. Return null when the IP cannot be resolved. */
+export interface GeoIpResolver {
+  lookup(ip: string): Promise<GeoLocation | null>
+}
+
+const BROWSERS: [RegExp, string][] = [
+  [/Edg(?:e|A|iOS)?\/([\d.]+)/, 'Edge'],
+  [/OPR\/([\d.]+)/, 'Opera'],
+  [/Firefox\/([\d.]+)/, 'Firefox'],
+  [/Chrome\/([\d.]+)/, 'Chrome'],
+  [/Version\/([\d.]+).*Safari/, 'Safari'],
+  [/curl\/([\d.]+)/, 'curl'],
+]
+
+const OPERATING_SYSTEMS: [RegExp, string][] = [
+  [/Windows NT/, 'Windows'],
+  [/iPhone|iPad|iPod/, 'iOS'],
+  [/Mac OS X/, 'macOS'],
+  [/Android/, 'Android'],
+  [/CrOS/, 'ChromeOS'],
+  [/Linux/, 'Linux'],
+]
+
+export function parseUserAgent(ua: string | null | undefined): DeviceInfo {
+  if (!ua) return { browser: 'Unknown', os: 'Unknown', deviceType: 'unknown' }
+
+  let browser = 'Unknown'
+  for (const [re, name] of BROWSERS) {
+    const m = ua.match(re)
+    if (m) {
+      browser = `${name} ${m[1].split('.')[0]}`
+      break
+    }
+  }
+
+  const os = OPERATING_SYSTEMS.find(([re]) => re.test(ua))?.[1] ?? 'Unknown'
+
+  let deviceType: DeviceInfo['deviceType'] = 'desktop'
+  if (/bot|crawler|spider/i.test(ua)) deviceType = 'bot'
+  else if (/iPad|Tablet/.test(ua) || (/Android/.test(ua) && !/Mobile/.test(ua))) deviceType = 'tablet'
+  else if (/Mobi|iPhone|Android/.test(ua)) deviceType = 'mobile'
+
+  return { browser, os, deviceType }
+}
+
+function isPrivateIp(ip: string): boolean {
+  return (
+    ip === '::1' ||
+    ip.startsWith('127.') ||
+    ip.startsWith('10.') ||
+    ip.startsWith('192.168.') ||
+    /^172\.(1[6-9]|2\d|3[01])\./.test(ip) ||
+    /^f[cd]/i.test(ip)
+  )
+}
+
+/**
+ * HTTP resolver for services answering JSON at a URL containing `{ip}`,
+ * e.g. GEOIP_LOOKUP_URL=http://ip-api.com/json/{ip}?fields=country,regionName,city
+ */
+function httpGeoIpResolver(template: string): GeoIpResolver {
+  return {
+    async lookup(ip) {
+      const res = await fetch(template.replace('{ip}', encodeURIComponent(ip)), {
+        signal: AbortSignal.timeout(2000),
+      })
+      if (!res.ok) return null
+      const data = (await res.json()) as Record<string, unknown>
+      const str = (v: unknown) => (typeof v === 'string' && v ? v : undefined)
+      return {
+        country: str(data.country) ?? str(data.country_name),
+        region: str(data.regionName) ?? str(data.region),
+        city: str(data.city),
+      }
+    },
+  }
+}
+
+let resolver: GeoIpResolver | null = process.env.GEOIP_LOOKUP_URL
+  ? httpGeoIpResolver(process.env.GEOIP_LOOKUP_URL)
+  : null
+
+/** Install a custom geo-IP resolver (pass null to disable enrichment) */
+export function registerGeoIpResolver(r: GeoIpResolver | null): void {
+  resolver = r
+  geoCache.clear()
+}
+
+const GEO_CACHE_MAX = 1000
+const geoCache = new Map<string, GeoLocation | null>()
+
+const geoCounter = registerCache('geoip', {
+  description: 'Geo-IP lookups by IP (first 1000)',
+  keys: () => [...geoCache.keys()],
+  clear: (ip) => {
+    if (ip) geoCache.delete(ip)
+    else geoCache.clear()
+  },
+})
+
+/** Resolve an IP's location. Never throws; null when disabled or unknown. */
+export async function lookupGeoIp(ip: string): Promise<GeoLocation | null> {
+  // Hashed / redacted audit IPs (AUDIT_IP_MODE) cannot be resolved
+  if (!resolver || !isIP(ip) || isPrivateIp(ip)) return null
+  if (geoCache.has(ip)) {
+    geoCounter.hit()
+    return geoCache.get(ip) ?? null
+  }
+  geoCounter.miss()
+
+  let location: GeoLocation | null = null
+  try {
+    location = await resolver.lookup(ip)
+  } catch {
+    // Enrichment is best-effort
+  }
+
+  if (geoCache.size >= GEO_CACHE_MAX) {
+    geoCache.delete(geoCache.keys().next().value as string)
+  }
+  geoCache.set(ip, location)
+  return location
+}
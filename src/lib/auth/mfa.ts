@@ -0,0 +1,161 @@
+import { createHash, createHmac, randomBytes, randomInt, timingSafeEqual } from 'crypto'
+import { prisma } from '@/lib/db'
+import { encrypt, decrypt } from '@/lib/auth/encryption'
+
+// TOTP two-factor authentication (RFC 6238: SHA-1, 6 digits, 30 s steps, as
+// every authenticator app expects). Setup stores a new encrypted secret and
+// returns the otpauth:// URI for the QR code; the first valid code turns MFA
+// on and hands out RECOVERY_CODE_COUNT one-time recovery codes, kept only as
+// SHA-256 hashes. With MFA on, login answers with a short-lived MFA token
+// instead of the session cookies, exchanged at /auth/mfa/verify for a code.
+//
+// An admin can require MFA per user (mfaRequired); such a user can only use
+// the enrolment endpoints until MFA is on. SSO logins (lib/auth/oidc) leave
+// the second factor to the identity provider.
+//
+// MFA_ISSUER — account label issuer shown in authenticator apps (default "TeamClaw")
+
+const STEP_SECONDS = 30
+const DIGITS = 6
+// Accept the previous and next step too, for clock drift
+const DRIFT_STEPS = 1
+export const RECOVERY_CODE_COUNT = 10
+
+// ─── Base32 (RFC 4648) ──────────────────────────────────────────────
+
+const BASE32 = 'ABCDEFGHIJKLMNOPQRSTUVWXYZ234567'
+
+function base32Encode(buf: Buffer): string {
+  let bits = 0
+  let value = 0
+  let out = ''
+  for (const byte of buf) {
+    value = (value << 8) | byte
+    bits += 8
+    while (bits >= 5) {
+      out += BASE32[(value >>> (bits - 5)) & 31]
+      bits -= 5
+    }
+  }
+  if (bits > 0) out += BASE32[(value << (5 - bits)) & 31]
+  return out
+}
+
+function base32Decode(text: string): Buffer {
+  let bits = 0
+  let value = 0
+  const out: number[] = []
+  for (const ch of text.replace(/=+$/, '').toUpperCase()) {
+    const idx = BASE32.indexOf(ch)
+    if (idx < 0) throw new Error('Invalid base32 secret')
+    value = (value << 5) | idx
+    bits += 5
+    if (bits >= 8) {
+      out.push((value >>> (bits - 8)) & 0xff)
+      bits -= 8
+    }
+  }
+  return Buffer.from(out)
+}
+
+// ─── TOTP ───────────────────────────────────────────────────────────
+
+export function generateSecret(): string {
+  return base32Encode(randomBytes(20))
+}
+
+function hotp(secret: Buffer, counter: number): string {
+  const msg = Buffer.alloc(8)
+  msg.writeBigUInt64BE(BigInt(counter))
+  const hmac = createHmac('sha1', secret).update(msg).digest()
+  const offset = hmac[hmac.length - 1] & 0x0f
+  const code = (hmac.readUInt32BE(offset) & 0x7fffffff) % 10 ** DIGITS
+  return code.toString().padStart(DIGITS, '0')
+}
+
+/** Time step the code belongs to, or null when it matches none in the drift window */
+export function matchTotp(secret: string, code: string, now = Date.now()): number | null {
+  if (!/^\d{6}$/.test(code)) return null
+  const key = base32Decode(secret)
+  const current = Math.floor(now / 1000 / STEP_SECONDS)
+  for (let step = current - DRIFT_STEPS; step <= current + DRIFT_STEPS; step++) {
+    if (timingSafeEqual(Buffer.from(hotp(key, step)), Buffer.from(code))) return step
+  }
+  return null
+}
+
+export function provisioningUri(email: string, secret: string): string {
+  const issuer = process.env.MFA_ISSUER || 'TeamClaw'
+  const params = new URLSearchParams({
+    secret,
+    issuer,
+    algorithm: 'SHA1',
+    digits: String(DIGITS),
+    period: String(STEP_SECONDS),
+  })
+  return `otpauth://totp/${encodeURIComponent(`${issuer}:${email}`)}?${params}`
+}
+
+// ─── Secrets and recovery codes ─────────────────────────────────────
+
+export const sealSecret = (secret: string) => encrypt(secret)
+export const openSecret = (sealed: string) => decrypt(sealed)
+
+const hashCode = (code: string) =>
+  createHash('sha256').update(code.replace(/[\s-]/g, '').toLowerCase()).digest('hex')
+
+/** New recovery codes for the user, replacing any old ones; the plain codes are shown once */
+export async function issueRecoveryCodes(userId: string): Promise<string[]> {
+  const alphabet = 'abcdefghjkmnpqrstuvwxyz23456789'
+  const codes = Array.from({ length: RECOVERY_CODE_COUNT }, () => {
+    const raw = Array.from({ length: 10 }, () => alphabet[randomInt(alphabet.length)]).join('')
+    return `${raw.slice(0, 5)}-${raw.slice(5)}`
+  })
+  await prisma.$transaction([
+    prisma.mfaRecoveryCode.deleteMany({ where: { userId } }),
+    prisma.mfaRecoveryCode.createMany({ data: codes.map((code) => ({ userId, codeHash: hashCode(code) })) }),
+  ])
+  return codes
+}
+
+/**
+ * Check a second factor: a TOTP code (each time step accepted once) or an
+ * unused recovery code, which is used up.
+ */
+export async function verifySecondFactor(
+  user: { id: string; mfaSecret: string | null; mfaLastStep: number | null },
+  code: string,
+): Promise<'totp' | 'recovery' | null> {
+  const trimmed = code.trim()
+  if (user.mfaSecret && /^\d{6}$/.test(trimmed)) {
+    const step = matchTotp(openSecret(user.mfaSecret), trimmed)
+    if (step === null || (user.mfaLastStep !== null && step <= user.mfaLastStep)) return null
+    // Conditional update: two concurrent requests with the same code cannot both pass
+    const { count } = await prisma.user.updateMany({
+      where: { id: user.id, OR: [{ mfaLastStep: null }, { mfaLastStep: { lt: step } }] },
+      data: { mfaLastStep: step },
+    })
+    return count === 1 ? 'totp' : null
+  }
+
+  const { count } = await prisma.mfaRecoveryCode.updateMany({
+    where: { userId: user.id, codeHash: hashCode(trimmed), usedAt: null },
+    data: { usedAt: new Date() },
+  })
+  return count === 1 ? 'recovery' : null
+}
+
+export async function countRecoveryCodesLeft(userId: string): Promise<number> {
+  return prisma.mfaRecoveryCode.count({ where: { userId, usedAt: null } })
+}
+
+/** Turn MFA off and forget the secret and recovery codes */
+export async function resetMfa(userId: string): Promise<void> {
+  await prisma.$transaction([
+    prisma.mfaRecoveryCode.deleteMany({ where: { userId } }),
+    prisma.user.update({
+      where: { id: userId },
+      data: { mfaEnabled: false, mfaSecret: null, mfaLastStep: null },
+    }),
+  ])
+}
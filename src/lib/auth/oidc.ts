@@ -0,0 +1,274 @@
+import { createHash, randomBytes } from 'crypto'
+import { createRemoteJWKSet, jwtVerify, type JWTPayload } from 'jose'
+import { prisma } from '@/lib/db'
+import { hashPassword } from '@/lib/auth/password'
+import { enforceLicenseLimit } from '@/lib/license'
+import { createLogger } from '@/lib/logger'
+import type { Role, User, Department } from '@/generated/prisma'
+
+// Single sign-on through an OpenID Connect provider (Okta, Azure AD / Entra
+// ID, Keycloak, ...), using the authorization code flow with PKCE.
+//
+//   GET /api/v1/auth/oidc/login     → redirect to the provider
+//   GET /api/v1/auth/oidc/callback  → verify the ID token, sign the user in
+//
+// A returning user is matched by the provider's subject; a first SSO login
+// links to the local account with the same (verified) email, and anyone else
+// is provisioned with OIDC_DEFAULT_ROLE unless OIDC_AUTO_PROVISION=false.
+// Provisioned accounts get an unusable random password, so they can only
+// sign in through the provider.
+//
+// OIDC_ISSUER              — issuer URL; SSO is off while unset
+// OIDC_CLIENT_ID / _SECRET — client registered with the provider
+// OIDC_REDIRECT_URI        — default <app origin>/api/v1/auth/oidc/callback
+// OIDC_SCOPES              — default "openid email profile"
+// OIDC_PROVIDER_NAME       — label on the login button (default "SSO")
+// OIDC_AUTO_PROVISION      — create unknown users (default true)
+// OIDC_DEFAULT_ROLE        — role of provisioned users (default USER)
+// OIDC_ALLOWED_DOMAINS     — comma-separated email domains allowed to sign
+//                            in; empty allows any
+
+const log = createLogger('auth:oidc')
+
+const DISCOVERY_TTL_MS = 60 * 60_000
+const ROLES: Role[] = ['SYSTEM_ADMIN', 'DEPT_ADMIN', 'USER', 'VIEWER']
+
+export interface OidcConfig {
+  issuer: string
+  clientId: string
+  clientSecret: string
+  redirectUri: string | null
+  scopes: string
+  providerName: string
+  autoProvision: boolean
+  defaultRole: Role
+  allowedDomains: string[]
+}
+
+export function getOidcConfig(): OidcConfig | null {
+  const issuer = process.env.OIDC_ISSUER?.replace(/\/+$/, '')
+  const clientId = process.env.OIDC_CLIENT_ID
+  if (!issuer || !clientId) return null
+  const role = process.env.OIDC_DEFAULT_ROLE as Role | undefined
+  return {
+    issuer,
+    clientId,
+    clientSecret: process.env.OIDC_CLIENT_SECRET ?? '',
+    redirectUri: process.env.OIDC_REDIRECT_URI || null,
+    scopes: process.env.OIDC_SCOPES || 'openid email profile',
+    providerName: process.env.OIDC_PROVIDER_NAME || 'SSO',
+    autoProvision: process.env.OIDC_AUTO_PROVISION !== 'false',
+    defaultRole: role && ROLES.includes(role) ? role : 'USER',
+    allowedDomains: (process.env.OIDC_ALLOWED_DOMAINS ?? '')
+      .split(',')
+      .map((d) => d.trim().toLowerCase())
+      .filter(Boolean),
+  }
+}
+
+// ─── Discovery ──────────────────────────────────────────────────────
+
+interface ProviderMetadata {
+  authorization_endpoint: string
+  token_endpoint: string
+  jwks_uri: string
+}
+
+const globalForOidc = globalThis as unknown as {
+  oidcDiscovery?: { issuer: string; metadata: ProviderMetadata; jwks: ReturnType<typeof createRemoteJWKSet>; at: number }
+}
+
+async function discover(config: OidcConfig) {
+  const cached = globalForOidc.oidcDiscovery
+  if (cached && cached.issuer === config.issuer && Date.now() - cached.at < DISCOVERY_TTL_MS) return cached
+
+  const res = await fetch(`${config.issuer}/.well-known/openid-configuration`, {
+    signal: AbortSignal.timeout(10_000),
+  })
+  if (!res.ok) throw new Error(`OIDC discovery failed: HTTP ${res.status}`)
+  const metadata = (await res.json()) as ProviderMetadata
+  const entry = {
+    issuer: config.issuer,
+    metadata,
+    jwks: createRemoteJWKSet(new URL(metadata.jwks_uri)),
+    at: Date.now(),
+  }
+  globalForOidc.oidcDiscovery = entry
+  return entry
+}
+
+// ─── Authorization request ──────────────────────────────────────────
+
+/** Carried in a short-lived cookie between /login and /callback */
+export interface OidcLoginState {
+  state: string
+  nonce: string
+  codeVerifier: string
+  redirectUri: string
+  returnTo: string
+}
+
+export const OIDC_STATE_COOKIE = 'oidc_state'
+
+const token = () => randomBytes(32).toString('base64url')
+
+export function encodeLoginState(state: OidcLoginState): string {
+  return Buffer.from(JSON.stringify(state)).toString('base64url')
+}
+
+export function decodeLoginState(value: string | undefined): OidcLoginState | null {
+  if (!value) return null
+  try {
+    return JSON.parse(Buffer.from(value, 'base64url').toString()) as OidcLoginState
+  } catch {
+    return null
+  }
+}
+
+/** Only same-origin paths, so the callback cannot be turned into an open redirect */
+export function safeReturnTo(value: string | null): string {
+  return value && value.startsWith('/') && !value.startsWith('//') ? value : '/chat'
+}
+
+export async function buildAuthorizationUrl(
+  config: OidcConfig,
+  redirectUri: string,
+  returnTo: string,
+): Promise<{ url: string; loginState: OidcLoginState }> {
+  const { metadata } = await discover(config)
+  const loginState: OidcLoginState = {
+    state: token(),
+    nonce: token(),
+    codeVerifier: token(),
+    redirectUri,
+    returnTo,
+  }
+  const url = new URL(metadata.authorization_endpoint)
+  url.search = new URLSearchParams({
+    response_type: 'code',
+    client_id: config.clientId,
+    redirect_uri: redirectUri,
+    scope: config.scopes,
+    state: loginState.state,
+    nonce: loginState.nonce,
+    code_challenge: createHash('sha256').update(loginState.codeVerifier).digest('base64url'),
+    code_challenge_method: 'S256',
+  }).toString()
+  return { url: url.toString(), loginState }
+}
+
+// ─── Callback ───────────────────────────────────────────────────────
+
+export interface OidcIdentity {
+  subject: string
+  email: string
+  /** Only a verified email may link to or create an account */
+  emailVerified: boolean
+  name: string
+}
+
+/** Exchange the authorization code and verify the ID token it returns */
+export async function completeLogin(
+  config: OidcConfig,
+  code: string,
+  loginState: OidcLoginState,
+): Promise<OidcIdentity> {
+  const { metadata, jwks } = await discover(config)
+
+  const res = await fetch(metadata.token_endpoint, {
+    method: 'POST',
+    headers: {
+      'Content-Type': 'application/x-www-form-urlencoded',
+      Authorization: `Basic ${Buffer.from(
+        `${encodeURIComponent(config.clientId)}:${encodeURIComponent(config.clientSecret)}`,
+      ).toString('base64')}`,
+    },
+    body: new URLSearchParams({
+      grant_type: 'authorization_code',
+      code,
+      redirect_uri: loginState.redirectUri,
+      code_verifier: loginState.codeVerifier,
+    }),
+    signal: AbortSignal.timeout(10_000),
+  })
+  if (!res.ok) throw new Error(`Token exchange failed: HTTP ${res.status}`)
+  const tokens = (await res.json()) as { id_token?: string }
+  if (!tokens.id_token) throw new Error('Provider returned no ID token')
+
+  const { payload } = await jwtVerify(tokens.id_token, jwks, {
+    issuer: config.issuer,
+    audience: config.clientId,
+  })
+  return identityFromClaims(config, payload, loginState.nonce)
+}
+
+function identityFromClaims(config: OidcConfig, claims: JWTPayload, nonce: string): OidcIdentity {
+  if (claims.nonce !== nonce) throw new Error('ID token nonce mismatch')
+  if (!claims.sub) throw new Error('ID token has no subject')
+
+  // Azure AD puts the address in preferred_username when email is not released.
+  // That claim is user-editable at many providers, so it is never trusted as
+  // verified, and neither is an email without email_verified: true
+  const email = String(claims.email ?? claims.preferred_username ?? '').trim().toLowerCase()
+  if (!email.includes('@')) throw new Error('ID token has no email address')
+  const emailVerified = typeof claims.email === 'string' && claims.email_verified === true
+
+  const domain = email.split('@')[1]
+  if (config.allowedDomains.length > 0 && !config.allowedDomains.includes(domain)) {
+    throw new Error(`Email domain ${domain} is not allowed to sign in`)
+  }
+
+  const name = String(claims.name ?? [claims.given_name, claims.family_name].filter(Boolean).join(' '))
+  return { subject: claims.sub, email, emailVerified, name: name.trim() || email.split('@')[0] }
+}
+
+export type OidcUser = User & { department: Department | null }
+
+/**
+ * The local account for an SSO identity: by subject, else linked by verified
+ * email, else provisioned. An unverified email only signs in to an account
+ * already linked to the subject. Returns an error message when none can be used.
+ */
+export async function resolveOidcUser(
+  config: OidcConfig,
+  identity: OidcIdentity,
+): Promise<{ user: OidcUser; created: boolean; linked: boolean } | { error: string }> {
+  const include = { department: true } as const
+
+  const bySubject = await prisma.user.findUnique({ where: { oidcSubject: identity.subject }, include })
+  if (bySubject) return { user: bySubject, created: false, linked: false }
+
+  // Otherwise anyone able to set an address at the provider could take over
+  // the local account that uses it
+  if (!identity.emailVerified) {
+    return { error: 'The SSO provider has not verified this email address' }
+  }
+
+  const byEmail = await prisma.user.findUnique({ where: { email: identity.email }, include })
+  if (byEmail) {
+    if (byEmail.isServiceAccount) return { error: 'Service accounts cannot sign in' }
+    if (byEmail.oidcSubject) return { error: 'Account is linked to another SSO identity' }
+    const user = await prisma.user.update({
+      where: { id: byEmail.id },
+      data: { oidcSubject: identity.subject },
+      include,
+    })
+    log.info('Linked SSO identity to existing account', { userId: user.id })
+    return { user, created: false, linked: true }
+  }
+
+  if (!config.autoProvision) return { error: 'No account exists for this email' }
+  if (await enforceLicenseLimit('seat')) return { error: 'License seat limit reached' }
+
+  const user = await prisma.user.create({
+    data: {
+      email: identity.email,
+      name: identity.name.slice(0, 50),
+      passwordHash: await hashPassword(randomBytes(32).toString('hex')),
+      role: config.defaultRole,
+      oidcSubject: identity.subject,
+    },
+    include,
+  })
+  return { user, created: true, linked: false }
+}
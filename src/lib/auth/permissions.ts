@@ -7,17 +7,29 @@ export interface PermissionConfig {
 
 const ALL_ROLES: Role[] = [Role.SYSTEM_ADMIN, Role.DEPT_ADMIN, Role.USER]
 
+// VIEWER is a read-only auditor: org-wide list/view, never mutation
+const VIEW_ROLES: Role[] = [Role.SYSTEM_ADMIN, Role.DEPT_ADMIN, Role.VIEWER]
+
 export const ROUTE_PERMISSIONS: Record<string, PermissionConfig> = {
   // Users
   'users:create': { roles: [Role.SYSTEM_ADMIN] },
   'users:update': { roles: [Role.SYSTEM_ADMIN] },
   'users:delete': { roles: [Role.SYSTEM_ADMIN] },
-  'users:list': { roles: [Role.SYSTEM_ADMIN, Role.DEPT_ADMIN] },
+  'users:list': { roles: VIEW_ROLES },
   'users:reset_password': { roles: [Role.SYSTEM_ADMIN] },
+  'users:reset_mfa': { roles: [Role.SYSTEM_ADMIN] },
+  'users:view_logins': { roles: [Role.SYSTEM_ADMIN] },
+  'users:data_rights': { roles: [Role.SYSTEM_ADMIN] },
+  'users:inspect_sessions': { roles: [Role.SYSTEM_ADMIN] },
 
   // Departments
   'departments:manage': { roles: [Role.SYSTEM_ADMIN] },
-  'departments:view': { roles: [Role.SYSTEM_ADMIN, Role.DEPT_ADMIN] },
+  'departments:view': { roles: VIEW_ROLES },
+  'departments:chat_defaults': { roles: [Role.SYSTEM_ADMIN, Role.DEPT_ADMIN], resourceCheck: true },
+  'departments:egress_policy': { roles: [Role.SYSTEM_ADMIN] },
+  'departments:residency': { roles: [Role.SYSTEM_ADMIN] },
+  'departments:tool_redaction': { roles: [Role.SYSTEM_ADMIN, Role.DEPT_ADMIN], resourceCheck: true },
+  'departments:review_joins': { roles: [Role.SYSTEM_ADMIN, Role.DEPT_ADMIN], resourceCheck: true },
 
   // Instance Access
   'instance_access:manage': { roles: [Role.SYSTEM_ADMIN] },
@@ -50,13 +62,21 @@ export const ROUTE_PERMISSIONS: Record<string, PermissionConfig> = {
   'config:manage': { roles: [Role.SYSTEM_ADMIN] },
 
   // Audit
-  'audit:view_all': { roles: [Role.SYSTEM_ADMIN] },
-  'audit:view_dept': { roles: [Role.SYSTEM_ADMIN, Role.DEPT_ADMIN] },
+  'audit:view_all': { roles: [Role.SYSTEM_ADMIN, Role.VIEWER] },
+  'audit:view_dept': { roles: VIEW_ROLES },
 
   // Approvals
   'approvals:review': { roles: [Role.SYSTEM_ADMIN] },
   'approvals:create': { roles: ALL_ROLES },
 
+  // Access recertification; DEPT_ADMIN reviews their department's grants
+  'access_reviews:manage': { roles: [Role.SYSTEM_ADMIN] },
+  'access_reviews:review': { roles: [Role.SYSTEM_ADMIN, Role.DEPT_ADMIN] },
+
+  // Emergency elevation; SYSTEM_ADMIN already has everything it grants
+  'break_glass:activate': { roles: [Role.DEPT_ADMIN] },
+  'break_glass:manage': { roles: [Role.SYSTEM_ADMIN] },
+
   // Channels
   'channels:manage': { roles: [Role.SYSTEM_ADMIN] },
   'channels:view': { roles: [Role.SYSTEM_ADMIN, Role.DEPT_ADMIN] },
@@ -64,9 +84,21 @@ export const ROUTE_PERMISSIONS: Record<string, PermissionConfig> = {
   // Chat
   'chat:use': { roles: ALL_ROLES },
 
+  // Support tickets filed from chat ("report a problem")
+  'support:manage': { roles: [Role.SYSTEM_ADMIN] },
+
   // Monitor
-  'monitor:view': { roles: [Role.SYSTEM_ADMIN] },
-  'monitor:view_basic': { roles: [Role.SYSTEM_ADMIN, Role.DEPT_ADMIN] },
+  'monitor:view': { roles: [Role.SYSTEM_ADMIN, Role.VIEWER] },
+  'monitor:view_basic': { roles: VIEW_ROLES },
+  'monitor:refresh_stats': { roles: [Role.SYSTEM_ADMIN] },
+  'monitor:caches': { roles: [Role.SYSTEM_ADMIN] },
+  'monitor:profiling': { roles: [Role.SYSTEM_ADMIN] },
+  // Synthetic agent probes
+  'probes:manage': { roles: [Role.SYSTEM_ADMIN] },
+
+  // Chat analytics export (BI ingestion); DEPT_ADMIN is scoped to their department
+  'analytics:export': { roles: VIEW_ROLES },
+  'analytics:export_content': { roles: [Role.SYSTEM_ADMIN] },
 
   // Usage
   'usage:view_all': { roles: [Role.SYSTEM_ADMIN] },
@@ -75,7 +107,9 @@ export const ROUTE_PERMISSIONS: Record<string, PermissionConfig> = {
 
   // Instances
   'instances:manage': { roles: [Role.SYSTEM_ADMIN] },
-  'instances:view': { roles: [Role.SYSTEM_ADMIN, Role.DEPT_ADMIN] },
+  'instances:view': { roles: VIEW_ROLES },
+  // Container actions; DEPT_ADMIN additionally needs an InstanceDelegation
+  'instances:control': { roles: [Role.SYSTEM_ADMIN, Role.DEPT_ADMIN], resourceCheck: true },
 
   // API Keys
   'api_keys:manage': { roles: ALL_ROLES },
@@ -87,12 +121,110 @@ export const ROUTE_PERMISSIONS: Record<string, PermissionConfig> = {
 
   // Resources
   'resources:manage': { roles: [Role.SYSTEM_ADMIN] },
+
+  // Integrations (inbound webhooks)
+  'integrations:manage': { roles: [Role.SYSTEM_ADMIN] },
+
+  // Settings
+  'settings:branding': { roles: [Role.SYSTEM_ADMIN] },
+  'settings:license': { roles: [Role.SYSTEM_ADMIN] },
+  'settings:logging': { roles: [Role.SYSTEM_ADMIN] },
+  'settings:audit_sampling': { roles: [Role.SYSTEM_ADMIN] },
+  'settings:tool_redaction': { roles: [Role.SYSTEM_ADMIN] },
+  'settings:encryption': { roles: [Role.SYSTEM_ADMIN] },
+  'settings:rbac': { roles: [Role.SYSTEM_ADMIN] },
+
+  // Embeddable chat widgets
+  'widgets:manage': { roles: [Role.SYSTEM_ADMIN] },
+
+  // Notification channels (outbound Teams / WeCom webhooks)
+  'notifications:manage': { roles: [Role.SYSTEM_ADMIN, Role.DEPT_ADMIN], resourceCheck: true },
+}
+
+// Role lists imported through /api/v1/rbac/policies replace the defaults
+// above. Only populated server-side (lib/auth/policy-store); the client
+// keeps using the defaults for UI gating.
+const globalForPolicies = globalThis as unknown as {
+  policyOverrides?: Map<string, Role[]>
+}
+
+/** Permissions whose roles currently differ from the defaults */
+export function getOverriddenPermissions(): string[] {
+  return [...(globalForPolicies.policyOverrides?.keys() ?? [])]
+}
+
+export function setPolicyOverrides(overrides: Record<string, Role[]>): void {
+  globalForPolicies.policyOverrides = new Map(Object.entries(overrides))
+}
+
+/** Roles currently granted a permission (override or default) */
+export function getEffectiveRoles(permission: string): Role[] {
+  return (
+    globalForPolicies.policyOverrides?.get(permission) ??
+    ROUTE_PERMISSIONS[permission]?.roles ??
+    []
+  )
 }
 
 export function hasPermission(role: string, permission: string): boolean {
+  if (!ROUTE_PERMISSIONS[permission]) return false
+  return getEffectiveRoles(permission).includes(role as Role)
+}
+
+// Never granted through a custom role: these assign roles, expose secrets
+// (process memory, encryption keys) or switch off the safeguards that keep
+// an administrator in check, so they stay with the built-in roles
+const NON_GRANTABLE = new Set([
+  'users:create',
+  'users:update',
+  'users:reset_password',
+  'users:reset_mfa',
+  'users:data_rights',
+  'users:inspect_sessions',
+  'config:manage',
+  'approvals:review',
+  'break_glass:activate',
+  'break_glass:manage',
+  'monitor:profiling',
+  'settings:encryption',
+  'settings:rbac',
+])
+
+/**
+ * Permissions a custom role may grant. Department-scoped ones are left out:
+ * those with a resource check, and any DEPT_ADMIN holds, since handlers
+ * narrow results to the caller's department by checking for that role and
+ * would hand anyone else the whole organisation. So are the NON_GRANTABLE ones.
+ */
+export function isGrantablePermission(permission: string): boolean {
   const config = ROUTE_PERMISSIONS[permission]
-  if (!config) return false
-  return config.roles.includes(role as Role)
+  if (!config || config.resourceCheck || NON_GRANTABLE.has(permission)) return false
+  return !config.roles.includes(Role.DEPT_ADMIN) && !getEffectiveRoles(permission).includes(Role.DEPT_ADMIN)
+}
+
+/** The user's role grants the permission, or their custom role does (lib/auth/custom-roles) */
+export function userHasPermission(
+  user: { role: string; customRole?: { permissions: string[] } },
+  permission: string,
+): boolean {
+  if (hasPermission(user.role, permission)) return true
+  return isGrantablePermission(permission) && !!user.customRole?.permissions.includes(permission)
+}
+
+/**
+ * The role a user holds right now. A temporary elevation stops counting the
+ * moment it expires, even before the expiry job has reverted the stored role.
+ */
+export function effectiveRole(user: { role: Role; roleExpiresAt: Date | null; baseRole: Role | null }): Role {
+  if (user.roleExpiresAt && user.roleExpiresAt.getTime() <= Date.now()) {
+    return user.baseRole ?? Role.USER
+  }
+  return user.role
+}
+
+/** Roles that see organisation-wide data rather than a department slice. */
+export function hasOrgWideView(role: string): boolean {
+  return role === Role.SYSTEM_ADMIN || role === Role.VIEWER
 }
 
 export function getPermissionConfig(
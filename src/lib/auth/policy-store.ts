@@ -0,0 +1,222 @@
+import { randomUUID } from 'crypto'
+import { prisma } from '@/lib/db'
+import { redis } from '@/lib/redis'
+import { Prisma, Role } from '@/generated/prisma'
+import { registerCache } from '@/lib/caches'
+import { createLogger } from '@/lib/logger'
+import { ROUTE_PERMISSIONS, getEffectiveRoles, getOverriddenPermissions, setPolicyOverrides } from './permissions'
+import type { PolicyChange, PolicyDocument, PolicyEntry } from '@/types/rbac'
+
+/** SystemConfig key holding role overrides (only permissions that differ from the defaults) */
+export const RBAC_POLICIES_KEY = 'rbac_policies'
+
+const POLICY_ADMIN_PERMISSION = 'settings:rbac'
+
+// Each replica holds the overrides in memory; a save (or POST
+// /api/v1/rbac/reload) publishes on this channel so the others re-read them
+const RELOAD_CHANNEL = 'rbac:policies:reload'
+
+const log = createLogger('auth:policies')
+
+const globalForPolicySync = globalThis as unknown as {
+  policySyncStarted?: boolean
+  policyReplicaId?: string
+}
+
+const replicaId = (globalForPolicySync.policyReplicaId ??= randomUUID())
+const VALID_ROLES = new Set<string>(Object.values(Role))
+
+function sortRoles(roles: Role[]): Role[] {
+  const order = Object.values(Role)
+  return [...new Set(roles)].sort((a, b) => order.indexOf(a) - order.indexOf(b))
+}
+
+function sameRoles(a: Role[], b: Role[]): boolean {
+  return sortRoles(a).join(',') === sortRoles(b).join(',')
+}
+
+/** Apply persisted overrides; called at startup and after an import */
+export async function loadPolicyOverrides(): Promise<void> {
+  const row = await prisma.systemConfig.findUnique({ where: { key: RBAC_POLICIES_KEY } })
+  const stored = (row?.value ?? {}) as Record<string, Role[]>
+  const overrides: Record<string, Role[]> = {}
+  for (const [permission, roles] of Object.entries(stored)) {
+    // Permissions removed from the code since the import are ignored
+    if (ROUTE_PERMISSIONS[permission] && Array.isArray(roles)) {
+      overrides[permission] = roles.filter((r) => VALID_ROLES.has(r))
+    }
+  }
+  setPolicyOverrides(overrides)
+}
+
+/** Tell the other replicas to reload; they pick the overrides up from SystemConfig */
+export async function broadcastPolicyReload(): Promise<void> {
+  await redis
+    .publish(RELOAD_CHANNEL, replicaId)
+    .catch((err) => log.warn('Could not broadcast policy reload', { error: (err as Error).message }))
+}
+
+/** Follow reloads other replicas broadcast. Called once at startup. */
+export function startPolicySync(): void {
+  if (globalForPolicySync.policySyncStarted) return
+  globalForPolicySync.policySyncStarted = true
+
+  // A subscribed connection can do nothing else, so it gets its own
+  const subscriber = redis.duplicate()
+  subscriber.on('message', (channel: string, sender: string) => {
+    if (channel !== RELOAD_CHANNEL || sender === replicaId) return
+    loadPolicyOverrides()
+      .then(() => log.info('Reloaded RBAC policies', { from: sender }))
+      .catch((err) => log.error('Policy reload failed', { error: (err as Error).message }))
+  })
+  subscriber.subscribe(RELOAD_CHANNEL).catch((err) => {
+    log.error('Could not subscribe to policy reloads', { error: (err as Error).message })
+  })
+}
+
+// Overrides are held in memory after startup; clearing re-reads SystemConfig
+registerCache('rbac-policies', {
+  description: 'Role overrides by permission (reloaded on clear)',
+  keys: getOverriddenPermissions,
+  clear: () => loadPolicyOverrides(),
+})
+
+/** The full effective policy set, sorted by permission for stable diffs */
+export function exportPolicies(): PolicyDocument {
+  const policies: PolicyEntry[] = Object.keys(ROUTE_PERMISSIONS)
+    .sort()
+    .map((permission) => ({
+      permission,
+      roles: sortRoles(getEffectiveRoles(permission)),
+      ...(ROUTE_PERMISSIONS[permission].resourceCheck ? { resourceCheck: true } : {}),
+    }))
+  return { version: 1, exportedAt: new Date().toISOString(), policies }
+}
+
+// ─── CSV ─────────────────────────────────────────────────────────────
+//
+// permission,roles,resourceCheck
+// users:list,SYSTEM_ADMIN|DEPT_ADMIN|VIEWER,false
+
+export function policiesToCsv(doc: PolicyDocument): string {
+  const lines = ['permission,roles,resourceCheck']
+  for (const p of doc.policies) {
+    lines.push(`${p.permission},${p.roles.join('|')},${p.resourceCheck ? 'true' : 'false'}`)
+  }
+  return lines.join('\n') + '\n'
+}
+
+function csvToEntries(csv: string): { entries: PolicyEntry[]; errors: string[] } {
+  const entries: PolicyEntry[] = []
+  const errors: string[] = []
+  const lines = csv.split(/\r?\n/).map((l) => l.trim()).filter(Boolean)
+  if (lines[0]?.startsWith('permission,')) lines.shift()
+
+  lines.forEach((line, i) => {
+    const [permission, roles = ''] = line.split(',').map((c) => c.trim())
+    if (!permission) {
+      errors.push(`Line ${i + 2}: missing permission`)
+      return
+    }
+    entries.push({ permission, roles: roles ? (roles.split('|') as Role[]) : [] })
+  })
+  return { entries, errors }
+}
+
+// ─── Import ──────────────────────────────────────────────────────────
+
+/**
+ * Parse and validate a policy set. The set is complete: permissions it
+ * omits fall back to the code defaults, so staging and production end up
+ * identical regardless of what was imported before.
+ */
+export function parsePolicies(
+  input: string,
+  format: 'json' | 'csv',
+): { next: Record<string, Role[]>; errors: string[] } {
+  let entries: PolicyEntry[] = []
+  const errors: string[] = []
+
+  if (format === 'csv') {
+    const parsed = csvToEntries(input)
+    entries = parsed.entries
+    errors.push(...parsed.errors)
+  } else {
+    try {
+      const doc = JSON.parse(input) as Partial<PolicyDocument>
+      if (doc.version !== 1 || !Array.isArray(doc.policies)) {
+        errors.push('Expected a policy document with version 1 and a policies array')
+      } else {
+        entries = doc.policies
+      }
+    } catch {
+      errors.push('Invalid JSON')
+    }
+  }
+
+  const next: Record<string, Role[]> = {}
+  for (const [permission, config] of Object.entries(ROUTE_PERMISSIONS)) {
+    next[permission] = sortRoles(config.roles)
+  }
+
+  const seen = new Set<string>()
+  for (const entry of entries) {
+    if (typeof entry?.permission !== 'string' || !Array.isArray(entry.roles)) {
+      errors.push('Each policy needs a permission and a roles array')
+      continue
+    }
+    if (!ROUTE_PERMISSIONS[entry.permission]) {
+      errors.push(`Unknown permission: ${entry.permission}`)
+      continue
+    }
+    if (seen.has(entry.permission)) {
+      errors.push(`Duplicate permission: ${entry.permission}`)
+      continue
+    }
+    seen.add(entry.permission)
+    const badRoles = entry.roles.filter((r) => !VALID_ROLES.has(r))
+    if (badRoles.length > 0) {
+      errors.push(`${entry.permission}: unknown role(s) ${badRoles.join(', ')}`)
+      continue
+    }
+    next[entry.permission] = sortRoles(entry.roles)
+  }
+
+  // Never let an import lock administrators out of policy management
+  if (!next[POLICY_ADMIN_PERMISSION].includes(Role.SYSTEM_ADMIN)) {
+    errors.push(`${POLICY_ADMIN_PERMISSION} must keep SYSTEM_ADMIN`)
+  }
+
+  return { next, errors }
+}
+
+export function diffPolicies(next: Record<string, Role[]>): PolicyChange[] {
+  const changes: PolicyChange[] = []
+  for (const permission of Object.keys(next).sort()) {
+    const before = sortRoles(getEffectiveRoles(permission))
+    if (!sameRoles(before, next[permission])) {
+      changes.push({ permission, before, after: next[permission] })
+    }
+  }
+  return changes
+}
+
+/** Persist a complete policy set (storing only deviations from the defaults) and apply it */
+export async function savePolicies(next: Record<string, Role[]>): Promise<void> {
+  const overrides: Record<string, Role[]> = {}
+  for (const [permission, roles] of Object.entries(next)) {
+    if (!sameRoles(roles, ROUTE_PERMISSIONS[permission].roles)) overrides[permission] = roles
+  }
+
+  await prisma.systemConfig.upsert({
+    where: { key: RBAC_POLICIES_KEY },
+    update: { value: overrides as unknown as Prisma.InputJsonValue },
+    create: {
+      key: RBAC_POLICIES_KEY,
+      value: overrides as unknown as Prisma.InputJsonValue,
+      description: 'Role overrides for route permissions (imported policy set)',
+    },
+  })
+  setPolicyOverrides(overrides)
+  await broadcastPolicyReload()
+}
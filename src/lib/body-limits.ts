@@ -0,0 +1,97 @@
+import { NextResponse } from 'next/server'
+import { globToRegExp } from '@/lib/utils/glob'
+
+// Request body limits, enforced in middleware from Content-Length so oversized
+// payloads are refused before any route buffers them. Runs on the edge runtime:
+// no Node imports here.
+//
+// BODY_LIMIT_DEFAULT — fallback for every route, e.g. "1mb" (default)
+// BODY_LIMITS        — per-route overrides: "/api/v1/chat/send=40mb,/api/v1/agents/*=2mb"
+
+const KB = 1024
+const MB = 1024 * KB
+
+const DEFAULT_LIMIT = 1 * MB
+
+/** Routes that legitimately carry more than the default; first match wins */
+const ROUTE_LIMITS: [pattern: string, bytes: number][] = [
+  // Multipart file uploads (50MB file + form overhead)
+  ['/api/v1/chat/sessions/*/files/upload', 51 * MB],
+  // Chat attachment uploads (FILE_MAX_MB, default 25MB, + form overhead)
+  ['/api/v1/files', 26 * MB],
+  // Chat messages carry base64-encoded image attachments
+  ['/api/v1/chat/send', 25 * MB],
+  ['/api/v1/widget/chat', 64 * KB],
+  // Skill file editor (whole file per save)
+  ['/api/v1/skills/*/files/*', 5 * MB],
+  // Bulk imports
+  ['/api/v1/rbac/policies/import', 5 * MB],
+]
+
+export const PAYLOAD_TOO_LARGE = 'PAYLOAD_TOO_LARGE'
+export const LENGTH_REQUIRED = 'LENGTH_REQUIRED'
+
+export function parseSize(value: string): number | null {
+  const m = value.trim().toLowerCase().match(/^(\d+(?:\.\d+)?)\s*(b|kb|mb)?$/)
+  if (!m) return null
+  const n = parseFloat(m[1])
+  return Math.floor(m[2] === 'mb' ? n * MB : m[2] === 'kb' ? n * KB : n)
+}
+
+let compiled: { source: string; defaultLimit: number; routes: [RegExp, number][] } | null = null
+
+function getLimits() {
+  const source = `${process.env.BODY_LIMIT_DEFAULT ?? ''}|${process.env.BODY_LIMITS ?? ''}`
+  if (compiled?.source === source) return compiled
+
+  const overrides: [string, number][] = []
+  for (const pair of (process.env.BODY_LIMITS ?? '').split(',')) {
+    const i = pair.lastIndexOf('=')
+    if (i === -1) continue
+    const bytes = parseSize(pair.slice(i + 1))
+    if (bytes !== null) overrides.push([pair.slice(0, i).trim(), bytes])
+  }
+
+  compiled = {
+    source,
+    defaultLimit: parseSize(process.env.BODY_LIMIT_DEFAULT ?? '') ?? DEFAULT_LIMIT,
+    // Env overrides take precedence over the built-in table
+    routes: [...overrides, ...ROUTE_LIMITS].map(([p, bytes]) => [globToRegExp(p), bytes]),
+  }
+  return compiled
+}
+
+export function getBodyLimit(pathname: string): number {
+  const limits = getLimits()
+  return limits.routes.find(([re]) => re.test(pathname))?.[1] ?? limits.defaultLimit
+}
+
+/**
+ * Returns a 413/411 response when the request body is (or may be) over the
+ * route's limit, or null to let it through.
+ */
+export function checkBodyLimit(req: { method: string; headers: Headers }, pathname: string): NextResponse | null {
+  if (req.method === 'GET' || req.method === 'HEAD' || req.method === 'OPTIONS') return null
+
+  const limit = getBodyLimit(pathname)
+  const length = req.headers.get('content-length')
+
+  if (length === null) {
+    // Chunked bodies can't be sized up front; require a length instead
+    if (req.headers.get('transfer-encoding')) {
+      return NextResponse.json(
+        { error: 'Content-Length required', code: LENGTH_REQUIRED },
+        { status: 411 },
+      )
+    }
+    return null
+  }
+
+  if (Number(length) > limit) {
+    return NextResponse.json(
+      { error: 'Request body too large', code: PAYLOAD_TOO_LARGE, limit },
+      { status: 413 },
+    )
+  }
+  return null
+}
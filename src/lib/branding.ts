@@ -0,0 +1,60 @@
+import { prisma } from '@/lib/db'
+import { registerCache } from '@/lib/caches'
+import { Prisma } from '@/generated/prisma'
+import type { Branding } from '@/types/branding'
+
+/** SystemConfig key holding the Branding */
+export const BRANDING_KEY = 'branding'
+
+export const DEFAULT_BRANDING: Branding = {
+  productName: 'TeamClaw',
+  logoUrl: null,
+  colors: {},
+  darkColors: {},
+  loginBanner: null,
+}
+
+// Read on every unauthenticated page load, so cache briefly
+const CACHE_TTL_MS = 60_000
+let cached: { value: Branding; at: number } | null = null
+
+const counter = registerCache('branding', {
+  description: 'Branding settings (60s TTL)',
+  keys: () => (cached ? [BRANDING_KEY] : []),
+  clear: () => {
+    cached = null
+  },
+})
+
+export async function getBranding(): Promise<Branding> {
+  if (cached && Date.now() - cached.at < CACHE_TTL_MS) {
+    counter.hit()
+    return cached.value
+  }
+  counter.miss()
+
+  const row = await prisma.systemConfig.findUnique({ where: { key: BRANDING_KEY } })
+  const stored = (row?.value ?? {}) as Partial<Branding>
+  const value: Branding = {
+    productName: stored.productName || DEFAULT_BRANDING.productName,
+    logoUrl: stored.logoUrl ?? null,
+    colors: stored.colors ?? {},
+    darkColors: stored.darkColors ?? {},
+    loginBanner: stored.loginBanner ?? null,
+  }
+  cached = { value, at: Date.now() }
+  return value
+}
+
+export async function setBranding(branding: Branding): Promise<void> {
+  await prisma.systemConfig.upsert({
+    where: { key: BRANDING_KEY },
+    update: { value: branding as unknown as Prisma.InputJsonValue },
+    create: {
+      key: BRANDING_KEY,
+      value: branding as unknown as Prisma.InputJsonValue,
+      description: 'White-label product name, logo, colors, and login banner',
+    },
+  })
+  cached = null
+}
@@ -0,0 +1,101 @@
+// Registry of the in-process caches, so an admin can see what is cached and
+// drop stale entries without a restart (GET/DELETE /api/v1/admin/caches).
+// Each cache module calls registerCache() at load time and reports hits and
+// misses through the returned counter.
+
+export interface CacheSource {
+  description: string
+  /** Current keys (values are never exposed) */
+  keys(): string[] | Promise<string[]>
+  /** Drop one entry, or everything when key is omitted */
+  clear(key?: string): void | Promise<void>
+}
+
+export interface CacheCounter {
+  hit(): void
+  miss(): void
+}
+
+export interface CacheInfo {
+  name: string
+  description: string
+  size: number
+  keys: string[]
+  hits: number
+  misses: number
+  /** hits / (hits + misses), null before the first lookup */
+  hitRate: number | null
+}
+
+const MAX_LISTED_KEYS = 100
+
+type Registered = CacheSource & { hits: number; misses: number }
+
+const globalForCaches = globalThis as unknown as {
+  cacheRegistry?: Map<string, Registered>
+}
+
+const registry = (globalForCaches.cacheRegistry ??= new Map())
+
+export function registerCache(name: string, source: CacheSource): CacheCounter {
+  // Keep counters across hot reloads, which re-run the registering module
+  const previous = registry.get(name)
+  const entry: Registered = { ...source, hits: previous?.hits ?? 0, misses: previous?.misses ?? 0 }
+  registry.set(name, entry)
+  return {
+    hit: () => void entry.hits++,
+    miss: () => void entry.misses++,
+  }
+}
+
+// Caches register when their module is first imported; load them all so the
+// listing is complete even if nothing has used a cache yet.
+const CACHE_MODULES = [
+  () => import('@/lib/branding'),
+  () => import('@/lib/auth/data-keys'),
+  () => import('@/lib/auth/login-history'),
+  () => import('@/lib/auth/policy-store'),
+  () => import('@/lib/skills/clawhub'),
+  () => import('@/lib/instances/versions'),
+  () => import('@/lib/dashboard/stats'),
+  () => import('@/lib/instances/live'),
+]
+
+async function ensureCachesLoaded(): Promise<void> {
+  await Promise.all(CACHE_MODULES.map((load) => load()))
+}
+
+export async function listCaches(): Promise<CacheInfo[]> {
+  await ensureCachesLoaded()
+  return Promise.all(
+    [...registry].map(async ([name, c]): Promise<CacheInfo> => {
+      const keys = await c.keys()
+      const lookups = c.hits + c.misses
+      return {
+        name,
+        description: c.description,
+        size: keys.length,
+        keys: keys.slice(0, MAX_LISTED_KEYS),
+        hits: c.hits,
+        misses: c.misses,
+        hitRate: lookups > 0 ? c.hits / lookups : null,
+      }
+    }),
+  )
+}
+
+/** Clear one cache (or one key in it); false for an unknown cache name */
+export async function invalidateCache(name: string, key?: string): Promise<boolean> {
+  await ensureCachesLoaded()
+  const cache = registry.get(name)
+  if (!cache) return false
+  await cache.clear(key)
+  return true
+}
+
+/** Clear every cache; returns the names cleared */
+export async function invalidateAllCaches(): Promise<string[]> {
+  await ensureCachesLoaded()
+  for (const cache of registry.values()) await cache.clear()
+  return [...registry.keys()]
+}
@@ -1,33 +1,16 @@
 import type { ChatStreamEvent } from '@/types/chat'
 
-export async function* streamChat(
-  body: {
-    instanceId: string
-    agentId: string
-    message: string
-    sessionId?: string
-    attachments?: { name: string; content: string; mimeType: string }[]
-  },
-  signal?: AbortSignal,
-): AsyncGenerator<ChatStreamEvent> {
-  const response = await fetch('/api/v1/chat/send', {
-    method: 'POST',
-    headers: { 'Content-Type': 'application/json' },
-    body: JSON.stringify(body),
-    credentials: 'include',
-    signal,
-  })
-
-  if (!response.ok) {
-    const data = await response.json().catch(() => null)
-    throw new Error(
-      (data as { error?: string } | null)?.error || '发送消息失败',
-    )
-  }
+/** A stream event with its SSE id (run journal number), when it has one */
+export interface ChatStreamEntry {
+  id: number | null
+  event: ChatStreamEvent
+}
 
+async function* readEvents(response: Response): AsyncGenerator<ChatStreamEntry> {
   const reader = response.body!.getReader()
   const decoder = new TextDecoder()
   let buffer = ''
+  let id: number | null = null
 
   while (true) {
     const { done, value } = await reader.read()
@@ -38,14 +21,74 @@ export async function* streamChat(
     buffer = lines.pop() || ''
 
     for (const line of lines) {
-      if (line.startsWith('data: ')) {
+      if (line.startsWith('id: ')) {
+        id = Number(line.slice(4)) || null
+      } else if (line.startsWith('data: ')) {
         try {
           const event = JSON.parse(line.slice(6)) as ChatStreamEvent
-          yield event
+          yield { id, event }
         } catch {
           // skip malformed
         }
+        id = null
       }
     }
   }
 }
+
+async function ensureOk(response: Response, fallback: string): Promise<void> {
+  if (response.ok) return
+  const data = await response.json().catch(() => null)
+  throw new Error((data as { error?: string } | null)?.error || fallback)
+}
+
+export async function* streamChat(
+  body: {
+    instanceId: string
+    agentId: string
+    message: string
+    sessionId?: string
+    attachments?: { fileId: string }[]
+  },
+  signal?: AbortSignal,
+): AsyncGenerator<ChatStreamEntry> {
+  const response = await fetch('/api/v1/chat/send', {
+    method: 'POST',
+    headers: { 'Content-Type': 'application/json' },
+    body: JSON.stringify(body),
+    credentials: 'include',
+    signal,
+  })
+
+  await ensureOk(response, '发送消息失败')
+  yield* readEvents(response)
+}
+
+/** Upload an attachment; messages reference it by id */
+export async function uploadChatFile(file: File): Promise<{ id: string }> {
+  const formData = new FormData()
+  formData.append('file', file)
+  const response = await fetch('/api/v1/files', {
+    method: 'POST',
+    body: formData,
+    credentials: 'include',
+  })
+
+  await ensureOk(response, '上传文件失败')
+  return ((await response.json()) as { file: { id: string } }).file
+}
+
+/** Re-attach to a run whose stream broke, from the event after `after` */
+export async function* resumeChatRun(
+  runId: string,
+  after: number,
+  signal?: AbortSignal,
+): AsyncGenerator<ChatStreamEntry> {
+  const response = await fetch(`/api/v1/chat/runs/${runId}/stream?after=${after}`, {
+    credentials: 'include',
+    signal,
+  })
+
+  await ensureOk(response, '重新连接失败')
+  yield* readEvents(response)
+}
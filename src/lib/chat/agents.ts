@@ -0,0 +1,106 @@
+import { prisma } from '@/lib/db'
+import { registry, ensureRegistryInitialized } from '@/lib/gateway/registry'
+import { autoRegisterAgents, isAgentVisible } from '@/lib/agents/helpers'
+import { getUserInstanceIds } from '@/lib/instances/access'
+import { iconUrl } from '@/lib/files/icons'
+import type { AuthUser } from '@/types/auth'
+import type { ChatAgentInfo } from '@/types/chat'
+import type { AgentCategory } from '@/types/agent'
+
+/**
+ * Agents the user can chat with: live agents on reachable instances they
+ * have access to, filtered by AgentMeta visibility.
+ */
+export async function listChatAgents(user: AuthUser): Promise<ChatAgentInfo[]> {
+  await ensureRegistryInitialized()
+
+  const agents: ChatAgentInfo[] = []
+
+  // Determine which instances the user can access
+  let instanceIds: string[]
+
+  if (user.role === 'SYSTEM_ADMIN') {
+    const instances = await prisma.instance.findMany({
+      where: { status: { in: ['ONLINE', 'DEGRADED'] } },
+      select: { id: true, name: true },
+    })
+    instanceIds = instances.map((i) => i.id)
+  } else {
+    // Instances granted to the department or to the user directly
+    const granted = await getUserInstanceIds(user)
+    if (granted.length === 0) {
+      return []
+    }
+    const instances = await prisma.instance.findMany({
+      where: { id: { in: granted }, status: { in: ['ONLINE', 'DEGRADED'] } },
+      select: { id: true },
+    })
+    instanceIds = instances.map((i) => i.id)
+  }
+
+  const department = user.departmentId
+    ? await prisma.department.findUnique({
+        where: { id: user.departmentId },
+        select: { defaultInstanceId: true, defaultAgentId: true },
+      })
+    : null
+
+  // Fetch instance name map
+  const instances = await prisma.instance.findMany({
+    where: { id: { in: instanceIds } },
+    select: { id: true, name: true, containerId: true },
+  })
+  const nameMap = new Map(instances.map((i) => [i.id, i.name]))
+  const containerMap = new Map(instances.map((i) => [i.id, !!i.containerId]))
+
+  await Promise.allSettled(
+    instanceIds.map(async (instanceId) => {
+      const adapter = registry.getAdapter(instanceId)
+      const client = registry.getClient(instanceId)
+      if (!adapter || !client) return
+
+      try {
+        const { agents: liveAgents } = await adapter.getAgents(client)
+        const agentIds = liveAgents.map((a) => a.id)
+
+        // Auto-register unknown agents
+        await autoRegisterAgents(instanceId, agentIds, user.id)
+
+        // Fetch AgentMeta for visibility filtering
+        const metas = await prisma.agentMeta.findMany({
+          where: { instanceId },
+          include: {
+            department: { select: { name: true, iconFileId: true } },
+            owner: { select: { name: true } },
+          },
+        })
+        const metaMap = new Map(metas.map((m) => [m.agentId, m]))
+
+        for (const agent of liveAgents) {
+          const meta = metaMap.get(agent.id)
+          // If meta exists, check visibility; if not, treat as DEFAULT (visible to all)
+          if (meta && !isAgentVisible(meta, user)) continue
+
+          agents.push({
+            instanceId,
+            instanceName: nameMap.get(instanceId) || instanceId,
+            agentId: agent.id,
+            agentName: agent.name || agent.id,
+            status: agent.status || 'active',
+            model: agent.model,
+            category: (meta?.category as AgentCategory) ?? 'DEFAULT',
+            hasContainer: containerMap.get(instanceId) ?? false,
+            iconUrl: iconUrl(meta?.iconFileId ?? (meta?.category === 'DEPARTMENT' ? meta.department?.iconFileId : null)),
+            isDefault:
+              department?.defaultInstanceId === instanceId &&
+              department?.defaultAgentId === agent.id,
+          })
+        }
+      } catch {
+        // Skip instances that fail to respond
+      }
+    }),
+  )
+
+  return agents
+}
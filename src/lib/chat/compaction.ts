@@ -0,0 +1,266 @@
+import { prisma } from '@/lib/db'
+import { registry, ensureRegistryInitialized } from '@/lib/gateway/registry'
+import { runThrowawayPrompt } from '@/lib/chat/run'
+import { decryptSnapshots, isSnapshotEncryptionEnabled } from '@/lib/chat/snapshot-crypto'
+import { getActiveDataKey, getDataKey, sealData, openData } from '@/lib/auth/data-keys'
+import { createLogger } from '@/lib/logger'
+import { Prisma, type ChatSession } from '@/generated/prisma'
+
+// Snapshot compaction. Every context reset leaves a snapshot batch behind,
+// so long-lived sessions pile them up. This job, per session:
+//
+//   1. merges batches older than CHAT_COMPACT_AFTER_DAYS into one
+//      `compacted-<session>` batch (default 90; 0 turns the job off)
+//   2. with CHAT_COMPACT_SUMMARY=true, has the session's own agent summarize
+//      the compacted messages, in a throwaway gateway session; the summary is
+//      extended as more messages are compacted and shown at the top of the
+//      session history
+//   3. with CHAT_TRIM_AFTER_DAYS set (default 0 = never), drops thinking, tool
+//      calls and images of compacted messages older than that, and cuts their
+//      text to TRIM_CONTENT_CHARS. When summaries are on, only messages the
+//      summary already covers are trimmed.
+//
+// Summaries are sealed like snapshots when SNAPSHOT_ENCRYPTION is on.
+
+const INTERVAL_MS = 60 * 60_000
+const SESSIONS_PER_TICK = 20
+const TRIM_BATCH = 200
+const TRIM_CONTENT_CHARS = 2000
+// Transcript sent to the agent per summary run; longer backlogs take several runs
+const SUMMARY_INPUT_CHARS = 60_000
+const SUMMARY_MESSAGE_CHARS = 2000
+const SUMMARY_FETCH = 500
+const MAX_SUMMARY_RUNS = 5
+const SUMMARY_TIMEOUT_MS = 5 * 60_000
+const DAY_MS = 24 * 3600_000
+
+const log = createLogger('chat:compaction')
+
+const globalForCompaction = globalThis as unknown as {
+  compactionTimer?: ReturnType<typeof setInterval> | null
+  compactionRunning?: boolean
+}
+
+function daysEnv(name: string, fallback: number): number {
+  const n = parseInt(process.env[name] ?? '', 10)
+  return Number.isFinite(n) && n >= 0 ? n : fallback
+}
+
+const compactedBatchId = (chatSessionId: string) => `compacted-${chatSessionId}`
+
+// ─── Summary ────────────────────────────────────────────────────────
+
+export interface SessionSummary {
+  text: string
+  messagesSummarized: number
+  coversUntil: string
+}
+
+/** The session's compaction summary, decrypted */
+export async function getSessionSummary(chatSessionId: string): Promise<SessionSummary | null> {
+  const row = await prisma.chatSessionSummary.findUnique({ where: { chatSessionId } })
+  if (!row) return null
+  return {
+    text: row.dataKeyId ? openData(await getDataKey(row.dataKeyId), row.summary) : row.summary,
+    messagesSummarized: row.messagesSummarized,
+    coversUntil: row.coversUntil.toISOString(),
+  }
+}
+
+function summaryPrompt(previous: string | null, transcript: string): string {
+  return [
+    'Summarize the following conversation history for the user who had it.',
+    'Keep decisions, facts, open questions and anything they may want to look up later.',
+    'Reply with the summary only, in the language of the conversation, at most 400 words.',
+    ...(previous ? ['', 'Summary of the earlier part of the conversation:', previous] : []),
+    '',
+    'Conversation:',
+    transcript,
+  ].join('\n')
+}
+
+/**
+ * Extend the summary over compacted messages it does not cover yet, one
+ * transcript's worth per run. Returns the time up to which messages are
+ * summarized, or null when there is no summary.
+ */
+async function summarize(session: ChatSession & { user: { departmentId: string | null } }): Promise<Date | null> {
+  const batchId = compactedBatchId(session.id)
+  let current = await getSessionSummary(session.id)
+
+  for (let run = 0; run < MAX_SUMMARY_RUNS; run++) {
+    const since = current ? new Date(current.coversUntil) : null
+    const rows = await decryptSnapshots(
+      await prisma.chatMessageSnapshot.findMany({
+        where: { chatSessionId: session.id, batchId, ...(since ? { createdAt: { gt: since } } : {}) },
+        orderBy: [{ createdAt: 'asc' }, { orderIndex: 'asc' }, { id: 'asc' }],
+        take: SUMMARY_FETCH,
+      }),
+    )
+    if (rows.length === 0) break
+
+    // Whole timestamps only, so coversUntil never splits a batch. The last
+    // one fetched may continue past the fetch, unless it is the only one.
+    const groups: (typeof rows)[] = []
+    for (const row of rows) {
+      const last = groups[groups.length - 1]
+      if (last && last[0].createdAt.getTime() === row.createdAt.getTime()) last.push(row)
+      else groups.push([row])
+    }
+    if (rows.length === SUMMARY_FETCH && groups.length > 1) groups.pop()
+
+    const lines: string[] = []
+    let size = 0
+    let coversUntil = groups[0][0].createdAt
+    for (const group of groups) {
+      if (lines.length > 0 && size >= SUMMARY_INPUT_CHARS) break
+      for (const row of group) {
+        const line = `${row.role === 'user' ? 'User' : 'Assistant'}: ${row.content.slice(0, SUMMARY_MESSAGE_CHARS)}`
+        lines.push(line)
+        size += line.length
+      }
+      coversUntil = group[0].createdAt
+    }
+
+    await ensureRegistryInitialized()
+    const client = registry.getClient(session.instanceId)
+    const adapter = registry.getAdapter(session.instanceId)
+    if (!client || !adapter) return since
+
+    const { text } = await runThrowawayPrompt(
+      client,
+      adapter,
+      session.agentId,
+      summaryPrompt(current?.text ?? null, lines.join('\n\n')),
+      { timeoutMs: SUMMARY_TIMEOUT_MS, tag: 'compaction' },
+    )
+    if (!text.trim()) return since
+
+    let summary = text.trim()
+    let dataKeyId: string | null = null
+    if (isSnapshotEncryptionEnabled()) {
+      const { id, key } = await getActiveDataKey(session.user.departmentId)
+      summary = sealData(key, summary)
+      dataKeyId = id
+    }
+    const messagesSummarized = (current?.messagesSummarized ?? 0) + lines.length
+    await prisma.chatSessionSummary.upsert({
+      where: { chatSessionId: session.id },
+      create: { chatSessionId: session.id, summary, dataKeyId, messagesSummarized, coversUntil },
+      update: { summary, dataKeyId, messagesSummarized, coversUntil },
+    })
+    current = { text: text.trim(), messagesSummarized, coversUntil: coversUntil.toISOString() }
+  }
+
+  return current ? new Date(current.coversUntil) : null
+}
+
+// ─── Trimming ───────────────────────────────────────────────────────
+
+/** Trim compacted messages created at or before `until` */
+async function trim(chatSessionId: string, until: Date): Promise<number> {
+  let trimmed = 0
+  for (;;) {
+    const rows = await prisma.chatMessageSnapshot.findMany({
+      where: { chatSessionId, batchId: compactedBatchId(chatSessionId), createdAt: { lte: until }, trimmedAt: null },
+      select: { id: true, content: true, dataKeyId: true },
+      take: TRIM_BATCH,
+    })
+    if (rows.length === 0) break
+
+    const now = new Date()
+    await prisma.$transaction(
+      await Promise.all(
+        rows.map(async (row) => {
+          const key = row.dataKeyId ? await getDataKey(row.dataKeyId) : null
+          const text = key ? openData(key, row.content) : row.content
+          const cut = text.length > TRIM_CONTENT_CHARS ? `${text.slice(0, TRIM_CONTENT_CHARS)}…` : text
+          return prisma.chatMessageSnapshot.update({
+            where: { id: row.id },
+            data: {
+              content: key ? sealData(key, cut) : cut,
+              thinking: null,
+              toolCalls: Prisma.DbNull,
+              contentBlocks: Prisma.DbNull,
+              trimmedAt: now,
+            },
+          })
+        }),
+      ),
+    )
+    trimmed += rows.length
+  }
+  return trimmed
+}
+
+// ─── Job ────────────────────────────────────────────────────────────
+
+async function compactSession(chatSessionId: string, cutoff: Date, trimCutoff: Date | null): Promise<void> {
+  const session = await prisma.chatSession.findUnique({
+    where: { id: chatSessionId },
+    include: { user: { select: { departmentId: true } } },
+  })
+  if (!session) return
+
+  const { count: merged } = await prisma.chatMessageSnapshot.updateMany({
+    where: { chatSessionId, createdAt: { lt: cutoff }, batchId: { not: compactedBatchId(chatSessionId) } },
+    data: { batchId: compactedBatchId(chatSessionId) },
+  })
+
+  let trimUntil = trimCutoff
+  if (process.env.CHAT_COMPACT_SUMMARY === 'true') {
+    const covered = await summarize(session).catch((err) => {
+      log.warn('Summary failed', { chatSessionId, error: (err as Error).message })
+      return null
+    })
+    // Nothing is trimmed before the summary has it
+    const existing = covered ?? (await prisma.chatSessionSummary.findUnique({ where: { chatSessionId } }))?.coversUntil
+    trimUntil = trimCutoff && existing ? new Date(Math.min(trimCutoff.getTime(), existing.getTime())) : null
+  }
+  const trimmed = trimUntil ? await trim(chatSessionId, trimUntil) : 0
+
+  if (merged > 0 || trimmed > 0) {
+    log.info('Compacted session history', { chatSessionId, merged, trimmed })
+  }
+}
+
+async function tick(): Promise<void> {
+  if (globalForCompaction.compactionRunning) return
+  globalForCompaction.compactionRunning = true
+  try {
+    const afterDays = daysEnv('CHAT_COMPACT_AFTER_DAYS', 90)
+    if (afterDays === 0) return
+    const cutoff = new Date(Date.now() - afterDays * DAY_MS)
+    const trimDays = daysEnv('CHAT_TRIM_AFTER_DAYS', 0)
+    const trimCutoff = trimDays > 0 ? new Date(Math.min(Date.now() - trimDays * DAY_MS, cutoff.getTime())) : null
+
+    // Sessions with batches to merge, or compacted messages left to trim
+    const candidates = await prisma.chatMessageSnapshot.findMany({
+      where: {
+        OR: [
+          { createdAt: { lt: cutoff }, NOT: { batchId: { startsWith: 'compacted-' } } },
+          ...(trimCutoff ? [{ createdAt: { lt: trimCutoff }, trimmedAt: null }] : []),
+        ],
+      },
+      distinct: ['chatSessionId'],
+      select: { chatSessionId: true },
+      take: SESSIONS_PER_TICK,
+    })
+    for (const { chatSessionId } of candidates) {
+      await compactSession(chatSessionId, cutoff, trimCutoff).catch((err) =>
+        log.error('Compaction failed', { chatSessionId, error: (err as Error).message }),
+      )
+    }
+  } catch (err) {
+    log.error('Compaction tick failed', { err })
+  } finally {
+    globalForCompaction.compactionRunning = false
+  }
+}
+
+/** Start the compaction job (idempotent across hot reloads) */
+export function startSnapshotCompaction(): void {
+  if (globalForCompaction.compactionTimer) return
+  void tick()
+  globalForCompaction.compactionTimer = setInterval(() => void tick(), INTERVAL_MS)
+}
@@ -0,0 +1,208 @@
+import type { ChatPriority } from '@/generated/prisma'
+
+// Per-instance chat concurrency. At most CHAT_INSTANCE_CONCURRENCY runs
+// stream from one instance at a time (per TeamClaw process); further sends
+// wait in a queue ordered by the sender's department priority tier, so a
+// HIGH request goes ahead of every queued NORMAL / LOW one (a run that has
+// started is never interrupted). Within a tier the queue is first come,
+// first served. A request still waiting after CHAT_QUEUE_TIMEOUT_MS is
+// refused.
+//
+// CHAT_INSTANCE_CONCURRENCY — parallel runs per instance (default 0: no limit)
+// CHAT_QUEUE_TIMEOUT_MS     — longest wait for a slot (default 60 s)
+
+export const CHAT_PRIORITIES: readonly ChatPriority[] = ['HIGH', 'NORMAL', 'LOW']
+
+const RANK: Record<ChatPriority, number> = { HIGH: 0, NORMAL: 1, LOW: 2 }
+
+// Queue wait histogram buckets, seconds
+const WAIT_BUCKETS = [0.1, 0.5, 1, 2.5, 5, 10, 30, 60]
+
+function intEnv(name: string, fallback: number): number {
+  const n = parseInt(process.env[name] ?? '', 10)
+  return Number.isFinite(n) && n >= 0 ? n : fallback
+}
+
+export class ChatQueueTimeoutError extends Error {
+  constructor() {
+    super('Instance is busy; no chat slot became free in time')
+    this.name = 'ChatQueueTimeoutError'
+  }
+}
+
+interface Waiter {
+  priority: ChatPriority
+  enqueuedAt: number
+  grant: () => void
+}
+
+interface InstanceQueue {
+  running: number
+  waiting: Waiter[]
+}
+
+interface TierStats {
+  waitBuckets: number[]
+  waitCount: number
+  waitSumSeconds: number
+  timeouts: number
+}
+
+const globalForChatQueue = globalThis as unknown as {
+  chatQueues?: Map<string, InstanceQueue>
+  chatQueueStats?: Record<ChatPriority, TierStats>
+}
+
+const queues = (globalForChatQueue.chatQueues ??= new Map<string, InstanceQueue>())
+
+const emptyStats = (): TierStats => ({
+  waitBuckets: WAIT_BUCKETS.map(() => 0),
+  waitCount: 0,
+  waitSumSeconds: 0,
+  timeouts: 0,
+})
+
+const stats = (globalForChatQueue.chatQueueStats ??= {
+  HIGH: emptyStats(),
+  NORMAL: emptyStats(),
+  LOW: emptyStats(),
+})
+
+function observeWait(priority: ChatPriority, ms: number): void {
+  const s = stats[priority]
+  const seconds = ms / 1000
+  s.waitCount++
+  s.waitSumSeconds += seconds
+  WAIT_BUCKETS.forEach((b, i) => {
+    if (seconds <= b) s.waitBuckets[i]++
+  })
+}
+
+export interface ChatSlot {
+  /** Free the slot for the next queued request; safe to call more than once */
+  release(): void
+}
+
+function queueOf(instanceId: string): InstanceQueue {
+  let q = queues.get(instanceId)
+  if (!q) {
+    q = { running: 0, waiting: [] }
+    queues.set(instanceId, q)
+  }
+  return q
+}
+
+function dispatch(instanceId: string, limit: number): void {
+  const q = queues.get(instanceId)
+  if (!q) return
+  while (q.running < limit && q.waiting.length > 0) {
+    q.running++
+    q.waiting.shift()!.grant()
+  }
+  if (q.running === 0 && q.waiting.length === 0) queues.delete(instanceId)
+}
+
+/**
+ * Wait for a run slot on the instance. `onQueued` is told the 1-based queue
+ * position when the request has to wait. Rejects with ChatQueueTimeoutError
+ * after CHAT_QUEUE_TIMEOUT_MS, or with the signal's reason when aborted.
+ */
+export function acquireChatSlot(
+  instanceId: string,
+  priority: ChatPriority,
+  opts: { signal?: AbortSignal; onQueued?: (position: number) => void } = {},
+): Promise<ChatSlot> {
+  const limit = intEnv('CHAT_INSTANCE_CONCURRENCY', 0)
+  if (limit === 0) {
+    observeWait(priority, 0)
+    return Promise.resolve({ release() {} })
+  }
+
+  const q = queueOf(instanceId)
+  let released = false
+  const slot: ChatSlot = {
+    release() {
+      if (released) return
+      released = true
+      q.running--
+      dispatch(instanceId, limit)
+    },
+  }
+
+  if (q.running < limit && q.waiting.length === 0) {
+    q.running++
+    observeWait(priority, 0)
+    return Promise.resolve(slot)
+  }
+
+  return new Promise((resolve, reject) => {
+    const leave = () => {
+      const i = q.waiting.indexOf(waiter)
+      if (i >= 0) q.waiting.splice(i, 1)
+      clearTimeout(timer)
+      opts.signal?.removeEventListener('abort', onAbort)
+      dispatch(instanceId, limit)
+    }
+    const onAbort = () => {
+      leave()
+      reject(opts.signal!.reason)
+    }
+    const waiter: Waiter = {
+      priority,
+      enqueuedAt: Date.now(),
+      grant: () => {
+        clearTimeout(timer)
+        opts.signal?.removeEventListener('abort', onAbort)
+        observeWait(priority, Date.now() - waiter.enqueuedAt)
+        resolve(slot)
+      },
+    }
+    const timer = setTimeout(() => {
+      stats[priority].timeouts++
+      leave()
+      reject(new ChatQueueTimeoutError())
+    }, intEnv('CHAT_QUEUE_TIMEOUT_MS', 60_000))
+
+    // Behind everyone of the same or a higher tier, ahead of lower tiers
+    let at = q.waiting.findIndex((w) => RANK[w.priority] > RANK[priority])
+    if (at < 0) at = q.waiting.length
+    q.waiting.splice(at, 0, waiter)
+    opts.signal?.addEventListener('abort', onAbort, { once: true })
+    opts.onQueued?.(at + 1)
+  })
+}
+
+/** Prometheus gauges / histogram for the chat queue, by tier */
+export function chatQueueToPrometheus(): string {
+  const queued: Record<ChatPriority, number> = { HIGH: 0, NORMAL: 0, LOW: 0 }
+  let running = 0
+  for (const q of queues.values()) {
+    running += q.running
+    for (const w of q.waiting) queued[w.priority]++
+  }
+
+  const lines = [
+    '# HELP teamclaw_chat_queue_running Chat runs holding an instance slot',
+    '# TYPE teamclaw_chat_queue_running gauge',
+    `teamclaw_chat_queue_running ${running}`,
+    '# HELP teamclaw_chat_queue_waiting Chat requests waiting for an instance slot, by tier',
+    '# TYPE teamclaw_chat_queue_waiting gauge',
+    ...CHAT_PRIORITIES.map((p) => `teamclaw_chat_queue_waiting{tier="${p.toLowerCase()}"} ${queued[p]}`),
+    '# HELP teamclaw_chat_queue_wait_seconds Time chat requests waited for an instance slot, by tier',
+    '# TYPE teamclaw_chat_queue_wait_seconds histogram',
+  ]
+  for (const p of CHAT_PRIORITIES) {
+    const s = stats[p]
+    const tier = `tier="${p.toLowerCase()}"`
+    WAIT_BUCKETS.forEach((b, i) => lines.push(`teamclaw_chat_queue_wait_seconds_bucket{${tier},le="${b}"} ${s.waitBuckets[i]}`))
+    lines.push(`teamclaw_chat_queue_wait_seconds_bucket{${tier},le="+Inf"} ${s.waitCount}`)
+    lines.push(`teamclaw_chat_queue_wait_seconds_sum{${tier}} ${s.waitSumSeconds.toFixed(3)}`)
+    lines.push(`teamclaw_chat_queue_wait_seconds_count{${tier}} ${s.waitCount}`)
+  }
+  lines.push('# HELP teamclaw_chat_queue_timeouts_total Chat requests refused after waiting too long, by tier')
+  lines.push('# TYPE teamclaw_chat_queue_timeouts_total counter')
+  for (const p of CHAT_PRIORITIES) {
+    lines.push(`teamclaw_chat_queue_timeouts_total{tier="${p.toLowerCase()}"} ${stats[p].timeouts}`)
+  }
+  return lines.join('\n') + '\n'
+}
@@ -0,0 +1,135 @@
+import { prisma } from '@/lib/db'
+import { decryptSnapshots } from '@/lib/chat/snapshot-crypto'
+import { snapshotRowsToBatches } from '@/lib/chat/snapshot-helpers'
+import { getLiveMessages } from '@/lib/chat/live-messages'
+import { getSessionSummary, type SessionSummary } from '@/lib/chat/compaction'
+import type { ChatMessage, ChatToolCall } from '@/types/chat'
+import type { ChatSession } from '@/generated/prisma'
+
+// Session transcript export: every snapshot batch, oldest first, followed by
+// the live messages of an active session (as stored after its last run, so
+// the gateway is not needed). Images are kept as they were stored: data URLs
+// inline, http URLs as links.
+
+export type ChatExportFormat = 'md' | 'json'
+
+export interface TranscriptMessage extends ChatMessage {
+  /** Snapshot batch the message was archived in; null for live messages */
+  batchId: string | null
+}
+
+export interface SessionTranscript {
+  exportedAt: string
+  session: {
+    id: string
+    title: string | null
+    instanceId: string
+    instanceName: string
+    agentId: string
+    isActive: boolean
+    createdAt: string
+    lastMessageAt: string | null
+  }
+  summary: SessionSummary | null
+  messages: TranscriptMessage[]
+}
+
+export async function buildSessionTranscript(
+  session: ChatSession & { instance: { name: string } },
+): Promise<SessionTranscript> {
+  const [rows, live, summary] = await Promise.all([
+    prisma.chatMessageSnapshot
+      .findMany({
+        where: { chatSessionId: session.id },
+        orderBy: [{ createdAt: 'asc' }, { orderIndex: 'asc' }, { id: 'asc' }],
+      })
+      .then(decryptSnapshots),
+    session.isActive ? getLiveMessages(session.id) : Promise.resolve([]),
+    getSessionSummary(session.id),
+  ])
+
+  return {
+    exportedAt: new Date().toISOString(),
+    session: {
+      id: session.id,
+      title: session.title,
+      instanceId: session.instanceId,
+      instanceName: session.instance.name,
+      agentId: session.agentId,
+      isActive: session.isActive,
+      createdAt: session.createdAt.toISOString(),
+      lastMessageAt: session.lastMessageAt?.toISOString() ?? null,
+    },
+    summary,
+    messages: [
+      ...snapshotRowsToBatches(rows).flatMap((b) => b.messages.map((m) => ({ ...m, batchId: b.batchId }))),
+      ...live.map((m) => ({ ...m, batchId: null })),
+    ],
+  }
+}
+
+// ─── Markdown ───────────────────────────────────────────────────────
+
+/** A fence longer than any backtick run in `text` */
+function fence(text: string, lang = ''): string {
+  const longest = Math.max(2, ...(text.match(/`+/g) ?? []).map((run) => run.length))
+  const marks = '`'.repeat(longest + 1)
+  return `${marks}${lang}\n${text}\n${marks}`
+}
+
+function formatValue(value: unknown): { text: string; lang: string } {
+  if (typeof value === 'string') return { text: value, lang: '' }
+  return { text: JSON.stringify(value ?? null, null, 2), lang: 'json' }
+}
+
+function toolCallToMarkdown(call: ChatToolCall): string {
+  const parts = [`<details>\n<summary>Tool: ${call.toolName}</summary>\n`]
+  if (call.toolInput !== null && call.toolInput !== undefined) {
+    const input = formatValue(call.toolInput)
+    parts.push('Input:\n', fence(input.text, input.lang), '')
+  }
+  if (call.toolOutput !== undefined) {
+    const output = formatValue(call.toolOutput)
+    parts.push('Output:\n', fence(output.text, output.lang), '')
+  }
+  parts.push('</details>')
+  return parts.join('\n')
+}
+
+function messageToMarkdown(msg: TranscriptMessage): string {
+  const parts = [`### ${msg.role === 'user' ? 'User' : 'Assistant'} · ${msg.createdAt}`]
+  if (msg.thinking) {
+    parts.push(`<details>\n<summary>Thinking</summary>\n\n${fence(msg.thinking)}\n\n</details>`)
+  }
+  for (const call of msg.toolCalls ?? []) parts.push(toolCallToMarkdown(call))
+  if (msg.content) parts.push(msg.content)
+  for (const block of msg.contentBlocks ?? []) {
+    if (block.type === 'image' && block.imageUrl) parts.push(`![${block.alt ?? 'image'}](${block.imageUrl})`)
+  }
+  if (msg.error) parts.push(`> Error: ${msg.error}`)
+  return parts.join('\n\n')
+}
+
+export function transcriptToMarkdown(t: SessionTranscript): string {
+  const lines = [
+    `# ${t.session.title || 'Chat session'}`,
+    '',
+    `- Agent: ${t.session.agentId} (${t.session.instanceName})`,
+    `- Started: ${t.session.createdAt}`,
+    `- Exported: ${t.exportedAt}`,
+    `- Messages: ${t.messages.length}`,
+  ]
+  if (t.summary) {
+    lines.push('', '## Summary of compacted history', '', t.summary.text)
+  }
+
+  let batchId: string | null | undefined
+  for (const msg of t.messages) {
+    if (msg.batchId !== batchId) {
+      batchId = msg.batchId
+      lines.push('', '---', '', batchId === null ? '## Current context' : `## Archived context · ${msg.createdAt}`)
+    }
+    lines.push('', messageToMarkdown(msg))
+  }
+  return lines.join('\n') + '\n'
+}
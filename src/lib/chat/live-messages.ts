@@ -0,0 +1,77 @@
+import { prisma } from '@/lib/db'
+import { Prisma } from '@/generated/prisma'
+import { sealRows, decryptSnapshots } from './snapshot-crypto'
+import type { ChatMessage, ChatToolCall, ChatContentBlock } from '@/types/chat'
+
+// Live messages: the current context of an active session, in the ChatMessage
+// table, so that it survives a TeamClaw restart or a gateway that lost the
+// session. They are written as they happen:
+//
+//   - the user's message once the gateway accepted it
+//   - the assistant's reply when the run ends, from the streamed text
+//   - after each run, all rows are replaced with the gateway's chat.history,
+//     which also has the tool calls, thinking and images the stream lacks
+//     (snapshot-helpers saveLiveSnapshot; lib/chat/live-reconciliation retries
+//     sessions where that did not happen)
+//
+// Archiving a session turns its context into a snapshot batch and clears them.
+
+type LiveMessageInput = Pick<ChatMessage, 'role' | 'content' | 'thinking' | 'toolCalls' | 'contentBlocks'>
+
+function toRow(chatSessionId: string, position: number, msg: LiveMessageInput): Prisma.ChatMessageCreateManyInput {
+  return {
+    chatSessionId,
+    position,
+    role: msg.role,
+    content: msg.content,
+    thinking: msg.thinking || null,
+    toolCalls: msg.toolCalls?.length ? (msg.toolCalls as unknown as Prisma.InputJsonValue) : undefined,
+    contentBlocks: msg.contentBlocks?.length ? (msg.contentBlocks as unknown as Prisma.InputJsonValue) : undefined,
+  }
+}
+
+/** Append a message of a run to the session's live messages */
+export async function recordLiveMessage(chatSessionId: string, runId: string, msg: LiveMessageInput): Promise<void> {
+  const { _max } = await prisma.chatMessage.aggregate({ where: { chatSessionId }, _max: { position: true } })
+  const [row] = await sealRows([{ ...toRow(chatSessionId, (_max.position ?? -1) + 1, msg), runId }])
+  await prisma.chatMessage.create({ data: row })
+}
+
+/** Replace the session's live messages with a transcript from the gateway */
+export async function replaceLiveMessages(chatSessionId: string, messages: ChatMessage[]): Promise<void> {
+  const reconciledAt = new Date()
+  const data = await sealRows(
+    messages
+      .filter((m) => m.role === 'user' || m.role === 'assistant')
+      .map((m, i) => ({ ...toRow(chatSessionId, i, m), reconciledAt })),
+  )
+  await prisma.$transaction([
+    prisma.chatMessage.deleteMany({ where: { chatSessionId } }),
+    prisma.chatMessage.createMany({ data }),
+    // Sync clients pick up sessions by updatedAt
+    prisma.chatSession.update({ where: { id: chatSessionId }, data: { updatedAt: reconciledAt } }),
+  ])
+}
+
+/** The session's live messages, decrypted, oldest first */
+export async function getLiveMessages(chatSessionId: string): Promise<ChatMessage[]> {
+  const rows = await decryptSnapshots(
+    await prisma.chatMessage.findMany({
+      where: { chatSessionId },
+      orderBy: [{ position: 'asc' }, { createdAt: 'asc' }],
+    }),
+  )
+  return rows.map((row) => ({
+    id: row.id,
+    role: row.role as 'user' | 'assistant',
+    content: row.content,
+    ...(row.contentBlocks ? { contentBlocks: row.contentBlocks as unknown as ChatContentBlock[] } : {}),
+    ...(row.thinking ? { thinking: row.thinking } : {}),
+    ...(row.toolCalls ? { toolCalls: row.toolCalls as unknown as ChatToolCall[] } : {}),
+    createdAt: row.createdAt.toISOString(),
+  }))
+}
+
+export async function clearLiveMessages(chatSessionId: string): Promise<void> {
+  await prisma.chatMessage.deleteMany({ where: { chatSessionId } })
+}
@@ -0,0 +1,59 @@
+import { prisma } from '@/lib/db'
+import { registry, ensureRegistryInitialized } from '@/lib/gateway/registry'
+import { saveLiveSnapshot } from '@/lib/chat/snapshot-helpers'
+import { createLogger } from '@/lib/logger'
+
+// Live messages written from the stream are replaced with the gateway's
+// transcript right after their run (saveLiveSnapshot). When that did not
+// happen (the gateway timed out, TeamClaw restarted mid-run), this job
+// retries for sessions with stream-written messages older than
+// RECONCILE_AFTER_MS and no run in flight.
+
+const INTERVAL_MS = 5 * 60_000
+const RECONCILE_AFTER_MS = 2 * 60_000
+const SESSIONS_PER_TICK = 20
+
+const log = createLogger('chat:live-reconciliation')
+
+const globalForReconciliation = globalThis as unknown as {
+  liveReconciliationTimer?: ReturnType<typeof setInterval> | null
+  liveReconciliationRunning?: boolean
+}
+
+async function tick(): Promise<void> {
+  if (globalForReconciliation.liveReconciliationRunning) return
+  globalForReconciliation.liveReconciliationRunning = true
+  try {
+    const candidates = await prisma.chatMessage.findMany({
+      where: {
+        reconciledAt: null,
+        createdAt: { lt: new Date(Date.now() - RECONCILE_AFTER_MS) },
+        chatSession: { isActive: true, runs: { none: {} } },
+      },
+      distinct: ['chatSessionId'],
+      select: { chatSession: { select: { id: true, instanceId: true, sessionId: true } } },
+      take: SESSIONS_PER_TICK,
+    })
+    if (candidates.length === 0) return
+
+    await ensureRegistryInitialized()
+    for (const { chatSession: session } of candidates) {
+      const client = registry.getClient(session.instanceId)
+      if (!client) continue
+      await saveLiveSnapshot(session.id, client, session.sessionId).catch((err) =>
+        log.warn('Reconciliation failed', { chatSessionId: session.id, error: (err as Error).message }),
+      )
+    }
+  } catch (err) {
+    log.error('Reconciliation tick failed', { err })
+  } finally {
+    globalForReconciliation.liveReconciliationRunning = false
+  }
+}
+
+/** Start the reconciliation job (idempotent across hot reloads) */
+export function startLiveReconciliation(): void {
+  if (globalForReconciliation.liveReconciliationTimer) return
+  void tick()
+  globalForReconciliation.liveReconciliationTimer = setInterval(() => void tick(), INTERVAL_MS)
+}
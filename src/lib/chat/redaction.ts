@@ -0,0 +1,228 @@
+import { randomUUID } from 'crypto'
+import { prisma } from '@/lib/db'
+import { redis } from '@/lib/redis'
+import { Prisma } from '@/generated/prisma'
+import { REDACTED } from '@/lib/audit'
+import { createLogger } from '@/lib/logger'
+import { DEFAULT_REDACT_FIELDS } from '@/lib/utils/redact'
+import { MAX_USER_REGEX_LENGTH, isUnsafeRegex } from '@/lib/utils/regex'
+
+// Redaction of tool outputs. Tool results can carry secrets (API responses,
+// env dumps, key files), so they are scrubbed before they are streamed as
+// tool_result events, recorded as ToolInvocation rows or stored in live
+// messages and snapshots. The history view of a running session goes through
+// the same filter.
+//
+// Two kinds of rules:
+//   - regex: every match in a string is replaced (default "[redacted]";
+//     "$1"-style group references are allowed in the replacement)
+//   - field: values under the key are replaced, both in JSON values and in
+//     "key=value" / "key: value" text. Keys match ignoring case, '_' and '-',
+//     and by suffix, so "apiKey" also covers OPENAI_API_KEY.
+//
+// Global rules apply everywhere; department rules are added for sessions
+// owned by members of that department. The built-in set (common credential
+// formats and secret field names) can be switched off globally.
+//
+// Each replica compiles the rules once; a save publishes on RELOAD_CHANNEL
+// so the others re-read them instead of redacting with stale rules.
+
+/** SystemConfig key holding tool output redaction rules */
+export const TOOL_REDACTION_KEY = 'tool_output_redaction'
+
+export interface RedactionRule {
+  type: 'regex' | 'field'
+  pattern: string
+  replacement?: string      // regex rules only
+}
+
+export interface ToolRedactionConfig {
+  builtin: boolean
+  rules: RedactionRule[]
+  departments: Record<string, RedactionRule[]>
+}
+
+const BUILTIN_PATTERNS: RedactionRule[] = [
+  { type: 'regex', pattern: '-----BEGIN [A-Z ]*PRIVATE KEY-----[\\s\\S]*?-----END [A-Z ]*PRIVATE KEY-----' },
+  { type: 'regex', pattern: '\\b(?:AKIA|ASIA)[0-9A-Z]{16}\\b' },
+  { type: 'regex', pattern: '\\b(Bearer\\s+)[A-Za-z0-9._~+/-]+=*', replacement: `$1${REDACTED}` },
+  { type: 'regex', pattern: '\\bsk-[A-Za-z0-9_-]{20,}' },
+  { type: 'regex', pattern: '\\bgh[pousr]_[A-Za-z0-9]{36,}\\b' },
+  { type: 'regex', pattern: '\\beyJ[A-Za-z0-9_-]{8,}\\.[A-Za-z0-9_-]{8,}\\.[A-Za-z0-9_-]+' },
+  { type: 'regex', pattern: '\\b([a-z][a-z0-9+.-]*://[^\\s:/@]+:)[^\\s@/]+@', replacement: `$1${REDACTED}@` },
+]
+
+const BUILTIN_FIELDS = [
+  ...DEFAULT_REDACT_FIELDS.filter((f) => f !== 'webhookUrl' && f !== 'xTeamclawSignature'),
+  'secretAccessKey',
+  'credentials',
+  'connectionString',
+]
+
+const DEFAULT_CONFIG: ToolRedactionConfig = { builtin: true, rules: [], departments: {} }
+
+const RELOAD_CHANNEL = 'chat:redaction:reload'
+
+const log = createLogger('chat:redaction')
+
+const globalForRedaction = globalThis as unknown as {
+  toolRedactionConfig?: ToolRedactionConfig
+  toolRedactors?: Map<string, ToolOutputRedactor>
+  redactionSyncStarted?: boolean
+  redactionReplicaId?: string
+}
+
+const replicaId = (globalForRedaction.redactionReplicaId ??= randomUUID())
+
+/** Deep-copy of a value (or string) with secrets replaced */
+export type ToolOutputRedactor = <T>(value: T) => T
+
+export function getToolRedactionConfig(): ToolRedactionConfig {
+  return globalForRedaction.toolRedactionConfig ?? DEFAULT_CONFIG
+}
+
+function setConfig(config: ToolRedactionConfig): void {
+  globalForRedaction.toolRedactionConfig = config
+  globalForRedaction.toolRedactors = new Map()
+}
+
+const normalizeKey = (key: string) => key.replace(/[-_]/g, '').toLowerCase()
+
+// "key=value", "key: value", "\"key\": \"value\"" — value quoted or up to a delimiter
+const KEY_VALUE = /(["']?)([A-Za-z_][A-Za-z0-9_.-]*)\1(\s*[:=]\s*)("(?:[^"\\\n]|\\.)*"|'[^'\n]*'|[^\s,;&}\]]+)/g
+
+/** Rules saved before the pattern checks existed are skipped rather than run */
+function isUsableRule(rule: RedactionRule): boolean {
+  if (rule.type !== 'regex') return true
+  if (rule.pattern.length <= MAX_USER_REGEX_LENGTH && !isUnsafeRegex(rule.pattern)) return true
+  log.warn('Skipping unsafe redaction pattern', { pattern: rule.pattern.slice(0, 50) })
+  return false
+}
+
+function compile(rules: RedactionRule[]): ToolOutputRedactor {
+  const patterns = rules
+    .filter((r) => r.type === 'regex')
+    .map((r) => ({ re: new RegExp(r.pattern, 'g'), replacement: r.replacement ?? REDACTED }))
+  const fields = rules.filter((r) => r.type === 'field').map((r) => normalizeKey(r.pattern))
+
+  const isSensitive = (key: string) => {
+    const k = normalizeKey(key)
+    return fields.some((f) => k === f || k.endsWith(f))
+  }
+
+  const redactText = (text: string) => {
+    let out = text
+    for (const { re, replacement } of patterns) out = out.replace(re, replacement)
+    if (fields.length === 0) return out
+    return out.replace(KEY_VALUE, (match, quote: string, key: string, sep: string, value: string) => {
+      if (!isSensitive(key) || value.includes(REDACTED)) return match
+      const q = value[0] === '"' || value[0] === "'" ? value[0] : ''
+      return `${quote}${key}${quote}${sep}${q}${REDACTED}${q}`
+    })
+  }
+
+  const visit = (value: unknown, depth: number): unknown => {
+    if (typeof value === 'string') return redactText(value)
+    if (depth > 32) return value
+    if (Array.isArray(value)) return value.map((v) => visit(v, depth + 1))
+    if (value && typeof value === 'object') {
+      const out: Record<string, unknown> = {}
+      for (const [k, v] of Object.entries(value)) {
+        out[k] = isSensitive(k) && v != null ? REDACTED : visit(v, depth + 1)
+      }
+      return out
+    }
+    return value
+  }
+
+  if (patterns.length === 0 && fields.length === 0) return (value) => value
+  return (value) => visit(value, 0) as typeof value
+}
+
+/** Redactor for tool output seen by a member of `departmentId` (null: no department) */
+export function getToolOutputRedactor(departmentId: string | null): ToolOutputRedactor {
+  const cacheKey = departmentId ?? ''
+  const cache = (globalForRedaction.toolRedactors ??= new Map())
+  const cached = cache.get(cacheKey)
+  if (cached) return cached
+
+  const config = getToolRedactionConfig()
+  const custom = [...config.rules, ...(departmentId ? config.departments[departmentId] ?? [] : [])]
+  const rules = [
+    ...(config.builtin ? [...BUILTIN_PATTERNS, ...BUILTIN_FIELDS.map((pattern) => ({ type: 'field' as const, pattern }))] : []),
+    ...custom.filter(isUsableRule),
+  ]
+  const redactor = compile(rules)
+  cache.set(cacheKey, redactor)
+  return redactor
+}
+
+/** Redactor for a chat session, by its owner's department */
+export async function getSessionToolOutputRedactor(chatSessionId: string): Promise<ToolOutputRedactor> {
+  const session = await prisma.chatSession.findUnique({
+    where: { id: chatSessionId },
+    select: { user: { select: { departmentId: true } } },
+  })
+  return getToolOutputRedactor(session?.user.departmentId ?? null)
+}
+
+function parseConfig(value: unknown): ToolRedactionConfig {
+  const stored = value as Partial<ToolRedactionConfig> | undefined
+  return {
+    builtin: stored?.builtin !== false,
+    rules: Array.isArray(stored?.rules) ? stored.rules : [],
+    departments: stored?.departments && typeof stored.departments === 'object' ? stored.departments : {},
+  }
+}
+
+/** Apply rules saved through the admin endpoints */
+export async function loadToolRedactionConfig(): Promise<void> {
+  const row = await prisma.systemConfig.findUnique({ where: { key: TOOL_REDACTION_KEY } })
+  setConfig(parseConfig(row?.value))
+}
+
+export async function saveToolRedactionConfig(config: ToolRedactionConfig): Promise<void> {
+  const value = config as unknown as Prisma.InputJsonValue
+  await prisma.systemConfig.upsert({
+    where: { key: TOOL_REDACTION_KEY },
+    update: { value },
+    create: {
+      key: TOOL_REDACTION_KEY,
+      value,
+      description: 'Redaction rules applied to agent tool outputs before streaming and storage',
+    },
+  })
+  setConfig(config)
+  await redis
+    .publish(RELOAD_CHANNEL, replicaId)
+    .catch((err) => log.warn('Could not broadcast redaction reload', { error: (err as Error).message }))
+}
+
+/** Follow rule changes saved on other replicas. Called once at startup. */
+export function startRedactionSync(): void {
+  if (globalForRedaction.redactionSyncStarted) return
+  globalForRedaction.redactionSyncStarted = true
+
+  // A subscribed connection can do nothing else, so it gets its own
+  const subscriber = redis.duplicate()
+  subscriber.on('message', (channel: string, sender: string) => {
+    if (channel !== RELOAD_CHANNEL || sender === replicaId) return
+    loadToolRedactionConfig()
+      .then(() => log.info('Reloaded tool output redaction rules', { from: sender }))
+      .catch((err) => log.error('Redaction reload failed', { error: (err as Error).message }))
+  })
+  subscriber.subscribe(RELOAD_CHANNEL).catch((err) => {
+    log.error('Could not subscribe to redaction reloads', { error: (err as Error).message })
+  })
+}
+
+/** Replace one department's rules; an empty list removes the entry */
+export async function saveDepartmentRedactionRules(departmentId: string, rules: RedactionRule[]): Promise<void> {
+  // Re-read so concurrent edits from another process are not overwritten
+  await loadToolRedactionConfig()
+  const { departments, ...rest } = getToolRedactionConfig()
+  const next = { ...departments }
+  if (rules.length > 0) next[departmentId] = rules
+  else delete next[departmentId]
+  await saveToolRedactionConfig({ ...rest, departments: next })
+}
@@ -0,0 +1,172 @@
+import { prisma } from '@/lib/db'
+import { redis } from '@/lib/redis'
+import { createLogger } from '@/lib/logger'
+import type { ChatStreamEvent } from '@/types/chat'
+
+// Run journal: the events of a chat run, numbered from 1, kept in Redis so a
+// client whose stream broke (network drop, server restart) can re-attach with
+// GET /api/v1/chat/runs/[id]/stream?after=<last id> and pick up where it left
+// off. The SSE `id:` of each event is its number. A journal always ends with
+// a done, aborted or error event.
+//
+// While the gateway is running the run, a ChatRun row records it, so that
+// after a restart lib/chat/run-recovery can subscribe to it again.
+//
+// Events the client can do without are not journaled: queue / reconnect
+// notices, tool progress (superseded by the result), images (large; the
+// history sync after the run brings them in) and instance announcements (the
+// chat polls for those).
+//
+// CHAT_RUN_JOURNAL_TTL_SEC — how long a run's events stay re-attachable
+//                            (default 3600)
+
+const log = createLogger('chat:run-journal')
+
+const SKIPPED: ReadonlySet<ChatStreamEvent['type']> = new Set([
+  'queued',
+  'reconnecting',
+  'tool_progress',
+  'image',
+  'system',
+])
+
+const POLL_INTERVAL_MS = 500
+
+const eventsKey = (runId: string) => `chat_run:${runId}:events`
+const ownerKey = (runId: string) => `chat_run:${runId}:owner`
+
+function intEnv(name: string, fallback: number): number {
+  const n = parseInt(process.env[name] ?? '', 10)
+  return Number.isFinite(n) && n > 0 ? n : fallback
+}
+
+const ttlSeconds = () => intEnv('CHAT_RUN_JOURNAL_TTL_SEC', 3600)
+
+export const isTerminalEvent = (event: ChatStreamEvent) =>
+  event.type === 'done' || event.type === 'aborted' || event.type === 'error'
+
+export interface RunMeta {
+  runId: string
+  chatSessionId: string
+  userId: string
+  instanceId: string
+  agentId: string
+  sessionKey: string
+}
+
+export interface RunJournal {
+  /** Record an event; returns its number, or undefined when it is not journaled */
+  append(event: ChatStreamEvent): number | undefined
+  /** The message reached the gateway: recover this run if the server restarts */
+  track(): void
+  /** The run is over; ends the journal with done unless it already ended */
+  end(): void
+}
+
+function openJournal(meta: RunMeta, seq: number, tracked: boolean): RunJournal {
+  const { runId, ...rest } = meta
+  const key = eventsKey(runId)
+  let ended = false
+
+  return {
+    append(event) {
+      if (ended || SKIPPED.has(event.type)) return undefined
+      if (isTerminalEvent(event)) ended = true
+      seq++
+      redis
+        .multi()
+        .rpush(key, JSON.stringify(event))
+        .expire(key, ttlSeconds())
+        .exec()
+        .catch((err) => log.warn('Journal write failed', { runId: meta.runId, error: (err as Error).message }))
+      return seq
+    },
+    track() {
+      // The run may already be over by the time the send is acknowledged
+      if (tracked || ended) return
+      tracked = true
+      prisma.chatRun
+        .create({ data: { ...rest, id: runId } })
+        .catch((err) => log.warn('Could not record run', { runId: meta.runId, error: (err as Error).message }))
+    },
+    end() {
+      if (!ended) this.append({ type: 'done' })
+      if (!tracked) return
+      tracked = false
+      prisma.chatRun.deleteMany({ where: { id: runId } }).catch(() => {})
+    },
+  }
+}
+
+/** Journal for a new run; the owner is who may re-attach */
+export function createRunJournal(meta: RunMeta): RunJournal {
+  redis
+    .set(ownerKey(meta.runId), meta.userId, 'EX', ttlSeconds())
+    .catch((err) => log.warn('Journal write failed', { runId: meta.runId, error: (err as Error).message }))
+  return openJournal(meta, 0, false)
+}
+
+/** Continue the journal of a run recovered after a restart */
+export async function reopenRunJournal(meta: RunMeta): Promise<RunJournal> {
+  return openJournal(meta, await redis.llen(eventsKey(meta.runId)), true)
+}
+
+export async function getRunOwner(runId: string): Promise<string | null> {
+  return redis.get(ownerKey(runId))
+}
+
+export interface JournaledEvent {
+  id: number
+  event: ChatStreamEvent
+}
+
+/** Events after number `after` */
+export async function readRunEvents(runId: string, after: number): Promise<JournaledEvent[]> {
+  const raw = await redis.lrange(eventsKey(runId), after, -1)
+  return raw.map((item, i) => ({ id: after + i + 1, event: JSON.parse(item) as ChatStreamEvent }))
+}
+
+/**
+ * Deliver the run's events after `after` as they are journaled, until the
+ * journal ends, nothing new arrives for CHAT_RUN_IDLE_MS, or the returned
+ * stop function is called. `onEnd` runs once in every case.
+ */
+export function followRun(
+  runId: string,
+  after: number,
+  onEvent: (entry: JournaledEvent) => void,
+  onEnd: () => void,
+): () => void {
+  const idleMs = intEnv('CHAT_RUN_IDLE_MS', 3 * 60_000)
+  let stopped = false
+  let lastActivity = Date.now()
+  let timer: ReturnType<typeof setTimeout> | null = null
+
+  const stop = () => {
+    if (stopped) return
+    stopped = true
+    if (timer) clearTimeout(timer)
+    onEnd()
+  }
+
+  const poll = async () => {
+    timer = null
+    try {
+      for (const entry of await readRunEvents(runId, after)) {
+        if (stopped) return
+        after = entry.id
+        lastActivity = Date.now()
+        onEvent(entry)
+        if (isTerminalEvent(entry.event)) return stop()
+      }
+    } catch (err) {
+      log.warn('Journal read failed', { runId, error: (err as Error).message })
+    }
+    if (stopped) return
+    if (Date.now() - lastActivity > idleMs) return stop()
+    timer = setTimeout(poll, POLL_INTERVAL_MS)
+  }
+
+  void poll()
+  return stop
+}
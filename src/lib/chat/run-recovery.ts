@@ -0,0 +1,207 @@
+import { prisma } from '@/lib/db'
+import { registry, ensureRegistryInitialized } from '@/lib/gateway/registry'
+import { createLogger } from '@/lib/logger'
+import { guardRun } from '@/lib/chat/stream-guard'
+import { reopenRunJournal, readRunEvents, type RunJournal } from '@/lib/chat/run-journal'
+import { extractText, extractThinking, saveLiveSnapshot, stripFinalTags } from '@/lib/chat/snapshot-helpers'
+import { recordLiveMessage } from '@/lib/chat/live-messages'
+import { createToolRecorder } from '@/lib/chat/tool-invocations'
+import { getToolOutputRedactor } from '@/lib/chat/redaction'
+import { requiresApproval, GATEWAY_APPROVAL_TOOL } from '@/lib/chat/tool-approvals'
+import { loadEgressPolicy, checkToolCall, describeViolation, recordEgressViolation } from '@/lib/egress-policy'
+import type { ChatStreamEvent } from '@/types/chat'
+import type { ChatHistoryMessage } from '@/types/gateway'
+import type { ChatRun } from '@/generated/prisma'
+
+// Runs a server restart interrupted. On startup every ChatRun row is a run
+// the previous process was relaying; each one is followed again from the
+// gateway's events (text, thinking, tool calls, the end of the run) into its
+// journal, where the client re-attaches. The gateway keeps running agents
+// while TeamClaw is down, but events sent meanwhile are lost: deltas carry
+// the whole message so far, so text catches up, while tool calls made during
+// the gap only show up in the history sync after the run.
+//
+// Nobody is watching a resumed run to answer tool confirmation prompts, so a
+// call that needs one stops the run (gateway exec approvals are denied).
+// Egress policy and output redaction apply as for any run.
+
+const log = createLogger('chat:run-recovery')
+
+type GatewayEvent = Record<string, unknown> | undefined
+
+const contentOf = (message: unknown) => (message as { content?: ChatHistoryMessage['content'] } | undefined)?.content
+
+function interrupt(journal: RunJournal, reason: string): void {
+  journal.append({ type: 'error', error: reason })
+  journal.end()
+}
+
+async function resumeRun(run: ChatRun): Promise<void> {
+  const journal = await reopenRunJournal({
+    runId: run.id,
+    chatSessionId: run.chatSessionId,
+    userId: run.userId,
+    instanceId: run.instanceId,
+    agentId: run.agentId,
+    sessionKey: run.sessionKey,
+  })
+
+  const client = registry.getClient(run.instanceId)
+  const user = await prisma.user.findUnique({ where: { id: run.userId }, select: { departmentId: true } })
+  if (!client || !user) {
+    return interrupt(journal, 'The run was interrupted by a server restart')
+  }
+
+  const departmentId = user.departmentId
+  const egressPolicy = departmentId ? await loadEgressPolicy(departmentId) : null
+  const redactToolOutput = getToolOutputRedactor(departmentId)
+  const tools = createToolRecorder({
+    chatSessionId: run.chatSessionId,
+    runId: run.id,
+    userId: run.userId,
+    departmentId,
+    instanceId: run.instanceId,
+    agentId: run.agentId,
+  })
+
+  // Text the client already has; the next delta continues from there
+  const past = await readRunEvents(run.id, 0)
+  let lastText = past.map(({ event }) => (event.type === 'text' ? event.content : '')).join('')
+  let lastThinking = past.map(({ event }) => (event.type === 'thinking' ? event.content : '')).join('')
+
+  let ended = false
+  const abort = () => client.request('chat.abort', { sessionKey: run.sessionKey, runId: run.id }).catch(() => {})
+
+  function finish(event: ChatStreamEvent) {
+    if (ended) return
+    ended = true
+    runGuard.stop()
+    unsubChat()
+    unsubAgent()
+    unsubExecApproval()
+    tools.abortOpen()
+    journal.append(event)
+    journal.end()
+  }
+
+  function emitMessage(message: unknown) {
+    const thinking = extractThinking(contentOf(message))
+    if (thinking.length > lastThinking.length && thinking.startsWith(lastThinking)) {
+      journal.append({ type: 'thinking', content: thinking.slice(lastThinking.length) })
+      lastThinking = thinking
+    }
+    const text = extractText(contentOf(message))
+    if (text.length > lastText.length && text.startsWith(lastText)) {
+      journal.append({ type: 'text', content: text.slice(lastText.length) })
+      lastText = text
+    }
+  }
+
+  const runGuard = guardRun((reason) => {
+    abort()
+    finish({
+      type: 'error',
+      error: reason === 'deadline' ? 'Agent run exceeded the time limit' : 'Agent stopped responding',
+    })
+  })
+
+  const unsubChat = client.on('chat', (payload: unknown) => {
+    const evt = payload as GatewayEvent
+    if (ended || !evt || evt.runId !== run.id) return
+    runGuard.touch()
+
+    if (evt.state === 'delta') {
+      emitMessage(evt.message)
+    } else if (evt.state === 'final') {
+      emitMessage(evt.message)
+      recordLiveMessage(run.chatSessionId, run.id, {
+        role: 'assistant',
+        content: stripFinalTags(lastText),
+        thinking: lastThinking,
+      })
+        .then(() => saveLiveSnapshot(run.chatSessionId, client, run.sessionKey))
+        .catch((err) => log.warn('Live snapshot failed', { runId: run.id, error: (err as Error).message }))
+      finish({ type: 'done' })
+    } else if (evt.state === 'error') {
+      finish({ type: 'error', error: String(evt.errorMessage ?? 'Unknown error') })
+    } else if (evt.state === 'aborted') {
+      finish({ type: 'aborted' })
+    }
+  })
+
+  const unsubAgent = client.on('agent', (payload: unknown) => {
+    const evt = payload as GatewayEvent
+    if (ended || !evt || evt.runId !== run.id || evt.stream !== 'tool') return
+    runGuard.touch()
+
+    const data = (evt.data ?? {}) as Record<string, unknown>
+    const toolName = String(data.name ?? 'tool')
+    const toolCallId = typeof data.toolCallId === 'string' ? data.toolCallId : null
+
+    if (data.phase === 'start') {
+      const violation = egressPolicy && checkToolCall(egressPolicy, toolName, data.args)
+      if (violation) {
+        tools.record(toolName, data.args, 'BLOCKED')
+        abort()
+        void recordEgressViolation(violation, {
+          userId: run.userId,
+          departmentId: egressPolicy!.departmentId,
+          instanceId: run.instanceId,
+          agentId: run.agentId,
+          chatSessionId: run.chatSessionId,
+          ipAddress: 'unknown',
+        })
+        return finish({ type: 'error', error: describeViolation(violation) })
+      }
+      if (requiresApproval(toolName) && toolName !== GATEWAY_APPROVAL_TOOL) {
+        tools.record(toolName, data.args, 'DENIED')
+        abort()
+        return finish({
+          type: 'error',
+          error: `Tool "${toolName}" needs confirmation, which a run resumed after a restart cannot ask for; the run was stopped`,
+        })
+      }
+      tools.start(toolName, toolCallId, data.args)
+      journal.append({ type: 'tool_call', toolName, toolInput: data.args ?? {} })
+    } else if (data.phase === 'result') {
+      const output = redactToolOutput(data.result ?? null)
+      tools.finish(toolName, toolCallId, output, data.isError === true)
+      journal.append({ type: 'tool_result', toolName, toolOutput: output })
+    }
+  })
+
+  const unsubExecApproval = client.on('exec.approval.requested', (payload: unknown) => {
+    const evt = payload as { id?: string; request?: { command?: string; sessionKey?: string } } | undefined
+    if (ended || !evt?.id || evt.request?.sessionKey !== run.sessionKey) return
+    runGuard.touch()
+    client.request('exec.approval.resolve', { id: evt.id, decision: 'deny' }).catch(() => {})
+    tools.record(GATEWAY_APPROVAL_TOOL, { command: evt.request.command }, 'DENIED')
+  })
+
+  log.info('Resumed chat run', { runId: run.id, instanceId: run.instanceId, resumedAfterEvents: past.length })
+}
+
+/**
+ * Follow the runs the previous process left in flight. Called once at
+ * startup; runs that already passed CHAT_RUN_DEADLINE_MS are closed.
+ */
+export async function resumeInterruptedRuns(): Promise<void> {
+  const runs = await prisma.chatRun.findMany()
+  if (runs.length === 0) return
+  await ensureRegistryInitialized()
+
+  const deadlineMs = parseInt(process.env.CHAT_RUN_DEADLINE_MS ?? '', 10) || 10 * 60_000
+  for (const run of runs) {
+    try {
+      if (Date.now() - run.startedAt.getTime() > deadlineMs) {
+        const journal = await reopenRunJournal({ ...run, runId: run.id })
+        interrupt(journal, 'The run was interrupted by a server restart')
+      } else {
+        await resumeRun(run)
+      }
+    } catch (err) {
+      log.error('Could not resume chat run', { runId: run.id, error: (err as Error).message })
+      await prisma.chatRun.deleteMany({ where: { id: run.id } }).catch(() => {})
+    }
+  }
+}
@@ -0,0 +1,89 @@
+import { randomUUID } from 'crypto'
+import type { GatewayAdapter } from '@/lib/gateway/adapter'
+import type { GatewayConnection } from '@/lib/gateway/client'
+
+const DEFAULT_RUN_TIMEOUT_MS = 10 * 60_000
+
+function extractRunText(message: unknown): string {
+  if (!message || typeof message !== 'object') return ''
+  const content = (message as Record<string, unknown>).content
+  if (typeof content === 'string') return content
+  if (!Array.isArray(content)) return ''
+  return content
+    .filter((b): b is { type: string; text: string } =>
+      !!b && typeof b === 'object' && b.type === 'text' && typeof b.text === 'string')
+    .map((b) => b.text)
+    .join('\n')
+    .trim()
+}
+
+/**
+ * Send a message and wait for the run to finish, without streaming.
+ * Used by non-interactive callers (integrations, scheduled jobs) that only
+ * need the final reply. Rejects on gateway error, abort, or timeout.
+ */
+export function runAgentToCompletion(
+  client: GatewayConnection,
+  adapter: GatewayAdapter,
+  sessionKey: string,
+  message: string,
+  opts?: { timeoutMs?: number },
+): Promise<{ runId: string; text: string }> {
+  const runId = randomUUID()
+
+  return new Promise((resolve, reject) => {
+    let settled = false
+    const finish = (fn: () => void) => {
+      if (settled) return
+      settled = true
+      clearTimeout(timer)
+      unsubscribe()
+      fn()
+    }
+
+    const timer = setTimeout(
+      () => finish(() => reject(new Error('Agent run timed out'))),
+      opts?.timeoutMs ?? DEFAULT_RUN_TIMEOUT_MS,
+    )
+
+    const unsubscribe = client.on('chat', (payload: unknown) => {
+      const evt = payload as Record<string, unknown> | undefined
+      if (!evt || evt.runId !== runId) return
+
+      if (evt.state === 'final') {
+        finish(() => resolve({ runId, text: extractRunText(evt.message) }))
+      } else if (evt.state === 'error') {
+        finish(() => reject(new Error(String(evt.errorMessage ?? 'Unknown error'))))
+      } else if (evt.state === 'aborted') {
+        finish(() => reject(new Error('Conversation aborted')))
+      }
+    })
+
+    adapter.sendMessage(client, sessionKey, message, runId).catch((err: Error) => {
+      finish(() => reject(new Error(err.message || 'Failed to send message')))
+    })
+  })
+}
+
+/**
+ * Send one prompt in a fresh session that is deleted afterwards. Used by
+ * health probes that must not leave history behind or reuse context.
+ */
+export async function runThrowawayPrompt(
+  client: GatewayConnection,
+  adapter: GatewayAdapter,
+  agentId: string,
+  message: string,
+  opts: { timeoutMs: number; tag: string },
+): Promise<{ text: string; durationMs: number }> {
+  const sessionKey = `agent:${agentId}:tc:${opts.tag}:${randomUUID()}`
+  const start = Date.now()
+  try {
+    const { text } = await runAgentToCompletion(client, adapter, sessionKey, message, {
+      timeoutMs: opts.timeoutMs,
+    })
+    return { text, durationMs: Date.now() - start }
+  } finally {
+    await adapter.deleteSession(client, sessionKey).catch(() => {})
+  }
+}
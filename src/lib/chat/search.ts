@@ -0,0 +1,183 @@
+import { prisma } from '@/lib/db'
+import { containsInsensitive, dbDialect } from '@/lib/db-dialect'
+import { Prisma } from '@/generated/prisma'
+import type { ChatSearchFragment, ChatSearchResult } from '@/types/chat'
+
+// Full-text search over a user's chat history: archived snapshots and the
+// live messages of active sessions. Both tables carry a generated tsvector
+// column with a GIN index (migration 20260312100000_chat_search).
+//
+// The 'simple' configuration is used since conversations mix languages: no
+// stemming, and Chinese text is matched by whole runs of characters rather
+// than words. Messages sealed under SNAPSHOT_ENCRYPTION are not searchable.
+// SQLite and MySQL have no such index; there a substring match is used,
+// newest first.
+
+// ts_headline delimiters; control characters that do not occur in messages
+const START_SEL = '\u0002'
+const STOP_SEL = '\u0003'
+const HEADLINE_OPTIONS = `StartSel=${START_SEL}, StopSel=${STOP_SEL}, MaxFragments=3, MaxWords=20, MinWords=8, FragmentDelimiter=" … "`
+
+// Fallback fragments: characters kept on each side of the first match
+const FALLBACK_CONTEXT_CHARS = 80
+
+export const MIN_QUERY_LENGTH = 2
+export const MAX_QUERY_LENGTH = 200
+
+interface SearchRow {
+  id: string
+  source: 'snapshot' | 'live'
+  role: string
+  createdAt: Date
+  chatSessionId: string
+  instanceId: string
+  instanceName: string
+  agentId: string
+  title: string | null
+  isActive: boolean
+  headline: string
+}
+
+/** Split a ts_headline result into plain and matched stretches */
+function toFragments(headline: string): ChatSearchFragment[] {
+  const fragments: ChatSearchFragment[] = []
+  for (const part of headline.split(START_SEL)) {
+    const [head, tail] = part.includes(STOP_SEL) ? part.split(STOP_SEL, 2) : [null, part]
+    if (head) fragments.push({ text: head, match: true })
+    if (tail) fragments.push({ text: tail, match: false })
+  }
+  return fragments
+}
+
+/** The text around the first occurrence of `q`, occurrences marked */
+function fallbackFragments(content: string, q: string): ChatSearchFragment[] {
+  const lower = content.toLowerCase()
+  const needle = q.toLowerCase()
+  const first = Math.max(0, lower.indexOf(needle))
+  const start = Math.max(0, first - FALLBACK_CONTEXT_CHARS)
+  const end = Math.min(content.length, first + needle.length + FALLBACK_CONTEXT_CHARS)
+
+  const fragments: ChatSearchFragment[] = []
+  let pos = start
+  for (let i = lower.indexOf(needle, start); i !== -1 && i + needle.length <= end; i = lower.indexOf(needle, pos)) {
+    if (i > pos) fragments.push({ text: content.slice(pos, i), match: false })
+    fragments.push({ text: content.slice(i, i + needle.length), match: true })
+    pos = i + needle.length
+  }
+  if (end > pos) fragments.push({ text: content.slice(pos, end), match: false })
+  if (start > 0) fragments.unshift({ text: '…', match: false })
+  if (end < content.length) fragments.push({ text: '…', match: false })
+  return fragments
+}
+
+async function searchBySubstring(
+  userId: string,
+  q: string,
+  page: number,
+  pageSize: number,
+): Promise<{ results: ChatSearchResult[]; total: number }> {
+  const where = { chatSession: { userId }, dataKeyId: null, content: containsInsensitive(q) }
+  const session = {
+    select: {
+      id: true,
+      instanceId: true,
+      agentId: true,
+      title: true,
+      isActive: true,
+      instance: { select: { name: true } },
+    },
+  }
+  // Enough of each table to fill the page once both are merged
+  const take = page * pageSize
+  const [snapshots, live, snapshotCount, liveCount] = await Promise.all([
+    prisma.chatMessageSnapshot.findMany({ where, include: { chatSession: session }, orderBy: { createdAt: 'desc' }, take }),
+    prisma.chatMessage.findMany({ where, include: { chatSession: session }, orderBy: { createdAt: 'desc' }, take }),
+    prisma.chatMessageSnapshot.count({ where }),
+    prisma.chatMessage.count({ where }),
+  ])
+
+  const hits = [
+    ...snapshots.map((m) => ({ ...m, source: 'snapshot' as const })),
+    ...live.map((m) => ({ ...m, source: 'live' as const })),
+  ]
+    .sort((a, b) => b.createdAt.getTime() - a.createdAt.getTime())
+    .slice((page - 1) * pageSize, take)
+
+  return {
+    results: hits.map((m) => ({
+      messageId: m.id,
+      source: m.source,
+      role: m.role === 'user' ? 'user' : 'assistant',
+      createdAt: m.createdAt.toISOString(),
+      session: {
+        id: m.chatSession.id,
+        instanceId: m.chatSession.instanceId,
+        instanceName: m.chatSession.instance.name,
+        agentId: m.chatSession.agentId,
+        title: m.chatSession.title,
+        isActive: m.chatSession.isActive,
+      },
+      fragments: fallbackFragments(m.content, q),
+    })),
+    total: snapshotCount + liveCount,
+  }
+}
+
+/** Messages of the user's sessions matching `q` (web search syntax), best match first */
+export async function searchChatHistory(
+  userId: string,
+  q: string,
+  page: number,
+  pageSize: number,
+): Promise<{ results: ChatSearchResult[]; total: number }> {
+  if (dbDialect() !== 'postgresql') return searchBySubstring(userId, q, page, pageSize)
+
+  const query = Prisma.sql`websearch_to_tsquery('simple', ${q})`
+  const matches = Prisma.sql`
+    SELECT m."id", 'snapshot' AS "source", m."role", m."content", m."createdAt", m."chatSessionId",
+           ts_rank(m."searchVector", ${query}) AS "rank"
+    FROM "ChatMessageSnapshot" m
+    JOIN "ChatSession" s ON s."id" = m."chatSessionId"
+    WHERE s."userId" = ${userId} AND m."searchVector" @@ ${query}
+    UNION ALL
+    SELECT m."id", 'live' AS "source", m."role", m."content", m."createdAt", m."chatSessionId",
+           ts_rank(m."searchVector", ${query}) AS "rank"
+    FROM "ChatMessage" m
+    JOIN "ChatSession" s ON s."id" = m."chatSessionId"
+    WHERE s."userId" = ${userId} AND m."searchVector" @@ ${query}`
+
+  const [rows, [{ count }]] = await Promise.all([
+    prisma.$queryRaw<SearchRow[]>`
+      SELECT hit."id", hit."source", hit."role", hit."createdAt", hit."chatSessionId",
+             s."instanceId", i."name" AS "instanceName", s."agentId", s."title", s."isActive",
+             ts_headline('simple', hit."content", ${query}, ${HEADLINE_OPTIONS}) AS "headline"
+      FROM (
+        SELECT * FROM (${matches}) AS found
+        ORDER BY found."rank" DESC, found."createdAt" DESC
+        LIMIT ${pageSize} OFFSET ${(page - 1) * pageSize}
+      ) AS hit
+      JOIN "ChatSession" s ON s."id" = hit."chatSessionId"
+      JOIN "Instance" i ON i."id" = s."instanceId"
+      ORDER BY hit."rank" DESC, hit."createdAt" DESC`,
+    prisma.$queryRaw<{ count: bigint }[]>`SELECT COUNT(*) AS "count" FROM (${matches}) AS found`,
+  ])
+
+  return {
+    results: rows.map((r) => ({
+      messageId: r.id,
+      source: r.source,
+      role: r.role === 'user' ? 'user' : 'assistant',
+      createdAt: r.createdAt.toISOString(),
+      session: {
+        id: r.chatSessionId,
+        instanceId: r.instanceId,
+        instanceName: r.instanceName,
+        agentId: r.agentId,
+        title: r.title,
+        isActive: r.isActive,
+      },
+      fragments: toFragments(r.headline),
+    })),
+    total: Number(count),
+  }
+}
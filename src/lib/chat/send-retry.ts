@@ -0,0 +1,44 @@
+import { GatewayConnectionError, type GatewayConnection } from '@/lib/gateway/client'
+
+// Retry of chat.send across brief gateway reconnects. A send that fails
+// because the connection was down or dropped (GatewayConnectionError, not an
+// error answered by the gateway) waits up to CHAT_SEND_RETRY_GRACE_MS for the
+// connection to come back and is sent again, up to CHAT_SEND_RETRY_ATTEMPTS
+// times. Every attempt reuses the run's idempotencyKey, so a send the
+// gateway did receive before the drop is not started twice.
+//
+// CHAT_SEND_RETRY_ATTEMPTS — retries after the first send (default 2; 0 disables)
+// CHAT_SEND_RETRY_GRACE_MS — wait for the reconnect per attempt (default 15 s)
+
+function intEnv(name: string, fallback: number): number {
+  const n = parseInt(process.env[name] ?? '', 10)
+  return Number.isFinite(n) && n >= 0 ? n : fallback
+}
+
+export function sendRetryAttempts(): number {
+  return intEnv('CHAT_SEND_RETRY_ATTEMPTS', 2)
+}
+
+/**
+ * Run `send`, retrying connection failures. `onReconnecting` is called before
+ * each wait for the connection; `isCancelled` stops retrying once the run
+ * has ended otherwise (client gone, aborted).
+ */
+export async function sendWithRetry<T>(
+  client: GatewayConnection,
+  send: () => Promise<T>,
+  opts: { onReconnecting?: (attempt: number, maxAttempts: number) => void; isCancelled?: () => boolean } = {},
+): Promise<T> {
+  const maxAttempts = sendRetryAttempts()
+  const grace = intEnv('CHAT_SEND_RETRY_GRACE_MS', 15_000)
+
+  for (let attempt = 1; ; attempt++) {
+    try {
+      return await send()
+    } catch (err) {
+      if (!(err instanceof GatewayConnectionError) || attempt > maxAttempts || opts.isCancelled?.()) throw err
+      opts.onReconnecting?.(attempt, maxAttempts)
+      if (!(await client.waitForConnection(grace)) || opts.isCancelled?.()) throw err
+    }
+  }
+}
@@ -0,0 +1,78 @@
+import { redis } from '@/lib/redis'
+import { createLogger } from '@/lib/logger'
+
+// One run at a time per chat session. Two tabs sending to the same session
+// would otherwise start interleaved runs on one gateway sessionKey. The lock
+// lives in Redis, so it holds across TeamClaw processes; it is keyed by
+// user, instance and agent (a user has one active session per agent), and
+// its value is the run holding it, so a refused client can re-attach to that
+// run instead.
+//
+// CHAT_SESSION_CONCURRENCY — reject: a send while a run is in progress gets
+//                            409 (default)
+//                            queue: it waits for the run to end, up to
+//                            CHAT_QUEUE_TIMEOUT_MS, then gets 409
+//
+// The lock expires CHAT_RUN_DEADLINE_MS (plus a margin) after it was taken,
+// so a process that dies holding it does not block the session for good.
+
+const log = createLogger('chat:session-lock')
+
+const POLL_INTERVAL_MS = 250
+const EXPIRY_MARGIN_MS = 60_000
+
+// Delete the lock only while it is still ours
+const RELEASE_SCRIPT = `if redis.call("get", KEYS[1]) == ARGV[1] then return redis.call("del", KEYS[1]) else return 0 end`
+
+const lockKey = (userId: string, instanceId: string, agentId: string) =>
+  `chat_session_lock:${userId}:${instanceId}:${agentId}`
+
+function intEnv(name: string, fallback: number): number {
+  const n = parseInt(process.env[name] ?? '', 10)
+  return Number.isFinite(n) && n > 0 ? n : fallback
+}
+
+export class SessionBusyError extends Error {
+  constructor(readonly runId: string | null) {
+    super('Another message is still being answered in this session')
+    this.name = 'SessionBusyError'
+  }
+}
+
+export interface SessionLock {
+  /** Let the next send in; safe to call more than once */
+  release(): void
+}
+
+/**
+ * Take the session's run lock for `runId`. Rejects with SessionBusyError
+ * when another run holds it (after waiting, in queue mode), or with the
+ * signal's reason when aborted while waiting.
+ */
+export async function acquireSessionLock(
+  session: { userId: string; instanceId: string; agentId: string },
+  runId: string,
+  signal?: AbortSignal,
+): Promise<SessionLock> {
+  const key = lockKey(session.userId, session.instanceId, session.agentId)
+  const ttlMs = intEnv('CHAT_RUN_DEADLINE_MS', 10 * 60_000) + EXPIRY_MARGIN_MS
+  const queue = process.env.CHAT_SESSION_CONCURRENCY === 'queue'
+  const giveUpAt = Date.now() + intEnv('CHAT_QUEUE_TIMEOUT_MS', 60_000)
+
+  while ((await redis.set(key, runId, 'PX', ttlMs, 'NX')) !== 'OK') {
+    if (!queue || Date.now() >= giveUpAt) throw new SessionBusyError(await redis.get(key))
+    signal?.throwIfAborted()
+    await new Promise((resolve) => setTimeout(resolve, POLL_INTERVAL_MS))
+  }
+
+  let released = false
+  return {
+    release() {
+      if (released) return
+      released = true
+      redis
+        .eval(RELEASE_SCRIPT, 1, key, runId)
+        .catch((err) => log.warn('Could not release session lock', { key, error: (err as Error).message }))
+    },
+  }
+}
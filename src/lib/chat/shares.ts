@@ -0,0 +1,38 @@
+import { createHash, randomBytes } from 'crypto'
+import type { SessionShare } from '@/generated/prisma'
+
+/** Public page that renders a share; the token is the only credential */
+export const SHARE_PATH_PREFIX = '/share/'
+
+export function generateShareToken(): { token: string; tokenHash: string } {
+  const token = randomBytes(24).toString('base64url')
+  return { token, tokenHash: hashShareToken(token) }
+}
+
+export function hashShareToken(token: string): string {
+  return createHash('sha256').update(token).digest('hex')
+}
+
+export type ShareState = 'active' | 'expired' | 'revoked'
+
+export function shareState(share: Pick<SessionShare, 'expiresAt' | 'revokedAt'>): ShareState {
+  if (share.revokedAt) return 'revoked'
+  if (share.expiresAt <= new Date()) return 'expired'
+  return 'active'
+}
+
+export function toShareResponse(share: SessionShare, token?: string) {
+  return {
+    id: share.id,
+    chatSessionId: share.chatSessionId,
+    passwordProtected: !!share.passwordHash,
+    state: shareState(share),
+    expiresAt: share.expiresAt.toISOString(),
+    revokedAt: share.revokedAt?.toISOString() ?? null,
+    viewCount: share.viewCount,
+    lastViewedAt: share.lastViewedAt?.toISOString() ?? null,
+    createdAt: share.createdAt.toISOString(),
+    // Only known at creation time — the token is not stored
+    ...(token ? { url: `${SHARE_PATH_PREFIX}${token}` } : {}),
+  }
+}
@@ -0,0 +1,166 @@
+import { Prisma } from '@/generated/prisma'
+import { prisma } from '@/lib/db'
+import { getActiveDataKey, getDataKey, sealData, openData } from '@/lib/auth/data-keys'
+import { createLogger } from '@/lib/logger'
+
+// SNAPSHOT_ENCRYPTION=true seals content, thinking and toolCalls of new
+// ChatMessageSnapshot and ChatMessage rows with the session owner's
+// department data key.
+// Reads are transparent either way: rows with a dataKeyId are decrypted,
+// plaintext rows pass through.
+
+const MIGRATION_BATCH = 200
+
+const log = createLogger('chat:encryption')
+
+const globalForSnapshotCrypto = globalThis as unknown as {
+  snapshotMigrationRunning?: boolean
+}
+
+export function isSnapshotEncryptionEnabled(): boolean {
+  return process.env.SNAPSHOT_ENCRYPTION === 'true'
+}
+
+interface SealableFields {
+  content: string
+  thinking?: string | null
+  toolCalls?: unknown
+}
+
+function sealFields<T extends SealableFields>(row: T, key: Buffer): T {
+  const hasToolCalls = row.toolCalls != null && row.toolCalls !== Prisma.DbNull && row.toolCalls !== Prisma.JsonNull
+  return {
+    ...row,
+    content: sealData(key, row.content),
+    thinking: row.thinking ? sealData(key, row.thinking) : row.thinking,
+    // Sealed tool calls are stored as a JSON string
+    toolCalls: hasToolCalls ? sealData(key, JSON.stringify(row.toolCalls)) : row.toolCalls,
+  } as T
+}
+
+async function departmentsForSessions(sessionIds: string[]): Promise<Map<string, string | null>> {
+  const sessions = await prisma.chatSession.findMany({
+    where: { id: { in: sessionIds } },
+    select: { id: true, user: { select: { departmentId: true } } },
+  })
+  return new Map(sessions.map((s) => [s.id, s.user.departmentId]))
+}
+
+/** Rows to create, sealed when encryption is enabled */
+export async function sealRows<T extends SealableFields & { chatSessionId: string; dataKeyId?: string | null }>(
+  data: T[],
+): Promise<T[]> {
+  if (data.length === 0 || !isSnapshotEncryptionEnabled()) return data
+
+  const departments = await departmentsForSessions([...new Set(data.map((d) => d.chatSessionId))])
+  const sealed: T[] = []
+  for (const row of data) {
+    const { id, key } = await getActiveDataKey(departments.get(row.chatSessionId) ?? null)
+    sealed.push({ ...sealFields(row, key), dataKeyId: id })
+  }
+  return sealed
+}
+
+/** createMany for snapshots, sealing them when encryption is enabled */
+export async function createSnapshots(data: Prisma.ChatMessageSnapshotCreateManyInput[]): Promise<void> {
+  if (data.length === 0) return
+  await prisma.chatMessageSnapshot.createMany({ data: await sealRows(data) })
+}
+
+interface OpenableRow {
+  dataKeyId: string | null
+  content: string
+  thinking: string | null
+  toolCalls: Prisma.JsonValue
+}
+
+/** Decrypt sealed snapshot rows in place of their ciphertext */
+export async function decryptSnapshots<T extends OpenableRow>(rows: T[]): Promise<T[]> {
+  const out: T[] = []
+  for (const row of rows) {
+    if (!row.dataKeyId) {
+      out.push(row)
+      continue
+    }
+    const key = await getDataKey(row.dataKeyId)
+    out.push({
+      ...row,
+      content: openData(key, row.content),
+      thinking: row.thinking ? openData(key, row.thinking) : row.thinking,
+      toolCalls: typeof row.toolCalls === 'string' ? JSON.parse(openData(key, row.toolCalls)) : row.toolCalls,
+    })
+  }
+  return out
+}
+
+// ─── Migration of existing rows ─────────────────────────────────────
+
+export interface SnapshotEncryptionStatus {
+  enabled: boolean
+  encrypted: number
+  plaintext: number
+  migrationRunning: boolean
+}
+
+export async function getSnapshotEncryptionStatus(): Promise<SnapshotEncryptionStatus> {
+  const [encrypted, plaintext] = await Promise.all([
+    prisma.chatMessageSnapshot.count({ where: { dataKeyId: { not: null } } }),
+    prisma.chatMessageSnapshot.count({ where: { dataKeyId: null } }),
+  ])
+  return {
+    enabled: isSnapshotEncryptionEnabled(),
+    encrypted,
+    plaintext,
+    migrationRunning: !!globalForSnapshotCrypto.snapshotMigrationRunning,
+  }
+}
+
+/**
+ * Seal every plaintext snapshot in batches. Returns the number of rows
+ * encrypted, or null if a migration is already running.
+ */
+export async function encryptExistingSnapshots(): Promise<number | null> {
+  if (globalForSnapshotCrypto.snapshotMigrationRunning) return null
+  globalForSnapshotCrypto.snapshotMigrationRunning = true
+
+  let total = 0
+  try {
+    for (;;) {
+      const rows = await prisma.chatMessageSnapshot.findMany({
+        where: { dataKeyId: null },
+        select: {
+          id: true,
+          content: true,
+          thinking: true,
+          toolCalls: true,
+          chatSession: { select: { user: { select: { departmentId: true } } } },
+        },
+        take: MIGRATION_BATCH,
+      })
+      if (rows.length === 0) break
+
+      const updates = []
+      for (const row of rows) {
+        const { id: dataKeyId, key } = await getActiveDataKey(row.chatSession.user.departmentId)
+        const sealed = sealFields(row, key)
+        updates.push(
+          prisma.chatMessageSnapshot.update({
+            where: { id: row.id },
+            data: {
+              content: sealed.content,
+              thinking: sealed.thinking,
+              toolCalls: (sealed.toolCalls ?? undefined) as Prisma.InputJsonValue | undefined,
+              dataKeyId,
+            },
+          }),
+        )
+      }
+      await prisma.$transaction(updates)
+      total += rows.length
+      log.info('Encrypted snapshot batch', { batch: rows.length, total })
+    }
+  } finally {
+    globalForSnapshotCrypto.snapshotMigrationRunning = false
+  }
+  return total
+}
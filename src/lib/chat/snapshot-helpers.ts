@@ -1,9 +1,18 @@
 import { randomUUID } from 'crypto'
 import { Prisma } from '@/generated/prisma'
 import { prisma } from '@/lib/db'
-import type { ChatHistoryMessage, ChatHistoryResult } from '@/types/gateway'
-import type { ChatToolCall, ChatContentBlock, ChatMessage } from '@/types/chat'
-import type { GatewayClient } from '@/lib/gateway/client'
+import { createSnapshots } from './snapshot-crypto'
+import { getSessionToolOutputRedactor, type ToolOutputRedactor } from './redaction'
+import type { ChatHistoryMessage } from '@/types/gateway'
+import type { ChatToolCall, ChatContentBlock, ChatMessage, ChatSnapshotBatch } from '@/types/chat'
+import type { ChatMessageSnapshot } from '@/generated/prisma'
+import type { GatewayConnection } from '@/lib/gateway/client'
+import { fetchChatHistory } from '@/lib/gateway/history'
+import { getLiveMessages, replaceLiveMessages, clearLiveMessages } from './live-messages'
+import { chatSessionKey } from '@/lib/agents/canary'
+import { fallbackTitle } from './titles'
+
+const unredacted: ToolOutputRedactor = (value) => value
 
 // ─── Extraction helpers (shared across snapshot + liveMessages) ──────
 
@@ -46,11 +55,26 @@ export function extractContentBlocks(content: ChatHistoryMessage['content']): Ch
   return blocks.length > 0 ? blocks : undefined
 }
 
+const WELCOME_CONTEXT_RE = /<department-welcome>[\s\S]*?<\/department-welcome>\s*/
+const PREAMBLE_RE = /<teamclaw-context>[\s\S]*?<\/teamclaw-context>\s*/
+
+/** Prepend a department welcome prompt to a user's first message. */
+export function wrapWelcomeContext(welcomePrompt: string, message: string): string {
+  return `<department-welcome>\n${welcomePrompt}\n</department-welcome>\n\n${message}`
+}
+
+/** Prepend the agent's managed preamble (lib/agents/preamble) to a message. */
+export function wrapPreamble(preamble: string, message: string): string {
+  return `<teamclaw-context>\n${preamble}\n</teamclaw-context>\n\n${message}`
+}
+
 /**
  * Strip OpenClaw delivery metadata from stored user messages.
  * OpenClaw prepends "Conversation info ... [timestamp]" to user messages.
+ * Also removes injected department welcome context and managed preambles.
  */
-export function stripUserMetadata(text: string): string {
+export function stripUserMetadata(raw: string): string {
+  const text = raw.replace(PREAMBLE_RE, '').replace(WELCOME_CONTEXT_RE, '')
   const match = text.match(/\[[\w\s:+\-]+UTC\]\s*/)
   if (match && match.index !== undefined) {
     const after = text.slice(match.index + match[0].length)
@@ -95,10 +119,12 @@ export function splitThinkingFallback(thinking: string): { thinking: string; tex
 /**
  * Build ChatMessageSnapshot data from gateway chat.history messages.
  * Returns structured data ready for prisma.createMany and the first user message for auto-title.
+ * Tool outputs pass through `redact` before they are stored.
  */
 export function buildSnapshotData(
   chatSessionId: string,
   rawMessages: ChatHistoryMessage[],
+  redact: ToolOutputRedactor = unredacted,
 ): { snapshotData: Prisma.ChatMessageSnapshotCreateManyInput[]; firstUserMessage: string | null } {
   const batchId = randomUUID()
   let orderIndex = 0
@@ -149,7 +175,7 @@ export function buildSnapshotData(
         existing.push({
           toolName: msg.toolName ?? 'tool',
           toolInput: null,
-          toolOutput: extractText(msg.content),
+          toolOutput: redact(extractText(msg.content)),
         })
         lastSnapshot.toolCalls = existing as unknown as Prisma.InputJsonValue
       }
@@ -159,10 +185,41 @@ export function buildSnapshotData(
   return { snapshotData, firstUserMessage }
 }
 
+/** Group stored snapshot rows (ordered by createdAt, orderIndex) into batches */
+export function snapshotRowsToBatches(rows: ChatMessageSnapshot[]): ChatSnapshotBatch[] {
+  const batchMap = new Map<string, { createdAt: string; messages: ChatMessage[] }>()
+  for (const row of rows) {
+    if (!batchMap.has(row.batchId)) {
+      batchMap.set(row.batchId, {
+        createdAt: row.createdAt.toISOString(),
+        messages: [],
+      })
+    }
+    const batch = batchMap.get(row.batchId)!
+    batch.messages.push({
+      id: row.id,
+      role: row.role as 'user' | 'assistant',
+      content: row.content,
+      ...(row.contentBlocks ? { contentBlocks: row.contentBlocks as unknown as ChatContentBlock[] } : {}),
+      ...(row.thinking ? { thinking: row.thinking } : {}),
+      ...(row.toolCalls ? { toolCalls: row.toolCalls as unknown as ChatToolCall[] } : {}),
+      createdAt: row.createdAt.toISOString(),
+    })
+  }
+
+  return Array.from(batchMap.entries()).map(([batchId, data]) => ({
+    batchId,
+    createdAt: data.createdAt,
+    messages: data.messages,
+  }))
+}
+
 // ─── Full archive flow ──────────────────────────────────────────────
 
 /**
  * Archive a session: fetch chat.history → create snapshots → delete OpenClaw session → mark inactive.
+ * When the gateway has no transcript (offline, or it lost the session), the
+ * live messages stored as the session streamed are archived instead.
  * Used by clear-context, conversations/new, and switchActiveSession.
  */
 export async function archiveSession(
@@ -170,32 +227,32 @@ export async function archiveSession(
   instanceId: string,
   agentId: string,
   userId: string,
-  client: GatewayClient,
+  client: GatewayConnection,
   opts?: { keepActive?: boolean },
 ): Promise<void> {
-  const sessionKey = `agent:${agentId}:tc:${userId}`
+  const session = await prisma.chatSession.findUnique({
+    where: { id: sessionId },
+    select: { title: true, routedAgentId: true },
+  })
+  const sessionKey = chatSessionKey(userId, agentId, session?.routedAgentId)
+  let archived = false
 
   try {
-    const rawResult = await client.request('chat.history', { sessionKey, limit: 200 })
-    const historyResult = rawResult as ChatHistoryResult
+    const historyResult = await fetchChatHistory(client, sessionKey, 200)
     const rawMessages = historyResult.messages ?? []
 
     if (rawMessages.length > 0) {
-      const { snapshotData, firstUserMessage } = buildSnapshotData(sessionId, rawMessages)
+      const redact = await getSessionToolOutputRedactor(sessionId)
+      const { snapshotData, firstUserMessage } = buildSnapshotData(sessionId, rawMessages, redact)
 
-      if (snapshotData.length > 0) {
-        await prisma.chatMessageSnapshot.createMany({ data: snapshotData })
-      }
+      await createSnapshots(snapshotData)
+      archived = true
 
       // Auto-generate title from first user message
-      const session = await prisma.chatSession.findUnique({
-        where: { id: sessionId },
-        select: { title: true },
-      })
       if (!session?.title && firstUserMessage) {
         await prisma.chatSession.update({
           where: { id: sessionId },
-          data: { title: firstUserMessage.slice(0, 50) },
+          data: { title: fallbackTitle(firstUserMessage) },
         })
       }
     }
@@ -206,21 +263,37 @@ export async function archiveSession(
     // Gateway offline — continue with DB operations
   }
 
+  if (archived) await clearLiveMessages(sessionId)
+  else await archiveStoredMessages(sessionId)
+
   if (!opts?.keepActive) {
     await prisma.chatSession.update({
       where: { id: sessionId },
-      data: { isActive: false, liveMessages: Prisma.DbNull },
+      data: { isActive: false },
     })
   }
 }
 
+/**
+ * Archive the stored live messages as a snapshot batch and clear them, for
+ * when the gateway cannot provide the transcript.
+ */
+export async function archiveStoredMessages(sessionId: string): Promise<void> {
+  const messages = await getLiveMessages(sessionId)
+  if (messages.length > 0) await persistLiveAsSnapshot(sessionId, messages)
+  await clearLiveMessages(sessionId)
+}
+
 // ─── Live messages (post-run auto-snapshot) ─────────────────────────
 
 /**
- * Transform gateway raw messages to frontend ChatMessage[] format for liveMessages storage.
+ * Transform gateway raw messages to frontend ChatMessage[] format for live message storage.
  * Similar to the history route's transformMessages but without file system image loading.
  */
-export function transformToLiveMessages(rawMessages: ChatHistoryMessage[]): ChatMessage[] {
+export function transformToLiveMessages(
+  rawMessages: ChatHistoryMessage[],
+  redact: ToolOutputRedactor = unredacted,
+): ChatMessage[] {
   const result: ChatMessage[] = []
 
   for (const msg of rawMessages) {
@@ -260,7 +333,7 @@ export function transformToLiveMessages(rawMessages: ChatHistoryMessage[]): Chat
         const tc: ChatToolCall = {
           toolName: msg.toolName ?? 'tool',
           toolInput: null,
-          toolOutput: extractText(msg.content),
+          toolOutput: redact(extractText(msg.content)),
         }
         last.toolCalls = [...(last.toolCalls ?? []), tc]
       }
@@ -279,29 +352,26 @@ export function transformToLiveMessages(rawMessages: ChatHistoryMessage[]): Chat
 }
 
 /**
- * Save liveMessages snapshot after a chat run completes.
- * Fire-and-forget: caller should .catch() errors.
+ * Replace the session's live messages with the gateway's transcript after a
+ * chat run completes. Fire-and-forget: caller should .catch() errors.
  */
 export async function saveLiveSnapshot(
   chatSessionId: string,
-  client: GatewayClient,
+  client: GatewayConnection,
   sessionKey: string,
 ): Promise<void> {
-  const rawResult = await client.request('chat.history', { sessionKey, limit: 200 }, 10_000)
-  const historyResult = rawResult as ChatHistoryResult
+  const historyResult = await fetchChatHistory(client, sessionKey, 200, 10_000)
   const rawMessages = historyResult.messages ?? []
   if (rawMessages.length === 0) return
 
-  const liveMessages = transformToLiveMessages(rawMessages)
-
-  await prisma.chatSession.update({
-    where: { id: chatSessionId },
-    data: { liveMessages: liveMessages as unknown as Prisma.InputJsonValue },
-  })
+  await replaceLiveMessages(
+    chatSessionId,
+    transformToLiveMessages(rawMessages, await getSessionToolOutputRedactor(chatSessionId)),
+  )
 }
 
 /**
- * Persist liveMessages as permanent ChatMessageSnapshot rows.
+ * Persist live messages as permanent ChatMessageSnapshot rows.
  * Used when recovering from a stale session (SIGUSR1 restart).
  */
 export async function persistLiveAsSnapshot(
@@ -321,7 +391,5 @@ export async function persistLiveAsSnapshot(
       toolCalls: msg.toolCalls ? (msg.toolCalls as unknown as Prisma.InputJsonValue) : undefined,
       contentBlocks: msg.contentBlocks ? (msg.contentBlocks as unknown as Prisma.InputJsonValue) : undefined,
     }))
-  if (data.length > 0) {
-    await prisma.chatMessageSnapshot.createMany({ data })
-  }
+  await createSnapshots(data)
 }
@@ -0,0 +1,171 @@
+import type { ChatStreamEvent } from '@/types/chat'
+
+// Guards for the chat SSE path (POST /api/v1/chat/send). Each send
+// subscribes to gateway events until the run ends; if the gateway never
+// answers, those listeners must still be released, and if the browser stops
+// reading, so must the buffered output.
+//
+// CHAT_RUN_DEADLINE_MS    — hard cap on one run, after which the stream is
+//                           force-completed (default 10 min)
+// CHAT_RUN_IDLE_MS        — expire the run when the gateway sends nothing
+//                           for this long (default 3 min)
+// CHAT_STREAM_MAX_BUFFER  — bytes queued for a slow client before optional
+//                           events (thinking, tools, images) are dropped
+//                           (default 1 MiB); text is never dropped
+
+const DROPPABLE: ReadonlySet<ChatStreamEvent['type']> = new Set(['thinking', 'tool_call', 'tool_progress', 'tool_result', 'image'])
+
+function intEnv(name: string, fallback: number): number {
+  const n = parseInt(process.env[name] ?? '', 10)
+  return Number.isFinite(n) && n > 0 ? n : fallback
+}
+
+interface ChatStreamCounters {
+  runs: number
+  active: number
+  deadlineExceeded: number
+  idleExpired: number
+  clientDisconnects: number
+  droppedEvents: number
+}
+
+const globalForChatStream = globalThis as unknown as {
+  chatStreamCounters?: ChatStreamCounters
+}
+
+const counters = (globalForChatStream.chatStreamCounters ??= {
+  runs: 0,
+  active: 0,
+  deadlineExceeded: 0,
+  idleExpired: 0,
+  clientDisconnects: 0,
+  droppedEvents: 0,
+})
+
+export interface SseWriter {
+  /**
+   * Queue an event, with its SSE id when it has one (run journal number);
+   * never waits. Returns false if it was dropped or the stream is closed.
+   */
+  write(event: ChatStreamEvent, id?: number): boolean
+  close(): void
+  readonly closed: boolean
+  readonly dropped: number
+}
+
+/**
+ * Non-blocking SSE writer with a bounded queue. `onGone` fires once when the
+ * client disconnects (a write fails), so the caller can unsubscribe.
+ */
+export function createSseWriter(writable: WritableStream<Uint8Array>, onGone: () => void): SseWriter {
+  const writer = writable.getWriter()
+  const encoder = new TextEncoder()
+  const maxBuffer = intEnv('CHAT_STREAM_MAX_BUFFER', 1024 * 1024)
+  let queued = 0
+  let closed = false
+  let dropped = 0
+
+  const gone = () => {
+    if (closed) return
+    closed = true
+    counters.clientDisconnects++
+    onGone()
+  }
+
+  return {
+    write(event, id) {
+      if (closed) return false
+      const chunk = encoder.encode(`${id != null ? `id: ${id}\n` : ''}data: ${JSON.stringify(event)}\n\n`)
+      if (queued + chunk.byteLength > maxBuffer && DROPPABLE.has(event.type)) {
+        dropped++
+        counters.droppedEvents++
+        return false
+      }
+      queued += chunk.byteLength
+      writer.write(chunk).then(() => {
+        queued -= chunk.byteLength
+      }, gone)
+      return true
+    },
+    close() {
+      if (closed) return
+      closed = true
+      writer.close().catch(() => {})
+    },
+    get closed() {
+      return closed
+    },
+    get dropped() {
+      return dropped
+    },
+  }
+}
+
+export interface RunGuard {
+  /** Record gateway activity, pushing back the idle expiry */
+  touch(): void
+  /** Stop the timers; call once the run has ended */
+  stop(): void
+}
+
+/**
+ * Deadline + idle timers for one run. `onExpire` is called at most once,
+ * with the reason, unless stop() ran first.
+ */
+export function guardRun(onExpire: (reason: 'deadline' | 'idle') => void): RunGuard {
+  const idleMs = intEnv('CHAT_RUN_IDLE_MS', 3 * 60_000)
+  let stopped = false
+  counters.runs++
+  counters.active++
+
+  const expire = (reason: 'deadline' | 'idle') => {
+    if (stopped) return
+    stop()
+    if (reason === 'deadline') counters.deadlineExceeded++
+    else counters.idleExpired++
+    onExpire(reason)
+  }
+
+  const deadline = setTimeout(() => expire('deadline'), intEnv('CHAT_RUN_DEADLINE_MS', 10 * 60_000))
+  let idle = setTimeout(() => expire('idle'), idleMs)
+
+  function stop() {
+    if (stopped) return
+    stopped = true
+    counters.active--
+    clearTimeout(deadline)
+    clearTimeout(idle)
+  }
+
+  return {
+    touch() {
+      if (stopped) return
+      clearTimeout(idle)
+      idle = setTimeout(() => expire('idle'), idleMs)
+    },
+    stop,
+  }
+}
+
+/** Prometheus counters / gauge for chat streams */
+export function chatStreamToPrometheus(): string {
+  const c = counters
+  return [
+    '# HELP teamclaw_chat_stream_active Chat runs currently streaming',
+    '# TYPE teamclaw_chat_stream_active gauge',
+    `teamclaw_chat_stream_active ${c.active}`,
+    '# HELP teamclaw_chat_stream_runs_total Chat runs started',
+    '# TYPE teamclaw_chat_stream_runs_total counter',
+    `teamclaw_chat_stream_runs_total ${c.runs}`,
+    '# HELP teamclaw_chat_stream_expired_total Chat runs force-completed, by reason',
+    '# TYPE teamclaw_chat_stream_expired_total counter',
+    `teamclaw_chat_stream_expired_total{reason="deadline"} ${c.deadlineExceeded}`,
+    `teamclaw_chat_stream_expired_total{reason="idle"} ${c.idleExpired}`,
+    '# HELP teamclaw_chat_stream_client_disconnects_total Chat streams whose client went away mid-run',
+    '# TYPE teamclaw_chat_stream_client_disconnects_total counter',
+    `teamclaw_chat_stream_client_disconnects_total ${c.clientDisconnects}`,
+    '# HELP teamclaw_chat_stream_dropped_events_total Optional events dropped for slow clients',
+    '# TYPE teamclaw_chat_stream_dropped_events_total counter',
+    `teamclaw_chat_stream_dropped_events_total ${c.droppedEvents}`,
+  ].join('\n') + '\n'
+}
@@ -0,0 +1,95 @@
+import { prisma } from '@/lib/db'
+import { registry, ensureRegistryInitialized } from '@/lib/gateway/registry'
+import { runThrowawayPrompt } from '@/lib/chat/run'
+import { createLogger } from '@/lib/logger'
+
+// Session titles. After a session's first exchange, the agent serving it is
+// asked for a short title in a throwaway gateway session. Should that fail,
+// or with CHAT_TITLE_MODE=first_message, the start of the first user message
+// is used. A title is never overwritten: renaming (PATCH
+// /api/v1/chat/sessions/[id]) or an earlier title wins.
+
+export const TITLE_MAX_CHARS = 50
+const TITLE_TIMEOUT_MS = 60_000
+// Excerpts of the exchange included in the prompt
+const EXCERPT_CHARS = 1500
+
+const log = createLogger('chat:titles')
+
+const globalForTitles = globalThis as unknown as { titlesInFlight?: Set<string> }
+const inFlight = (globalForTitles.titlesInFlight ??= new Set<string>())
+
+/** Title from the first user message */
+export function fallbackTitle(firstUserMessage: string): string {
+  return firstUserMessage.trim().slice(0, TITLE_MAX_CHARS)
+}
+
+function titlePrompt(userMessage: string, reply: string): string {
+  return [
+    'Write a title for the conversation below.',
+    `Reply with the title only: at most ${TITLE_MAX_CHARS} characters, no quotes, no trailing punctuation,`,
+    'in the language of the conversation.',
+    '',
+    `User: ${userMessage.slice(0, EXCERPT_CHARS)}`,
+    '',
+    `Assistant: ${reply.slice(0, EXCERPT_CHARS)}`,
+  ].join('\n')
+}
+
+/** First line of the agent's reply, without heading marks, quotes or trailing punctuation */
+function cleanTitle(text: string): string {
+  const line = text.split('\n').map((l) => l.trim()).find(Boolean) ?? ''
+  return line
+    .replace(/^#+\s*/, '')
+    .replace(/^(title|标题)\s*[:：]\s*/i, '')
+    .replace(/^["'“”「『*]+|["'“”」』*]+$/g, '')
+    .replace(/[.。!！]+$/, '')
+    .trim()
+    .slice(0, TITLE_MAX_CHARS)
+}
+
+async function askAgent(session: { instanceId: string; agentId: string; routedAgentId: string | null }, userMessage: string, reply: string) {
+  await ensureRegistryInitialized()
+  const client = registry.getClient(session.instanceId)
+  const adapter = registry.getAdapter(session.instanceId)
+  if (!client || !adapter) return ''
+  const { text } = await runThrowawayPrompt(
+    client,
+    adapter,
+    session.routedAgentId ?? session.agentId,
+    titlePrompt(userMessage, reply),
+    { timeoutMs: TITLE_TIMEOUT_MS, tag: 'title' },
+  )
+  return cleanTitle(text)
+}
+
+/**
+ * Give an untitled session a title after its first exchange. Runs in the
+ * background; the sessions list picks the title up on its next refetch.
+ */
+export async function generateSessionTitle(chatSessionId: string, userMessage: string, reply: string): Promise<void> {
+  if (inFlight.has(chatSessionId)) return
+  inFlight.add(chatSessionId)
+  try {
+    const session = await prisma.chatSession.findUnique({
+      where: { id: chatSessionId },
+      select: { title: true, instanceId: true, agentId: true, routedAgentId: true },
+    })
+    if (!session || session.title) return
+
+    let title = ''
+    if (process.env.CHAT_TITLE_MODE !== 'first_message' && reply.trim()) {
+      title = await askAgent(session, userMessage, reply).catch((err) => {
+        log.warn('Title generation failed', { chatSessionId, error: (err as Error).message })
+        return ''
+      })
+    }
+    title ||= fallbackTitle(userMessage)
+    if (!title) return
+
+    // Renamed meanwhile: keep the user's title
+    await prisma.chatSession.updateMany({ where: { id: chatSessionId, title: null }, data: { title } })
+  } finally {
+    inFlight.delete(chatSessionId)
+  }
+}
@@ -0,0 +1,109 @@
+import { randomUUID } from 'crypto'
+import { globToRegExp } from '@/lib/utils/glob'
+
+// Conversation-level tool confirmation. A tool call matching
+// CHAT_TOOL_APPROVAL_TOOLS pauses the chat stream: later events are held
+// back, the browser gets a `tool_approval_required` event, and the stream
+// resumes or stops once the user answers through
+// POST /api/v1/chat/tool-approvals/[id], or is denied when nobody answers
+// within CHAT_TOOL_APPROVAL_TIMEOUT_MS.
+//
+// When the gateway itself asks before running a command
+// (exec.approval.requested), the answer is forwarded to it with
+// exec.approval.resolve, so the command never runs without consent. Pending
+// approvals live in this process, next to the gateway connection and the
+// SSE stream they belong to.
+//
+// CHAT_TOOL_APPROVAL_TOOLS      — comma-separated tool names, '*' globs,
+//                                 case-insensitive; empty disables prompts
+//                                 (default: shell and file-writing tools)
+// CHAT_TOOL_APPROVAL_TIMEOUT_MS — how long a prompt waits (default 2 min)
+
+export type ToolApprovalOutcome = 'approve' | 'deny' | 'timeout'
+
+/** Tool the gateway pauses on its own (exec approvals) */
+export const GATEWAY_APPROVAL_TOOL = 'exec'
+
+const DEFAULT_TOOLS = 'exec,bash,shell,process,write,edit,apply_patch'
+
+interface PendingApproval {
+  userId: string
+  settle: (outcome: ToolApprovalOutcome) => void
+}
+
+const globalForToolApprovals = globalThis as unknown as {
+  toolApprovals?: Map<string, PendingApproval>
+}
+
+const pending = (globalForToolApprovals.toolApprovals ??= new Map<string, PendingApproval>())
+
+function timeoutMs(): number {
+  const n = parseInt(process.env.CHAT_TOOL_APPROVAL_TIMEOUT_MS ?? '', 10)
+  return Number.isFinite(n) && n > 0 ? n : 2 * 60_000
+}
+
+let compiled: { source: string; patterns: RegExp[] } | null = null
+
+export function requiresApproval(toolName: string): boolean {
+  const source = process.env.CHAT_TOOL_APPROVAL_TOOLS ?? DEFAULT_TOOLS
+  if (compiled?.source !== source) {
+    compiled = {
+      source,
+      patterns: source
+        .split(',')
+        .map((s) => s.trim())
+        .filter(Boolean)
+        .map((p) => new RegExp(globToRegExp(p).source, 'i')),
+    }
+  }
+  return compiled.patterns.some((re) => re.test(toolName))
+}
+
+export interface ToolApproval {
+  id: string
+  expiresAt: Date
+  /** Settles with the user's answer, or 'timeout' */
+  outcome: Promise<ToolApprovalOutcome>
+  /** Drop the prompt without an answer (the stream ended first) */
+  cancel(): void
+}
+
+/** Open a prompt that only `userId` may answer */
+export function requestToolApproval(userId: string): ToolApproval {
+  const id = randomUUID()
+  const ms = timeoutMs()
+  let timer: ReturnType<typeof setTimeout> | undefined
+
+  const outcome = new Promise<ToolApprovalOutcome>((resolve) => {
+    const settle = (o: ToolApprovalOutcome) => {
+      clearTimeout(timer)
+      pending.delete(id)
+      resolve(o)
+    }
+    pending.set(id, { userId, settle })
+    timer = setTimeout(() => settle('timeout'), ms)
+  })
+
+  return {
+    id,
+    expiresAt: new Date(Date.now() + ms),
+    outcome,
+    cancel() {
+      clearTimeout(timer)
+      pending.delete(id)
+    },
+  }
+}
+
+/** Answer a prompt on behalf of the user who owns it */
+export function resolveToolApproval(
+  id: string,
+  userId: string,
+  decision: 'approve' | 'deny',
+): 'ok' | 'not_found' | 'forbidden' {
+  const approval = pending.get(id)
+  if (!approval) return 'not_found'
+  if (approval.userId !== userId) return 'forbidden'
+  approval.settle(decision)
+  return 'ok'
+}
@@ -0,0 +1,200 @@
+import { Prisma } from '@/generated/prisma'
+import { prisma } from '@/lib/db'
+import { getActiveDataKey, getDataKey, sealData, openData } from '@/lib/auth/data-keys'
+import { isSnapshotEncryptionEnabled } from './snapshot-crypto'
+import { createLogger } from '@/lib/logger'
+import type { ToolInvocation, ToolInvocationStatus } from '@/generated/prisma'
+import type { ToolInvocationEntry } from '@/types/chat'
+
+// Tool-call transcript. The chat stream records every tool call it sees
+// (start → result) as a ToolInvocation row, so audits can answer what an
+// agent actually executed without digging through snapshot JSON.
+//
+// Arguments and results over TOOL_INVOCATION_MAX_BYTES (default 16 KiB) each
+// are cut to that many characters of their JSON text and the row is flagged
+// `truncated`. With SNAPSHOT_ENCRYPTION=true they are sealed with the
+// department data key, like snapshots.
+
+const DEFAULT_MAX_BYTES = 16 * 1024
+
+const log = createLogger('chat:tools')
+
+function maxBytes(): number {
+  const n = parseInt(process.env.TOOL_INVOCATION_MAX_BYTES ?? '', 10)
+  return Number.isFinite(n) && n > 0 ? n : DEFAULT_MAX_BYTES
+}
+
+/** The value as stored: itself, or its JSON text cut to the size limit */
+function clip(value: unknown): { value: Prisma.InputJsonValue | undefined; truncated: boolean } {
+  if (value === undefined || value === null) return { value: undefined, truncated: false }
+  const json = JSON.stringify(value)
+  const max = maxBytes()
+  if (Buffer.byteLength(json) <= max) return { value: value as Prisma.InputJsonValue, truncated: false }
+  let cut = json.slice(0, max)
+  while (Buffer.byteLength(cut) > max) cut = cut.slice(0, Math.floor(cut.length * 0.9))
+  return { value: `${cut}…`, truncated: true }
+}
+
+export interface ToolRunContext {
+  chatSessionId: string
+  runId: string
+  userId: string
+  departmentId: string | null
+  instanceId: string
+  agentId: string
+}
+
+interface OpenCall {
+  id: Promise<string | null>
+  startedAt: number
+}
+
+export interface ToolRecorder {
+  start(name: string, toolCallId: string | null, args: unknown): void
+  finish(name: string, toolCallId: string | null, result: unknown, isError: boolean): void
+  /** Close the oldest open call of this tool with a non-result status */
+  close(name: string, toolCallId: string | null, status: ToolInvocationStatus): void
+  /** A call that never got to run (blocked / denied before starting) */
+  record(name: string, args: unknown, status: ToolInvocationStatus): void
+  /** Mark whatever is still open as ABORTED */
+  abortOpen(): void
+}
+
+/** Per-run recorder; writes are fire-and-forget and never throw */
+export function createToolRecorder(run: ToolRunContext): ToolRecorder {
+  const open = new Map<string, OpenCall[]>()
+  const keyOf = (name: string, toolCallId: string | null) => toolCallId ?? `name:${name}`
+
+  let sealer: Promise<{ id: string; key: Buffer } | null> | null = null
+  const getSealer = () =>
+    (sealer ??= isSnapshotEncryptionEnabled() ? getActiveDataKey(run.departmentId) : Promise.resolve(null))
+
+  function seal(value: Prisma.InputJsonValue | undefined, sealKey: { key: Buffer } | null) {
+    if (value === undefined || !sealKey) return value
+    return sealData(sealKey.key, JSON.stringify(value))
+  }
+
+  async function create(
+    name: string,
+    toolCallId: string | null,
+    args: unknown,
+    status: ToolInvocationStatus,
+  ): Promise<string | null> {
+    try {
+      const clipped = clip(args)
+      const sealKey = await getSealer()
+      const row = await prisma.toolInvocation.create({
+        data: {
+          chatSessionId: run.chatSessionId,
+          runId: run.runId,
+          toolCallId,
+          userId: run.userId,
+          instanceId: run.instanceId,
+          agentId: run.agentId,
+          name,
+          args: seal(clipped.value, sealKey),
+          truncated: clipped.truncated,
+          status,
+          dataKeyId: sealKey?.id,
+          ...(status !== 'RUNNING' ? { finishedAt: new Date(), durationMs: 0 } : {}),
+        },
+        select: { id: true },
+      })
+      return row.id
+    } catch (err) {
+      log.error('Failed to record tool call', { err, name })
+      return null
+    }
+  }
+
+  async function update(call: OpenCall, status: ToolInvocationStatus, result?: unknown): Promise<void> {
+    const id = await call.id
+    if (!id) return
+    try {
+      const clipped = clip(result)
+      const sealKey = await getSealer()
+      await prisma.toolInvocation.update({
+        where: { id },
+        data: {
+          status,
+          result: seal(clipped.value, sealKey),
+          ...(clipped.truncated ? { truncated: true } : {}),
+          finishedAt: new Date(),
+          durationMs: Date.now() - call.startedAt,
+        },
+      })
+    } catch (err) {
+      log.error('Failed to record tool result', { err, id })
+    }
+  }
+
+  function take(name: string, toolCallId: string | null): OpenCall | undefined {
+    const queue = open.get(keyOf(name, toolCallId))
+    const call = queue?.shift()
+    if (queue?.length === 0) open.delete(keyOf(name, toolCallId))
+    return call
+  }
+
+  return {
+    start(name, toolCallId, args) {
+      const key = keyOf(name, toolCallId)
+      const queue = open.get(key) ?? []
+      queue.push({ id: create(name, toolCallId, args, 'RUNNING'), startedAt: Date.now() })
+      open.set(key, queue)
+    },
+    finish(name, toolCallId, result, isError) {
+      const call = take(name, toolCallId)
+      if (call) void update(call, isError ? 'ERROR' : 'SUCCESS', result)
+    },
+    close(name, toolCallId, status) {
+      const call = take(name, toolCallId)
+      if (call) void update(call, status)
+    },
+    record(name, args, status) {
+      void create(name, null, args, status)
+    },
+    abortOpen() {
+      for (const queue of open.values()) {
+        for (const call of queue) void update(call, 'ABORTED')
+      }
+      open.clear()
+    },
+  }
+}
+
+// ─── Reading ────────────────────────────────────────────────────────
+
+async function openJson(value: Prisma.JsonValue, key: Buffer | null): Promise<unknown> {
+  if (!key || typeof value !== 'string') return value
+  return JSON.parse(openData(key, value))
+}
+
+/** API shape of stored rows, decrypting sealed ones */
+export async function toToolInvocationEntries(
+  rows: ToolInvocation[],
+  userNames: Map<string, string> = new Map(),
+): Promise<ToolInvocationEntry[]> {
+  const out: ToolInvocationEntry[] = []
+  for (const row of rows) {
+    const key = row.dataKeyId ? await getDataKey(row.dataKeyId) : null
+    out.push({
+      id: row.id,
+      chatSessionId: row.chatSessionId,
+      runId: row.runId,
+      toolCallId: row.toolCallId,
+      userId: row.userId,
+      userName: userNames.get(row.userId) ?? null,
+      instanceId: row.instanceId,
+      agentId: row.agentId,
+      name: row.name,
+      args: await openJson(row.args, key),
+      result: await openJson(row.result, key),
+      truncated: row.truncated,
+      status: row.status,
+      durationMs: row.durationMs,
+      startedAt: row.startedAt.toISOString(),
+      finishedAt: row.finishedAt?.toISOString() ?? null,
+    })
+  }
+  return out
+}
@@ -0,0 +1,97 @@
+import type { ChatStreamToolProgressEvent } from '@/types/chat'
+
+// Partial output of long-running tools (shell, browse, ...). The gateway
+// sends tool events with phase "update" carrying the output so far; the chat
+// stream forwards it as tool_progress events, at most one per call every
+// TOOL_PROGRESS_INTERVAL_MS, with the newest output winning. Only the tail of
+// the output is sent, since each event replaces the previous one on the
+// client. The final result still arrives as tool_result; when a tool's
+// result phase carries no output, the last partial output is what gets
+// recorded.
+//
+// TOOL_PROGRESS_INTERVAL_MS — least time between updates of one call (default 500)
+// TOOL_PROGRESS_MAX_CHARS   — characters of output per update, from the end
+//                             (default 8000)
+
+function intEnv(name: string, fallback: number): number {
+  const n = parseInt(process.env[name] ?? '', 10)
+  return Number.isFinite(n) && n > 0 ? n : fallback
+}
+
+/** Text of a partial result: plain strings, or the text blocks of a content result */
+export function partialOutputText(partial: unknown): string {
+  if (partial == null) return ''
+  if (typeof partial === 'string') return partial
+  const content = (partial as { content?: unknown }).content
+  if (Array.isArray(content)) {
+    return content
+      .map((block) => (block && typeof block === 'object' && 'text' in block ? String(block.text) : ''))
+      .join('')
+  }
+  return JSON.stringify(partial)
+}
+
+interface PendingProgress {
+  toolName: string
+  toolCallId: string | null
+  output: string
+  lastSentAt: number
+  timer: ReturnType<typeof setTimeout> | null
+}
+
+export interface ToolProgressThrottle {
+  /** Latest output of a running call; sent now or when the interval allows */
+  push(toolName: string, toolCallId: string | null, output: string): void
+  /** The call finished: drop any unsent update and return its last output */
+  finish(toolName: string, toolCallId: string | null): string | null
+  /** Cancel every pending update (end of the run) */
+  clear(): void
+}
+
+export function createToolProgressThrottle(
+  emit: (event: ChatStreamToolProgressEvent) => void,
+): ToolProgressThrottle {
+  const interval = intEnv('TOOL_PROGRESS_INTERVAL_MS', 500)
+  const maxChars = intEnv('TOOL_PROGRESS_MAX_CHARS', 8000)
+  const calls = new Map<string, PendingProgress>()
+  const keyOf = (name: string, toolCallId: string | null) => toolCallId ?? `name:${name}`
+
+  function send(call: PendingProgress) {
+    call.timer = null
+    call.lastSentAt = Date.now()
+    emit({
+      type: 'tool_progress',
+      toolName: call.toolName,
+      ...(call.toolCallId ? { toolCallId: call.toolCallId } : {}),
+      output: call.output.length > maxChars ? `…${call.output.slice(-maxChars)}` : call.output,
+    })
+  }
+
+  return {
+    push(toolName, toolCallId, output) {
+      const key = keyOf(toolName, toolCallId)
+      let call = calls.get(key)
+      if (!call) {
+        call = { toolName, toolCallId, output, lastSentAt: 0, timer: null }
+        calls.set(key, call)
+      }
+      call.output = output
+      if (call.timer) return
+      const wait = call.lastSentAt + interval - Date.now()
+      if (wait <= 0) send(call)
+      else call.timer = setTimeout(() => send(call), wait)
+    },
+    finish(toolName, toolCallId) {
+      const key = keyOf(toolName, toolCallId)
+      const call = calls.get(key)
+      if (!call) return null
+      if (call.timer) clearTimeout(call.timer)
+      calls.delete(key)
+      return call.output
+    },
+    clear() {
+      for (const call of calls.values()) if (call.timer) clearTimeout(call.timer)
+      calls.clear()
+    },
+  }
+}
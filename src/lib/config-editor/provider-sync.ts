@@ -1,6 +1,9 @@
 import { prisma } from '@/lib/db'
 import { decryptCredential } from '@/lib/resources/credential-utils'
 import { getProvider } from '@/lib/resources/providers'
+import { createLogger } from '@/lib/logger'
+
+const log = createLogger('resource-sync')
 
 // OpenClaw's default API type — omit when it matches
 const DEFAULT_API_TYPE = 'openai-completions'
@@ -189,13 +192,13 @@ export async function syncProviderToInstances(providerId: string): Promise<void>
         raw: JSON.stringify(patch),
         baseHash: configResult.hash,
       })
-      console.log(`[resource-sync] Synced provider "${providerId}" to instance ${instanceId}`)
+      log.info('Synced provider to instance', { providerId, instanceId })
     }),
   )
 
   for (const [i, r] of results.entries()) {
     if (r.status === 'rejected') {
-      console.warn(`[resource-sync] Failed to sync to ${connectedIds[i]}:`, (r.reason as Error)?.message)
+      log.warn('Failed to sync provider', { providerId, instanceId: connectedIds[i], err: r.reason })
     }
   }
 }
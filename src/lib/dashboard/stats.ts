@@ -0,0 +1,167 @@
+import type { Prisma } from '@/generated/prisma'
+import { prisma } from '@/lib/db'
+import { hasOrgWideView } from '@/lib/auth/permissions'
+import { getProvider } from '@/lib/resources/providers'
+import { getDepartmentInstanceIds } from '@/lib/instances/access'
+import { createLogger } from '@/lib/logger'
+import { registerCache } from '@/lib/caches'
+import type { AuthUser } from '@/types/auth'
+import type { DashboardStats, ProviderDistribution } from '@/types/dashboard'
+
+// Dashboard counters are precomputed into DashboardStat rows instead of
+// running the COUNT queries on every page view. A background job refreshes
+// the org-wide row and one row per department every minute; reads fall back
+// to computing inline when a row is missing or the job has stalled (e.g. dev
+// servers, which don't run background jobs).
+
+export const REFRESH_INTERVAL_MS = 60_000
+const STALE_AFTER_MS = 3 * REFRESH_INTERVAL_MS
+const ACTIVE_USER_DAYS = 7
+
+const log = createLogger('dashboard:stats')
+
+const globalForDashboard = globalThis as unknown as {
+  dashboardStatsTimer?: ReturnType<typeof setInterval> | null
+  dashboardStatsRefreshing?: boolean
+}
+
+export interface ScopedStats {
+  stats: DashboardStats
+  providerDistribution: ProviderDistribution[]
+  refreshedAt: Date
+}
+
+const ORG_SCOPE = 'org'
+
+function deptScope(departmentId: string | null): string {
+  return `dept:${departmentId ?? 'none'}`
+}
+
+// Rows are the cache; dropping one makes the next read recompute it inline
+const statsCounter = registerCache('dashboard-stats', {
+  description: 'Precomputed dashboard counters by scope (refreshed every minute)',
+  keys: async () => (await prisma.dashboardStat.findMany({ select: { scope: true } })).map((r) => r.scope),
+  clear: async (scope) => {
+    await prisma.dashboardStat.deleteMany({ where: scope ? { scope } : {} })
+  },
+})
+
+export function scopeFor(user: AuthUser): string {
+  return hasOrgWideView(user.role) ? ORG_SCOPE : deptScope(user.departmentId)
+}
+
+/** Run the counts for one scope; DEPT_ADMIN scopes only see accessible instances */
+async function computeScope(scope: string): Promise<Omit<ScopedStats, 'refreshedAt'>> {
+  const orgWide = scope === ORG_SCOPE
+  const departmentId = orgWide || scope === deptScope(null) ? null : scope.slice('dept:'.length)
+
+  let instanceFilter: Prisma.InstanceWhereInput | undefined
+  if (departmentId) {
+    instanceFilter = { id: { in: await getDepartmentInstanceIds(departmentId) } }
+  }
+  const userFilter: Prisma.UserWhereInput = orgWide ? { status: 'ACTIVE' } : { status: 'ACTIVE', departmentId }
+  const activeSince = new Date(Date.now() - ACTIVE_USER_DAYS * 86400000)
+
+  const [
+    totalInstances,
+    onlineInstances,
+    totalUsers,
+    activeUsers,
+    totalSessions,
+    totalResources,
+    totalSkills,
+    grouped,
+  ] = await Promise.all([
+    prisma.instance.count({ where: instanceFilter }),
+    prisma.instance.count({ where: { ...instanceFilter, status: 'ONLINE' } }),
+    prisma.user.count({ where: userFilter }),
+    prisma.user.count({ where: { ...userFilter, lastLoginAt: { gte: activeSince } } }),
+    prisma.chatSession.count(),
+    orgWide ? prisma.resource.count() : Promise.resolve(0),
+    prisma.skill.count(),
+    // Provider distribution is SYSTEM_ADMIN / VIEWER only
+    orgWide
+      ? prisma.resource.groupBy({
+          by: ['provider'],
+          _count: { id: true },
+          orderBy: { _count: { id: 'desc' } },
+        })
+      : Promise.resolve([]),
+  ])
+
+  return {
+    stats: {
+      totalInstances,
+      onlineInstances,
+      totalUsers,
+      activeUsers,
+      totalSessions,
+      totalResources,
+      totalSkills,
+    },
+    providerDistribution: grouped.map((g) => ({
+      provider: g.provider,
+      providerName: getProvider(g.provider)?.name ?? g.provider,
+      count: g._count.id,
+    })),
+  }
+}
+
+async function refreshScope(scope: string): Promise<ScopedStats> {
+  const { stats, providerDistribution } = await computeScope(scope)
+  const refreshedAt = new Date()
+  const data = {
+    stats: stats as unknown as Prisma.InputJsonValue,
+    providers: providerDistribution as unknown as Prisma.InputJsonValue,
+    refreshedAt,
+  }
+  await prisma.dashboardStat.upsert({ where: { scope }, create: { scope, ...data }, update: data })
+  return { stats, providerDistribution, refreshedAt }
+}
+
+/**
+ * Recompute every scope: org-wide, each department, and users without one.
+ * Returns the number of scopes refreshed, or null if a refresh is already
+ * running.
+ */
+export async function refreshDashboardStats(): Promise<number | null> {
+  if (globalForDashboard.dashboardStatsRefreshing) return null
+  globalForDashboard.dashboardStatsRefreshing = true
+  try {
+    const departments = await prisma.department.findMany({ select: { id: true } })
+    const scopes = [ORG_SCOPE, deptScope(null), ...departments.map((d) => deptScope(d.id))]
+    for (const scope of scopes) await refreshScope(scope)
+
+    // Departments that have since been deleted
+    await prisma.dashboardStat.deleteMany({ where: { scope: { notIn: scopes } } })
+    return scopes.length
+  } finally {
+    globalForDashboard.dashboardStatsRefreshing = false
+  }
+}
+
+/** Precomputed stats for the caller's scope, computed inline if missing or stale */
+export async function getDashboardStats(user: AuthUser): Promise<ScopedStats> {
+  const scope = scopeFor(user)
+  const row = await prisma.dashboardStat.findUnique({ where: { scope } })
+  if (!row || Date.now() - row.refreshedAt.getTime() > STALE_AFTER_MS) {
+    statsCounter.miss()
+    return refreshScope(scope)
+  }
+  statsCounter.hit()
+  return {
+    stats: row.stats as unknown as DashboardStats,
+    providerDistribution: row.providers as unknown as ProviderDistribution[],
+    refreshedAt: row.refreshedAt,
+  }
+}
+
+/** Start the refresh job (idempotent across hot reloads) */
+export function startDashboardStatsRefresh(): void {
+  if (globalForDashboard.dashboardStatsTimer) return
+  const run = () => {
+    refreshDashboardStats().catch((err) => log.error('Dashboard stats refresh failed', { err }))
+  }
+  run()
+  globalForDashboard.dashboardStatsTimer = setInterval(run, REFRESH_INTERVAL_MS)
+}
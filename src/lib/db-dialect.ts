@@ -0,0 +1,93 @@
+// Database dialect, picked from the DATABASE_URL scheme:
+//   file: / sqlite:      SQLite, for laptop demos and small single-node setups
+//   mysql: / mariadb:    MySQL 8+ / MariaDB 10.6+
+//   anything else        PostgreSQL (the primary, fully supported target)
+// The Prisma client is generated per dialect (see prisma.config.ts and
+// scripts/dialect-schema.ts), so queries that use Postgres-only features go
+// through the helpers below instead of spelling them out.
+//
+// Parity caveats on SQLite / MySQL:
+// - Case-insensitive search relies on the column collation instead of ILIKE:
+//   SQLite's LIKE ignores ASCII case only, MySQL follows the database collation
+//   (the MySQL 8 default, utf8mb4_0900_ai_ci, is case-insensitive; a _bin or
+//   _cs collation breaks search). scripts/check-dialect.ts verifies this in CI.
+// - Json columns are JSON (MySQL) / TEXT (SQLite) rather than jsonb; code only
+//   reads and writes them whole, never filters inside them (except listHas).
+// - Scalar lists (String[], ContainerAction[]) are Postgres-only, so the
+//   derived schemas store them as nullable Json arrays: read them through
+//   stringList() and filter them with listHas(); writing an array works as is.
+// - The hand-written migrations are Postgres SQL; other dialects create and
+//   update their schema with `prisma db push`.
+// - SQLite: createMany cannot skip duplicates, and writes are serialized.
+// - Chat search (lib/chat/search) has no full-text index: it falls back to a
+//   substring match, newest first.
+//
+// Setup: npm install --no-save @prisma/adapter-better-sqlite3 (SQLite) or
+// @prisma/adapter-mariadb (MySQL), then npm run db:sqlite / db:mysql.
+
+export type DbDialect = 'postgresql' | 'sqlite' | 'mysql'
+
+export function dbDialect(url = process.env.DATABASE_URL ?? ''): DbDialect {
+  if (url.startsWith('file:') || url.startsWith('sqlite:')) return 'sqlite'
+  if (url.startsWith('mysql:') || url.startsWith('mariadb:')) return 'mysql'
+  return 'postgresql'
+}
+
+export function isSqlite(): boolean {
+  return dbDialect() === 'sqlite'
+}
+
+/**
+ * Case-insensitive substring filter. Postgres needs `mode: 'insensitive'`
+ * (ILIKE); SQLite and MySQL have no such mode, their LIKE already ignores
+ * case under the default collations.
+ */
+export function containsInsensitive(value: string): { contains: string; mode?: 'insensitive' } {
+  return dbDialect() === 'postgresql' ? { contains: value, mode: 'insensitive' } : { contains: value }
+}
+
+/**
+ * A scalar list column as an array: String[] on Postgres, a Json array (or
+ * null) on the other dialects.
+ */
+export function stringList<T extends string = string>(value: unknown): T[] {
+  return Array.isArray(value) ? value.filter((v): v is T => typeof v === 'string') : []
+}
+
+/**
+ * "The list contains value" filter for a scalar list column. Typed to fit
+ * both the String[] list filter and the Json filter of the derived schemas.
+ */
+export function listHas(value: string): { has?: string; array_contains?: string[] } {
+  return dbDialect() === 'postgresql' ? { has: value } : { array_contains: [value] }
+}
+
+/** createMany's skipDuplicates (ON CONFLICT DO NOTHING / INSERT IGNORE) is not available on SQLite */
+export function skipDuplicatesOption(): { skipDuplicates?: boolean } {
+  return isSqlite() ? {} : { skipDuplicates: true }
+}
+
+/**
+ * Driver adapter for the non-Postgres dialects. The packages are only
+ * installed for those setups, hence require() rather than an import.
+ */
+export function createDialectAdapter(url: string) {
+  if (dbDialect(url) === 'sqlite') {
+    // eslint-disable-next-line @typescript-eslint/no-require-imports
+    const { PrismaBetterSqlite3 } = require('@prisma/adapter-better-sqlite3')
+    return new PrismaBetterSqlite3({ url: url.replace(/^sqlite:/, 'file:') })
+  }
+
+  // The MariaDB connector speaks the MySQL protocol too
+  // eslint-disable-next-line @typescript-eslint/no-require-imports
+  const { PrismaMariaDb } = require('@prisma/adapter-mariadb')
+  const u = new URL(url)
+  return new PrismaMariaDb({
+    host: u.hostname,
+    port: u.port ? Number(u.port) : 3306,
+    user: decodeURIComponent(u.username),
+    password: decodeURIComponent(u.password),
+    database: u.pathname.slice(1),
+    connectionLimit: Number(u.searchParams.get('connection_limit')) || 10,
+  })
+}
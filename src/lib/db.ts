@@ -1,6 +1,7 @@
 import { Pool } from 'pg'
 import { PrismaPg } from '@prisma/adapter-pg'
 import { PrismaClient } from '@/generated/prisma'
+import { createDialectAdapter, dbDialect } from '@/lib/db-dialect'
 
 const globalForDb = globalThis as unknown as {
   pgPool: Pool
@@ -15,12 +16,13 @@ function getPool() {
 }
 
 function createPrismaClient() {
-  const adapter = new PrismaPg(getPool())
+  const adapter =
+    dbDialect() === 'postgresql' ? new PrismaPg(getPool()) : createDialectAdapter(process.env.DATABASE_URL!)
   return new PrismaClient({ adapter })
 }
 
 // In dev, always recreate PrismaClient to pick up schema changes after `prisma generate`.
-// The pg Pool is cached separately to avoid connection exhaustion.
+// The pg Pool (Postgres only) is cached separately to avoid connection exhaustion.
 export const prisma =
   process.env.NODE_ENV === 'production'
     ? (globalForDb.prisma || (globalForDb.prisma = createPrismaClient()))
@@ -0,0 +1,124 @@
+import { Session } from 'inspector'
+import { Readable } from 'stream'
+import { getHeapSnapshot, getHeapStatistics, getHeapSpaceStatistics } from 'v8'
+import { monitorEventLoopDelay, performance, type IntervalHistogram } from 'perf_hooks'
+
+// ─── Runtime profiling ──────────────────────────────────────────────
+//
+// PROFILING_ENABLED=true — expose /api/v1/admin/debug/* (SYSTEM_ADMIN only).
+// Off by default: a heap snapshot pauses the process and can hold secrets.
+//
+// The Node counterparts of pprof / expvar:
+//   CPU profile   — V8 sampling profiler via the inspector (.cpuprofile, opens
+//                   in Chrome DevTools or speedscope)
+//   heap snapshot — v8.getHeapSnapshot() (.heapsnapshot, Chrome DevTools)
+//   report        — process.report: JS + native stacks, libuv handles and
+//                   resource usage, the closest thing to a goroutine dump
+//   stats         — memory, heap spaces, event loop delay / utilization
+
+export const MAX_CPU_PROFILE_SECONDS = 60
+
+export function isProfilingEnabled(): boolean {
+  return process.env.PROFILING_ENABLED === 'true'
+}
+
+const globalForProfiling = globalThis as unknown as {
+  profilingBusy?: boolean
+  eventLoopDelay?: IntervalHistogram
+}
+
+// Started on first use; the histogram covers the time since then
+function eventLoopDelay(): IntervalHistogram {
+  if (!globalForProfiling.eventLoopDelay) {
+    globalForProfiling.eventLoopDelay = monitorEventLoopDelay({ resolution: 20 })
+    globalForProfiling.eventLoopDelay.enable()
+  }
+  return globalForProfiling.eventLoopDelay
+}
+
+const ms = (ns: number) => Math.round(ns / 1e4) / 100
+
+export function getRuntimeStats() {
+  const delay = eventLoopDelay()
+  return {
+    pid: process.pid,
+    nodeVersion: process.version,
+    uptimeSeconds: Math.round(process.uptime()),
+    memory: process.memoryUsage(),
+    resourceUsage: process.resourceUsage(),
+    heap: getHeapStatistics(),
+    heapSpaces: getHeapSpaceStatistics().map((s) => ({
+      name: s.space_name,
+      size: s.space_size,
+      used: s.space_used_size,
+      available: s.space_available_size,
+    })),
+    eventLoop: {
+      utilization: performance.eventLoopUtilization().utilization,
+      delayMs: {
+        min: ms(delay.min),
+        mean: ms(delay.mean),
+        p50: ms(delay.percentile(50)),
+        p99: ms(delay.percentile(99)),
+        max: ms(delay.max),
+      },
+    },
+    activeResources: process.getActiveResourcesInfo(),
+  }
+}
+
+/** Run fn unless another profile / snapshot is in progress (returns null then) */
+async function exclusive<T>(fn: () => Promise<T>): Promise<T | null> {
+  if (globalForProfiling.profilingBusy) return null
+  globalForProfiling.profilingBusy = true
+  try {
+    return await fn()
+  } finally {
+    globalForProfiling.profilingBusy = false
+  }
+}
+
+function post<T>(session: Session, method: string, params?: object): Promise<T> {
+  return new Promise((resolve, reject) => {
+    session.post(method, params ?? {}, (err, result) => (err ? reject(err) : resolve(result as T)))
+  })
+}
+
+/** Sample the CPU for `seconds`; null if a profile is already running */
+export function captureCpuProfile(seconds: number): Promise<string | null> {
+  return exclusive(async () => {
+    const session = new Session()
+    session.connect()
+    try {
+      await post(session, 'Profiler.enable')
+      await post(session, 'Profiler.start')
+      await new Promise((r) => setTimeout(r, seconds * 1000))
+      const { profile } = await post<{ profile: object }>(session, 'Profiler.stop')
+      return JSON.stringify(profile)
+    } finally {
+      session.disconnect()
+    }
+  })
+}
+
+/**
+ * Heap snapshot as a web stream. The process is paused while V8 writes it,
+ * so this is strictly for leak investigations.
+ */
+export function captureHeapSnapshot(): Promise<ReadableStream<Uint8Array> | null> {
+  return exclusive(async () => Readable.toWeb(getHeapSnapshot()) as ReadableStream<Uint8Array>)
+}
+
+/**
+ * Diagnostic report (stacks, handles, resource usage). The environment and
+ * command line are dropped: they carry DATABASE_URL, JWT keys and the like.
+ */
+export function getDiagnosticReport(): object {
+  const report = process.report.getReport() as {
+    header?: { commandLine?: unknown }
+    environmentVariables?: unknown
+  }
+  delete report.environmentVariables
+  if (report.header) delete report.header.commandLine
+  return report
+}
@@ -0,0 +1,136 @@
+import dns from 'dns'
+import { BlockList, isIP, type LookupFunction } from 'net'
+import { createLogger } from '@/lib/logger'
+
+// Outbound destination policy (SSRF guard) for connections to admin-entered
+// addresses: gateway WebSockets, webhook deliveries and resource API tests.
+// Every address a hostname resolves to is checked, so a DNS name pointing at
+// an internal service is refused the same as the literal IP.
+//
+// Always denied: link-local and cloud metadata endpoints (169.254.0.0/16 incl.
+// 169.254.169.254, fe80::/10, fd00:ec2::254, Alibaba Cloud 100.100.100.200),
+// 0.0.0.0/8 and multicast. Loopback and private ranges stay reachable —
+// gateways normally live on localhost, a LAN or a Docker network.
+//
+// OUTBOUND_DENY_CIDRS  — extra ranges to refuse, e.g. "10.0.0.0/8,192.168.0.0/16"
+// OUTBOUND_ALLOW_CIDRS — ranges exempt from every deny rule (allow wins)
+
+const DEFAULT_DENY = [
+  '0.0.0.0/8',
+  '169.254.0.0/16',
+  '100.100.100.200/32',
+  '224.0.0.0/4',
+  'fe80::/10',
+  'fd00:ec2::254/128',
+  'ff00::/8',
+]
+
+const log = createLogger('destination-policy')
+
+export class DestinationDeniedError extends Error {
+  readonly code = 'EDESTDENIED'
+
+  constructor(
+    readonly host: string,
+    readonly address: string,
+  ) {
+    super(host === address
+      ? `Destination ${address} is blocked by the outbound policy`
+      : `Destination ${host} (${address}) is blocked by the outbound policy`)
+    this.name = 'DestinationDeniedError'
+  }
+}
+
+type IpType = 'ipv4' | 'ipv6'
+
+/** Unwrap IPv4-mapped IPv6 (::ffff:a.b.c.d) so it meets the IPv4 rules */
+function normalize(address: string): [string, IpType] | null {
+  const mapped = /^::ffff:(\d+\.\d+\.\d+\.\d+)$/i.exec(address)?.[1]
+  const ip = mapped ?? address
+  const family = isIP(ip)
+  return family === 4 ? [ip, 'ipv4'] : family === 6 ? [ip, 'ipv6'] : null
+}
+
+function buildList(entries: string[], source: string): BlockList {
+  const list = new BlockList()
+  for (const entry of entries) {
+    const [addr, bits] = entry.split('/')
+    const parsed = normalize(addr.trim())
+    const prefix = bits === undefined ? (parsed?.[1] === 'ipv6' ? 128 : 32) : Number(bits)
+    if (!parsed || !Number.isInteger(prefix) || prefix < 0 || prefix > (parsed[1] === 'ipv6' ? 128 : 32)) {
+      log.warn('Ignoring invalid CIDR', { source, entry })
+      continue
+    }
+    list.addSubnet(parsed[0], prefix, parsed[1])
+  }
+  return list
+}
+
+const splitList = (value: string | undefined) =>
+  (value ?? '').split(',').map((s) => s.trim()).filter(Boolean)
+
+let compiled: { source: string; allow: BlockList; deny: BlockList } | null = null
+
+function getPolicy() {
+  const source = `${process.env.OUTBOUND_DENY_CIDRS ?? ''}|${process.env.OUTBOUND_ALLOW_CIDRS ?? ''}`
+  if (compiled?.source === source) return compiled
+  compiled = {
+    source,
+    allow: buildList(splitList(process.env.OUTBOUND_ALLOW_CIDRS), 'OUTBOUND_ALLOW_CIDRS'),
+    deny: buildList([...DEFAULT_DENY, ...splitList(process.env.OUTBOUND_DENY_CIDRS)], 'OUTBOUND_DENY_CIDRS'),
+  }
+  return compiled
+}
+
+export function isAddressAllowed(address: string): boolean {
+  const parsed = normalize(address)
+  if (!parsed) return false
+  const { allow, deny } = getPolicy()
+  if (allow.check(parsed[0], parsed[1])) return true
+  return !deny.check(parsed[0], parsed[1])
+}
+
+/** URL.hostname keeps the brackets around IPv6 literals */
+const bareHost = (hostname: string) => hostname.replace(/^\[(.*)\]$/, '$1')
+
+/**
+ * Denial for an IP-literal host, null for allowed IPs and DNS names.
+ * Sockets skip the lookup hook for literals, so callers check them here.
+ */
+export function checkLiteralHost(hostname: string): DestinationDeniedError | null {
+  const host = bareHost(hostname)
+  if (!isIP(host) || isAddressAllowed(host)) return null
+  return new DestinationDeniedError(host, host)
+}
+
+/**
+ * Resolve the URL's host and throw DestinationDeniedError if any address is
+ * denied. Resolution failures are left for the connection itself to report.
+ * fetch() resolves again when it connects, so pair this with redirect
+ * handling that does not follow to unchecked hosts.
+ */
+export async function assertDestinationAllowed(url: string): Promise<void> {
+  const host = bareHost(new URL(url).hostname)
+  if (isIP(host)) {
+    if (!isAddressAllowed(host)) throw new DestinationDeniedError(host, host)
+    return
+  }
+  const addresses = await dns.promises.lookup(host, { all: true }).catch(() => [])
+  const denied = addresses.find((a) => !isAddressAllowed(a.address))
+  if (denied) throw new DestinationDeniedError(host, denied.address)
+}
+
+/**
+ * dns.lookup replacement for http / ws connection options: the policy is
+ * applied to the addresses the socket actually connects to, which also
+ * covers DNS answers that change between validation and connect.
+ */
+export const guardedLookup: LookupFunction = (hostname, options, callback) => {
+  dns.lookup(hostname, { ...options, all: true }, (err, addresses) => {
+    if (err) return callback(err, '', 0)
+    const denied = addresses.find((a) => !isAddressAllowed(a.address))
+    if (denied) return callback(new DestinationDeniedError(hostname, denied.address), '', 0)
+    if (options.all) return callback(null, addresses)
+    callback(null, addresses[0].address, addresses[0].family)
+  })
+}
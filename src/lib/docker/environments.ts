@@ -0,0 +1,48 @@
+import type { DockerConfig, DockerConfigOverride, DeploymentEnvironment } from '@/types/instance'
+
+// Environment-scoped container parameters. An instance's DockerConfig is the
+// template; `overrides.<environment>` is layered over it for the environment
+// this TeamClaw deployment runs in (TEAMCLAW_ENV: development | staging |
+// production, default from NODE_ENV). env, volumes and portBindings are merged
+// key by key, scalars replace the template value. The result is applied when
+// the container is created, and a container created under different
+// parameters is recreated on the next start (see lib/instances/container-config.ts).
+//
+// No server imports: the validation schemas use this too.
+
+export const DEPLOYMENT_ENVIRONMENTS = ['development', 'staging', 'production'] as const
+
+export function currentDeploymentEnvironment(): DeploymentEnvironment {
+  const value = process.env.TEAMCLAW_ENV
+  if ((DEPLOYMENT_ENVIRONMENTS as readonly string[]).includes(value ?? '')) return value as DeploymentEnvironment
+  return process.env.NODE_ENV === 'production' ? 'production' : 'development'
+}
+
+/** Start parameters of a container: the template with the environment's overrides */
+export type ResolvedDockerConfig = Omit<DockerConfig, 'overrides'>
+
+export function resolveDockerConfig(
+  config: unknown,
+  environment: DeploymentEnvironment = currentDeploymentEnvironment(),
+): ResolvedDockerConfig {
+  const { overrides, ...base } = (config ?? {}) as DockerConfig
+  const override: DockerConfigOverride = overrides?.[environment] ?? {}
+
+  return {
+    ...base,
+    ...(override.restartPolicy ? { restartPolicy: override.restartPolicy } : {}),
+    ...(override.memoryLimit ? { memoryLimit: override.memoryLimit } : {}),
+    ...(override.sandboxProfile ? { sandboxProfile: override.sandboxProfile } : {}),
+    env: mergeRecords(base.env, override.env),
+    volumes: mergeRecords(base.volumes, override.volumes),
+    portBindings: mergeRecords(base.portBindings, override.portBindings),
+  }
+}
+
+function mergeRecords(
+  base: Record<string, string> | undefined,
+  override: Record<string, string> | undefined,
+): Record<string, string> | undefined {
+  if (!base && !override) return undefined
+  return { ...base, ...override }
+}
@@ -1,7 +1,16 @@
 import Docker from 'dockerode'
 import tar from 'tar-stream'
 import { createGzip } from 'zlib'
+import { hostname } from 'os'
 import type { ContainerCreateOptions, ContainerInfo } from './types'
+import {
+  DOCKER_SOCKET_BIND,
+  SANDBOX_PROFILE_LABEL,
+  getSandboxProfile,
+  isolatedNetworkName,
+  sandboxHostConfig,
+  type SandboxProfile,
+} from './sandbox-profiles'
 
 const NETWORK_NAME = process.env.DOCKER_NETWORK || 'gateway-net'
 
@@ -66,22 +75,51 @@ export class DockerManager {
   }
 
   // Network management
-  async ensureNetwork(name: string = NETWORK_NAME): Promise<void> {
+  async ensureNetwork(name: string = NETWORK_NAME, opts?: { internal?: boolean }): Promise<void> {
     try {
       const networks = await this.docker.listNetworks({
         filters: JSON.stringify({ name: [name] }),
       })
       if (networks.length === 0) {
-        await this.docker.createNetwork({ Name: name, Driver: 'bridge' })
+        await this.docker.createNetwork({ Name: name, Driver: 'bridge', Internal: opts?.internal })
       }
     } catch (err) {
       throw new Error(`Failed to ensure network "${name}": ${(err as Error).message}`)
     }
   }
 
+  /**
+   * Join TeamClaw's own container to a network so it can reach instances
+   * that live only there. Docker sets the container hostname to its ID.
+   */
+  async connectSelfToNetwork(name: string): Promise<void> {
+    try {
+      await this.docker.getNetwork(name).connect({ Container: hostname() })
+    } catch (err) {
+      const msg = (err as Error).message
+      if (!msg.includes('already exists')) {
+        throw new Error(`Failed to join network "${name}": ${msg}`)
+      }
+    }
+  }
+
+  /** Network a container runs on under the given profile */
+  private async prepareNetwork(profile: SandboxProfile, requested?: string): Promise<string> {
+    if (profile.network === 'internal') {
+      const name = isolatedNetworkName()
+      await this.ensureNetwork(name, { internal: true })
+      await this.connectSelfToNetwork(name)
+      return name
+    }
+    const name = requested || NETWORK_NAME
+    await this.ensureNetwork(name)
+    return name
+  }
+
   // Container lifecycle
   async createContainer(options: ContainerCreateOptions): Promise<string> {
-    await this.ensureNetwork(options.networkName || NETWORK_NAME)
+    const sandbox = options.sandbox ?? getSandboxProfile(undefined)
+    const networkName = await this.prepareNetwork(sandbox, options.networkName)
 
     const portBindings: Record<string, { HostPort: string }[]> = {}
     const exposedPorts: Record<string, Record<string, never>> = {}
@@ -102,6 +140,8 @@ export class DockerManager {
     if (options.extraBinds) {
       binds.push(...options.extraBinds)
     }
+    // The socket is root on the host; only the standard profile gets it
+    const allowedBinds = sandbox.dockerSocket ? binds : binds.filter((b) => b !== DOCKER_SOCKET_BIND)
 
     const env = options.env
       ? Object.entries(options.env).map(([k, v]) => `${k}=${v}`)
@@ -116,18 +156,32 @@ export class DockerManager {
       Image: options.imageName,
       Env: env,
       ExposedPorts: exposedPorts,
+      Labels: { ...options.labels, [SANDBOX_PROFILE_LABEL]: sandbox.id },
       HostConfig: {
         PortBindings: portBindings,
-        Binds: binds.length > 0 ? binds : undefined,
+        Binds: allowedBinds.length > 0 ? allowedBinds : undefined,
         RestartPolicy: restartPolicy,
         Memory: options.memoryLimit || 0,
-        NetworkMode: options.networkName || NETWORK_NAME,
+        NetworkMode: networkName,
+        ...sandboxHostConfig(sandbox),
       },
     })
 
     return container.id
   }
 
+  /**
+   * Replace a container with a new one created from `options` (same name).
+   * Data lives in host volumes, so nothing inside the old container is kept.
+   * Returns the new container ID; the new container is not started.
+   */
+  async recreateContainer(containerId: string, options: ContainerCreateOptions): Promise<string> {
+    const container = this.docker.getContainer(containerId)
+    await container.stop({ t: 10 }).catch(() => {})
+    await container.remove({ force: true })
+    return this.createContainer(options)
+  }
+
   async startContainer(containerId: string): Promise<void> {
     const container = this.docker.getContainer(containerId)
     await container.start()
@@ -186,6 +240,8 @@ export class DockerManager {
       imageName: info.Config.Image,
       version,
       ports,
+      labels: info.Config.Labels ?? {},
+      binds: info.HostConfig.Binds ?? [],
       createdAt: info.Created,
     }
   }
@@ -0,0 +1,114 @@
+import fs from 'fs'
+
+// Sandbox profiles for managed instance containers. A profile is chosen per
+// instance (dockerConfig.sandboxProfile) and turned into HostConfig security
+// settings when the container is created. Docker cannot change these on an
+// existing container, so starting or restarting an instance whose container
+// was created under another profile recreates it first (see
+// lib/instances/container-config.ts); volumes live on the host and survive that.
+//
+//   standard — Docker defaults, Docker socket mounted for OpenClaw's own
+//              sandbox mode (the behaviour before profiles existed)
+//   hardened — no Docker socket, all capabilities dropped except the few
+//              root execs need for file ownership, no-new-privileges
+//   isolated — hardened + read-only root filesystem (data and workspace
+//              volumes stay writable) + an internal network without a route
+//              out; only containers on the same network (TeamClaw itself)
+//              can reach it. Model APIs must then be reachable on that
+//              network too, e.g. through a proxy container.
+//
+// DOCKER_SECCOMP_PROFILE  — path to a seccomp JSON profile for hardened /
+//                           isolated ("unconfined" disables seccomp; empty
+//                           keeps Docker's default profile)
+// DOCKER_APPARMOR_PROFILE — AppArmor profile name loaded on the host
+// DOCKER_ISOLATED_NETWORK — internal network for isolated (default
+//                           "<DOCKER_NETWORK>-isolated")
+
+export const SANDBOX_PROFILE_IDS = ['standard', 'hardened', 'isolated'] as const
+export type SandboxProfileId = (typeof SANDBOX_PROFILE_IDS)[number]
+
+export const DEFAULT_SANDBOX_PROFILE: SandboxProfileId = 'standard'
+
+/** Docker label recording the profile a container was created with */
+export const SANDBOX_PROFILE_LABEL = 'teamclaw.sandbox-profile'
+
+export interface SandboxProfile {
+  id: SandboxProfileId
+  readOnlyRootfs: boolean
+  network: 'default' | 'internal'
+  dockerSocket: boolean
+  noNewPrivileges: boolean
+  capDrop: string[]
+  capAdd: string[]
+  seccompProfile?: string
+  apparmorProfile?: string
+}
+
+// Root execs (ensureContainerDir, skill installs) chown files to the node user
+const FILE_OWNER_CAPS = ['CHOWN', 'DAC_OVERRIDE', 'FOWNER', 'SETGID', 'SETUID']
+
+export const DOCKER_SOCKET_BIND = '/var/run/docker.sock:/var/run/docker.sock'
+
+export function isSandboxProfileId(value: unknown): value is SandboxProfileId {
+  return typeof value === 'string' && (SANDBOX_PROFILE_IDS as readonly string[]).includes(value)
+}
+
+export function getSandboxProfile(id: unknown): SandboxProfile {
+  const seccompProfile = process.env.DOCKER_SECCOMP_PROFILE || undefined
+  const apparmorProfile = process.env.DOCKER_APPARMOR_PROFILE || undefined
+  const hardened = {
+    dockerSocket: false,
+    noNewPrivileges: true,
+    capDrop: ['ALL'],
+    capAdd: FILE_OWNER_CAPS,
+    seccompProfile,
+    apparmorProfile,
+  }
+
+  switch (isSandboxProfileId(id) ? id : DEFAULT_SANDBOX_PROFILE) {
+    case 'hardened':
+      return { id: 'hardened', readOnlyRootfs: false, network: 'default', ...hardened }
+    case 'isolated':
+      return { id: 'isolated', readOnlyRootfs: true, network: 'internal', ...hardened }
+    default:
+      return {
+        id: 'standard',
+        readOnlyRootfs: false,
+        network: 'default',
+        dockerSocket: true,
+        noNewPrivileges: false,
+        capDrop: [],
+        capAdd: [],
+      }
+  }
+}
+
+export function isolatedNetworkName(): string {
+  return process.env.DOCKER_ISOLATED_NETWORK || `${process.env.DOCKER_NETWORK || 'gateway-net'}-isolated`
+}
+
+/** The profile's part of a container HostConfig */
+export function sandboxHostConfig(profile: SandboxProfile) {
+  const securityOpt: string[] = []
+  if (profile.noNewPrivileges) securityOpt.push('no-new-privileges:true')
+  if (profile.seccompProfile) {
+    // The API takes the profile itself, not a path (the docker CLI reads the file)
+    securityOpt.push(
+      profile.seccompProfile === 'unconfined'
+        ? 'seccomp=unconfined'
+        : `seccomp=${fs.readFileSync(profile.seccompProfile, 'utf8')}`,
+    )
+  }
+  if (profile.apparmorProfile) securityOpt.push(`apparmor=${profile.apparmorProfile}`)
+
+  return {
+    ReadonlyRootfs: profile.readOnlyRootfs || undefined,
+    // A read-only root still needs scratch space
+    Tmpfs: profile.readOnlyRootfs
+      ? { '/tmp': 'rw,nosuid,size=256m', '/home/node/.cache': 'rw,nosuid,size=256m,uid=1000,gid=1000' }
+      : undefined,
+    CapDrop: profile.capDrop.length > 0 ? profile.capDrop : undefined,
+    CapAdd: profile.capAdd.length > 0 ? profile.capAdd : undefined,
+    SecurityOpt: securityOpt.length > 0 ? securityOpt : undefined,
+  }
+}
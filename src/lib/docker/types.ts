@@ -1,3 +1,5 @@
+import type { SandboxProfile } from './sandbox-profiles'
+
 export interface ContainerCreateOptions {
   name: string
   imageName: string
@@ -8,6 +10,8 @@ export interface ContainerCreateOptions {
   restartPolicy?: 'no' | 'always' | 'unless-stopped' | 'on-failure'
   memoryLimit?: number // bytes
   networkName?: string // default: 'gateway-net'
+  sandbox?: SandboxProfile // default: standard (Docker defaults)
+  labels?: Record<string, string>
 }
 
 export interface ContainerInfo {
@@ -18,6 +22,8 @@ export interface ContainerInfo {
   imageName: string
   version?: string // extracted from env/labels
   ports: Record<string, string>
+  labels: Record<string, string>
+  binds: string[] // "host:container[:mode]"
   createdAt: string
 }
 
@@ -0,0 +1,156 @@
+import { prisma } from '@/lib/db'
+import { auditLogCritical } from '@/lib/audit'
+import { createLogger } from '@/lib/logger'
+import { globToRegExp } from '@/lib/utils/glob'
+import type { EgressPolicy } from '@/generated/prisma'
+import type { EgressPolicyResponse } from '@/types/department'
+
+// Per-department egress policy for agent tool calls. Instances are shared
+// between departments, so the policy cannot be pushed into one gateway
+// config; it is enforced on the chat stream instead: when a run started by a
+// member announces a tool call that the policy forbids, the tool_result is
+// never relayed, the run is aborted and a critical security event is audited.
+//
+// Tool patterns are case-insensitive '*' globs. A domain entry covers the
+// host and its subdomains ("example.com"), or subdomains only
+// ("*.example.com"). Domains are taken from URLs and host-like fields in the
+// tool arguments. Deny lists win over allow lists; a null list is no limit.
+
+const log = createLogger('egress-policy')
+
+export interface CompiledEgressPolicy {
+  departmentId: string
+  allowedTools: RegExp[] | null
+  deniedTools: RegExp[]
+  allowedDomains: string[] | null
+  deniedDomains: string[]
+}
+
+export interface EgressViolation {
+  toolName: string
+  domain?: string
+  reason: 'tool_not_allowed' | 'tool_denied' | 'domain_not_allowed' | 'domain_denied'
+}
+
+const asList = (value: unknown): string[] | null =>
+  Array.isArray(value) ? value.filter((v): v is string => typeof v === 'string') : null
+
+const toolRegExp = (pattern: string) => new RegExp(globToRegExp(pattern).source, 'i')
+
+export function toEgressPolicyResponse(departmentId: string, policy: EgressPolicy | null): EgressPolicyResponse {
+  return {
+    departmentId,
+    enabled: policy?.enabled ?? false,
+    allowedTools: asList(policy?.allowedTools),
+    deniedTools: asList(policy?.deniedTools),
+    allowedDomains: asList(policy?.allowedDomains),
+    deniedDomains: asList(policy?.deniedDomains),
+    updatedAt: policy?.updatedAt.toISOString() ?? null,
+  }
+}
+
+/** The department's enabled policy, ready for per-event checks; null if none */
+export async function loadEgressPolicy(departmentId: string): Promise<CompiledEgressPolicy | null> {
+  const policy = await prisma.egressPolicy.findUnique({ where: { departmentId } })
+  if (!policy?.enabled) return null
+  return {
+    departmentId,
+    allowedTools: asList(policy.allowedTools)?.map(toolRegExp) ?? null,
+    deniedTools: (asList(policy.deniedTools) ?? []).map(toolRegExp),
+    allowedDomains: asList(policy.allowedDomains)?.map((d) => d.toLowerCase()) ?? null,
+    deniedDomains: (asList(policy.deniedDomains) ?? []).map((d) => d.toLowerCase()),
+  }
+}
+
+function domainMatches(host: string, entry: string): boolean {
+  if (entry.startsWith('*.')) return host.endsWith(entry.slice(1))
+  return host === entry || host.endsWith(`.${entry}`)
+}
+
+const URL_HOST = /\b[a-z][a-z0-9+.-]*:\/\/(?:[^@\s/?#]*@)?(\[[^\]]+\]|[^\s/:?#"'<>)]+)/gi
+const HOST_KEYS = new Set(['host', 'hostname', 'domain'])
+
+/** Hostnames referenced by a tool's arguments */
+export function extractDomains(args: unknown): string[] {
+  const hosts = new Set<string>()
+  const visit = (value: unknown, key: string | null, depth: number) => {
+    if (depth > 8) return
+    if (typeof value === 'string') {
+      for (const m of value.matchAll(URL_HOST)) hosts.add(m[1].toLowerCase().replace(/\.$/, ''))
+      if (key && HOST_KEYS.has(key.toLowerCase()) && /^[a-z0-9.-]+$/i.test(value)) {
+        hosts.add(value.toLowerCase().replace(/\.$/, ''))
+      }
+    } else if (Array.isArray(value)) {
+      for (const v of value) visit(v, key, depth + 1)
+    } else if (value && typeof value === 'object') {
+      for (const [k, v] of Object.entries(value)) visit(v, k, depth + 1)
+    }
+  }
+  visit(args, null, 0)
+  return [...hosts]
+}
+
+/** First rule a tool call breaks, or null if it may proceed */
+export function checkToolCall(
+  policy: CompiledEgressPolicy,
+  toolName: string,
+  args: unknown,
+): EgressViolation | null {
+  if (policy.deniedTools.some((re) => re.test(toolName))) return { toolName, reason: 'tool_denied' }
+  if (policy.allowedTools && !policy.allowedTools.some((re) => re.test(toolName))) {
+    return { toolName, reason: 'tool_not_allowed' }
+  }
+
+  for (const domain of extractDomains(args)) {
+    if (policy.deniedDomains.some((d) => domainMatches(domain, d))) {
+      return { toolName, domain, reason: 'domain_denied' }
+    }
+    if (policy.allowedDomains && !policy.allowedDomains.some((d) => domainMatches(domain, d))) {
+      return { toolName, domain, reason: 'domain_not_allowed' }
+    }
+  }
+  return null
+}
+
+export function describeViolation(v: EgressViolation): string {
+  return v.domain
+    ? `Tool "${v.toolName}" tried to reach ${v.domain}, which the department egress policy does not allow`
+    : `Tool "${v.toolName}" is not allowed by the department egress policy`
+}
+
+/** Record a blocked tool call as a critical security event; never throws */
+export async function recordEgressViolation(
+  violation: EgressViolation,
+  run: {
+    userId: string
+    departmentId: string
+    instanceId: string
+    agentId: string
+    chatSessionId: string
+    ipAddress: string
+    userAgent?: string
+  },
+): Promise<void> {
+  log.warn('Egress policy violation', { ...violation, userId: run.userId, instanceId: run.instanceId, agentId: run.agentId })
+  try {
+    await auditLogCritical({
+      userId: run.userId,
+      action: 'EGRESS_POLICY_VIOLATION',
+      resource: 'chat_session',
+      resourceId: run.chatSessionId,
+      details: {
+        departmentId: run.departmentId,
+        instanceId: run.instanceId,
+        agentId: run.agentId,
+        toolName: violation.toolName,
+        domain: violation.domain ?? null,
+        reason: violation.reason,
+      },
+      ipAddress: run.ipAddress,
+      userAgent: run.userAgent,
+      result: 'DENIED',
+    })
+  } catch (err) {
+    log.error('Failed to record egress violation', { err })
+  }
+}
@@ -0,0 +1,173 @@
+import { prisma } from '@/lib/db'
+import { storeFile, signFileUrl } from '@/lib/files'
+import { createLogger } from '@/lib/logger'
+import { encodeTable, EXPORT_MIME_TYPES, type ExportFormat, type Table } from './formats'
+import type { ExportJob } from '@/generated/prisma'
+
+// Export jobs: a table (users, departments) written to CSV or XLSX in the
+// background, so a large organisation's export does not hold a request open.
+// POST .../export queues a job and answers 202; GET /api/v1/exports/[id]
+// reports its status and, once it completed, a signed download URL for the
+// file it produced (a StoredFile owned by the requester).
+//
+// Department admins export their own department only; the scope is fixed
+// when the job is queued.
+
+const log = createLogger('exports')
+
+export type ExportKind = 'users' | 'departments'
+
+const globalForExports = globalThis as unknown as {
+  exportJobsInFlight?: Set<string>
+}
+
+const inFlight = (globalForExports.exportJobsInFlight ??= new Set<string>())
+
+const iso = (d: Date | null) => d?.toISOString() ?? null
+
+// ─── Tables ─────────────────────────────────────────────────────────
+
+async function usersTable(departmentId: string | null): Promise<Table> {
+  const users = await prisma.user.findMany({
+    where: departmentId ? { departmentId } : {},
+    select: {
+      name: true,
+      email: true,
+      role: true,
+      status: true,
+      isServiceAccount: true,
+      mfaEnabled: true,
+      lastLoginAt: true,
+      createdAt: true,
+      department: { select: { name: true } },
+    },
+    orderBy: { createdAt: 'asc' },
+  })
+  return {
+    columns: ['Name', 'Email', 'Role', 'Department', 'Status', 'Service Account', 'MFA', 'Last Login', 'Created'],
+    rows: users.map((u) => [
+      u.name,
+      u.email,
+      u.role,
+      u.department?.name ?? null,
+      u.status,
+      u.isServiceAccount ? 'yes' : 'no',
+      u.mfaEnabled ? 'yes' : 'no',
+      iso(u.lastLoginAt),
+      iso(u.createdAt),
+    ]),
+  }
+}
+
+async function departmentsTable(departmentId: string | null): Promise<Table> {
+  const departments = await prisma.department.findMany({
+    where: departmentId ? { id: departmentId } : {},
+    include: { _count: { select: { users: true, instanceAccess: true } } },
+    orderBy: { name: 'asc' },
+  })
+  const active = await prisma.user.groupBy({
+    by: ['departmentId'],
+    where: { status: 'ACTIVE', departmentId: { in: departments.map((d) => d.id) } },
+    _count: { _all: true },
+  })
+  const activeCount = new Map(active.map((a) => [a.departmentId, a._count._all]))
+
+  return {
+    columns: ['Name', 'Description', 'Members', 'Active Members', 'Instance Grants', 'Chat Priority', 'Created'],
+    rows: departments.map((d) => [
+      d.name,
+      d.description,
+      d._count.users,
+      activeCount.get(d.id) ?? 0,
+      d._count.instanceAccess,
+      d.chatPriority,
+      iso(d.createdAt),
+    ]),
+  }
+}
+
+const TABLES: Record<ExportKind, (departmentId: string | null) => Promise<Table>> = {
+  users: usersTable,
+  departments: departmentsTable,
+}
+
+// ─── Jobs ───────────────────────────────────────────────────────────
+
+async function executeExportJob(jobId: string): Promise<void> {
+  const job = await prisma.exportJob.update({
+    where: { id: jobId },
+    data: { status: 'RUNNING', startedAt: new Date() },
+  })
+
+  const table = await TABLES[job.kind as ExportKind](job.departmentId)
+  const format = job.format as ExportFormat
+  const name = `${job.kind}-${new Date().toISOString().slice(0, 10)}.${format}`
+  const file = await storeFile(job.createdById, name, EXPORT_MIME_TYPES[format], encodeTable(format, job.kind, table))
+
+  await prisma.exportJob.update({
+    where: { id: jobId },
+    data: { status: 'COMPLETED', rowCount: table.rows.length, fileId: file.id, finishedAt: new Date() },
+  })
+  log.info('Export finished', { jobId, kind: job.kind, format, rows: table.rows.length })
+}
+
+/** Build a queued export in the background */
+export function startExportJob(jobId: string): void {
+  if (inFlight.has(jobId)) return
+  inFlight.add(jobId)
+  executeExportJob(jobId)
+    .catch(async (err) => {
+      log.error('Export failed', { jobId, error: (err as Error).message })
+      await prisma.exportJob
+        .update({ where: { id: jobId }, data: { status: 'FAILED', error: (err as Error).message, finishedAt: new Date() } })
+        .catch(() => {})
+    })
+    .finally(() => inFlight.delete(jobId))
+}
+
+/** Queue an export for the user and start it */
+export async function queueExport(
+  kind: ExportKind,
+  format: ExportFormat,
+  user: { id: string; role: string; departmentId: string | null },
+): Promise<ExportJob> {
+  const job = await prisma.exportJob.create({
+    data: {
+      kind,
+      format,
+      departmentId: user.role === 'DEPT_ADMIN' ? user.departmentId : null,
+      createdById: user.id,
+    },
+  })
+  startExportJob(job.id)
+  return job
+}
+
+/** Jobs the previous process was building cannot be picked up again. Called once at startup. */
+export async function failInterruptedExportJobs(): Promise<void> {
+  const { count } = await prisma.exportJob.updateMany({
+    where: { status: { in: ['QUEUED', 'RUNNING'] }, id: { notIn: [...inFlight] } },
+    data: { status: 'FAILED', error: 'Interrupted by a server restart', finishedAt: new Date() },
+  })
+  if (count > 0) log.warn('Marked interrupted exports as failed', { count })
+}
+
+export function parseExportFormat(value: string | null): ExportFormat | null {
+  if (!value) return 'csv'
+  return value === 'csv' || value === 'xlsx' ? value : null
+}
+
+export function toExportJobResponse(j: ExportJob) {
+  return {
+    id: j.id,
+    kind: j.kind as ExportKind,
+    format: j.format as ExportFormat,
+    status: j.status,
+    rowCount: j.rowCount,
+    downloadUrl: j.status === 'COMPLETED' && j.fileId ? signFileUrl(j.fileId) : null,
+    error: j.error,
+    startedAt: j.startedAt?.toISOString() ?? null,
+    finishedAt: j.finishedAt?.toISOString() ?? null,
+    createdAt: j.createdAt.toISOString(),
+  }
+}
@@ -0,0 +1,24 @@
+import { prisma } from '@/lib/db'
+import { FileRejectedError, signFileUrl } from '@/lib/files'
+import type { StoredFile } from '@/generated/prisma'
+
+// Department and agent icons are uploaded like any file (POST /api/v1/files)
+// and then set by id. Only small raster images qualify: SVG could carry
+// script, and icons are shown in every agent list.
+
+const ICON_TYPES = new Set(['image/png', 'image/jpeg', 'image/webp', 'image/gif'])
+const ICON_MAX_BYTES = 1024 * 1024
+
+/** The user's upload `fileId`, checked for use as an icon */
+export async function resolveIconFile(userId: string, fileId: string): Promise<StoredFile> {
+  const file = await prisma.storedFile.findUnique({ where: { id: fileId } })
+  if (!file || file.userId !== userId) throw new FileRejectedError('Icon file not found')
+  if (!ICON_TYPES.has(file.mimeType)) throw new FileRejectedError('Icons must be PNG, JPEG, WebP or GIF images')
+  if (file.size > ICON_MAX_BYTES) throw new FileRejectedError('Icons must be 1MB or smaller')
+  return file
+}
+
+/** Signed URL of an icon, or null when none is set */
+export function iconUrl(fileId: string | null | undefined): string | null {
+  return fileId ? signFileUrl(fileId) : null
+}
@@ -0,0 +1,128 @@
+import { createHash, createHmac, randomUUID, timingSafeEqual } from 'crypto'
+import { prisma } from '@/lib/db'
+import { globToRegExp } from '@/lib/utils/glob'
+import { configuredBackend, getFileStorage, type StorageBackend } from '@/lib/files/storage'
+import type { StoredFile } from '@/generated/prisma'
+
+// Uploaded files (chat attachments): POST /api/v1/files stores the file and
+// returns its id, and messages reference it instead of carrying base64.
+// Downloads go through short-lived signed URLs, so they work in <img> tags
+// and links without a session.
+//
+// FILE_MAX_MB         — largest accepted upload (default 25)
+// FILE_ALLOWED_TYPES  — accepted MIME types, '*' globs
+//                       (default image/*,text/*,application/pdf,application/json)
+// FILE_URL_TTL_SEC    — lifetime of signed download URLs (default 900)
+
+const DEFAULT_ALLOWED_TYPES = 'image/*,text/*,application/pdf,application/json'
+
+function intEnv(name: string, fallback: number): number {
+  const n = parseInt(process.env[name] ?? '', 10)
+  return Number.isFinite(n) && n > 0 ? n : fallback
+}
+
+export const maxFileBytes = () => intEnv('FILE_MAX_MB', 25) * 1024 * 1024
+
+export function isAllowedFileType(mimeType: string): boolean {
+  const patterns = (process.env.FILE_ALLOWED_TYPES || DEFAULT_ALLOWED_TYPES).split(',')
+  return patterns.some((p) => p.trim() && globToRegExp(p.trim().toLowerCase()).test(mimeType.toLowerCase()))
+}
+
+export class FileRejectedError extends Error {}
+
+/** Validate and store an upload for the user */
+export async function saveFile(userId: string, file: File): Promise<StoredFile> {
+  const name = file.name
+  if (!name || name.length > 255 || name.includes('/') || name.includes('\\') || name.includes('\0')) {
+    throw new FileRejectedError('Invalid filename')
+  }
+  if (file.size === 0) {
+    throw new FileRejectedError('File is empty')
+  }
+  if (file.size > maxFileBytes()) {
+    throw new FileRejectedError(`File size exceeds ${maxFileBytes() / 1024 / 1024}MB limit`)
+  }
+  const mimeType = file.type || 'application/octet-stream'
+  if (!isAllowedFileType(mimeType)) {
+    throw new FileRejectedError(`File type ${mimeType} is not allowed`)
+  }
+
+  return storeFile(userId, name, mimeType, Buffer.from(await file.arrayBuffer()))
+}
+
+/** Store a file TeamClaw produced (an export, say) for the user; no upload checks apply */
+export async function storeFile(userId: string, name: string, mimeType: string, data: Buffer): Promise<StoredFile> {
+  const backend = configuredBackend()
+  const id = randomUUID()
+  const storageKey = `${userId}/${id}`
+  await getFileStorage(backend).put(storageKey, data, mimeType)
+
+  return prisma.storedFile.create({
+    data: {
+      userId,
+      name,
+      mimeType,
+      size: data.length,
+      sha256: createHash('sha256').update(data).digest('hex'),
+      storageKey,
+      backend,
+    },
+  })
+}
+
+export async function readStoredFile(file: StoredFile): Promise<Buffer> {
+  return getFileStorage(file.backend as StorageBackend).get(file.storageKey)
+}
+
+/** Remove the file's blob and its row */
+export async function deleteStoredFile(file: StoredFile): Promise<void> {
+  await getFileStorage(file.backend as StorageBackend).delete(file.storageKey)
+  await prisma.storedFile.deleteMany({ where: { id: file.id } })
+}
+
+/**
+ * Remove all of a user's files; returns how many were deleted. Department
+ * and agent icons the user uploaded belong to the organisation and stay.
+ */
+export async function deleteUserFiles(userId: string): Promise<number> {
+  const files = await prisma.storedFile.findMany({
+    where: { userId, departmentIcons: { none: {} }, agentIcons: { none: {} } },
+  })
+  for (const file of files) {
+    await deleteStoredFile(file)
+  }
+  return files.length
+}
+
+// ─── Signed download URLs ───────────────────────────────────────────
+
+function signature(fileId: string, expires: number): string {
+  return createHmac('sha256', process.env.ENCRYPTION_KEY || '')
+    .update(`file:${fileId}:${expires}`)
+    .digest('base64url')
+}
+
+export function signFileUrl(fileId: string): string {
+  const expires = Math.floor(Date.now() / 1000) + intEnv('FILE_URL_TTL_SEC', 900)
+  return `/api/v1/files/${fileId}/download?expires=${expires}&sig=${signature(fileId, expires)}`
+}
+
+export function verifyFileSignature(fileId: string, expires: string | null, sig: string | null): boolean {
+  const exp = Number(expires)
+  if (!sig || !Number.isInteger(exp) || exp < Date.now() / 1000) return false
+  const expected = Buffer.from(signature(fileId, exp))
+  const given = Buffer.from(sig)
+  return given.length === expected.length && timingSafeEqual(given, expected)
+}
+
+export function toFileResponse(f: StoredFile) {
+  return {
+    id: f.id,
+    name: f.name,
+    mimeType: f.mimeType,
+    size: f.size,
+    sha256: f.sha256,
+    url: signFileUrl(f.id),
+    createdAt: f.createdAt.toISOString(),
+  }
+}
@@ -0,0 +1,135 @@
+import { createHash, createHmac } from 'crypto'
+import { mkdir, readFile, rm, writeFile } from 'fs/promises'
+import { dirname, join } from 'path'
+
+// Blob backends for uploaded files. Each StoredFile row records the backend
+// it was written to, so files stay readable after FILE_STORAGE changes as
+// long as the old backend is still configured.
+//
+// FILE_STORAGE          — local (default) | s3
+// TEAMCLAW_FILES_DIR    — local directory (default data/files)
+// S3_ENDPOINT           — S3-compatible endpoint (MinIO, R2, ...); empty = AWS
+// S3_REGION             — default us-east-1
+// S3_BUCKET, S3_ACCESS_KEY_ID, S3_SECRET_ACCESS_KEY
+// S3_FORCE_PATH_STYLE   — bucket in the path instead of the host name
+//                         (default true with a custom endpoint)
+
+export type StorageBackend = 'local' | 's3'
+
+export interface FileStorage {
+  put(key: string, data: Buffer, mimeType: string): Promise<void>
+  get(key: string): Promise<Buffer>
+  delete(key: string): Promise<void>
+}
+
+export function configuredBackend(): StorageBackend {
+  return process.env.FILE_STORAGE === 's3' ? 's3' : 'local'
+}
+
+// ─── Local disk ─────────────────────────────────────────────────────
+
+const filesDir = () => process.env.TEAMCLAW_FILES_DIR || join(process.cwd(), 'data', 'files')
+
+const diskStorage: FileStorage = {
+  async put(key, data) {
+    const path = join(filesDir(), key)
+    await mkdir(dirname(path), { recursive: true })
+    await writeFile(path, data)
+  },
+  async get(key) {
+    return readFile(join(filesDir(), key))
+  },
+  async delete(key) {
+    await rm(join(filesDir(), key), { force: true })
+  },
+}
+
+// ─── S3 (Signature V4) ──────────────────────────────────────────────
+
+const sha256Hex = (data: string | Buffer) => createHash('sha256').update(data).digest('hex')
+const hmac = (key: string | Buffer, data: string) => createHmac('sha256', key).update(data).digest()
+
+function s3Config() {
+  const bucket = process.env.S3_BUCKET
+  const accessKeyId = process.env.S3_ACCESS_KEY_ID
+  const secretAccessKey = process.env.S3_SECRET_ACCESS_KEY
+  if (!bucket || !accessKeyId || !secretAccessKey) {
+    throw new Error('S3 storage needs S3_BUCKET, S3_ACCESS_KEY_ID and S3_SECRET_ACCESS_KEY')
+  }
+  const region = process.env.S3_REGION || 'us-east-1'
+  const endpoint = process.env.S3_ENDPOINT?.replace(/\/+$/, '') || ''
+  const pathStyle = process.env.S3_FORCE_PATH_STYLE ? process.env.S3_FORCE_PATH_STYLE === 'true' : !!endpoint
+  return { bucket, accessKeyId, secretAccessKey, region, endpoint, pathStyle }
+}
+
+function objectUrl(cfg: ReturnType<typeof s3Config>, key: string): URL {
+  const path = key.split('/').map(encodeURIComponent).join('/')
+  if (cfg.endpoint) {
+    return cfg.pathStyle
+      ? new URL(`${cfg.endpoint}/${cfg.bucket}/${path}`)
+      : new URL(`${cfg.endpoint.replace('://', `://${cfg.bucket}.`)}/${path}`)
+  }
+  return cfg.pathStyle
+    ? new URL(`https://s3.${cfg.region}.amazonaws.com/${cfg.bucket}/${path}`)
+    : new URL(`https://${cfg.bucket}.s3.${cfg.region}.amazonaws.com/${path}`)
+}
+
+async function s3Request(method: 'PUT' | 'GET' | 'DELETE', key: string, body?: Buffer, mimeType?: string) {
+  const cfg = s3Config()
+  const url = objectUrl(cfg, key)
+  const amzDate = new Date().toISOString().replace(/[-:]/g, '').replace(/\.\d{3}/, '')
+  const date = amzDate.slice(0, 8)
+  const payloadHash = sha256Hex(body ?? '')
+
+  const headers: Record<string, string> = {
+    host: url.host,
+    'x-amz-content-sha256': payloadHash,
+    'x-amz-date': amzDate,
+    ...(mimeType ? { 'content-type': mimeType } : {}),
+  }
+  const names = Object.keys(headers).sort()
+  const signedHeaders = names.join(';')
+  const canonicalRequest = [
+    method,
+    url.pathname,
+    '',
+    names.map((n) => `${n}:${headers[n]}\n`).join(''),
+    signedHeaders,
+    payloadHash,
+  ].join('\n')
+
+  const scope = `${date}/${cfg.region}/s3/aws4_request`
+  const stringToSign = ['AWS4-HMAC-SHA256', amzDate, scope, sha256Hex(canonicalRequest)].join('\n')
+  const signingKey = hmac(hmac(hmac(hmac(`AWS4${cfg.secretAccessKey}`, date), cfg.region), 's3'), 'aws4_request')
+  const signature = createHmac('sha256', signingKey).update(stringToSign).digest('hex')
+
+  const { host: _host, ...sent } = headers
+  const response = await fetch(url, {
+    method,
+    headers: {
+      ...sent,
+      Authorization: `AWS4-HMAC-SHA256 Credential=${cfg.accessKeyId}/${scope}, SignedHeaders=${signedHeaders}, Signature=${signature}`,
+    },
+    body: body ? new Uint8Array(body) : undefined,
+  })
+  if (!response.ok && !(method === 'DELETE' && response.status === 404)) {
+    throw new Error(`S3 ${method} failed: HTTP ${response.status}`)
+  }
+  return response
+}
+
+const s3Storage: FileStorage = {
+  async put(key, data, mimeType) {
+    await s3Request('PUT', key, data, mimeType)
+  },
+  async get(key) {
+    return Buffer.from(await (await s3Request('GET', key)).arrayBuffer())
+  },
+  async delete(key) {
+    await s3Request('DELETE', key)
+  },
+}
+
+export function getFileStorage(backend: StorageBackend): FileStorage {
+  return backend === 's3' ? s3Storage : diskStorage
+}
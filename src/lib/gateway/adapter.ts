@@ -9,7 +9,8 @@ import type {
   ChatHistoryResult,
   ConfigSchemaResult,
 } from '@/types/gateway'
-import { GatewayClient } from './client'
+import type { GatewayConnection } from './client'
+import { fetchChatHistory } from './history'
 
 /**
  * Abstract adapter interface for OpenClaw Gateway protocol versions.
@@ -20,17 +21,17 @@ export interface GatewayAdapter {
   readonly protocolVersion: string
 
   // Agent operations
-  getAgents(client: GatewayClient): Promise<AgentsListResult>
-  getAgent(client: GatewayClient, agentId: string): Promise<GatewayAgent>
+  getAgents(client: GatewayConnection): Promise<AgentsListResult>
+  getAgent(client: GatewayConnection, agentId: string): Promise<GatewayAgent>
 
   // Session operations
-  getSessions(client: GatewayClient, agentId?: string): Promise<GatewaySession[]>
-  getSession(client: GatewayClient, sessionId: string): Promise<GatewaySession>
-  deleteSession(client: GatewayClient, sessionId: string): Promise<void>
+  getSessions(client: GatewayConnection, agentId?: string): Promise<GatewaySession[]>
+  getSession(client: GatewayConnection, sessionId: string): Promise<GatewaySession>
+  deleteSession(client: GatewayConnection, sessionId: string): Promise<void>
 
   // Chat operations
   sendMessage(
-    client: GatewayClient,
+    client: GatewayConnection,
     sessionKey: string,
     message: string,
     idempotencyKey: string,
@@ -38,17 +39,17 @@ export interface GatewayAdapter {
   ): Promise<unknown>
 
   // Config operations — read/write openclaw.json via gateway protocol
-  getConfig(client: GatewayClient): Promise<ConfigGetResult>
-  getSchema(client: GatewayClient): Promise<ConfigSchemaResult>
-  patchConfig(client: GatewayClient, patch: Record<string, unknown>, baseHash: string): Promise<void>
-  applyConfig(client: GatewayClient, raw: string, baseHash: string): Promise<void>
+  getConfig(client: GatewayConnection): Promise<ConfigGetResult>
+  getSchema(client: GatewayConnection): Promise<ConfigSchemaResult>
+  patchConfig(client: GatewayConnection, patch: Record<string, unknown>, baseHash: string): Promise<void>
+  applyConfig(client: GatewayConnection, raw: string, baseHash: string): Promise<void>
 
   // Chat history
-  getHistory(client: GatewayClient, sessionKey: string, limit?: number): Promise<ChatHistoryResult>
+  getHistory(client: GatewayConnection, sessionKey: string, limit?: number): Promise<ChatHistoryResult>
 
   // System
-  getHealth(client: GatewayClient): Promise<HealthStatus>
-  getCronJobs(client: GatewayClient): Promise<unknown>
+  getHealth(client: GatewayConnection): Promise<HealthStatus>
+  getCronJobs(client: GatewayConnection): Promise<unknown>
 }
 
 /**
@@ -59,7 +60,7 @@ export interface GatewayAdapter {
 export class GatewayV1Adapter implements GatewayAdapter {
   readonly protocolVersion = '1.0'
 
-  async getAgents(client: GatewayClient): Promise<AgentsListResult> {
+  async getAgents(client: GatewayConnection): Promise<AgentsListResult> {
     const result = (await client.request('agents.list')) as
       | { defaultId?: string; agents?: GatewayAgent[] }
       | GatewayAgent[]
@@ -73,27 +74,27 @@ export class GatewayV1Adapter implements GatewayAdapter {
     }
   }
 
-  async getAgent(client: GatewayClient, agentId: string): Promise<GatewayAgent> {
+  async getAgent(client: GatewayConnection, agentId: string): Promise<GatewayAgent> {
     return (await client.request('agents.get', { agentId })) as GatewayAgent
   }
 
-  async getSessions(client: GatewayClient, agentId?: string): Promise<GatewaySession[]> {
+  async getSessions(client: GatewayConnection, agentId?: string): Promise<GatewaySession[]> {
     return (await client.request(
       'sessions.list',
       agentId ? { agentId } : undefined,
     )) as GatewaySession[]
   }
 
-  async getSession(client: GatewayClient, sessionId: string): Promise<GatewaySession> {
+  async getSession(client: GatewayConnection, sessionId: string): Promise<GatewaySession> {
     return (await client.request('sessions.get', { sessionId })) as GatewaySession
   }
 
-  async deleteSession(client: GatewayClient, sessionKey: string): Promise<void> {
+  async deleteSession(client: GatewayConnection, sessionKey: string): Promise<void> {
     await client.request('sessions.delete', { key: sessionKey })
   }
 
   async sendMessage(
-    client: GatewayClient,
+    client: GatewayConnection,
     sessionKey: string,
     message: string,
     idempotencyKey: string,
@@ -115,23 +116,23 @@ export class GatewayV1Adapter implements GatewayAdapter {
   }
 
   async getHistory(
-    client: GatewayClient,
+    client: GatewayConnection,
     sessionKey: string,
     limit = 200,
   ): Promise<ChatHistoryResult> {
-    return (await client.request('chat.history', { sessionKey, limit })) as ChatHistoryResult
+    return fetchChatHistory(client, sessionKey, limit)
   }
 
-  async getConfig(client: GatewayClient): Promise<ConfigGetResult> {
+  async getConfig(client: GatewayConnection): Promise<ConfigGetResult> {
     return (await client.request('config.get')) as ConfigGetResult
   }
 
-  async getSchema(client: GatewayClient): Promise<ConfigSchemaResult> {
+  async getSchema(client: GatewayConnection): Promise<ConfigSchemaResult> {
     return (await client.request('config.schema')) as ConfigSchemaResult
   }
 
   async patchConfig(
-    client: GatewayClient,
+    client: GatewayConnection,
     patch: Record<string, unknown>,
     baseHash: string,
   ): Promise<void> {
@@ -142,18 +143,18 @@ export class GatewayV1Adapter implements GatewayAdapter {
   }
 
   async applyConfig(
-    client: GatewayClient,
+    client: GatewayConnection,
     raw: string,
     baseHash: string,
   ): Promise<void> {
     await client.request('config.apply', { raw, baseHash })
   }
 
-  async getHealth(client: GatewayClient): Promise<HealthStatus> {
+  async getHealth(client: GatewayConnection): Promise<HealthStatus> {
     return (await client.request('health')) as HealthStatus
   }
 
-  async getCronJobs(client: GatewayClient): Promise<unknown> {
+  async getCronJobs(client: GatewayConnection): Promise<unknown> {
     return client.request('cron.list')
   }
 }
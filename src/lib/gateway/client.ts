@@ -1,5 +1,8 @@
 import { randomUUID } from 'crypto'
 import WebSocket from 'ws'
+import { checkLiteralHost, guardedLookup } from '@/lib/destination-policy'
+import { createLogger } from '@/lib/logger'
+import { formatTraceparent, startSpan } from '@/lib/tracing'
 import type {
   GatewayMessage,
   GatewayResponse,
@@ -12,15 +15,89 @@ const MAX_RECONNECT_ATTEMPTS = 10
 const BASE_RECONNECT_DELAY_MS = 1_000
 const MAX_RECONNECT_DELAY_MS = 32_000
 
+// Message size handling. History payloads with inline images can run to many
+// megabytes, so frames are compressed (permessage-deflate) unless
+// GATEWAY_WS_COMPRESSION=false, and incoming messages are size-checked:
+//
+//   GATEWAY_WS_MAX_MESSAGE_BYTES — larger messages are discarded and the
+//                                  request they answer fails with
+//                                  GatewayMessageTooLargeError; the connection
+//                                  stays up (default 16 MiB)
+//
+// The socket itself only gives up (close 1009) on messages over four times
+// that, which would otherwise have to be buffered whole.
+//
+// Each request is a tracing span (lib/tracing) under the caller's current
+// span. GATEWAY_TRACE_PROPAGATION=true also sends the span's traceparent in
+// the request frame, for gateways that continue the trace.
+
+const log = createLogger('gateway:client')
+
+const DEFAULT_MAX_MESSAGE_BYTES = 16 * 1024 * 1024
+// Request ids are looked for this far into an oversized response
+const ID_SCAN_BYTES = 1024
+
+function maxMessageBytes(): number {
+  const n = parseInt(process.env.GATEWAY_WS_MAX_MESSAGE_BYTES ?? '', 10)
+  return Number.isFinite(n) && n > 0 ? n : DEFAULT_MAX_MESSAGE_BYTES
+}
+
+function messageSize(data: WebSocket.Data): number {
+  if (Array.isArray(data)) return data.reduce((sum, chunk) => sum + chunk.length, 0)
+  return data instanceof ArrayBuffer ? data.byteLength : data.length
+}
+
 interface PendingRequest {
   resolve: (payload: unknown) => void
   reject: (error: Error) => void
   timer: ReturnType<typeof setTimeout>
 }
 
-type EventCallback = (payload: unknown) => void
+export type EventCallback = (payload: unknown) => void
+
+export type ConnectionStatus = 'connecting' | 'connected' | 'disconnected' | 'error'
+
+/** The request never got an answer because the connection was down or dropped */
+export class GatewayConnectionError extends Error {
+  constructor(message: string) {
+    super(message)
+    this.name = 'GatewayConnectionError'
+  }
+}
+
+/** The gateway's answer was over GATEWAY_WS_MAX_MESSAGE_BYTES and was discarded */
+export class GatewayMessageTooLargeError extends Error {
+  constructor(
+    message: string,
+    public readonly size: number,
+  ) {
+    super(message)
+    this.name = 'GatewayMessageTooLargeError'
+  }
+}
+
+/**
+ * What the rest of the app needs from a gateway connection. GatewayClient is
+ * the WebSocket implementation; FakeGatewayClient (./fake) is an in-memory
+ * stand-in for running without a live OpenClaw.
+ */
+export interface GatewayConnection {
+  serverVersion: string | null
+  onStatusChange?: (status: ConnectionStatus) => void
+  onPermanentDisconnect?: () => void
+  onRequestComplete?: (method: string, durationMs: number, ok: boolean) => void
+  traceAttributes?: Record<string, string>
+
+  connect(): Promise<void>
+  disconnect(): void
+  isConnected(): boolean
+  waitForConnection(timeoutMs?: number): Promise<boolean>
+  request(method: string, params?: Record<string, unknown>, timeoutMs?: number): Promise<unknown>
+  on(event: string, callback: EventCallback): () => void
+  off(event: string, callback: EventCallback): void
+}
 
-export class GatewayClient {
+export class GatewayClient implements GatewayConnection {
   private ws: WebSocket | null = null
   private url: string
   private token: string
@@ -42,8 +119,12 @@ export class GatewayClient {
   /** Server version extracted from the hello-ok handshake payload. */
   public serverVersion: string | null = null
 
-  onStatusChange?: (status: 'connecting' | 'connected' | 'disconnected' | 'error') => void
+  onStatusChange?: (status: ConnectionStatus) => void
   onPermanentDisconnect?: () => void
+  /** Called when a request settles (resolved, rejected, or timed out) */
+  onRequestComplete?: (method: string, durationMs: number, ok: boolean) => void
+  /** Extra attributes for the tracing span of each request */
+  traceAttributes?: Record<string, string>
 
   constructor(url: string, token: string) {
     this.url = url
@@ -63,6 +144,14 @@ export class GatewayClient {
     this.intentionalDisconnect = false
     this.onStatusChange?.('connecting')
 
+    // Outbound policy: IP literals are checked here, DNS names in guardedLookup.
+    // A denied destination is not retried.
+    const denied = checkLiteralHost(new URL(this.url).hostname)
+    if (denied) {
+      this.onStatusChange?.('disconnected')
+      throw denied
+    }
+
     return new Promise<void>((resolve, reject) => {
       this.connectResolve = resolve
       this.connectReject = reject
@@ -88,18 +177,30 @@ export class GatewayClient {
         const parsed = new URL(loopbackUrl)
         headers['Host'] = parsed.host
       }
-      this.ws = new WebSocket(this.url, { headers })
+      const limit = maxMessageBytes()
+      this.ws = new WebSocket(this.url, {
+        headers,
+        lookup: guardedLookup,
+        perMessageDeflate: process.env.GATEWAY_WS_COMPRESSION !== 'false',
+        maxPayload: limit * 4,
+      })
 
       this.ws.on('message', (data: WebSocket.Data) => {
         this.handleMessage(data)
       })
 
-      this.ws.on('close', () => {
+      this.ws.on('close', (code: number) => {
+        if (code === 1009) {
+          log.warn('Gateway connection closed: message too large', { url: this.url, maxPayload: limit * 4 })
+        }
         this.clearConnectTimer()
         this.connected = false
         this.stopTickWatch()
         this.onStatusChange?.('disconnected')
 
+        // Answers to requests sent on this socket can no longer arrive
+        this.rejectAllPending('Connection to the gateway was lost')
+
         // Reject any pending connect() promise so the caller doesn't hang.
         // handleReconnect() will create a fresh connect() call with its own promise.
         if (this.connectReject) {
@@ -170,20 +271,46 @@ export class GatewayClient {
   request(method: string, params?: Record<string, unknown>, timeoutMs?: number): Promise<unknown> {
     return new Promise((resolve, reject) => {
       if (!this.ws || this.ws.readyState !== WebSocket.OPEN) {
-        return reject(new Error('WebSocket is not connected'))
+        return reject(new GatewayConnectionError('WebSocket is not connected'))
       }
 
       const timeout = timeoutMs ?? REQUEST_TIMEOUT_MS
       const id = randomUUID()
+      const started = Date.now()
+      const span = startSpan(`gateway ${method}`, {
+        kind: 'client',
+        attributes: { 'rpc.system': 'openclaw', 'rpc.method': method, 'rpc.request_id': id, ...this.traceAttributes },
+      })
+      const settle = (ok: boolean, err?: Error) => {
+        if (err) span.setError(err.message)
+        span.end()
+        if (method !== 'connect') this.onRequestComplete?.(method, Date.now() - started, ok)
+      }
+
       const timer = setTimeout(() => {
         this.pending.delete(id)
-        reject(new Error(`Request ${method} (id=${id}) timed out after ${timeout}ms`))
+        const err = new Error(`Request ${method} (id=${id}) timed out after ${timeout}ms`)
+        settle(false, err)
+        reject(err)
       }, timeout)
 
-      this.pending.set(id, { resolve, reject, timer })
+      this.pending.set(id, {
+        resolve: (payload) => {
+          settle(true)
+          resolve(payload)
+        },
+        reject: (err) => {
+          settle(false, err)
+          reject(err)
+        },
+        timer,
+      })
 
+      const traceparent = process.env.GATEWAY_TRACE_PROPAGATION === 'true' && span.context.sampled
+        ? formatTraceparent(span.context)
+        : undefined
       this.ws.send(
-        JSON.stringify({ type: 'req', id, method, params }),
+        JSON.stringify({ type: 'req', id, method, params, ...(traceparent ? { traceparent } : {}) }),
       )
     })
   }
@@ -207,6 +334,12 @@ export class GatewayClient {
   // --- Private -----------------------------------------------------------
 
   private handleMessage(data: WebSocket.Data): void {
+    const size = messageSize(data)
+    if (size > maxMessageBytes()) {
+      this.rejectOversized(data, size)
+      return
+    }
+
     let msg: GatewayMessage
     try {
       msg = JSON.parse(data.toString()) as GatewayMessage
@@ -323,6 +456,32 @@ export class GatewayClient {
     }
   }
 
+  /**
+   * Fail the request an oversized response answers, without parsing it: the
+   * request id is picked out of the start of the frame.
+   */
+  private rejectOversized(data: WebSocket.Data, size: number): void {
+    const first = Array.isArray(data) ? data[0] : data
+    const head = (first instanceof ArrayBuffer ? Buffer.from(first, 0, ID_SCAN_BYTES) : first.subarray(0, ID_SCAN_BYTES))
+      .toString('utf-8')
+    const id = [...head.matchAll(/"id"\s*:\s*"([^"]+)"/g)]
+      .map((m) => m[1])
+      .find((candidate) => this.pending.has(candidate))
+
+    log.warn('Discarded oversized gateway message', { url: this.url, size, requestId: id ?? null })
+    if (!id) return
+
+    const pending = this.pending.get(id)!
+    clearTimeout(pending.timer)
+    this.pending.delete(id)
+    pending.reject(
+      new GatewayMessageTooLargeError(
+        `Gateway response of ${size} bytes exceeds the ${maxMessageBytes()} byte limit`,
+        size,
+      ),
+    )
+  }
+
   private handleReconnect(): void {
     if (this.reconnectAttempts >= MAX_RECONNECT_ATTEMPTS) {
       this.rejectAllPending('Max reconnect attempts reached')
@@ -382,7 +541,7 @@ export class GatewayClient {
   private rejectAllPending(reason: string): void {
     for (const [id, pending] of this.pending) {
       clearTimeout(pending.timer)
-      pending.reject(new Error(reason))
+      pending.reject(new GatewayConnectionError(reason))
       this.pending.delete(id)
     }
   }
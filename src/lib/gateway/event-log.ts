@@ -0,0 +1,148 @@
+import { prisma } from '@/lib/db'
+import { createLogger } from '@/lib/logger'
+import { onInstanceDeleted } from '@/lib/instances/events'
+import type { ConnectionStatus, GatewayConnection } from './client'
+import type { GatewayEvent, Prisma } from '@/generated/prisma'
+
+// Gateway event log: the significant push events each instance sent, stored
+// so admins can see what a gateway actually did (GET /api/v1/gateway/[id]/events)
+// without attaching a live stream. Recorded are
+//
+//   chat        — final, error and aborted states (not deltas)
+//   agent       — lifecycle events (run start / end / error), not tool or text streams
+//   shutdown    — the gateway announcing it is going down
+//   connection  — TeamClaw's own connection to the gateway changing status
+//
+// Rows are buffered and written in batches; payloads over
+// GATEWAY_EVENT_MAX_BYTES are cut to a preview.
+//
+// GATEWAY_EVENT_LOG=false          — record nothing
+// GATEWAY_EVENT_RETENTION_DAYS     — how long rows are kept (default 7)
+// GATEWAY_EVENT_MAX_BYTES          — largest payload stored whole (default 16384)
+
+const FLUSH_INTERVAL_MS = 2_000
+const MAX_BUFFERED = 5_000
+const PRUNE_INTERVAL_MS = 60 * 60_000
+
+const CHAT_STATES = new Set(['final', 'error', 'aborted'])
+
+const log = createLogger('gateway:event-log')
+
+type GatewayEventRow = Prisma.GatewayEventCreateManyInput
+
+const globalForEventLog = globalThis as unknown as {
+  gatewayEventBuffer?: GatewayEventRow[]
+  gatewayEventFlushTimer?: ReturnType<typeof setTimeout> | null
+  gatewayEventPruneTimer?: ReturnType<typeof setInterval> | null
+}
+
+const buffer = (globalForEventLog.gatewayEventBuffer ??= [])
+
+function intEnv(name: string, fallback: number): number {
+  const n = parseInt(process.env[name] ?? '', 10)
+  return Number.isFinite(n) && n > 0 ? n : fallback
+}
+
+const enabled = () => process.env.GATEWAY_EVENT_LOG !== 'false'
+
+// Buffered rows of a deleted instance would fail the whole batch on the foreign key
+onInstanceDeleted(({ instanceId }) => {
+  for (let i = buffer.length - 1; i >= 0; i--) {
+    if (buffer[i].instanceId === instanceId) buffer.splice(i, 1)
+  }
+})
+
+function storedPayload(payload: unknown): Prisma.InputJsonValue {
+  const json = JSON.stringify(payload ?? null)
+  const max = intEnv('GATEWAY_EVENT_MAX_BYTES', 16_384)
+  if (Buffer.byteLength(json) <= max) return (payload ?? {}) as Prisma.InputJsonValue
+  return { truncated: true, bytes: Buffer.byteLength(json), preview: json.slice(0, max) }
+}
+
+async function flush(): Promise<void> {
+  globalForEventLog.gatewayEventFlushTimer = null
+  const batch = buffer.splice(0, buffer.length)
+  if (batch.length === 0) return
+  try {
+    await prisma.gatewayEvent.createMany({ data: batch })
+  } catch (err) {
+    log.warn('Could not write gateway events', { count: batch.length, error: (err as Error).message })
+  }
+}
+
+function record(row: GatewayEventRow): void {
+  if (buffer.length >= MAX_BUFFERED) return
+  buffer.push({ ...row, createdAt: new Date() })
+  globalForEventLog.gatewayEventFlushTimer ??= setTimeout(() => void flush(), FLUSH_INTERVAL_MS)
+}
+
+const str = (v: unknown) => (typeof v === 'string' ? v : null)
+
+/** Record the instance's significant events; returns a function that stops recording */
+export function attachEventLog(instanceId: string, client: GatewayConnection): () => void {
+  if (!enabled()) return () => {}
+
+  const unsubChat = client.on('chat', (payload) => {
+    const evt = (payload ?? {}) as Record<string, unknown>
+    const state = str(evt.state)
+    if (!state || !CHAT_STATES.has(state)) return
+    record({ instanceId, event: 'chat', state, runId: str(evt.runId), sessionKey: str(evt.sessionKey), payload: storedPayload(evt) })
+  })
+
+  const unsubAgent = client.on('agent', (payload) => {
+    const evt = (payload ?? {}) as Record<string, unknown>
+    if (evt.stream !== 'lifecycle') return
+    const data = (evt.data ?? {}) as Record<string, unknown>
+    record({
+      instanceId,
+      event: 'agent',
+      state: str(data.phase),
+      runId: str(evt.runId),
+      sessionKey: str(evt.sessionKey),
+      payload: storedPayload(evt),
+    })
+  })
+
+  const unsubShutdown = client.on('shutdown', (payload) => {
+    record({ instanceId, event: 'shutdown', payload: storedPayload(payload) })
+  })
+
+  return () => {
+    unsubChat()
+    unsubAgent()
+    unsubShutdown()
+  }
+}
+
+/** Record TeamClaw's connection to the instance changing status */
+export function recordConnectionStatus(instanceId: string, status: ConnectionStatus): void {
+  if (!enabled()) return
+  record({ instanceId, event: 'connection', state: status, payload: { status } })
+}
+
+async function prune(): Promise<void> {
+  const cutoff = new Date(Date.now() - intEnv('GATEWAY_EVENT_RETENTION_DAYS', 7) * 24 * 3600_000)
+  const { count } = await prisma.gatewayEvent.deleteMany({ where: { createdAt: { lt: cutoff } } })
+  if (count > 0) log.info('Pruned gateway events', { count })
+}
+
+/** Start the retention job (idempotent across hot reloads) */
+export function startGatewayEventPruning(): void {
+  if (globalForEventLog.gatewayEventPruneTimer) return
+  prune().catch((err) => log.error('Gateway event pruning failed', { error: (err as Error).message }))
+  globalForEventLog.gatewayEventPruneTimer = setInterval(() => {
+    prune().catch((err) => log.error('Gateway event pruning failed', { error: (err as Error).message }))
+  }, PRUNE_INTERVAL_MS)
+}
+
+export function toGatewayEventResponse(e: GatewayEvent) {
+  return {
+    id: e.id,
+    event: e.event,
+    state: e.state,
+    runId: e.runId,
+    sessionKey: e.sessionKey,
+    payload: e.payload,
+    createdAt: e.createdAt.toISOString(),
+  }
+}
@@ -0,0 +1,247 @@
+import { createHash, randomUUID } from 'crypto'
+import { GatewayConnectionError } from './client'
+import type { ConnectionStatus, EventCallback, GatewayConnection } from './client'
+import type { ChatHistoryMessage, GatewayAgent, GatewaySession } from '@/types/gateway'
+
+// In-memory stand-in for an OpenClaw gateway. Implements the handshake,
+// push events and the common methods (health, agents, sessions, chat,
+// config) closely enough that chat and agent handlers run unchanged.
+// Replies stream as `chat` delta events followed by a final, like the real
+// gateway. Methods can be overridden or added per test with handle(), and
+// every request is recorded in `requests` for assertions.
+
+type Handler = (params: Record<string, unknown>) => unknown | Promise<unknown>
+
+export interface FakeGatewayOptions {
+  agents?: GatewayAgent[]
+  /** Reply for a chat.send; defaults to echoing the message */
+  reply?: (message: string, sessionKey: string) => string
+  /** Delay between streamed chunks */
+  chunkDelayMs?: number
+  serverVersion?: string
+  /** Make connect() fail, to exercise error paths */
+  failConnect?: boolean
+}
+
+const DEFAULT_AGENTS: GatewayAgent[] = [
+  { id: 'main', name: 'Main', status: 'idle', workspace: '~/.openclaw/workspace', model: 'fake/model' },
+]
+
+function hashConfig(raw: string): string {
+  return createHash('sha256').update(raw).digest('hex').slice(0, 16)
+}
+
+function agentIdFromKey(sessionKey: string): string {
+  return sessionKey.split(':')[1] ?? 'main'
+}
+
+export class FakeGatewayClient implements GatewayConnection {
+  serverVersion: string | null = null
+  onStatusChange?: (status: ConnectionStatus) => void
+  onPermanentDisconnect?: () => void
+  onRequestComplete?: (method: string, durationMs: number, ok: boolean) => void
+
+  /** Every request received, in order */
+  readonly requests: { method: string; params: Record<string, unknown> }[] = []
+
+  private connected = false
+  private listeners = new Map<string, Set<EventCallback>>()
+  private handlers = new Map<string, Handler>()
+  private agents: GatewayAgent[]
+  private sessions = new Map<string, { messages: ChatHistoryMessage[]; createdAt: string }>()
+  private config: Record<string, unknown> = { agents: { list: [] } }
+  private opts: FakeGatewayOptions
+
+  constructor(opts: FakeGatewayOptions = {}) {
+    this.opts = opts
+    this.agents = opts.agents ?? DEFAULT_AGENTS
+    this.registerDefaults()
+  }
+
+  async connect(): Promise<void> {
+    this.onStatusChange?.('connecting')
+    if (this.opts.failConnect) {
+      this.onStatusChange?.('disconnected')
+      throw new Error('Connect handshake timed out')
+    }
+    this.connected = true
+    this.serverVersion = this.opts.serverVersion ?? 'fake'
+    this.onStatusChange?.('connected')
+  }
+
+  disconnect(): void {
+    this.connected = false
+    this.onStatusChange?.('disconnected')
+  }
+
+  isConnected(): boolean {
+    return this.connected
+  }
+
+  async waitForConnection(): Promise<boolean> {
+    return this.connected
+  }
+
+  async request(method: string, params: Record<string, unknown> = {}): Promise<unknown> {
+    if (!this.connected) throw new GatewayConnectionError('WebSocket is not connected')
+    this.requests.push({ method, params })
+
+    const handler = this.handlers.get(method)
+    const started = Date.now()
+    try {
+      if (!handler) throw new Error(`[UNKNOWN_METHOD] Unknown method: ${method}`)
+      const result = await handler(params)
+      this.onRequestComplete?.(method, Date.now() - started, true)
+      return result
+    } catch (err) {
+      this.onRequestComplete?.(method, Date.now() - started, false)
+      throw err
+    }
+  }
+
+  on(event: string, callback: EventCallback): () => void {
+    let set = this.listeners.get(event)
+    if (!set) {
+      set = new Set()
+      this.listeners.set(event, set)
+    }
+    set.add(callback)
+    return () => this.off(event, callback)
+  }
+
+  off(event: string, callback: EventCallback): void {
+    this.listeners.get(event)?.delete(callback)
+  }
+
+  // --- Test controls -----------------------------------------------------
+
+  /** Push an event to subscribers, as the gateway would */
+  emit(event: string, payload: unknown): void {
+    for (const cb of this.listeners.get(event) ?? []) {
+      try {
+        cb(payload)
+      } catch {
+        // listener errors should not crash the client
+      }
+    }
+  }
+
+  /** Override or add a method */
+  handle(method: string, handler: Handler): this {
+    this.handlers.set(method, handler)
+    return this
+  }
+
+  /** Simulate the gateway going away for good (reconnects exhausted) */
+  dropConnection(): void {
+    this.connected = false
+    this.onStatusChange?.('error')
+    this.onPermanentDisconnect?.()
+  }
+
+  // --- Default methods ---------------------------------------------------
+
+  private registerDefaults(): void {
+    this.handle('health', () => ({
+      status: 'healthy',
+      uptime: Math.round(process.uptime()),
+      version: this.serverVersion,
+      agents: this.agents.map((a) => ({ id: a.id, status: a.status })),
+    }))
+    this.handle('agents.list', () => ({ defaultId: this.agents[0]?.id ?? null, agents: this.agents }))
+    this.handle('agents.get', ({ agentId }) => {
+      const agent = this.agents.find((a) => a.id === agentId)
+      if (!agent) throw new Error(`[NOT_FOUND] Agent ${agentId} not found`)
+      return agent
+    })
+
+    this.handle('sessions.list', ({ agentId }) => this.listSessions(agentId as string | undefined))
+    this.handle('sessions.get', ({ sessionId }) => {
+      const session = this.listSessions().find((s) => s.id === sessionId)
+      if (!session) throw new Error(`[NOT_FOUND] Session ${sessionId} not found`)
+      return session
+    })
+    this.handle('sessions.delete', ({ key }) => {
+      this.sessions.delete(key as string)
+      return { ok: true }
+    })
+
+    this.handle('chat.send', ({ sessionKey, message, idempotencyKey }) => {
+      const key = sessionKey as string
+      const runId = (idempotencyKey as string) ?? randomUUID()
+      const session = this.sessions.get(key) ?? { messages: [], createdAt: new Date().toISOString() }
+      this.sessions.set(key, session)
+      session.messages.push({ role: 'user', content: message as string })
+
+      const text = this.opts.reply?.(message as string, key) ?? `Echo: ${message as string}`
+      void this.streamReply(key, runId, text)
+      return { runId, status: 'started' }
+    })
+    this.handle('chat.history', ({ sessionKey, limit, offset }) => {
+      const messages = this.sessions.get(sessionKey as string)?.messages ?? []
+      const end = messages.length - (Number(offset) || 0)
+      const start = Math.max(0, end - (Number(limit) || 200))
+      return { sessionId: sessionKey, messages: messages.slice(start, Math.max(end, 0)), hasMore: start > 0 }
+    })
+    this.handle('chat.abort', ({ runId }) => {
+      this.emit('chat', { runId, state: 'aborted' })
+      return { ok: true }
+    })
+
+    this.handle('config.get', () => {
+      const raw = JSON.stringify(this.config, null, 2)
+      return { raw, hash: hashConfig(raw), config: this.config }
+    })
+    this.handle('config.schema', () => ({ schema: { type: 'object' }, uiHints: {}, version: this.serverVersion }))
+    this.handle('config.patch', ({ raw, baseHash }) => {
+      this.checkHash(baseHash)
+      this.config = { ...this.config, ...(JSON.parse(raw as string) as Record<string, unknown>) }
+      return { ok: true }
+    })
+    this.handle('config.apply', ({ raw, baseHash }) => {
+      this.checkHash(baseHash)
+      this.config = JSON.parse(raw as string) as Record<string, unknown>
+      return { ok: true }
+    })
+    this.handle('cron.list', () => [])
+  }
+
+  private checkHash(baseHash: unknown): void {
+    if (baseHash !== hashConfig(JSON.stringify(this.config, null, 2))) {
+      throw new Error('[INVALID_REQUEST] config changed since last load; baseHash mismatch')
+    }
+  }
+
+  private listSessions(agentId?: string): GatewaySession[] {
+    return [...this.sessions]
+      .filter(([key]) => !agentId || agentIdFromKey(key) === agentId)
+      .map(([key, s]) => ({
+        id: key,
+        agentId: agentIdFromKey(key),
+        status: 'idle',
+        messageCount: s.messages.length,
+        createdAt: s.createdAt,
+      }))
+  }
+
+  /** Stream the reply word by word as cumulative deltas, then the final */
+  private async streamReply(sessionKey: string, runId: string, text: string): Promise<void> {
+    const words = text.match(/\S+\s*/g) ?? [text]
+    let sent = ''
+    for (const word of words) {
+      await new Promise((r) => setTimeout(r, this.opts.chunkDelayMs ?? 20))
+      if (!this.connected) return
+      sent += word
+      this.emit('chat', {
+        runId,
+        sessionKey,
+        state: 'delta',
+        message: { role: 'assistant', content: [{ type: 'text', text: sent }] },
+      })
+    }
+
+    const message = { role: 'assistant' as const, content: [{ type: 'text', text }] }
+    this.sessions.get(sessionKey)?.messages.push(message)
+    this.emit('chat', { runId, sessionKey, state: 'final', message })
+  }
+}
@@ -0,0 +1,54 @@
+import { registry } from './registry'
+import { resolveAdapter } from './adapter'
+import { DestinationDeniedError } from '@/lib/destination-policy'
+import type { GatewayHandshakeErrorCode, GatewayHandshakeResult } from '@/types/instance'
+
+// Credential pre-check for instance create / update (?validate=true): open a
+// throwaway connection, complete the connect handshake, list agents, and
+// close it again — nothing is registered or written.
+
+function classify(err: Error): GatewayHandshakeErrorCode {
+  const msg = err.message
+  const code = (err as NodeJS.ErrnoException).code ?? ''
+  if (err instanceof DestinationDeniedError) return 'destination_denied'
+  if (/^(ECONNREFUSED|ENOTFOUND|EHOSTUNREACH|ENETUNREACH|EAI_AGAIN|ECONNRESET)$/.test(code)) return 'unreachable'
+  if (/CERT|SSL|TLS|SELF_SIGNED/i.test(code) || /certificate|self[- ]signed/i.test(msg)) return 'tls'
+  if (/timed out/i.test(msg)) return 'timeout'
+  if (/Unexpected server response: (401|403)/.test(msg) || /unauthori[sz]ed|forbidden|auth|token/i.test(msg)) {
+    return 'auth_failed'
+  }
+  if (/protocol/i.test(msg)) return 'protocol_mismatch'
+  return 'handshake_failed'
+}
+
+/** Handshake with a gateway without registering it. Never throws. */
+export async function probeGateway(url: string, token: string): Promise<GatewayHandshakeResult> {
+  const start = Date.now()
+  try {
+    new URL(url.replace(/^ws/, 'http'))
+  } catch {
+    return { ok: false, url, latencyMs: 0, error: { code: 'invalid_url', message: 'Invalid gateway URL' } }
+  }
+
+  const client = registry.createDetachedClient(url, token)
+  try {
+    await client.connect()
+    const latencyMs = Date.now() - start
+    const adapter = resolveAdapter(client.serverVersion ?? undefined)
+    const { agents } = await adapter.getAgents(client).catch(() => ({ agents: undefined }))
+    return {
+      ok: true,
+      url,
+      latencyMs,
+      serverVersion: client.serverVersion,
+      protocolVersion: adapter.protocolVersion,
+      agentCount: agents?.length,
+    }
+  } catch (err) {
+    const e = err instanceof Error ? err : new Error(String(err))
+    return { ok: false, url, latencyMs: Date.now() - start, error: { code: classify(e), message: e.message } }
+  } finally {
+    // Also cancels the reconnect a failed handshake would schedule
+    client.disconnect()
+  }
+}
@@ -2,6 +2,9 @@ import type { Prisma } from '@/generated/prisma'
 import { prisma } from '@/lib/db'
 import { redis } from '@/lib/redis'
 import { decrypt } from '@/lib/auth/encryption'
+import { createLogger } from '@/lib/logger'
+import { transitionInstanceStatus } from '@/lib/instances/status'
+import { onInstanceDeleted } from '@/lib/instances/events'
 import { registry, ensureRegistryInitialized, resolveGatewayUrl } from './registry'
 
 /** Return the version string only if it looks like a real release (not "dev", "unknown", etc.). */
@@ -16,6 +19,8 @@ const HEALTH_TIMEOUT_MS = 10_000
 const MAX_CONCURRENT = 5
 const FAILURE_THRESHOLD = 3
 
+const log = createLogger('gateway:health')
+
 const globalForHealth = globalThis as unknown as {
   healthIntervalTimer?: ReturnType<typeof setInterval> | null
   healthRecoveryTimer?: ReturnType<typeof setInterval> | null
@@ -23,6 +28,11 @@ const globalForHealth = globalThis as unknown as {
   healthEnsured?: boolean
 }
 
+// Failure counters of deleted instances would otherwise linger until their TTL
+onInstanceDeleted(({ instanceId }) => {
+  redis.del(`health_failures:${instanceId}`).catch(() => {})
+})
+
 async function checkInstance(instanceId: string): Promise<void> {
   const failureKey = `health_failures:${instanceId}`
 
@@ -41,14 +51,10 @@ async function checkInstance(instanceId: string): Promise<void> {
 
     // Success: update DB + reset failure counter
     await Promise.all([
-      prisma.instance.update({
-        where: { id: instanceId },
-        data: {
-          status: 'ONLINE',
-          lastHealthCheck: new Date(),
-          healthData: health as Prisma.InputJsonValue,
-          version: usableVersion(health.version as string) ?? usableVersion(registry.getServerVersion(instanceId)) ?? undefined,
-        },
+      transitionInstanceStatus(instanceId, 'health_ok', {
+        lastHealthCheck: new Date(),
+        healthData: health as Prisma.InputJsonValue,
+        version: usableVersion(health.version as string) ?? usableVersion(registry.getServerVersion(instanceId)) ?? undefined,
       }),
       redis.del(failureKey),
     ])
@@ -57,14 +63,8 @@ async function checkInstance(instanceId: string): Promise<void> {
     const failures = await redis.incr(failureKey)
     await redis.expire(failureKey, 600) // 10 min TTL
 
-    const newStatus = failures >= FAILURE_THRESHOLD ? 'OFFLINE' : 'DEGRADED'
-
-    await prisma.instance.update({
-      where: { id: instanceId },
-      data: {
-        status: newStatus,
-        lastHealthCheck: new Date(),
-      },
+    await transitionInstanceStatus(instanceId, failures >= FAILURE_THRESHOLD ? 'health_failed' : 'health_degraded', {
+      lastHealthCheck: new Date(),
     })
   }
 }
@@ -97,9 +97,10 @@ async function recoverInstances(): Promise<void> {
             }
             await registry.connect(inst.id, resolveGatewayUrl(inst), decrypt(inst.gatewayToken))
           }
-          // Connection succeeded — run health check to update status to ONLINE
+          // Connection succeeded — DEGRADED first, then the health check promotes it to ONLINE
+          await transitionInstanceStatus(inst.id, 'reconnected')
           await checkInstance(inst.id)
-          console.log(`[health] Recovered instance ${inst.name} (${inst.id})`)
+          log.info('Recovered instance', { instanceId: inst.id, name: inst.name })
         } catch {
           // Still unreachable — leave in current state, will retry next cycle
         }
@@ -176,5 +177,21 @@ export async function ensureHealthChecks(): Promise<void> {
     globalForHealth.healthRunning = true
     startHealthChecks()
     startRecoveryChecks()
+
+    // Keep AgentMeta in step with what the gateways report
+    import('@/lib/agents/sync').then(({ syncAllAgents, startAgentSync }) => {
+      syncAllAgents().catch(console.error)
+      startAgentSync()
+    })
+
+    import('./slo-alerts').then(({ startSloAlerts }) => startSloAlerts())
+    import('@/lib/probes').then(({ startProbeScheduler }) => startProbeScheduler())
+    import('@/lib/agents/evals').then(({ failInterruptedEvalRuns }) => failInterruptedEvalRuns().catch(console.error))
+    import('@/lib/exports').then(({ failInterruptedExportJobs }) => failInterruptedExportJobs().catch(console.error))
+    import('@/lib/dashboard/stats').then(({ startDashboardStatsRefresh }) => startDashboardStatsRefresh())
+    import('@/lib/access-reviews').then(({ startAccessReviewScheduler }) => startAccessReviewScheduler())
+    import('@/lib/chat/compaction').then(({ startSnapshotCompaction }) => startSnapshotCompaction())
+    import('@/lib/chat/live-reconciliation').then(({ startLiveReconciliation }) => startLiveReconciliation())
+    import('./event-log').then(({ startGatewayEventPruning }) => startGatewayEventPruning())
   }
 }
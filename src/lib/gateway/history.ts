@@ -0,0 +1,77 @@
+import { createLogger } from '@/lib/logger'
+import { GatewayMessageTooLargeError, type GatewayConnection } from './client'
+import type { ChatHistoryMessage, ChatHistoryResult } from '@/types/gateway'
+
+// chat.history in pages, so a long session with inline images does not have
+// to come back in one WebSocket message. Pages walk back from the newest
+// message with `offset`; a gateway that pages answers with `hasMore`. One
+// that does not (no `hasMore` in the answer) is asked once for the whole
+// limit, and remembered per connection.
+//
+// A page over the connection's message limit (GatewayMessageTooLargeError)
+// is retried at half the size; when even a few messages are too large, the
+// older history is left out.
+//
+// CHAT_HISTORY_PAGE_SIZE — messages per chat.history request (default 50)
+
+const log = createLogger('gateway:history')
+
+const MIN_PAGE_SIZE = 5
+
+const unpaged = new WeakSet<GatewayConnection>()
+
+function pageSize(): number {
+  const n = parseInt(process.env.CHAT_HISTORY_PAGE_SIZE ?? '', 10)
+  return Number.isFinite(n) && n > 0 ? n : 50
+}
+
+/** The newest `limit` messages of a session, oldest first */
+export async function fetchChatHistory(
+  client: GatewayConnection,
+  sessionKey: string,
+  limit = 200,
+  timeoutMs?: number,
+): Promise<ChatHistoryResult> {
+  const request = async (params: Record<string, unknown>) =>
+    (await client.request('chat.history', { sessionKey, ...params }, timeoutMs)) as ChatHistoryResult
+
+  if (unpaged.has(client)) return request({ limit })
+
+  let size = Math.min(pageSize(), limit)
+  let sessionId = sessionKey
+  const pages: ChatHistoryMessage[][] = []
+  let fetched = 0
+  let hasMore = false
+
+  while (fetched < limit) {
+    let page: ChatHistoryResult
+    try {
+      page = await request({ limit: Math.min(size, limit - fetched), offset: fetched })
+    } catch (err) {
+      if (!(err instanceof GatewayMessageTooLargeError)) throw err
+      if (size > MIN_PAGE_SIZE) {
+        size = Math.max(MIN_PAGE_SIZE, Math.floor(size / 2))
+        continue
+      }
+      if (fetched === 0) throw err
+      log.warn('Older history left out: messages too large', { sessionKey, fetched })
+      hasMore = true
+      break
+    }
+
+    const messages = page.messages ?? []
+    sessionId = page.sessionId ?? sessionId
+    if (page.hasMore === undefined) {
+      // Gateway without paging: `offset` was ignored and this is the newest page
+      unpaged.add(client)
+      return messages.length < size ? page : request({ limit })
+    }
+
+    pages.unshift(messages)
+    fetched += messages.length
+    hasMore = page.hasMore
+    if (!hasMore || messages.length === 0) break
+  }
+
+  return { sessionId, messages: pages.flat(), hasMore }
+}
@@ -1,3 +1,5 @@
-export { GatewayClient } from './client'
+export { GatewayClient, GatewayMessageTooLargeError, type GatewayConnection } from './client'
+export { fetchChatHistory } from './history'
+export { FakeGatewayClient } from './fake'
 export { type GatewayAdapter, GatewayV1Adapter, resolveAdapter } from './adapter'
 export { GatewayRegistry, registry, ensureRegistryInitialized } from './registry'
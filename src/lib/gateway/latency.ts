@@ -0,0 +1,144 @@
+// Rolling per-(instance, method) latency windows for gateway requests.
+//
+// Each request is also judged against the method's SLO threshold: a request
+// is "bad" if it failed or took longer than the threshold. Burn rate is the
+// bad ratio divided by the error budget (1 - objective), so 1.0 means the
+// budget is being spent exactly as fast as allowed.
+//
+// GATEWAY_SLO_OBJECTIVE     — fraction of requests that must be good (0.95)
+// GATEWAY_SLO_THRESHOLDS_MS — per-method thresholds: "chat.send=5000,chat.history=2000"
+// GATEWAY_SLO_DEFAULT_MS    — threshold for methods not listed (10000)
+
+const WINDOW_MS = 15 * 60_000
+const MAX_SAMPLES = 1_000
+
+const DEFAULT_THRESHOLDS_MS: Record<string, number> = {
+  'chat.send': 5_000,
+  'chat.history': 3_000,
+}
+
+interface Sample {
+  at: number
+  ms: number
+  ok: boolean
+}
+
+export interface LatencyStats {
+  instanceId: string
+  method: string
+  count: number
+  errors: number
+  p50: number
+  p95: number
+  p99: number
+  sloThresholdMs: number
+  badRatio: number
+  burnRate: number
+}
+
+function parseThresholds(): Record<string, number> {
+  const out = { ...DEFAULT_THRESHOLDS_MS }
+  for (const pair of (process.env.GATEWAY_SLO_THRESHOLDS_MS ?? '').split(',')) {
+    const [method, ms] = pair.split('=').map((s) => s.trim())
+    const n = Number(ms)
+    if (method && n > 0) out[method] = n
+  }
+  return out
+}
+
+export function sloObjective(): number {
+  const v = Number(process.env.GATEWAY_SLO_OBJECTIVE)
+  return v > 0 && v < 1 ? v : 0.95
+}
+
+export function sloThreshold(method: string): number {
+  return parseThresholds()[method] ?? (Number(process.env.GATEWAY_SLO_DEFAULT_MS) || 10_000)
+}
+
+export function percentile(sorted: number[], p: number): number {
+  if (sorted.length === 0) return 0
+  const idx = Math.min(sorted.length - 1, Math.ceil(p * sorted.length) - 1)
+  return sorted[Math.max(0, idx)]
+}
+
+export class LatencyTracker {
+  private windows = new Map<string, { instanceId: string; method: string; samples: Sample[] }>()
+
+  record(instanceId: string, method: string, ms: number, ok: boolean): void {
+    const key = `${instanceId}|${method}`
+    let w = this.windows.get(key)
+    if (!w) {
+      w = { instanceId, method, samples: [] }
+      this.windows.set(key, w)
+    }
+    w.samples.push({ at: Date.now(), ms, ok })
+    if (w.samples.length > MAX_SAMPLES) w.samples.splice(0, w.samples.length - MAX_SAMPLES)
+  }
+
+  forget(instanceId: string): void {
+    for (const [key, w] of this.windows) {
+      if (w.instanceId === instanceId) this.windows.delete(key)
+    }
+  }
+
+  stats(filter?: { instanceId?: string; method?: string }): LatencyStats[] {
+    const cutoff = Date.now() - WINDOW_MS
+    const budget = 1 - sloObjective()
+    const out: LatencyStats[] = []
+
+    for (const [key, w] of this.windows) {
+      if (filter?.instanceId && w.instanceId !== filter.instanceId) continue
+      if (filter?.method && w.method !== filter.method) continue
+
+      w.samples = w.samples.filter((s) => s.at >= cutoff)
+      if (w.samples.length === 0) {
+        this.windows.delete(key)
+        continue
+      }
+
+      const threshold = sloThreshold(w.method)
+      const sorted = w.samples.map((s) => s.ms).sort((a, b) => a - b)
+      const errors = w.samples.filter((s) => !s.ok).length
+      const bad = w.samples.filter((s) => !s.ok || s.ms > threshold).length
+      const badRatio = bad / w.samples.length
+
+      out.push({
+        instanceId: w.instanceId,
+        method: w.method,
+        count: w.samples.length,
+        errors,
+        p50: percentile(sorted, 0.5),
+        p95: percentile(sorted, 0.95),
+        p99: percentile(sorted, 0.99),
+        sloThresholdMs: threshold,
+        badRatio,
+        burnRate: budget > 0 ? badRatio / budget : 0,
+      })
+    }
+    return out.sort((a, b) => a.instanceId.localeCompare(b.instanceId) || a.method.localeCompare(b.method))
+  }
+}
+
+/** Prometheus text exposition of the current windows */
+export function latencyToPrometheus(stats: LatencyStats[], instanceNames: Map<string, string>): string {
+  const lines = [
+    '# HELP teamclaw_gateway_request_latency_ms Gateway request latency over the rolling window',
+    '# TYPE teamclaw_gateway_request_latency_ms summary',
+  ]
+  const label = (s: LatencyStats) =>
+    `instance_id="${s.instanceId}",instance="${(instanceNames.get(s.instanceId) ?? '').replace(/["\\\n]/g, '_')}",method="${s.method}"`
+
+  for (const s of stats) {
+    lines.push(`teamclaw_gateway_request_latency_ms{${label(s)},quantile="0.5"} ${s.p50}`)
+    lines.push(`teamclaw_gateway_request_latency_ms{${label(s)},quantile="0.95"} ${s.p95}`)
+    lines.push(`teamclaw_gateway_request_latency_ms{${label(s)},quantile="0.99"} ${s.p99}`)
+    lines.push(`teamclaw_gateway_request_latency_ms_count{${label(s)}} ${s.count}`)
+  }
+  lines.push('# HELP teamclaw_gateway_request_errors Failed gateway requests in the rolling window')
+  lines.push('# TYPE teamclaw_gateway_request_errors gauge')
+  for (const s of stats) lines.push(`teamclaw_gateway_request_errors{${label(s)}} ${s.errors}`)
+  lines.push('# HELP teamclaw_gateway_slo_burn_rate Error budget burn rate (1 = exactly on budget)')
+  lines.push('# TYPE teamclaw_gateway_slo_burn_rate gauge')
+  for (const s of stats) lines.push(`teamclaw_gateway_slo_burn_rate{${label(s)}} ${s.burnRate.toFixed(4)}`)
+  return lines.join('\n') + '\n'
+}
@@ -1,15 +1,23 @@
-import { GatewayClient } from './client'
+import { GatewayClient, type ConnectionStatus, type GatewayConnection } from './client'
+import { FakeGatewayClient } from './fake'
 import { type GatewayAdapter, resolveAdapter } from './adapter'
 import { prisma } from '@/lib/db'
 import { decrypt } from '@/lib/auth/encryption'
+import { createLogger } from '@/lib/logger'
+import { transitionInstanceStatus } from '@/lib/instances/status'
+import { LatencyTracker } from './latency'
+import { attachEventLog, recordConnectionStatus } from './event-log'
 import type { ConfigGetResult, ConfigSchemaResult } from '@/types/gateway'
 
-type ConnectionStatus = 'connecting' | 'connected' | 'disconnected' | 'error'
+const log = createLogger('gateway:registry')
+
+type ClientFactory = (url: string, token: string) => GatewayConnection
 
 interface ManagedInstance {
-  client: GatewayClient
+  client: GatewayConnection
   instanceId: string
   status: ConnectionStatus
+  stopEventLog: () => void
 }
 
 const globalForRegistry = globalThis as unknown as {
@@ -17,29 +25,55 @@ const globalForRegistry = globalThis as unknown as {
   registryInitialized?: boolean
 }
 
+// GATEWAY_FAKE=true answers every instance from an in-memory fake, for
+// developing against the UI without a running OpenClaw. Ignored in production.
+function defaultClientFactory(url: string, token: string): GatewayConnection {
+  if (process.env.GATEWAY_FAKE === 'true' && process.env.NODE_ENV !== 'production') {
+    return new FakeGatewayClient()
+  }
+  return new GatewayClient(url, token)
+}
+
 export class GatewayRegistry {
   private instances = new Map<string, ManagedInstance>()
+  private createClient: ClientFactory = defaultClientFactory
+  /** Rolling request latency per instance/method, for SLO metrics and alerts */
+  readonly latency = new LatencyTracker()
+
+  /** Deleted instances; never reconnected by health recovery or lazy init */
+  private retired = new Set<string>()
 
   async connect(instanceId: string, url: string, token: string): Promise<void> {
+    if (this.retired.has(instanceId)) throw new Error('Instance has been deleted')
+
     // If already connected, disconnect first
     if (this.instances.has(instanceId)) {
       await this.disconnect(instanceId)
     }
 
-    const client = new GatewayClient(url, token)
-    const managed: ManagedInstance = { client, instanceId, status: 'connecting' }
+    const client = this.createClient(url, token)
+    const managed: ManagedInstance = {
+      client,
+      instanceId,
+      status: 'connecting',
+      stopEventLog: attachEventLog(instanceId, client),
+    }
 
     client.onStatusChange = (status) => {
+      if (status !== managed.status) recordConnectionStatus(instanceId, status)
       managed.status = status
     }
 
+    client.traceAttributes = { 'teamclaw.instance_id': instanceId }
+    client.onRequestComplete = (method, durationMs, ok) => {
+      this.latency.record(instanceId, method, durationMs, ok)
+    }
+
     client.onPermanentDisconnect = () => {
+      if (managed.status !== 'error') recordConnectionStatus(instanceId, 'error')
       managed.status = 'error'
-      // Update DB status to ERROR (fire-and-forget)
-      prisma.instance.update({
-        where: { id: instanceId },
-        data: { status: 'ERROR' },
-      }).catch(console.error)
+      // Mark ERROR (fire-and-forget)
+      transitionInstanceStatus(instanceId, 'connection_lost').catch(console.error)
     }
 
     this.instances.set(instanceId, managed)
@@ -49,12 +83,30 @@ export class GatewayRegistry {
   async disconnect(instanceId: string): Promise<void> {
     const managed = this.instances.get(instanceId)
     if (managed) {
+      managed.stopEventLog()
       managed.client.disconnect()
       this.instances.delete(instanceId)
     }
   }
 
-  getClient(instanceId: string): GatewayClient | undefined {
+  /** Disconnect a deleted instance for good and drop its latency history */
+  async retire(instanceId: string): Promise<void> {
+    this.retired.add(instanceId)
+    await this.disconnect(instanceId)
+    this.latency.forget(instanceId)
+  }
+
+  /** A client built like registered ones, but not tracked (credential pre-checks) */
+  createDetachedClient(url: string, token: string): GatewayConnection {
+    return this.createClient(url, token)
+  }
+
+  /** Swap how connections are built, e.g. to hand out FakeGatewayClients in tests */
+  setClientFactory(factory: ClientFactory | null): void {
+    this.createClient = factory ?? defaultClientFactory
+  }
+
+  getClient(instanceId: string): GatewayConnection | undefined {
     return this.instances.get(instanceId)?.client
   }
 
@@ -198,28 +250,18 @@ export async function ensureRegistryInitialized(): Promise<void> {
               setTimeout(() => reject(new Error('Init connect timed out')), 20_000),
             ),
           ])
-          // Connection succeeded — if instance was ERROR/OFFLINE, mark as DEGRADED
-          // so the health check cycle can promote it to ONLINE on next success.
-          if (inst.status === 'ERROR' || inst.status === 'OFFLINE') {
-            await prisma.instance.update({
-              where: { id: inst.id },
-              data: { status: 'DEGRADED' },
-            }).catch(console.error)
-          }
+          // Connection succeeded — ERROR/OFFLINE becomes DEGRADED so the
+          // health check cycle can promote it to ONLINE on next success.
+          await transitionInstanceStatus(inst.id, 'reconnected').catch(console.error)
         } catch (err) {
-          console.error(`Failed to restore connection for instance ${inst.id}:`, err)
-          // Only downgrade ONLINE/DEGRADED → ERROR; leave ERROR/OFFLINE as-is
-          if (inst.status === 'ONLINE' || inst.status === 'DEGRADED') {
-            await prisma.instance.update({
-              where: { id: inst.id },
-              data: { status: 'ERROR' },
-            }).catch(console.error)
-          }
+          log.error('Failed to restore connection', { instanceId: inst.id, err })
+          // Only downgrades ONLINE/DEGRADED → ERROR; ERROR/OFFLINE stay as-is
+          await transitionInstanceStatus(inst.id, 'connection_lost').catch(console.error)
         }
       })
     )
   } catch (err) {
-    console.error('Failed to initialize gateway registry:', err)
+    log.error('Failed to initialize gateway registry', { err })
   }
 
   // Start health checks + recovery in background (lazy import to avoid circular deps)
@@ -0,0 +1,74 @@
+import { prisma } from '@/lib/db'
+import { notifyInstanceDepartments } from '@/lib/notifications'
+import { createLogger } from '@/lib/logger'
+import { registry } from './registry'
+
+// Alerts when chat.send p95 on an instance exceeds its SLO threshold
+// (GATEWAY_SLO_THRESHOLDS_MS, default 5s). Sent to the notification
+// channels of every department with access to the instance, repeated
+// at most every REALERT_MS while the breach lasts, plus one recovery notice.
+
+const EVAL_INTERVAL_MS = 60_000
+const REALERT_MS = 30 * 60_000
+const MIN_SAMPLES = 20
+const ALERT_METHOD = 'chat.send'
+
+const log = createLogger('gateway:slo')
+
+const globalForSlo = globalThis as unknown as {
+  sloAlertTimer?: ReturnType<typeof setInterval> | null
+  sloAlerting?: Map<string, number>
+}
+
+const alerting = (globalForSlo.sloAlerting ??= new Map<string, number>())
+
+export async function evaluateSloAlerts(): Promise<void> {
+  const stats = registry.latency.stats({ method: ALERT_METHOD })
+  const breaching = new Map(
+    stats.filter((s) => s.count >= MIN_SAMPLES && s.p95 > s.sloThresholdMs).map((s) => [s.instanceId, s]),
+  )
+
+  const ids = [...new Set([...breaching.keys(), ...alerting.keys()])]
+  if (ids.length === 0) return
+  const instances = await prisma.instance.findMany({
+    where: { id: { in: ids } },
+    select: { id: true, name: true },
+  })
+  const names = new Map(instances.map((i) => [i.id, i.name]))
+
+  for (const [instanceId, s] of breaching) {
+    const last = alerting.get(instanceId)
+    if (last && Date.now() - last < REALERT_MS) continue
+    alerting.set(instanceId, Date.now())
+
+    const name = names.get(instanceId) ?? instanceId
+    log.warn('chat.send p95 over SLO', { instanceId, p95: s.p95, thresholdMs: s.sloThresholdMs })
+    await notifyInstanceDepartments(instanceId, {
+      title: `[SLO] ${name}: ${ALERT_METHOD} p95 ${s.p95}ms`,
+      text:
+        `${ALERT_METHOD} p95 latency on instance ${name} is ${s.p95}ms ` +
+        `(threshold ${s.sloThresholdMs}ms, ${s.count} requests, burn rate ${s.burnRate.toFixed(2)}).`,
+      source: `slo:${instanceId}:${ALERT_METHOD}`,
+    })
+  }
+
+  for (const instanceId of [...alerting.keys()]) {
+    if (breaching.has(instanceId)) continue
+    alerting.delete(instanceId)
+    const name = names.get(instanceId) ?? instanceId
+    log.info('chat.send p95 back within SLO', { instanceId })
+    await notifyInstanceDepartments(instanceId, {
+      title: `[SLO resolved] ${name}: ${ALERT_METHOD}`,
+      text: `${ALERT_METHOD} p95 latency on instance ${name} is back within its SLO.`,
+      source: `slo:${instanceId}:${ALERT_METHOD}`,
+    })
+  }
+}
+
+/** Start periodic evaluation (idempotent across hot reloads) */
+export function startSloAlerts(): void {
+  if (globalForSlo.sloAlertTimer) return
+  globalForSlo.sloAlertTimer = setInterval(() => {
+    evaluateSloAlerts().catch((err) => log.error('SLO evaluation failed', { err }))
+  }, EVAL_INTERVAL_MS)
+}
@@ -0,0 +1,64 @@
+// Gateway URL parsing. Typos (http://, a missing scheme, stray query strings)
+// would otherwise only surface later as opaque WebSocket dial errors, so
+// URLs are checked and put in one canonical form before they are stored:
+//
+//   - scheme ws:// or wss:// only (http(s):// is rejected with a hint)
+//   - ws:// without a port gets the OpenClaw default 18789; wss:// keeps the
+//     implicit 443 (TLS is normally terminated by a reverse proxy)
+//   - no credentials, query string or fragment
+//   - path made of plain segments, trailing slash removed
+//   - host lowercased (done by URL parsing)
+//
+// Shared by the zod schemas and the instance form, so no server imports.
+
+export const DEFAULT_GATEWAY_PORT = 18789
+
+const SEGMENT = /^[A-Za-z0-9._~-]+$/
+
+export type GatewayUrlResult = { ok: true; url: string } | { ok: false; error: string }
+
+export function normalizeGatewayUrl(raw: string): GatewayUrlResult {
+  const input = raw.trim()
+  if (!input) return { ok: false, error: 'Gateway URL 不能为空' }
+
+  const scheme = /^([a-z][a-z0-9+.-]*):\/\//i.exec(input)?.[1]?.toLowerCase()
+  if (!scheme) return { ok: false, error: 'Gateway URL 需以 ws:// 或 wss:// 开头' }
+  if (scheme === 'http' || scheme === 'https') {
+    return { ok: false, error: `Gateway 使用 WebSocket，请改用 ${scheme === 'https' ? 'wss' : 'ws'}://` }
+  }
+  if (scheme !== 'ws' && scheme !== 'wss') {
+    return { ok: false, error: 'Gateway URL 只支持 ws:// 或 wss://' }
+  }
+
+  let parsed: URL
+  try {
+    parsed = new URL(input)
+  } catch {
+    return { ok: false, error: '请输入有效的 URL' }
+  }
+
+  if (!parsed.hostname) return { ok: false, error: 'Gateway URL 缺少主机名' }
+  if (parsed.username || parsed.password) {
+    return { ok: false, error: 'Gateway URL 不能包含用户名或密码，请使用 Gateway Token' }
+  }
+  if (parsed.search || parsed.hash || input.includes('?') || input.includes('#')) {
+    return { ok: false, error: 'Gateway URL 不能包含查询参数或锚点' }
+  }
+
+  const segments = parsed.pathname.split('/').filter(Boolean)
+  if (segments.some((s) => !SEGMENT.test(s))) {
+    return { ok: false, error: 'Gateway URL 路径包含无效字符' }
+  }
+  if (/\/\//.test(parsed.pathname)) {
+    return { ok: false, error: 'Gateway URL 路径不能包含空段' }
+  }
+
+  // URL drops a port equal to the scheme default (80 / 443); keep it if typed
+  const authority = input.slice(scheme.length + 3).split('/')[0]
+  const typedPort = /:(\d+)$/.exec(authority.slice(authority.lastIndexOf(']') + 1))?.[1]
+  const port = parsed.port || typedPort || (scheme === 'ws' ? String(DEFAULT_GATEWAY_PORT) : '')
+  if (port === '0') return { ok: false, error: 'Gateway URL 端口无效' }
+  const path = segments.length > 0 ? `/${segments.join('/')}` : ''
+
+  return { ok: true, url: `${scheme}://${parsed.hostname}${port ? `:${port}` : ''}${path}` }
+}
@@ -0,0 +1,199 @@
+import { prisma } from '@/lib/db'
+import type { AuthUser } from '@/types/auth'
+import { Prisma } from '@/generated/prisma'
+import type { InstanceAccess, UserInstanceAccess } from '@/generated/prisma'
+
+// Instance scoping, shared by every handler that lets non-admins see or use
+// an instance. A department's grant (InstanceAccess) covers its members; a
+// user grant (UserInstanceAccess) covers one user on top of that, e.g. a
+// pilot user trying an instance before their department is given it.
+// Agent visibility (AgentMeta category) applies the same either way.
+//
+// Only instance scoping is centralised here. User and chat session scoping
+// is still inline in the handlers (see CONTRIBUTING.md, Data Access Scoping).
+
+/**
+ * Grants still in force (department or user grants). A time-boxed grant
+ * stops counting at expiresAt, even before the expiry job deletes it.
+ */
+export function activeGrantWhere() {
+  return { OR: [{ expiresAt: null }, { expiresAt: { gt: new Date() } }] }
+}
+
+/** IDs of the instances a department has been granted */
+export async function getDepartmentInstanceIds(departmentId: string): Promise<string[]> {
+  const access = await prisma.instanceAccess.findMany({
+    where: { departmentId, ...activeGrantWhere() },
+    select: { instanceId: true },
+  })
+  return access.map((a) => a.instanceId)
+}
+
+/** The department's grant on an instance, or null without one (or without a department) */
+export function findInstanceAccess(
+  departmentId: string | null,
+  instanceId: string,
+): Promise<InstanceAccess | null> {
+  if (!departmentId) return Promise.resolve(null)
+  return prisma.instanceAccess.findFirst({
+    where: { departmentId, instanceId, ...activeGrantWhere() },
+  })
+}
+
+/** A grant with no agent list covers every agent on the instance */
+export function grantAllowsAgent(access: Pick<InstanceAccess, 'agentIds'>, agentId: string): boolean {
+  const allowedIds = access.agentIds as string[] | null
+  return !allowedIds || allowedIds.includes(agentId)
+}
+
+type Grantee = Pick<AuthUser, 'id' | 'departmentId'>
+
+/**
+ * What the user may use on an instance through their department's grant and
+ * their own, combined: every agent if either grant covers all of them,
+ * otherwise both agent lists. Null without any grant.
+ */
+export async function findUserInstanceAccess(
+  user: Grantee,
+  instanceId: string,
+): Promise<Pick<InstanceAccess, 'agentIds'> | null> {
+  const [departmentGrant, userGrant] = await Promise.all([
+    findInstanceAccess(user.departmentId, instanceId),
+    prisma.userInstanceAccess.findFirst({ where: { userId: user.id, instanceId, ...activeGrantWhere() } }),
+  ])
+  const grants = [departmentGrant, userGrant].filter((g): g is InstanceAccess | UserInstanceAccess => !!g)
+  if (grants.length === 0) return null
+  const lists = grants.map((g) => g.agentIds as string[] | null)
+  if (lists.some((l) => !l)) return { agentIds: null }
+  return { agentIds: [...new Set(lists.flatMap((l) => l!))] }
+}
+
+/** IDs of the instances the user has been granted, through the department or directly */
+export async function getUserInstanceIds(user: Grantee): Promise<string[]> {
+  const [departmentIds, userGrants] = await Promise.all([
+    user.departmentId ? getDepartmentInstanceIds(user.departmentId) : Promise.resolve([]),
+    prisma.userInstanceAccess.findMany({
+      where: { userId: user.id, ...activeGrantWhere() },
+      select: { instanceId: true },
+    }),
+  ])
+  return [...new Set([...departmentIds, ...userGrants.map((g) => g.instanceId)])]
+}
+
+/** SYSTEM_ADMIN always; anyone else through their department's grant or their own */
+export async function canAccessInstance(user: AuthUser, instanceId: string): Promise<boolean> {
+  if (user.role === 'SYSTEM_ADMIN') return true
+  return !!(await findUserInstanceAccess(user, instanceId))
+}
+
+export interface BulkGrant {
+  instanceId: string
+  /** null = all agents; omitted keeps an existing grant's scoping */
+  agentIds?: string[] | null
+  /** ISO timestamp; null = permanent; omitted keeps an existing grant's expiry */
+  expiresAt?: string | null
+}
+
+/** Prisma value for an optional expiry: undefined leaves the column alone */
+export function expiryValue(expiresAt: string | null | undefined): Date | null | undefined {
+  if (expiresAt === undefined) return undefined
+  return expiresAt ? new Date(expiresAt) : null
+}
+
+/**
+ * Grant a department several instances in one transaction: either every
+ * grant is upserted or none is. Returns the resulting grant ids.
+ */
+export async function bulkGrantInstanceAccess(
+  departmentId: string,
+  grants: BulkGrant[],
+  grantedById: string,
+): Promise<string[]> {
+  const rows = await prisma.$transaction(
+    grants.map(({ instanceId, agentIds, expiresAt }) =>
+      prisma.instanceAccess.upsert({
+        where: { departmentId_instanceId: { departmentId, instanceId } },
+        update: {
+          agentIds: agentIds !== undefined
+            ? (agentIds as unknown as Prisma.InputJsonValue ?? Prisma.DbNull)
+            : undefined,
+          expiresAt: expiryValue(expiresAt),
+          expiryNotifiedAt: expiresAt !== undefined ? null : undefined,
+          grantedById,
+        },
+        create: {
+          departmentId,
+          instanceId,
+          agentIds: agentIds != null ? (agentIds as unknown as Prisma.InputJsonValue) : undefined,
+          expiresAt: expiryValue(expiresAt),
+          grantedById,
+        },
+        select: { id: true },
+      }),
+    ),
+  )
+  return rows.map((r) => r.id)
+}
+
+/** Revoke a department's grants on the given instances; returns how many existed */
+export async function bulkRevokeInstanceAccess(departmentId: string, instanceIds: string[]): Promise<number> {
+  const { count } = await prisma.instanceAccess.deleteMany({
+    where: { departmentId, instanceId: { in: instanceIds } },
+  })
+  return count
+}
+
+export const USER_GRANT_INCLUDE = {
+  user: { select: { name: true, email: true } },
+  instance: { select: { name: true, status: true } },
+  grantedBy: { select: { name: true } },
+} as const
+
+/** Grant a user an instance, or update their existing grant on it */
+export function grantUserInstanceAccess(
+  userId: string,
+  { instanceId, agentIds, expiresAt }: BulkGrant,
+  grantedById: string,
+) {
+  return prisma.userInstanceAccess.upsert({
+    where: { userId_instanceId: { userId, instanceId } },
+    update: {
+      agentIds: agentIds !== undefined
+        ? (agentIds as unknown as Prisma.InputJsonValue ?? Prisma.DbNull)
+        : undefined,
+      expiresAt: expiryValue(expiresAt),
+      grantedById,
+    },
+    create: {
+      userId,
+      instanceId,
+      agentIds: agentIds != null ? (agentIds as unknown as Prisma.InputJsonValue) : undefined,
+      expiresAt: expiryValue(expiresAt),
+      grantedById,
+    },
+    include: USER_GRANT_INCLUDE,
+  })
+}
+
+export function toUserGrantResponse(
+  g: UserInstanceAccess & {
+    user: { name: string; email: string }
+    instance: { name: string; status: string }
+    grantedBy: { name: string }
+  },
+) {
+  return {
+    id: g.id,
+    userId: g.userId,
+    userName: g.user.name,
+    userEmail: g.user.email,
+    instanceId: g.instanceId,
+    instanceName: g.instance.name,
+    instanceStatus: g.instance.status,
+    agentIds: g.agentIds as string[] | null,
+    expiresAt: g.expiresAt?.toISOString() ?? null,
+    grantedByName: g.grantedBy.name,
+    createdAt: g.createdAt.toISOString(),
+    updatedAt: g.updatedAt.toISOString(),
+  }
+}
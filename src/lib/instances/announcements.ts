@@ -0,0 +1,59 @@
+import { EventEmitter } from 'events'
+import { prisma } from '@/lib/db'
+import type { InstanceAnnouncement } from '@/generated/prisma'
+import type { ChatAnnouncement } from '@/types/chat'
+
+// Instance announcements: an admin's notice to everyone chatting with an
+// instance's agents, typically ahead of maintenance. Chat streams open when
+// one is made get it as a `system` event right away (in-process, like
+// ./events); everyone else sees it in chat until it expires
+// (GET /api/v1/chat/announcements).
+
+export function toChatAnnouncement(
+  a: InstanceAnnouncement & { createdBy?: { name: string } | null },
+): ChatAnnouncement {
+  return {
+    id: a.id,
+    instanceId: a.instanceId,
+    message: a.message,
+    level: a.level,
+    expiresAt: a.expiresAt.toISOString(),
+    createdAt: a.createdAt.toISOString(),
+    createdByName: a.createdBy?.name ?? null,
+  }
+}
+
+/** Announcements of an instance that have not expired, newest first */
+export async function activeAnnouncements(instanceId: string) {
+  return prisma.instanceAnnouncement.findMany({
+    where: { instanceId, expiresAt: { gt: new Date() } },
+    include: { createdBy: { select: { name: true } } },
+    orderBy: { createdAt: 'desc' },
+  })
+}
+
+const globalForAnnouncements = globalThis as unknown as {
+  announcementEmitter?: EventEmitter
+}
+
+const emitter = (globalForAnnouncements.announcementEmitter ??= new EventEmitter().setMaxListeners(0))
+
+export function onAnnouncement(
+  instanceId: string,
+  listener: (announcement: ChatAnnouncement) => void,
+): () => void {
+  const event = `announce:${instanceId}`
+  emitter.on(event, listener)
+  return () => emitter.off(event, listener)
+}
+
+export function emitAnnouncement(announcement: ChatAnnouncement): void {
+  const event = `announce:${announcement.instanceId}`
+  for (const listener of emitter.listeners(event) as ((a: ChatAnnouncement) => void)[]) {
+    try {
+      listener(announcement)
+    } catch (err) {
+      console.error('[instances:announcements] listener failed:', err)
+    }
+  }
+}
@@ -0,0 +1,166 @@
+import { createHash } from 'crypto'
+import path from 'path'
+import { prisma } from '@/lib/db'
+import { dockerManager } from '@/lib/docker'
+import { decrypt } from '@/lib/auth/encryption'
+import { createLogger } from '@/lib/logger'
+import { DEFAULT_GATEWAY_PORT } from '@/lib/gateway/url'
+import { resolveDockerConfig, type ResolvedDockerConfig } from '@/lib/docker/environments'
+import {
+  DOCKER_SOCKET_BIND,
+  SANDBOX_PROFILE_LABEL,
+  getSandboxProfile,
+  type SandboxProfile,
+} from '@/lib/docker/sandbox-profiles'
+import type { ContainerCreateOptions } from '@/lib/docker'
+import type { DockerConfig } from '@/types/instance'
+
+// Start parameters of managed instance containers. Create and recreate both
+// build their options here, from the instance's DockerConfig resolved for
+// the current deployment environment. The container is labelled with a
+// fingerprint of those parameters; start / restart compare it and recreate
+// the container when the template, an environment override or the sandbox
+// profile changed since it was created.
+
+const log = createLogger('instances:container')
+
+/** Docker label with the fingerprint of the parameters a container was created with */
+export const START_CONFIG_LABEL = 'teamclaw.start-config'
+
+/** Container path of the instance data directory (openclaw.json, agents, skills) */
+const DATA_MOUNT = '/home/node/.openclaw'
+
+/** Why a profile cannot be used in this deployment, or null */
+export function sandboxProfileError(profile: SandboxProfile): string | null {
+  // Internal networks publish no ports, so TeamClaw has to share the network
+  if (profile.network === 'internal' && !process.env.DOCKER_NETWORK) {
+    return `Sandbox profile "${profile.id}" requires TeamClaw to run in Docker (DOCKER_NETWORK)`
+  }
+  return null
+}
+
+function startConfigFingerprint(resolved: ResolvedDockerConfig): string {
+  const { imageName: _image, ...params } = resolved
+  return createHash('sha256').update(JSON.stringify(params)).digest('hex').slice(0, 16)
+}
+
+/** Docker create options for an instance container */
+export function buildContainerOptions(params: {
+  containerName: string
+  imageName: string
+  dataDir: string
+  hostPort: number
+  gatewayToken: string
+  dockerConfig: unknown
+}): ContainerCreateOptions {
+  const resolved = resolveDockerConfig(params.dockerConfig)
+  const workspaceHostPath = path.join(params.dataDir, 'workspace')
+
+  return {
+    name: params.containerName,
+    imageName: params.imageName,
+    volumes: {
+      ...resolved.volumes,
+      [params.dataDir]: DATA_MOUNT,
+      [workspaceHostPath]: '/workspace',
+    },
+    // Extra binds for sandbox support (Docker-in-Docker):
+    // 1. Mount workspace at its host path so OpenClaw sandbox can bind-mount
+    //    workspace into sandbox containers using host-resolvable paths.
+    // 2. Mount Docker socket for sandbox container management
+    //    (dropped by profiles other than standard).
+    extraBinds: [
+      `${workspaceHostPath}:${workspaceHostPath}`,
+      DOCKER_SOCKET_BIND,
+    ],
+    portBindings: {
+      ...resolved.portBindings,
+      [`${DEFAULT_GATEWAY_PORT}`]: String(params.hostPort),
+    },
+    env: {
+      OPENCLAW_GATEWAY_TOKEN: params.gatewayToken,
+      ...resolved.env,
+    },
+    restartPolicy: resolved.restartPolicy || 'unless-stopped',
+    memoryLimit: resolved.memoryLimit,
+    sandbox: getSandboxProfile(resolved.sandboxProfile),
+    labels: { [START_CONFIG_LABEL]: startConfigFingerprint(resolved) },
+  }
+}
+
+/**
+ * One-time setup of a freshly created (and started) container: Python
+ * tooling where the root filesystem is writable, and the Docker CLI for
+ * OpenClaw's sandbox mode where the profile mounts the Docker socket.
+ * Both are best-effort; the instance works without them.
+ */
+export async function initializeContainer(containerId: string, profile: SandboxProfile, name: string): Promise<void> {
+  if (!profile.readOnlyRootfs) {
+    await dockerManager.initContainerEnv(containerId).catch(() => {})
+  }
+  if (!profile.dockerSocket) return
+
+  // Needs a running container; group changes take effect after a restart
+  try {
+    await dockerManager.initSandboxSupport(containerId)
+    await dockerManager.restartContainer(containerId)
+  } catch (err) {
+    log.warn('Sandbox init failed', { instance: name, err: (err as Error).message })
+  }
+}
+
+function isUpToDate(labels: Record<string, string>, dockerConfig: unknown, resolved: ResolvedDockerConfig): boolean {
+  const applied = labels[START_CONFIG_LABEL]
+  if (applied) return applied === startConfigFingerprint(resolved)
+  // Created before start parameters were tracked: rebuild only for a new
+  // sandbox profile or an environment override
+  const overrides = (dockerConfig as DockerConfig | null)?.overrides ?? {}
+  return (
+    (labels[SANDBOX_PROFILE_LABEL] ?? 'standard') === getSandboxProfile(resolved.sandboxProfile).id &&
+    Object.keys(overrides).length === 0
+  )
+}
+
+/**
+ * Make sure a managed instance's container runs with its current start
+ * parameters, recreating it when it was created with others.
+ * The returned container is not started.
+ */
+export async function applyContainerConfig(instance: {
+  id: string
+  name: string
+  containerId: string
+  gatewayToken: string // encrypted, as stored
+  dockerConfig: unknown
+}): Promise<{ containerId: string; profile: SandboxProfile; recreated: boolean }> {
+  const resolved = resolveDockerConfig(instance.dockerConfig)
+  const profile = getSandboxProfile(resolved.sandboxProfile)
+  const info = await dockerManager.inspectContainer(instance.containerId)
+  if (isUpToDate(info.labels, instance.dockerConfig, resolved)) {
+    return { containerId: instance.containerId, profile, recreated: false }
+  }
+
+  const error = sandboxProfileError(profile)
+  if (error) throw new Error(error)
+
+  const dataDir = info.binds.map((b) => b.split(':')).find(([, target]) => target === DATA_MOUNT)?.[0]
+  const hostPort = (instance.dockerConfig as { hostPort?: unknown } | null)?.hostPort
+  if (!dataDir || typeof hostPort !== 'number') {
+    throw new Error('Container was not created by TeamClaw; cannot recreate it')
+  }
+
+  log.info('Recreating container with new start parameters', { instance: instance.name, sandboxProfile: profile.id })
+  const containerId = await dockerManager.recreateContainer(
+    instance.containerId,
+    buildContainerOptions({
+      containerName: info.name,
+      imageName: info.imageName,
+      dataDir,
+      hostPort,
+      gatewayToken: decrypt(instance.gatewayToken),
+      dockerConfig: instance.dockerConfig,
+    }),
+  )
+  await prisma.instance.update({ where: { id: instance.id }, data: { containerId } })
+  return { containerId, profile, recreated: true }
+}
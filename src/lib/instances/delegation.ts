@@ -0,0 +1,48 @@
+import { prisma } from '@/lib/db'
+import { stringList } from '@/lib/db-dialect'
+import type { AuthUser } from '@/types/auth'
+import type { ContainerAction, Prisma } from '@/generated/prisma'
+
+export const delegationInclude = {
+  department: { select: { name: true } },
+  instance: { select: { name: true } },
+  grantedBy: { select: { name: true } },
+} as const
+
+type DelegationWithRelations = Prisma.InstanceDelegationGetPayload<{
+  include: typeof delegationInclude
+}>
+
+export function toDelegationResponse(d: DelegationWithRelations) {
+  return {
+    id: d.id,
+    departmentId: d.departmentId,
+    departmentName: d.department.name,
+    instanceId: d.instanceId,
+    instanceName: d.instance.name,
+    actions: stringList<ContainerAction>(d.actions),
+    grantedByName: d.grantedBy.name,
+    createdAt: d.createdAt.toISOString(),
+    updatedAt: d.updatedAt.toISOString(),
+  }
+}
+
+/**
+ * Check if a user may perform a container action on an instance.
+ * SYSTEM_ADMIN always can; DEPT_ADMIN only when SYSTEM_ADMIN has delegated
+ * the action on this instance to their department.
+ */
+export async function canControlInstance(
+  user: AuthUser,
+  instanceId: string,
+  action: ContainerAction,
+): Promise<boolean> {
+  if (user.role === 'SYSTEM_ADMIN') return true
+  if (user.role !== 'DEPT_ADMIN' || !user.departmentId) return false
+
+  const delegation = await prisma.instanceDelegation.findUnique({
+    where: { departmentId_instanceId: { departmentId: user.departmentId, instanceId } },
+    select: { actions: true },
+  })
+  return !!delegation && stringList(delegation.actions).includes(action)
+}
@@ -0,0 +1,38 @@
+import { EventEmitter } from 'events'
+
+// Instance lifecycle events for in-process state keyed by instance ID
+// (gateway clients, latency windows, health counters). Modules that keep
+// such state subscribe when they load; the emitting side lives in
+// lifecycle.ts. Listeners run synchronously and must not throw.
+
+export interface InstanceDeletedEvent {
+  instanceId: string
+  name: string
+  containerId: string | null
+  /** OpenClaw sessions closed on the gateway before it was disconnected */
+  sessionsClosed: number
+  /** Dependent rows removed with the instance, by model */
+  removed: Record<string, number>
+  at: Date
+}
+
+const globalForInstanceEvents = globalThis as unknown as {
+  instanceEventEmitter?: EventEmitter
+}
+
+const emitter = (globalForInstanceEvents.instanceEventEmitter ??= new EventEmitter())
+
+export function onInstanceDeleted(listener: (event: InstanceDeletedEvent) => void): () => void {
+  emitter.on('deleted', listener)
+  return () => emitter.off('deleted', listener)
+}
+
+export function emitInstanceDeleted(event: InstanceDeletedEvent): void {
+  for (const listener of emitter.listeners('deleted') as ((e: InstanceDeletedEvent) => void)[]) {
+    try {
+      listener(event)
+    } catch (err) {
+      console.error('[instances:events] InstanceDeleted listener failed:', err)
+    }
+  }
+}
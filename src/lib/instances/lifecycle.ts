@@ -0,0 +1,107 @@
+import { prisma } from '@/lib/db'
+import { registry } from '@/lib/gateway/registry'
+import { dockerManager } from '@/lib/docker'
+import { cleanupInstanceFiles } from '@/lib/docker/config-generator'
+import { createLogger } from '@/lib/logger'
+import { emitInstanceDeleted, type InstanceDeletedEvent } from './events'
+
+const log = createLogger('instances:lifecycle')
+
+/**
+ * Tear down an instance:
+ *  1. close its OpenClaw sessions on the gateway (external gateways outlive
+ *     the instance row and would otherwise keep them)
+ *  2. retire the gateway client, so health recovery cannot reconnect it
+ *     while the rest is removed, and remove the managed container
+ *  3. delete dependent rows and the instance in one transaction
+ *  4. delete host files and emit InstanceDeleted for in-process state
+ */
+export async function destroyInstance(instance: {
+  id: string
+  name: string
+  containerId: string | null
+}): Promise<InstanceDeletedEvent> {
+  const sessionsClosed = await closeGatewaySessions(instance.id)
+
+  await registry.retire(instance.id)
+
+  // Stop and remove container if managed
+  if (instance.containerId) {
+    try {
+      await dockerManager.stopContainer(instance.containerId)
+    } catch {
+      // Container may already be stopped
+    }
+    try {
+      await dockerManager.removeContainer(instance.containerId, true)
+    } catch {
+      // Container may not exist
+    }
+  }
+
+  // Foreign keys cascade too; deleting explicitly keeps the counts and also
+  // covers rows that only carry the instance ID
+  const where = { instanceId: instance.id }
+  const removed = await prisma.$transaction(async (tx) => {
+    const counts = {
+      toolInvocations: (await tx.toolInvocation.deleteMany({ where })).count,
+      chatSessions: (await tx.chatSession.deleteMany({ where })).count,
+      agentMetas: (await tx.agentMeta.deleteMany({ where })).count,
+      skillInstallations: (await tx.skillInstallation.deleteMany({ where })).count,
+      instanceAccesses: (await tx.instanceAccess.deleteMany({ where })).count,
+      delegations: (await tx.instanceDelegation.deleteMany({ where })).count,
+      integrationEndpoints: (await tx.integrationEndpoint.deleteMany({ where })).count,
+      chatWidgets: (await tx.chatWidget.deleteMany({ where })).count,
+      syntheticProbes: (await tx.syntheticProbe.deleteMany({ where })).count,
+      gatewayEvents: (await tx.gatewayEvent.deleteMany({ where })).count,
+      departmentDefaults: (
+        await tx.department.updateMany({
+          where: { defaultInstanceId: instance.id },
+          data: { defaultInstanceId: null, defaultAgentId: null },
+        })
+      ).count,
+    }
+    await tx.instance.delete({ where: { id: instance.id } })
+    return counts
+  })
+
+  // Clean up host data directory
+  try {
+    await cleanupInstanceFiles(instance.name)
+  } catch {
+    // Non-fatal: log but don't fail the delete
+  }
+
+  const event: InstanceDeletedEvent = {
+    instanceId: instance.id,
+    name: instance.name,
+    containerId: instance.containerId,
+    sessionsClosed,
+    removed,
+    at: new Date(),
+  }
+  log.info('Instance deleted', { instanceId: instance.id, name: instance.name, sessionsClosed, ...removed })
+  emitInstanceDeleted(event)
+  return event
+}
+
+/** Best-effort sessions.delete for every active session; returns how many succeeded */
+async function closeGatewaySessions(instanceId: string): Promise<number> {
+  const client = registry.getClient(instanceId)
+  if (!client) return 0
+
+  const sessions = await prisma.chatSession.findMany({
+    where: { instanceId, isActive: true },
+    select: { sessionId: true },
+  })
+  let closed = 0
+  for (const s of sessions) {
+    try {
+      await client.request('sessions.delete', { key: s.sessionId }, 10_000)
+      closed++
+    } catch (err) {
+      log.warn('Failed to close gateway session', { instanceId, sessionKey: s.sessionId, err: (err as Error).message })
+    }
+  }
+  return closed
+}
@@ -0,0 +1,74 @@
+import { registry, ensureRegistryInitialized } from '@/lib/gateway/registry'
+import { dockerManager } from '@/lib/docker'
+import { registerCache } from '@/lib/caches'
+import type { InstanceContainerState, InstanceLiveInfo } from '@/types/instance'
+
+// Live runtime state for instance listings (GET /api/v1/instances?include=live):
+// the registry's connection status and server version, and the container's
+// state. Container inspections are cached for INSTANCE_CONTAINER_CACHE_MS
+// (default 15 s), so a list that refreshes every few seconds does not hit
+// the Docker API each time.
+
+const DEFAULT_CACHE_MS = 15_000
+const INSPECT_CONCURRENCY = 10
+
+const globalForLive = globalThis as unknown as {
+  containerStateCache?: Map<string, { at: number; state: InstanceContainerState }>
+}
+
+const cache = (globalForLive.containerStateCache ??= new Map())
+
+const counter = registerCache('container-state', {
+  description: 'Container state for instance listings (INSTANCE_CONTAINER_CACHE_MS)',
+  keys: () => [...cache.keys()],
+  clear: (key) => {
+    if (key) cache.delete(key)
+    else cache.clear()
+  },
+})
+
+function cacheMs(): number {
+  const n = parseInt(process.env.INSTANCE_CONTAINER_CACHE_MS ?? '', 10)
+  return Number.isFinite(n) && n >= 0 ? n : DEFAULT_CACHE_MS
+}
+
+async function containerState(containerId: string): Promise<InstanceContainerState> {
+  const cached = cache.get(containerId)
+  if (cached && Date.now() - cached.at < cacheMs()) {
+    counter.hit()
+    return cached.state
+  }
+  counter.miss()
+
+  let state: InstanceContainerState
+  try {
+    const info = await dockerManager.inspectContainer(containerId)
+    state = { state: info.state, status: info.status, error: null, checkedAt: new Date().toISOString() }
+  } catch (err) {
+    state = { state: 'unknown', status: null, error: (err as Error).message, checkedAt: new Date().toISOString() }
+  }
+  cache.set(containerId, { at: Date.now(), state })
+  return state
+}
+
+/** Live info per instance id */
+export async function getInstancesLiveInfo(
+  instances: { id: string; containerId: string | null }[],
+): Promise<Map<string, InstanceLiveInfo>> {
+  await ensureRegistryInitialized()
+
+  const result = new Map<string, InstanceLiveInfo>()
+  for (let i = 0; i < instances.length; i += INSPECT_CONCURRENCY) {
+    const batch = instances.slice(i, i + INSPECT_CONCURRENCY)
+    await Promise.all(
+      batch.map(async (inst) => {
+        result.set(inst.id, {
+          connection: registry.getStatus(inst.id) ?? 'disconnected',
+          serverVersion: registry.getServerVersion(inst.id),
+          container: inst.containerId ? await containerState(inst.containerId) : null,
+        })
+      }),
+    )
+  }
+  return result
+}
@@ -0,0 +1,191 @@
+import { randomUUID } from 'crypto'
+import { registry, ensureRegistryInitialized } from '@/lib/gateway/registry'
+import { percentile } from '@/lib/gateway/latency'
+import { runAgentToCompletion } from '@/lib/chat/run'
+import { createLogger } from '@/lib/logger'
+import type { LoadTestConfig, LoadTestJob, LoadTestStats } from '@/types/instance'
+
+// Replays synthetic conversations against an instance to size it before
+// onboarding: `concurrency` workers pull conversations until `conversations`
+// have run, each sending `turnsPerConversation` prompts in its own session.
+// Jobs live in memory (one per instance, the last one kept for reporting);
+// sessions are deleted afterwards and never recorded as ChatSessions.
+
+const MAX_ERROR_KINDS = 10
+
+const log = createLogger('load-test')
+
+interface JobState {
+  job: LoadTestJob
+  latencies: number[]
+  cancelled: boolean
+}
+
+const globalForLoadTest = globalThis as unknown as {
+  loadTestJobs?: Map<string, JobState>
+}
+
+const jobs = (globalForLoadTest.loadTestJobs ??= new Map<string, JobState>())
+
+function emptyStats(): LoadTestStats {
+  return {
+    conversationsCompleted: 0,
+    conversationsFailed: 0,
+    turnsOk: 0,
+    turnsFailed: 0,
+    throughput: 0,
+    latencyMs: { avg: 0, p50: 0, p95: 0, p99: 0, max: 0 },
+    errors: {},
+  }
+}
+
+/** Refresh derived stats (latency percentiles, throughput, duration) */
+function snapshot(state: JobState): LoadTestJob {
+  const { job, latencies } = state
+  const end = job.finishedAt ? new Date(job.finishedAt).getTime() : Date.now()
+  job.durationMs = end - new Date(job.startedAt).getTime()
+
+  const sorted = [...latencies].sort((a, b) => a - b)
+  job.stats.latencyMs = {
+    avg: sorted.length ? Math.round(sorted.reduce((a, b) => a + b, 0) / sorted.length) : 0,
+    p50: percentile(sorted, 0.5),
+    p95: percentile(sorted, 0.95),
+    p99: percentile(sorted, 0.99),
+    max: sorted[sorted.length - 1] ?? 0,
+  }
+  job.stats.throughput = job.durationMs > 0 ? +(job.stats.turnsOk / (job.durationMs / 1000)).toFixed(2) : 0
+  return job
+}
+
+function recordError(stats: LoadTestStats, message: string): void {
+  if (message in stats.errors || Object.keys(stats.errors).length < MAX_ERROR_KINDS) {
+    stats.errors[message] = (stats.errors[message] ?? 0) + 1
+  } else {
+    stats.errors.other = (stats.errors.other ?? 0) + 1
+  }
+}
+
+async function runConversation(state: JobState, n: number): Promise<void> {
+  const { job } = state
+  const { agentId, turnsPerConversation, prompt, timeoutMs } = job.config
+  const client = registry.getClient(job.instanceId)
+  const adapter = registry.getAdapter(job.instanceId)
+  if (!client || !adapter || !client.isConnected()) throw new Error('Instance not connected')
+
+  const sessionKey = `agent:${agentId}:tc:loadtest:${job.id}:${n}`
+  let failed = false
+  try {
+    for (let t = 0; t < turnsPerConversation && !state.cancelled; t++) {
+      const start = Date.now()
+      try {
+        await runAgentToCompletion(client, adapter, sessionKey, prompt, { timeoutMs })
+        state.latencies.push(Date.now() - start)
+        job.stats.turnsOk++
+      } catch (err) {
+        job.stats.turnsFailed++
+        recordError(job.stats, (err as Error).message)
+        failed = true
+        // Session state after a failed turn is unknown; end the conversation
+        break
+      }
+    }
+  } finally {
+    await adapter.deleteSession(client, sessionKey).catch(() => {})
+  }
+
+  if (failed) job.stats.conversationsFailed++
+  else if (!state.cancelled) job.stats.conversationsCompleted++
+}
+
+async function runJob(state: JobState): Promise<void> {
+  const { job } = state
+  let next = 0
+
+  const worker = async () => {
+    while (!state.cancelled && next < job.config.conversations) {
+      const n = next++
+      try {
+        await runConversation(state, n)
+      } catch (err) {
+        job.stats.conversationsFailed++
+        recordError(job.stats, (err as Error).message)
+      }
+    }
+  }
+
+  try {
+    await Promise.all(Array.from({ length: job.config.concurrency }, worker))
+    job.status = state.cancelled ? 'CANCELLED' : 'COMPLETED'
+  } catch (err) {
+    job.status = 'FAILED'
+    job.error = (err as Error).message
+  } finally {
+    job.finishedAt = new Date().toISOString()
+    snapshot(state)
+    log.info('Load test finished', {
+      jobId: job.id,
+      instanceId: job.instanceId,
+      status: job.status,
+      turnsOk: job.stats.turnsOk,
+      turnsFailed: job.stats.turnsFailed,
+      p95: job.stats.latencyMs.p95,
+    })
+  }
+}
+
+/**
+ * Start a load test in the background. Returns null if one is already
+ * running on the instance; throws if the instance isn't connected.
+ */
+export async function startLoadTest(
+  instanceId: string,
+  config: LoadTestConfig,
+  startedBy: string,
+): Promise<LoadTestJob | null> {
+  if (jobs.get(instanceId)?.job.status === 'RUNNING') return null
+
+  await ensureRegistryInitialized()
+  if (!registry.isConnected(instanceId)) throw new Error('Instance not connected')
+
+  const state: JobState = {
+    job: {
+      id: randomUUID(),
+      instanceId,
+      status: 'RUNNING',
+      config,
+      stats: emptyStats(),
+      startedBy,
+      startedAt: new Date().toISOString(),
+      finishedAt: null,
+      durationMs: 0,
+      error: null,
+    },
+    latencies: [],
+    cancelled: false,
+  }
+  jobs.set(instanceId, state)
+  log.info('Load test started', {
+    jobId: state.job.id,
+    instanceId,
+    agentId: config.agentId,
+    conversations: config.conversations,
+    concurrency: config.concurrency,
+  })
+
+  runJob(state).catch((err) => log.error('Load test crashed', { jobId: state.job.id, err }))
+  return snapshot(state)
+}
+
+/** Current or most recent load test on an instance, with live stats */
+export function getLoadTest(instanceId: string): LoadTestJob | null {
+  const state = jobs.get(instanceId)
+  return state ? snapshot(state) : null
+}
+
+/** Stop a running load test; in-flight turns finish, no new ones start */
+export function cancelLoadTest(instanceId: string): LoadTestJob | null {
+  const state = jobs.get(instanceId)
+  if (!state || state.job.status !== 'RUNNING') return null
+  state.cancelled = true
+  return snapshot(state)
+}
@@ -0,0 +1,78 @@
+import { NextResponse } from 'next/server'
+import { prisma } from '@/lib/db'
+
+// Data residency. Instances carry a region label (Instance.region) and
+// departments may list the regions their data is allowed to reach
+// (Department.allowedRegions, null = unrestricted). A restricted department
+// cannot be granted an instance outside its regions, and its members cannot
+// chat with one — including through grants made before the restriction. An
+// untagged instance counts as outside every region.
+
+export type ResidencyErrorCode = 'RESIDENCY_REGION_NOT_ALLOWED' | 'RESIDENCY_REGION_UNKNOWN'
+
+export interface ResidencyViolation {
+  code: ResidencyErrorCode
+  departmentId: string
+  instanceId: string
+  instanceRegion: string | null
+  allowedRegions: string[]
+}
+
+export class ResidencyError extends Error {
+  constructor(public readonly violation: ResidencyViolation) {
+    super(
+      violation.code === 'RESIDENCY_REGION_UNKNOWN'
+        ? 'Instance has no residency region; this department only allows tagged regions'
+        : `Instance region "${violation.instanceRegion}" is not allowed for this department`,
+    )
+    this.name = 'ResidencyError'
+  }
+}
+
+export function parseAllowedRegions(value: unknown): string[] | null {
+  return Array.isArray(value) ? value.filter((r): r is string => typeof r === 'string') : null
+}
+
+/** The residency rule a department would break by using the instances, if any */
+export async function findResidencyViolation(
+  departmentId: string,
+  instanceIds: string[],
+): Promise<ResidencyViolation | null> {
+  const department = await prisma.department.findUnique({
+    where: { id: departmentId },
+    select: { allowedRegions: true },
+  })
+  const allowedRegions = parseAllowedRegions(department?.allowedRegions)
+  if (!allowedRegions) return null
+
+  const instances = await prisma.instance.findMany({
+    where: { id: { in: instanceIds } },
+    select: { id: true, region: true },
+  })
+  for (const instance of instances) {
+    if (instance.region && allowedRegions.includes(instance.region)) continue
+    return {
+      code: instance.region ? 'RESIDENCY_REGION_NOT_ALLOWED' : 'RESIDENCY_REGION_UNKNOWN',
+      departmentId,
+      instanceId: instance.id,
+      instanceRegion: instance.region,
+      allowedRegions,
+    }
+  }
+  return null
+}
+
+/** Throws ResidencyError when the department may not use the instances */
+export async function assertResidencyAllowed(departmentId: string, instanceIds: string[]): Promise<void> {
+  const violation = await findResidencyViolation(departmentId, instanceIds)
+  if (violation) throw new ResidencyError(violation)
+}
+
+/** 403 body for a violation: { error, code, instanceId, instanceRegion, allowedRegions } */
+export function residencyErrorResponse(violation: ResidencyViolation): NextResponse {
+  const { code, instanceId, instanceRegion, allowedRegions } = violation
+  return NextResponse.json(
+    { error: new ResidencyError(violation).message, code, instanceId, instanceRegion, allowedRegions },
+    { status: 403 },
+  )
+}
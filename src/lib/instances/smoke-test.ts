@@ -0,0 +1,121 @@
+import type { Instance } from '@/generated/prisma'
+import { decrypt } from '@/lib/auth/encryption'
+import { registry, ensureRegistryInitialized, resolveGatewayUrl } from '@/lib/gateway/registry'
+import { dockerManager } from '@/lib/docker'
+import { runThrowawayPrompt } from '@/lib/chat/run'
+import type { SmokeTestReport, SmokeTestStep, SmokeTestStepName } from '@/types/instance'
+
+const DEFAULT_CHAT_TIMEOUT_MS = 60_000
+const SMOKE_TEST_PROMPT = 'Smoke test: reply with the single word "pong".'
+
+class SkipStep extends Error {}
+
+async function runStep(
+  steps: SmokeTestStep[],
+  name: SmokeTestStepName,
+  fn: () => Promise<string | undefined>,
+): Promise<boolean> {
+  const start = Date.now()
+  try {
+    const detail = await fn()
+    steps.push({ name, status: 'pass', durationMs: Date.now() - start, detail })
+    return true
+  } catch (err) {
+    const skipped = err instanceof SkipStep
+    steps.push({
+      name,
+      status: skipped ? 'skip' : 'fail',
+      durationMs: Date.now() - start,
+      detail: (err as Error).message,
+    })
+    return skipped
+  }
+}
+
+function skip(name: SmokeTestStepName, reason: string): SmokeTestStep {
+  return { name, status: 'skip', durationMs: 0, detail: reason }
+}
+
+/**
+ * Scripted post-provisioning check: gateway handshake, agents.list, a
+ * trivial chat round-trip against a test agent, and container status.
+ * Later gateway steps are skipped once an earlier one fails; the container
+ * check always runs so a dead container is reported alongside the cause.
+ *
+ * The test agent is `agentId` if given, else SMOKE_TEST_AGENT_ID, else the
+ * gateway's default agent. The chat uses a throwaway session that is
+ * deleted afterwards and never recorded as a ChatSession.
+ */
+export async function runSmokeTest(
+  instance: Instance,
+  opts: { agentId?: string; timeoutMs?: number } = {},
+): Promise<SmokeTestReport> {
+  const startedAt = new Date()
+  const steps: SmokeTestStep[] = []
+  // Assigned inside the agents step; cast so TS doesn't narrow it to null
+  let agentId = null as string | null
+
+  await ensureRegistryInitialized()
+
+  // An existing connection is reused so live chats aren't dropped; the
+  // handshake is then proven by a round-trip on the open socket.
+  const connected = await runStep(steps, 'handshake', async () => {
+    if (!registry.isConnected(instance.id)) {
+      await registry.connect(instance.id, resolveGatewayUrl(instance), decrypt(instance.gatewayToken))
+    } else {
+      await registry.checkHealth(instance.id)
+    }
+    const version = registry.getServerVersion(instance.id)
+    return version ? `Connected (gateway ${version})` : 'Connected'
+  })
+
+  const client = connected ? registry.getClient(instance.id) : undefined
+  const adapter = connected ? registry.getAdapter(instance.id) : undefined
+
+  let agentsOk = false
+  if (client && adapter) {
+    agentsOk = await runStep(steps, 'agents', async () => {
+      const { agents, defaultId } = await adapter.getAgents(client)
+      const wanted = opts.agentId || process.env.SMOKE_TEST_AGENT_ID || defaultId || agents[0]?.id
+      if (!wanted) throw new Error('Gateway returned no agents')
+      if (!agents.some((a) => a.id === wanted)) {
+        throw new Error(`Test agent "${wanted}" not found among ${agents.length} agent(s)`)
+      }
+      agentId = wanted
+      return `${agents.length} agent(s), test agent "${wanted}"`
+    })
+  } else {
+    steps.push(skip('agents', 'Gateway handshake failed'))
+  }
+
+  if (client && adapter && agentsOk && agentId) {
+    const testAgent = agentId
+    await runStep(steps, 'chat', async () => {
+      const { text } = await runThrowawayPrompt(client, adapter, testAgent, SMOKE_TEST_PROMPT, {
+        timeoutMs: opts.timeoutMs ?? DEFAULT_CHAT_TIMEOUT_MS,
+        tag: 'smoke',
+      })
+      if (!text) throw new Error('Agent returned an empty reply')
+      return `Reply: ${text.slice(0, 80)}`
+    })
+  } else {
+    steps.push(skip('chat', agentsOk ? 'No test agent' : 'Agent listing failed'))
+  }
+
+  const { containerId } = instance
+  await runStep(steps, 'container', async () => {
+    if (!containerId) throw new SkipStep('External instance, no managed container')
+    const info = await dockerManager.inspectContainer(containerId)
+    if (info.state !== 'running') throw new Error(`Container is ${info.state} (${info.status})`)
+    return info.status
+  })
+
+  return {
+    instanceId: instance.id,
+    passed: steps.every((s) => s.status !== 'fail'),
+    agentId,
+    steps,
+    startedAt: startedAt.toISOString(),
+    durationMs: Date.now() - startedAt.getTime(),
+  }
+}
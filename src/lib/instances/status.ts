@@ -0,0 +1,126 @@
+import { EventEmitter } from 'events'
+import { prisma } from '@/lib/db'
+import { createLogger } from '@/lib/logger'
+import type { InstanceStatus, Prisma } from '@/generated/prisma'
+
+// Single writer for Instance.status. Gateway callbacks, the health checker
+// and lifecycle handlers all report *what happened* (an event) instead of
+// writing a status; this module decides whether that event may move the
+// instance from its current status, applies it with a compare-and-set
+// update (so a concurrent write in another process is never overwritten
+// blindly), and notifies listeners once the write has committed.
+//
+// Example races this stops: a health check that started before an admin
+// stopped the instance can no longer flip it back to ONLINE, and a soft
+// health failure can't downgrade an ERROR (lost connection) to DEGRADED.
+
+export type InstanceStatusEvent =
+  /** Admin start / restart / create succeeded */
+  | 'started'
+  /** Admin start / restart failed */
+  | 'start_failed'
+  /** Admin stopped the container */
+  | 'stopped'
+  /** Periodic health check passed */
+  | 'health_ok'
+  /** Periodic health check failed, below the failure threshold */
+  | 'health_degraded'
+  /** Periodic health check failed repeatedly */
+  | 'health_failed'
+  /** Explicit health check (admin) passed, whatever the previous status */
+  | 'checked_ok'
+  /** WebSocket gave up reconnecting, or restoring the connection failed */
+  | 'connection_lost'
+  /** Connection re-established; the next health check decides ONLINE */
+  | 'reconnected'
+
+const ALL: InstanceStatus[] = ['ONLINE', 'DEGRADED', 'OFFLINE', 'ERROR']
+
+const TRANSITIONS: Record<InstanceStatusEvent, { to: InstanceStatus; from: InstanceStatus[] }> = {
+  started: { to: 'ONLINE', from: ALL },
+  start_failed: { to: 'ERROR', from: ALL },
+  stopped: { to: 'OFFLINE', from: ALL },
+  health_ok: { to: 'ONLINE', from: ['ONLINE', 'DEGRADED'] },
+  health_degraded: { to: 'DEGRADED', from: ['ONLINE', 'DEGRADED'] },
+  health_failed: { to: 'OFFLINE', from: ['ONLINE', 'DEGRADED', 'OFFLINE'] },
+  checked_ok: { to: 'ONLINE', from: ALL },
+  connection_lost: { to: 'ERROR', from: ['ONLINE', 'DEGRADED'] },
+  reconnected: { to: 'DEGRADED', from: ['ERROR', 'OFFLINE'] },
+}
+
+export interface InstanceStatusChange {
+  instanceId: string
+  from: InstanceStatus
+  to: InstanceStatus
+  event: InstanceStatusEvent
+  at: Date
+}
+
+const log = createLogger('instances:status')
+
+const globalForStatus = globalThis as unknown as {
+  instanceStatusEmitter?: EventEmitter
+  instanceStatusQueues?: Map<string, Promise<unknown>>
+}
+
+const emitter = (globalForStatus.instanceStatusEmitter ??= new EventEmitter())
+const queues = (globalForStatus.instanceStatusQueues ??= new Map())
+
+/** Subscribe to committed status changes (not fired for same-status updates) */
+export function onInstanceStatusChange(listener: (change: InstanceStatusChange) => void): () => void {
+  emitter.on('change', listener)
+  return () => emitter.off('change', listener)
+}
+
+/** Transitions for one instance run one at a time within this process */
+function serialize<T>(instanceId: string, fn: () => Promise<T>): Promise<T> {
+  const prev = queues.get(instanceId) ?? Promise.resolve()
+  const next = prev.catch(() => {}).then(fn)
+  queues.set(instanceId, next)
+  next
+    .finally(() => {
+      if (queues.get(instanceId) === next) queues.delete(instanceId)
+    })
+    .catch(() => {})
+  return next
+}
+
+/**
+ * Apply an event to an instance. `data` (health data, version, …) is written
+ * together with the status, and only if the transition is allowed. Returns
+ * false when the current status doesn't accept the event or the instance is
+ * gone.
+ */
+export function transitionInstanceStatus(
+  instanceId: string,
+  event: InstanceStatusEvent,
+  data: Omit<Prisma.InstanceUpdateManyMutationInput, 'status'> = {},
+): Promise<boolean> {
+  const { to, from: allowed } = TRANSITIONS[event]
+
+  return serialize(instanceId, async () => {
+    // One retry: another process may change the status between read and write
+    for (let attempt = 0; attempt < 2; attempt++) {
+      const current = await prisma.instance.findUnique({ where: { id: instanceId }, select: { status: true } })
+      if (!current) return false
+      if (!allowed.includes(current.status)) {
+        log.debug('Ignored status event', { instanceId, event, status: current.status })
+        return false
+      }
+
+      const { count } = await prisma.instance.updateMany({
+        where: { id: instanceId, status: current.status },
+        data: { ...data, status: to },
+      })
+      if (count === 0) continue
+
+      if (current.status !== to) {
+        log.info('Instance status changed', { instanceId, from: current.status, to, event })
+        emitter.emit('change', { instanceId, from: current.status, to, event, at: new Date() } satisfies InstanceStatusChange)
+      }
+      return true
+    }
+    log.warn('Status event lost to concurrent updates', { instanceId, event })
+    return false
+  })
+}
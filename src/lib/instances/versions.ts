@@ -0,0 +1,171 @@
+import { prisma } from '@/lib/db'
+import { createLogger } from '@/lib/logger'
+import { registerCache } from '@/lib/caches'
+import type { OutdatedInstance, ReleaseNote, VersionAdvisory, VersionInventoryEntry } from '@/types/dashboard'
+
+// Upgrade advisories: compare the OpenClaw versions instances report against
+// the latest known release.
+//
+// OPENCLAW_RELEASE_FEED_URL — JSON feed, either { latest, releases: [...] },
+//   a bare array of { version, date, notes }, or the GitHub releases API
+//   format ({ tag_name, published_at, body }). ClawHub or a static file.
+// OPENCLAW_LATEST_VERSION   — fallback when no feed is configured or it fails.
+
+const FEED_TTL_MS = 60 * 60_000
+const FEED_TIMEOUT_MS = 10_000
+
+const log = createLogger('versions')
+
+interface Feed {
+  latest: string | null
+  releases: ReleaseNote[]
+}
+
+let feedCache: { at: number; feed: Feed } | null = null
+
+const feedCounter = registerCache('release-feed', {
+  description: 'OpenClaw release feed (1h TTL)',
+  keys: () => (feedCache ? [process.env.OPENCLAW_RELEASE_FEED_URL ?? 'feed'] : []),
+  clear: () => {
+    feedCache = null
+  },
+})
+
+function parseVersion(v: string): { nums: number[]; pre: string } {
+  const [core, pre = ''] = v.trim().replace(/^v/i, '').split('-', 2)
+  return { nums: core.split('.').map((n) => parseInt(n, 10) || 0), pre }
+}
+
+/** Compare dotted versions (semver or calendar style); prereleases sort first */
+export function compareVersions(a: string, b: string): number {
+  const pa = parseVersion(a)
+  const pb = parseVersion(b)
+  for (let i = 0; i < Math.max(pa.nums.length, pb.nums.length); i++) {
+    const d = (pa.nums[i] ?? 0) - (pb.nums[i] ?? 0)
+    if (d !== 0) return d
+  }
+  if (pa.pre === pb.pre) return 0
+  if (!pa.pre) return 1
+  if (!pb.pre) return -1
+  return pa.pre.localeCompare(pb.pre)
+}
+
+function toRelease(raw: Record<string, unknown>): ReleaseNote | null {
+  if (raw.draft || raw.prerelease) return null
+  const version = (raw.version ?? raw.tag_name) as string | undefined
+  if (typeof version !== 'string' || !version) return null
+  return {
+    version: version.replace(/^v/i, ''),
+    date: ((raw.date ?? raw.published_at) as string | undefined) ?? null,
+    notes: ((raw.notes ?? raw.body ?? raw.changelog) as string | undefined) ?? '',
+  }
+}
+
+async function fetchFeed(url: string): Promise<Feed> {
+  const res = await fetch(url, {
+    headers: { Accept: 'application/json' },
+    signal: AbortSignal.timeout(FEED_TIMEOUT_MS),
+  })
+  if (!res.ok) throw new Error(`Release feed returned ${res.status}`)
+  const data = (await res.json()) as unknown
+
+  const list = Array.isArray(data) ? data : ((data as { releases?: unknown[] }).releases ?? [])
+  const releases = list
+    .map((r) => (r && typeof r === 'object' ? toRelease(r as Record<string, unknown>) : null))
+    .filter((r): r is ReleaseNote => !!r)
+    .sort((a, b) => compareVersions(b.version, a.version))
+
+  const declared = !Array.isArray(data) ? (data as { latest?: unknown }).latest : undefined
+  const latest = typeof declared === 'string' && declared ? declared.replace(/^v/i, '') : releases[0]?.version ?? null
+  return { latest, releases }
+}
+
+interface LatestInfo {
+  latest: string | null
+  source: VersionAdvisory['source']
+  releases: ReleaseNote[]
+  feedError: string | null
+}
+
+async function getLatest(): Promise<LatestInfo> {
+  const url = process.env.OPENCLAW_RELEASE_FEED_URL
+  const fallback = process.env.OPENCLAW_LATEST_VERSION?.replace(/^v/i, '') || null
+  let feedError: string | null = null
+
+  if (url) {
+    try {
+      if (!feedCache || Date.now() - feedCache.at > FEED_TTL_MS) {
+        feedCounter.miss()
+        feedCache = { at: Date.now(), feed: await fetchFeed(url) }
+      } else {
+        feedCounter.hit()
+      }
+      const { latest, releases } = feedCache.feed
+      if (latest) return { latest, source: 'feed', releases, feedError }
+    } catch (err) {
+      feedError = (err as Error).message
+      log.warn('Release feed unavailable', { url, err })
+    }
+  }
+
+  return {
+    latest: fallback,
+    source: fallback ? 'static' : null,
+    releases: feedCache?.feed.releases ?? [],
+    feedError,
+  }
+}
+
+function usableVersion(v: string | null): string | null {
+  if (!v || v === 'dev' || v === 'unknown') return null
+  return v.replace(/^v/i, '')
+}
+
+/**
+ * Version inventory across instances plus the ones behind the latest known
+ * release, each with the changelog entries it is missing.
+ */
+export async function getVersionAdvisory(): Promise<VersionAdvisory> {
+  const [instances, { latest, source, releases, feedError }] = await Promise.all([
+    prisma.instance.findMany({
+      select: { id: true, name: true, status: true, version: true },
+      orderBy: { name: 'asc' },
+    }),
+    getLatest(),
+  ])
+
+  const counts = new Map<string | null, number>()
+  for (const inst of instances) {
+    const v = usableVersion(inst.version)
+    counts.set(v, (counts.get(v) ?? 0) + 1)
+  }
+  const inventory: VersionInventoryEntry[] = [...counts]
+    .map(([version, count]) => ({ version, count }))
+    .sort((a, b) => (a.version && b.version ? compareVersions(b.version, a.version) : a.version ? -1 : 1))
+
+  const outdated: OutdatedInstance[] = []
+  if (latest) {
+    for (const inst of instances) {
+      const version = usableVersion(inst.version)
+      if (!version || compareVersions(version, latest) >= 0) continue
+      outdated.push({
+        id: inst.id,
+        name: inst.name,
+        status: inst.status,
+        version,
+        changelog: releases.filter(
+          (r) => compareVersions(r.version, version) > 0 && compareVersions(r.version, latest) <= 0,
+        ),
+      })
+    }
+  }
+
+  return {
+    latest,
+    source,
+    checkedAt: new Date(feedCache?.at ?? Date.now()).toISOString(),
+    feedError,
+    inventory,
+    outdated,
+  }
+}
@@ -0,0 +1,148 @@
+import { createHmac, randomBytes, timingSafeEqual } from 'crypto'
+import { prisma } from '@/lib/db'
+import { redis } from '@/lib/redis'
+import { decrypt } from '@/lib/auth/encryption'
+import { registry, ensureRegistryInitialized } from '@/lib/gateway/registry'
+import { fetchChatHistory } from '@/lib/gateway/history'
+import { runAgentToCompletion } from '@/lib/chat/run'
+import { buildSnapshotData } from '@/lib/chat/snapshot-helpers'
+import { createSnapshots } from '@/lib/chat/snapshot-crypto'
+import { getSessionToolOutputRedactor } from '@/lib/chat/redaction'
+import type { IntegrationEndpoint } from '@/generated/prisma'
+
+/** Header carrying `sha256=<hex HMAC of "<timestamp>.<raw body>">` */
+export const SIGNATURE_HEADER = 'x-teamclaw-signature'
+
+/** Header carrying the signing time in Unix seconds; covered by the signature */
+export const TIMESTAMP_HEADER = 'x-teamclaw-timestamp'
+
+// Signed requests further than this from the server clock are refused; a
+// signature is remembered for twice as long so it cannot be replayed
+const SIGNATURE_TOLERANCE_SEC = 300
+
+export type IntegrationAuthResult = 'ok' | 'invalid' | 'stale' | 'replayed'
+
+export function generateIntegrationSecret(): string {
+  return randomBytes(32).toString('hex')
+}
+
+function safeEqual(a: string, b: string): boolean {
+  const ab = Buffer.from(a)
+  const bb = Buffer.from(b)
+  return ab.length === bb.length && timingSafeEqual(ab, bb)
+}
+
+/**
+ * Accept either an HMAC signature of the timestamp and raw body (preferred)
+ * or the secret itself as a bearer token, for senders that cannot sign
+ * (e.g. Alertmanager). Signed requests must be fresh and are accepted once.
+ */
+export async function verifyIntegrationRequest(
+  endpointId: string,
+  secret: string,
+  rawBody: string,
+  headers: Headers,
+): Promise<IntegrationAuthResult> {
+  const signature = headers.get(SIGNATURE_HEADER)
+  if (signature) {
+    const timestamp = headers.get(TIMESTAMP_HEADER) ?? ''
+    if (!/^\d{1,12}$/.test(timestamp)) return 'invalid'
+    const expected =
+      'sha256=' + createHmac('sha256', secret).update(`${timestamp}.${rawBody}`).digest('hex')
+    if (!safeEqual(signature, expected)) return 'invalid'
+    if (Math.abs(Date.now() / 1000 - Number(timestamp)) > SIGNATURE_TOLERANCE_SEC) return 'stale'
+    const first = await redis.set(
+      `integration:signature:${endpointId}:${signature}`,
+      '1',
+      'EX',
+      SIGNATURE_TOLERANCE_SEC * 2,
+      'NX',
+    )
+    return first === 'OK' ? 'ok' : 'replayed'
+  }
+  const auth = headers.get('authorization')
+  if (auth?.startsWith('Bearer ')) {
+    return safeEqual(auth.slice(7), secret) ? 'ok' : 'invalid'
+  }
+  return 'invalid'
+}
+
+/**
+ * Run an accepted event to completion and store the transcript as a
+ * session owned by the endpoint's service account. Each event gets its own
+ * gateway session so concurrent events never share context.
+ */
+export async function processIntegrationEvent(
+  endpoint: IntegrationEndpoint,
+  eventId: string,
+  prompt: string,
+): Promise<void> {
+  const sessionKey = `agent:${endpoint.agentId}:tc:${endpoint.serviceAccountId}:${eventId}`
+
+  const session = await prisma.chatSession.create({
+    data: {
+      userId: endpoint.serviceAccountId,
+      instanceId: endpoint.instanceId,
+      agentId: endpoint.agentId,
+      sessionId: sessionKey,
+      title: `${endpoint.name} · ${new Date().toISOString().slice(0, 16).replace('T', ' ')}`,
+      lastMessageAt: new Date(),
+      messageCount: 1,
+      isActive: false,
+    },
+  })
+  await prisma.integrationEvent.update({
+    where: { id: eventId },
+    data: { chatSessionId: session.id },
+  })
+
+  try {
+    await ensureRegistryInitialized()
+    const client = registry.getClient(endpoint.instanceId)
+    const adapter = registry.getAdapter(endpoint.instanceId)
+    if (!client || !adapter) throw new Error('Instance not connected')
+
+    await runAgentToCompletion(client, adapter, sessionKey, prompt)
+
+    const history = await fetchChatHistory(client, sessionKey, 200, 10_000)
+    const redact = await getSessionToolOutputRedactor(session.id)
+    const { snapshotData } = buildSnapshotData(session.id, history.messages ?? [], redact)
+    await createSnapshots(snapshotData)
+    await client.request('sessions.delete', { key: sessionKey }).catch(() => {})
+
+    await prisma.integrationEvent.update({
+      where: { id: eventId },
+      data: { status: 'COMPLETED', completedAt: new Date() },
+    })
+  } catch (err) {
+    await prisma.integrationEvent.update({
+      where: { id: eventId },
+      data: { status: 'FAILED', error: (err as Error).message, completedAt: new Date() },
+    })
+  }
+}
+
+export function decryptIntegrationSecret(endpoint: IntegrationEndpoint): string {
+  return decrypt(endpoint.secret)
+}
+
+export function toIntegrationResponse(
+  e: IntegrationEndpoint & { instance?: { name: string } | null },
+) {
+  return {
+    id: e.id,
+    name: e.name,
+    slug: e.slug,
+    description: e.description,
+    instanceId: e.instanceId,
+    instanceName: e.instance?.name ?? null,
+    agentId: e.agentId,
+    promptTemplate: e.promptTemplate,
+    enabled: e.enabled,
+    serviceAccountId: e.serviceAccountId,
+    hookUrl: `/api/v1/hooks/${e.slug}`,
+    lastTriggeredAt: e.lastTriggeredAt?.toISOString() ?? null,
+    createdAt: e.createdAt.toISOString(),
+    updatedAt: e.updatedAt.toISOString(),
+  }
+}
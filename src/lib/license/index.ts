@@ -0,0 +1,153 @@
+import { readFileSync } from 'fs'
+import { createPublicKey, verify } from 'crypto'
+import { NextResponse } from 'next/server'
+import { prisma } from '@/lib/db'
+import { createLogger } from '@/lib/logger'
+import type { LicensePayload, LicenseState, LicenseStatus } from '@/types/license'
+
+// License format: <base64url(payload JSON)>.<base64url(Ed25519 signature over the first part)>
+// LICENSE_FILE points at the file; LICENSE_KEY may hold the same content inline.
+// LICENSE_PUBLIC_KEY is the issuer's Base64-encoded SPKI PEM (scripts/sign-license.mjs).
+
+const DEFAULT_GRACE_DAYS = 14
+
+const log = createLogger('license')
+const DAY_MS = 86_400_000
+
+interface LoadedLicense {
+  payload: LicensePayload | null
+  error: string | null
+  configured: boolean
+}
+
+const globalForLicense = globalThis as unknown as {
+  license?: LoadedLicense
+}
+
+function readLicenseText(): string | null {
+  if (process.env.LICENSE_KEY) return process.env.LICENSE_KEY.trim()
+  if (process.env.LICENSE_FILE) return readFileSync(process.env.LICENSE_FILE, 'utf8').trim()
+  return null
+}
+
+function parseLicense(text: string): LicensePayload {
+  const b64 = process.env.LICENSE_PUBLIC_KEY
+  if (!b64) throw new Error('LICENSE_PUBLIC_KEY is not set')
+
+  const [body, signature, extra] = text.split('.')
+  if (!body || !signature || extra !== undefined) throw new Error('Malformed license')
+
+  const publicKey = createPublicKey(Buffer.from(b64, 'base64').toString('utf8'))
+  const ok = verify(null, Buffer.from(body), publicKey, Buffer.from(signature, 'base64url'))
+  if (!ok) throw new Error('License signature mismatch')
+
+  const payload = JSON.parse(Buffer.from(body, 'base64url').toString('utf8')) as LicensePayload
+  if (!payload.licenseId || !payload.expiresAt || Number.isNaN(Date.parse(payload.expiresAt))) {
+    throw new Error('License payload is incomplete')
+  }
+  return payload
+}
+
+/** (Re)read and verify the configured license. Called once at startup. */
+export function loadLicense(): LoadedLicense {
+  let loaded: LoadedLicense
+  try {
+    const text = readLicenseText()
+    loaded = text
+      ? { payload: parseLicense(text), error: null, configured: true }
+      : { payload: null, error: null, configured: false }
+  } catch (err) {
+    loaded = { payload: null, error: (err as Error).message, configured: true }
+  }
+  globalForLicense.license = loaded
+  return loaded
+}
+
+function getLoadedLicense(): LoadedLicense {
+  return globalForLicense.license ?? loadLicense()
+}
+
+function resolveState(loaded: LoadedLicense, now = Date.now()): LicenseState {
+  if (!loaded.configured) return 'UNLICENSED'
+  if (!loaded.payload) return 'INVALID'
+  const expiresAt = Date.parse(loaded.payload.expiresAt)
+  if (now < expiresAt) return 'VALID'
+  const graceDays = loaded.payload.graceDays ?? DEFAULT_GRACE_DAYS
+  return now < expiresAt + graceDays * DAY_MS ? 'GRACE' : 'EXPIRED'
+}
+
+/** Seats are interactive active users; service accounts don't count */
+function countSeats(): Promise<number> {
+  return prisma.user.count({ where: { status: 'ACTIVE', isServiceAccount: false } })
+}
+
+export async function getLicenseStatus(): Promise<LicenseStatus> {
+  const loaded = getLoadedLicense()
+  const payload = loaded.payload
+  const [seats, instances] = await Promise.all([countSeats(), prisma.instance.count()])
+
+  let graceEndsAt: string | null = null
+  if (payload) {
+    const graceDays = payload.graceDays ?? DEFAULT_GRACE_DAYS
+    graceEndsAt = new Date(Date.parse(payload.expiresAt) + graceDays * DAY_MS).toISOString()
+  }
+
+  return {
+    state: resolveState(loaded),
+    licenseId: payload?.licenseId ?? null,
+    licensee: payload?.licensee ?? null,
+    expiresAt: payload?.expiresAt ?? null,
+    graceEndsAt,
+    seats: { used: seats, limit: payload?.maxSeats ?? null },
+    instances: { used: instances, limit: payload?.maxInstances ?? null },
+    error: loaded.error,
+  }
+}
+
+/**
+ * Check whether one more seat/instance may be added. Returns a 403 response
+ * when the license forbids it, or null when the caller may proceed.
+ */
+export async function enforceLicenseLimit(kind: 'seat' | 'instance'): Promise<NextResponse | null> {
+  const loaded = getLoadedLicense()
+  const state = resolveState(loaded)
+  if (state === 'UNLICENSED') return null
+
+  if (state === 'INVALID' || state === 'EXPIRED') {
+    return NextResponse.json(
+      { error: state === 'INVALID' ? 'License is invalid' : 'License has expired', code: 'LICENSE_' + state },
+      { status: 403 },
+    )
+  }
+
+  const limit = kind === 'seat' ? loaded.payload!.maxSeats : loaded.payload!.maxInstances
+  if (limit == null) return null
+
+  const used = kind === 'seat' ? await countSeats() : await prisma.instance.count()
+  if (used >= limit) {
+    return NextResponse.json(
+      {
+        error: kind === 'seat' ? 'License seat limit reached' : 'License instance limit reached',
+        code: 'LICENSE_LIMIT',
+        limit,
+      },
+      { status: 403 },
+    )
+  }
+  return null
+}
+
+/** Log the license state once when the server boots */
+export async function initLicense(): Promise<void> {
+  const loaded = loadLicense()
+  const state = resolveState(loaded)
+  if (state === 'UNLICENSED') return
+  if (state === 'INVALID') {
+    log.error('Invalid license', { error: loaded.error })
+    return
+  }
+  const p = loaded.payload!
+  const fields = { licenseId: p.licenseId, licensee: p.licensee, state, expiresAt: p.expiresAt }
+  if (state === 'VALID') log.info('License loaded', fields)
+  else log.warn('License is not current', fields)
+}
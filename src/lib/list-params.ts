@@ -0,0 +1,114 @@
+import { NextResponse } from 'next/server'
+
+// Sorting and sparse field selection for list endpoints:
+//
+//   ?sort=name:asc,createdAt:desc  — fields from the endpoint's allowlist;
+//                                     the direction defaults to asc
+//   ?fields=id,name,status         — only these fields of each item (id is
+//                                     always included)
+//
+// Each endpoint declares what may be sorted (and the Prisma path it maps to,
+// e.g. departmentName → department.name) and which fields exist. Unknown
+// names get a 400 rather than being ignored, so a typo is not mistaken for
+// a result.
+
+export type SortDirection = 'asc' | 'desc'
+
+export interface SortSpec {
+  field: string
+  direction: SortDirection
+}
+
+export interface ListSpec {
+  /** Sortable field → its dotted Prisma path */
+  sortable: Record<string, string>
+  /** Used when there is no ?sort */
+  defaultSort: SortSpec[]
+  /** Fields each item has, for ?fields */
+  fields: readonly string[]
+}
+
+export interface ListParams<TOrderBy> {
+  sort: SortSpec[]
+  fields: string[] | null
+  /** Prisma orderBy for the sort, with id as the tie-breaker so pages are stable */
+  orderBy: TOrderBy[]
+}
+
+const MAX_SORT_FIELDS = 3
+
+function nest(path: string, direction: SortDirection): Record<string, unknown> {
+  return path
+    .split('.')
+    .reduceRight<unknown>((inner, key) => ({ [key]: inner }), direction) as Record<string, unknown>
+}
+
+/** Parse ?sort and ?fields; returns a 400 response when they name something not allowed */
+export function parseListParams<TOrderBy = Record<string, unknown>>(
+  query: URLSearchParams,
+  spec: ListSpec,
+): ListParams<TOrderBy> | NextResponse {
+  let sort = spec.defaultSort
+  const rawSort = query.get('sort')
+  if (rawSort) {
+    sort = []
+    for (const part of rawSort.split(',').map((s) => s.trim()).filter(Boolean)) {
+      const [field, direction = 'asc'] = part.split(':')
+      if (!(field in spec.sortable)) {
+        return NextResponse.json(
+          { error: `Cannot sort by "${field}"; sortable: ${Object.keys(spec.sortable).join(', ')}` },
+          { status: 400 },
+        )
+      }
+      if (direction !== 'asc' && direction !== 'desc') {
+        return NextResponse.json({ error: `Sort direction must be asc or desc, got "${direction}"` }, { status: 400 })
+      }
+      sort.push({ field, direction })
+    }
+    if (sort.length > MAX_SORT_FIELDS) {
+      return NextResponse.json({ error: `At most ${MAX_SORT_FIELDS} sort fields` }, { status: 400 })
+    }
+  }
+
+  let fields: string[] | null = null
+  const rawFields = query.get('fields')
+  if (rawFields) {
+    fields = [...new Set(['id', ...rawFields.split(',').map((f) => f.trim()).filter(Boolean)])]
+    const unknown = fields.filter((f) => !spec.fields.includes(f))
+    if (unknown.length > 0) {
+      return NextResponse.json({ error: `Unknown fields: ${unknown.join(', ')}` }, { status: 400 })
+    }
+  }
+
+  const orderBy = sort.map((s) => nest(spec.sortable[s.field], s.direction))
+  if (!sort.some((s) => s.field === 'id')) orderBy.push({ id: 'asc' })
+  return { sort, fields, orderBy: orderBy as TOrderBy[] }
+}
+
+/** The item with only the requested fields (all of them when none were requested) */
+export function pickFields<T extends object>(item: T, fields: string[] | null): Partial<T> {
+  if (!fields) return item
+  const picked: Partial<T> = {}
+  for (const f of fields) {
+    if (f in item) picked[f as keyof T] = item[f as keyof T]
+  }
+  return picked
+}
+
+/** Sort items in memory, for lists not read from the database */
+export function sortItems<T extends object>(items: T[], sort: SortSpec[]): T[] {
+  const value = (item: T, field: string) => (item as Record<string, unknown>)[field]
+  return [...items].sort((a, b) => {
+    for (const { field, direction } of sort) {
+      const x = value(a, field)
+      const y = value(b, field)
+      if (x === y) continue
+      // Missing values sort last either way
+      if (x === null || x === undefined) return 1
+      if (y === null || y === undefined) return -1
+      const cmp = typeof x === 'string' && typeof y === 'string' ? x.localeCompare(y) : x < y ? -1 : 1
+      return direction === 'asc' ? cmp : -cmp
+    }
+    return 0
+  })
+}
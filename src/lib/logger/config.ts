@@ -0,0 +1,39 @@
+import { prisma } from '@/lib/db'
+import { Prisma } from '@/generated/prisma'
+import { applyLogLevels, isLogLevel } from './index'
+import type { LogLevel } from './index'
+
+/** SystemConfig key holding runtime log level overrides */
+export const LOG_LEVELS_KEY = 'log_levels'
+
+interface StoredLogLevels {
+  defaultLevel: LogLevel
+  moduleLevels: Record<string, LogLevel>
+}
+
+/** Apply levels saved through the admin endpoint; env values stay as the fallback */
+export async function loadPersistedLogLevels(): Promise<void> {
+  const row = await prisma.systemConfig.findUnique({ where: { key: LOG_LEVELS_KEY } })
+  const stored = row?.value as Partial<StoredLogLevels> | undefined
+  if (!stored || !isLogLevel(stored.defaultLevel)) return
+
+  const moduleLevels: Record<string, LogLevel> = {}
+  for (const [mod, level] of Object.entries(stored.moduleLevels ?? {})) {
+    if (isLogLevel(level)) moduleLevels[mod] = level
+  }
+  applyLogLevels(stored.defaultLevel, moduleLevels)
+}
+
+export async function saveLogLevels(defaultLevel: LogLevel, moduleLevels: Record<string, LogLevel>): Promise<void> {
+  const value: StoredLogLevels = { defaultLevel, moduleLevels }
+  await prisma.systemConfig.upsert({
+    where: { key: LOG_LEVELS_KEY },
+    update: { value: value as unknown as Prisma.InputJsonValue },
+    create: {
+      key: LOG_LEVELS_KEY,
+      value: value as unknown as Prisma.InputJsonValue,
+      description: 'Runtime log levels (default and per module)',
+    },
+  })
+  applyLogLevels(defaultLevel, moduleLevels)
+}
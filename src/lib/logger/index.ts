@@ -0,0 +1,169 @@
+import { fileSink, lokiSink, stdoutSink } from './sinks'
+import type { LogLevel, LogRecord, LogSink } from './sinks'
+
+export type { LogLevel } from './sinks'
+
+// ─── Configuration ───────────────────────────────────────────────────
+//
+// LOG_SINKS          — comma-separated: stdout (default), file, loki
+// LOG_FORMAT         — stdout format: json | pretty (default: json in production)
+// LOG_LEVEL          — default level (info)
+// LOG_MODULE_LEVELS  — per-module overrides: "gateway=debug,clawhub=warn"
+// LOG_FILE           — file sink path; LOG_FILE_MAX_MB (50), LOG_FILE_MAX_FILES (5)
+// LOKI_URL           — push endpoint, e.g. http://loki:3100/loki/api/v1/push
+// LOKI_LABELS        — extra stream labels: "env=prod,region=cn"
+// LOKI_BASIC_AUTH    — "user:password"
+//
+// Module levels can be changed at runtime via /api/v1/settings/logging.
+
+export const LOG_LEVELS: LogLevel[] = ['debug', 'info', 'warn', 'error']
+
+const LEVEL_RANK: Record<LogLevel, number> = { debug: 10, info: 20, warn: 30, error: 40 }
+
+export function isLogLevel(v: unknown): v is LogLevel {
+  return typeof v === 'string' && (LOG_LEVELS as string[]).includes(v)
+}
+
+function parsePairs(value: string | undefined): Record<string, string> {
+  const out: Record<string, string> = {}
+  for (const pair of (value ?? '').split(',')) {
+    const [k, v] = pair.split('=').map((s) => s.trim())
+    if (k && v) out[k] = v
+  }
+  return out
+}
+
+interface LoggerState {
+  sinks: LogSink[]
+  defaultLevel: LogLevel
+  moduleLevels: Map<string, LogLevel>
+  modules: Set<string>
+}
+
+const globalForLogger = globalThis as unknown as {
+  loggerState?: LoggerState
+}
+
+function buildSinks(): LogSink[] {
+  const names = (process.env.LOG_SINKS || 'stdout').split(',').map((s) => s.trim())
+  const sinks: LogSink[] = []
+  for (const name of names) {
+    try {
+      if (name === 'stdout') {
+        const format = process.env.LOG_FORMAT ?? (process.env.NODE_ENV === 'production' ? 'json' : 'pretty')
+        sinks.push(stdoutSink(format === 'json' ? 'json' : 'pretty'))
+      } else if (name === 'file' && process.env.LOG_FILE) {
+        const maxMb = parseInt(process.env.LOG_FILE_MAX_MB || '50')
+        const maxFiles = parseInt(process.env.LOG_FILE_MAX_FILES || '5')
+        sinks.push(fileSink(process.env.LOG_FILE, maxMb * 1024 * 1024, maxFiles))
+      } else if (name === 'loki' && process.env.LOKI_URL) {
+        const labels = { app: 'teamclaw', ...parsePairs(process.env.LOKI_LABELS) }
+        sinks.push(lokiSink(process.env.LOKI_URL, labels, process.env.LOKI_BASIC_AUTH || undefined))
+      }
+    } catch (err) {
+      process.stderr.write(`[logger] sink "${name}" disabled: ${(err as Error).message}\n`)
+    }
+  }
+  // Never lose logs entirely because of a bad config
+  return sinks.length > 0 ? sinks : [stdoutSink('pretty')]
+}
+
+function getState(): LoggerState {
+  if (!globalForLogger.loggerState) {
+    const envLevel = process.env.LOG_LEVEL
+    const moduleLevels = new Map<string, LogLevel>()
+    for (const [mod, level] of Object.entries(parsePairs(process.env.LOG_MODULE_LEVELS))) {
+      if (isLogLevel(level)) moduleLevels.set(mod, level)
+    }
+    globalForLogger.loggerState = {
+      sinks: buildSinks(),
+      defaultLevel: isLogLevel(envLevel) ? envLevel : 'info',
+      moduleLevels,
+      modules: new Set(),
+    }
+  }
+  return globalForLogger.loggerState
+}
+
+// ─── Runtime level control ───────────────────────────────────────────
+
+export interface LoggingConfig {
+  defaultLevel: LogLevel
+  moduleLevels: Record<string, LogLevel>
+  /** Modules that have logged or created a logger since startup */
+  knownModules: string[]
+  sinks: string[]
+}
+
+export function getLoggingConfig(): LoggingConfig {
+  const state = getState()
+  return {
+    defaultLevel: state.defaultLevel,
+    moduleLevels: Object.fromEntries(state.moduleLevels),
+    knownModules: [...state.modules].sort(),
+    sinks: state.sinks.map((s) => s.name),
+  }
+}
+
+/** Replace the default level and per-module overrides */
+export function applyLogLevels(defaultLevel: LogLevel, moduleLevels: Record<string, LogLevel>): void {
+  const state = getState()
+  state.defaultLevel = defaultLevel
+  state.moduleLevels = new Map(Object.entries(moduleLevels))
+}
+
+function levelFor(module: string): LogLevel {
+  const state = getState()
+  // "gateway:health" falls back to "gateway" before the default
+  let name = module
+  while (name) {
+    const level = state.moduleLevels.get(name)
+    if (level) return level
+    const i = name.lastIndexOf(':')
+    name = i === -1 ? '' : name.slice(0, i)
+  }
+  return state.defaultLevel
+}
+
+// ─── Logger ──────────────────────────────────────────────────────────
+
+export type LogFields = Record<string, unknown>
+
+export interface Logger {
+  debug(msg: string, fields?: LogFields): void
+  info(msg: string, fields?: LogFields): void
+  warn(msg: string, fields?: LogFields): void
+  error(msg: string, fields?: LogFields): void
+}
+
+function serializeFields(fields: LogFields): LogFields {
+  const out: LogFields = {}
+  for (const [k, v] of Object.entries(fields)) {
+    out[k] = v instanceof Error ? { message: v.message, stack: v.stack } : v
+  }
+  return out
+}
+
+export function createLogger(module: string): Logger {
+  getState().modules.add(module)
+
+  function log(level: LogLevel, msg: string, fields?: LogFields) {
+    if (LEVEL_RANK[level] < LEVEL_RANK[levelFor(module)]) return
+    // Fields first so they can never overwrite the core keys
+    const record: LogRecord = {
+      ...(fields ? serializeFields(fields) : {}),
+      time: new Date().toISOString(),
+      level,
+      module,
+      msg,
+    }
+    for (const sink of getState().sinks) sink.write(record)
+  }
+
+  return {
+    debug: (msg, fields) => log('debug', msg, fields),
+    info: (msg, fields) => log('info', msg, fields),
+    warn: (msg, fields) => log('warn', msg, fields),
+    error: (msg, fields) => log('error', msg, fields),
+  }
+}
@@ -0,0 +1,127 @@
+import fs from 'fs'
+import path from 'path'
+
+export type LogLevel = 'debug' | 'info' | 'warn' | 'error'
+
+export interface LogRecord {
+  time: string
+  level: LogLevel
+  module: string
+  msg: string
+  [key: string]: unknown
+}
+
+export interface LogSink {
+  name: string
+  write(record: LogRecord): void
+}
+
+// ─── stdout ──────────────────────────────────────────────────────────
+
+export function stdoutSink(format: 'json' | 'pretty'): LogSink {
+  return {
+    name: 'stdout',
+    write(record) {
+      const out = record.level === 'error' || record.level === 'warn' ? process.stderr : process.stdout
+      if (format === 'json') {
+        out.write(JSON.stringify(record) + '\n')
+        return
+      }
+      const { time, level, module, msg, ...fields } = record
+      const extra = Object.keys(fields).length ? ' ' + JSON.stringify(fields) : ''
+      out.write(`${time} ${level.toUpperCase().padEnd(5)} [${module}] ${msg}${extra}\n`)
+    },
+  }
+}
+
+// ─── JSON file with size-based rotation ──────────────────────────────
+//
+// app.log → app.log.1 → … → app.log.<maxFiles>; the oldest is dropped.
+
+export function fileSink(file: string, maxBytes: number, maxFiles: number): LogSink {
+  fs.mkdirSync(path.dirname(file), { recursive: true })
+  let size = fs.existsSync(file) ? fs.statSync(file).size : 0
+
+  function rotate() {
+    for (let i = maxFiles - 1; i >= 1; i--) {
+      const from = `${file}.${i}`
+      if (fs.existsSync(from)) fs.renameSync(from, `${file}.${i + 1}`)
+    }
+    if (fs.existsSync(file)) fs.renameSync(file, `${file}.1`)
+    size = 0
+  }
+
+  return {
+    name: 'file',
+    write(record) {
+      const line = JSON.stringify(record) + '\n'
+      try {
+        if (size + line.length > maxBytes) rotate()
+        fs.appendFileSync(file, line)
+        size += line.length
+      } catch (err) {
+        process.stderr.write(`[logger] file sink failed: ${(err as Error).message}\n`)
+      }
+    },
+  }
+}
+
+// ─── Loki / HTTP push ────────────────────────────────────────────────
+//
+// Batches records and POSTs them in Loki's push format. One stream per
+// (module, level) pair so they can be filtered by label.
+
+const LOKI_FLUSH_MS = 2_000
+const LOKI_MAX_BATCH = 500
+
+export function lokiSink(url: string, labels: Record<string, string>, basicAuth?: string): LogSink {
+  let buffer: LogRecord[] = []
+  let timer: ReturnType<typeof setTimeout> | null = null
+
+  async function flush() {
+    timer = null
+    const batch = buffer
+    buffer = []
+    if (batch.length === 0) return
+
+    const streams = new Map<string, { stream: Record<string, string>; values: [string, string][] }>()
+    for (const record of batch) {
+      const key = `${record.module}|${record.level}`
+      let entry = streams.get(key)
+      if (!entry) {
+        entry = { stream: { ...labels, module: record.module, level: record.level }, values: [] }
+        streams.set(key, entry)
+      }
+      // Loki wants nanosecond timestamps as strings
+      entry.values.push([`${Date.parse(record.time)}000000`, JSON.stringify(record)])
+    }
+
+    try {
+      const res = await fetch(url, {
+        method: 'POST',
+        headers: {
+          'Content-Type': 'application/json',
+          ...(basicAuth ? { Authorization: `Basic ${Buffer.from(basicAuth).toString('base64')}` } : {}),
+        },
+        body: JSON.stringify({ streams: [...streams.values()] }),
+        signal: AbortSignal.timeout(5_000),
+      })
+      if (!res.ok) process.stderr.write(`[logger] loki push failed: HTTP ${res.status}\n`)
+    } catch (err) {
+      process.stderr.write(`[logger] loki push failed: ${(err as Error).message}\n`)
+    }
+  }
+
+  return {
+    name: 'loki',
+    write(record) {
+      buffer.push(record)
+      if (buffer.length >= LOKI_MAX_BATCH) {
+        if (timer) clearTimeout(timer)
+        flush()
+      } else if (!timer) {
+        timer = setTimeout(flush, LOKI_FLUSH_MS)
+      }
+    },
+  }
+}
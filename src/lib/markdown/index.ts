@@ -0,0 +1,271 @@
+// Server-side markdown → sanitized HTML, for embedding surfaces that can't
+// safely render raw agent markdown themselves. No external library: the
+// renderer escapes all source HTML and emits a small markdown subset
+// (headings, paragraphs, lists, quotes, tables, code, emphasis, links,
+// images); its output is then passed through an allowlist sanitizer as a
+// second line of defence.
+
+export type RenderMode = 'html'
+
+const ALLOWED_TAGS: Record<string, string[]> = {
+  p: [], br: [], hr: [],
+  h1: [], h2: [], h3: [], h4: [], h5: [], h6: [],
+  strong: [], em: [], del: [], code: ['class'], pre: [], blockquote: [],
+  ul: [], ol: [], li: [],
+  table: [], thead: [], tbody: [], tr: [], th: [], td: [],
+  a: ['href', 'title'],
+  img: ['src', 'alt', 'title'],
+}
+const VOID_TAGS = new Set(['br', 'hr', 'img'])
+// Dropped together with everything inside them
+const DROP_CONTENT_TAGS = new Set(['script', 'style', 'iframe', 'object', 'embed', 'noscript', 'template', 'textarea', 'select'])
+
+const SAFE_LINK = /^(https?:|mailto:)/i
+const SAFE_IMAGE = /^(https?:|data:image\/(png|jpe?g|gif|webp);base64,)/i
+
+const TAG_RE =
+  /<!--[\s\S]*?-->|<(\/?)([a-zA-Z][a-zA-Z0-9]*)((?:\s+[^\s"'>/=]+(?:\s*=\s*(?:"[^"]*"|'[^']*'|[^\s"'=<>`]+))?)*)\s*\/?>/g
+const ATTR_RE = /([^\s"'>/=]+)(?:\s*=\s*(?:"([^"]*)"|'([^']*)'|([^\s"'=<>`]+)))?/g
+
+export function escapeHtml(text: string): string {
+  return text
+    .replace(/&/g, '&amp;')
+    .replace(/</g, '&lt;')
+    .replace(/>/g, '&gt;')
+    .replace(/"/g, '&quot;')
+    .replace(/'/g, '&#39;')
+}
+
+/** Escape markup but keep entities that are already encoded */
+function escapeLoose(text: string): string {
+  return text
+    .replace(/&(?!(?:[a-zA-Z][a-zA-Z0-9]{1,31}|#\d{1,7}|#x[0-9a-fA-F]{1,6});)/g, '&amp;')
+    .replace(/</g, '&lt;')
+    .replace(/>/g, '&gt;')
+    .replace(/"/g, '&quot;')
+}
+
+function decodeEntities(text: string): string {
+  const fromCode = (n: number) => (n > 0 && n <= 0x10ffff ? String.fromCodePoint(n) : '')
+  return text
+    .replace(/&#x([0-9a-f]+);?/gi, (_, hex: string) => fromCode(parseInt(hex, 16)))
+    .replace(/&#(\d+);?/g, (_, dec: string) => fromCode(Number(dec)))
+    .replace(/&colon;/gi, ':')
+    .replace(/&(tab|newline);/gi, '')
+    .replace(/&amp;/gi, '&')
+}
+
+function isSafeUrl(value: string, pattern: RegExp): boolean {
+  // Browsers ignore control characters and whitespace inside the scheme
+  return pattern.test(decodeEntities(value).replace(/[\u0000- \u007f]/g, ''))
+}
+
+function sanitizeAttrs(tag: string, rawAttrs: string): string {
+  const allowed = ALLOWED_TAGS[tag]
+  const out: string[] = []
+  for (const m of rawAttrs.matchAll(ATTR_RE)) {
+    const name = m[1].toLowerCase()
+    const value = m[2] ?? m[3] ?? m[4] ?? ''
+    if (!allowed.includes(name)) continue
+    if (name === 'href' && !isSafeUrl(value, SAFE_LINK)) continue
+    if (name === 'src' && !isSafeUrl(value, SAFE_IMAGE)) continue
+    if (name === 'class' && !/^language-[\w+-]+$/.test(value)) continue
+    out.push(` ${name}="${escapeLoose(value)}"`)
+  }
+  if (tag === 'a') out.push(' rel="noopener noreferrer nofollow" target="_blank"')
+  return out.join('')
+}
+
+/**
+ * Allowlist sanitizer: keeps only ALLOWED_TAGS with their listed attributes,
+ * restricts URLs to safe schemes, drops comments and script-like elements
+ * with their content, escapes everything else, and balances open tags.
+ */
+export function sanitizeHtml(html: string): string {
+  const out: string[] = []
+  const stack: string[] = []
+  let last = 0
+
+  TAG_RE.lastIndex = 0
+  let m: RegExpExecArray | null
+  while ((m = TAG_RE.exec(html))) {
+    out.push(escapeLoose(html.slice(last, m.index)))
+    last = TAG_RE.lastIndex
+
+    const [full, closing, rawTag, rawAttrs = ''] = m
+    if (full.startsWith('<!--')) continue
+    const tag = rawTag.toLowerCase()
+
+    if (DROP_CONTENT_TAGS.has(tag)) {
+      if (!closing) {
+        const end = html.toLowerCase().indexOf(`</${tag}`, last)
+        last = end === -1 ? html.length : html.indexOf('>', end) + 1 || html.length
+        TAG_RE.lastIndex = last
+      }
+      continue
+    }
+    if (!(tag in ALLOWED_TAGS)) continue
+
+    if (closing) {
+      const idx = stack.lastIndexOf(tag)
+      if (idx === -1) continue
+      while (stack.length > idx) out.push(`</${stack.pop()}>`)
+    } else {
+      out.push(`<${tag}${sanitizeAttrs(tag, rawAttrs)}>`)
+      if (!VOID_TAGS.has(tag)) stack.push(tag)
+    }
+  }
+  out.push(escapeLoose(html.slice(last)))
+  while (stack.length) out.push(`</${stack.pop()}>`)
+  return out.join('')
+}
+
+// ─── Markdown ────────────────────────────────────────────────────────
+
+const LIST_ITEM = /^\s*([-*+]|\d+[.)])\s+(.*)$/
+const TABLE_SEP = /^\s*\|?\s*:?-{3,}:?\s*(\|\s*:?-{3,}:?\s*)*\|?\s*$/
+const HR = /^\s*([-*_])(\s*\1){2,}\s*$/
+const PLACEHOLDER = /\u0000(\d+)\u0000/g
+
+function emphasis(s: string): string {
+  return s
+    .replace(/\*\*(\S(?:[^*]*\S)?)\*\*/g, '<strong>$1</strong>')
+    .replace(/__(\S(?:[^_]*\S)?)__/g, '<strong>$1</strong>')
+    .replace(/(^|[^*\w])\*(\S(?:[^*]*\S)?)\*(?!\w)/g, '$1<em>$2</em>')
+    .replace(/(^|[^_\w])_(\S(?:[^_]*\S)?)_(?!\w)/g, '$1<em>$2</em>')
+    .replace(/~~(\S(?:[^~]*\S)?)~~/g, '<del>$1</del>')
+}
+
+/** Inline markdown on already-escaped text */
+function renderInline(text: string): string {
+  // Code spans, images and links are stashed so emphasis can't reach into them
+  const stash: string[] = []
+  const keep = (html: string) => `\u0000${stash.push(html) - 1}\u0000`
+
+  let s = escapeHtml(text)
+    .replace(/`([^`]+)`/g, (_, code: string) => keep(`<code>${code}</code>`))
+    .replace(/!\[([^\]]*)\]\(([^)\s]+)\)/g, (_, alt: string, url: string) =>
+      isSafeUrl(url, SAFE_IMAGE) ? keep(`<img src="${url}" alt="${alt}">`) : alt,
+    )
+    .replace(/\[([^\]]+)\]\(([^)\s]+)\)/g, (_, label: string, url: string) =>
+      isSafeUrl(url, SAFE_LINK) ? keep(`<a href="${url}">${emphasis(label)}</a>`) : label,
+    )
+  s = emphasis(s)
+
+  // Stashed links may contain stashed code spans
+  while (s.includes('\u0000')) {
+    s = s.replace(PLACEHOLDER, (_, i: string) => stash[Number(i)])
+  }
+  return s
+}
+
+function splitRow(line: string): string[] {
+  return line.trim().replace(/^\|/, '').replace(/\|$/, '').split('|').map((c) => c.trim())
+}
+
+function renderBlocks(lines: string[]): string {
+  const out: string[] = []
+  let para: string[] = []
+  const flush = () => {
+    if (para.length) out.push(`<p>${para.map(renderInline).join('<br>')}</p>`)
+    para = []
+  }
+
+  for (let i = 0; i < lines.length; i++) {
+    const line = lines[i]
+
+    const fence = line.match(/^\s*(```|~~~)\s*([\w+-]*)/)
+    if (fence) {
+      flush()
+      const body: string[] = []
+      for (i++; i < lines.length && !lines[i].trim().startsWith(fence[1]); i++) body.push(lines[i])
+      const cls = fence[2] ? ` class="language-${fence[2]}"` : ''
+      out.push(`<pre><code${cls}>${escapeHtml(body.join('\n'))}</code></pre>`)
+      continue
+    }
+
+    if (!line.trim()) {
+      flush()
+      continue
+    }
+
+    const heading = line.match(/^(#{1,6})\s+(.*?)\s*#*\s*$/)
+    if (heading) {
+      flush()
+      const n = heading[1].length
+      out.push(`<h${n}>${renderInline(heading[2])}</h${n}>`)
+      continue
+    }
+
+    if (HR.test(line)) {
+      flush()
+      out.push('<hr>')
+      continue
+    }
+
+    if (/^\s*>/.test(line)) {
+      flush()
+      const quoted: string[] = []
+      for (; i < lines.length && /^\s*>/.test(lines[i]); i++) quoted.push(lines[i].replace(/^\s*>\s?/, ''))
+      i--
+      out.push(`<blockquote>${renderBlocks(quoted)}</blockquote>`)
+      continue
+    }
+
+    const item = line.match(LIST_ITEM)
+    if (item) {
+      flush()
+      const ordered = /\d/.test(item[1])
+      const items: string[] = []
+      for (; i < lines.length; i++) {
+        const m = lines[i].match(LIST_ITEM)
+        if (m && /\d/.test(m[1]) === ordered) items.push(m[2])
+        else if (items.length && /^\s{2,}\S/.test(lines[i])) items[items.length - 1] += ' ' + lines[i].trim()
+        else break
+      }
+      i--
+      const tag = ordered ? 'ol' : 'ul'
+      out.push(`<${tag}>${items.map((t) => `<li>${renderInline(t)}</li>`).join('')}</${tag}>`)
+      continue
+    }
+
+    if (line.includes('|') && lines[i + 1]?.includes('|') && TABLE_SEP.test(lines[i + 1])) {
+      flush()
+      const header = splitRow(line)
+      const rows: string[][] = []
+      for (i += 2; i < lines.length && lines[i].includes('|') && lines[i].trim(); i++) rows.push(splitRow(lines[i]))
+      i--
+      const cells = (r: string[], tag: 'th' | 'td') => r.map((c) => `<${tag}>${renderInline(c)}</${tag}>`).join('')
+      out.push(
+        `<table><thead><tr>${cells(header, 'th')}</tr></thead>` +
+          `<tbody>${rows.map((r) => `<tr>${cells(r, 'td')}</tr>`).join('')}</tbody></table>`,
+      )
+      continue
+    }
+
+    para.push(line)
+  }
+  flush()
+  return out.join('\n')
+}
+
+/** Render agent markdown to sanitized HTML */
+export function renderMarkdown(markdown: string): string {
+  if (!markdown) return ''
+  const lines = markdown.replace(/\r\n?/g, '\n').replace(/\u0000/g, '').split('\n')
+  return sanitizeHtml(renderBlocks(lines))
+}
+
+/** `?render=html` selects server-side rendering; anything else keeps raw markdown */
+export function parseRenderMode(url: string | URL): RenderMode | null {
+  return new URL(url).searchParams.get('render') === 'html' ? 'html' : null
+}
+
+/** Attach `html` to each message when rendering was requested */
+export function withRenderedHtml<T extends { content: string }>(
+  messages: T[],
+  mode: RenderMode | null,
+): (T & { html?: string })[] {
+  if (mode !== 'html') return messages
+  return messages.map((m) => ({ ...m, html: renderMarkdown(m.content) }))
+}
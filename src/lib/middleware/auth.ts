@@ -1,8 +1,14 @@
 import { NextRequest, NextResponse } from 'next/server'
 import { z } from 'zod'
 import { prisma } from '@/lib/db'
+import { stringList } from '@/lib/db-dialect'
 import { verifyAccessToken } from '@/lib/auth/jwt'
-import { hasPermission } from '@/lib/auth/permissions'
+import { effectiveRole, userHasPermission } from '@/lib/auth/permissions'
+import { findActiveBreakGlass } from '@/lib/auth/break-glass'
+import { authenticateApiKey, isApiKey, scopesAllow, type ApiKeyIdentity } from '@/lib/auth/api-keys'
+import { auditLog } from '@/lib/audit'
+import { withDebugLog } from './debug-log'
+import { withTracing } from './tracing'
 import type { AuthUser } from '@/types/auth'
 
 export type RouteParams = Record<string, string | string[]>
@@ -25,6 +31,23 @@ export function paramArray(ctx: AuthContext, key: string): string[] {
   return val ? val.split('/') : []
 }
 
+// All a user who must enrol in MFA can reach until they have
+const MFA_ENROLMENT_PATHS = ['/api/v1/auth/me', '/api/v1/auth/logout', '/api/v1/auth/mfa']
+
+/**
+ * Admin-enforced MFA (lib/auth/mfa): the 403 for a user who has yet to enrol,
+ * outside the enrolment routes; null when the request may go on. Every auth
+ * path calls this, withAuth and the routes that authenticate inline.
+ */
+export function mfaSetupResponse(
+  user: { mfaRequired: boolean; mfaEnabled: boolean },
+  req: NextRequest,
+): NextResponse | null {
+  if (!user.mfaRequired || user.mfaEnabled) return null
+  if (MFA_ENROLMENT_PATHS.some((p) => req.nextUrl.pathname.startsWith(p))) return null
+  return NextResponse.json({ error: '请先启用两步验证', code: 'MFA_SETUP_REQUIRED' }, { status: 403 })
+}
+
 export type AuthHandler = (
   req: NextRequest,
   ctx: AuthContext,
@@ -33,40 +56,62 @@ export type AuthHandler = (
 /**
  * Extract user ID from request headers or JWT token.
  * Used by both `withAuth` wrapper and standalone SSE routes that need
- * inline auth before constructing a streaming response.
+ * inline auth before constructing a streaming response; those must also
+ * check mfaSetupResponse once they have loaded the user.
  */
 export async function resolveRequestUserId(req: NextRequest): Promise<string | null> {
-  const headerUserId = req.headers.get('x-user-id')
-  if (headerUserId) return headerUserId
+  const identity = await resolveRequestIdentity(req)
+  // Scopes are enforced by withPermission, which these routes do not use
+  return identity && !identity.apiKey ? identity.userId : null
+}
 
+/** User ID plus the break-glass session or API key the request carries, if any */
+async function resolveRequestIdentity(
+  req: NextRequest,
+): Promise<{ userId: string; breakGlassId?: string; apiKey?: ApiKeyIdentity } | null> {
   const authHeader = req.headers.get('authorization')
-  const cookieToken = req.cookies.get('access_token')?.value
-  const token = authHeader?.startsWith('Bearer ') ? authHeader.slice(7) : cookieToken
+  const bearer = authHeader?.startsWith('Bearer ') ? authHeader.slice(7) : null
+  if (bearer && isApiKey(bearer)) {
+    const ip = req.headers.get('x-forwarded-for')?.split(',')[0]?.trim() || 'unknown'
+    const apiKey = await authenticateApiKey(bearer, ip)
+    return apiKey ? { userId: apiKey.userId, apiKey } : null
+  }
+
+  const headerUserId = req.headers.get('x-user-id')
+  if (headerUserId) {
+    return { userId: headerUserId, breakGlassId: req.headers.get('x-break-glass') || undefined }
+  }
+
+  const token = bearer ?? req.cookies.get('access_token')?.value
   if (!token) return null
 
   const payload = await verifyAccessToken(token)
-  return payload?.userId ?? null
+  return payload ? { userId: payload.userId, breakGlassId: payload.breakGlassId } : null
 }
 
 /**
  * Wraps a route handler with authentication.
  * Reads user from middleware-injected headers, falling back to JWT verification.
  * Returns a standard Next.js route handler function.
+ * Routes matching DEBUG_LOG_ROUTES also log their redacted request/response,
+ * and every request gets a tracing span (lib/tracing).
  */
 export function withAuth(handler: AuthHandler) {
-  return async (
+  const logged = withDebugLog(handler, (ctx: AuthContext) => ctx.user.id)
+  return withTracing(async (
     req: NextRequest,
     segmentData?: { params?: Promise<RouteParams> },
   ) => {
-    const userId = await resolveRequestUserId(req)
+    const identity = await resolveRequestIdentity(req)
 
-    if (!userId) {
+    if (!identity) {
       return NextResponse.json({ error: '未授权访问' }, { status: 401 })
     }
+    const { userId } = identity
 
     const user = await prisma.user.findUnique({
       where: { id: userId },
-      include: { department: true },
+      include: { department: true, customRole: { select: { id: true, name: true, permissions: true } } },
     })
 
     if (!user || user.status !== 'ACTIVE') {
@@ -77,16 +122,53 @@ export function withAuth(handler: AuthHandler) {
       id: user.id,
       name: user.name,
       email: user.email,
-      role: user.role, // Always use DB role, never trust header
+      role: effectiveRole(user), // Always use DB role, never trust header
       departmentId: user.departmentId,
       departmentName: user.department?.name ?? null,
       avatar: user.avatar,
+      customRole: user.customRole
+        ? { ...user.customRole, permissions: stringList(user.customRole.permissions) }
+        : undefined,
+    }
+
+    // API key: the key's role and scopes, whatever the service account holds
+    if (identity.apiKey) {
+      authUser.role = identity.apiKey.role
+      authUser.apiKey = { id: identity.apiKey.id, scopes: identity.apiKey.scopes }
+      authUser.customRole = undefined
+    }
+
+    // Admin-enforced MFA: nothing else until the user has enrolled
+    const mfaBlocked = mfaSetupResponse(user, req)
+    if (mfaBlocked) return mfaBlocked
+    if (user.mfaRequired && !user.mfaEnabled) authUser.mfaSetupRequired = true
+
+    // Emergency elevation: SYSTEM_ADMIN for this token only, while the session lasts
+    if (identity.breakGlassId) {
+      const session = await findActiveBreakGlass(identity.breakGlassId, user.id)
+      if (!session) {
+        return NextResponse.json({ error: '紧急访问已结束，请重新登录' }, { status: 401 })
+      }
+      authUser.role = 'SYSTEM_ADMIN'
+      authUser.breakGlassId = session.id
+      if (req.method !== 'GET' && req.method !== 'HEAD') {
+        auditLog({
+          userId: user.id,
+          action: 'BREAK_GLASS_REQUEST',
+          resource: 'auth',
+          resourceId: session.id,
+          details: { method: req.method, path: req.nextUrl.pathname },
+          ipAddress: req.headers.get('x-forwarded-for') || 'unknown',
+          userAgent: req.headers.get('user-agent') || undefined,
+          result: 'SUCCESS',
+        })
+      }
     }
 
     const params = segmentData?.params ? await segmentData.params : undefined
 
-    return handler(req, { user: authUser, params })
-  }
+    return logged(req, { user: authUser, params })
+  })
 }
 
 /**
@@ -133,9 +215,12 @@ export function withValidation<T extends z.ZodType>(
  */
 export function withPermission(permission: string, handler: AuthHandler): AuthHandler {
   return async (req: NextRequest, ctx: AuthContext) => {
-    if (!hasPermission(ctx.user.role, permission)) {
+    if (!userHasPermission(ctx.user, permission)) {
       return NextResponse.json({ error: '权限不足' }, { status: 403 })
     }
+    if (ctx.user.apiKey && !scopesAllow(ctx.user.apiKey.scopes, permission)) {
+      return NextResponse.json({ error: 'API 密钥的权限范围不包含此操作' }, { status: 403 })
+    }
     return handler(req, ctx)
   }
 }
@@ -0,0 +1,103 @@
+import { NextRequest, NextResponse } from 'next/server'
+import { createRedactor, redactJson, redactEntries } from '@/lib/utils/redact'
+import { createLogger } from '@/lib/logger'
+import { globToRegExp } from '@/lib/utils/glob'
+
+// ─── Debug request/response logging ──────────────────────────────────
+//
+// DEBUG_LOG_ROUTES        — comma-separated path patterns to log, '*' matches
+//                           any run of characters: "/api/v1/chat/*,/api/v1/instances/*"
+// DEBUG_LOG_REDACT_FIELDS — extra JSON field names to redact on top of the defaults
+//
+// Bodies are only logged when they are JSON, so every field passes through
+// the redactor; anything else is summarised by size.
+
+const MAX_BODY_CHARS = 16_384
+
+const log = createLogger('http')
+
+interface DebugLogConfig {
+  source: string
+  patterns: RegExp[]
+  isSensitive: (field: string) => boolean
+}
+
+let cachedConfig: DebugLogConfig | null = null
+
+function getConfig(): DebugLogConfig {
+  const source = `${process.env.DEBUG_LOG_ROUTES ?? ''}|${process.env.DEBUG_LOG_REDACT_FIELDS ?? ''}`
+  if (cachedConfig?.source === source) return cachedConfig
+
+  const patterns = (process.env.DEBUG_LOG_ROUTES ?? '')
+    .split(',')
+    .map((p) => p.trim())
+    .filter(Boolean)
+    .map(globToRegExp)
+  const extra = (process.env.DEBUG_LOG_REDACT_FIELDS ?? '')
+    .split(',')
+    .map((f) => f.trim())
+    .filter(Boolean)
+
+  cachedConfig = { source, patterns, isSensitive: createRedactor(extra) }
+  return cachedConfig
+}
+
+export function isDebugLogged(pathname: string): boolean {
+  return getConfig().patterns.some((re) => re.test(pathname))
+}
+
+function describeBody(text: string, contentType: string | null, isSensitive: (f: string) => boolean): unknown {
+  if (!text) return null
+  if (!contentType?.includes('application/json')) return `[${contentType ?? 'unknown'} body, ${text.length} chars]`
+  try {
+    const redacted = JSON.stringify(redactJson(JSON.parse(text), isSensitive))
+    return redacted.length > MAX_BODY_CHARS
+      ? redacted.slice(0, MAX_BODY_CHARS) + `…[truncated ${redacted.length - MAX_BODY_CHARS} chars]`
+      : JSON.parse(redacted)
+  } catch {
+    return `[invalid JSON body, ${text.length} chars]`
+  }
+}
+
+/**
+ * Wrap a route handler so matching routes log their redacted request and
+ * response. Non-matching routes run the handler untouched.
+ */
+export function withDebugLog<A extends unknown[]>(
+  handler: (req: NextRequest, ...args: A) => Promise<NextResponse>,
+  getUserId?: (...args: A) => string | undefined,
+) {
+  return async (req: NextRequest, ...args: A): Promise<NextResponse> => {
+    const url = new URL(req.url)
+    if (!isDebugLogged(url.pathname)) return handler(req, ...args)
+
+    const { isSensitive } = getConfig()
+    const started = Date.now()
+    const requestText = await req.clone().text().catch(() => '')
+
+    const res = await handler(req, ...args)
+
+    const resType = res.headers.get('content-type')
+    // Streams must not be buffered; log only their headers
+    const responseText = resType?.includes('text/event-stream') ? '' : await res.clone().text().catch(() => '')
+
+    log.info(`${req.method} ${url.pathname} ${res.status}`, {
+      method: req.method,
+      path: url.pathname,
+      status: res.status,
+      durationMs: Date.now() - started,
+      userId: getUserId?.(...args),
+      request: {
+        query: redactEntries(url.searchParams.entries(), isSensitive),
+        headers: redactEntries(req.headers.entries(), isSensitive),
+        body: describeBody(requestText, req.headers.get('content-type'), isSensitive),
+      },
+      response: {
+        headers: redactEntries(res.headers.entries(), isSensitive),
+        body: describeBody(responseText, resType, isSensitive),
+      },
+    })
+
+    return res
+  }
+}
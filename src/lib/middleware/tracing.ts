@@ -0,0 +1,34 @@
+import { NextRequest } from 'next/server'
+import { parseTraceparent, runInSpan, startSpan } from '@/lib/tracing'
+
+/**
+ * Wraps a route handler in a server span named after the method and path,
+ * continuing the caller's trace when the request carries a traceparent
+ * header. A no-op unless tracing is configured (lib/tracing).
+ */
+export function withTracing<A extends unknown[], R extends Response>(
+  handler: (req: NextRequest, ...rest: A) => Promise<R>,
+) {
+  return async (req: NextRequest, ...rest: A): Promise<R> => {
+    const span = startSpan(`${req.method} ${req.nextUrl.pathname}`, {
+      kind: 'server',
+      parent: parseTraceparent(req.headers.get('traceparent')),
+      attributes: {
+        'http.request.method': req.method,
+        'url.path': req.nextUrl.pathname,
+        'user_agent.original': req.headers.get('user-agent'),
+      },
+    })
+    try {
+      const res = await runInSpan(span, () => handler(req, ...rest))
+      span.setAttribute('http.response.status_code', res.status)
+      if (res.status >= 500) span.setError(`HTTP ${res.status}`)
+      return res
+    } catch (err) {
+      span.setError((err as Error).message)
+      throw err
+    } finally {
+      span.end()
+    }
+  }
+}
@@ -0,0 +1,103 @@
+import { assertDestinationAllowed } from '@/lib/destination-policy'
+import type { NotificationChannelType } from '@/generated/prisma'
+
+export interface ConnectorResult {
+  ok: boolean
+  httpStatus: number | null
+  error?: string
+}
+
+/**
+ * Formats and posts one message to a provider webhook. The body is
+ * already rendered markdown; connectors only wrap it in the provider's
+ * envelope and interpret the provider's success signal.
+ */
+type Connector = (webhookUrl: string, title: string, body: string) => Promise<ConnectorResult>
+
+const TIMEOUT_MS = 10_000
+// WeCom rejects markdown content over 4096 bytes
+const WECOM_MAX_BYTES = 4096
+
+function truncateBytes(text: string, maxBytes: number): string {
+  if (Buffer.byteLength(text) <= maxBytes) return text
+  const suffix = '\n\n…'
+  let out = text
+  while (Buffer.byteLength(out + suffix) > maxBytes) {
+    out = out.slice(0, Math.floor(out.length * 0.9))
+  }
+  return out + suffix
+}
+
+// Redirects are refused: a followed Location would skip the destination check
+async function postJson(url: string, body: unknown): Promise<Response> {
+  await assertDestinationAllowed(url)
+  return fetch(url, {
+    method: 'POST',
+    headers: { 'Content-Type': 'application/json' },
+    body: JSON.stringify(body),
+    redirect: 'error',
+    signal: AbortSignal.timeout(TIMEOUT_MS),
+  })
+}
+
+// Adaptive Card envelope — accepted by both legacy Office 365 connectors
+// and Power Automate "Workflows" webhooks.
+const teams: Connector = async (webhookUrl, title, body) => {
+  const res = await postJson(webhookUrl, {
+    type: 'message',
+    attachments: [
+      {
+        contentType: 'application/vnd.microsoft.card.adaptive',
+        content: {
+          $schema: 'http://adaptivecards.io/schemas/adaptive-card.json',
+          type: 'AdaptiveCard',
+          version: '1.4',
+          body: [
+            { type: 'TextBlock', text: title, weight: 'Bolder', size: 'Medium', wrap: true },
+            { type: 'TextBlock', text: body, wrap: true },
+          ],
+        },
+      },
+    ],
+  })
+  if (!res.ok) {
+    return { ok: false, httpStatus: res.status, error: (await res.text()).slice(0, 500) }
+  }
+  return { ok: true, httpStatus: res.status }
+}
+
+// WeCom group robot: HTTP 200 even on failure, errcode carries the result
+const wecom: Connector = async (webhookUrl, title, body) => {
+  const res = await postJson(webhookUrl, {
+    msgtype: 'markdown',
+    markdown: { content: truncateBytes(`**${title}**\n${body}`, WECOM_MAX_BYTES) },
+  })
+  if (!res.ok) {
+    return { ok: false, httpStatus: res.status, error: (await res.text()).slice(0, 500) }
+  }
+  const data = (await res.json().catch(() => ({}))) as { errcode?: number; errmsg?: string }
+  if (data.errcode !== 0) {
+    return { ok: false, httpStatus: res.status, error: `errcode ${data.errcode}: ${data.errmsg ?? 'unknown'}` }
+  }
+  return { ok: true, httpStatus: res.status }
+}
+
+export const CONNECTORS: Record<NotificationChannelType, Connector> = {
+  TEAMS: teams,
+  WECOM: wecom,
+}
+
+/** Hosts each channel type may post to, so a channel cannot be aimed at internal URLs */
+export const WEBHOOK_HOSTS: Record<NotificationChannelType, RegExp> = {
+  TEAMS: /(^|\.)(webhook\.office\.com|logic\.azure\.com|powerplatform\.com)$/,
+  WECOM: /^qyapi\.weixin\.qq\.com$/,
+}
+
+export function isAllowedWebhookUrl(type: NotificationChannelType, url: string): boolean {
+  try {
+    const parsed = new URL(url)
+    return parsed.protocol === 'https:' && WEBHOOK_HOSTS[type].test(parsed.hostname)
+  } catch {
+    return false
+  }
+}
@@ -0,0 +1,156 @@
+import { prisma } from '@/lib/db'
+import { decrypt } from '@/lib/auth/encryption'
+import { renderTemplate } from '@/lib/utils/template'
+import { CONNECTORS } from './connectors'
+import { activeGrantWhere } from '@/lib/instances/access'
+import { DestinationDeniedError } from '@/lib/destination-policy'
+import type { NotificationChannel, NotificationLog } from '@/generated/prisma'
+
+const MAX_ATTEMPTS = 3
+const RETRY_DELAY_MS = 2_000
+const PREVIEW_LENGTH = 500
+
+const DEFAULT_TEMPLATE = '{{text}}'
+
+export interface NotificationMessage {
+  title: string
+  text: string
+  /** Identifies the trigger in the delivery log, e.g. "scheduled_task:<id>" */
+  source: string
+}
+
+/**
+ * Deliver a message to one channel, retrying transient failures, and record
+ * the outcome in NotificationLog. Never throws — callers (scheduled tasks,
+ * alert rules) read the returned log entry for status.
+ */
+export async function deliverNotification(
+  channel: NotificationChannel & { department?: { name: string } | null },
+  message: NotificationMessage,
+): Promise<NotificationLog> {
+  const body = renderTemplate(channel.template || DEFAULT_TEMPLATE, {
+    title: message.title,
+    text: message.text,
+    source: message.source,
+    department: channel.department?.name ?? '',
+    time: new Date().toISOString(),
+  })
+
+  let attempts = 0
+  let result: { ok: boolean; httpStatus: number | null; error?: string } = {
+    ok: false,
+    httpStatus: null,
+  }
+
+  if (!channel.enabled) {
+    result.error = 'Channel disabled'
+  } else {
+    const connector = CONNECTORS[channel.type]
+    while (attempts < MAX_ATTEMPTS) {
+      attempts++
+      try {
+        result = await connector(decrypt(channel.webhookUrl), message.title, body)
+      } catch (err) {
+        result = { ok: false, httpStatus: null, error: (err as Error).message }
+        // Policy denials will not change on retry
+        if (err instanceof DestinationDeniedError) break
+      }
+      // Retry only network errors, rate limits, and server errors
+      const retryable = result.httpStatus === null || result.httpStatus === 429 || result.httpStatus >= 500
+      if (result.ok || !retryable) break
+      if (attempts < MAX_ATTEMPTS) {
+        await new Promise((r) => setTimeout(r, RETRY_DELAY_MS * attempts))
+      }
+    }
+  }
+
+  return prisma.notificationLog.create({
+    data: {
+      channelId: channel.id,
+      source: message.source,
+      title: message.title,
+      preview: body.slice(0, PREVIEW_LENGTH),
+      status: result.ok ? 'SENT' : 'FAILED',
+      httpStatus: result.httpStatus,
+      error: result.error,
+      attempts: Math.max(attempts, 1),
+    },
+  })
+}
+
+/** Deliver to every enabled channel of a department */
+export async function notifyDepartment(
+  departmentId: string,
+  message: NotificationMessage,
+): Promise<NotificationLog[]> {
+  const channels = await prisma.notificationChannel.findMany({
+    where: { departmentId, enabled: true },
+    include: { department: { select: { name: true } } },
+  })
+  return Promise.all(channels.map((c) => deliverNotification(c, message)))
+}
+
+/** Deliver to every department with access to an instance (instance-level alerts) */
+export async function notifyInstanceDepartments(
+  instanceId: string,
+  message: NotificationMessage,
+): Promise<NotificationLog[]> {
+  const access = await prisma.instanceAccess.findMany({
+    where: { instanceId, ...activeGrantWhere() },
+    select: { departmentId: true },
+  })
+  const departmentIds = [...new Set(access.map((a) => a.departmentId))]
+  const logs = await Promise.all(departmentIds.map((id) => notifyDepartment(id, message)))
+  return logs.flat()
+}
+
+/** Mask the webhook URL: it embeds the credential (WeCom key / Teams signature) */
+export function maskWebhookUrl(encrypted: string): string {
+  try {
+    const url = new URL(decrypt(encrypted))
+    return `${url.protocol}//${url.host}/…`
+  } catch {
+    return '…'
+  }
+}
+
+export function toChannelResponse(
+  c: NotificationChannel & { department?: { name: string } | null },
+) {
+  return {
+    id: c.id,
+    name: c.name,
+    type: c.type,
+    departmentId: c.departmentId,
+    departmentName: c.department?.name ?? null,
+    webhookUrl: maskWebhookUrl(c.webhookUrl),
+    template: c.template,
+    enabled: c.enabled,
+    createdAt: c.createdAt.toISOString(),
+    updatedAt: c.updatedAt.toISOString(),
+  }
+}
+
+export function toNotificationLogResponse(l: NotificationLog) {
+  return {
+    id: l.id,
+    channelId: l.channelId,
+    source: l.source,
+    title: l.title,
+    preview: l.preview,
+    status: l.status,
+    httpStatus: l.httpStatus,
+    error: l.error,
+    attempts: l.attempts,
+    createdAt: l.createdAt.toISOString(),
+  }
+}
+
+/** DEPT_ADMIN may only manage channels of their own department */
+export function canManageDepartmentChannels(
+  user: { role: string; departmentId: string | null },
+  departmentId: string,
+): boolean {
+  if (user.role === 'SYSTEM_ADMIN') return true
+  return user.role === 'DEPT_ADMIN' && user.departmentId === departmentId
+}
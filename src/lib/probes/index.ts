@@ -0,0 +1,256 @@
+import Ajv, { type ValidateFunction } from 'ajv'
+import addFormats from 'ajv-formats'
+import type { SyntheticProbe } from '@/generated/prisma'
+import { prisma } from '@/lib/db'
+import { registry, ensureRegistryInitialized } from '@/lib/gateway/registry'
+import { runThrowawayPrompt } from '@/lib/chat/run'
+import { notifyInstanceDepartments } from '@/lib/notifications'
+import { createLogger } from '@/lib/logger'
+
+// Synthetic probes: a canned prompt sent to an agent on a schedule. A run
+// fails on gateway error, a reply that misses the regex / JSON Schema, or
+// one slower than the latency budget. After `failureThreshold` consecutive
+// failures the instance's departments are notified once, plus a recovery
+// notice on the next pass.
+
+const TICK_INTERVAL_MS = 30_000
+const MAX_CONCURRENT = 3
+const MAX_RUN_TIMEOUT_MS = 10 * 60_000
+
+const log = createLogger('probes')
+
+const globalForProbes = globalThis as unknown as {
+  probeTimer?: ReturnType<typeof setInterval> | null
+  probesInFlight?: Set<string>
+  probeTickRunning?: boolean
+}
+
+const inFlight = (globalForProbes.probesInFlight ??= new Set<string>())
+
+let ajvInstance: Ajv | null = null
+
+function getAjv(): Ajv {
+  if (!ajvInstance) {
+    ajvInstance = new Ajv({ allErrors: true, strict: false })
+    addFormats(ajvInstance)
+  }
+  return ajvInstance
+}
+
+// Keyed by schema text: rows come back as fresh objects on every run,
+// and ajv would otherwise cache a new compiled copy each time.
+const validators = new Map<string, ValidateFunction>()
+
+function compileSchema(schema: unknown): ValidateFunction {
+  const key = JSON.stringify(schema)
+  let validate = validators.get(key)
+  if (!validate) {
+    validate = getAjv().compile(schema as Record<string, unknown>)
+    validators.set(key, validate)
+  }
+  return validate
+}
+
+/** Returns an error message if the schema can't be compiled */
+export function checkProbeSchema(schema: Record<string, unknown>): string | null {
+  try {
+    compileSchema(schema)
+    return null
+  } catch (err) {
+    return `Invalid JSON Schema: ${(err as Error).message}`
+  }
+}
+
+/** Agents often wrap JSON in a fenced code block; accept both forms */
+function parseReplyJson(text: string): unknown {
+  const fenced = text.match(/```(?:json)?\s*([\s\S]*?)```/)
+  return JSON.parse((fenced ? fenced[1] : text).trim())
+}
+
+/** Assertion failures for a reply (empty when it passes) */
+export function checkReply(
+  probe: Pick<SyntheticProbe, 'expectRegex' | 'expectJsonSchema' | 'latencyBudgetMs'>,
+  text: string,
+  durationMs: number,
+): string[] {
+  const errors: string[] = []
+
+  if (!text) errors.push('Empty reply')
+  if (durationMs > probe.latencyBudgetMs) {
+    errors.push(`Latency ${durationMs}ms over budget ${probe.latencyBudgetMs}ms`)
+  }
+  if (probe.expectRegex && !new RegExp(probe.expectRegex).test(text)) {
+    errors.push(`Reply does not match /${probe.expectRegex}/`)
+  }
+  if (probe.expectJsonSchema) {
+    let data: unknown
+    try {
+      data = parseReplyJson(text)
+    } catch {
+      errors.push('Reply is not valid JSON')
+      return errors
+    }
+    const validate = compileSchema(probe.expectJsonSchema)
+    if (!validate(data)) {
+      errors.push(`Reply fails JSON Schema: ${getAjv().errorsText(validate.errors)}`)
+    }
+  }
+  return errors
+}
+
+export interface ProbeRunResult {
+  status: 'PASS' | 'FAIL'
+  latencyMs: number | null
+  errors: string[]
+  reply: string | null
+}
+
+async function executeProbe(probe: SyntheticProbe): Promise<ProbeRunResult> {
+  await ensureRegistryInitialized()
+  const client = registry.getClient(probe.instanceId)
+  const adapter = registry.getAdapter(probe.instanceId)
+  if (!client || !adapter || !client.isConnected()) {
+    return { status: 'FAIL', latencyMs: null, errors: ['Instance not connected'], reply: null }
+  }
+
+  try {
+    // Let slow replies finish so the reported latency is real, not the timeout
+    const { text, durationMs } = await runThrowawayPrompt(client, adapter, probe.agentId, probe.prompt, {
+      timeoutMs: Math.min(probe.latencyBudgetMs * 2, MAX_RUN_TIMEOUT_MS),
+      tag: 'probe',
+    })
+    const errors = checkReply(probe, text, durationMs)
+    return { status: errors.length ? 'FAIL' : 'PASS', latencyMs: durationMs, errors, reply: text }
+  } catch (err) {
+    return { status: 'FAIL', latencyMs: null, errors: [(err as Error).message], reply: null }
+  }
+}
+
+async function alertTransition(probe: SyntheticProbe, result: ProbeRunResult, failures: number): Promise<void> {
+  const instance = await prisma.instance.findUnique({
+    where: { id: probe.instanceId },
+    select: { name: true },
+  })
+  const where = `${probe.agentId} @ ${instance?.name ?? probe.instanceId}`
+  const source = `probe:${probe.id}`
+
+  if (result.status === 'FAIL' && failures === probe.failureThreshold) {
+    log.warn('Probe failing', { probeId: probe.id, failures, errors: result.errors })
+    await notifyInstanceDepartments(probe.instanceId, {
+      title: `[Probe] ${probe.name} failing (${where})`,
+      text: `Synthetic probe "${probe.name}" failed ${failures} times in a row: ${result.errors.join('; ')}`,
+      source,
+    })
+  } else if (result.status === 'PASS' && probe.consecutiveFailures >= probe.failureThreshold) {
+    log.info('Probe recovered', { probeId: probe.id })
+    await notifyInstanceDepartments(probe.instanceId, {
+      title: `[Probe resolved] ${probe.name} (${where})`,
+      text: `Synthetic probe "${probe.name}" is passing again (${result.latencyMs}ms).`,
+      source,
+    })
+  }
+}
+
+/** Run one probe now, persist the outcome and fire alerts on state changes */
+export async function runProbe(probe: SyntheticProbe): Promise<ProbeRunResult> {
+  inFlight.add(probe.id)
+  try {
+    const result = await executeProbe(probe)
+    const failures = result.status === 'FAIL' ? probe.consecutiveFailures + 1 : 0
+
+    await prisma.syntheticProbe.update({
+      where: { id: probe.id },
+      data: {
+        lastRunAt: new Date(),
+        lastStatus: result.status,
+        lastLatencyMs: result.latencyMs,
+        lastError: result.errors.join('; ') || null,
+        consecutiveFailures: failures,
+      },
+    })
+
+    await alertTransition(probe, result, failures).catch((err) =>
+      log.error('Probe alert failed', { probeId: probe.id, err }),
+    )
+    return result
+  } finally {
+    inFlight.delete(probe.id)
+  }
+}
+
+export async function runDueProbes(): Promise<void> {
+  // A slow batch can outlast the tick; don't queue the same probes twice
+  if (globalForProbes.probeTickRunning) return
+  globalForProbes.probeTickRunning = true
+  try {
+    const probes = await prisma.syntheticProbe.findMany({ where: { enabled: true } })
+    const now = Date.now()
+    const due = probes.filter(
+      (p) => !inFlight.has(p.id) && (!p.lastRunAt || p.lastRunAt.getTime() + p.intervalSeconds * 1000 <= now),
+    )
+
+    for (let i = 0; i < due.length; i += MAX_CONCURRENT) {
+      const batch = due.slice(i, i + MAX_CONCURRENT)
+      await Promise.allSettled(
+        batch.map((p) => runProbe(p).catch((err) => log.error('Probe run failed', { probeId: p.id, err }))),
+      )
+    }
+  } finally {
+    globalForProbes.probeTickRunning = false
+  }
+}
+
+/** Start the probe scheduler (idempotent across hot reloads) */
+export function startProbeScheduler(): void {
+  if (globalForProbes.probeTimer) return
+  globalForProbes.probeTimer = setInterval(() => {
+    runDueProbes().catch((err) => log.error('Probe scheduling failed', { err }))
+  }, TICK_INTERVAL_MS)
+}
+
+export function toProbeResponse(p: SyntheticProbe & { instance?: { name: string } | null }) {
+  return {
+    id: p.id,
+    name: p.name,
+    instanceId: p.instanceId,
+    instanceName: p.instance?.name ?? null,
+    agentId: p.agentId,
+    prompt: p.prompt,
+    expectRegex: p.expectRegex,
+    expectJsonSchema: p.expectJsonSchema,
+    latencyBudgetMs: p.latencyBudgetMs,
+    intervalSeconds: p.intervalSeconds,
+    failureThreshold: p.failureThreshold,
+    enabled: p.enabled,
+    lastRunAt: p.lastRunAt?.toISOString() ?? null,
+    lastStatus: p.lastStatus,
+    lastLatencyMs: p.lastLatencyMs,
+    lastError: p.lastError,
+    consecutiveFailures: p.consecutiveFailures,
+    createdAt: p.createdAt.toISOString(),
+  }
+}
+
+/** Prometheus gauges for the latest run of each enabled probe */
+export function probesToPrometheus(
+  probes: (SyntheticProbe & { instance: { name: string } })[],
+): string {
+  const lines = [
+    '# HELP teamclaw_probe_success Whether the last synthetic probe run passed',
+    '# TYPE teamclaw_probe_success gauge',
+  ]
+  const latency = [
+    '# HELP teamclaw_probe_latency_ms Reply latency of the last synthetic probe run',
+    '# TYPE teamclaw_probe_latency_ms gauge',
+  ]
+  const clean = (v: string) => v.replace(/["\\\n]/g, '_')
+
+  for (const p of probes) {
+    if (!p.lastStatus) continue
+    const label =
+      `probe="${clean(p.name)}",probe_id="${p.id}",instance="${clean(p.instance.name)}",agent="${clean(p.agentId)}"`
+    lines.push(`teamclaw_probe_success{${label}} ${p.lastStatus === 'PASS' ? 1 : 0}`)
+    if (p.lastLatencyMs !== null) latency.push(`teamclaw_probe_latency_ms{${label}} ${p.lastLatencyMs}`)
+  }
+  return [...lines, ...latency].join('\n') + '\n'
+}
@@ -0,0 +1,87 @@
+import { getProvider } from './providers'
+import { decryptCredential } from './credential-utils'
+import { resolveTestUrl } from './test-connection'
+import { assertDestinationAllowed } from '@/lib/destination-policy'
+import type { Resource } from '@/generated/prisma'
+import type { ResourceConfig } from '@/types/resource'
+
+// One-shot completions against a MODEL resource, for TeamClaw's own use
+// (e.g. scoring eval replies) rather than through an agent. Only the
+// Anthropic Messages and OpenAI Chat Completions APIs are spoken; the chat
+// endpoint is derived from the provider's test endpoint, so the resource's
+// baseUrl applies the same way.
+
+const DEFAULT_TIMEOUT_MS = 60_000
+
+type CompletionResource = Pick<Resource, 'provider' | 'credentials' | 'config'>
+
+function chatEndpoint(resource: CompletionResource): { url: string; apiType: string; model: string | null } {
+  const providerDef = getProvider(resource.provider)
+  if (!providerDef) throw new Error(`Unknown provider: ${resource.provider}`)
+
+  const config = resource.config as ResourceConfig | null
+  const apiType = config?.apiType || providerDef.apiType || ''
+  const testUrl = resolveTestUrl(providerDef, config)
+  if (!testUrl) throw new Error('The resource has no API base URL')
+
+  let url: string | null = null
+  if (apiType === 'anthropic-messages' && /\/v1\/messages$/.test(testUrl)) {
+    url = testUrl
+  } else if (apiType === 'openai-completions') {
+    const chat = testUrl.replace(/\/models$/, '/chat/completions')
+    if (chat.endsWith('/chat/completions')) url = chat
+  }
+  if (!url) throw new Error(`Completions are not supported for ${providerDef.name} (${apiType || 'unknown API'})`)
+
+  const model = config?.models?.[0]?.id ?? providerDef.defaultModels?.[0]?.id ?? null
+  return { url, apiType, model }
+}
+
+/** Send one user message and return the reply text */
+export async function completeWithResource(
+  resource: CompletionResource,
+  prompt: string,
+  opts?: { model?: string | null; maxTokens?: number; timeoutMs?: number },
+): Promise<string> {
+  const { url, apiType, model: defaultModel } = chatEndpoint(resource)
+  const model = opts?.model || defaultModel
+  if (!model) throw new Error('No model configured for the resource')
+
+  await assertDestinationAllowed(url)
+
+  const providerDef = getProvider(resource.provider)!
+  const apiKey = decryptCredential(resource.credentials)
+  const headers: Record<string, string> = {
+    ...providerDef.testEndpoint.headers(apiKey),
+    ...((resource.config as ResourceConfig | null)?.headers ?? {}),
+    'Content-Type': 'application/json',
+  }
+  const body = {
+    model,
+    max_tokens: opts?.maxTokens ?? 1024,
+    messages: [{ role: 'user', content: prompt }],
+  }
+
+  const response = await fetch(url, {
+    method: 'POST',
+    headers,
+    body: JSON.stringify(body),
+    redirect: 'manual',
+    signal: AbortSignal.timeout(opts?.timeoutMs ?? DEFAULT_TIMEOUT_MS),
+  })
+  const data = (await response.json().catch(() => null)) as Record<string, unknown> | null
+  if (!response.ok) {
+    const error = data?.error as { message?: string } | string | undefined
+    throw new Error((typeof error === 'string' ? error : error?.message) || `HTTP ${response.status}`)
+  }
+
+  if (apiType === 'anthropic-messages') {
+    const content = (data?.content ?? []) as { type?: string; text?: string }[]
+    return content
+      .filter((b) => b.type === 'text' && typeof b.text === 'string')
+      .map((b) => b.text)
+      .join('')
+  }
+  const choices = (data?.choices ?? []) as { message?: { content?: string } }[]
+  return choices[0]?.message?.content ?? ''
+}
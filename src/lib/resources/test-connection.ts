@@ -1,6 +1,7 @@
 import { getProvider, type ProviderDef } from './providers'
 import { decryptCredential } from './credential-utils'
 import { isKnownMultimodal } from './model-capabilities'
+import { assertDestinationAllowed } from '@/lib/destination-policy'
 import type { TestConnectionResult, ResourceConfig, DetectedModelInfo } from '@/types/resource'
 
 const TEST_TIMEOUT_MS = 10_000
@@ -35,20 +36,12 @@ async function executeTest(
   config?: ResourceConfig | null,
 ): Promise<TestConnectionResult> {
   const { testEndpoint } = providerDef
-  const baseUrl = config?.baseUrl || providerDef.baseUrl || ''
 
-  // Resolve URL
-  let url: string
-  if (typeof testEndpoint.url === 'function') {
-    if (!baseUrl) {
-      return { ok: false, latencyMs: 0, error: '需要提供 API 地址 (baseUrl)' }
-    }
-    url = testEndpoint.url(baseUrl)
-  } else if (config?.baseUrl && providerDef.baseUrl && config.baseUrl !== providerDef.baseUrl) {
-    url = testEndpoint.url.replace(providerDef.baseUrl, config.baseUrl)
-  } else {
-    url = testEndpoint.url
+  const resolved = resolveTestUrl(providerDef, config)
+  if (!resolved) {
+    return { ok: false, latencyMs: 0, error: '需要提供 API 地址 (baseUrl)' }
   }
+  let url = resolved
 
   // Google uses query param auth
   if (providerDef.id === 'google') {
@@ -63,6 +56,14 @@ async function executeTest(
     headers['Content-Type'] = 'application/json'
   }
 
+  // baseUrl is admin-entered: apply the outbound policy, and don't follow
+  // redirects to hosts it never saw
+  try {
+    await assertDestinationAllowed(url)
+  } catch (err) {
+    return { ok: false, latencyMs: 0, error: err instanceof Error ? err.message : 'API 地址无效' }
+  }
+
   const start = Date.now()
 
   const controller = new AbortController()
@@ -73,6 +74,7 @@ async function executeTest(
       method: testEndpoint.method,
       headers,
       body,
+      redirect: 'manual',
       signal: controller.signal,
     })
 
@@ -120,6 +122,23 @@ async function executeTest(
   }
 }
 
+/**
+ * The provider's test endpoint with the resource's baseUrl applied; null when
+ * the provider needs a baseUrl and none is configured.
+ */
+export function resolveTestUrl(providerDef: ProviderDef, config?: ResourceConfig | null): string | null {
+  const { url } = providerDef.testEndpoint
+  const baseUrl = config?.baseUrl || providerDef.baseUrl || ''
+
+  if (typeof url === 'function') {
+    return baseUrl ? url(baseUrl) : null
+  }
+  if (config?.baseUrl && providerDef.baseUrl && config.baseUrl !== providerDef.baseUrl) {
+    return url.replace(providerDef.baseUrl, config.baseUrl)
+  }
+  return url
+}
+
 /**
  * Billing/quota errors indicate the API key is valid but the account
  * has no credits. This is a successful connection test.
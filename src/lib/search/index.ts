@@ -0,0 +1,208 @@
+import { prisma } from '@/lib/db'
+import { containsInsensitive, listHas, stringList } from '@/lib/db-dialect'
+import { userHasPermission } from '@/lib/auth/permissions'
+import { isAgentVisible } from '@/lib/agents/helpers'
+import { isSkillVisible } from '@/lib/skills/permissions'
+import { getUserInstanceIds } from '@/lib/instances/access'
+import type { AuthUser } from '@/types/auth'
+import type { SearchResult, SearchResultType } from '@/types/search'
+
+export const SEARCH_TYPES: SearchResultType[] = [
+  'user',
+  'department',
+  'instance',
+  'agent',
+  'skill',
+  'session',
+]
+
+/**
+ * Score how well `value` matches `query` (both compared case-insensitively).
+ * Exact > prefix > word prefix > substring; `weight` ranks primary fields
+ * (names) above secondary ones (descriptions, emails).
+ */
+export function scoreMatch(value: string | null | undefined, query: string, weight = 1): number {
+  if (!value) return 0
+  const v = value.toLowerCase()
+  const q = query.toLowerCase()
+  let base = 0
+  if (v === q) base = 100
+  else if (v.startsWith(q)) base = 75
+  else if (new RegExp(`[\\s_\\-./@]${q.replace(/[.*+?^${}()|[\]\\]/g, '\\$&')}`).test(v)) base = 50
+  else if (v.includes(q)) base = 25
+  return base * weight
+}
+
+function best(...scores: number[]): number {
+  return Math.max(0, ...scores)
+}
+
+type Searcher = (q: string, user: AuthUser, limit: number) => Promise<SearchResult[]>
+
+const searchUsers: Searcher = async (q, user, limit) => {
+  if (!userHasPermission(user, 'users:list')) return []
+  const users = await prisma.user.findMany({
+    where: {
+      ...(user.role === 'DEPT_ADMIN' ? { departmentId: user.departmentId } : {}),
+      OR: [
+        { name: containsInsensitive(q) },
+        { email: containsInsensitive(q) },
+      ],
+    },
+    select: { id: true, name: true, email: true },
+    take: limit,
+  })
+  return users.map((u) => ({
+    type: 'user',
+    id: u.id,
+    title: u.name,
+    subtitle: u.email,
+    href: `/users?search=${encodeURIComponent(u.email)}`,
+    score: best(scoreMatch(u.name, q), scoreMatch(u.email, q, 0.8)),
+  }))
+}
+
+const searchDepartments: Searcher = async (q, user, limit) => {
+  if (!userHasPermission(user, 'departments:view')) return []
+  const departments = await prisma.department.findMany({
+    where: {
+      ...(user.role === 'DEPT_ADMIN' ? { id: user.departmentId ?? '' } : {}),
+      OR: [
+        { name: containsInsensitive(q) },
+        { description: containsInsensitive(q) },
+      ],
+    },
+    select: { id: true, name: true, description: true },
+    take: limit,
+  })
+  return departments.map((d) => ({
+    type: 'department',
+    id: d.id,
+    title: d.name,
+    subtitle: d.description,
+    href: `/departments?id=${d.id}`,
+    score: best(scoreMatch(d.name, q), scoreMatch(d.description, q, 0.5)),
+  }))
+}
+
+const searchInstances: Searcher = async (q, user, limit) => {
+  if (!userHasPermission(user, 'instances:view')) return []
+  const instances = await prisma.instance.findMany({
+    where: {
+      OR: [
+        { name: containsInsensitive(q) },
+        { description: containsInsensitive(q) },
+      ],
+    },
+    select: { id: true, name: true, description: true, status: true },
+    take: limit,
+  })
+  return instances.map((i) => ({
+    type: 'instance',
+    id: i.id,
+    title: i.name,
+    subtitle: i.description ?? i.status,
+    href: `/instances?search=${encodeURIComponent(i.name)}`,
+    score: best(scoreMatch(i.name, q), scoreMatch(i.description, q, 0.5)),
+  }))
+}
+
+const searchAgents: Searcher = async (q, user, limit) => {
+  if (!userHasPermission(user, 'agents:view')) return []
+
+  // Non-admins only see agents on instances granted to them or their department
+  let instanceFilter: { instanceId?: { in: string[] } } = {}
+  if (user.role !== 'SYSTEM_ADMIN') {
+    instanceFilter = { instanceId: { in: await getUserInstanceIds(user) } }
+  }
+
+  const metas = await prisma.agentMeta.findMany({
+    where: { ...instanceFilter, agentId: containsInsensitive(q) },
+    include: { instance: { select: { name: true } } },
+    take: limit * 3,
+  })
+  return metas
+    .filter((m) => isAgentVisible(m, user))
+    .slice(0, limit)
+    .map((m) => ({
+      type: 'agent',
+      id: m.id,
+      title: m.agentId,
+      subtitle: m.instance.name,
+      href: `/agents?instanceId=${m.instanceId}&agentId=${encodeURIComponent(m.agentId)}`,
+      score: scoreMatch(m.agentId, q),
+    }))
+}
+
+const searchSkills: Searcher = async (q, user, limit) => {
+  const skills = await prisma.skill.findMany({
+    where: {
+      OR: [
+        { name: containsInsensitive(q) },
+        { slug: containsInsensitive(q) },
+        { description: containsInsensitive(q) },
+        { tags: listHas(q.toLowerCase()) },
+      ],
+    },
+    include: { departments: { select: { id: true } } },
+    take: limit * 3,
+  })
+  return skills
+    .filter((s) => isSkillVisible(s, user))
+    .slice(0, limit)
+    .map((s) => ({
+      type: 'skill',
+      id: s.id,
+      title: s.name,
+      subtitle: s.slug,
+      href: `/skills/${s.id}`,
+      score: best(
+        scoreMatch(s.name, q),
+        scoreMatch(s.slug, q, 0.9),
+        scoreMatch(s.description, q, 0.4),
+        stringList(s.tags).includes(q.toLowerCase()) ? 60 : 0,
+      ),
+    }))
+}
+
+const searchSessions: Searcher = async (q, user, limit) => {
+  if (!userHasPermission(user, 'chat:use')) return []
+  const sessions = await prisma.chatSession.findMany({
+    where: { userId: user.id, title: containsInsensitive(q) },
+    include: { instance: { select: { name: true } } },
+    orderBy: { lastMessageAt: 'desc' },
+    take: limit,
+  })
+  return sessions.map((s) => ({
+    type: 'session',
+    id: s.id,
+    title: s.title ?? s.agentId,
+    subtitle: `${s.instance.name} / ${s.agentId}`,
+    href: `/chat?sessionId=${s.id}`,
+    score: scoreMatch(s.title, q),
+  }))
+}
+
+const SEARCHERS: Record<SearchResultType, Searcher> = {
+  user: searchUsers,
+  department: searchDepartments,
+  instance: searchInstances,
+  agent: searchAgents,
+  skill: searchSkills,
+  session: searchSessions,
+}
+
+/** Search every requested type the user can access, ranked by relevance */
+export async function searchAll(
+  q: string,
+  user: AuthUser,
+  opts: { types?: SearchResultType[]; limit?: number } = {},
+): Promise<SearchResult[]> {
+  const types = opts.types?.length ? opts.types : SEARCH_TYPES
+  const limit = opts.limit ?? 10
+
+  const settled = await Promise.allSettled(types.map((t) => SEARCHERS[t](q, user, limit)))
+  const results = settled.flatMap((r) => (r.status === 'fulfilled' ? r.value : []))
+
+  return results.sort((a, b) => b.score - a.score || a.title.localeCompare(b.title))
+}
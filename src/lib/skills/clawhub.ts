@@ -2,12 +2,16 @@ import { execFile } from 'child_process'
 import { promisify } from 'util'
 import { readFile, mkdir, writeFile } from 'fs/promises'
 import { dirname, join } from 'path'
+import { createLogger } from '@/lib/logger'
+import { registerCache } from '@/lib/caches'
 import type { ClawHubSearchResult } from '@/types/skill'
 
 const execFileAsync = promisify(execFile)
 
 const CLAWHUB_REGISTRY_URL = process.env.CLAWHUB_REGISTRY_URL || 'https://clawhub.ai'
 
+const log = createLogger('clawhub')
+
 /* ─── Retry-aware fetch ─────────────────────────────────── */
 
 function sleep(ms: number): Promise<void> {
@@ -38,7 +42,7 @@ async function fetchWithRetry(
         const retryAfter = parseInt(res.headers.get('retry-after') || '', 10)
         // Cap wait to 10s — don't block the request for minutes
         const waitMs = Math.min((retryAfter > 0 ? retryAfter : 3) * 1000, 10_000)
-        console.warn(`[clawhub] 429 rate-limited, retrying after ${waitMs}ms (attempt ${attempt + 1})`)
+        log.warn('Rate-limited, retrying', { waitMs, attempt: attempt + 1 })
         await sleep(waitMs)
         continue
       }
@@ -46,7 +50,7 @@ async function fetchWithRetry(
       // 5xx server error — retry with backoff
       if (res.status >= 500 && attempt < maxRetries) {
         const waitMs = 2000 * Math.pow(2, attempt)
-        console.warn(`[clawhub] ${res.status} server error, retrying after ${waitMs}ms (attempt ${attempt + 1})`)
+        log.warn('Server error, retrying', { status: res.status, waitMs, attempt: attempt + 1 })
         await sleep(waitMs)
         continue
       }
@@ -56,7 +60,7 @@ async function fetchWithRetry(
       lastError = err as Error
       if (attempt < maxRetries) {
         const waitMs = 2000 * Math.pow(2, attempt)
-        console.warn(`[clawhub] fetch error: ${(err as Error).message}, retrying after ${waitMs}ms (attempt ${attempt + 1})`)
+        log.warn('Fetch error, retrying', { err, waitMs, attempt: attempt + 1 })
         await sleep(waitMs)
         continue
       }
@@ -71,9 +75,22 @@ async function fetchWithRetry(
 const infoCache = new Map<string, { data: ClawHubSkillInfo; ts: number }>()
 const INFO_CACHE_TTL = 5 * 60 * 1000 // 5 minutes
 
+const infoCounter = registerCache('clawhub-info', {
+  description: 'ClawHub skill info by slug (5min TTL)',
+  keys: () => [...infoCache.keys()],
+  clear: (slug) => {
+    if (slug) infoCache.delete(slug)
+    else infoCache.clear()
+  },
+})
+
 function getCachedInfo(slug: string): ClawHubSkillInfo | null {
   const entry = infoCache.get(slug)
-  if (entry && Date.now() - entry.ts < INFO_CACHE_TTL) return entry.data
+  if (entry && Date.now() - entry.ts < INFO_CACHE_TTL) {
+    infoCounter.hit()
+    return entry.data
+  }
+  infoCounter.miss()
   if (entry) infoCache.delete(slug)
   return null
 }
@@ -211,7 +228,7 @@ export async function searchClawHub(query: string): Promise<ClawHubSearchResult[
       }
     }
   } catch (err) {
-    console.error('[clawhub:search] HTTP API failed:', (err as Error).message)
+    log.error('Search HTTP API failed', { err, slug })
   }
 
   // Fallback: CLI
@@ -222,7 +239,7 @@ export async function searchClawHub(query: string): Promise<ClawHubSearchResult[
     })
     return parseSearchOutput(stdout)
   } catch (err) {
-    console.error('[clawhub:search] CLI failed:', (err as Error).message)
+    log.error('Search CLI failed', { err, slug })
     return []
   }
 }
@@ -258,7 +275,7 @@ export async function pullClawHubSkill(
       return { name: slug, version: '1.0.0' }
     }
   } catch (cliErr) {
-    console.error('[clawhub:install] CLI failed, trying HTTP fallback:', (cliErr as Error).message)
+    log.warn('Install CLI failed, trying HTTP fallback', { err: cliErr, slug })
   }
 
   // Brief pause before HTTP fallback — CLI failure may have been due to rate limiting
@@ -268,7 +285,7 @@ export async function pullClawHubSkill(
   try {
     return await pullViaHttpApi(slug, targetDir)
   } catch (err) {
-    console.error('[clawhub:install] HTTP fallback failed:', (err as Error).message)
+    log.error('Install HTTP fallback failed', { err, slug })
     return null
   }
 }
@@ -354,7 +371,7 @@ async function pullViaHttpApi(
     }
 
     if (!downloaded) {
-      console.error(`[clawhub:pull] Failed to download ${file.path}, skipping`)
+      log.error('Failed to download file, skipping', { slug, path: file.path })
     }
   }
 
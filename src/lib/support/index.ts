@@ -0,0 +1,154 @@
+import { createHmac } from 'crypto'
+import { prisma } from '@/lib/db'
+import { registry } from '@/lib/gateway/registry'
+import { assertDestinationAllowed } from '@/lib/destination-policy'
+import { createLogger } from '@/lib/logger'
+import type { SupportTicket } from '@/generated/prisma'
+import type { SupportTicketDiagnostics, SupportTicketResponse } from '@/types/support'
+
+// Support tickets: "report a problem with this response" from chat. A ticket
+// carries a copy of the response and a snapshot of the instance and session
+// state, and is assigned to the admin who owns (created) the instance; they
+// see it in their sync notifications. SYSTEM_ADMINs can work every ticket.
+//
+// SUPPORT_TICKET_WEBHOOK_URL     — also POST each new ticket as JSON there
+//                                  (Jira automation, a helpdesk, ...)
+// SUPPORT_TICKET_WEBHOOK_SECRET  — signs the body: x-teamclaw-signature:
+//                                  sha256=<hex HMAC>, as inbound integrations
+//                                  expect it
+
+const WEBHOOK_TIMEOUT_MS = 10_000
+const RECENT_TOOL_FAILURES = 10
+
+const log = createLogger('support')
+
+/** The admin a ticket about this instance goes to: its creator, while active */
+export async function ticketAssignee(instanceId: string): Promise<string | null> {
+  const instance = await prisma.instance.findUnique({
+    where: { id: instanceId },
+    select: { createdBy: { select: { id: true, status: true } } },
+  })
+  return instance?.createdBy.status === 'ACTIVE' ? instance.createdBy.id : null
+}
+
+/** Instance and session state, as of now */
+export async function collectDiagnostics(
+  instanceId: string,
+  chatSessionId: string,
+): Promise<SupportTicketDiagnostics> {
+  const [instance, session, runs, toolFailures] = await Promise.all([
+    prisma.instance.findUnique({
+      where: { id: instanceId },
+      select: { name: true, status: true, version: true, region: true, lastHealthCheck: true, healthData: true },
+    }),
+    prisma.chatSession.findUnique({
+      where: { id: chatSessionId },
+      select: { sessionId: true, isActive: true, messageCount: true, lastMessageAt: true },
+    }),
+    prisma.chatRun.count({ where: { chatSessionId } }),
+    prisma.toolInvocation.findMany({
+      where: { chatSessionId, status: { in: ['ERROR', 'BLOCKED', 'DENIED', 'ABORTED'] } },
+      select: { name: true, status: true, startedAt: true },
+      orderBy: { startedAt: 'desc' },
+      take: RECENT_TOOL_FAILURES,
+    }),
+  ])
+
+  return {
+    instance: {
+      name: instance?.name ?? null,
+      status: instance?.status ?? null,
+      version: instance?.version ?? null,
+      region: instance?.region ?? null,
+      connected: !!registry.getClient(instanceId),
+      lastHealthCheck: instance?.lastHealthCheck?.toISOString() ?? null,
+      health: instance?.healthData ?? null,
+    },
+    session: {
+      sessionKey: session?.sessionId ?? null,
+      isActive: session?.isActive ?? false,
+      messageCount: session?.messageCount ?? 0,
+      lastMessageAt: session?.lastMessageAt?.toISOString() ?? null,
+      runsInFlight: runs,
+    },
+    recentToolFailures: toolFailures.map((t) => ({
+      name: t.name,
+      status: t.status,
+      at: t.startedAt.toISOString(),
+    })),
+    collectedAt: new Date().toISOString(),
+  }
+}
+
+type TicketWithNames = SupportTicket & {
+  reporter?: { name: string } | null
+  assignee?: { name: string } | null
+  instance?: { name: string } | null
+}
+
+export function toTicketResponse(t: TicketWithNames): SupportTicketResponse {
+  return {
+    id: t.id,
+    reporterId: t.reporterId,
+    reporterName: t.reporter?.name ?? null,
+    chatSessionId: t.chatSessionId,
+    instanceId: t.instanceId,
+    instanceName: t.instance?.name ?? null,
+    agentId: t.agentId,
+    messageId: t.messageId,
+    messageContent: t.messageContent,
+    category: t.category,
+    description: t.description,
+    diagnostics: t.diagnostics as unknown as SupportTicketDiagnostics,
+    status: t.status,
+    assigneeId: t.assigneeId,
+    assigneeName: t.assignee?.name ?? null,
+    externalStatus: t.externalStatus,
+    resolution: t.resolution,
+    resolvedAt: t.resolvedAt?.toISOString() ?? null,
+    createdAt: t.createdAt.toISOString(),
+  }
+}
+
+export const TICKET_INCLUDE = {
+  reporter: { select: { name: true } },
+  assignee: { select: { name: true } },
+  instance: { select: { name: true } },
+}
+
+/**
+ * POST a new ticket to SUPPORT_TICKET_WEBHOOK_URL, when set, and record the
+ * response status on it. Never throws.
+ */
+export async function forwardTicket(ticket: SupportTicketResponse): Promise<void> {
+  const url = process.env.SUPPORT_TICKET_WEBHOOK_URL
+  if (!url) return
+
+  const body = JSON.stringify({ event: 'support_ticket.created', ticket })
+  const headers: Record<string, string> = { 'Content-Type': 'application/json' }
+  const secret = process.env.SUPPORT_TICKET_WEBHOOK_SECRET
+  if (secret) {
+    headers['x-teamclaw-signature'] = 'sha256=' + createHmac('sha256', secret).update(body).digest('hex')
+  }
+
+  let status: number | null = null
+  try {
+    await assertDestinationAllowed(url)
+    // Redirects are refused: a followed Location would skip the destination check
+    const res = await fetch(url, {
+      method: 'POST',
+      headers,
+      body,
+      redirect: 'error',
+      signal: AbortSignal.timeout(WEBHOOK_TIMEOUT_MS),
+    })
+    status = res.status
+    if (!res.ok) log.warn('Ticket webhook refused', { ticketId: ticket.id, status })
+  } catch (err) {
+    log.warn('Ticket webhook failed', { ticketId: ticket.id, error: (err as Error).message })
+  }
+
+  await prisma.supportTicket
+    .update({ where: { id: ticket.id }, data: { externalStatus: status ?? 0 } })
+    .catch(() => {})
+}
@@ -0,0 +1,288 @@
+import { prisma } from '@/lib/db'
+import type { Prisma } from '@/generated/prisma'
+import { userHasPermission } from '@/lib/auth/permissions'
+import { expireStaleApprovals } from '@/lib/approvals'
+import { listChatAgents } from '@/lib/chat/agents'
+import { decryptSnapshots } from '@/lib/chat/snapshot-crypto'
+import { getLiveMessages } from '@/lib/chat/live-messages'
+import { JOIN_REQUEST_INCLUDE, reviewableWhere } from '@/lib/users/join-requests'
+import type { AuthUser } from '@/types/auth'
+import type { ChatContentBlock, ChatSessionResponse, ChatToolCall } from '@/types/chat'
+import type { SyncApprovalNotification, SyncMessage, SyncNotification, SyncResponse } from '@/types/sync'
+
+// Delta sync for mobile/offline clients: everything that changed since a
+// cursor in one call. Items can repeat across calls (the cursor overlaps a
+// little to cover in-flight writes), so clients upsert by id.
+
+const MAX_MESSAGES = 500
+const CURSOR_OVERLAP_MS = 2_000
+const FULL_SYNC_NOTIFICATION_DAYS = 30
+
+export function encodeCursor(at: Date): string {
+  return Buffer.from(String(at.getTime())).toString('base64url')
+}
+
+/** Returns null for malformed cursors */
+export function decodeCursor(cursor: string): Date | null {
+  const ms = Number(Buffer.from(cursor, 'base64url').toString())
+  return Number.isFinite(ms) && ms > 0 ? new Date(ms) : null
+}
+
+function toSessionResponse(
+  r: Prisma.ChatSessionGetPayload<{ include: { instance: { select: { name: true } } } }>,
+): ChatSessionResponse {
+  return {
+    id: r.id,
+    sessionId: r.sessionId,
+    instanceId: r.instanceId,
+    instanceName: r.instance.name,
+    agentId: r.agentId,
+    title: r.title,
+    lastMessageAt: r.lastMessageAt?.toISOString() ?? null,
+    messageCount: r.messageCount,
+    isActive: r.isActive,
+    closedReason: r.closedReason,
+    createdAt: r.createdAt.toISOString(),
+  }
+}
+
+/**
+ * Archived messages after `since`, oldest first, capped at MAX_MESSAGES.
+ * Snapshot batches share a createdAt, so a page never splits a timestamp:
+ * the cursor for the next page is the last timestamp fully included.
+ */
+async function loadMessages(
+  userId: string,
+  since: Date | null,
+): Promise<{ messages: SyncMessage[]; nextCursor: Date | null }> {
+  const where: Prisma.ChatMessageSnapshotWhereInput = {
+    chatSession: { userId },
+    ...(since ? { createdAt: { gt: since } } : {}),
+  }
+  const orderBy = [{ createdAt: 'asc' as const }, { orderIndex: 'asc' as const }]
+
+  let rows = await prisma.chatMessageSnapshot.findMany({ where, orderBy, take: MAX_MESSAGES + 1 })
+  let nextCursor: Date | null = null
+
+  if (rows.length > MAX_MESSAGES) {
+    const cut = rows[MAX_MESSAGES].createdAt
+    const kept = rows.filter((r) => r.createdAt < cut)
+    if (kept.length > 0) {
+      rows = kept
+      nextCursor = kept[kept.length - 1].createdAt
+    } else {
+      // One timestamp holds more than a page: send all of it
+      rows = await prisma.chatMessageSnapshot.findMany({ where: { ...where, createdAt: cut }, orderBy })
+      nextCursor = cut
+    }
+  }
+
+  const messages = (await decryptSnapshots(rows)).map((row) => ({
+    id: row.id,
+    chatSessionId: row.chatSessionId,
+    batchId: row.batchId,
+    role: row.role as 'user' | 'assistant',
+    content: row.content,
+    ...(row.contentBlocks ? { contentBlocks: row.contentBlocks as unknown as ChatContentBlock[] } : {}),
+    ...(row.thinking ? { thinking: row.thinking } : {}),
+    ...(row.toolCalls ? { toolCalls: row.toolCalls as unknown as ChatToolCall[] } : {}),
+    createdAt: row.createdAt.toISOString(),
+  }))
+  return { messages, nextCursor }
+}
+
+/** Has anything that shapes the user's agent list changed since `since`? */
+async function agentsChanged(user: AuthUser, since: Date): Promise<boolean> {
+  const changed = { updatedAt: { gt: since } }
+  const counts = await Promise.all([
+    prisma.agentMeta.count({ where: changed }),
+    user.departmentId
+      ? prisma.instanceAccess.count({ where: { departmentId: user.departmentId, ...changed } })
+      : Promise.resolve(0),
+    prisma.userInstanceAccess.count({ where: { userId: user.id, ...changed } }),
+    user.departmentId
+      ? prisma.department.count({ where: { id: user.departmentId, ...changed } })
+      : Promise.resolve(0),
+  ])
+  return counts.some((c) => c > 0)
+}
+
+async function loadNotifications(user: AuthUser, since: Date | null): Promise<SyncNotification[]> {
+  await expireStaleApprovals()
+
+  const include = {
+    requestedBy: { select: { name: true } },
+    reviewedBy: { select: { name: true } },
+  }
+  const after = since ?? new Date(Date.now() - FULL_SYNC_NOTIFICATION_DAYS * 86400000)
+  const reviewableJoins = userHasPermission(user, 'departments:review_joins') ? reviewableWhere(user) : null
+  const [own, pending, closed, tickets, joinRequests] = await Promise.all([
+    prisma.approvalRequest.findMany({
+      where: {
+        requestedById: user.id,
+        status: { not: 'PENDING' },
+        updatedAt: { gt: after },
+      },
+      include,
+      orderBy: { updatedAt: 'desc' },
+      take: 100,
+    }),
+    userHasPermission(user, 'approvals:review')
+      ? prisma.approvalRequest.findMany({
+          where: { status: 'PENDING', ...(since ? { createdAt: { gt: since } } : {}) },
+          include,
+          orderBy: { createdAt: 'desc' },
+          take: 100,
+        })
+      : Promise.resolve([]),
+    prisma.chatSession.findMany({
+      where: { userId: user.id, closedAt: { gt: after } },
+      include: { instance: { select: { name: true } } },
+      orderBy: { closedAt: 'desc' },
+      take: 100,
+    }),
+    prisma.supportTicket.findMany({
+      where: {
+        OR: [
+          { assigneeId: user.id, createdAt: { gt: after } },
+          { reporterId: user.id, resolvedAt: { gt: after } },
+        ],
+      },
+      include: { instance: { select: { name: true } }, reporter: { select: { name: true } } },
+      orderBy: { createdAt: 'desc' },
+      take: 100,
+    }),
+    prisma.departmentJoinRequest.findMany({
+      where: {
+        OR: [
+          { userId: user.id, status: { in: ['APPROVED', 'REJECTED'] }, reviewedAt: { gt: after } },
+          ...(reviewableJoins
+            ? [{ ...reviewableJoins, status: 'PENDING' as const, ...(since ? { createdAt: { gt: since } } : {}) }]
+            : []),
+        ],
+      },
+      include: JOIN_REQUEST_INCLUDE,
+      orderBy: { createdAt: 'desc' },
+      take: 100,
+    }),
+  ])
+
+  const toNotification = (
+    type: SyncApprovalNotification['type'],
+    a: (typeof own)[number],
+    at: Date,
+  ): SyncNotification => ({
+    id: `${type}:${a.id}:${at.getTime()}`,
+    type,
+    at: at.toISOString(),
+    approvalId: a.id,
+    action: a.action,
+    resource: a.resource,
+    resourceId: a.resourceId,
+    status: a.status,
+    requestedByName: a.requestedBy?.name ?? null,
+    reviewedByName: a.reviewedBy?.name ?? null,
+    reviewComment: a.reviewComment,
+  })
+
+  return [
+    ...own.map((a) => toNotification('approval_update', a, a.updatedAt)),
+    ...pending.map((a) => toNotification('approval_pending', a, a.createdAt)),
+    ...closed.map((s): SyncNotification => ({
+      id: `session_closed:${s.id}:${s.closedAt!.getTime()}`,
+      type: 'session_closed',
+      at: s.closedAt!.toISOString(),
+      chatSessionId: s.id,
+      instanceName: s.instance.name,
+      agentId: s.agentId,
+      title: s.title,
+      reason: s.closedReason ?? 'closed',
+    })),
+    ...tickets.flatMap((t): SyncNotification[] => {
+      const base = {
+        ticketId: t.id,
+        instanceName: t.instance.name,
+        agentId: t.agentId,
+        category: t.category,
+        reporterName: t.reporter.name,
+        resolution: t.resolution,
+      }
+      const out: SyncNotification[] = []
+      if (t.assigneeId === user.id && t.createdAt > after) {
+        out.push({
+          id: `support_ticket_assigned:${t.id}`,
+          type: 'support_ticket_assigned',
+          at: t.createdAt.toISOString(),
+          ...base,
+        })
+      }
+      if (t.reporterId === user.id && t.resolvedAt && t.resolvedAt > after) {
+        out.push({
+          id: `support_ticket_resolved:${t.id}:${t.resolvedAt.getTime()}`,
+          type: 'support_ticket_resolved',
+          at: t.resolvedAt.toISOString(),
+          ...base,
+        })
+      }
+      return out
+    }),
+    ...joinRequests.map((r): SyncNotification => {
+      const mine = r.userId === user.id && r.reviewedAt
+      const at = mine ? r.reviewedAt! : r.createdAt
+      const type = mine ? 'join_request_update' : 'join_request_pending'
+      return {
+        id: `${type}:${r.id}:${at.getTime()}`,
+        type,
+        at: at.toISOString(),
+        requestId: r.id,
+        departmentName: r.department.name,
+        userName: r.user.name,
+        status: r.status,
+        reviewedByName: r.reviewedBy?.name ?? null,
+        reviewComment: r.reviewComment,
+      }
+    }),
+  ].sort((a, b) => b.at.localeCompare(a.at))
+}
+
+export async function buildSyncResponse(user: AuthUser, since: Date | null): Promise<SyncResponse> {
+  const startedAt = Date.now()
+  const loadAgents = async () =>
+    !since || (await agentsChanged(user, since)) ? listChatAgents(user) : null
+  const sessionWhere: Prisma.ChatSessionWhereInput = {
+    userId: user.id,
+    ...(since ? { updatedAt: { gt: since } } : {}),
+  }
+
+  const [sessionRows, allSessions, { messages, nextCursor }, agents, notifications] = await Promise.all([
+    prisma.chatSession.findMany({
+      where: sessionWhere,
+      include: { instance: { select: { name: true } } },
+      orderBy: { updatedAt: 'desc' },
+    }),
+    prisma.chatSession.findMany({ where: { userId: user.id }, select: { id: true } }),
+    loadMessages(user.id, since),
+    loadAgents(),
+    loadNotifications(user, since),
+  ])
+
+  const liveMessages = await Promise.all(
+    sessionRows
+      .filter((r) => r.isActive)
+      .map(async (r) => ({ chatSessionId: r.id, messages: await getLiveMessages(r.id) })),
+  ).then((all) => all.filter((l) => l.messages.length > 0))
+
+  const cursor = nextCursor ?? new Date(Math.max(since?.getTime() ?? 0, startedAt - CURSOR_OVERLAP_MS))
+
+  return {
+    cursor: encodeCursor(cursor),
+    hasMore: nextCursor !== null,
+    full: !since,
+    sessions: sessionRows.map(toSessionResponse),
+    sessionIds: allSessions.map((s) => s.id),
+    messages,
+    liveMessages,
+    agents,
+    notifications,
+  }
+}
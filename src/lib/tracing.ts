@@ -0,0 +1,286 @@
+import { AsyncLocalStorage } from 'async_hooks'
+import { randomBytes } from 'crypto'
+
+// Distributed tracing, exported as OTLP/HTTP JSON so any OpenTelemetry
+// collector (Jaeger, Tempo, Honeycomb, ...) can take the spans. API requests
+// open a server span (lib/middleware/tracing), chat sends a run span, and
+// every gateway request a client span under whatever span is current, so a
+// chat send reads end to end: HTTP handler → WS request → response.
+//
+// Configured with the standard OpenTelemetry variables:
+//
+//   OTEL_EXPORTER_OTLP_TRACES_ENDPOINT — full traces URL, e.g.
+//                                        http://otel-collector:4318/v1/traces
+//   OTEL_EXPORTER_OTLP_ENDPOINT        — or the collector base URL; /v1/traces
+//                                        is appended
+//   OTEL_EXPORTER_OTLP_HEADERS         — "key=value,..." sent with each export
+//   OTEL_SERVICE_NAME                  — service.name (default teamclaw)
+//   OTEL_TRACES_SAMPLER_ARG            — ratio of new traces to record (default 1);
+//                                        incoming traceparent flags win
+//
+// Tracing is off (spans are no-ops) unless an endpoint is set.
+
+const MAX_BATCH = 256
+const FLUSH_MS = 5_000
+const MAX_QUEUE = 4_096
+
+export type SpanKind = 'internal' | 'server' | 'client'
+
+// OTLP SpanKind enum values
+const KIND_CODES: Record<SpanKind, number> = { internal: 1, server: 2, client: 3 }
+
+type AttributeValue = string | number | boolean
+
+export interface SpanContext {
+  traceId: string
+  spanId: string
+  sampled: boolean
+}
+
+export interface Span {
+  readonly context: SpanContext
+  setAttribute(key: string, value: AttributeValue | null | undefined): void
+  /** Mark the span failed */
+  setError(message: string): void
+  end(): void
+}
+
+interface TracingConfig {
+  endpoint: string
+  headers: Record<string, string>
+  serviceName: string
+  sampleRatio: number
+}
+
+interface FinishedSpan {
+  context: SpanContext
+  parentSpanId: string | null
+  name: string
+  kind: SpanKind
+  startNs: bigint
+  endNs: bigint
+  attributes: Record<string, AttributeValue>
+  error: string | null
+}
+
+const globalForTracing = globalThis as unknown as {
+  tracingConfig?: TracingConfig | null
+  tracingStorage?: AsyncLocalStorage<SpanContext>
+  tracingQueue?: FinishedSpan[]
+  tracingTimer?: ReturnType<typeof setTimeout> | null
+}
+
+const storage = (globalForTracing.tracingStorage ??= new AsyncLocalStorage<SpanContext>())
+const queue = (globalForTracing.tracingQueue ??= [])
+
+function parseHeaders(raw: string): Record<string, string> {
+  const headers: Record<string, string> = {}
+  for (const pair of raw.split(',')) {
+    const eq = pair.indexOf('=')
+    if (eq > 0) headers[decodeURIComponent(pair.slice(0, eq).trim())] = decodeURIComponent(pair.slice(eq + 1).trim())
+  }
+  return headers
+}
+
+function loadConfig(): TracingConfig | null {
+  const base = process.env.OTEL_EXPORTER_OTLP_ENDPOINT?.trim().replace(/\/+$/, '')
+  const endpoint = process.env.OTEL_EXPORTER_OTLP_TRACES_ENDPOINT?.trim() || (base ? `${base}/v1/traces` : '')
+  if (!endpoint) return null
+  const ratio = parseFloat(process.env.OTEL_TRACES_SAMPLER_ARG ?? '')
+  return {
+    endpoint,
+    headers: parseHeaders(process.env.OTEL_EXPORTER_OTLP_HEADERS ?? ''),
+    serviceName: process.env.OTEL_SERVICE_NAME?.trim() || 'teamclaw',
+    sampleRatio: Number.isFinite(ratio) ? Math.min(1, Math.max(0, ratio)) : 1,
+  }
+}
+
+function getConfig(): TracingConfig | null {
+  if (globalForTracing.tracingConfig === undefined) globalForTracing.tracingConfig = loadConfig()
+  return globalForTracing.tracingConfig
+}
+
+/** Read the exporter configuration. Called once at startup. */
+export function initTracing(): void {
+  globalForTracing.tracingConfig = loadConfig()
+  const config = globalForTracing.tracingConfig
+  if (config) {
+    process.stdout.write(`[tracing] exporting spans to ${config.endpoint} as ${config.serviceName}\n`)
+  }
+}
+
+export function isTracingEnabled(): boolean {
+  return getConfig() !== null
+}
+
+// ─── W3C trace context ──────────────────────────────────────────────
+
+const TRACEPARENT_RE = /^00-([0-9a-f]{32})-([0-9a-f]{16})-([0-9a-f]{2})$/
+
+export function parseTraceparent(header: string | null | undefined): SpanContext | null {
+  const m = header?.trim().toLowerCase().match(TRACEPARENT_RE)
+  if (!m || /^0+$/.test(m[1]) || /^0+$/.test(m[2])) return null
+  return { traceId: m[1], spanId: m[2], sampled: (parseInt(m[3], 16) & 1) === 1 }
+}
+
+export function formatTraceparent(ctx: SpanContext): string {
+  return `00-${ctx.traceId}-${ctx.spanId}-${ctx.sampled ? '01' : '00'}`
+}
+
+/** traceparent of the current span, for passing the trace on to another service */
+export function currentTraceparent(): string | null {
+  const ctx = storage.getStore()
+  return ctx ? formatTraceparent(ctx) : null
+}
+
+// ─── Spans ──────────────────────────────────────────────────────────
+
+const NOOP_SPAN: Span = {
+  context: { traceId: '0'.repeat(32), spanId: '0'.repeat(16), sampled: false },
+  setAttribute() {},
+  setError() {},
+  end() {},
+}
+
+/**
+ * Start a span under `parent`, or under the current span when no parent is
+ * given. The span is not made current; use runInSpan for that.
+ */
+export function startSpan(
+  name: string,
+  opts?: { kind?: SpanKind; parent?: SpanContext | null; attributes?: Record<string, AttributeValue | null | undefined> },
+): Span {
+  const config = getConfig()
+  if (!config) return NOOP_SPAN
+
+  const parent = opts?.parent ?? storage.getStore() ?? null
+  const context: SpanContext = {
+    traceId: parent?.traceId ?? randomBytes(16).toString('hex'),
+    spanId: randomBytes(8).toString('hex'),
+    sampled: parent ? parent.sampled : Math.random() < config.sampleRatio,
+  }
+  const kind = opts?.kind ?? 'internal'
+  const startNs = process.hrtime.bigint()
+  const startEpochNs = BigInt(Date.now()) * BigInt(1_000_000)
+  const attributes: Record<string, AttributeValue> = {}
+  let error: string | null = null
+  let ended = false
+
+  const span: Span = {
+    context,
+    setAttribute(key, value) {
+      if (value !== null && value !== undefined) attributes[key] = value
+    },
+    setError(message) {
+      error = message
+    },
+    end() {
+      if (ended) return
+      ended = true
+      if (!context.sampled) return
+      const endNs = startEpochNs + (process.hrtime.bigint() - startNs)
+      enqueue({
+        context,
+        parentSpanId: parent?.spanId ?? null,
+        name,
+        kind,
+        startNs: startEpochNs,
+        endNs,
+        attributes,
+        error,
+      })
+    },
+  }
+  for (const [key, value] of Object.entries(opts?.attributes ?? {})) span.setAttribute(key, value)
+  return span
+}
+
+/** Run `fn` with `span` as the current span */
+export function runInSpan<T>(span: Span, fn: () => T): T {
+  return span === NOOP_SPAN ? fn() : storage.run(span.context, fn)
+}
+
+/** Run `fn` in a new span that ends (failed, if `fn` throws) when it settles */
+export async function withSpan<T>(
+  name: string,
+  fn: (span: Span) => Promise<T>,
+  opts?: Parameters<typeof startSpan>[1],
+): Promise<T> {
+  const span = startSpan(name, opts)
+  try {
+    return await runInSpan(span, () => fn(span))
+  } catch (err) {
+    span.setError((err as Error).message)
+    throw err
+  } finally {
+    span.end()
+  }
+}
+
+// ─── OTLP export ────────────────────────────────────────────────────
+
+function enqueue(span: FinishedSpan): void {
+  if (queue.length >= MAX_QUEUE) return
+  queue.push(span)
+  if (queue.length >= MAX_BATCH) {
+    if (globalForTracing.tracingTimer) clearTimeout(globalForTracing.tracingTimer)
+    void flush()
+  } else if (!globalForTracing.tracingTimer) {
+    globalForTracing.tracingTimer = setTimeout(flush, FLUSH_MS)
+    globalForTracing.tracingTimer.unref?.()
+  }
+}
+
+function toAnyValue(value: AttributeValue) {
+  if (typeof value === 'string') return { stringValue: value }
+  if (typeof value === 'boolean') return { boolValue: value }
+  return Number.isInteger(value) ? { intValue: String(value) } : { doubleValue: value }
+}
+
+function toOtlpSpan(s: FinishedSpan) {
+  return {
+    traceId: s.context.traceId,
+    spanId: s.context.spanId,
+    ...(s.parentSpanId ? { parentSpanId: s.parentSpanId } : {}),
+    name: s.name,
+    kind: KIND_CODES[s.kind],
+    startTimeUnixNano: s.startNs.toString(),
+    endTimeUnixNano: s.endNs.toString(),
+    attributes: Object.entries(s.attributes).map(([key, value]) => ({ key, value: toAnyValue(value) })),
+    // STATUS_CODE_ERROR = 2
+    ...(s.error !== null ? { status: { code: 2, message: s.error } } : {}),
+  }
+}
+
+async function flush(): Promise<void> {
+  globalForTracing.tracingTimer = null
+  const config = getConfig()
+  const batch = queue.splice(0, MAX_BATCH)
+  if (!config || batch.length === 0) return
+
+  const body = {
+    resourceSpans: [
+      {
+        resource: { attributes: [{ key: 'service.name', value: { stringValue: config.serviceName } }] },
+        scopeSpans: [{ scope: { name: 'teamclaw' }, spans: batch.map(toOtlpSpan) }],
+      },
+    ],
+  }
+
+  try {
+    const res = await fetch(config.endpoint, {
+      method: 'POST',
+      headers: { 'Content-Type': 'application/json', ...config.headers },
+      body: JSON.stringify(body),
+      signal: AbortSignal.timeout(10_000),
+    })
+    if (!res.ok) process.stderr.write(`[tracing] span export failed: HTTP ${res.status}\n`)
+  } catch (err) {
+    process.stderr.write(`[tracing] span export failed: ${(err as Error).message}\n`)
+  }
+
+  if (queue.length > 0 && !globalForTracing.tracingTimer) {
+    globalForTracing.tracingTimer = setTimeout(flush, FLUSH_MS)
+    globalForTracing.tracingTimer.unref?.()
+  }
+}
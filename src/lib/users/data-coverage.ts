@@ -0,0 +1,77 @@
+/**
+ * How data subject requests (lib/users/data-rights) treat every model that
+ * points at a user. scripts/check-user-data.ts fails CI when a model with a
+ * User relation or user ID column is missing here, so a new model cannot
+ * hold personal data without a decision on export and erasure.
+ *
+ *   export  — included in the user's data export
+ *   erase   — deleted or stripped of personal fields on erasure
+ *   cascade — goes with a parent that is exported / erased (named in note)
+ *   keep    — an organisational record that only references the user; the
+ *             reference then points at the pseudonymized user row
+ */
+export type UserDataHandling = 'export+erase' | 'export' | 'erase' | 'cascade' | 'keep'
+
+export const USER_DATA_COVERAGE: Record<
+  string,
+  { handling: UserDataHandling; note: string }
+> = {
+  AuditLog: { handling: 'export+erase', note: 'exported; IP and user agent pseudonymized' },
+  ChatSession: { handling: 'export+erase', note: 'exported with messages; deleted' },
+  ChatRun: { handling: 'cascade', note: 'deleted with the chat session' },
+  ToolInvocation: { handling: 'cascade', note: 'exported and deleted with the chat session' },
+  SessionShare: { handling: 'cascade', note: 'deleted with the chat session' },
+  RefreshToken: { handling: 'erase', note: 'revoked' },
+  MfaRecoveryCode: { handling: 'erase', note: 'deleted' },
+  AgentMeta: {
+    handling: 'export+erase',
+    note: 'owned agents exported, then released; createdBy kept',
+  },
+  Skill: { handling: 'export', note: 'created skills exported; kept as a shared asset' },
+  SupportTicket: {
+    handling: 'export+erase',
+    note: 'reported tickets exported, content cleared; assignee / resolver kept',
+  },
+  DepartmentJoinRequest: {
+    handling: 'export+erase',
+    note: 'own requests exported and deleted; reviewer kept',
+  },
+  UserPreference: { handling: 'export+erase', note: 'exported; deleted' },
+  OnboardingStep: { handling: 'export+erase', note: 'exported; deleted' },
+  StoredFile: { handling: 'export+erase', note: 'metadata exported; blobs and rows deleted' },
+  SavedFilter: { handling: 'export+erase', note: 'exported; deleted' },
+  ExportJob: { handling: 'erase', note: 'deleted with their files' },
+  BreakGlassSession: {
+    handling: 'export+erase',
+    note: 'exported; justification and IP cleared, the session record stays',
+  },
+  ApiKey: {
+    handling: 'erase',
+    note: 'a service account key is revoked and its last-used IP cleared; created keys kept',
+  },
+  ApprovalRequest: { handling: 'keep', note: 'requester / reviewer of an administrative action' },
+  Instance: { handling: 'keep', note: 'creator' },
+  InstanceAccess: { handling: 'keep', note: 'granter' },
+  UserInstanceAccess: {
+    handling: 'erase',
+    note: 'grants to the user deleted; grants made by the user kept',
+  },
+  InstanceDelegation: { handling: 'keep', note: 'granter' },
+  SkillVersion: { handling: 'keep', note: 'publisher' },
+  SkillInstallation: { handling: 'keep', note: 'installer' },
+  Resource: { handling: 'keep', note: 'creator' },
+  IntegrationEndpoint: { handling: 'keep', note: 'creator / service account' },
+  NotificationChannel: { handling: 'keep', note: 'creator' },
+  ChatWidget: { handling: 'keep', note: 'creator / service account' },
+  SyntheticProbe: { handling: 'keep', note: 'creator' },
+  InstanceAnnouncement: { handling: 'keep', note: 'author' },
+  AgentCanary: { handling: 'keep', note: 'creator' },
+  Experiment: { handling: 'keep', note: 'creator' },
+  AgentPreambleVersion: { handling: 'keep', note: 'author' },
+  EvalSet: { handling: 'keep', note: 'creator' },
+  EvalRun: { handling: 'keep', note: 'starter' },
+  AccessReviewCampaign: { handling: 'keep', note: 'creator' },
+  AccessReviewItem: { handling: 'keep', note: 'reviewer decision' },
+  CustomRole: { handling: 'keep', note: 'creator' },
+  EgressPolicy: { handling: 'keep', note: 'last editor' },
+}
@@ -0,0 +1,371 @@
+import { randomBytes } from 'crypto'
+import { createGzip } from 'zlib'
+import tar from 'tar-stream'
+import { prisma } from '@/lib/db'
+import { decryptSnapshots } from '@/lib/chat/snapshot-crypto'
+import { getLiveMessages } from '@/lib/chat/live-messages'
+import { toToolInvocationEntries } from '@/lib/chat/tool-invocations'
+import { hashPassword } from '@/lib/auth/password'
+import { deleteUserFiles } from '@/lib/files'
+import { withRenderedHtml, type RenderMode } from '@/lib/markdown'
+
+/**
+ * Data subject rights: export everything we hold about a user, and erase
+ * it in a way that keeps the audit trail intact. What happens to each model
+ * that references a user is listed in ./data-coverage, which CI checks
+ * against the schema.
+ */
+
+export interface UserDataExport {
+  exportedAt: string
+  profile: Record<string, unknown>
+  sessions: Record<string, unknown>[]
+  agents: Record<string, unknown>[]
+  skills: Record<string, unknown>[]
+  auditEntries: Record<string, unknown>[]
+  logins: Record<string, unknown>[]
+  supportTickets: Record<string, unknown>[]
+  joinRequests: Record<string, unknown>[]
+  preferences: Record<string, unknown>[]
+  onboardingSteps: Record<string, unknown>[]
+  files: Record<string, unknown>[]
+  savedFilters: Record<string, unknown>[]
+  breakGlassSessions: Record<string, unknown>[]
+}
+
+export async function collectUserData(
+  userId: string,
+  render: RenderMode | null = null,
+): Promise<UserDataExport | null> {
+  const user = await prisma.user.findUnique({
+    where: { id: userId },
+    select: {
+      id: true,
+      email: true,
+      name: true,
+      avatar: true,
+      role: true,
+      status: true,
+      department: { select: { id: true, name: true } },
+      lastLoginAt: true,
+      createdAt: true,
+      updatedAt: true,
+    },
+  })
+  if (!user) return null
+
+  const [
+    sessions,
+    agents,
+    skills,
+    auditEntries,
+    logins,
+    supportTickets,
+    joinRequests,
+    preferences,
+    onboardingSteps,
+    files,
+    savedFilters,
+    breakGlassSessions,
+  ] = await Promise.all([
+    prisma.chatSession.findMany({
+      where: { userId },
+      include: {
+        instance: { select: { name: true } },
+        snapshots: {
+          orderBy: [{ batchId: 'asc' }, { orderIndex: 'asc' }],
+          select: {
+            batchId: true,
+            orderIndex: true,
+            role: true,
+            content: true,
+            thinking: true,
+            toolCalls: true,
+            dataKeyId: true,
+            createdAt: true,
+          },
+        },
+        toolInvocations: { orderBy: { startedAt: 'asc' } },
+      },
+      orderBy: { createdAt: 'asc' },
+    }),
+    prisma.agentMeta.findMany({
+      where: { ownerId: userId },
+      select: { instanceId: true, agentId: true, category: true, createdAt: true },
+    }),
+    prisma.skill.findMany({
+      where: { creatorId: userId },
+      select: { slug: true, name: true, description: true, category: true, version: true, createdAt: true },
+    }),
+    prisma.auditLog.findMany({
+      where: { userId },
+      select: {
+        action: true,
+        resource: true,
+        resourceId: true,
+        ipAddress: true,
+        userAgent: true,
+        result: true,
+        createdAt: true,
+      },
+      orderBy: { createdAt: 'asc' },
+    }),
+    // Login history as shown in the profile: with method and failure reason
+    prisma.auditLog.findMany({
+      where: { userId, action: 'LOGIN' },
+      select: { ipAddress: true, userAgent: true, result: true, details: true, createdAt: true },
+      orderBy: { createdAt: 'asc' },
+    }),
+    prisma.supportTicket.findMany({
+      where: { reporterId: userId },
+      select: {
+        id: true,
+        instance: { select: { name: true } },
+        agentId: true,
+        messageContent: true,
+        category: true,
+        description: true,
+        status: true,
+        resolution: true,
+        resolvedAt: true,
+        createdAt: true,
+      },
+      orderBy: { createdAt: 'asc' },
+    }),
+    prisma.departmentJoinRequest.findMany({
+      where: { userId },
+      select: {
+        department: { select: { name: true } },
+        message: true,
+        status: true,
+        reviewComment: true,
+        reviewedAt: true,
+        createdAt: true,
+      },
+      orderBy: { createdAt: 'asc' },
+    }),
+    prisma.userPreference.findMany({
+      where: { userId },
+      select: { key: true, value: true, updatedAt: true },
+    }),
+    prisma.onboardingStep.findMany({
+      where: { userId },
+      select: { step: true, completedAt: true },
+    }),
+    // Metadata only; the archive would otherwise grow without bound
+    prisma.storedFile.findMany({
+      where: { userId },
+      select: { id: true, name: true, mimeType: true, size: true, sha256: true, createdAt: true },
+      orderBy: { createdAt: 'asc' },
+    }),
+    prisma.savedFilter.findMany({
+      where: { ownerId: userId },
+      select: { name: true, endpoint: true, query: true, shared: true, createdAt: true },
+    }),
+    prisma.breakGlassSession.findMany({
+      where: { userId },
+      select: {
+        justification: true,
+        ipAddress: true,
+        expiresAt: true,
+        endedAt: true,
+        createdAt: true,
+      },
+      orderBy: { createdAt: 'asc' },
+    }),
+  ])
+
+  return {
+    exportedAt: new Date().toISOString(),
+    profile: user,
+    sessions: await Promise.all(
+      sessions.map(async (s) => ({
+        id: s.id,
+        instanceName: s.instance.name,
+        agentId: s.agentId,
+        title: s.title,
+        isActive: s.isActive,
+        createdAt: s.createdAt,
+        lastMessageAt: s.lastMessageAt,
+        liveMessages: await getLiveMessages(s.id),
+        snapshots: withRenderedHtml(
+          (await decryptSnapshots(s.snapshots)).map((m) => ({
+            batchId: m.batchId,
+            orderIndex: m.orderIndex,
+            role: m.role,
+            content: m.content,
+            thinking: m.thinking,
+            toolCalls: m.toolCalls,
+            createdAt: m.createdAt,
+          })),
+          render,
+        ),
+        toolInvocations: await toToolInvocationEntries(s.toolInvocations),
+      })),
+    ),
+    agents,
+    skills,
+    auditEntries,
+    logins,
+    supportTickets: supportTickets.map(({ instance, ...t }) => ({
+      ...t,
+      instanceName: instance.name,
+    })),
+    joinRequests: joinRequests.map(({ department, ...r }) => ({
+      ...r,
+      departmentName: department.name,
+    })),
+    preferences,
+    onboardingSteps,
+    files,
+    savedFilters,
+    breakGlassSessions,
+  }
+}
+
+/** Package an export as a .tar.gz with one JSON file per section */
+export async function buildDataExportArchive(data: UserDataExport): Promise<Buffer> {
+  const pack = tar.pack()
+  const sections: Record<string, unknown> = {
+    'profile.json': data.profile,
+    'sessions.json': data.sessions,
+    'agents.json': data.agents,
+    'skills.json': data.skills,
+    'audit-log.json': data.auditEntries,
+    'logins.json': data.logins,
+    'support-tickets.json': data.supportTickets,
+    'join-requests.json': data.joinRequests,
+    'preferences.json': data.preferences,
+    'onboarding.json': data.onboardingSteps,
+    'files.json': data.files,
+    'saved-filters.json': data.savedFilters,
+    'break-glass.json': data.breakGlassSessions,
+  }
+  const files: Record<string, unknown> = {
+    'manifest.json': {
+      exportedAt: data.exportedAt,
+      userId: data.profile.id,
+      files: Object.keys(sections),
+    },
+    ...sections,
+  }
+  for (const [name, content] of Object.entries(files)) {
+    pack.entry({ name, mtime: new Date(data.exportedAt) }, JSON.stringify(content, null, 2))
+  }
+  pack.finalize()
+
+  const gzip = createGzip()
+  const chunks: Buffer[] = []
+  return new Promise<Buffer>((resolve, reject) => {
+    gzip.on('data', (chunk: Buffer) => chunks.push(chunk))
+    gzip.on('end', () => resolve(Buffer.concat(chunks)))
+    gzip.on('error', reject)
+    pack.on('error', reject)
+    pack.pipe(gzip)
+  })
+}
+
+export interface ErasureReport {
+  userId: string
+  sessionsDeleted: number
+  filesDeleted: number
+  refreshTokensRevoked: number
+  agentsReleased: number
+  auditEntriesPseudonymized: number
+  supportTicketsRedacted: number
+  apiKeysRevoked: number
+  savedFiltersDeleted: number
+  exportJobsDeleted: number
+  breakGlassSessionsRedacted: number
+}
+
+/**
+ * Erase a user's personal data.
+ *
+ * Chat content is deleted outright, along with the copies kept elsewhere:
+ * support tickets the user reported stay in the queue with their message,
+ * description and diagnostics cleared, and join request messages,
+ * preferences, saved filters and export jobs are deleted. Rows other records
+ * depend on (the user itself, audit entries, break-glass sessions) are kept
+ * but stripped of identifying fields, so retention obligations and
+ * referential integrity both survive erasure. A service account's API key
+ * is revoked.
+ */
+export async function eraseUserData(userId: string): Promise<ErasureReport> {
+  const placeholderPassword = await hashPassword(randomBytes(32).toString('hex'))
+  // Blobs live outside the database, so they go first
+  const filesDeleted = await deleteUserFiles(userId)
+
+  return prisma.$transaction(async (tx) => {
+    const sessions = await tx.chatSession.deleteMany({ where: { userId } })
+    const tokens = await tx.refreshToken.deleteMany({ where: { userId } })
+
+    // Personal agents stay on the instance, but no longer point at the user
+    const agents = await tx.agentMeta.updateMany({
+      where: { ownerId: userId },
+      data: { ownerId: null },
+    })
+
+    const audit = await tx.auditLog.updateMany({
+      where: { userId },
+      data: { ipAddress: 'erased', userAgent: null },
+    })
+
+    await tx.mfaRecoveryCode.deleteMany({ where: { userId } })
+
+    const tickets = await tx.supportTicket.updateMany({
+      where: { reporterId: userId },
+      data: { messageContent: '', description: null, diagnostics: {} },
+    })
+    await tx.departmentJoinRequest.deleteMany({ where: { userId } })
+    await tx.userPreference.deleteMany({ where: { userId } })
+    await tx.onboardingStep.deleteMany({ where: { userId } })
+    await tx.userInstanceAccess.deleteMany({ where: { userId } })
+    const filters = await tx.savedFilter.deleteMany({ where: { ownerId: userId } })
+    // Export files are stored under the user, so their blobs went with deleteUserFiles
+    const exportJobs = await tx.exportJob.deleteMany({ where: { createdById: userId } })
+
+    // The emergency access record stays for the security review, minus the free text
+    const breakGlass = await tx.breakGlassSession.updateMany({
+      where: { userId },
+      data: { justification: '', ipAddress: 'erased' },
+    })
+
+    const apiKeys = await tx.apiKey.updateMany({
+      where: { serviceAccountId: userId, revokedAt: null },
+      data: { revokedAt: new Date() },
+    })
+    await tx.apiKey.updateMany({ where: { serviceAccountId: userId }, data: { lastUsedIp: null } })
+
+    await tx.user.update({
+      where: { id: userId },
+      data: {
+        email: `erased-${userId}@erased.invalid`,
+        name: 'Erased User',
+        avatar: null,
+        passwordHash: placeholderPassword,
+        oidcSubject: null,
+        ldapDn: null,
+        mfaSecret: null,
+        mfaEnabled: false,
+        mfaLastStep: null,
+        status: 'DISABLED',
+        departmentId: null,
+      },
+    })
+
+    return {
+      userId,
+      sessionsDeleted: sessions.count,
+      filesDeleted,
+      refreshTokensRevoked: tokens.count,
+      agentsReleased: agents.count,
+      auditEntriesPseudonymized: audit.count,
+      supportTicketsRedacted: tickets.count,
+      apiKeysRevoked: apiKeys.count,
+      savedFiltersDeleted: filters.count,
+      exportJobsDeleted: exportJobs.count,
+      breakGlassSessionsRedacted: breakGlass.count,
+    }
+  })
+}
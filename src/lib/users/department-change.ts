@@ -0,0 +1,85 @@
+import { prisma } from '@/lib/db'
+import { registry, ensureRegistryInitialized } from '@/lib/gateway/registry'
+import { archiveSession, archiveStoredMessages } from '@/lib/chat/snapshot-helpers'
+import { findUserInstanceAccess, grantAllowsAgent } from '@/lib/instances/access'
+import { isAgentVisible } from '@/lib/agents/helpers'
+import type { AuthUser } from '@/types/auth'
+import type { DepartmentChangeReport } from '@/types/user'
+
+/** ChatSession.closedReason for sessions closed by a department move */
+export const DEPARTMENT_CHANGE_REASON = 'department_change'
+
+/** Could `user` still send to this agent? Mirrors the chat send check. */
+async function canUseAgent(user: AuthUser, instanceId: string, agentId: string): Promise<boolean> {
+  if (user.role === 'SYSTEM_ADMIN') return true
+  const access = await findUserInstanceAccess(user, instanceId)
+  if (!access) return false
+  const meta = await prisma.agentMeta.findUnique({
+    where: { instanceId_agentId: { instanceId, agentId } },
+  })
+  return meta ? isAgentVisible(meta, user) : grantAllowsAgent(access, agentId)
+}
+
+/**
+ * Follow-up to moving a user to another department (call after the update).
+ * Active sessions on agents the new department cannot reach are archived
+ * instead of failing with 403 on the next message, their share links are
+ * revoked, and the session is marked with closedReason so the user is told
+ * why it ended (session list, sync notifications).
+ */
+export async function applyDepartmentChange(
+  userId: string,
+  fromDepartmentId: string | null,
+): Promise<DepartmentChangeReport> {
+  const target = await prisma.user.findUniqueOrThrow({
+    where: { id: userId },
+    select: { id: true, name: true, email: true, avatar: true, role: true, departmentId: true },
+  })
+  const user: AuthUser = { ...target, departmentName: null }
+
+  const report: DepartmentChangeReport = {
+    userId,
+    fromDepartmentId,
+    toDepartmentId: target.departmentId,
+    sessionsArchived: [],
+    sharesRevoked: 0,
+  }
+
+  const sessions = await prisma.chatSession.findMany({
+    where: { userId, isActive: true },
+    select: { id: true, instanceId: true, agentId: true },
+  })
+  const lost: typeof sessions = []
+  for (const s of sessions) {
+    if (!(await canUseAgent(user, s.instanceId, s.agentId))) lost.push(s)
+  }
+  if (lost.length === 0) return report
+
+  await ensureRegistryInitialized()
+  for (const s of lost) {
+    const client = registry.getClient(s.instanceId)
+    if (client) {
+      await archiveSession(s.id, s.instanceId, s.agentId, userId, client)
+    } else {
+      // Gateway offline — keep the stored live messages
+      await archiveStoredMessages(s.id)
+    }
+    await prisma.chatSession.update({
+      where: { id: s.id },
+      data: {
+        isActive: false,
+        closedReason: DEPARTMENT_CHANGE_REASON,
+        closedAt: new Date(),
+      },
+    })
+    report.sessionsArchived.push({ id: s.id, instanceId: s.instanceId, agentId: s.agentId })
+  }
+
+  const revoked = await prisma.sessionShare.updateMany({
+    where: { chatSessionId: { in: lost.map((s) => s.id) }, revokedAt: null },
+    data: { revokedAt: new Date() },
+  })
+  report.sharesRevoked = revoked.count
+
+  return report
+}
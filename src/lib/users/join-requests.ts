@@ -0,0 +1,92 @@
+import { prisma } from '@/lib/db'
+import { notifyDepartment } from '@/lib/notifications'
+import { createLogger } from '@/lib/logger'
+import type { DepartmentJoinRequest, Prisma } from '@/generated/prisma'
+import type { AuthUser } from '@/types/auth'
+
+// Self-service department membership: a user without a department asks to
+// join one instead of waiting for a SYSTEM_ADMIN to assign it. The
+// department's admins see the request in their sync notifications (and its
+// notification channels get a message); approving it assigns the department
+// and closes the user's other open requests. SYSTEM_ADMINs can review any
+// request.
+
+const log = createLogger('users:join-requests')
+
+export const JOIN_REQUEST_INCLUDE = {
+  user: { select: { name: true, email: true } },
+  department: { select: { name: true } },
+  reviewedBy: { select: { name: true } },
+} as const
+
+type JoinRequestWithRelations = Prisma.DepartmentJoinRequestGetPayload<{ include: typeof JOIN_REQUEST_INCLUDE }>
+
+/** Requests the user may review: their department's, or all for a SYSTEM_ADMIN */
+export function reviewableWhere(user: AuthUser): Prisma.DepartmentJoinRequestWhereInput | null {
+  if (user.role === 'SYSTEM_ADMIN') return {}
+  if (user.role === 'DEPT_ADMIN' && user.departmentId) return { departmentId: user.departmentId }
+  return null
+}
+
+export function canReview(user: AuthUser, request: Pick<DepartmentJoinRequest, 'departmentId'>): boolean {
+  const where = reviewableWhere(user)
+  return !!where && (!where.departmentId || where.departmentId === request.departmentId)
+}
+
+/** Tell the department's notification channels; never throws */
+export function announceJoinRequest(request: JoinRequestWithRelations): void {
+  notifyDepartment(request.departmentId, {
+    title: 'Department join request',
+    text:
+      `${request.user.name} (${request.user.email}) asked to join ${request.department.name}` +
+      (request.message ? `: ${request.message}` : ''),
+    source: `join_request:${request.id}`,
+  }).catch((err) => log.warn('Join request notification failed', { requestId: request.id, error: (err as Error).message }))
+}
+
+/**
+ * Approve: assign the department, unless the user got one meanwhile, and
+ * close the user's other pending requests. Returns null when the user
+ * already has a department.
+ */
+export async function approveJoinRequest(
+  request: DepartmentJoinRequest,
+  reviewerId: string,
+  comment: string | null,
+): Promise<JoinRequestWithRelations | null> {
+  return prisma.$transaction(async (tx) => {
+    const { count } = await tx.user.updateMany({
+      where: { id: request.userId, departmentId: null },
+      data: { departmentId: request.departmentId },
+    })
+    if (count === 0) return null
+
+    await tx.departmentJoinRequest.updateMany({
+      where: { userId: request.userId, status: 'PENDING', id: { not: request.id } },
+      data: { status: 'WITHDRAWN' },
+    })
+    return tx.departmentJoinRequest.update({
+      where: { id: request.id },
+      data: { status: 'APPROVED', reviewedById: reviewerId, reviewComment: comment, reviewedAt: new Date() },
+      include: JOIN_REQUEST_INCLUDE,
+    })
+  })
+}
+
+export function toJoinRequestResponse(r: JoinRequestWithRelations) {
+  return {
+    id: r.id,
+    userId: r.userId,
+    userName: r.user.name,
+    userEmail: r.user.email,
+    departmentId: r.departmentId,
+    departmentName: r.department.name,
+    message: r.message,
+    status: r.status,
+    reviewedById: r.reviewedById,
+    reviewedByName: r.reviewedBy?.name ?? null,
+    reviewComment: r.reviewComment,
+    reviewedAt: r.reviewedAt?.toISOString() ?? null,
+    createdAt: r.createdAt.toISOString(),
+  }
+}
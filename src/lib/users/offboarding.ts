@@ -0,0 +1,92 @@
+import { prisma } from '@/lib/db'
+import { registry, ensureRegistryInitialized } from '@/lib/gateway/registry'
+import { archiveSession, archiveStoredMessages } from '@/lib/chat/snapshot-helpers'
+import type { OffboardingReport } from '@/types/user'
+
+/**
+ * Disable a user and clean up everything they left running:
+ * refresh tokens, active chat sessions, and (optionally) ownership of
+ * personal agents and skills, which move to `transferToUserId`.
+ */
+export async function offboardUser(
+  userId: string,
+  opts: { transferToUserId?: string } = {},
+): Promise<OffboardingReport> {
+  const report: OffboardingReport = {
+    userId,
+    disabled: false,
+    refreshTokensRevoked: 0,
+    sessionsArchived: [],
+    agentsTransferred: [],
+    agentsRetained: [],
+    skillsTransferred: [],
+    skillsRetained: [],
+    transferredTo: opts.transferToUserId ?? null,
+  }
+
+  // 1. Disable account — withAuth rejects non-ACTIVE users immediately
+  const user = await prisma.user.update({
+    where: { id: userId },
+    data: { status: 'DISABLED' },
+    select: { status: true },
+  })
+  report.disabled = user.status === 'DISABLED'
+
+  // 2. Revoke refresh tokens so no new access tokens can be minted
+  const revoked = await prisma.refreshToken.deleteMany({ where: { userId } })
+  report.refreshTokensRevoked = revoked.count
+
+  // 3. Archive active chat sessions
+  const sessions = await prisma.chatSession.findMany({
+    where: { userId, isActive: true },
+    select: { id: true, instanceId: true, agentId: true },
+  })
+  if (sessions.length > 0) await ensureRegistryInitialized()
+
+  for (const s of sessions) {
+    const client = registry.getClient(s.instanceId)
+    if (client) {
+      await archiveSession(s.id, s.instanceId, s.agentId, userId, client)
+    } else {
+      // Gateway offline — keep the stored live messages, then close the session
+      await archiveStoredMessages(s.id)
+      await prisma.chatSession.update({
+        where: { id: s.id },
+        data: { isActive: false },
+      })
+    }
+    report.sessionsArchived.push({ id: s.id, instanceId: s.instanceId, agentId: s.agentId })
+  }
+
+  // 4. Personal agents
+  const agents = await prisma.agentMeta.findMany({
+    where: { ownerId: userId },
+    select: { id: true, instanceId: true, agentId: true },
+  })
+  if (opts.transferToUserId && agents.length > 0) {
+    await prisma.agentMeta.updateMany({
+      where: { id: { in: agents.map((a) => a.id) } },
+      data: { ownerId: opts.transferToUserId },
+    })
+    report.agentsTransferred = agents
+  } else {
+    report.agentsRetained = agents
+  }
+
+  // 5. Created skills
+  const skills = await prisma.skill.findMany({
+    where: { creatorId: userId },
+    select: { id: true, slug: true },
+  })
+  if (opts.transferToUserId && skills.length > 0) {
+    await prisma.skill.updateMany({
+      where: { id: { in: skills.map((s) => s.id) } },
+      data: { creatorId: opts.transferToUserId },
+    })
+    report.skillsTransferred = skills
+  } else {
+    report.skillsRetained = skills
+  }
+
+  return report
+}
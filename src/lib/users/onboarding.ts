@@ -0,0 +1,147 @@
+import { prisma } from '@/lib/db'
+import { skipDuplicatesOption } from '@/lib/db-dialect'
+import { createLogger } from '@/lib/logger'
+import type { Prisma } from '@/generated/prisma'
+import type { OnboardingFunnel, OnboardingProgress, OnboardingStepName } from '@/types/user'
+
+// Onboarding checklist shown to new users. Most steps are detected from what
+// the user has already done; the rest are completed by the client:
+//
+//   join_department     — the user belongs to a department
+//   pick_default_agent  — the defaultAgent preference is set
+//   send_first_message  — one of the user's chat sessions has a message
+//   install_mobile_link — completed by the mobile app once it is linked
+//
+// A step is recorded in OnboardingStep the first time it is seen done and
+// stays done afterwards (leaving the department does not undo it), which is
+// what the adoption funnel counts.
+//
+// ONBOARDING_STEPS — comma-separated steps to show, in order (default: all)
+
+const log = createLogger('users:onboarding')
+
+const STEP_DETECTORS: Record<OnboardingStepName, Prisma.UserWhereInput | null> = {
+  join_department: { departmentId: { not: null } },
+  pick_default_agent: { preferences: { some: { key: 'defaultAgent' } } },
+  send_first_message: { chatSessions: { some: { lastMessageAt: { not: null } } } },
+  install_mobile_link: null,
+}
+
+export const ONBOARDING_STEPS = Object.keys(STEP_DETECTORS) as OnboardingStepName[]
+
+export const isOnboardingStep = (step: string): step is OnboardingStepName => step in STEP_DETECTORS
+
+/** Whether the client marks the step done, rather than TeamClaw detecting it */
+export const isManualStep = (step: OnboardingStepName) => STEP_DETECTORS[step] === null
+
+let warnedUnknown = false
+
+/** The configured steps, in order */
+export function onboardingSteps(): OnboardingStepName[] {
+  const raw = process.env.ONBOARDING_STEPS?.trim()
+  if (!raw) return ONBOARDING_STEPS
+  const names = raw.split(',').map((s) => s.trim()).filter(Boolean)
+  const unknown = names.filter((s) => !isOnboardingStep(s))
+  if (unknown.length > 0 && !warnedUnknown) {
+    warnedUnknown = true
+    log.warn('Ignoring unknown ONBOARDING_STEPS entries', { unknown })
+  }
+  const steps = [...new Set(names.filter(isOnboardingStep))]
+  return steps.length > 0 ? steps : ONBOARDING_STEPS
+}
+
+/** Users who have done the step: recorded, or detected from their data */
+function stepDoneWhere(step: OnboardingStepName): Prisma.UserWhereInput {
+  const recorded: Prisma.UserWhereInput = { onboardingSteps: { some: { step } } }
+  const detector = STEP_DETECTORS[step]
+  return detector ? { OR: [recorded, detector] } : recorded
+}
+
+async function detectSteps(userId: string, steps: OnboardingStepName[]): Promise<OnboardingStepName[]> {
+  const found = await Promise.all(
+    steps.map(async (step) => {
+      const detector = STEP_DETECTORS[step]
+      if (!detector) return null
+      const count = await prisma.user.count({ where: { id: userId, ...detector } })
+      return count > 0 ? step : null
+    }),
+  )
+  return found.filter((s): s is OnboardingStepName => s !== null)
+}
+
+async function recordSteps(userId: string, steps: OnboardingStepName[]): Promise<void> {
+  if (steps.length === 0) return
+  await prisma.onboardingStep.createMany({
+    data: steps.map((step) => ({ userId, step })),
+    ...skipDuplicatesOption(),
+  })
+}
+
+/** The user's checklist; steps detected as done since the last look are recorded now */
+export async function getOnboardingProgress(userId: string): Promise<OnboardingProgress> {
+  const steps = onboardingSteps()
+  const rows = await prisma.onboardingStep.findMany({ where: { userId, step: { in: steps } } })
+  const completedAt = new Map(rows.map((r) => [r.step, r.completedAt]))
+
+  const detected = await detectSteps(userId, steps.filter((s) => !completedAt.has(s)))
+  if (detected.length > 0) {
+    await recordSteps(userId, detected).catch((err) =>
+      log.warn('Could not record onboarding steps', { userId, error: (err as Error).message }),
+    )
+    const now = new Date()
+    for (const step of detected) completedAt.set(step, now)
+  }
+
+  const completedCount = steps.filter((s) => completedAt.has(s)).length
+  return {
+    steps: steps.map((step) => ({
+      step,
+      completed: completedAt.has(step),
+      completedAt: completedAt.get(step)?.toISOString() ?? null,
+    })),
+    completedCount,
+    total: steps.length,
+    finished: completedCount === steps.length,
+  }
+}
+
+/**
+ * Mark a step done. Detected steps are only recorded when they really are
+ * done; returns false when such a step is not.
+ */
+export async function completeOnboardingStep(userId: string, step: OnboardingStepName): Promise<boolean> {
+  if (!isManualStep(step)) {
+    const [detected] = await detectSteps(userId, [step])
+    if (!detected) return false
+  }
+  await prisma.onboardingStep.upsert({
+    where: { userId_step: { userId, step } },
+    create: { userId, step },
+    update: {},
+  })
+  return true
+}
+
+/** Per-step counts over active, interactive users (of one department, when given) */
+export async function getOnboardingFunnel(departmentId: string | null): Promise<OnboardingFunnel> {
+  const steps = onboardingSteps()
+  const base: Prisma.UserWhereInput = {
+    status: 'ACTIVE',
+    isServiceAccount: false,
+    ...(departmentId ? { departmentId } : {}),
+  }
+
+  const [users, ...counts] = await Promise.all([
+    prisma.user.count({ where: base }),
+    ...steps.flatMap((step, i) => [
+      prisma.user.count({ where: { ...base, AND: [stepDoneWhere(step)] } }),
+      prisma.user.count({ where: { ...base, AND: steps.slice(0, i + 1).map(stepDoneWhere) } }),
+    ]),
+  ])
+
+  return {
+    departmentId,
+    users,
+    steps: steps.map((step, i) => ({ step, completed: counts[2 * i], reached: counts[2 * i + 1] })),
+  }
+}
@@ -0,0 +1,47 @@
+import { prisma } from '@/lib/db'
+import { Prisma } from '@/generated/prisma'
+import { preferenceSchemas, type UpdatePreferencesInput } from '@/lib/validations/user'
+import type { PreferenceKey, UserPreferences } from '@/types/user'
+
+// User preferences: one UserPreference row per key that differs from the
+// default. Values are checked against the key's schema when read as well,
+// so a row left over from an older shape falls back to the default.
+
+export const DEFAULT_PREFERENCES: UserPreferences = {
+  theme: 'system',
+  locale: 'zh-CN',
+  defaultAgent: null,
+  notifications: { desktop: false, sound: true },
+  chatDisplay: { showThinking: true, showToolCalls: true },
+}
+
+const isPreferenceKey = (key: string): key is PreferenceKey => key in preferenceSchemas
+
+export async function getPreferences(userId: string): Promise<UserPreferences> {
+  const rows = await prisma.userPreference.findMany({ where: { userId } })
+  const prefs: Record<string, unknown> = { ...DEFAULT_PREFERENCES }
+  for (const row of rows) {
+    if (!isPreferenceKey(row.key)) continue
+    const parsed = preferenceSchemas[row.key].safeParse(row.value)
+    if (parsed.success) prefs[row.key] = parsed.data
+  }
+  return prefs as unknown as UserPreferences
+}
+
+/** Store the given keys; null resets a key to its default (drops its row) */
+export async function updatePreferences(userId: string, patch: UpdatePreferencesInput): Promise<UserPreferences> {
+  const ops = Object.entries(patch).flatMap(([key, value]) => {
+    if (value === undefined) return []
+    return value === null
+      ? [prisma.userPreference.deleteMany({ where: { userId, key } })]
+      : [
+          prisma.userPreference.upsert({
+            where: { userId_key: { userId, key } },
+            create: { userId, key, value: value as Prisma.InputJsonValue },
+            update: { value: value as Prisma.InputJsonValue },
+          }),
+        ]
+  })
+  await prisma.$transaction(ops)
+  return getPreferences(userId)
+}
@@ -0,0 +1,27 @@
+import { randomBytes } from 'crypto'
+import { prisma } from '@/lib/db'
+import { hashPassword } from '@/lib/auth/password'
+import type { Role } from '@/generated/prisma'
+
+/**
+ * Create a non-interactive user that owns sessions started by machines
+ * (inbound integrations, embedded widgets). It has an unguessable password
+ * and is rejected by the login route, so it can never sign in.
+ */
+export async function createServiceAccount(
+  name: string,
+  emailPrefix: string,
+  opts: { role?: Role; departmentId?: string | null } = {},
+): Promise<string> {
+  const user = await prisma.user.create({
+    data: {
+      email: `${emailPrefix}-${randomBytes(4).toString('hex')}@service.invalid`,
+      name,
+      passwordHash: await hashPassword(randomBytes(32).toString('hex')),
+      role: opts.role ?? 'USER',
+      departmentId: opts.departmentId ?? null,
+      isServiceAccount: true,
+    },
+  })
+  return user.id
+}
@@ -0,0 +1,160 @@
+import { NextRequest, NextResponse } from 'next/server'
+import { prisma } from '@/lib/db'
+import { interceptForApproval, requiresApproval, toApprovalResponse } from '@/lib/approvals'
+import { decryptSnapshots } from '@/lib/chat/snapshot-crypto'
+import { snapshotRowsToBatches } from '@/lib/chat/snapshot-helpers'
+import { getLiveMessages } from '@/lib/chat/live-messages'
+import { getToolOutputRedactor } from '@/lib/chat/redaction'
+import { toToolInvocationEntries } from '@/lib/chat/tool-invocations'
+import type { AuthUser } from '@/types/auth'
+import type { ChatSessionResponse, InspectedSessionResponse } from '@/types/chat'
+
+// Compliance access to another user's sessions. Every read needs a reason
+// (?reason=, at least MIN_REASON_LENGTH characters) and is written to the
+// audit log as a critical entry. When SESSION_INSPECT is in the approval
+// policy, the first read files an approval request instead; once a second
+// SYSTEM_ADMIN approves it, the requester may read that user's sessions for
+// SESSION_INSPECTION_WINDOW_HOURS (default 24). Transcripts pass through the
+// tool output redaction rules of the target user's department.
+
+export const MIN_REASON_LENGTH = 10
+
+const DEFAULT_WINDOW_HOURS = 24
+
+function windowMs(): number {
+  const n = parseInt(process.env.SESSION_INSPECTION_WINDOW_HOURS ?? '', 10)
+  return (Number.isFinite(n) && n > 0 ? n : DEFAULT_WINDOW_HOURS) * 3600_000
+}
+
+export type InspectionAuthorization =
+  | { ok: true; reason: string; approvalId: string | null }
+  | { ok: false; response: NextResponse }
+
+/**
+ * Check the reason and, when required, the approval for `reviewer` to read
+ * `target`'s sessions. A failed check carries the response to send: 400
+ * without a reason, 202 while an approval is pending.
+ */
+export async function authorizeSessionInspection(
+  req: NextRequest,
+  reviewer: AuthUser,
+  target: { id: string; name: string; email: string },
+): Promise<InspectionAuthorization> {
+  const reason = new URL(req.url).searchParams.get('reason')?.trim().slice(0, 1000) ?? ''
+  if (reason.length < MIN_REASON_LENGTH) {
+    return {
+      ok: false,
+      response: NextResponse.json(
+        { error: `A reason of at least ${MIN_REASON_LENGTH} characters is required (?reason=)` },
+        { status: 400 },
+      ),
+    }
+  }
+
+  if (!(await requiresApproval('SESSION_INSPECT'))) return { ok: true, reason, approvalId: null }
+
+  const mine = { action: 'SESSION_INSPECT', resourceId: target.id, requestedById: reviewer.id }
+  const approved = await prisma.approvalRequest.findFirst({
+    where: { ...mine, status: 'APPROVED', executedAt: { gt: new Date(Date.now() - windowMs()) } },
+    orderBy: { executedAt: 'desc' },
+  })
+  if (approved) return { ok: true, reason: approved.reason ?? reason, approvalId: approved.id }
+
+  const pending = await prisma.approvalRequest.findFirst({
+    where: { ...mine, status: 'PENDING', expiresAt: { gt: new Date() } },
+  })
+  if (pending) {
+    return {
+      ok: false,
+      response: NextResponse.json(
+        { status: 'pending_approval', approval: toApprovalResponse(pending) },
+        { status: 202 },
+      ),
+    }
+  }
+
+  const response = await interceptForApproval(req, {
+    action: 'SESSION_INSPECT',
+    resource: 'user',
+    resourceId: target.id,
+    payload: { userId: target.id },
+    summary: { name: target.name, email: target.email },
+    requestedById: reviewer.id,
+  })
+  // The policy can change between the two checks
+  return response ? { ok: false, response } : { ok: true, reason, approvalId: null }
+}
+
+function toSessionResponse(r: {
+  id: string
+  sessionId: string
+  instanceId: string
+  instance: { name: string }
+  agentId: string
+  title: string | null
+  lastMessageAt: Date | null
+  messageCount: number
+  isActive: boolean
+  closedReason: string | null
+  createdAt: Date
+}): ChatSessionResponse {
+  return {
+    id: r.id,
+    sessionId: r.sessionId,
+    instanceId: r.instanceId,
+    instanceName: r.instance.name,
+    agentId: r.agentId,
+    title: r.title,
+    lastMessageAt: r.lastMessageAt?.toISOString() ?? null,
+    messageCount: r.messageCount,
+    isActive: r.isActive,
+    closedReason: r.closedReason,
+    createdAt: r.createdAt.toISOString(),
+  }
+}
+
+/** Session list of a user, newest activity first */
+export async function listUserSessions(userId: string): Promise<ChatSessionResponse[]> {
+  const rows = await prisma.chatSession.findMany({
+    where: { userId },
+    orderBy: { lastMessageAt: { sort: 'desc', nulls: 'last' } },
+    include: { instance: { select: { name: true } } },
+  })
+  return rows.map(toSessionResponse)
+}
+
+/** Full transcript of one of the user's sessions, or null if it is not theirs */
+export async function loadInspectedSession(
+  userId: string,
+  chatSessionId: string,
+): Promise<InspectedSessionResponse | null> {
+  const session = await prisma.chatSession.findFirst({
+    where: { id: chatSessionId, userId },
+    include: {
+      instance: { select: { name: true } },
+      user: { select: { departmentId: true } },
+    },
+  })
+  if (!session) return null
+
+  const [snapshotRows, invocationRows] = await Promise.all([
+    prisma.chatMessageSnapshot.findMany({
+      where: { chatSessionId },
+      orderBy: [{ createdAt: 'asc' }, { orderIndex: 'asc' }],
+    }),
+    prisma.toolInvocation.findMany({ where: { chatSessionId }, orderBy: { startedAt: 'asc' } }),
+  ])
+
+  const redact = getToolOutputRedactor(session.user.departmentId)
+  const liveMessages = await getLiveMessages(chatSessionId)
+
+  return {
+    session: toSessionResponse(session),
+    snapshots: redact(snapshotRowsToBatches(await decryptSnapshots(snapshotRows))),
+    liveMessages: redact(liveMessages),
+    toolInvocations: (await toToolInvocationEntries(invocationRows)).map((e) => ({
+      ...e,
+      result: redact(e.result),
+    })),
+  }
+}
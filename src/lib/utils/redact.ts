@@ -0,0 +1,59 @@
+import { REDACTED } from '@/lib/audit'
+
+/**
+ * Field names whose values are never written to logs. Matching ignores case,
+ * '_' and '-', so `api_key`, `apiKey` and `API-KEY` are all caught.
+ */
+export const DEFAULT_REDACT_FIELDS = [
+  'password',
+  'passwordHash',
+  'currentPassword',
+  'newPassword',
+  'token',
+  'accessToken',
+  'refreshToken',
+  'gatewayToken',
+  'secret',
+  'clientSecret',
+  'apiKey',
+  'authorization',
+  'cookie',
+  'setCookie',
+  'webhookUrl',
+  'privateKey',
+  'encryptionKey',
+  'xTeamclawSignature',
+]
+
+function normalize(field: string): string {
+  return field.replace(/[-_]/g, '').toLowerCase()
+}
+
+/** Build a matcher from field names (defaults + extras) */
+export function createRedactor(extraFields: string[] = []): (field: string) => boolean {
+  const set = new Set([...DEFAULT_REDACT_FIELDS, ...extraFields].map(normalize))
+  return (field) => set.has(normalize(field))
+}
+
+/** Deep-copy a JSON value, replacing values under sensitive keys */
+export function redactJson(value: unknown, isSensitive: (field: string) => boolean): unknown {
+  if (Array.isArray(value)) return value.map((v) => redactJson(v, isSensitive))
+  if (value && typeof value === 'object') {
+    const out: Record<string, unknown> = {}
+    for (const [k, v] of Object.entries(value)) {
+      out[k] = isSensitive(k) && v != null ? REDACTED : redactJson(v, isSensitive)
+    }
+    return out
+  }
+  return value
+}
+
+/** Redact a Headers / URLSearchParams style collection into a plain object */
+export function redactEntries(
+  entries: Iterable<[string, string]>,
+  isSensitive: (field: string) => boolean,
+): Record<string, string> {
+  const out: Record<string, string> = {}
+  for (const [k, v] of entries) out[k] = isSensitive(k) ? REDACTED : v
+  return out
+}
@@ -0,0 +1,44 @@
+/** Length limit for admin-supplied patterns that run against arbitrary text */
+export const MAX_USER_REGEX_LENGTH = 200
+
+// "*", "+" and open-ended or ranged "{n,m}" repeat; "?" and "{n}" do not
+function isRepeatAt(pattern: string, i: number): boolean {
+  const c = pattern[i]
+  return c === '*' || c === '+' || (c === '{' && /^\{\d+,\d*\}/.test(pattern.slice(i)))
+}
+
+/**
+ * Whether a pattern risks catastrophic backtracking: a repeated group that
+ * itself repeats or alternates ("(a+)+", "(\w|\d)*"), or a backreference.
+ * A heuristic that errs on the side of rejecting.
+ */
+export function isUnsafeRegex(pattern: string): boolean {
+  if (/\\[1-9]|\\k</.test(pattern)) return true
+
+  const groups = [{ repeats: false, alternates: false }]
+  for (let i = 0; i < pattern.length; i++) {
+    const c = pattern[i]
+    const top = groups[groups.length - 1]
+    if (c === '\\') {
+      i++
+    } else if (c === '[') {
+      // Character class: no groups or quantifiers inside
+      i++
+      if (pattern[i] === '^') i++
+      if (pattern[i] === ']') i++
+      while (i < pattern.length && pattern[i] !== ']') i += pattern[i] === '\\' ? 2 : 1
+    } else if (c === '(') {
+      groups.push({ repeats: false, alternates: false })
+    } else if (c === '|') {
+      top.alternates = true
+    } else if (c === ')' && groups.length > 1) {
+      const group = groups.pop()!
+      const repeated = isRepeatAt(pattern, i + 1)
+      if (repeated && (group.repeats || group.alternates)) return true
+      if (repeated || group.repeats) groups[groups.length - 1].repeats = true
+    } else if (isRepeatAt(pattern, i)) {
+      top.repeats = true
+    }
+  }
+  return false
+}
@@ -0,0 +1,22 @@
+function resolvePath(data: unknown, path: string): unknown {
+  let current: unknown = data
+  for (const key of path.split('.')) {
+    if (current === null || typeof current !== 'object') return undefined
+    current = (current as Record<string, unknown>)[key]
+  }
+  return current
+}
+
+/**
+ * Render `{{placeholders}}` against a data object.
+ * `{{a.b.0.c}}` resolves a dotted path (array indexes allowed), `{{.}}`
+ * inserts the whole object as JSON. Missing values render as empty.
+ */
+export function renderTemplate(template: string, data: unknown): string {
+  return template.replace(/\{\{\s*([\w.\-]+)\s*\}\}/g, (_, path: string) => {
+    const value = path === '.' ? data : resolvePath(data, path)
+    if (value === undefined || value === null) return ''
+    if (typeof value === 'object') return JSON.stringify(value, null, 2)
+    return String(value)
+  })
+}
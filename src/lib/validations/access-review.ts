@@ -0,0 +1,16 @@
+import { z } from 'zod'
+
+export const createAccessReviewSchema = z.object({
+  name: z.string().min(1, '名称不能为空').max(100, '名称最多100个字符'),
+  dueAt: z.iso.datetime({ message: '截止时间格式无效' }).optional(),
+  autoRevoke: z.boolean().default(true),
+  includeRoles: z.boolean().default(true),
+})
+
+export const decideAccessReviewItemSchema = z.object({
+  decision: z.enum(['KEEP', 'REVOKE'], { message: '决定必须为 KEEP 或 REVOKE' }),
+  comment: z.string().max(1000, '备注最多1000个字符').optional(),
+})
+
+export type CreateAccessReviewInput = z.infer<typeof createAccessReviewSchema>
+export type DecideAccessReviewItemInput = z.infer<typeof decideAccessReviewItemSchema>
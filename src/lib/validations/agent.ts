@@ -94,6 +94,15 @@ export const classifyAgentSchema = z.object({
 
 export type ClassifyAgentInput = z.infer<typeof classifyAgentSchema>
 
+// ─── Managed preamble ───────────────────────────────────────────
+
+export const updatePreambleSchema = z.object({
+  template: z.string().max(8000, '前置说明最多8000个字符'),
+  enabled: z.boolean().optional(),
+})
+
+export type UpdatePreambleInput = z.infer<typeof updatePreambleSchema>
+
 // ─── Clone agent ────────────────────────────────────────────────
 
 export const cloneAgentSchema = z.object({
@@ -0,0 +1,10 @@
+import { z } from 'zod'
+
+export const createAnnouncementSchema = z.object({
+  message: z.string().trim().min(1, '公告内容不能为空').max(2000, '公告内容最多2000个字符'),
+  level: z.enum(['INFO', 'WARNING']).default('INFO'),
+  // How long the notice stays up in chat
+  durationMinutes: z.number().int().min(1, '至少1分钟').max(7 * 24 * 60, '最多7天').default(60),
+})
+
+export type CreateAnnouncementInput = z.infer<typeof createAnnouncementSchema>
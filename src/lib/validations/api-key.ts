@@ -0,0 +1,18 @@
+import { z } from 'zod'
+
+export const createApiKeySchema = z.object({
+  name: z.string().min(1, '名称不能为空').max(100, '名称最多100个字符'),
+  // Defaults to the creator's role
+  role: z.enum(['SYSTEM_ADMIN', 'DEPT_ADMIN', 'USER', 'VIEWER']).optional(),
+  scopes: z.array(z.string().min(1)).max(50, '权限范围最多50项').default([]),
+  // null: the key never expires
+  expiresInDays: z.number().int().min(1, '有效期至少1天').max(3650, '有效期最多3650天').nullable().default(90),
+})
+
+export const updateApiKeySchema = createApiKeySchema
+  .pick({ name: true })
+  .extend({ scopes: z.array(z.string().min(1)).max(50, '权限范围最多50项') })
+  .partial()
+
+export type CreateApiKeyInput = z.infer<typeof createApiKeySchema>
+export type UpdateApiKeyInput = z.infer<typeof updateApiKeySchema>
@@ -0,0 +1,14 @@
+import { z } from 'zod'
+import { APPROVAL_ACTIONS } from '@/lib/approvals'
+
+export const reviewApprovalSchema = z.object({
+  comment: z.string().max(1000, '备注最多1000个字符').optional(),
+})
+
+export const approvalPolicySchema = z.object({
+  requiredActions: z.array(z.enum(APPROVAL_ACTIONS)),
+  expiryHours: z.number().int().min(1, '有效期至少1小时').max(720, '有效期最多720小时'),
+})
+
+export type ReviewApprovalInput = z.infer<typeof reviewApprovalSchema>
+export type ApprovalPolicyInput = z.infer<typeof approvalPolicySchema>
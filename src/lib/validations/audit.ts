@@ -0,0 +1,18 @@
+import { z } from 'zod'
+import { UNSAMPLED_RESOURCES } from '@/lib/audit-sampling'
+
+export const auditSamplingRuleSchema = z.object({
+  resource: z
+    .string()
+    .regex(/^(\*|[a-z0-9_]+)$/, '资源类型格式不正确')
+    .refine((r) => !UNSAMPLED_RESOURCES.includes(r), '该资源类型的审计日志不可采样'),
+  action: z.string().regex(/^[A-Z0-9_]+\*?$/, '操作名格式不正确').max(64).optional(),
+  sampleRate: z.number().min(0, '采样率不能小于 0').max(1, '采样率不能大于 1'),
+  keepFailures: z.boolean().default(true),
+})
+
+export const updateAuditSamplingSchema = z.object({
+  rules: z.array(auditSamplingRuleSchema).max(50, '规则最多 50 条'),
+})
+
+export type UpdateAuditSamplingInput = z.infer<typeof updateAuditSamplingSchema>
@@ -16,5 +16,24 @@ export const registerSchema = z.object({
   name: z.string().min(2, 'Name must be at least 2 characters'),
 })
 
+export const breakGlassSchema = z.object({
+  justification: z
+    .string()
+    .trim()
+    .min(20, 'Justification must be at least 20 characters')
+    .max(1000, 'Justification must be at most 1000 characters'),
+  minutes: z.number().int().min(5, 'At least 5 minutes').optional(),
+  password: z.string().min(1, 'Password is required'),
+})
+
+export const mfaCodeSchema = z.object({
+  code: z.string().trim().min(6, 'Code is required').max(20, 'Invalid code'),
+})
+
+export const mfaVerifySchema = mfaCodeSchema.extend({
+  mfaToken: z.string().min(1, 'MFA token is required'),
+})
+
 export type LoginInput = z.infer<typeof loginSchema>
 export type RegisterInput = z.infer<typeof registerSchema>
+export type BreakGlassInput = z.infer<typeof breakGlassSchema>
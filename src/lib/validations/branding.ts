@@ -0,0 +1,38 @@
+import { z } from 'zod'
+import { BRANDING_COLOR_KEYS } from '@/types/branding'
+
+// Colors are injected into a <style> tag, so only allow plain color syntax
+const colorSchema = z
+  .string()
+  .max(64)
+  .regex(/^(#[0-9a-fA-F]{3,8}|(rgb|rgba|hsl|hsla|oklch|oklab)\([\d\s.,%/-]+\))$/, '颜色格式不正确')
+
+const paletteSchema = z.object(
+  Object.fromEntries(BRANDING_COLOR_KEYS.map((k) => [k, colorSchema.optional()])) as Record<
+    (typeof BRANDING_COLOR_KEYS)[number],
+    z.ZodOptional<typeof colorSchema>
+  >,
+)
+
+const logoUrlSchema = z
+  .string()
+  .max(200_000, 'Logo 不能超过 200KB')
+  .refine(
+    (v) => /^https:\/\//.test(v) || /^\/[^/]/.test(v) || /^data:image\/(png|jpeg|gif|webp|svg\+xml);base64,/.test(v),
+    'Logo 必须是 https 地址、站内路径或 data:image URL',
+  )
+
+export const brandingSchema = z.object({
+  productName: z.string().min(1, '产品名称不能为空').max(50, '产品名称最多50个字符'),
+  logoUrl: logoUrlSchema.nullable(),
+  colors: paletteSchema,
+  darkColors: paletteSchema,
+  loginBanner: z
+    .object({
+      title: z.string().min(1).max(120),
+      subtitle: z.string().max(300).optional(),
+    })
+    .nullable(),
+})
+
+export type BrandingInput = z.infer<typeof brandingSchema>
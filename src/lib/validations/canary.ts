@@ -0,0 +1,25 @@
+import { z } from 'zod'
+
+export const createCanarySchema = z
+  .object({
+    name: z.string().min(1, '名称不能为空').max(100, '名称最多100个字符'),
+    departmentId: z.string().min(1, '请选择部门'),
+    instanceId: z.string().min(1, '请选择实例'),
+    controlAgentId: z.string().min(1, '请选择对照智能体'),
+    canaryAgentId: z.string().min(1, '请选择灰度智能体'),
+    percent: z.number().int().min(1, '比例至少为1%').max(100, '比例最多为100%'),
+    enabled: z.boolean().optional(),
+  })
+  .refine((v) => v.controlAgentId !== v.canaryAgentId, {
+    message: '对照智能体和灰度智能体不能相同',
+    path: ['canaryAgentId'],
+  })
+
+export const updateCanarySchema = z.object({
+  name: z.string().min(1, '名称不能为空').max(100, '名称最多100个字符').optional(),
+  percent: z.number().int().min(1, '比例至少为1%').max(100, '比例最多为100%').optional(),
+  enabled: z.boolean().optional(),
+})
+
+export type CreateCanaryInput = z.infer<typeof createCanarySchema>
+export type UpdateCanaryInput = z.infer<typeof updateCanarySchema>
@@ -1,15 +1,82 @@
 import { z } from 'zod'
+import { MAX_USER_REGEX_LENGTH, isUnsafeRegex } from '@/lib/utils/regex'
 
 export const sendMessageSchema = z.object({
   instanceId: z.string().min(1, '请选择实例'),
   agentId: z.string().min(1, '请选择 Agent'),
   message: z.string().min(1, '消息不能为空').max(32000, '消息最多32000个字符'),
   sessionId: z.string().optional(), // TeamClaw ChatSession ID — targets a specific conversation
-  attachments: z.array(z.object({
-    name: z.string().max(255),
-    content: z.string(),       // base64 (no data:... prefix)
-    mimeType: z.string().max(100),
-  })).max(5).optional(),       // max 5 attachments
+  attachments: z.array(z.union([
+    z.object({ fileId: z.string().min(1) }), // uploaded with POST /api/v1/files
+    z.object({
+      name: z.string().max(255),
+      content: z.string(),     // base64 (no data:... prefix)
+      mimeType: z.string().max(100),
+    }),
+  ])).max(5).optional(),       // max 5 attachments
 })
 
 export type SendMessageInput = z.infer<typeof sendMessageSchema>
+
+export const toolApprovalDecisionSchema = z.object({
+  decision: z.enum(['approve', 'deny']),
+})
+
+export const redactionRuleSchema = z
+  .object({
+    type: z.enum(['regex', 'field']),
+    pattern: z.string().trim().min(1, '规则不能为空').max(500, '规则最多500个字符'),
+    replacement: z.string().max(100).optional(),
+  })
+  .refine((r) => {
+    if (r.type !== 'regex') return true
+    try {
+      new RegExp(r.pattern, 'g')
+      return true
+    } catch {
+      return false
+    }
+  }, { message: '正则表达式无效', path: ['pattern'] })
+  // Rules run against every tool output: no pattern may be able to stall the server
+  .refine((r) => r.type !== 'regex' || r.pattern.length <= MAX_USER_REGEX_LENGTH, {
+    message: `正则表达式最多${MAX_USER_REGEX_LENGTH}个字符`,
+    path: ['pattern'],
+  })
+  .refine((r) => r.type !== 'regex' || !isUnsafeRegex(r.pattern), {
+    message: '正则表达式不能包含嵌套量词、重复的分支或反向引用',
+    path: ['pattern'],
+  })
+  .refine((r) => r.type !== 'field' || /^[A-Za-z0-9_.-]+$/.test(r.pattern), {
+    message: '字段名只能包含字母、数字、点、下划线和连字符',
+    path: ['pattern'],
+  })
+  .refine((r) => r.type === 'regex' || r.replacement === undefined, {
+    message: '只有正则规则可以设置替换文本',
+    path: ['replacement'],
+  })
+
+const redactionRulesSchema = z.array(redactionRuleSchema).max(100, '规则最多 100 条')
+
+export const updateToolRedactionSchema = z.object({
+  builtin: z.boolean(),
+  rules: redactionRulesSchema,
+})
+
+export const updateDepartmentRedactionSchema = z.object({
+  rules: redactionRulesSchema,
+})
+
+export const renameSessionSchema = z.object({
+  title: z.string().trim().min(1, '标题不能为空').max(100, '标题最多100个字符'),
+})
+
+export const createSessionShareSchema = z.object({
+  expiresInHours: z.number().int().min(1, '有效期至少1小时').max(720, '有效期最多30天'),
+  password: z.string().min(4, '密码至少4个字符').max(100).optional(),
+})
+
+export const viewSessionShareSchema = z.object({
+  password: z.string().max(100).optional(),
+})
+
+export type CreateSessionShareInput = z.infer<typeof createSessionShareSchema>
@@ -1,14 +1,66 @@
 import { z } from 'zod'
+import { regionSchema } from './instance'
+
+// 对话排队优先级：实例并发已满时 HIGH 优先于 NORMAL、NORMAL 优先于 LOW
+const chatPrioritySchema = z.enum(['HIGH', 'NORMAL', 'LOW'])
 
 export const createDepartmentSchema = z.object({
   name: z.string().min(2, '部门名称至少2个字符').max(50, '部门名称最多50个字符'),
   description: z.string().max(256, '描述最多256个字符').optional(),
+  chatPriority: chatPrioritySchema.optional(),
 })
 
 export const updateDepartmentSchema = z.object({
   name: z.string().min(2, '部门名称至少2个字符').max(50, '部门名称最多50个字符').optional(),
   description: z.string().max(256, '描述最多256个字符').nullable().optional(),
+  chatPriority: chatPrioritySchema.optional(),
+})
+
+export const departmentChatDefaultsSchema = z
+  .object({
+    defaultInstanceId: z.string().min(1).nullable(),
+    defaultAgentId: z.string().min(1).nullable(),
+    welcomePrompt: z.string().max(4000, '欢迎提示最多4000个字符').nullable(),
+  })
+  .refine((d) => (d.defaultInstanceId === null) === (d.defaultAgentId === null), {
+    message: '默认实例和默认 Agent 需同时设置或同时清空',
+    path: ['defaultAgentId'],
+  })
+
+const toolPattern = z.string().trim().min(1).max(100, '工具名最多100个字符').regex(/^[\w.*:-]+$/, '工具名格式不正确')
+const domainPattern = z
+  .string()
+  .trim()
+  .toLowerCase()
+  .regex(/^(\*\.)?[a-z0-9-]+(\.[a-z0-9-]+)*$|^\[[0-9a-f:.]+\]$/, '域名格式不正确，例如 example.com 或 *.example.com')
+
+export const egressPolicySchema = z.object({
+  enabled: z.boolean(),
+  allowedTools: z.array(toolPattern).max(200).nullable(),
+  deniedTools: z.array(toolPattern).max(200).nullable(),
+  allowedDomains: z.array(domainPattern).max(500).nullable(),
+  deniedDomains: z.array(domainPattern).max(500).nullable(),
+})
+
+// null = 不限制区域
+export const departmentResidencySchema = z.object({
+  allowedRegions: z.array(regionSchema).max(50).nullable(),
+})
+
+export const createJoinRequestSchema = z.object({
+  departmentId: z.string().min(1, '请选择部门'),
+  message: z.string().max(1000, '说明最多1000个字符').optional(),
+})
+
+export const reviewJoinRequestSchema = z.object({
+  decision: z.enum(['approve', 'reject']),
+  comment: z.string().max(1000, '备注最多1000个字符').optional(),
 })
 
 export type CreateDepartmentInput = z.infer<typeof createDepartmentSchema>
 export type UpdateDepartmentInput = z.infer<typeof updateDepartmentSchema>
+export type DepartmentChatDefaultsInput = z.infer<typeof departmentChatDefaultsSchema>
+export type EgressPolicyInput = z.infer<typeof egressPolicySchema>
+export type DepartmentResidencyInput = z.infer<typeof departmentResidencySchema>
+export type CreateJoinRequestInput = z.infer<typeof createJoinRequestSchema>
+export type ReviewJoinRequestInput = z.infer<typeof reviewJoinRequestSchema>
@@ -0,0 +1,51 @@
+import { z } from 'zod'
+
+const evalCaseSchema = z
+  .object({
+    key: z
+      .string()
+      .min(1)
+      .max(100, '用例键最多100个字符')
+      .regex(/^[\w.-]+$/, '用例键只能包含字母、数字、下划线、点和连字符')
+      .optional(),
+    prompt: z.string().min(1, '提示不能为空').max(8000, '提示最多8000个字符'),
+    scorer: z.enum(['exact', 'regex', 'judge']),
+    expected: z.string().min(1, '预期结果不能为空').max(4000, '预期结果最多4000个字符'),
+  })
+  .refine((c) => {
+    if (c.scorer !== 'regex') return true
+    try {
+      new RegExp(c.expected)
+      return true
+    } catch {
+      return false
+    }
+  }, '无效的正则表达式')
+
+const casesSchema = z
+  .array(evalCaseSchema)
+  .min(1, '至少需要一个用例')
+  .max(500, '最多500个用例')
+  .refine((cases) => {
+    const keys = cases.flatMap((c) => (c.key ? [c.key] : []))
+    return new Set(keys).size === keys.length
+  }, '用例键不能重复')
+
+export const createEvalSetSchema = z.object({
+  name: z.string().min(1, '名称不能为空').max(100, '名称最多100个字符'),
+  description: z.string().max(2000, '描述最多2000个字符').optional(),
+  cases: casesSchema,
+  judgeResourceId: z.string().min(1).nullable().optional(),
+  judgeModel: z.string().max(200).nullable().optional(),
+})
+
+export const updateEvalSetSchema = createEvalSetSchema.partial()
+
+export const startEvalRunSchema = z.object({
+  instanceId: z.string().min(1, '请选择实例'),
+  agentId: z.string().min(1, '请选择智能体'),
+})
+
+export type CreateEvalSetInput = z.infer<typeof createEvalSetSchema>
+export type UpdateEvalSetInput = z.infer<typeof updateEvalSetSchema>
+export type StartEvalRunInput = z.infer<typeof startEvalRunSchema>
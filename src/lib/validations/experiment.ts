@@ -0,0 +1,14 @@
+import { z } from 'zod'
+
+export const createExperimentSchema = z.object({
+  name: z.string().min(1, '名称不能为空').max(100, '名称最多100个字符'),
+  hypothesis: z.string().max(2000, '假设最多2000个字符').optional(),
+  canaryId: z.string().min(1, '请选择灰度分流'),
+})
+
+export const stopExperimentSchema = z.object({
+  keepCanary: z.boolean().optional(),
+})
+
+export type CreateExperimentInput = z.infer<typeof createExperimentSchema>
+export type StopExperimentInput = z.infer<typeof stopExperimentSchema>
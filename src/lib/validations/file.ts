@@ -0,0 +1,8 @@
+import { z } from 'zod'
+
+// 图标：先通过 POST /api/v1/files 上传，再按文件 ID 设置；null 清除图标
+export const setIconSchema = z.object({
+  fileId: z.string().min(1, '文件 ID 不能为空').nullable(),
+})
+
+export type SetIconInput = z.infer<typeof setIconSchema>
@@ -1,14 +1,66 @@
 import { z } from 'zod'
 
+// null = permanent grant
+const expiresAtSchema = z
+  .iso.datetime({ message: '到期时间格式无效' })
+  .nullable()
+  .optional()
+  .refine((v) => !v || new Date(v).getTime() > Date.now(), '到期时间必须晚于当前时间')
+
 export const grantAccessSchema = z.object({
   departmentId: z.string().min(1, '请选择部门'),
   instanceId: z.string().min(1, '请选择实例'),
   agentIds: z.array(z.string()).nullable().optional(), // null = all agents
+  expiresAt: expiresAtSchema,
+})
+
+export const grantUserAccessSchema = z.object({
+  userId: z.string().min(1, '请选择用户'),
+  instanceId: z.string().min(1, '请选择实例'),
+  agentIds: z.array(z.string()).nullable().optional(), // null = all agents
+  expiresAt: expiresAtSchema,
 })
 
 export const updateAccessSchema = z.object({
-  agentIds: z.array(z.string()).nullable(), // null = all agents
+  agentIds: z.array(z.string()).nullable().optional(), // null = all agents
+  expiresAt: expiresAtSchema,
+})
+
+export const bulkGrantAccessSchema = z.object({
+  grants: z
+    .array(
+      z.object({
+        instanceId: z.string().min(1, '请选择实例'),
+        agentIds: z.array(z.string()).nullable().optional(), // null = all agents
+        expiresAt: expiresAtSchema,
+      }),
+    )
+    .min(1, '至少选择一个实例')
+    .max(200, '单次最多 200 个实例')
+    .refine((g) => new Set(g.map((x) => x.instanceId)).size === g.length, '实例不能重复'),
+})
+
+export const bulkRevokeAccessSchema = z.object({
+  instanceIds: z.array(z.string().min(1)).min(1, '至少选择一个实例').max(200, '单次最多 200 个实例'),
+})
+
+// ─── Container Delegation ────────────────────────────────────────────
+
+const containerActionSchema = z.enum(['RESTART', 'START', 'STOP', 'LOGS'])
+
+export const delegateInstanceSchema = z.object({
+  departmentId: z.string().min(1, '请选择部门'),
+  instanceId: z.string().min(1, '请选择实例'),
+  actions: z.array(containerActionSchema).min(1, '至少选择一个操作'),
+})
+
+export const updateDelegationSchema = z.object({
+  actions: z.array(containerActionSchema).min(1, '至少选择一个操作'),
 })
 
 export type GrantAccessInput = z.infer<typeof grantAccessSchema>
+export type GrantUserAccessInput = z.infer<typeof grantUserAccessSchema>
 export type UpdateAccessInput = z.infer<typeof updateAccessSchema>
+export type BulkGrantAccessInput = z.infer<typeof bulkGrantAccessSchema>
+export type DelegateInstanceInput = z.infer<typeof delegateInstanceSchema>
+export type UpdateDelegationInput = z.infer<typeof updateDelegationSchema>
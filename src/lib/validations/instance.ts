@@ -1,4 +1,7 @@
 import { z } from 'zod'
+import { SANDBOX_PROFILE_IDS } from '@/lib/docker/sandbox-profiles'
+import { DEPLOYMENT_ENVIRONMENTS } from '@/lib/docker/environments'
+import { DEFAULT_GATEWAY_PORT, normalizeGatewayUrl } from '@/lib/gateway/url'
 
 // ─── Model Provider ──────────────────────────────────────────────────
 
@@ -9,15 +12,49 @@ const modelProviderSchema = z.object({
   baseUrl: z.string().url('请输入有效的 Base URL').optional(),
 })
 
+// ─── Gateway URL ─────────────────────────────────────────────────────
+
+// 严格校验并规范化（ws/wss、默认端口、路径），见 lib/gateway/url
+const gatewayUrlSchema = z.string().transform((value, ctx) => {
+  const result = normalizeGatewayUrl(value)
+  if (!result.ok) {
+    ctx.addIssue({ code: 'custom', message: result.error })
+    return z.NEVER
+  }
+  return result.url
+})
+
+// ─── Data Residency ──────────────────────────────────────────────────
+
+// 数据驻留区域标签，如 eu-west、cn-north
+export const regionSchema = z
+  .string()
+  .trim()
+  .toLowerCase()
+  .regex(/^[a-z0-9][a-z0-9-]{0,31}$/, '区域标签只能包含小写字母、数字和连字符，最多32个字符')
+
 // ─── Docker Config ───────────────────────────────────────────────────
 
-const dockerConfigSchema = z.object({
-  imageName: z.string().min(1).optional(),
+const dockerStartSchema = z.object({
   env: z.record(z.string(), z.string()).optional(),
   portBindings: z.record(z.string(), z.string()).optional(),
   volumes: z.record(z.string(), z.string()).optional(),
   restartPolicy: z.enum(['no', 'always', 'unless-stopped', 'on-failure']).optional(),
   memoryLimit: z.number().int().positive().optional(),
+  // 沙箱配置: standard | hardened | isolated，变更后在下次启动/重启时重建容器
+  sandboxProfile: z.enum(SANDBOX_PROFILE_IDS).optional(),
+})
+
+// Gateway 端口映射由系统分配，各环境不能覆盖
+const dockerOverrideSchema = dockerStartSchema.refine(
+  (o) => !Object.keys(o.portBindings ?? {}).some((p) => p.split('/')[0] === String(DEFAULT_GATEWAY_PORT)),
+  { message: 'Gateway 端口映射不能按环境覆盖', path: ['portBindings'] },
+)
+
+const dockerConfigSchema = dockerStartSchema.extend({
+  imageName: z.string().min(1).optional(),
+  // 按部署环境 (TEAMCLAW_ENV) 覆盖的启动参数
+  overrides: z.partialRecord(z.enum(DEPLOYMENT_ENVIRONMENTS), dockerOverrideSchema).optional(),
 })
 
 // ─── Create Instance ─────────────────────────────────────────────────
@@ -29,14 +66,11 @@ export const createInstanceSchema = z.object({
     .max(64, '名称最多64个字符')
     .regex(/^[a-zA-Z0-9_-]+$/, '名称只能包含字母、数字、下划线和连字符'),
   description: z.string().max(256, '描述最多256个字符').optional(),
+  region: regionSchema.nullable().optional(),
   // 创建模式: docker 自动部署 | external 连接已有 Gateway
   mode: z.enum(['docker', 'external']).default('docker'),
   // docker 模式下 gatewayUrl/gatewayToken 由系统自动生成，external 模式下必填
-  gatewayUrl: z
-    .string()
-    .url('请输入有效的 URL')
-    .regex(/^wss?:\/\//, 'Gateway URL 必须以 ws:// 或 wss:// 开头')
-    .optional(),
+  gatewayUrl: gatewayUrlSchema.optional(),
   gatewayToken: z.string().min(1, 'Gateway Token 不能为空').optional(),
   // Docker 配置
   docker: dockerConfigSchema.optional(),
@@ -68,11 +102,8 @@ export const updateInstanceSchema = z.object({
     .regex(/^[a-zA-Z0-9_-]+$/, '名称只能包含字母、数字、下划线和连字符')
     .optional(),
   description: z.string().max(256, '描述最多256个字符').optional(),
-  gatewayUrl: z
-    .string()
-    .url('请输入有效的 URL')
-    .regex(/^wss?:\/\//, 'Gateway URL 必须以 ws:// 或 wss:// 开头')
-    .optional(),
+  region: regionSchema.nullable().optional(),
+  gatewayUrl: gatewayUrlSchema.optional(),
   gatewayToken: z.string().min(1, 'Gateway Token 不能为空').optional(),
   docker: dockerConfigSchema.optional(),
 })
@@ -83,8 +114,28 @@ export const updateInstanceConfigSchema = z.object({
   config: z.record(z.string(), z.unknown()),
 })
 
+// ─── Smoke Test ──────────────────────────────────────────────────────
+
+export const smokeTestSchema = z.object({
+  agentId: z.string().min(1).optional(),
+  timeoutMs: z.number().int().min(5_000).max(300_000).optional(),
+})
+
+// ─── Load Test ───────────────────────────────────────────────────────
+
+export const loadTestSchema = z.object({
+  agentId: z.string().min(1, '请选择智能体'),
+  conversations: z.number().int().min(1).max(1000),
+  concurrency: z.number().int().min(1).max(50),
+  turnsPerConversation: z.number().int().min(1).max(10).default(1),
+  prompt: z.string().min(1).max(4000).default('Load test: reply with one short sentence.'),
+  timeoutMs: z.number().int().min(5_000).max(600_000).default(120_000),
+})
+
 // ─── Inferred Types ──────────────────────────────────────────────────
 
 export type CreateInstanceInput = z.infer<typeof createInstanceSchema>
 export type UpdateInstanceInput = z.infer<typeof updateInstanceSchema>
 export type UpdateInstanceConfigInput = z.infer<typeof updateInstanceConfigSchema>
+export type SmokeTestInput = z.infer<typeof smokeTestSchema>
+export type LoadTestInput = z.infer<typeof loadTestSchema>
@@ -0,0 +1,22 @@
+import { z } from 'zod'
+
+export const createIntegrationSchema = z.object({
+  name: z.string().min(1, '名称不能为空').max(100, '名称最多100个字符'),
+  slug: z
+    .string()
+    .min(3, '标识至少3个字符')
+    .max(64, '标识最多64个字符')
+    .regex(/^[a-z0-9][a-z0-9-]*$/, '标识只能包含小写字母、数字和连字符'),
+  description: z.string().max(1000).optional(),
+  instanceId: z.string().min(1, '请选择实例'),
+  agentId: z.string().min(1, '请选择智能体'),
+  promptTemplate: z.string().min(1, '提示模板不能为空').max(20000, '提示模板最多20000个字符'),
+  enabled: z.boolean().optional(),
+})
+
+export const updateIntegrationSchema = createIntegrationSchema
+  .omit({ slug: true })
+  .partial()
+
+export type CreateIntegrationInput = z.infer<typeof createIntegrationSchema>
+export type UpdateIntegrationInput = z.infer<typeof updateIntegrationSchema>
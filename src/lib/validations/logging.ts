@@ -0,0 +1,13 @@
+import { z } from 'zod'
+
+const logLevelSchema = z.enum(['debug', 'info', 'warn', 'error'])
+
+export const updateLoggingSchema = z.object({
+  defaultLevel: logLevelSchema,
+  moduleLevels: z.record(
+    z.string().regex(/^[a-z0-9-]+(:[a-z0-9-]+)*$/, '模块名格式不正确').max(64),
+    logLevelSchema,
+  ),
+})
+
+export type UpdateLoggingInput = z.infer<typeof updateLoggingSchema>
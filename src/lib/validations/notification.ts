@@ -0,0 +1,22 @@
+import { z } from 'zod'
+
+export const createNotificationChannelSchema = z.object({
+  name: z.string().min(1, '名称不能为空').max(100, '名称最多100个字符'),
+  type: z.enum(['TEAMS', 'WECOM']),
+  departmentId: z.string().min(1, '请选择部门'),
+  webhookUrl: z.string().url('Webhook 地址格式不正确'),
+  template: z.string().max(5000, '模板最多5000个字符').nullable().optional(),
+  enabled: z.boolean().optional(),
+})
+
+export const updateNotificationChannelSchema = createNotificationChannelSchema
+  .omit({ type: true, departmentId: true })
+  .partial()
+
+export const testNotificationSchema = z.object({
+  title: z.string().max(200).optional(),
+  text: z.string().max(4000).optional(),
+})
+
+export type CreateNotificationChannelInput = z.infer<typeof createNotificationChannelSchema>
+export type UpdateNotificationChannelInput = z.infer<typeof updateNotificationChannelSchema>
@@ -0,0 +1,33 @@
+import { z } from 'zod'
+
+const regexSource = z
+  .string()
+  .max(500, '正则最多500个字符')
+  .refine((v) => {
+    try {
+      new RegExp(v)
+      return true
+    } catch {
+      return false
+    }
+  }, '无效的正则表达式')
+
+export const createProbeSchema = z.object({
+  name: z.string().min(1, '名称不能为空').max(100, '名称最多100个字符'),
+  instanceId: z.string().min(1, '请选择实例'),
+  agentId: z.string().min(1, '请选择智能体'),
+  prompt: z.string().min(1, '探测消息不能为空').max(4000, '探测消息最多4000个字符'),
+  expectRegex: regexSource.nullable().optional(),
+  expectJsonSchema: z.record(z.string(), z.unknown()).nullable().optional(),
+  latencyBudgetMs: z.number().int().min(1_000).max(600_000).optional(),
+  intervalSeconds: z.number().int().min(60, '间隔至少60秒').max(86_400).optional(),
+  failureThreshold: z.number().int().min(1).max(20).optional(),
+  enabled: z.boolean().optional(),
+})
+
+export const updateProbeSchema = createProbeSchema
+  .omit({ instanceId: true })
+  .partial()
+
+export type CreateProbeInput = z.infer<typeof createProbeSchema>
+export type UpdateProbeInput = z.infer<typeof updateProbeSchema>
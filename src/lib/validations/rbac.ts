@@ -0,0 +1,14 @@
+import { z } from 'zod'
+
+export const createCustomRoleSchema = z.object({
+  name: z
+    .string()
+    .regex(/^[A-Z][A-Z0-9_]{1,31}$/, '角色名须为2-32位大写字母、数字或下划线，以字母开头'),
+  description: z.string().max(500, '描述最多500个字符').nullable().optional(),
+  permissions: z.array(z.string()).max(200, '权限过多'),
+})
+
+export const updateCustomRoleSchema = createCustomRoleSchema.partial()
+
+export type CreateCustomRoleInput = z.infer<typeof createCustomRoleSchema>
+export type UpdateCustomRoleInput = z.infer<typeof updateCustomRoleSchema>
@@ -0,0 +1,16 @@
+import { z } from 'zod'
+
+// Keys of SAVED_FILTER_ENDPOINTS (lib/saved-filters)
+const savedFilterEndpointSchema = z.enum(['audit-logs', 'users'], { message: '不支持的列表' })
+
+export const createSavedFilterSchema = z.object({
+  name: z.string().trim().min(1, '名称不能为空').max(100, '名称最多100个字符'),
+  endpoint: savedFilterEndpointSchema,
+  query: z.record(z.string(), z.string().max(500, '参数值最多500个字符')),
+  shared: z.boolean().optional(),
+})
+
+export const updateSavedFilterSchema = createSavedFilterSchema.omit({ endpoint: true }).partial()
+
+export type CreateSavedFilterInput = z.infer<typeof createSavedFilterSchema>
+export type UpdateSavedFilterInput = z.infer<typeof updateSavedFilterSchema>
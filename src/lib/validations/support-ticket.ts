@@ -0,0 +1,20 @@
+import { z } from 'zod'
+
+const categories = ['INCORRECT', 'UNSAFE', 'BROKEN', 'OTHER'] as const
+
+export const createSupportTicketSchema = z.object({
+  messageId: z.string().max(100).optional(),
+  messageContent: z.string().min(1, '请提供有问题的回复').max(50_000, '回复内容过长'),
+  category: z.enum(categories),
+  description: z.string().trim().max(5000, '问题描述最多5000个字符').optional(),
+})
+
+export const updateSupportTicketSchema = z.object({
+  status: z.enum(['OPEN', 'RESOLVED']).optional(),
+  resolution: z.string().trim().max(5000, '处理说明最多5000个字符').optional(),
+  // Reassign to another admin
+  assigneeId: z.string().min(1).nullable().optional(),
+})
+
+export type CreateSupportTicketInput = z.infer<typeof createSupportTicketSchema>
+export type UpdateSupportTicketInput = z.infer<typeof updateSupportTicketSchema>
@@ -9,15 +9,24 @@ export const createUserSchema = z.object({
     .regex(/[A-Z]/, '密码需包含至少一个大写字母')
     .regex(/[a-z]/, '密码需包含至少一个小写字母')
     .regex(/[0-9]/, '密码需包含至少一个数字'),
-  role: z.enum(['SYSTEM_ADMIN', 'DEPT_ADMIN', 'USER']).default('USER'),
+  role: z.enum(['SYSTEM_ADMIN', 'DEPT_ADMIN', 'USER', 'VIEWER']).default('USER'),
   departmentId: z.string().optional(),
 })
 
 export const updateUserSchema = z.object({
   name: z.string().min(2, '姓名至少2个字符').max(50, '姓名最多50个字符').optional(),
-  role: z.enum(['SYSTEM_ADMIN', 'DEPT_ADMIN', 'USER']).optional(),
+  role: z.enum(['SYSTEM_ADMIN', 'DEPT_ADMIN', 'USER', 'VIEWER']).optional(),
+  // Temporary elevation: with role, the user reverts to their current role at this time
+  roleExpiresAt: z
+    .iso.datetime({ message: '到期时间格式无效' })
+    .nullable()
+    .optional()
+    .refine((v) => !v || new Date(v).getTime() > Date.now(), '到期时间必须晚于当前时间'),
   departmentId: z.string().nullable().optional(),
   status: z.enum(['ACTIVE', 'DISABLED']).optional(),
+  mfaRequired: z.boolean().optional(),
+  // Custom role on top of the role (lib/auth/custom-roles); null removes it
+  customRoleId: z.string().nullable().optional(),
 })
 
 export const resetPasswordSchema = z.object({
@@ -29,6 +38,40 @@ export const resetPasswordSchema = z.object({
     .regex(/[0-9]/, '密码需包含至少一个数字'),
 })
 
+export const offboardUserSchema = z.object({
+  transferToUserId: z.string().min(1).optional(),
+})
+
+export const eraseUserSchema = z.object({
+  confirmEmail: z.string().email('请输入有效的邮箱地址'),
+  reason: z.string().max(500, '原因最多500个字符').optional(),
+})
+
+// One schema per preference key (types/user UserPreferences)
+export const preferenceSchemas = {
+  theme: z.enum(['light', 'dark', 'system']),
+  locale: z.enum(['zh-CN', 'en']),
+  defaultAgent: z
+    .object({ instanceId: z.string().min(1), agentId: z.string().min(1) })
+    .nullable(),
+  notifications: z.object({ desktop: z.boolean(), sound: z.boolean() }),
+  chatDisplay: z.object({ showThinking: z.boolean(), showToolCalls: z.boolean() }),
+}
+
+// null resets a key to its default
+export const updatePreferencesSchema = z
+  .object({
+    theme: preferenceSchemas.theme.nullable().optional(),
+    locale: preferenceSchemas.locale.nullable().optional(),
+    defaultAgent: preferenceSchemas.defaultAgent.optional(),
+    notifications: preferenceSchemas.notifications.nullable().optional(),
+    chatDisplay: preferenceSchemas.chatDisplay.nullable().optional(),
+  })
+  .strict()
+
 export type CreateUserInput = z.infer<typeof createUserSchema>
 export type UpdateUserInput = z.infer<typeof updateUserSchema>
 export type ResetPasswordInput = z.infer<typeof resetPasswordSchema>
+export type OffboardUserInput = z.infer<typeof offboardUserSchema>
+export type EraseUserInput = z.infer<typeof eraseUserSchema>
+export type UpdatePreferencesInput = z.infer<typeof updatePreferencesSchema>
@@ -0,0 +1,26 @@
+import { z } from 'zod'
+
+const originSchema = z
+  .string()
+  .regex(/^https?:\/\/[^/\s]+$/, '来源格式应为 https://host[:port]，不含路径')
+
+export const createWidgetSchema = z.object({
+  name: z.string().min(1, '名称不能为空').max(100, '名称最多100个字符'),
+  instanceId: z.string().min(1, '请选择实例'),
+  agentId: z.string().min(1, '请选择智能体'),
+  allowedOrigins: z.array(originSchema).max(20, '最多20个来源'),
+  rateLimitPerMinute: z.number().int().min(1).max(600).optional(),
+  enabled: z.boolean().optional(),
+})
+
+export const updateWidgetSchema = createWidgetSchema
+  .omit({ instanceId: true, agentId: true })
+  .partial()
+
+export const widgetMessageSchema = z.object({
+  visitorId: z.string().regex(/^[\w-]{1,64}$/, 'Invalid visitorId'),
+  message: z.string().min(1, '消息不能为空').max(8000, '消息最多8000个字符'),
+})
+
+export type CreateWidgetInput = z.infer<typeof createWidgetSchema>
+export type UpdateWidgetInput = z.infer<typeof updateWidgetSchema>
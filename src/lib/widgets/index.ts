@@ -0,0 +1,166 @@
+import { createHash, randomBytes } from 'crypto'
+import { NextRequest, NextResponse } from 'next/server'
+import { prisma } from '@/lib/db'
+import { listHas, stringList } from '@/lib/db-dialect'
+import { registry, ensureRegistryInitialized } from '@/lib/gateway/registry'
+import { runAgentToCompletion } from '@/lib/chat/run'
+import { saveLiveSnapshot, transformToLiveMessages } from '@/lib/chat/snapshot-helpers'
+import { createLogger } from '@/lib/logger'
+import type { ChatWidget } from '@/generated/prisma'
+import type { ChatMessage } from '@/types/chat'
+
+const log = createLogger('widget')
+
+const TOKEN_PREFIX = 'tcw_'
+const VISITOR_ID_RE = /^[\w-]{1,64}$/
+// Widget replies are synchronous, so keep runs short
+const WIDGET_RUN_TIMEOUT_MS = 2 * 60_000
+
+export function generateWidgetToken(): { token: string; tokenHash: string; tokenPrefix: string } {
+  const token = TOKEN_PREFIX + randomBytes(32).toString('base64url')
+  return { token, tokenHash: hashWidgetToken(token), tokenPrefix: token.slice(0, 12) }
+}
+
+export function hashWidgetToken(token: string): string {
+  return createHash('sha256').update(token).digest('hex')
+}
+
+export function isValidVisitorId(visitorId: string | null | undefined): visitorId is string {
+  return !!visitorId && VISITOR_ID_RE.test(visitorId)
+}
+
+/**
+ * Browsers must come from an allowed origin. Requests without an Origin
+ * header (server-to-server) rely on the token alone.
+ */
+export function isOriginAllowed(widget: Pick<ChatWidget, 'allowedOrigins'>, origin: string | null): boolean {
+  if (!origin) return true
+  return stringList(widget.allowedOrigins).includes(origin)
+}
+
+export function corsHeaders(origin: string | null): Record<string, string> {
+  if (!origin) return {}
+  return {
+    'Access-Control-Allow-Origin': origin,
+    'Access-Control-Allow-Methods': 'GET, POST, OPTIONS',
+    'Access-Control-Allow-Headers': 'Authorization, Content-Type',
+    'Access-Control-Max-Age': '600',
+    Vary: 'Origin',
+  }
+}
+
+/** Preflight carries no token, so allow any origin that some enabled widget lists */
+export async function widgetPreflight(req: NextRequest): Promise<NextResponse> {
+  const origin = req.headers.get('origin')
+  const known = origin
+    ? await prisma.chatWidget.findFirst({
+        where: { enabled: true, allowedOrigins: listHas(origin) },
+        select: { id: true },
+      })
+    : null
+  return new NextResponse(null, { status: 204, headers: known ? corsHeaders(origin) : {} })
+}
+
+/**
+ * Authenticate a widget request: bearer token → enabled widget whose
+ * allowed origins include the caller. Returns an error response otherwise.
+ */
+export async function authenticateWidget(
+  req: NextRequest,
+): Promise<{ widget: ChatWidget; headers: Record<string, string> } | { error: NextResponse }> {
+  const origin = req.headers.get('origin')
+  const auth = req.headers.get('authorization')
+  const token = auth?.startsWith('Bearer ') ? auth.slice(7) : null
+
+  const widget = token
+    ? await prisma.chatWidget.findUnique({ where: { tokenHash: hashWidgetToken(token) } })
+    : null
+
+  if (!widget || !widget.enabled) {
+    return { error: NextResponse.json({ error: 'Invalid widget token' }, { status: 401 }) }
+  }
+  if (!isOriginAllowed(widget, origin)) {
+    return { error: NextResponse.json({ error: 'Origin not allowed' }, { status: 403 }) }
+  }
+  return { widget, headers: corsHeaders(origin) }
+}
+
+/** One gateway session per (widget, visitor), owned by the widget's service account */
+export function widgetSessionKey(widget: ChatWidget, visitorId: string): string {
+  return `agent:${widget.agentId}:tc:${widget.serviceAccountId}:w:${visitorId}`
+}
+
+function connectedClient(widget: ChatWidget) {
+  const client = registry.getClient(widget.instanceId)
+  const adapter = registry.getAdapter(widget.instanceId)
+  if (!client || !adapter || !client.isConnected()) throw new Error('Agent is unavailable')
+  return { client, adapter }
+}
+
+export async function sendWidgetMessage(
+  widget: ChatWidget,
+  visitorId: string,
+  message: string,
+): Promise<{ sessionId: string; reply: string }> {
+  await ensureRegistryInitialized()
+  const { client, adapter } = connectedClient(widget)
+  const sessionKey = widgetSessionKey(widget, visitorId)
+
+  const existing = await prisma.chatSession.findFirst({
+    where: { userId: widget.serviceAccountId, sessionId: sessionKey },
+  })
+  const session = existing
+    ? await prisma.chatSession.update({
+        where: { id: existing.id },
+        data: { lastMessageAt: new Date(), messageCount: { increment: 1 } },
+      })
+    : await prisma.chatSession.create({
+        data: {
+          userId: widget.serviceAccountId,
+          instanceId: widget.instanceId,
+          agentId: widget.agentId,
+          sessionId: sessionKey,
+          title: `${widget.name} · ${visitorId}`,
+          lastMessageAt: new Date(),
+          messageCount: 1,
+          isActive: true,
+        },
+      })
+
+  const { text } = await runAgentToCompletion(client, adapter, sessionKey, message, {
+    timeoutMs: WIDGET_RUN_TIMEOUT_MS,
+  })
+
+  saveLiveSnapshot(session.id, client, sessionKey).catch((err) =>
+    log.error('Live snapshot failed', { widgetId: widget.id, err }),
+  )
+  prisma.chatWidget
+    .update({ where: { id: widget.id }, data: { lastUsedAt: new Date() } })
+    .catch(() => {})
+
+  return { sessionId: session.id, reply: text }
+}
+
+export async function getWidgetHistory(widget: ChatWidget, visitorId: string): Promise<ChatMessage[]> {
+  await ensureRegistryInitialized()
+  const { client, adapter } = connectedClient(widget)
+  const history = await adapter.getHistory(client, widgetSessionKey(widget, visitorId), 100)
+  // Widgets only see the conversation, not the agent's reasoning or tool output
+  return transformToLiveMessages(history.messages ?? []).map(({ thinking: _t, toolCalls: _c, ...m }) => m)
+}
+
+export function toWidgetResponse(w: ChatWidget & { instance?: { name: string } | null }) {
+  return {
+    id: w.id,
+    name: w.name,
+    instanceId: w.instanceId,
+    instanceName: w.instance?.name ?? null,
+    agentId: w.agentId,
+    tokenPrefix: w.tokenPrefix,
+    allowedOrigins: stringList(w.allowedOrigins),
+    rateLimitPerMinute: w.rateLimitPerMinute,
+    enabled: w.enabled,
+    lastUsedAt: w.lastUsedAt?.toISOString() ?? null,
+    createdAt: w.createdAt.toISOString(),
+  }
+}
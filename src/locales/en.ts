@@ -109,6 +109,11 @@ const en: Record<TranslationKey, string> = {
   'auth.loginSuccess': 'Login successful',
   'auth.loginFailed': 'Login failed, please try again',
   'auth.networkError': 'Network error, please check your connection',
+  'auth.or': 'or',
+  'auth.ssoLogin': 'Sign in with {provider}',
+  'auth.ssoFailed': 'Single sign-on failed, please try again',
+  'auth.ssoNoAccount': 'No account is available for this identity, please contact your administrator',
+  'auth.accountDisabled': 'Account is disabled',
   'auth.noAccount': "Don't have an account?",
   'auth.registerNow': 'Register now',
   'auth.registerTitle': 'Create Account',
@@ -140,6 +145,30 @@ const en: Record<TranslationKey, string> = {
   'auth.tagline': 'Enterprise OpenClaw\nManagement Platform',
   'auth.taglineDesc': 'Manage Instances, Agents, and Skills in one place to empower team collaboration.',
 
+  // ── MFA ─────────────────────────────────────────────────
+  'mfa.title': 'Two-step verification',
+  'mfa.desc': 'Protect your account with a code from an authenticator app',
+  'mfa.requiredDesc': 'Your administrator requires two-step verification. Set it up to continue.',
+  'mfa.code': 'Verification code',
+  'mfa.codeHint': 'Enter the 6-digit code from your authenticator app, or a recovery code',
+  'mfa.verify': 'Verify',
+  'mfa.backToLogin': 'Back to sign in',
+  'mfa.failed': 'Verification failed, please try again',
+  'mfa.recoveryCodesLeft': 'Recovery code used, {n} remaining',
+  'mfa.start': 'Set up two-step verification',
+  'mfa.scanDesc': 'Add this account to your authenticator app with the key below, then enter the code it shows.',
+  'mfa.secret': 'Setup key',
+  'mfa.openInApp': 'Open in authenticator app',
+  'mfa.confirm': 'Confirm and enable',
+  'mfa.enabled': 'Two-step verification enabled',
+  'mfa.disabled': 'Two-step verification disabled',
+  'mfa.recoveryCodesDesc': 'Save these recovery codes somewhere safe. Each can be used once if you lose your device; they will not be shown again.',
+  'mfa.savedCodes': "I've saved these codes",
+  'mfa.statusOn': 'Two-step verification is on. {n} recovery codes remaining.',
+  'mfa.regenerateCodes': 'New recovery codes',
+  'mfa.disable': 'Turn off',
+  'mfa.backToApp': 'Back to TeamClaw',
+
   // ── Instance ────────────────────────────────────────────
   'instance.management': 'Instance Management',
   'instance.managementDesc': 'Manage and monitor all OpenClaw instances',
@@ -158,6 +187,11 @@ const en: Record<TranslationKey, string> = {
   'instance.advancedOptions': 'Advanced Options',
   'instance.memoryLimit': 'Memory Limit (MB)',
   'instance.restartPolicy': 'Restart Policy',
+  'instance.sandboxProfile': 'Sandbox Profile',
+  'instance.sandboxStandard': 'Standard (Docker defaults)',
+  'instance.sandboxHardened': 'Hardened (no Docker socket, minimal capabilities)',
+  'instance.sandboxIsolated': 'Isolated (read-only, internal network only)',
+  'instance.sandboxProfileHint': 'Applied when the container starts; changing it later recreates the container on the next start or restart.',
   'instance.gatewayToken': 'Gateway Token',
   'instance.gatewayTokenPlaceholder': 'Connection token',
   'instance.externalGatewayHint': 'Connect to a running external OpenClaw Gateway instance',
@@ -335,6 +369,8 @@ const en: Record<TranslationKey, string> = {
   'chat.recentSessions': 'Recent Sessions',
   'chat.noSessions': 'No sessions yet',
   'chat.sessionDeleted': 'Session deleted',
+  'chat.renameSession': 'Rename conversation',
+  'chat.closedDepartmentChange': 'Archived: no access after department change',
   'chat.noAgents': 'No Agents available',
   'chat.newConversation': 'New Conversation',
   'chat.newConversationTitle': 'New Conversation',
@@ -348,19 +384,73 @@ const en: Record<TranslationKey, string> = {
   'chat.confirmClear': 'Confirm',
   'chat.contextCleared': 'Context cleared',
   'chat.clearContextFailed': 'Failed to clear context',
+  'chat.toolApprovalRequired': 'The agent wants to run {tool}. Allow it?',
+  'chat.toolApprovalApprove': 'Allow',
+  'chat.toolApprovalDeny': 'Deny',
+  'chat.toolApprovalHint': 'The reply is paused until you answer. Unanswered requests are denied automatically.',
+  'chat.announcement': 'Announcement',
+  'chat.announcementFrom': 'Announcement from {name}',
+  'chat.announcementDismiss': 'Dismiss',
+  'chat.reportProblem': 'Report a problem',
+  'chat.reportTitle': 'Report a Problem',
+  'chat.reportDesc': 'The response and diagnostics of this conversation are sent to the administrator of the instance.',
+  'chat.reportCategory': 'What is wrong?',
+  'chat.reportIncorrect': 'Incorrect or made-up answer',
+  'chat.reportUnsafe': 'Harmful or inappropriate content',
+  'chat.reportBroken': 'Error, failed tool or no reply',
+  'chat.reportOther': 'Something else',
+  'chat.reportDescription': 'Details (optional)',
+  'chat.reportPlaceholder': 'What did you expect instead?',
+  'chat.reportSubmit': 'Send report',
+  'chat.reportSent': 'Report sent to the administrator',
+  'chat.reportFailed': 'Failed to send report',
   'chat.inputPlaceholder': 'Type a message... (Enter to send, Shift+Enter for new line)',
   'chat.uploadFile': 'Upload file',
   'chat.fileTooLarge': 'File "{name}" exceeds size limit ({limit})',
+  'chat.uploadFailed': 'Could not upload "{name}": {error}',
   'chat.maxAttachments': 'Maximum of 5 attachments allowed',
   'chat.attachment': '(attachment)',
   'chat.thinking': 'Thinking',
+  'chat.queued': 'Instance is busy, waiting in queue (position {position})...',
+  'chat.reconnecting': 'Connection to the agent was interrupted, reconnecting (attempt {attempt}/{max})...',
   'chat.imageAlt': 'Image',
   'chat.downloadImage': 'Download image',
   'chat.contextRestart': 'AI context restarted from here',
   'chat.contextReset': 'Context reset',
   'chat.loadingHistory': 'Loading history...',
+  'chat.loadOlder': 'Load earlier messages',
+  'chat.historySummary': 'Summary of {n} earlier messages',
   'chat.gatewayUnreachable': 'Gateway connection lost. Refresh to retry.',
   'chat.department': 'Department',
+  'chat.defaultAgent': 'Department default agent',
+  'chat.share': 'Share',
+  'chat.export': 'Export',
+  'chat.exportMarkdown': 'Markdown (.md)',
+  'chat.exportJson': 'JSON (.json)',
+  'chat.shareTitle': 'Share conversation',
+  'chat.shareDesc': 'Anyone with the link can view the saved messages of this conversation, read-only. Messages sent after the link is created are not included.',
+  'chat.shareExpiry': 'Expires after',
+  'chat.shareExpiry1d': '1 day',
+  'chat.shareExpiry7d': '7 days',
+  'chat.shareExpiry30d': '30 days',
+  'chat.sharePassword': 'Password (optional)',
+  'chat.shareCreate': 'Create link',
+  'chat.shareCopied': 'Link copied to clipboard',
+  'chat.shareFailed': 'Failed to create share link',
+  'chat.shareExisting': 'Existing links',
+  'chat.shareViews': '{count} views',
+  'chat.shareRevoke': 'Revoke',
+  'chat.shareRevoked': 'Link revoked',
+  'chat.shareStateActive': 'Active',
+  'chat.shareStateExpired': 'Expired',
+  'chat.shareStateRevoked': 'Revoked',
+  'share.passwordRequired': 'This conversation is password protected',
+  'share.passwordPlaceholder': 'Enter password',
+  'share.view': 'View',
+  'share.invalid': 'This link is invalid or has expired',
+  'share.sharedBy': 'Shared by {name}',
+  'share.expiresAt': 'Link expires {time}',
+  'share.readOnly': 'Read-only snapshot',
   'chat.personal': 'Personal',
   'chat.onlineStatus': 'Online',
 
@@ -640,6 +730,8 @@ const en: Record<TranslationKey, string> = {
   'dashboard.action.INSTANCE_RESTART': 'Restart Instance',
   'dashboard.action.INSTANCE_CONFIG_PATCH': 'Patch Config',
   'dashboard.action.INSTANCE_DASHBOARD': 'Access Console',
+  'dashboard.action.INSTANCE_SMOKE_TEST': 'Smoke Test Instance',
+  'dashboard.action.INSTANCE_LOAD_TEST': 'Load Test Instance',
   'dashboard.action.USER_CREATE': 'Create User',
   'dashboard.action.USER_UPDATE': 'Update User',
   'dashboard.action.USER_DELETE': 'Disable User',
@@ -669,6 +761,25 @@ const en: Record<TranslationKey, string> = {
   // Dashboard result labels
   'dashboard.resultFailure': 'Failed',
   'dashboard.resultDenied': 'Denied',
+  // Dashboard license card
+  'dashboard.license': 'License',
+  'dashboard.licenseStateValid': 'Active',
+  'dashboard.licenseStateGrace': 'Grace period',
+  'dashboard.licenseStateExpired': 'Expired',
+  'dashboard.licenseStateInvalid': 'Invalid',
+  'dashboard.licenseSeats': 'Seats',
+  'dashboard.licenseInstances': 'Instances',
+  'dashboard.licenseUnlimited': 'Unlimited',
+  'dashboard.licenseExpires': 'Expires {date}',
+  'dashboard.licenseGraceEnds': 'Grace period ends {date}; new users and instances will then be blocked',
+  'dashboard.licenseBlocked': 'New users and instances are blocked until the license is renewed',
+  'dashboard.versions': 'OpenClaw Versions',
+  'dashboard.versionsLatest': 'Latest v{version}',
+  'dashboard.versionsLatestUnknown': 'Latest unknown',
+  'dashboard.versionsUnreported': 'Unreported',
+  'dashboard.versionsUpToDate': 'All instances are on the latest version',
+  'dashboard.versionsNoChangelog': 'No changelog available',
+  'dashboard.versionsFeedError': 'Release feed unavailable: {error}',
 
   // ── Audit ───────────────────────────────────────────────
   'audit.searchPlaceholder': 'Search operation details...',
@@ -736,6 +847,7 @@ const en: Record<TranslationKey, string> = {
   'user.statusLabel': 'Status',
   'user.roleSystemAdmin': 'System Admin',
   'user.roleDeptAdmin': 'Dept Admin',
+  'user.roleViewer': 'Viewer',
   'user.roleUser': 'User',
   'user.statusActive': 'Active',
   'user.statusDisabled': 'Disabled',
@@ -768,6 +880,11 @@ const en: Record<TranslationKey, string> = {
   'user.createFailed': 'Create failed',
   'user.updateFailed': 'Update failed',
   'user.statusDisabledShort': 'Disabled',
+  'user.mfaRequired': 'Require two-step verification',
+  'user.mfaRequiredDesc': 'The user must set up two-step verification before using TeamClaw',
+  'user.resetMfa': 'Reset two-step verification',
+  'user.resetMfaDesc': 'Removes the authenticator and recovery codes and signs the user out',
+  'user.mfaResetMsg': 'Two-step verification reset',
 
   // ── Department ──────────────────────────────────────────
   'dept.management': 'Department Management',
@@ -782,6 +899,15 @@ const en: Record<TranslationKey, string> = {
   'dept.editTitle': 'Edit Department',
   'dept.editDesc': 'Modify department information',
   'dept.updatedMsg': 'Department updated successfully',
+  'dept.chatPriority': 'Chat Priority',
+  'dept.chatPriorityHigh': 'High (production support)',
+  'dept.chatPriorityNormal': 'Normal',
+  'dept.chatPriorityLow': 'Low (experimentation)',
+  'dept.chatPriorityHint': 'When an instance is busy, queued chats from higher tiers start first',
+  'dept.icon': 'Icon',
+  'dept.iconUpload': 'Upload',
+  'dept.iconRemove': 'Remove',
+  'dept.iconHint': 'PNG, JPEG, WebP or GIF up to 1MB; shown next to the department\'s agents in chat',
   'dept.deleteTitle': 'Delete Department',
   'dept.deleteConfirmMsg': 'Are you sure you want to delete department {name}?',
   'dept.deleteHasMembers': 'This department has {n} members. Please move them out before deleting.',
@@ -829,6 +955,9 @@ const en: Record<TranslationKey, string> = {
   'dept.agentCount': '{n} Agents',
   'dept.allAgents': 'All Agents',
   'dept.grantedBy': 'Granted by: {name}',
+  'dept.expiresAt': 'Expires: {date}',
+  'dept.accessExpiry': 'Expires (optional)',
+  'dept.accessExpiryHint': 'Leave empty for permanent access',
   'dept.deleteFailed': 'Delete failed',
   'dept.deleteCannotUndo': 'This action cannot be undone',
   'dept.viewDetail': 'View Details',
@@ -841,6 +970,20 @@ const en: Record<TranslationKey, string> = {
   'instance.startFailed': 'Start failed',
   'instance.stopFailed': 'Stop failed',
   'instance.restartFailed': 'Restart failed',
+  'instance.announce': 'Announce',
+  'instance.announceTitle': 'Announce to Chat Users',
+  'instance.announceDesc': 'Shown to everyone chatting with {name}, including replies in progress',
+  'instance.announceMessage': 'Message',
+  'instance.announcePlaceholder': 'e.g. Maintenance restart in 10 minutes',
+  'instance.announceLevel': 'Level',
+  'instance.announceLevelInfo': 'Info',
+  'instance.announceLevelWarning': 'Warning',
+  'instance.announceDuration': 'Show for',
+  'instance.announceMinutes': '{n} minutes',
+  'instance.announceHours': '{n} hours',
+  'instance.announceSend': 'Send',
+  'instance.announceSent': 'Announcement sent to {name}',
+  'instance.announceFailed': 'Failed to send announcement',
   'agent.connectionFailed': 'The following instances failed to connect:',
   'skill.deleteConfirmTitle': 'Confirm Delete',
   'skill.deleteConfirmMsg': 'Are you sure you want to delete Skill "{name}"? This will delete all files and installation records and cannot be undone.',
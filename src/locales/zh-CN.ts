@@ -107,6 +107,11 @@ const zhCN = {
   'auth.loginSuccess': '登录成功',
   'auth.loginFailed': '登录失败，请重试',
   'auth.networkError': '网络错误，请检查连接后重试',
+  'auth.or': '或',
+  'auth.ssoLogin': '使用 {provider} 登录',
+  'auth.ssoFailed': '单点登录失败，请重试',
+  'auth.ssoNoAccount': '该身份没有可用的账号，请联系管理员',
+  'auth.accountDisabled': '账号已被禁用',
   'auth.noAccount': '还没有账号？',
   'auth.registerNow': '立即注册',
   'auth.registerTitle': '创建账号',
@@ -138,6 +143,30 @@ const zhCN = {
   'auth.tagline': '企业级 OpenClaw\n管理平台',
   'auth.taglineDesc': '统一管理 Instances、Agents、Skills，赋能团队高效协作。',
 
+  // ── MFA ─────────────────────────────────────────────────
+  'mfa.title': '两步验证',
+  'mfa.desc': '使用身份验证器应用生成的验证码保护账号',
+  'mfa.requiredDesc': '管理员要求启用两步验证，请先完成设置',
+  'mfa.code': '验证码',
+  'mfa.codeHint': '输入身份验证器应用中的 6 位验证码，或一个恢复码',
+  'mfa.verify': '验证',
+  'mfa.backToLogin': '返回登录',
+  'mfa.failed': '验证失败，请重试',
+  'mfa.recoveryCodesLeft': '已使用恢复码，剩余 {n} 个',
+  'mfa.start': '设置两步验证',
+  'mfa.scanDesc': '使用下方密钥将账号添加到身份验证器应用，然后输入应用显示的验证码',
+  'mfa.secret': '设置密钥',
+  'mfa.openInApp': '在身份验证器应用中打开',
+  'mfa.confirm': '确认并启用',
+  'mfa.enabled': '两步验证已启用',
+  'mfa.disabled': '两步验证已关闭',
+  'mfa.recoveryCodesDesc': '请妥善保存以下恢复码。设备丢失时每个恢复码可使用一次，之后不会再次显示。',
+  'mfa.savedCodes': '我已保存恢复码',
+  'mfa.statusOn': '两步验证已启用，剩余 {n} 个恢复码',
+  'mfa.regenerateCodes': '重新生成恢复码',
+  'mfa.disable': '关闭',
+  'mfa.backToApp': '返回 TeamClaw',
+
   // ── Instance ────────────────────────────────────────────
   'instance.management': '实例管理',
   'instance.managementDesc': '管理和监控所有 OpenClaw 实例',
@@ -156,6 +185,11 @@ const zhCN = {
   'instance.advancedOptions': '高级选项',
   'instance.memoryLimit': '内存限制 (MB)',
   'instance.restartPolicy': '重启策略',
+  'instance.sandboxProfile': '沙箱配置',
+  'instance.sandboxStandard': '标准（Docker 默认权限）',
+  'instance.sandboxHardened': '加固（不挂载 Docker socket，最小权限）',
+  'instance.sandboxIsolated': '隔离（只读文件系统，仅内部网络）',
+  'instance.sandboxProfileHint': '容器启动时生效；之后修改会在下次启动或重启时重建容器。',
   'instance.gatewayToken': 'Gateway Token',
   'instance.gatewayTokenPlaceholder': '连接令牌',
   'instance.externalGatewayHint': '连接一个已运行的外部 OpenClaw Gateway 实例',
@@ -333,6 +367,8 @@ const zhCN = {
   'chat.recentSessions': '最近会话',
   'chat.noSessions': '暂无会话记录',
   'chat.sessionDeleted': '会话已删除',
+  'chat.renameSession': '重命名对话',
+  'chat.closedDepartmentChange': '已归档：调整部门后无权访问',
   'chat.noAgents': '暂无可用的 Agent',
   'chat.newConversation': '新对话',
   'chat.newConversationTitle': '新对话',
@@ -346,19 +382,73 @@ const zhCN = {
   'chat.confirmClear': '确认清空',
   'chat.contextCleared': '上下文已清空',
   'chat.clearContextFailed': '清空上下文失败',
+  'chat.toolApprovalRequired': 'Agent 请求执行 {tool}，是否允许？',
+  'chat.toolApprovalApprove': '允许',
+  'chat.toolApprovalDeny': '拒绝',
+  'chat.toolApprovalHint': '回复将暂停直到你作出选择，超时未答复将自动拒绝。',
+  'chat.announcement': '公告',
+  'chat.announcementFrom': '来自 {name} 的公告',
+  'chat.announcementDismiss': '关闭',
+  'chat.reportProblem': '报告问题',
+  'chat.reportTitle': '报告问题',
+  'chat.reportDesc': '此回复及当前对话的诊断信息将发送给该实例的管理员。',
+  'chat.reportCategory': '问题类型',
+  'chat.reportIncorrect': '回答错误或编造内容',
+  'chat.reportUnsafe': '有害或不当内容',
+  'chat.reportBroken': '出错、工具失败或无回复',
+  'chat.reportOther': '其他',
+  'chat.reportDescription': '详细说明（可选）',
+  'chat.reportPlaceholder': '你期望得到什么样的回复？',
+  'chat.reportSubmit': '提交报告',
+  'chat.reportSent': '已将报告发送给管理员',
+  'chat.reportFailed': '提交报告失败',
   'chat.inputPlaceholder': '输入消息... (Enter 发送, Shift+Enter 换行)',
   'chat.uploadFile': '上传文件',
   'chat.fileTooLarge': '文件 "{name}" 超过大小限制（{limit}）',
+  'chat.uploadFailed': '上传 "{name}" 失败：{error}',
   'chat.maxAttachments': '最多只能上传 5 个附件',
   'chat.attachment': '(附件)',
   'chat.thinking': '思考过程',
+  'chat.queued': '实例繁忙，正在排队（第 {position} 位）...',
+  'chat.reconnecting': '与智能体的连接中断，正在重连（第 {attempt}/{max} 次）...',
   'chat.imageAlt': '图片',
   'chat.downloadImage': '下载图片',
   'chat.contextRestart': 'AI 上下文从此处重新开始',
   'chat.contextReset': '上下文已重置',
   'chat.loadingHistory': '加载历史消息…',
+  'chat.loadOlder': '加载更早的消息',
+  'chat.historySummary': '前 {n} 条消息的摘要',
   'chat.gatewayUnreachable': 'Gateway 连接中断，请刷新页面重试。',
   'chat.department': '部门',
+  'chat.defaultAgent': '部门默认 Agent',
+  'chat.share': '分享',
+  'chat.export': '导出',
+  'chat.exportMarkdown': 'Markdown (.md)',
+  'chat.exportJson': 'JSON (.json)',
+  'chat.shareTitle': '分享对话',
+  'chat.shareDesc': '任何拿到链接的人都可以只读查看本对话已保存的消息。创建链接之后发送的消息不会包含在内。',
+  'chat.shareExpiry': '有效期',
+  'chat.shareExpiry1d': '1 天',
+  'chat.shareExpiry7d': '7 天',
+  'chat.shareExpiry30d': '30 天',
+  'chat.sharePassword': '访问密码（可选）',
+  'chat.shareCreate': '创建链接',
+  'chat.shareCopied': '链接已复制到剪贴板',
+  'chat.shareFailed': '创建分享链接失败',
+  'chat.shareExisting': '已有链接',
+  'chat.shareViews': '{count} 次查看',
+  'chat.shareRevoke': '撤销',
+  'chat.shareRevoked': '链接已撤销',
+  'chat.shareStateActive': '有效',
+  'chat.shareStateExpired': '已过期',
+  'chat.shareStateRevoked': '已撤销',
+  'share.passwordRequired': '此对话需要密码才能查看',
+  'share.passwordPlaceholder': '请输入密码',
+  'share.view': '查看',
+  'share.invalid': '链接无效或已过期',
+  'share.sharedBy': '由 {name} 分享',
+  'share.expiresAt': '链接将于 {time} 失效',
+  'share.readOnly': '只读快照',
   'chat.personal': '个人',
   'chat.onlineStatus': '在线',
 
@@ -638,6 +728,8 @@ const zhCN = {
   'dashboard.action.INSTANCE_RESTART': '重启实例',
   'dashboard.action.INSTANCE_CONFIG_PATCH': '修改配置',
   'dashboard.action.INSTANCE_DASHBOARD': '访问控制台',
+  'dashboard.action.INSTANCE_SMOKE_TEST': '实例冒烟测试',
+  'dashboard.action.INSTANCE_LOAD_TEST': '实例压力测试',
   'dashboard.action.USER_CREATE': '创建用户',
   'dashboard.action.USER_UPDATE': '更新用户',
   'dashboard.action.USER_DELETE': '禁用用户',
@@ -667,6 +759,25 @@ const zhCN = {
   // Dashboard result labels
   'dashboard.resultFailure': '失败',
   'dashboard.resultDenied': '拒绝',
+  // Dashboard license card
+  'dashboard.license': '许可证',
+  'dashboard.licenseStateValid': '有效',
+  'dashboard.licenseStateGrace': '宽限期',
+  'dashboard.licenseStateExpired': '已过期',
+  'dashboard.licenseStateInvalid': '无效',
+  'dashboard.licenseSeats': '席位',
+  'dashboard.licenseInstances': '实例',
+  'dashboard.licenseUnlimited': '不限',
+  'dashboard.licenseExpires': '{date} 到期',
+  'dashboard.licenseGraceEnds': '宽限期将于 {date} 结束，届时将无法新增用户和实例',
+  'dashboard.licenseBlocked': '许可证续期前无法新增用户和实例',
+  'dashboard.versions': 'OpenClaw 版本',
+  'dashboard.versionsLatest': '最新 v{version}',
+  'dashboard.versionsLatestUnknown': '最新版本未知',
+  'dashboard.versionsUnreported': '未上报',
+  'dashboard.versionsUpToDate': '所有实例均为最新版本',
+  'dashboard.versionsNoChangelog': '暂无更新日志',
+  'dashboard.versionsFeedError': '版本源不可用：{error}',
 
   // ── Audit ───────────────────────────────────────────────
   'audit.searchPlaceholder': '搜索操作详情...',
@@ -734,6 +845,7 @@ const zhCN = {
   'user.statusLabel': '状态',
   'user.roleSystemAdmin': '系统管理员',
   'user.roleDeptAdmin': '部门管理员',
+  'user.roleViewer': '只读审计员',
   'user.roleUser': '普通用户',
   'user.statusActive': '正常',
   'user.statusDisabled': '已禁用',
@@ -766,6 +878,11 @@ const zhCN = {
   'user.createFailed': '创建失败',
   'user.updateFailed': '更新失败',
   'user.statusDisabledShort': '禁用',
+  'user.mfaRequired': '强制两步验证',
+  'user.mfaRequiredDesc': '用户需先设置两步验证才能使用 TeamClaw',
+  'user.resetMfa': '重置两步验证',
+  'user.resetMfaDesc': '移除身份验证器与恢复码，并使该用户退出登录',
+  'user.mfaResetMsg': '两步验证已重置',
 
   // ── Department ──────────────────────────────────────────
   'dept.management': '部门管理',
@@ -780,6 +897,15 @@ const zhCN = {
   'dept.editTitle': '编辑部门',
   'dept.editDesc': '修改部门信息',
   'dept.updatedMsg': '部门更新成功',
+  'dept.chatPriority': '对话优先级',
+  'dept.chatPriorityHigh': '高（生产支持）',
+  'dept.chatPriorityNormal': '普通',
+  'dept.chatPriorityLow': '低（实验）',
+  'dept.chatPriorityHint': '实例繁忙时，优先级高的排队对话先开始',
+  'dept.icon': '图标',
+  'dept.iconUpload': '上传',
+  'dept.iconRemove': '移除',
+  'dept.iconHint': 'PNG、JPEG、WebP 或 GIF，最大 1MB；在对话中显示在本部门的智能体旁',
   'dept.deleteTitle': '删除部门',
   'dept.deleteConfirmMsg': '确定要删除部门 {name} 吗？',
   'dept.deleteHasMembers': '该部门下还有 {n} 名成员，删除前请先将成员移出。',
@@ -827,6 +953,9 @@ const zhCN = {
   'dept.agentCount': '{n} 个 Agent',
   'dept.allAgents': '所有 Agent',
   'dept.grantedBy': '授权人: {name}',
+  'dept.expiresAt': '到期: {date}',
+  'dept.accessExpiry': '到期时间（可选）',
+  'dept.accessExpiryHint': '留空表示永久授权',
   'dept.deleteFailed': '删除失败',
   'dept.deleteCannotUndo': '此操作无法撤销',
   'dept.viewDetail': '查看详情',
@@ -839,6 +968,20 @@ const zhCN = {
   'instance.startFailed': '启动失败',
   'instance.stopFailed': '停止失败',
   'instance.restartFailed': '重启失败',
+  'instance.announce': '发布公告',
+  'instance.announceTitle': '向对话用户发布公告',
+  'instance.announceDesc': '正在与 {name} 对话的所有用户都会看到，包括正在生成的回复',
+  'instance.announceMessage': '公告内容',
+  'instance.announcePlaceholder': '例如：10 分钟后维护重启',
+  'instance.announceLevel': '级别',
+  'instance.announceLevelInfo': '通知',
+  'instance.announceLevelWarning': '警告',
+  'instance.announceDuration': '显示时长',
+  'instance.announceMinutes': '{n} 分钟',
+  'instance.announceHours': '{n} 小时',
+  'instance.announceSend': '发布',
+  'instance.announceSent': '已向 {name} 发布公告',
+  'instance.announceFailed': '发布公告失败',
   'agent.connectionFailed': '以下实例连接失败：',
   'skill.deleteConfirmTitle': '确认删除',
   'skill.deleteConfirmMsg': '确定要删除 Skill「{name}」吗？此操作将删除所有文件和安装记录，且不可恢复。',
@@ -1,8 +1,11 @@
 import { NextRequest, NextResponse } from 'next/server'
 import { jwtVerify, importSPKI } from 'jose'
+import { checkBodyLimit } from '@/lib/body-limits'
 
 const ALG = 'RS256'
 const ISSUER = 'teamclaw'
+// Same as lib/auth/api-keys, which uses Node crypto and cannot load here
+const API_KEY_PREFIX = 'tck_'
 
 const PUBLIC_PATHS = [
   '/login',
@@ -10,10 +13,23 @@ const PUBLIC_PATHS = [
   '/api/v1/auth/login',
   '/api/v1/auth/register',
   '/api/v1/auth/refresh',
+  '/api/v1/auth/oidc', // SSO redirect flow (login / callback)
+  '/api/v1/auth/mfa/verify', // Second login step; carries its own MFA token
+  '/api/v1/hooks/', // Inbound integrations authenticate with their own secret
+  '/api/v1/shared/', // Public session share links (token in URL)
+  '/api/v1/widget/', // Embedded chat widgets authenticate with a widget token
+  '/api/v1/branding',
+  '/api/v1/metrics', // Prometheus scrape; checks METRICS_TOKEN itself
+  '/share/',
   '/_next',
   '/favicon.ico',
 ]
 
+// Public routes nested under authenticated ones
+const PUBLIC_PATTERNS = [
+  /^\/api\/v1\/files\/[^/]+\/download$/, // Signed file download URLs (signature in query)
+]
+
 let cachedPublicKey: CryptoKey | null = null
 
 async function getPublicKey(): Promise<CryptoKey> {
@@ -26,7 +42,7 @@ async function getPublicKey(): Promise<CryptoKey> {
 }
 
 function isPublicPath(pathname: string): boolean {
-  return PUBLIC_PATHS.some((p) => pathname.startsWith(p))
+  return PUBLIC_PATHS.some((p) => pathname.startsWith(p)) || PUBLIC_PATTERNS.some((re) => re.test(pathname))
 }
 
 function isApiRoute(pathname: string): boolean {
@@ -36,10 +52,32 @@ function isApiRoute(pathname: string): boolean {
 export async function middleware(req: NextRequest) {
   const { pathname } = req.nextUrl
 
+  // Applies to public routes too — they are the easiest to flood
+  if (isApiRoute(pathname)) {
+    const tooLarge = checkBodyLimit(req, pathname)
+    if (tooLarge) return tooLarge
+  }
+
   if (isPublicPath(pathname)) {
+    // Only the token verification below may assert a break-glass session
+    if (req.headers.has('x-break-glass')) {
+      const headers = new Headers(req.headers)
+      headers.delete('x-break-glass')
+      return NextResponse.next({ request: { headers } })
+    }
     return NextResponse.next()
   }
 
+  // API keys (lib/auth/api-keys) are looked up in the database by withAuth;
+  // the edge runtime cannot, so only drop identity headers a caller could forge
+  if (isApiRoute(pathname) && req.headers.get('authorization')?.startsWith(`Bearer ${API_KEY_PREFIX}`)) {
+    const headers = new Headers(req.headers)
+    for (const name of ['x-user-id', 'x-user-role', 'x-user-email', 'x-break-glass']) {
+      headers.delete(name)
+    }
+    return NextResponse.next({ request: { headers } })
+  }
+
   const token = req.cookies.get('access_token')?.value
 
   if (!token) {
@@ -54,7 +92,7 @@ export async function middleware(req: NextRequest) {
     const key = await getPublicKey()
     const { payload } = await jwtVerify(token, key, { issuer: ISSUER })
 
-    if (!payload.userId || !payload.role) {
+    if (!payload.userId || !payload.role || payload.purpose) {
       throw new Error('Invalid token payload')
     }
 
@@ -64,6 +102,11 @@ export async function middleware(req: NextRequest) {
     if (payload.email) {
       headers.set('x-user-email', payload.email as string)
     }
+    if (payload.breakGlassId) {
+      headers.set('x-break-glass', payload.breakGlassId as string)
+    } else {
+      headers.delete('x-break-glass')
+    }
 
     return NextResponse.next({ request: { headers } })
   } catch {
@@ -9,6 +9,10 @@ export interface AuthUser {
   departmentId: string | null
   departmentName: string | null
   avatar: string | null
+  /** MFA is required but not set up yet: only /mfa-setup is usable */
+  mfaSetupRequired?: boolean
+  /** Custom role whose permissions add to the role's */
+  customRole?: { id: string; name: string; permissions: string[] }
 }
 
 interface AuthState {
@@ -16,7 +20,9 @@ interface AuthState {
   isLoading: boolean
   setUser: (user: AuthUser | null) => void
   fetchUser: () => Promise<void>
-  login: (email: string, password: string) => Promise<void>
+  /** Resolves with an MFA token when the account needs a second factor */
+  login: (email: string, password: string) => Promise<{ mfaToken: string } | null>
+  verifyMfa: (mfaToken: string, code: string) => Promise<{ recoveryCodesLeft?: number }>
   register: (email: string, password: string, name: string) => Promise<void>
   logout: () => Promise<void>
 }
@@ -73,9 +79,26 @@ export const useAuthStore = create<AuthState>((set, get) => {
     },
 
     login: async (email, password) => {
-      await api.post("/api/v1/auth/login", { email, password })
+      const res = await api.post<{ status?: string; mfaToken?: string }>(
+        "/api/v1/auth/login",
+        { email, password },
+      )
+      if (res.status === "mfa_required" && res.mfaToken) {
+        return { mfaToken: res.mfaToken }
+      }
+      await get().fetchUser()
+      broadcast("login", get().user)
+      return null
+    },
+
+    verifyMfa: async (mfaToken, code) => {
+      const res = await api.post<{ recoveryCodesLeft?: number }>(
+        "/api/v1/auth/mfa/verify",
+        { mfaToken, code },
+      )
       await get().fetchUser()
       broadcast("login", get().user)
+      return res
     },
 
     register: async (email, password, name) => {
@@ -1,6 +1,7 @@
 import { create } from 'zustand'
-import { streamChat } from '@/lib/chat-stream'
-import type { ChatAgentInfo, ChatMessage, ChatToolCall, ChatHistoryResponse, ChatAttachment, ChatContentBlock } from '@/types/chat'
+import { streamChat, resumeChatRun, type ChatStreamEntry } from '@/lib/chat-stream'
+import { api } from '@/lib/api-client'
+import type { ChatAgentInfo, ChatMessage, ChatToolCall, ChatHistoryResponse, ChatAttachment, ChatContentBlock, ChatStreamToolApprovalEvent, ChatAnnouncement } from '@/types/chat'
 
 interface ChatState {
   // Selected agent
@@ -18,6 +19,7 @@ interface ChatState {
   appendAssistantImage: (imageUrl: string, mimeType?: string, alt?: string) => void
   appendThinking: (content: string) => void
   appendToolCall: (toolCall: ChatToolCall) => void
+  setToolProgress: (toolName: string, output: string | undefined) => void
   setAssistantError: (error: string) => void
   completeAssistantMessage: () => void
 
@@ -30,6 +32,18 @@ interface ChatState {
   isStreaming: boolean
   setStreaming: (v: boolean) => void
   abortController: AbortController | null
+  // Run being streamed; stopStreaming aborts it on the server too
+  activeRunId: string | null
+  stopStreaming: () => void
+  // Why the run is not producing output yet: waiting for an instance slot or for the gateway
+  streamStatus: { type: 'queued'; position: number } | { type: 'reconnecting'; attempt: number; maxAttempts: number } | null
+
+  // Sensitive tool call waiting for the user's confirmation (stream is paused)
+  pendingToolApproval: Omit<ChatStreamToolApprovalEvent, 'type'> | null
+  answerToolApproval: (decision: 'approve' | 'deny') => Promise<void>
+
+  // Instance announcements pushed during a run, ahead of the next announcements poll
+  streamAnnouncements: ChatAnnouncement[]
 
   // Send message action
   sendMessage: (
@@ -37,7 +51,7 @@ interface ChatState {
     agentId: string,
     message: string,
     sessionId?: string,
-    attachments?: { name: string; content: string; mimeType: string; size: number; dataUrl: string }[],
+    attachments?: { name: string; fileId: string; mimeType: string; size: number; dataUrl: string }[],
   ) => Promise<void>
 
   // Session management
@@ -52,6 +66,21 @@ interface ChatState {
   setSidebarOpen: (v: boolean) => void
 }
 
+// Re-attaching to a run after its stream broke; waits grow by RESUME_DELAY_MS
+// per attempt, enough to ride out a server restart
+const RESUME_ATTEMPTS = 5
+const RESUME_DELAY_MS = 2000
+
+function wait(ms: number, signal: AbortSignal): Promise<void> {
+  return new Promise((resolve, reject) => {
+    const timer = setTimeout(resolve, ms)
+    signal.addEventListener('abort', () => {
+      clearTimeout(timer)
+      reject(new DOMException('Aborted', 'AbortError'))
+    }, { once: true })
+  })
+}
+
 /**
  * After streaming completes, replace messages with full history from the API.
  *
@@ -60,13 +89,17 @@ interface ChatState {
  * so the streaming view is incomplete. By syncing from history after streaming,
  * we get the full picture: all thinking blocks, tool calls, images, etc.
  * This ensures consistency between the post-streaming view and page refresh.
+ * History is paged; the page asked for is at least as long as the list shown,
+ * so earlier pages the user loaded stay in view.
  */
 async function syncFromHistory(
   activeSessionId: string,
   set: (fn: (s: ChatState) => Partial<ChatState>) => void,
+  shownCount: number,
 ) {
   try {
-    const res = await fetch(`/api/v1/chat/sessions/${activeSessionId}/history`, {
+    const limit = Math.max(shownCount, 100)
+    const res = await fetch(`/api/v1/chat/sessions/${activeSessionId}/history?limit=${limit}`, {
       credentials: 'include',
     })
     if (!res.ok) return
@@ -163,6 +196,23 @@ export const useChatStore = create<ChatState>((set, get) => ({
     })
   },
 
+  setToolProgress: (toolName, output) => {
+    set((s) => {
+      const msgs = [...s.messages]
+      const last = msgs[msgs.length - 1]
+      if (last?.role !== 'assistant' || !last.toolCalls) return s
+      // The newest call of this tool that has no result yet
+      const index = last.toolCalls.findLastIndex(
+        (tc) => tc.toolName === toolName && tc.toolOutput == null && tc.toolInput != null,
+      )
+      if (index < 0) return s
+      const toolCalls = [...last.toolCalls]
+      toolCalls[index] = { ...toolCalls[index], toolProgress: output }
+      msgs[msgs.length - 1] = { ...last, toolCalls }
+      return { messages: msgs }
+    })
+  },
+
   appendToolCall: (toolCall) => {
     set((s) => {
       const msgs = [...s.messages]
@@ -204,6 +254,18 @@ export const useChatStore = create<ChatState>((set, get) => ({
   isStreaming: false,
   setStreaming: (v) => set({ isStreaming: v }),
   abortController: null,
+  activeRunId: null,
+  streamStatus: null,
+
+  pendingToolApproval: null,
+  streamAnnouncements: [],
+  answerToolApproval: async (decision) => {
+    const pending = get().pendingToolApproval
+    if (!pending) return
+    set({ pendingToolApproval: null })
+    // The stream reports the outcome (resumes, or ends with an error)
+    await api.post(`/api/v1/chat/tool-approvals/${pending.approvalId}`, { decision }).catch(() => {})
+  },
 
   sendMessage: async (instanceId, agentId, message, sessionId, attachments) => {
     const { addUserMessage } = get()
@@ -233,69 +295,135 @@ export const useChatStore = create<ChatState>((set, get) => ({
     const controller = new AbortController()
     set({ isStreaming: true, abortController: controller })
 
-    try {
-      // 4. Stream events
-      // Build attachments payload (base64 only, no data URL prefix)
-      const streamAttachments = attachments?.map(a => ({
-        name: a.name,
-        content: a.content,
-        mimeType: a.mimeType,
-      }))
-
-      for await (const event of streamChat(
-        { instanceId, agentId, message, sessionId, attachments: streamAttachments },
-        controller.signal,
-      )) {
-        switch (event.type) {
-          case 'session':
-            // API sends the session ID as the first event — track it
-            // so syncFromHistory works even when no sessionId was passed
-            capturedSessionId = event.sessionId
-            if (!get().activeSessionId) {
-              set({ activeSessionId: event.sessionId })
-            }
-            break
-          case 'text':
-            get().appendAssistantContent(event.content)
-            break
-          case 'thinking':
-            get().appendThinking(event.content)
-            break
-          case 'tool_call':
-            get().appendToolCall({
+    // Run of this send, from the session event; the stream can be re-attached
+    // from the last event id if it breaks
+    let runId = null as string | null
+    let lastEventId = 0
+    let finished = false
+
+    const handleEvent = ({ id, event }: ChatStreamEntry) => {
+      if (id) lastEventId = id
+      if (event.type === 'done' || event.type === 'aborted' || event.type === 'error') finished = true
+      if (
+        event.type !== 'session' &&
+        event.type !== 'queued' &&
+        event.type !== 'reconnecting' &&
+        event.type !== 'system' &&
+        get().streamStatus
+      ) {
+        set({ streamStatus: null })
+      }
+      switch (event.type) {
+        case 'queued':
+          set({ streamStatus: { type: 'queued', position: event.position } })
+          break
+        case 'reconnecting':
+          set({ streamStatus: { type: 'reconnecting', attempt: event.attempt, maxAttempts: event.maxAttempts } })
+          break
+        case 'session':
+          // API sends the session ID as the first event — track it
+          // so syncFromHistory works even when no sessionId was passed
+          capturedSessionId = event.sessionId
+          runId = event.runId ?? null
+          set({ activeRunId: runId })
+          if (!get().activeSessionId) {
+            set({ activeSessionId: event.sessionId })
+          }
+          break
+        case 'text':
+          get().appendAssistantContent(event.content)
+          break
+        case 'thinking':
+          get().appendThinking(event.content)
+          break
+        case 'tool_call':
+          get().appendToolCall({
+            toolName: event.toolName,
+            toolInput: event.toolInput,
+          })
+          break
+        case 'tool_progress':
+          get().setToolProgress(event.toolName, event.output)
+          break
+        case 'tool_result':
+          get().setToolProgress(event.toolName, undefined)
+          get().appendToolCall({
+            toolName: event.toolName,
+            toolInput: null,
+            toolOutput: event.toolOutput,
+          })
+          break
+        case 'tool_approval_required':
+          set({
+            pendingToolApproval: {
+              approvalId: event.approvalId,
               toolName: event.toolName,
               toolInput: event.toolInput,
-            })
-            break
-          case 'tool_result':
-            get().appendToolCall({
-              toolName: event.toolName,
-              toolInput: null,
-              toolOutput: event.toolOutput,
-            })
-            break
-          case 'image':
-            get().appendAssistantImage(event.imageUrl, event.mimeType, event.alt)
-            break
-          case 'error':
-            get().setAssistantError(event.error)
-            break
-          case 'done':
-            get().completeAssistantMessage()
-            break
+              expiresAt: event.expiresAt,
+            },
+          })
+          break
+        case 'system':
+          set((s) => ({ streamAnnouncements: [...s.streamAnnouncements, event.announcement] }))
+          break
+        case 'image':
+          get().appendAssistantImage(event.imageUrl, event.mimeType, event.alt)
+          break
+        case 'error':
+          get().setAssistantError(event.error)
+          break
+        case 'done':
+        case 'aborted':
+          get().completeAssistantMessage()
+          break
+      }
+    }
+
+    try {
+      // 4. Stream events
+      // Attachments were uploaded when picked; the message references them
+      const streamAttachments = attachments?.map(a => ({ fileId: a.fileId }))
+
+      let lostError: unknown = null
+      try {
+        for await (const entry of streamChat(
+          { instanceId, agentId, message, sessionId, attachments: streamAttachments },
+          controller.signal,
+        )) {
+          handleEvent(entry)
         }
+      } catch (err) {
+        // Without a run there is nothing to re-attach to: the send itself failed
+        if (!runId || (err as Error).name === 'AbortError') throw err
+        lostError = err
       }
+
+      // The stream broke before the run ended (network drop, server restart):
+      // re-attach from the last event received
+      for (let attempt = 1; runId && !finished && attempt <= RESUME_ATTEMPTS; attempt++) {
+        set({ streamStatus: { type: 'reconnecting', attempt, maxAttempts: RESUME_ATTEMPTS } })
+        await wait(attempt * RESUME_DELAY_MS, controller.signal)
+        try {
+          for await (const entry of resumeChatRun(runId, lastEventId, controller.signal)) {
+            handleEvent(entry)
+          }
+        } catch (err) {
+          if ((err as Error).name === 'AbortError') throw err
+          lostError = err
+        }
+      }
+      if (runId && !finished) throw lostError ?? new Error('Connection to the server was lost')
     } catch (err) {
       if ((err as Error).name !== 'AbortError') {
         get().setAssistantError((err as Error).message || 'Failed to send message')
       }
     } finally {
-      set({ isStreaming: false, abortController: null })
+      set({ isStreaming: false, abortController: null, activeRunId: null, pendingToolApproval: null, streamStatus: null })
 
       // 5. Sync with full history (gateway omits thinking + tool events during streaming)
       // Use captured ID to avoid reading a stale/changed activeSessionId
       if (capturedSessionId) {
-        syncFromHistory(capturedSessionId, set)
+        syncFromHistory(capturedSessionId, set, get().messages.length)
       }
     }
   },
@@ -303,7 +431,19 @@ export const useChatStore = create<ChatState>((set, get) => ({
   clearMessages: () => {
     const { abortController } = get()
     if (abortController) abortController.abort()
-    set({ messages: [], isStreaming: false, abortController: null, activeSessionId: null, connectionStatus: 'ok' })
+    set({ messages: [], isStreaming: false, abortController: null, activeRunId: null, pendingToolApproval: null, streamStatus: null, activeSessionId: null, connectionStatus: 'ok' })
+  },
+
+  stopStreaming: () => {
+    const { abortController, activeRunId, activeSessionId } = get()
+    // Closing the stream leaves the run going on the server, so stop it there
+    if (activeRunId) {
+      api.delete(`/api/v1/chat/runs/${activeRunId}`).catch(() => {})
+    } else if (activeSessionId) {
+      // Not acknowledged by the gateway yet: stop whatever runs on the session
+      api.post(`/api/v1/chat/sessions/${activeSessionId}/abort`).catch(() => {})
+    }
+    abortController?.abort()
   },
 
   connectionStatus: 'ok',
@@ -0,0 +1,34 @@
+export type AccessReviewItemKind = 'instance_access' | 'role'
+export type AccessReviewDecisionValue = 'PENDING' | 'KEEP' | 'REVOKE' | 'AUTO_REVOKED'
+
+export interface AccessReviewProgress {
+  total: number
+  pending: number
+  kept: number
+  revoked: number
+}
+
+export interface AccessReviewCampaignResponse {
+  id: string
+  name: string
+  status: 'OPEN' | 'CLOSED'
+  dueAt: string
+  autoRevoke: boolean
+  createdByName: string | null
+  closedAt: string | null
+  createdAt: string
+  progress: AccessReviewProgress
+}
+
+export interface AccessReviewItemResponse {
+  id: string
+  kind: AccessReviewItemKind
+  subjectId: string
+  departmentId: string | null
+  /** Snapshot at campaign creation: department/instance/agent names or user/role */
+  summary: Record<string, unknown>
+  decision: AccessReviewDecisionValue
+  reviewerName: string | null
+  comment: string | null
+  decidedAt: string | null
+}
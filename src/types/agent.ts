@@ -25,6 +25,7 @@ export interface AgentOverview {
   category?: AgentCategory
   departmentName?: string | null
   ownerName?: string | null
+  iconUrl?: string | null  // Signed URL of the agent's icon (PUT /agents/[id]/icon)
 }
 
 /** Agent detail with full config */
@@ -53,3 +54,86 @@ export interface AgentDefaultsResponse {
   defaults: AgentDefaults
   hash: string             // config hash for subsequent patches
 }
+
+/** Result of reconciling one instance's agents with AgentMeta */
+export interface AgentSyncResult {
+  instanceId: string
+  agentCount: number
+  created: string[]       // agent IDs that got a new DEFAULT meta
+  restored: number        // previously missing agents that reappeared
+  newlyMissing: number    // metas flagged missing in this run
+}
+
+/** One side of a canary split, as measured over its sessions */
+export interface CanaryArmStats {
+  agentId: string
+  sessions: number
+  runs: number
+  abortRate: number        // aborted runs / runs
+  errorRate: number        // failed runs / runs
+  firstTokenMs: { avg: number; p50: number; p95: number }
+  durationMs: { avg: number; p50: number; p95: number }
+  reports: number          // support tickets filed on its sessions
+  reportsPerSession: number
+}
+
+/** Control vs canary comparison */
+export interface CanaryReport {
+  canaryId: string
+  percent: number
+  since: string
+  control: CanaryArmStats
+  canary: CanaryArmStats
+}
+
+/** An agent's config entry and prompt files at one point in time */
+export interface AgentVersion {
+  agentId: string
+  /** SHA-256 of the config entry and prompt file hashes together */
+  hash: string
+  config: Record<string, unknown> | null
+  /** Top-level Markdown files of the workspace (AGENTS.md, SOUL.md, ...) */
+  promptFiles: { name: string; sha256: string; size: number }[]
+  capturedAt: string
+}
+
+/** Canary minus control, per metric */
+export interface ExperimentDeltas {
+  abortRate: number
+  errorRate: number
+  firstTokenP50Ms: number
+  durationP50Ms: number
+  reportsPerSession: number
+}
+
+export interface ExperimentResults {
+  report: CanaryReport
+  deltas: ExperimentDeltas
+  /** Agents whose version differs between start and stop (or now) */
+  changedDuringRun: string[]
+  computedAt: string
+}
+
+export type EvalScorer = 'exact' | 'regex' | 'judge'
+
+/** A golden-set case; `expected` is the text, pattern or judging criteria for the scorer */
+export interface EvalCase {
+  key: string
+  prompt: string
+  scorer: EvalScorer
+  expected: string
+}
+
+/** How a case changed between a baseline run and this one */
+export type EvalCaseChange = 'regressed' | 'fixed' | 'unchanged' | 'new'
+
+export interface EvalComparison {
+  baselineRunId: string
+  /** The agent version differs from the baseline's */
+  versionChanged: boolean
+  regressed: string[]
+  fixed: string[]
+  /** Keys in this run that the baseline did not have */
+  added: string[]
+  passRateDelta: number
+}
@@ -18,3 +18,16 @@ export interface AuditLogListResponse {
   page: number
   pageSize: number
 }
+
+export interface AuditFieldChange {
+  field: string
+  before: unknown
+  after: unknown
+}
+
+export interface AuditDiffResponse {
+  log: AuditLogEntry
+  changes: AuditFieldChange[]
+  /** Unified-diff style rendering: "- field: old" / "+ field: new" */
+  text: string
+}
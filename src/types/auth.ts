@@ -6,6 +6,14 @@ export interface AuthUser {
   departmentId: string | null
   departmentName: string | null
   avatar: string | null
+  /** Set while the request runs under a break-glass session (role is SYSTEM_ADMIN) */
+  breakGlassId?: string
+  /** MFA is required for the account but not set up yet; only enrolment is allowed */
+  mfaSetupRequired?: boolean
+  /** Set when the request authenticated with an API key; role is the key's */
+  apiKey?: { id: string; scopes: string[] }
+  /** Custom role whose permissions add to the role's (not applied to API keys) */
+  customRole?: { id: string; name: string; permissions: string[] }
 }
 
 export interface JWTPayload {
@@ -0,0 +1,29 @@
+/** Theme tokens a deployment may override; each maps to a CSS variable */
+export const BRANDING_COLOR_KEYS = [
+  'primary',
+  'primaryForeground',
+  'accent',
+  'accentForeground',
+  'ring',
+  'sidebar',
+  'sidebarForeground',
+  'sidebarPrimary',
+] as const
+
+export type BrandingColorKey = (typeof BRANDING_COLOR_KEYS)[number]
+
+export type BrandingPalette = Partial<Record<BrandingColorKey, string>>
+
+export interface LoginBanner {
+  title: string
+  subtitle?: string
+}
+
+export interface Branding {
+  productName: string
+  /** null = built-in logo */
+  logoUrl: string | null
+  colors: BrandingPalette
+  darkColors: BrandingPalette
+  loginBanner: LoginBanner | null
+}
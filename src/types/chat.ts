@@ -9,6 +9,8 @@ export interface ChatAgentInfo {
   model?: string
   category?: AgentCategory
   hasContainer?: boolean
+  isDefault?: boolean     // Department's default agent for new conversations
+  iconUrl?: string | null // Agent icon, or its department's for department agents
 }
 
 // Structured content block — represents a single piece of content in a message
@@ -39,6 +41,8 @@ export interface ChatSessionResponse {
   lastMessageAt: string | null
   messageCount: number
   isActive: boolean
+  /** Why TeamClaw closed the session on the user's behalf, e.g. "department_change" */
+  closedReason: string | null
   createdAt: string
 }
 
@@ -48,11 +52,88 @@ export interface ChatSnapshotBatch {
   messages: ChatMessage[]
 }
 
+/**
+ * Where a history page lies on the session's timeline (snapshot messages,
+ * then live messages): positions [start, end) of `total`.
+ */
+export interface ChatHistoryPage {
+  start: number
+  end: number
+  total: number
+  hasOlder: boolean
+}
+
+/** Agent-written summary of a session's compacted history */
+export interface ChatHistorySummary {
+  text: string
+  messagesSummarized: number
+  coversUntil: string
+}
+
 export interface ChatHistoryResponse {
   snapshots: ChatSnapshotBatch[]
   currentMessages: ChatMessage[]
   isActive: boolean
   connectionStatus?: 'ok' | 'unreachable'
+  page?: ChatHistoryPage
+  summary?: ChatHistorySummary
+}
+
+/** A user's session as seen by a compliance reviewer */
+export interface InspectedSessionResponse {
+  session: ChatSessionResponse
+  snapshots: ChatSnapshotBatch[]
+  /** Live messages of an active session, as of its last completed run */
+  liveMessages: ChatMessage[]
+  toolInvocations: ToolInvocationEntry[]
+}
+
+/** Public read-only rendering of a shared session */
+export interface SharedSessionResponse {
+  title: string | null
+  agentId: string
+  instanceName: string
+  sharedBy: string
+  sharedAt: string
+  expiresAt: string
+  snapshots: ChatSnapshotBatch[]
+}
+
+/** A stretch of a search hit's text; `match` marks the query terms */
+export interface ChatSearchFragment {
+  text: string
+  match: boolean
+}
+
+export interface ChatSearchResult {
+  /** Snapshot or live message id */
+  messageId: string
+  source: 'snapshot' | 'live'
+  role: 'user' | 'assistant'
+  createdAt: string
+  session: Pick<ChatSessionResponse, 'id' | 'instanceId' | 'instanceName' | 'agentId' | 'title' | 'isActive'>
+  /** Up to three passages around the matches, `…`-separated */
+  fragments: ChatSearchFragment[]
+}
+
+export interface ChatSearchResponse {
+  results: ChatSearchResult[]
+  total: number
+  page: number
+  pageSize: number
+}
+
+export interface SessionShareInfo {
+  id: string
+  chatSessionId: string
+  passwordProtected: boolean
+  state: 'active' | 'expired' | 'revoked'
+  expiresAt: string
+  revokedAt: string | null
+  viewCount: number
+  lastViewedAt: string | null
+  createdAt: string
+  url?: string
 }
 
 export interface ChatMessage {
@@ -65,12 +146,43 @@ export interface ChatMessage {
   error?: string
   createdAt: string
   attachments?: ChatAttachment[]     // user-uploaded attachments
+  html?: string                      // sanitized HTML of content, only with ?render=html
 }
 
 export interface ChatToolCall {
   toolName: string
   toolInput: unknown
   toolOutput?: unknown
+  toolProgress?: string              // partial output while the tool is still running
+}
+
+export type ToolInvocationStatus = 'RUNNING' | 'SUCCESS' | 'ERROR' | 'BLOCKED' | 'DENIED' | 'ABORTED'
+
+/** One recorded tool call of an agent run */
+export interface ToolInvocationEntry {
+  id: string
+  chatSessionId: string
+  runId: string
+  toolCallId: string | null
+  userId: string
+  userName: string | null
+  instanceId: string
+  agentId: string
+  name: string
+  args: unknown
+  result: unknown
+  truncated: boolean
+  status: ToolInvocationStatus
+  durationMs: number | null
+  startedAt: string
+  finishedAt: string | null
+}
+
+export interface ToolInvocationListResponse {
+  invocations: ToolInvocationEntry[]
+  total: number
+  page: number
+  pageSize: number
 }
 
 // SSE event types from /api/v1/chat/send
@@ -96,6 +208,23 @@ export interface ChatStreamToolResultEvent {
   toolOutput: unknown
 }
 
+/** Output so far of a running tool; each event replaces the previous one */
+export interface ChatStreamToolProgressEvent {
+  type: 'tool_progress'
+  toolName: string
+  toolCallId?: string
+  output: string
+}
+
+/** A sensitive tool call is waiting for the user's confirmation; the stream pauses until answered */
+export interface ChatStreamToolApprovalEvent {
+  type: 'tool_approval_required'
+  approvalId: string
+  toolName: string
+  toolInput: unknown
+  expiresAt: string
+}
+
 export interface ChatStreamErrorEvent {
   type: 'error'
   error: string
@@ -110,11 +239,51 @@ export interface ChatStreamImageEvent {
 
 export interface ChatStreamDoneEvent {
   type: 'done'
+  /** Optional events (thinking, tools, images) skipped because the client fell behind */
+  droppedEvents?: number
+}
+
+/** An administrator's notice to everyone chatting with an instance */
+export interface ChatAnnouncement {
+  id: string
+  instanceId: string
+  message: string
+  level: 'INFO' | 'WARNING'
+  expiresAt: string
+  createdAt: string
+  createdByName: string | null
+}
+
+/** An instance announcement made while the run was streaming */
+export interface ChatStreamSystemEvent {
+  type: 'system'
+  announcement: ChatAnnouncement
+}
+
+/** The run was stopped (chat.abort); what streamed so far stands */
+export interface ChatStreamAbortedEvent {
+  type: 'aborted'
 }
 
 export interface ChatStreamSessionEvent {
   type: 'session'
   sessionId: string
+  /** Run to re-attach to (GET /api/v1/chat/runs/[id]/stream) if the stream breaks */
+  runId?: string
+}
+
+/** The instance is at its concurrency limit; the run starts when a slot frees up */
+export interface ChatStreamQueuedEvent {
+  type: 'queued'
+  /** 1-based place in the instance's queue */
+  position: number
+}
+
+/** The gateway connection dropped while sending; the send is retried once it is back */
+export interface ChatStreamReconnectingEvent {
+  type: 'reconnecting'
+  attempt: number
+  maxAttempts: number
 }
 
 export type ChatStreamEvent =
@@ -122,7 +291,13 @@ export type ChatStreamEvent =
   | ChatStreamThinkingEvent
   | ChatStreamToolCallEvent
   | ChatStreamToolResultEvent
+  | ChatStreamToolProgressEvent
+  | ChatStreamToolApprovalEvent
   | ChatStreamErrorEvent
   | ChatStreamImageEvent
   | ChatStreamDoneEvent
+  | ChatStreamAbortedEvent
+  | ChatStreamSystemEvent
   | ChatStreamSessionEvent
+  | ChatStreamQueuedEvent
+  | ChatStreamReconnectingEvent
@@ -37,7 +37,41 @@ export interface RecentActivity {
 
 export interface DashboardResponse {
   stats: DashboardStats
+  statsRefreshedAt: string
   instanceHealth: InstanceHealthCard[]
   providerDistribution: ProviderDistribution[]
   recentActivity: RecentActivity[]
 }
+
+// ─── Version Advisories ──────────────────────────────────────────────
+
+export interface ReleaseNote {
+  version: string
+  date: string | null
+  notes: string
+}
+
+export interface VersionInventoryEntry {
+  /** null = instance hasn't reported a version yet */
+  version: string | null
+  count: number
+}
+
+export interface OutdatedInstance {
+  id: string
+  name: string
+  status: string
+  version: string
+  /** Releases between the instance's version and latest, newest first */
+  changelog: ReleaseNote[]
+}
+
+export interface VersionAdvisory {
+  latest: string | null
+  /** Where `latest` came from: the release feed, OPENCLAW_LATEST_VERSION, or nothing */
+  source: 'feed' | 'static' | null
+  checkedAt: string
+  feedError: string | null
+  inventory: VersionInventoryEntry[]
+  outdated: OutdatedInstance[]
+}
@@ -2,13 +2,24 @@ export interface DepartmentResponse {
   id: string
   name: string
   description: string | null
+  /** Queue tier for chat requests when an instance is at its concurrency limit */
+  chatPriority: 'HIGH' | 'NORMAL' | 'LOW'
+  /** Signed URL of the department icon (PUT /departments/[id]/icon), or null */
+  iconUrl: string | null
   userCount: number
   accessCount: number
   createdAt: string
   updatedAt: string
 }
 
+export interface DepartmentChatDefaults {
+  defaultInstanceId: string | null
+  defaultAgentId: string | null
+  welcomePrompt: string | null
+}
+
 export interface DepartmentDetailResponse extends DepartmentResponse {
+  chatDefaults: DepartmentChatDefaults
   users: {
     id: string
     name: string
@@ -23,7 +34,19 @@ export interface DepartmentDetailResponse extends DepartmentResponse {
     instanceName: string
     instanceStatus: string
     agentIds: string[] | null
+    expiresAt: string | null
     grantedByName: string
     createdAt: string
   }[]
 }
+
+/** Per-department limits on agent tool calls (null list = no restriction) */
+export interface EgressPolicyResponse {
+  departmentId: string
+  enabled: boolean
+  allowedTools: string[] | null
+  deniedTools: string[] | null
+  allowedDomains: string[] | null
+  deniedDomains: string[] | null
+  updatedAt: string | null
+}
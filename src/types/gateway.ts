@@ -202,6 +202,8 @@ export interface ChatHistoryMessage {
 export interface ChatHistoryResult {
   sessionId: string
   messages: ChatHistoryMessage[]
+  /** Older messages remain (gateways that page chat.history with `offset`) */
+  hasMore?: boolean
 }
 
 // ─── Config Schema (from config.schema RPC) ────────────────────────
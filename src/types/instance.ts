@@ -6,6 +6,8 @@ export interface InstanceResponse {
   id: string
   name: string
   description: string | null
+  /** Data residency label; null = untagged */
+  region: string | null
   gatewayUrl: string
   // gatewayToken is NEVER returned
   containerId: string | null
@@ -19,6 +21,24 @@ export interface InstanceResponse {
   createdById: string
   createdAt: string
   updatedAt: string
+  /** Only with ?include=live on the list */
+  live?: InstanceLiveInfo
+}
+
+export interface InstanceContainerState {
+  /** Docker state (running, exited, ...), or "unknown" when inspection failed */
+  state: string
+  status: string | null
+  error: string | null
+  checkedAt: string
+}
+
+export interface InstanceLiveInfo {
+  /** This TeamClaw process's gateway connection */
+  connection: 'connecting' | 'connected' | 'disconnected' | 'error'
+  serverVersion: string | null
+  /** null for external (non-Docker) instances */
+  container: InstanceContainerState | null
 }
 
 export interface InstanceListResponse {
@@ -37,6 +57,27 @@ export interface InstanceHealthResponse {
   checkedAt: string
 }
 
+/** Why a gateway handshake pre-check (?validate=true) failed */
+export type GatewayHandshakeErrorCode =
+  | 'invalid_url'
+  | 'unreachable'
+  | 'destination_denied'
+  | 'timeout'
+  | 'tls'
+  | 'auth_failed'
+  | 'protocol_mismatch'
+  | 'handshake_failed'
+
+export interface GatewayHandshakeResult {
+  ok: boolean
+  url: string
+  latencyMs: number
+  serverVersion?: string | null
+  protocolVersion?: string
+  agentCount?: number
+  error?: { code: GatewayHandshakeErrorCode; message: string }
+}
+
 export interface InstanceLogsResponse {
   logs: string
   containerId: string
@@ -56,8 +97,18 @@ export interface DockerConfig {
   volumes?: Record<string, string>
   restartPolicy?: 'no' | 'always' | 'unless-stopped' | 'on-failure'
   memoryLimit?: number // bytes
+  sandboxProfile?: 'standard' | 'hardened' | 'isolated'
+  overrides?: Partial<Record<DeploymentEnvironment, DockerConfigOverride>>
 }
 
+export type DeploymentEnvironment = 'development' | 'staging' | 'production'
+
+/** Per-environment changes layered over DockerConfig at container start */
+export type DockerConfigOverride = Pick<
+  DockerConfig,
+  'env' | 'portBindings' | 'volumes' | 'restartPolicy' | 'memoryLimit' | 'sandboxProfile'
+>
+
 // ─── Model Provider ──────────────────────────────────────────────────
 
 export interface ModelProviderInput {
@@ -72,6 +123,7 @@ export interface ModelProviderInput {
 export interface CreateInstanceInput {
   name: string
   description?: string
+  region?: string | null
   mode?: 'docker' | 'external'
   gatewayUrl?: string
   gatewayToken?: string
@@ -83,6 +135,7 @@ export interface CreateInstanceInput {
 export interface UpdateInstanceInput {
   name?: string
   description?: string
+  region?: string | null
   gatewayUrl?: string
   gatewayToken?: string
   docker?: DockerConfig
@@ -91,3 +144,61 @@ export interface UpdateInstanceInput {
 export interface UpdateInstanceConfigInput {
   config: Record<string, unknown>
 }
+
+// ─── Smoke Test ──────────────────────────────────────────────────────
+
+export type SmokeTestStepName = 'handshake' | 'agents' | 'chat' | 'container'
+
+export interface SmokeTestStep {
+  name: SmokeTestStepName
+  status: 'pass' | 'fail' | 'skip'
+  durationMs: number
+  detail?: string
+}
+
+export interface SmokeTestReport {
+  instanceId: string
+  passed: boolean
+  agentId: string | null
+  steps: SmokeTestStep[]
+  startedAt: string
+  durationMs: number
+}
+
+// ─── Load Test ───────────────────────────────────────────────────────
+
+export type LoadTestStatus = 'RUNNING' | 'COMPLETED' | 'CANCELLED' | 'FAILED'
+
+export interface LoadTestConfig {
+  agentId: string
+  conversations: number
+  concurrency: number
+  turnsPerConversation: number
+  prompt: string
+  timeoutMs: number
+}
+
+export interface LoadTestStats {
+  conversationsCompleted: number
+  conversationsFailed: number
+  turnsOk: number
+  turnsFailed: number
+  /** Successful turns per second over the elapsed time */
+  throughput: number
+  latencyMs: { avg: number; p50: number; p95: number; p99: number; max: number }
+  /** Error message → occurrences (top entries only) */
+  errors: Record<string, number>
+}
+
+export interface LoadTestJob {
+  id: string
+  instanceId: string
+  status: LoadTestStatus
+  config: LoadTestConfig
+  stats: LoadTestStats
+  startedBy: string
+  startedAt: string
+  finishedAt: string | null
+  durationMs: number
+  error: string | null
+}
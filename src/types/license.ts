@@ -0,0 +1,36 @@
+/**
+ * UNLICENSED — no license configured (community mode, nothing enforced)
+ * VALID      — signature ok and not expired
+ * GRACE      — expired, but still inside the grace period
+ * EXPIRED    — past the grace period; new users/instances are blocked
+ * INVALID    — license configured but unreadable or badly signed
+ */
+export type LicenseState = 'UNLICENSED' | 'VALID' | 'GRACE' | 'EXPIRED' | 'INVALID'
+
+/** Signed content of a license file */
+export interface LicensePayload {
+  licenseId: string
+  licensee: string
+  /** null = unlimited */
+  maxSeats: number | null
+  maxInstances: number | null
+  issuedAt: string
+  expiresAt: string
+  graceDays?: number
+}
+
+export interface LicenseUsage {
+  used: number
+  limit: number | null
+}
+
+export interface LicenseStatus {
+  state: LicenseState
+  licenseId: string | null
+  licensee: string | null
+  expiresAt: string | null
+  graceEndsAt: string | null
+  seats: LicenseUsage
+  instances: LicenseUsage
+  error: string | null
+}
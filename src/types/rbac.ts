@@ -0,0 +1,26 @@
+import type { Role } from '@/generated/prisma'
+
+export interface PolicyEntry {
+  permission: string
+  roles: Role[]
+  /** Informational; resource checks are enforced in code and not importable */
+  resourceCheck?: boolean
+}
+
+export interface PolicyDocument {
+  version: 1
+  exportedAt: string
+  policies: PolicyEntry[]
+}
+
+export interface PolicyChange {
+  permission: string
+  before: Role[]
+  after: Role[]
+}
+
+export interface PolicyImportResult {
+  dryRun: boolean
+  changes: PolicyChange[]
+  errors: string[]
+}
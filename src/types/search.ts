@@ -0,0 +1,16 @@
+export type SearchResultType = 'user' | 'department' | 'instance' | 'agent' | 'skill' | 'session'
+
+export interface SearchResult {
+  type: SearchResultType
+  id: string
+  title: string
+  subtitle: string | null
+  /** Dashboard route that shows this result */
+  href: string
+  score: number
+}
+
+export interface SearchResponse {
+  query: string
+  results: SearchResult[]
+}
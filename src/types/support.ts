@@ -0,0 +1,49 @@
+export type SupportTicketCategory = 'INCORRECT' | 'UNSAFE' | 'BROKEN' | 'OTHER'
+export type SupportTicketStatus = 'OPEN' | 'RESOLVED'
+
+/** Instance and session state when a ticket was filed */
+export interface SupportTicketDiagnostics {
+  instance: {
+    name: string | null
+    status: string | null
+    version: string | null
+    region: string | null
+    /** Whether TeamClaw had a gateway connection */
+    connected: boolean
+    lastHealthCheck: string | null
+    health: unknown
+  }
+  session: {
+    sessionKey: string | null
+    isActive: boolean
+    messageCount: number
+    lastMessageAt: string | null
+    runsInFlight: number
+  }
+  /** Latest tool calls of the session that did not succeed */
+  recentToolFailures: { name: string; status: string; at: string }[]
+  collectedAt: string
+}
+
+export interface SupportTicketResponse {
+  id: string
+  reporterId: string
+  reporterName: string | null
+  chatSessionId: string | null
+  instanceId: string
+  instanceName: string | null
+  agentId: string
+  messageId: string | null
+  messageContent: string
+  category: SupportTicketCategory
+  description: string | null
+  diagnostics: SupportTicketDiagnostics
+  status: SupportTicketStatus
+  assigneeId: string | null
+  assigneeName: string | null
+  /** HTTP status of the ticket webhook delivery; 0 = it failed, null = not forwarded */
+  externalStatus: number | null
+  resolution: string | null
+  resolvedAt: string | null
+  createdAt: string
+}
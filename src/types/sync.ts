@@ -0,0 +1,94 @@
+import type { ChatAgentInfo, ChatMessage, ChatSessionResponse } from './chat'
+
+/** Archived message, flattened with the session/batch it belongs to */
+export interface SyncMessage extends ChatMessage {
+  chatSessionId: string
+  batchId: string
+}
+
+export interface SyncLiveMessages {
+  chatSessionId: string
+  messages: ChatMessage[]
+}
+
+export type SyncNotification =
+  | SyncApprovalNotification
+  | SyncSessionClosedNotification
+  | SyncSupportTicketNotification
+  | SyncJoinRequestNotification
+
+export interface SyncApprovalNotification {
+  id: string
+  /** approval_update: one of the user's requests changed; approval_pending: awaiting the user's review */
+  type: 'approval_update' | 'approval_pending'
+  at: string
+  approvalId: string
+  action: string
+  resource: string
+  resourceId: string | null
+  status: string
+  requestedByName: string | null
+  reviewedByName: string | null
+  reviewComment: string | null
+}
+
+/** One of the user's sessions was closed for them (e.g. after a department change) */
+export interface SyncSessionClosedNotification {
+  id: string
+  type: 'session_closed'
+  at: string
+  chatSessionId: string
+  instanceName: string
+  agentId: string
+  title: string | null
+  reason: string
+}
+
+/**
+ * support_ticket_assigned: a problem report was filed for the user to work;
+ * support_ticket_resolved: one of the user's reports was resolved
+ */
+export interface SyncSupportTicketNotification {
+  id: string
+  type: 'support_ticket_assigned' | 'support_ticket_resolved'
+  at: string
+  ticketId: string
+  instanceName: string
+  agentId: string
+  category: string
+  reporterName: string | null
+  resolution: string | null
+}
+
+/**
+ * join_request_pending: a user asked to join a department the user reviews;
+ * join_request_update: the user's own request was approved or rejected
+ */
+export interface SyncJoinRequestNotification {
+  id: string
+  type: 'join_request_pending' | 'join_request_update'
+  at: string
+  requestId: string
+  departmentName: string
+  userName: string
+  status: string
+  reviewedByName: string | null
+  reviewComment: string | null
+}
+
+export interface SyncResponse {
+  /** Pass back as ?since= on the next call */
+  cursor: string
+  /** More archived messages are waiting; call again immediately with `cursor` */
+  hasMore: boolean
+  /** No cursor was given: collections are complete rather than deltas */
+  full: boolean
+  sessions: ChatSessionResponse[]
+  /** Every session the user still has, so clients can drop deleted ones */
+  sessionIds: string[]
+  messages: SyncMessage[]
+  liveMessages: SyncLiveMessages[]
+  /** null when nothing affecting the agent list changed since the cursor */
+  agents: ChatAgentInfo[] | null
+  notifications: SyncNotification[]
+}
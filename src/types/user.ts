@@ -6,9 +6,19 @@ export interface UserResponse {
   name: string
   avatar: string | null
   role: Role
+  /** Set while the role is a temporary elevation */
+  roleExpiresAt: string | null
+  /** Role restored when the elevation expires */
+  baseRole: Role | null
   departmentId: string | null
   departmentName: string | null
+  /** Custom role whose permissions add to the role's */
+  customRoleId: string | null
+  customRoleName: string | null
   status: UserStatus
+  mfaEnabled: boolean
+  /** Admin-enforced MFA: the user must enrol before using the app */
+  mfaRequired: boolean
   lastLoginAt: string | null
   createdAt: string
   updatedAt: string
@@ -20,3 +30,100 @@ export interface UserListResponse {
   page: number
   pageSize: number
 }
+
+export interface DeviceInfo {
+  browser: string
+  os: string
+  deviceType: 'desktop' | 'mobile' | 'tablet' | 'bot' | 'unknown'
+}
+
+export interface GeoLocation {
+  country?: string
+  region?: string
+  city?: string
+}
+
+export interface LoginHistoryEntry {
+  id: string
+  time: string
+  ipAddress: string
+  device: DeviceInfo
+  userAgent: string | null
+  success: boolean
+  reason: string | null
+  location: GeoLocation | null
+}
+
+export interface LoginHistoryResponse {
+  logins: LoginHistoryEntry[]
+  total: number
+  page: number
+  pageSize: number
+}
+
+export interface OffboardingReport {
+  userId: string
+  disabled: boolean
+  refreshTokensRevoked: number
+  sessionsArchived: { id: string; instanceId: string; agentId: string }[]
+  agentsTransferred: { id: string; instanceId: string; agentId: string }[]
+  /** Owned agents left in place because no transfer target was given */
+  agentsRetained: { id: string; instanceId: string; agentId: string }[]
+  skillsTransferred: { id: string; slug: string }[]
+  skillsRetained: { id: string; slug: string }[]
+  transferredTo: string | null
+}
+
+export interface DepartmentChangeReport {
+  userId: string
+  fromDepartmentId: string | null
+  toDepartmentId: string | null
+  /** Active sessions on agents the new department cannot use */
+  sessionsArchived: { id: string; instanceId: string; agentId: string }[]
+  /** Share links on those sessions */
+  sharesRevoked: number
+}
+
+/** Per-user settings kept server-side so they follow the user across devices */
+export interface UserPreferences {
+  theme: 'light' | 'dark' | 'system'
+  locale: 'zh-CN' | 'en'
+  /** Agent selected when the chat opens */
+  defaultAgent: { instanceId: string; agentId: string } | null
+  notifications: {
+    /** Browser notification when a reply finishes in a background tab */
+    desktop: boolean
+    /** Play a sound when a tool call needs confirmation */
+    sound: boolean
+  }
+  chatDisplay: {
+    showThinking: boolean
+    showToolCalls: boolean
+  }
+}
+
+export type PreferenceKey = keyof UserPreferences
+
+export type OnboardingStepName = 'join_department' | 'pick_default_agent' | 'send_first_message' | 'install_mobile_link'
+
+/** The current user's onboarding checklist, in the configured order */
+export interface OnboardingProgress {
+  steps: { step: OnboardingStepName; completed: boolean; completedAt: string | null }[]
+  completedCount: number
+  total: number
+  /** Every step is done */
+  finished: boolean
+}
+
+/** How many users got through each onboarding step */
+export interface OnboardingFunnel {
+  departmentId: string | null
+  users: number
+  steps: {
+    step: OnboardingStepName
+    /** Users who did this step */
+    completed: number
+    /** Users who did this step and every step before it */
+    reached: number
+  }[]
+}